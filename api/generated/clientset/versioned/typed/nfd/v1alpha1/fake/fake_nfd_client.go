@@ -28,6 +28,10 @@ type FakeNfdV1alpha1 struct {
 	*testing.Fake
 }
 
+func (c *FakeNfdV1alpha1) NamespacedNodeFeatureRules(namespace string) v1alpha1.NamespacedNodeFeatureRuleInterface {
+	return newFakeNamespacedNodeFeatureRules(c, namespace)
+}
+
 func (c *FakeNfdV1alpha1) NodeFeatures(namespace string) v1alpha1.NodeFeatureInterface {
 	return newFakeNodeFeatures(c, namespace)
 }
@@ -40,6 +44,10 @@ func (c *FakeNfdV1alpha1) NodeFeatureRules() v1alpha1.NodeFeatureRuleInterface {
 	return newFakeNodeFeatureRules(c)
 }
 
+func (c *FakeNfdV1alpha1) NodeInventories() v1alpha1.NodeInventoryInterface {
+	return newFakeNodeInventories(c)
+}
+
 // RESTClient returns a RESTClient that is used to communicate
 // with API server by this client implementation.
 func (c *FakeNfdV1alpha1) RESTClient() rest.Interface {
@@ -18,8 +18,12 @@ limitations under the License.
 
 package v1alpha1
 
+type NamespacedNodeFeatureRuleExpansion interface{}
+
 type NodeFeatureExpansion interface{}
 
 type NodeFeatureGroupExpansion interface{}
 
 type NodeFeatureRuleExpansion interface{}
+
+type NodeInventoryExpansion interface{}
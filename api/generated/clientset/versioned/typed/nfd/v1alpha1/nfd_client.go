@@ -28,9 +28,11 @@ import (
 
 type NfdV1alpha1Interface interface {
 	RESTClient() rest.Interface
+	NamespacedNodeFeatureRulesGetter
 	NodeFeaturesGetter
 	NodeFeatureGroupsGetter
 	NodeFeatureRulesGetter
+	NodeInventoriesGetter
 }
 
 // NfdV1alpha1Client is used to interact with features provided by the nfd.k8s-sigs.io group.
@@ -38,6 +40,10 @@ type NfdV1alpha1Client struct {
 	restClient rest.Interface
 }
 
+func (c *NfdV1alpha1Client) NamespacedNodeFeatureRules(namespace string) NamespacedNodeFeatureRuleInterface {
+	return newNamespacedNodeFeatureRules(c, namespace)
+}
+
 func (c *NfdV1alpha1Client) NodeFeatures(namespace string) NodeFeatureInterface {
 	return newNodeFeatures(c, namespace)
 }
@@ -50,6 +56,10 @@ func (c *NfdV1alpha1Client) NodeFeatureRules() NodeFeatureRuleInterface {
 	return newNodeFeatureRules(c)
 }
 
+func (c *NfdV1alpha1Client) NodeInventories() NodeInventoryInterface {
+	return newNodeInventories(c)
+}
+
 // NewForConfig creates a new NfdV1alpha1Client for the given config.
 // NewForConfig is equivalent to NewForConfigAndClient(c, httpClient),
 // where httpClient was generated with rest.HTTPClientFor(c).
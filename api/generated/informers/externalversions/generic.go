@@ -53,12 +53,16 @@ func (f *genericInformer) Lister() cache.GenericLister {
 func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource) (GenericInformer, error) {
 	switch resource {
 	// Group=nfd.k8s-sigs.io, Version=v1alpha1
+	case v1alpha1.SchemeGroupVersion.WithResource("namespacednodefeaturerules"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Nfd().V1alpha1().NamespacedNodeFeatureRules().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("nodefeatures"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Nfd().V1alpha1().NodeFeatures().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("nodefeaturegroups"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Nfd().V1alpha1().NodeFeatureGroups().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("nodefeaturerules"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Nfd().V1alpha1().NodeFeatureRules().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("nodeinventories"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Nfd().V1alpha1().NodeInventories().Informer()}, nil
 
 	}
 
@@ -24,12 +24,16 @@ import (
 
 // Interface provides access to all the informers in this group version.
 type Interface interface {
+	// NamespacedNodeFeatureRules returns a NamespacedNodeFeatureRuleInformer.
+	NamespacedNodeFeatureRules() NamespacedNodeFeatureRuleInformer
 	// NodeFeatures returns a NodeFeatureInformer.
 	NodeFeatures() NodeFeatureInformer
 	// NodeFeatureGroups returns a NodeFeatureGroupInformer.
 	NodeFeatureGroups() NodeFeatureGroupInformer
 	// NodeFeatureRules returns a NodeFeatureRuleInformer.
 	NodeFeatureRules() NodeFeatureRuleInformer
+	// NodeInventories returns a NodeInventoryInformer.
+	NodeInventories() NodeInventoryInformer
 }
 
 type version struct {
@@ -43,6 +47,11 @@ func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakList
 	return &version{factory: f, namespace: namespace, tweakListOptions: tweakListOptions}
 }
 
+// NamespacedNodeFeatureRules returns a NamespacedNodeFeatureRuleInformer.
+func (v *version) NamespacedNodeFeatureRules() NamespacedNodeFeatureRuleInformer {
+	return &namespacedNodeFeatureRuleInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // NodeFeatures returns a NodeFeatureInformer.
 func (v *version) NodeFeatures() NodeFeatureInformer {
 	return &nodeFeatureInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
@@ -57,3 +66,8 @@ func (v *version) NodeFeatureGroups() NodeFeatureGroupInformer {
 func (v *version) NodeFeatureRules() NodeFeatureRuleInformer {
 	return &nodeFeatureRuleInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
 }
+
+// NodeInventories returns a NodeInventoryInformer.
+func (v *version) NodeInventories() NodeInventoryInformer {
+	return &nodeInventoryInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
@@ -47,6 +47,10 @@ const (
 	// ExtendedResourceAnnotation is the annotation that holds all extended resources managed by NFD.
 	ExtendedResourceAnnotation = AnnotationNs + "/extended-resources"
 
+	// ExtendedResourceValuesAnnotation is the annotation that holds the extended resources (and their values)
+	// to be advertised by nfd-device-plugin, instead of nfd-master patching the node status.
+	ExtendedResourceValuesAnnotation = AnnotationNs + "/extended-resource-values"
+
 	// FeatureLabelsAnnotation is the annotation that holds all feature labels managed by NFD.
 	FeatureLabelsAnnotation = AnnotationNs + "/feature-labels"
 
@@ -63,6 +67,34 @@ const (
 	// FeatureAnnotationsTrackingAnnotation is the annotation that holds all feature annotations that nfd-master set on the node
 	FeatureAnnotationsTrackingAnnotation = AnnotationNs + "/feature-annotations"
 
+	// LastAppliedTimeAnnotation is the annotation that holds the time when nfd-master last applied changes to the node
+	LastAppliedTimeAnnotation = AnnotationNs + "/last-applied-time"
+
+	// RejectedItemsAnnotation is the annotation that holds the number and the keys of the labels, annotations,
+	// extended resources and taints of the node that were rejected by nfd-master
+	RejectedItemsAnnotation = AnnotationNs + "/rejected"
+
+	// LabelConflictsAnnotation is the annotation that holds the NFD-managed labels of the node that are repeatedly
+	// changed or removed by someone else
+	LabelConflictsAnnotation = AnnotationNs + "/label-conflicts"
+
+	// NodeUIDAnnotation is the annotation of NodeResourceTopology objects that holds the UID of the node the object was created for
+	NodeUIDAnnotation = AnnotationNs + "/node-uid"
+
+	// SourceErrorsAnnotation is the annotation of NodeFeature objects that holds the errors of the feature sources of nfd-worker
+	SourceErrorsAnnotation = AnnotationNs + "/source-errors"
+
+	// DeprecatedFeaturesAnnotation is the annotation of NodeFeature objects that lists the deprecated features referenced by the rules of nfd-worker
+	DeprecatedFeaturesAnnotation = AnnotationNs + "/deprecated-features"
+
+	// TraceParentAnnotation is the annotation of NodeFeature objects that holds the W3C trace context of the last update of nfd-worker
+	TraceParentAnnotation = AnnotationNs + "/traceparent"
+
+	// CreatorNodeAnnotation is the annotation of NodeFeature objects that
+	// holds the name of the node whose identity was used to create or update
+	// the object. The value is supposed to be verified by an admission policy.
+	CreatorNodeAnnotation = AnnotationNs + "/creator-node"
+
 	// NodeFeatureObjNodeNameLabel is the label that specifies which node the
 	// NodeFeature object is targeting. Creators of NodeFeature objects must
 	// set this label and consumers of the objects are supposed to use the
@@ -77,4 +109,8 @@ const (
 
 	// FeatureAnnotationValueSizeLimit is the maximum allowed length for the value of a feature annotation.
 	FeatureAnnotationValueSizeLimit = 1 << 10
+
+	// CompactedLabelsAnnotation is the feature annotation that holds the
+	// boolean feature labels folded into a single annotation by nfd-master.
+	CompactedLabelsAnnotation = FeatureAnnotationNs + "/compacted-labels"
 )
@@ -80,6 +80,38 @@ func (f *Features) InsertAttributeFeatures(domain, feature string, values map[st
 	maps.Copy(f.Attributes[key].Elements, values)
 }
 
+// WithoutTimestamps returns a copy of the features with the LastUpdated
+// timestamps of all feature sets cleared. The features themselves are not
+// copied, i.e. the copy shares the elements of the feature sets.
+func (f *Features) WithoutTimestamps() *Features {
+	if f == nil {
+		return nil
+	}
+	out := &Features{}
+	if f.Flags != nil {
+		out.Flags = make(map[string]FlagFeatureSet, len(f.Flags))
+		for k, v := range f.Flags {
+			v.LastUpdated = nil
+			out.Flags[k] = v
+		}
+	}
+	if f.Attributes != nil {
+		out.Attributes = make(map[string]AttributeFeatureSet, len(f.Attributes))
+		for k, v := range f.Attributes {
+			v.LastUpdated = nil
+			out.Attributes[k] = v
+		}
+	}
+	if f.Instances != nil {
+		out.Instances = make(map[string]InstanceFeatureSet, len(f.Instances))
+		for k, v := range f.Instances {
+			v.LastUpdated = nil
+			out.Instances[k] = v
+		}
+	}
+	return out
+}
+
 // MergeInto merges two FeatureSpecs into one. Data in the input object takes
 // precedence (overwrite) over data of the existing object we're merging into.
 func (in *NodeFeatureSpec) MergeInto(out *NodeFeatureSpec) {
@@ -90,6 +122,19 @@ func (in *NodeFeatureSpec) MergeInto(out *NodeFeatureSpec) {
 		}
 		maps.Copy(out.Labels, in.Labels)
 	}
+	if in.Annotations != nil {
+		if out.Annotations == nil {
+			out.Annotations = make(map[string]string, len(in.Annotations))
+		}
+		maps.Copy(out.Annotations, in.Annotations)
+	}
+	if in.ExtendedResources != nil {
+		if out.ExtendedResources == nil {
+			out.ExtendedResources = make(map[string]string, len(in.ExtendedResources))
+		}
+		maps.Copy(out.ExtendedResources, in.ExtendedResources)
+	}
+	out.Taints = append(out.Taints, in.Taints...)
 }
 
 // MergeInto merges two sets of features into one. Features from the input set
@@ -136,6 +181,9 @@ func (in *FlagFeatureSet) MergeInto(out *FlagFeatureSet) {
 		}
 		maps.Copy(out.Elements, in.Elements)
 	}
+	if in.LastUpdated != nil {
+		out.LastUpdated = in.LastUpdated.DeepCopy()
+	}
 }
 
 // MergeInto merges two sets of attribute featues.
@@ -146,6 +194,9 @@ func (in *AttributeFeatureSet) MergeInto(out *AttributeFeatureSet) {
 		}
 		maps.Copy(out.Elements, in.Elements)
 	}
+	if in.LastUpdated != nil {
+		out.LastUpdated = in.LastUpdated.DeepCopy()
+	}
 }
 
 // MergeInto merges two sets of instance featues.
@@ -158,4 +209,7 @@ func (in *InstanceFeatureSet) MergeInto(out *InstanceFeatureSet) {
 			out.Elements = append(out.Elements, *e.DeepCopy())
 		}
 	}
+	if in.LastUpdated != nil {
+		out.LastUpdated = in.LastUpdated.DeepCopy()
+	}
 }
@@ -18,8 +18,10 @@ package v1alpha1
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestFlagFeatureSet(t *testing.T) {
@@ -74,6 +76,18 @@ func TestAttributeFeatureSet(t *testing.T) {
 	expectedElems["k3"] = "v3"
 	f2.MergeInto(&f1)
 	assert.Equal(t, expectedElems, f1.Elements)
+
+	// Timestamp of the input takes precedence
+	ts := metav1.NewTime(time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC))
+	f2 = NewAttributeFeatures(nil)
+	f2.LastUpdated = &ts
+	f2.MergeInto(&f1)
+	assert.Equal(t, &ts, f1.LastUpdated)
+	assert.Equal(t, expectedElems, f1.Elements)
+
+	f2 = NewAttributeFeatures(nil)
+	f2.MergeInto(&f1)
+	assert.Equal(t, &ts, f1.LastUpdated)
 }
 
 func TestInstanceFeatureSet(t *testing.T) {
@@ -189,3 +203,20 @@ func TestFeatureSpec(t *testing.T) {
 	f2.MergeInto(&f)
 	assert.Equal(t, expectedFeatures, f)
 }
+
+func TestFeaturesWithoutTimestamps(t *testing.T) {
+	ts := metav1.NewTime(time.Now())
+	f := NewFeatures()
+	f.Flags["dom.flag"] = FlagFeatureSet{Elements: map[string]Nil{"k1": {}}, LastUpdated: &ts}
+	f.Attributes["dom.attr"] = AttributeFeatureSet{Elements: map[string]string{"k1": "v1"}, LastUpdated: &ts}
+	f.Instances["dom.inst"] = InstanceFeatureSet{LastUpdated: &ts}
+
+	expected := NewFeatures()
+	expected.Flags["dom.flag"] = NewFlagFeatures("k1")
+	expected.Attributes["dom.attr"] = NewAttributeFeatures(map[string]string{"k1": "v1"})
+	expected.Instances["dom.inst"] = InstanceFeatureSet{}
+
+	assert.Equal(t, expected, f.WithoutTimestamps())
+	assert.NotNil(t, f.Flags["dom.flag"].LastUpdated, "input features must not be modified")
+	assert.Nil(t, (*Features)(nil).WithoutTimestamps())
+}
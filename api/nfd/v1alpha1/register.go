@@ -42,7 +42,9 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 	scheme.AddKnownTypes(SchemeGroupVersion,
 		&NodeFeature{},
 		&NodeFeatureRule{},
+		&NamespacedNodeFeatureRule{},
 		&NodeFeatureGroup{},
+		&NodeInventory{},
 	)
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
 	return nil
@@ -53,6 +53,18 @@ type NodeFeatureSpec struct {
 	// Labels is the set of node labels that are requested to be created.
 	// +optional
 	Labels map[string]string `json:"labels"`
+	// Annotations is the set of node annotations that are requested to be
+	// created.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// ExtendedResources is the set of node extended resources that are
+	// requested to be created.
+	// +optional
+	ExtendedResources map[string]string `json:"extendedResources,omitempty"`
+	// Taints is the list of node taints that are requested to be created.
+	// Only applied if taints are enabled in nfd-master.
+	// +optional
+	Taints []corev1.Taint `json:"taints,omitempty"`
 }
 
 // Features is the collection of all discovered features.
@@ -72,18 +84,33 @@ type Features struct {
 type FlagFeatureSet struct {
 	// Individual features of the feature set.
 	Elements map[string]Nil `json:"elements"`
+	// LastUpdated is the time when the feature set was last successfully
+	// discovered. Only set if the FeatureTimestamps feature gate of
+	// nfd-worker is enabled.
+	// +optional
+	LastUpdated *metav1.Time `json:"lastUpdated,omitempty"`
 }
 
 // AttributeFeatureSet is a set of features having string value.
 type AttributeFeatureSet struct {
 	// Individual features of the feature set.
 	Elements map[string]string `json:"elements"`
+	// LastUpdated is the time when the feature set was last successfully
+	// discovered. Only set if the FeatureTimestamps feature gate of
+	// nfd-worker is enabled.
+	// +optional
+	LastUpdated *metav1.Time `json:"lastUpdated,omitempty"`
 }
 
 // InstanceFeatureSet is a set of features each of which is an instance having multiple attributes.
 type InstanceFeatureSet struct {
 	// Individual features of the feature set.
 	Elements []InstanceFeature `json:"elements"`
+	// LastUpdated is the time when the feature set was last successfully
+	// discovered. Only set if the FeatureTimestamps feature gate of
+	// nfd-worker is enabled.
+	// +optional
+	LastUpdated *metav1.Time `json:"lastUpdated,omitempty"`
 }
 
 // InstanceFeature represents one instance of a complex features, e.g. a device.
@@ -126,6 +153,38 @@ type NodeFeatureRule struct {
 type NodeFeatureRuleSpec struct {
 	// Rules is a list of node customization rules.
 	Rules []Rule `json:"rules"`
+
+	// Suspend disables the evaluation of all rules of the object. Outputs
+	// previously created by the rules are removed from the nodes.
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+}
+
+// NamespacedNodeFeatureRuleList contains a list of NamespacedNodeFeatureRule
+// objects.
+// +kubebuilder:object:root=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type NamespacedNodeFeatureRuleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	// List of NamespacedNodeFeatureRules.
+	Items []NamespacedNodeFeatureRule `json:"items"`
+}
+
+// NamespacedNodeFeatureRule is a namespaced variant of NodeFeatureRule. The
+// rules only affect the nodes that match the node selector bound to the
+// namespace of the object in the nfd-master configuration.
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Namespaced,shortName=nnfr
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +genclient
+type NamespacedNodeFeatureRule struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec defines the rules to be evaluated.
+	Spec NodeFeatureRuleSpec `json:"spec"`
 }
 
 // NodeFeatureGroup resource holds Node pools by featureGroup
@@ -153,6 +212,10 @@ type NodeFeatureGroupSpec struct {
 }
 
 type NodeFeatureGroupStatus struct {
+	// ObservedGeneration is the generation of the NodeFeatureGroup spec that
+	// the status was computed from.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 	// Nodes is a list of FeatureGroupNode in the cluster that match the featureGroupRules
 	// +optional
 	// +patchMergeKey=name
@@ -160,6 +223,30 @@ type NodeFeatureGroupStatus struct {
 	// +listType=map
 	// +listMapKey=name
 	Nodes []FeatureGroupNode `json:"nodes"`
+	// Rules contains the nodes matched by each individual rule. Only
+	// populated if enabled in the nfd-master configuration.
+	// +optional
+	// +listType=atomic
+	Rules []FeatureGroupRuleStatus `json:"rules,omitempty"`
+}
+
+// FeatureGroupRuleStatus describes the nodes matched by one rule of a
+// NodeFeatureGroup.
+type FeatureGroupRuleStatus struct {
+	// Name of the rule.
+	Name string `json:"name"`
+	// Nodes is a list of nodes matching the rule. The list is truncated if
+	// the number of matching nodes exceeds the limit set in the nfd-master
+	// configuration.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Nodes []FeatureGroupNode `json:"nodes,omitempty"`
+	// NodeCount is the total number of nodes matching the rule.
+	NodeCount int `json:"nodeCount"`
+	// Truncated indicates that the list of nodes is incomplete.
+	// +optional
+	Truncated bool `json:"truncated,omitempty"`
 }
 
 type FeatureGroupNode struct {
@@ -178,6 +265,49 @@ type NodeFeatureGroupList struct {
 	Items []NodeFeatureGroup `json:"items"`
 }
 
+// NodeInventoryList contains a list of NodeInventory objects.
+// +kubebuilder:object:root=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type NodeInventoryList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	// List of NodeInventories.
+	Items []NodeInventory `json:"items"`
+}
+
+// NodeInventory records the labels managed by NFD for a machine. The object
+// is named after the stable machine identity (system UUID) of the node so
+// that the data survives re-creation of the node object, e.g. when the
+// machine is re-provisioned.
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster,shortName=ninv
+// +kubebuilder:printcolumn:name="Node",type="string",JSONPath=".spec.nodeName"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +genclient
+// +genclient:nonNamespaced
+type NodeInventory struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec is the recorded inventory of the machine.
+	Spec NodeInventorySpec `json:"spec"`
+}
+
+// NodeInventorySpec describes a NodeInventory object.
+type NodeInventorySpec struct {
+	// SystemUUID is the stable identity of the machine, as reported in the
+	// status of the node object.
+	SystemUUID string `json:"systemUUID"`
+	// NodeName is the name of the node object the machine was most recently
+	// registered as.
+	NodeName string `json:"nodeName"`
+	// Labels is the set of node labels computed by NFD.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
 // GroupRule defines a rule for nodegroup filtering.
 type GroupRule struct {
 	// Name of the rule.
@@ -190,6 +320,11 @@ type GroupRule struct {
 	// MatchAny specifies a list of matchers one of which must match.
 	// +optional
 	MatchAny []MatchAnyElem `json:"matchAny"`
+
+	// MatchNode specifies requirements against the metadata of the node
+	// object, all of which must match.
+	// +optional
+	MatchNode *NodeMatcher `json:"matchNode,omitempty"`
 }
 
 // Rule defines a rule for node customization such as labeling.
@@ -197,6 +332,11 @@ type Rule struct {
 	// Name of the rule.
 	Name string `json:"name"`
 
+	// Disabled disables the evaluation of the rule. Outputs previously
+	// created by the rule are removed from the nodes.
+	// +optional
+	Disabled bool `json:"disabled,omitempty"`
+
 	// Labels to create if the rule matches.
 	// +optional
 	Labels map[string]string `json:"labels"`
@@ -232,6 +372,14 @@ type Rule struct {
 	// +optional
 	ExtendedResources map[string]string `json:"extendedResources"`
 
+	// ElseLabels to create if the rule does not match.
+	// +optional
+	ElseLabels map[string]string `json:"elseLabels,omitempty"`
+
+	// ElseTaints to create if the rule does not match.
+	// +optional
+	ElseTaints []corev1.Taint `json:"elseTaints,omitempty"`
+
 	// MatchFeatures specifies a set of matcher terms all of which must match.
 	// +optional
 	MatchFeatures FeatureMatcher `json:"matchFeatures"`
@@ -239,6 +387,25 @@ type Rule struct {
 	// MatchAny specifies a list of matchers one of which must match.
 	// +optional
 	MatchAny []MatchAnyElem `json:"matchAny"`
+
+	// MatchNode specifies requirements against the metadata of the node
+	// object, all of which must match.
+	// +optional
+	MatchNode *NodeMatcher `json:"matchNode,omitempty"`
+}
+
+// NodeMatcher specifies requirements against the existing labels and
+// annotations of the node object. Labels and annotations managed by NFD are
+// not available for matching.
+type NodeMatcher struct {
+	// Labels is the set of expressions evaluated against the labels of the
+	// node.
+	// +optional
+	Labels *MatchExpressionSet `json:"labels,omitempty"`
+	// Annotations is the set of expressions evaluated against the
+	// annotations of the node.
+	// +optional
+	Annotations *MatchExpressionSet `json:"annotations,omitempty"`
 }
 
 // MatchAnyElem specifies one sub-matcher of MatchAny.
@@ -345,6 +512,18 @@ const (
 	RuleBackrefFeature = "matched"
 )
 
+const (
+	// NodeMetadataDomain is the special feature domain for the metadata of
+	// the node object.
+	NodeMetadataDomain = "node"
+	// NodeLabelFeature is the special feature name for the labels of the
+	// node object.
+	NodeLabelFeature = "label"
+	// NodeAnnotationFeature is the special feature name for the annotations
+	// of the node object.
+	NodeAnnotationFeature = "annotation"
+)
+
 // MatchAllNames is a special key in MatchExpressionSet to use field names
 // (keys from the input) instead of values when matching.
 const MatchAllNames = "*"
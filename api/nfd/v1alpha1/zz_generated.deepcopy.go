@@ -36,6 +36,10 @@ func (in *AttributeFeatureSet) DeepCopyInto(out *AttributeFeatureSet) {
 			(*out)[key] = val
 		}
 	}
+	if in.LastUpdated != nil {
+		in, out := &in.LastUpdated, &out.LastUpdated
+		*out = (*in).DeepCopy()
+	}
 	return
 }
 
@@ -65,6 +69,27 @@ func (in *FeatureGroupNode) DeepCopy() *FeatureGroupNode {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FeatureGroupRuleStatus) DeepCopyInto(out *FeatureGroupRuleStatus) {
+	*out = *in
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = make([]FeatureGroupNode, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FeatureGroupRuleStatus.
+func (in *FeatureGroupRuleStatus) DeepCopy() *FeatureGroupRuleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FeatureGroupRuleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in FeatureMatcher) DeepCopyInto(out *FeatureMatcher) {
 	{
@@ -174,6 +199,10 @@ func (in *FlagFeatureSet) DeepCopyInto(out *FlagFeatureSet) {
 			(*out)[key] = val
 		}
 	}
+	if in.LastUpdated != nil {
+		in, out := &in.LastUpdated, &out.LastUpdated
+		*out = (*in).DeepCopy()
+	}
 	return
 }
 
@@ -204,6 +233,11 @@ func (in *GroupRule) DeepCopyInto(out *GroupRule) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.MatchNode != nil {
+		in, out := &in.MatchNode, &out.MatchNode
+		*out = new(NodeMatcher)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -250,6 +284,10 @@ func (in *InstanceFeatureSet) DeepCopyInto(out *InstanceFeatureSet) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.LastUpdated != nil {
+		in, out := &in.LastUpdated, &out.LastUpdated
+		*out = (*in).DeepCopy()
+	}
 	return
 }
 
@@ -357,6 +395,66 @@ func (in MatchValue) DeepCopy() MatchValue {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespacedNodeFeatureRule) DeepCopyInto(out *NamespacedNodeFeatureRule) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespacedNodeFeatureRule.
+func (in *NamespacedNodeFeatureRule) DeepCopy() *NamespacedNodeFeatureRule {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespacedNodeFeatureRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NamespacedNodeFeatureRule) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespacedNodeFeatureRuleList) DeepCopyInto(out *NamespacedNodeFeatureRuleList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NamespacedNodeFeatureRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespacedNodeFeatureRuleList.
+func (in *NamespacedNodeFeatureRuleList) DeepCopy() *NamespacedNodeFeatureRuleList {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespacedNodeFeatureRuleList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NamespacedNodeFeatureRuleList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Nil) DeepCopyInto(out *Nil) {
 	*out = *in
@@ -492,6 +590,13 @@ func (in *NodeFeatureGroupStatus) DeepCopyInto(out *NodeFeatureGroupStatus) {
 		*out = make([]FeatureGroupNode, len(*in))
 		copy(*out, *in)
 	}
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]FeatureGroupRuleStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -632,6 +737,27 @@ func (in *NodeFeatureSpec) DeepCopyInto(out *NodeFeatureSpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ExtendedResources != nil {
+		in, out := &in.ExtendedResources, &out.ExtendedResources
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Taints != nil {
+		in, out := &in.Taints, &out.Taints
+		*out = make([]v1.Taint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -645,6 +771,143 @@ func (in *NodeFeatureSpec) DeepCopy() *NodeFeatureSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeInventory) DeepCopyInto(out *NodeInventory) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeInventory.
+func (in *NodeInventory) DeepCopy() *NodeInventory {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeInventory)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NodeInventory) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeInventoryList) DeepCopyInto(out *NodeInventoryList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NodeInventory, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeInventoryList.
+func (in *NodeInventoryList) DeepCopy() *NodeInventoryList {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeInventoryList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NodeInventoryList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeInventorySpec) DeepCopyInto(out *NodeInventorySpec) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeInventorySpec.
+func (in *NodeInventorySpec) DeepCopy() *NodeInventorySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeInventorySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeMatcher) DeepCopyInto(out *NodeMatcher) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = new(MatchExpressionSet)
+		if **in != nil {
+			in, out := *in, *out
+			*out = make(map[string]*MatchExpression, len(*in))
+			for key, val := range *in {
+				var outVal *MatchExpression
+				if val == nil {
+					(*out)[key] = nil
+				} else {
+					in, out := &val, &outVal
+					*out = new(MatchExpression)
+					(*in).DeepCopyInto(*out)
+				}
+				(*out)[key] = outVal
+			}
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = new(MatchExpressionSet)
+		if **in != nil {
+			in, out := *in, *out
+			*out = make(map[string]*MatchExpression, len(*in))
+			for key, val := range *in {
+				var outVal *MatchExpression
+				if val == nil {
+					(*out)[key] = nil
+				} else {
+					in, out := &val, &outVal
+					*out = new(MatchExpression)
+					(*in).DeepCopyInto(*out)
+				}
+				(*out)[key] = outVal
+			}
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeMatcher.
+func (in *NodeMatcher) DeepCopy() *NodeMatcher {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeMatcher)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Rule) DeepCopyInto(out *Rule) {
 	*out = *in
@@ -683,6 +946,20 @@ func (in *Rule) DeepCopyInto(out *Rule) {
 			(*out)[key] = val
 		}
 	}
+	if in.ElseLabels != nil {
+		in, out := &in.ElseLabels, &out.ElseLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ElseTaints != nil {
+		in, out := &in.ElseTaints, &out.ElseTaints
+		*out = make([]v1.Taint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.MatchFeatures != nil {
 		in, out := &in.MatchFeatures, &out.MatchFeatures
 		*out = make(FeatureMatcher, len(*in))
@@ -697,6 +974,11 @@ func (in *Rule) DeepCopyInto(out *Rule) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.MatchNode != nil {
+		in, out := &in.MatchNode, &out.MatchNode
+		*out = new(NodeMatcher)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
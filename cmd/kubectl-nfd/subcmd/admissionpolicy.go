@@ -0,0 +1,70 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package subcmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	kubectlnfd "sigs.k8s.io/node-feature-discovery/pkg/kubectl-nfd"
+)
+
+var admissionPolicyOpts = struct {
+	name            string
+	namespace       string
+	serviceAccount  string
+	labelNamespaces []string
+	annotationNs    []string
+	allowedUsers    []string
+}{}
+
+var admissionPolicyCmd = &cobra.Command{
+	Use:   "admission-policy",
+	Short: "Generate a ValidatingAdmissionPolicy protecting NFD-managed node labels",
+	Long: `Generate a ValidatingAdmissionPolicy and a binding that deny modifications of
+NFD-managed node labels and annotations by anybody else than nfd-master (and
+other explicitly allowed users). The output can be applied with kubectl.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		users := append([]string{
+			fmt.Sprintf("system:serviceaccount:%s:%s", admissionPolicyOpts.namespace, admissionPolicyOpts.serviceAccount),
+		}, admissionPolicyOpts.allowedUsers...)
+
+		out, err := kubectlnfd.GenerateAdmissionPolicy(kubectlnfd.AdmissionPolicyOptions{
+			Name:                 admissionPolicyOpts.name,
+			LabelNamespaces:      admissionPolicyOpts.labelNamespaces,
+			AnnotationNamespaces: admissionPolicyOpts.annotationNs,
+			AllowedUsers:         users,
+		})
+		if err != nil {
+			cmd.PrintErrln(err)
+			os.Exit(1)
+		}
+		fmt.Print(out)
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(admissionPolicyCmd)
+
+	admissionPolicyCmd.Flags().StringVar(&admissionPolicyOpts.name, "name", "nfd-node-metadata", "Name of the generated ValidatingAdmissionPolicy and binding")
+	admissionPolicyCmd.Flags().StringVar(&admissionPolicyOpts.namespace, "namespace", "node-feature-discovery", "Namespace where nfd-master is deployed")
+	admissionPolicyCmd.Flags().StringVar(&admissionPolicyOpts.serviceAccount, "service-account", "nfd-master", "Name of the service account of nfd-master")
+	admissionPolicyCmd.Flags().StringSliceVar(&admissionPolicyOpts.labelNamespaces, "label-ns", nil, "Additional (vendor) label namespaces to protect")
+	admissionPolicyCmd.Flags().StringSliceVar(&admissionPolicyOpts.annotationNs, "annotation-ns", nil, "Additional annotation namespaces to protect, e.g. the annotationNs of nfd-master")
+	admissionPolicyCmd.Flags().StringSliceVar(&admissionPolicyOpts.allowedUsers, "allowed-user", nil, "Additional users allowed to modify the protected labels and annotations")
+}
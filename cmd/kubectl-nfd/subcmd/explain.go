@@ -0,0 +1,68 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package subcmd
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	kubectlnfd "sigs.k8s.io/node-feature-discovery/pkg/kubectl-nfd"
+)
+
+var (
+	// Label to explain
+	label string
+	// Output format
+	output string
+	// Namespace of nfd-master
+	masterNamespace string
+	// Label selector of the nfd-master pods
+	masterSelector string
+)
+
+var explainCmd = &cobra.Command{
+	Use:   "explain",
+	Short: "Explain the NFD-managed labels of a Node",
+	Long: `Ask nfd-master to evaluate a Node with its live configuration and show the NodeFeature
+objects merged, the rules evaluated, their match results and the resulting labels, annotations,
+extended resources and taints, compared with the labels currently on the Node.
+
+Requires nfd-master to run with -enable-debug-endpoints. The nfd-master pods are looked up with
+--namespace and --selector, use --selector=app.kubernetes.io/name=node-feature-discovery,role=master
+with the Helm chart`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := kubectlnfd.Explain(os.Stdout, node, kubeconfig, masterNamespace, masterSelector, label, output); err != nil {
+			cmd.PrintErrln(err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(explainCmd)
+
+	explainCmd.Flags().StringVarP(&node, "nodename", "n", "", "Node to explain")
+	explainCmd.Flags().StringVarP(&kubeconfig, "kubeconfig", "k", "", "kubeconfig file to use")
+	explainCmd.Flags().StringVarP(&masterNamespace, "namespace", "N", "node-feature-discovery", "Namespace where nfd-master is deployed")
+	explainCmd.Flags().StringVar(&masterSelector, "selector", kubectlnfd.DefaultMasterSelector, "Label selector of the nfd-master pods")
+	explainCmd.Flags().StringVarP(&label, "label", "l", "", "Only show rules related to this label")
+	explainCmd.Flags().StringVarP(&output, "output", "o", "text", "Output format, one of text, yaml or json")
+	err := explainCmd.MarkFlagRequired("nodename")
+	if err != nil {
+		panic(err)
+	}
+}
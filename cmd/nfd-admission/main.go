@@ -0,0 +1,113 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"k8s.io/klog/v2"
+
+	nfdadmission "sigs.k8s.io/node-feature-discovery/pkg/nfd-admission"
+	"sigs.k8s.io/node-feature-discovery/pkg/utils"
+	"sigs.k8s.io/node-feature-discovery/pkg/version"
+)
+
+const (
+	// ProgramName is the canonical name of this program
+	ProgramName = "nfd-admission"
+)
+
+func main() {
+	flags := flag.NewFlagSet(ProgramName, flag.ExitOnError)
+
+	printVersion := flags.Bool("version", false, "Print version and exit.")
+
+	args := parseArgs(flags, os.Args[1:]...)
+
+	if *printVersion {
+		fmt.Println(ProgramName, version.Get())
+		os.Exit(0)
+	}
+
+	// Assert that the version is known
+	if version.Undefined() {
+		klog.InfoS("version not set! Set -ldflags \"-X sigs.k8s.io/node-feature-discovery/pkg/version.version=`git describe --tags --dirty --always --match 'v*'`\" during build or run.")
+	}
+
+	// Get new admission webhook instance
+	admission, err := nfdadmission.New(args)
+	if err != nil {
+		klog.ErrorS(err, "failed to initialize nfd admission webhook instance")
+		os.Exit(1)
+	}
+
+	utils.StopOnSignal(admission.Stop)
+	if err = admission.Run(); err != nil {
+		klog.ErrorS(err, "error while running")
+		os.Exit(1)
+	}
+}
+
+func parseArgs(flags *flag.FlagSet, osArgs ...string) *nfdadmission.Args {
+	args, overrides := initFlags(flags)
+
+	_ = flags.Parse(osArgs)
+	if len(flags.Args()) > 0 {
+		fmt.Fprintf(flags.Output(), "unknown command line argument: %s\n", flags.Args()[0])
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	// Only the flags specified on the command line override the config file
+	flags.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "deny-label-ns":
+			args.Overrides.DenyLabelNs = overrides.DenyLabelNs
+		case "extra-label-ns":
+			args.Overrides.ExtraLabelNs = overrides.ExtraLabelNs
+		}
+	})
+
+	return args
+}
+
+func initFlags(flagset *flag.FlagSet) (*nfdadmission.Args, *nfdadmission.ConfigOverrideArgs) {
+	args := &nfdadmission.Args{}
+	overrides := &nfdadmission.ConfigOverrideArgs{
+		DenyLabelNs:  &utils.StringSetVal{},
+		ExtraLabelNs: &utils.StringSetVal{},
+	}
+
+	flagset.StringVar(&args.CertFile, "cert-file", "",
+		"Certificate file used for serving the webhook over HTTPS.")
+	flagset.StringVar(&args.KeyFile, "key-file", "",
+		"Private key file used for serving the webhook over HTTPS.")
+	flagset.IntVar(&args.Port, "port", 8443,
+		"Port on which to serve the webhook and health endpoints.")
+	flagset.StringVar(&args.ConfigFile, "config", "/etc/kubernetes/node-feature-discovery/nfd-master.conf",
+		"Config file of nfd-master to read the label namespace restrictions from.")
+	flagset.Var(overrides.DenyLabelNs, "deny-label-ns",
+		"Comma separated list of denied label namespaces. Overrides denyLabelNs of the config file.")
+	flagset.Var(overrides.ExtraLabelNs, "extra-label-ns",
+		"Comma separated list of allowed extra label namespaces. Overrides extraLabelNs of the config file.")
+
+	klog.InitFlags(flagset)
+
+	return args, overrides
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"flag"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"sigs.k8s.io/node-feature-discovery/pkg/utils"
+)
+
+func TestArgsParse(t *testing.T) {
+	Convey("When parsing command line arguments", t, func() {
+		flags := flag.NewFlagSet(ProgramName, flag.ExitOnError)
+
+		Convey("When valid flags are specified", func() {
+			args := parseArgs(flags,
+				"-cert-file=tls.crt",
+				"-key-file=tls.key",
+				"-port=9443",
+				"-deny-label-ns=*.denied.io,denied.example.com",
+				"-extra-label-ns=extra.kubernetes.io")
+
+			Convey("args are set to appropriate values", func() {
+				So(args.CertFile, ShouldEqual, "tls.crt")
+				So(args.KeyFile, ShouldEqual, "tls.key")
+				So(args.Port, ShouldEqual, 9443)
+				So(*args.Overrides.DenyLabelNs, ShouldResemble, utils.StringSetVal{"*.denied.io": {}, "denied.example.com": {}})
+				So(*args.Overrides.ExtraLabelNs, ShouldResemble, utils.StringSetVal{"extra.kubernetes.io": {}})
+			})
+		})
+
+		Convey("When no overrides are specified", func() {
+			args := parseArgs(flags, "-config=/etc/nfd/nfd-master.conf")
+
+			Convey("config file is set and nothing is overridden", func() {
+				So(args.ConfigFile, ShouldEqual, "/etc/nfd/nfd-master.conf")
+				So(args.Overrides.DenyLabelNs, ShouldBeNil)
+				So(args.Overrides.ExtraLabelNs, ShouldBeNil)
+			})
+		})
+	})
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"k8s.io/klog/v2"
+	pluginapi "k8s.io/kubelet/pkg/apis/deviceplugin/v1beta1"
+
+	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
+	nfddeviceplugin "sigs.k8s.io/node-feature-discovery/pkg/nfd-device-plugin"
+	"sigs.k8s.io/node-feature-discovery/pkg/utils"
+	"sigs.k8s.io/node-feature-discovery/pkg/version"
+)
+
+const (
+	// ProgramName is the canonical name of this program
+	ProgramName = "nfd-device-plugin"
+)
+
+func main() {
+	flags := flag.NewFlagSet(ProgramName, flag.ExitOnError)
+
+	printVersion := flags.Bool("version", false, "Print version and exit.")
+
+	args := parseArgs(flags, os.Args[1:]...)
+
+	if *printVersion {
+		fmt.Println(ProgramName, version.Get())
+		os.Exit(0)
+	}
+
+	// Assert that the version is known
+	if version.Undefined() {
+		klog.InfoS("version not set! Set -ldflags \"-X sigs.k8s.io/node-feature-discovery/pkg/version.version=`git describe --tags --dirty --always --match 'v*'`\" during build or run.")
+	}
+
+	// Get new device plugin instance
+	plugin, err := nfddeviceplugin.New(args)
+	if err != nil {
+		klog.ErrorS(err, "failed to initialize nfd device plugin instance")
+		os.Exit(1)
+	}
+
+	utils.StopOnSignal(plugin.Stop)
+	if err = plugin.Run(); err != nil {
+		klog.ErrorS(err, "error while running")
+		os.Exit(1)
+	}
+}
+
+func parseArgs(flags *flag.FlagSet, osArgs ...string) *nfddeviceplugin.Args {
+	args := initFlags(flags)
+
+	_ = flags.Parse(osArgs)
+	if len(flags.Args()) > 0 {
+		fmt.Fprintf(flags.Output(), "unknown command line argument: %s\n", flags.Args()[0])
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	return args
+}
+
+func initFlags(flagset *flag.FlagSet) *nfddeviceplugin.Args {
+	args := &nfddeviceplugin.Args{}
+
+	flagset.StringVar(&args.Instance, "instance", "",
+		"Instance name of the nfd-master whose extended resources to advertise.")
+	flagset.StringVar(&args.AnnotationNs, "annotation-ns", nfdv1alpha1.AnnotationNs,
+		"Annotation namespace of the nfd-master whose extended resources to advertise.")
+	flagset.StringVar(&args.Kubeconfig, "kubeconfig", "",
+		"Kubeconfig to use")
+	flagset.StringVar(&args.PluginDir, "plugin-dir", pluginapi.DevicePluginPath,
+		"Kubelet device plugin directory.")
+
+	klog.InitFlags(flagset)
+
+	return args
+}
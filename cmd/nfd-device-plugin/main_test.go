@@ -0,0 +1,49 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"flag"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestArgsParse(t *testing.T) {
+	Convey("When parsing command line arguments", t, func() {
+		flags := flag.NewFlagSet(ProgramName, flag.ExitOnError)
+
+		Convey("When no flags are specified", func() {
+			args := parseArgs(flags)
+
+			Convey("args.PluginDir is set to the kubelet default", func() {
+				So(args.PluginDir, ShouldEqual, "/var/lib/kubelet/device-plugins/")
+			})
+		})
+
+		Convey("When -plugin-dir and -instance are specified", func() {
+			args := parseArgs(flags,
+				"-plugin-dir=/tmp/plugins",
+				"-instance=foo")
+
+			Convey("args are set to appropriate values", func() {
+				So(args.PluginDir, ShouldEqual, "/tmp/plugins")
+				So(args.Instance, ShouldEqual, "foo")
+			})
+		})
+	})
+}
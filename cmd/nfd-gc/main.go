@@ -24,7 +24,9 @@ import (
 
 	"k8s.io/klog/v2"
 
+	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
 	nfdgarbagecollector "sigs.k8s.io/node-feature-discovery/pkg/nfd-gc"
+	"sigs.k8s.io/node-feature-discovery/pkg/utils"
 	"sigs.k8s.io/node-feature-discovery/pkg/version"
 )
 
@@ -57,6 +59,7 @@ func main() {
 		os.Exit(1)
 	}
 
+	utils.StopOnSignal(gc.Stop)
 	if err = gc.Run(); err != nil {
 		klog.ErrorS(err, "error while running")
 		os.Exit(1)
@@ -85,6 +88,18 @@ func initFlags(flagset *flag.FlagSet) *nfdgarbagecollector.Args {
 		"Kubeconfig to use")
 	flagset.IntVar(&args.MetricsPort, "metrics", 8081,
 		"Port on which to expose metrics.")
+	flagset.StringVar(&args.MetricsCertFile, "metrics-cert-file", "",
+		"Certificate file used for serving metrics and health endpoints over HTTPS.")
+	flagset.StringVar(&args.MetricsKeyFile, "metrics-key-file", "",
+		"Private key file used for serving metrics and health endpoints over HTTPS.")
+	flagset.BoolVar(&args.PruneNodeLabels, "prune-node-labels", false,
+		"Remove labels, annotations and extended resources created by nfd-master from nodes without NodeFeature objects.")
+	flagset.StringVar(&args.Instance, "instance", "",
+		"Instance name of nfd-master whose labels (et al.) are pruned, see -prune-node-labels.")
+	flagset.StringVar(&args.AnnotationNs, "annotation-ns", nfdv1alpha1.AnnotationNs,
+		"Annotation namespace of nfd-master whose labels (et al.) are pruned, see -prune-node-labels.")
+	flagset.BoolVar(&args.EnableLeaderElection, "enable-leader-election", false,
+		"Enables a leader election. Enable this when running more than one replica of nfd-gc.")
 
 	klog.InitFlags(flagset)
 
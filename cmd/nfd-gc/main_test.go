@@ -37,5 +37,16 @@ func TestArgsParse(t *testing.T) {
 			})
 		})
 
+		Convey("When -instance and -annotation-ns are specified", func() {
+			args := parseArgs(flags,
+				"-instance=foo",
+				"-annotation-ns=nfd.example.com")
+
+			Convey("args are set to appropriate values", func() {
+				So(args.Instance, ShouldEqual, "foo")
+				So(args.AnnotationNs, ShouldEqual, "nfd.example.com")
+			})
+		})
+
 	})
 }
@@ -0,0 +1,259 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
+
+	"sigs.k8s.io/node-feature-discovery/pkg/features"
+	kubectlnfd "sigs.k8s.io/node-feature-discovery/pkg/kubectl-nfd"
+	worker "sigs.k8s.io/node-feature-discovery/pkg/nfd-worker"
+	"sigs.k8s.io/node-feature-discovery/pkg/utils"
+	klogutils "sigs.k8s.io/node-feature-discovery/pkg/utils/klog"
+	"sigs.k8s.io/node-feature-discovery/pkg/version"
+)
+
+const (
+	// ProgramName is the canonical name of this program
+	ProgramName = "nfd-inspect"
+)
+
+// Supported output formats
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+	outputYAML  = "yaml"
+)
+
+// inspectArgs are the command line arguments of nfd-inspect.
+type inspectArgs struct {
+	worker.Args
+	Output       string
+	ShowFeatures bool
+	Rules        string
+}
+
+func main() {
+	flags := flag.NewFlagSet(ProgramName, flag.ExitOnError)
+
+	printVersion := flags.Bool("version", false, "Print version and exit.")
+
+	// Add FeatureGates flag
+	if err := features.NFDMutableFeatureGate.Add(features.DefaultNFDFeatureGates); err != nil {
+		klog.ErrorS(err, "failed to add default feature gates")
+		os.Exit(1)
+	}
+	features.NFDMutableFeatureGate.AddFlag(flags)
+
+	args := parseArgs(flags, os.Args[1:]...)
+
+	if *printVersion {
+		fmt.Println(ProgramName, version.Get())
+		os.Exit(0)
+	}
+
+	result, err := worker.Inspect(&args.Args)
+	if err != nil {
+		klog.ErrorS(err, "feature discovery failed")
+		os.Exit(1)
+	}
+
+	// Evaluate NodeFeatureRules against the discovered features instead of
+	// printing them
+	if args.Rules != "" {
+		if errs := kubectlnfd.DryRunFeatures(args.Rules, result.Features); len(errs) > 0 {
+			for _, e := range errs {
+				fmt.Fprintln(os.Stderr, e)
+			}
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	if err := printResult(os.Stdout, result, args.Output, args.ShowFeatures); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func parseArgs(flags *flag.FlagSet, osArgs ...string) *inspectArgs {
+	args, overrides := initFlags(flags)
+
+	_ = flags.Parse(osArgs)
+	if len(flags.Args()) > 0 {
+		fmt.Fprintf(flags.Output(), "unknown command line argument: %s\n", flags.Args()[0])
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	if !slices.Contains([]string{outputTable, outputJSON, outputYAML}, args.Output) {
+		fmt.Fprintf(flags.Output(), "invalid output format %q\n", args.Output)
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	// Handle overrides
+	flags.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "feature-sources":
+			args.Overrides.FeatureSources = overrides.FeatureSources
+		case "label-sources":
+			args.Overrides.LabelSources = overrides.LabelSources
+		}
+	})
+
+	return args
+}
+
+func initFlags(flagset *flag.FlagSet) (*inspectArgs, *worker.ConfigOverrideArgs) {
+	args := &inspectArgs{}
+
+	flagset.StringVar(&args.ConfigFile, "config", "/etc/kubernetes/node-feature-discovery/nfd-worker.conf",
+		"nfd-worker config file to use. Missing file is ignored.")
+	flagset.StringVar(&args.Options, "options", "",
+		"Specify config options from command line. Config options are specified "+
+			"in the same format as in the nfd-worker config file (i.e. json or yaml).")
+	flagset.StringVar(&args.Output, "output", outputTable,
+		"Output format, one of: table, json, yaml.")
+	flagset.BoolVar(&args.ShowFeatures, "show-features", true,
+		"Print the raw features in addition to the labels.")
+	flagset.StringVar(&args.Rules, "rules", "",
+		"NodeFeatureRule file, or directory of files, to evaluate against the discovered features. "+
+			"The resulting labels, annotations, extended resources and taints are printed instead of the features.")
+
+	args.Klog = klogutils.InitKlogFlags(flagset)
+
+	// Flags overlapping with config file options
+	overrides := &worker.ConfigOverrideArgs{
+		FeatureSources: &utils.StringSliceVal{},
+		LabelSources:   &utils.StringSliceVal{},
+	}
+	flagset.Var(overrides.FeatureSources, "feature-sources",
+		"Comma separated list of feature sources. Special value 'all' enables all sources. "+
+			"Prefix the source name with '-' to disable it.")
+	flagset.Var(overrides.LabelSources, "label-sources",
+		"Comma separated list of label sources. Special value 'all' enables all sources. "+
+			"Prefix the source name with '-' to disable it.")
+
+	return args, overrides
+}
+
+// printResult writes the result of feature discovery to out in the given
+// format.
+func printResult(out io.Writer, result *worker.InspectResult, format string, showFeatures bool) error {
+	if !showFeatures {
+		result.Features = nil
+	}
+
+	switch format {
+	case outputJSON:
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(out, string(data))
+	case outputYAML:
+		data, err := yaml.Marshal(result)
+		if err != nil {
+			return err
+		}
+		fmt.Fprint(out, string(data))
+	default:
+		printTables(out, result)
+	}
+	return nil
+}
+
+// printTables writes the result of feature discovery to out as
+// human-readable tables.
+func printTables(out io.Writer, result *worker.InspectResult) {
+	newTable := func(title string, header table.Row) table.Writer {
+		t := table.NewWriter()
+		t.SetStyle(table.StyleLight)
+		t.SetOutputMirror(out)
+		t.Style().Format.Header = text.FormatDefault
+		t.SetTitle(text.Bold.Sprint(title))
+		t.AppendHeader(header)
+		return t
+	}
+
+	t := newTable("LABELS", table.Row{"Name", "Value"})
+	for _, k := range sortedKeys(result.Labels) {
+		t.AppendRow(table.Row{k, result.Labels[k]})
+	}
+	t.Render()
+
+	if f := result.Features; f != nil {
+		t = newTable("FLAGS", table.Row{"Feature", "Element"})
+		for _, k := range sortedKeys(f.Flags) {
+			for _, e := range sortedKeys(f.Flags[k].Elements) {
+				t.AppendRow(table.Row{k, e})
+			}
+		}
+		t.Render()
+
+		t = newTable("ATTRIBUTES", table.Row{"Feature", "Element", "Value"})
+		for _, k := range sortedKeys(f.Attributes) {
+			elems := f.Attributes[k].Elements
+			for _, e := range sortedKeys(elems) {
+				t.AppendRow(table.Row{k, e, elems[e]})
+			}
+		}
+		t.Render()
+
+		t = newTable("INSTANCES", table.Row{"Feature", "#", "Attributes"})
+		for _, k := range sortedKeys(f.Instances) {
+			for i, inst := range f.Instances[k].Elements {
+				attrs := make([]string, 0, len(inst.Attributes))
+				for _, a := range sortedKeys(inst.Attributes) {
+					attrs = append(attrs, a+"="+inst.Attributes[a])
+				}
+				t.AppendRow(table.Row{k, i, strings.Join(attrs, "\n")})
+			}
+		}
+		t.Render()
+	}
+
+	if len(result.SourceErrors) > 0 {
+		t = newTable("SOURCE ERRORS", table.Row{"Source", "Error"})
+		for _, k := range sortedKeys(result.SourceErrors) {
+			t.AppendRow(table.Row{k, result.SourceErrors[k]})
+		}
+		t.Render()
+	}
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"flag"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
+	worker "sigs.k8s.io/node-feature-discovery/pkg/nfd-worker"
+	"sigs.k8s.io/node-feature-discovery/pkg/utils"
+)
+
+func TestArgsParse(t *testing.T) {
+	Convey("When parsing command line arguments", t, func() {
+		flags := flag.NewFlagSet(ProgramName, flag.ExitOnError)
+
+		Convey("When no flags are specified", func() {
+			args := parseArgs(flags)
+
+			Convey("default values should be used", func() {
+				So(args.Output, ShouldEqual, outputTable)
+				So(args.ShowFeatures, ShouldBeTrue)
+				So(args.Overrides.FeatureSources, ShouldBeNil)
+				So(args.Overrides.LabelSources, ShouldBeNil)
+			})
+		})
+
+		Convey("When -output and source flags are specified", func() {
+			args := parseArgs(flags,
+				"-output=json",
+				"-feature-sources=cpu,kernel",
+				"-label-sources=cpu")
+
+			Convey("args should be set to appropriate values", func() {
+				So(args.Output, ShouldEqual, outputJSON)
+				So(*args.Overrides.FeatureSources, ShouldResemble, utils.StringSliceVal{"cpu", "kernel"})
+				So(*args.Overrides.LabelSources, ShouldResemble, utils.StringSliceVal{"cpu"})
+			})
+		})
+	})
+}
+
+func TestPrintResult(t *testing.T) {
+	Convey("When printing the result of feature discovery", t, func() {
+		newResult := func() *worker.InspectResult {
+			f := nfdv1alpha1.NewFeatures()
+			f.Attributes["cpu.model"] = nfdv1alpha1.NewAttributeFeatures(map[string]string{"vendor_id": "Intel"})
+			return &worker.InspectResult{
+				Labels:   worker.Labels{"feature.node.kubernetes.io/cpu-model.vendor_id": "Intel"},
+				Features: f,
+			}
+		}
+		out := &bytes.Buffer{}
+
+		Convey("JSON output should contain labels and features", func() {
+			So(printResult(out, newResult(), outputJSON, true), ShouldBeNil)
+			So(out.String(), ShouldContainSubstring, `"feature.node.kubernetes.io/cpu-model.vendor_id": "Intel"`)
+			So(out.String(), ShouldContainSubstring, `"cpu.model"`)
+		})
+		Convey("YAML output should omit features if disabled", func() {
+			So(printResult(out, newResult(), outputYAML, false), ShouldBeNil)
+			So(out.String(), ShouldContainSubstring, "feature.node.kubernetes.io/cpu-model.vendor_id: Intel")
+			So(out.String(), ShouldNotContainSubstring, "cpu.model")
+		})
+		Convey("table output should list labels and features", func() {
+			So(printResult(out, newResult(), outputTable, true), ShouldBeNil)
+			So(out.String(), ShouldContainSubstring, "LABELS")
+			So(out.String(), ShouldContainSubstring, "ATTRIBUTES")
+			So(out.String(), ShouldContainSubstring, "vendor_id")
+		})
+	})
+}
@@ -27,6 +27,7 @@ import (
 	"sigs.k8s.io/node-feature-discovery/pkg/features"
 	master "sigs.k8s.io/node-feature-discovery/pkg/nfd-master"
 	"sigs.k8s.io/node-feature-discovery/pkg/utils"
+	"sigs.k8s.io/node-feature-discovery/pkg/utils/featuregate"
 	klogutils "sigs.k8s.io/node-feature-discovery/pkg/utils/klog"
 	"sigs.k8s.io/node-feature-discovery/pkg/version"
 )
@@ -73,6 +74,15 @@ func main() {
 			args.Overrides.ResyncPeriod = overrides.ResyncPeriod
 		case "nfd-api-parallelism":
 			args.Overrides.NfdApiParallelism = overrides.NfdApiParallelism
+		case "feature-gates":
+			// Feature gates from the command line take precedence over
+			// the config file
+			gates, err := featuregate.Parse(f.Value.String())
+			if err != nil {
+				klog.ErrorS(err, "failed to parse feature gates")
+				os.Exit(2)
+			}
+			args.FeatureGates = gates
 		}
 	})
 
@@ -93,6 +103,7 @@ func main() {
 		os.Exit(1)
 	}
 
+	utils.StopOnSignal(instance.Stop)
 	if err = instance.Run(); err != nil {
 		klog.ErrorS(err, "error while running")
 		os.Exit(1)
@@ -110,6 +121,16 @@ func initFlags(flagset *flag.FlagSet) (*master.Args, *master.ConfigOverrideArgs)
 		"Kubeconfig to use")
 	flagset.IntVar(&args.MetricsPort, "metrics", 8081,
 		"Port on which to expose metrics.")
+	flagset.StringVar(&args.MetricsCertFile, "metrics-cert-file", "",
+		"Certificate file used for serving metrics and health endpoints over HTTPS.")
+	flagset.StringVar(&args.MetricsKeyFile, "metrics-key-file", "",
+		"Private key file used for serving metrics and health endpoints over HTTPS.")
+	flagset.BoolVar(&args.EnableDebugEndpoints, "enable-debug-endpoints", false,
+		"Enable the debug endpoints, e.g. for explaining the labels of a node, on the metrics server.")
+	flagset.StringVar(&args.Tracing.Endpoint, "tracing-endpoint", "",
+		"OTLP gRPC endpoint for exporting OpenTelemetry traces. Tracing is disabled if empty.")
+	flagset.IntVar(&args.Tracing.SamplingRatePerMillion, "tracing-sampling-rate", 0,
+		"Number of traces sampled per million.")
 	flagset.IntVar(&args.GrpcHealthPort, "grpc-health", 8082,
 		"Port on which to expose the grpc health endpoint.")
 	flagset.BoolVar(&args.Prune, "prune", false,
@@ -119,6 +140,10 @@ func initFlags(flagset *flag.FlagSet) (*master.Args, *master.ConfigOverrideArgs)
 			"in the same format as in the config file (i.e. json or yaml). These options")
 	flagset.BoolVar(&args.EnableLeaderElection, "enable-leader-election", false,
 		"Enables a leader election. Enable this when running more than one replica on nfd master.")
+	flagset.IntVar(&args.ShardCount, "shard-count", 0,
+		"Number of shards the nodes are split into between nfd-master instances. Zero disables sharding. Requires the MasterSharding feature gate.")
+	flagset.IntVar(&args.ShardIndex, "shard-index", -1,
+		"Shard of nodes processed by this instance. A negative value claims a shard automatically using Lease objects.")
 
 	args.Klog = klogutils.InitKlogFlags(flagset)
 
@@ -29,6 +29,7 @@ import (
 
 	topology "sigs.k8s.io/node-feature-discovery/pkg/nfd-topology-updater"
 	"sigs.k8s.io/node-feature-discovery/pkg/resourcemonitor"
+	"sigs.k8s.io/node-feature-discovery/pkg/utils"
 	"sigs.k8s.io/node-feature-discovery/pkg/utils/hostpath"
 	"sigs.k8s.io/node-feature-discovery/pkg/version"
 )
@@ -58,6 +59,7 @@ func main() {
 		os.Exit(1)
 	}
 
+	utils.StopOnSignal(instance.Stop)
 	if err = instance.Run(); err != nil {
 		klog.ErrorS(err, "error while running")
 		os.Exit(1)
@@ -80,13 +82,9 @@ func parseArgs(flags *flag.FlagSet, osArgs ...string) (*topology.Args, *resource
 		os.Exit(0)
 	}
 
-	if len(resourcemonitorArgs.KubeletConfigURI) == 0 {
-		nodeAddress := os.Getenv("NODE_ADDRESS")
-		if len(nodeAddress) == 0 {
-			fmt.Fprintf(flags.Output(), "unable to determine the default kubelet config endpoint 'https://${NODE_ADDRESS}:%d/configz' due to empty NODE_ADDRESS environment, "+
-				"please either define the NODE_ADDRESS environment variable or specify endpoint with the -kubelet-config-uri flag\n", kubeletSecurePort)
-			os.Exit(1)
-		}
+	// If NODE_ADDRESS is not set the kubelet config endpoint is discovered
+	// from the node object
+	if nodeAddress := os.Getenv("NODE_ADDRESS"); len(resourcemonitorArgs.KubeletConfigURI) == 0 && len(nodeAddress) > 0 {
 		if isIPv6(nodeAddress) {
 			// With IPv6 we need to wrap the IP address in brackets as we append :port below
 			nodeAddress = "[" + nodeAddress + "]"
@@ -109,6 +107,10 @@ func initFlags(flagset *flag.FlagSet) (*topology.Args, *resourcemonitor.Args) {
 		"Kube config file.")
 	flagset.IntVar(&args.MetricsPort, "metrics", 8081,
 		"Port on which to expose metrics.")
+	flagset.StringVar(&args.MetricsCertFile, "metrics-cert-file", "",
+		"Certificate file used for serving metrics and health endpoints over HTTPS.")
+	flagset.StringVar(&args.MetricsKeyFile, "metrics-key-file", "",
+		"Private key file used for serving metrics and health endpoints over HTTPS.")
 	flagset.IntVar(&args.GrpcHealthPort, "grpc-health", 8082,
 		"Port on which to expose the grpc health endpoint.")
 	flagset.DurationVar(&resourcemonitorArgs.SleepInterval, "sleep-interval", time.Duration(60)*time.Second,
@@ -116,7 +118,7 @@ func initFlags(flagset *flag.FlagSet) (*topology.Args, *resourcemonitor.Args) {
 	flagset.StringVar(&resourcemonitorArgs.Namespace, "watch-namespace", "*",
 		"Namespace to watch pods (for testing/debugging purpose). Use * for all namespaces.")
 	flagset.StringVar(&resourcemonitorArgs.KubeletConfigURI, "kubelet-config-uri", "",
-		"Kubelet config URI path. Default to kubelet configz endpoint.")
+		"Kubelet config URI path. Default to kubelet configz endpoint, determined from the NODE_ADDRESS environment variable or the node object.")
 	flagset.StringVar(&resourcemonitorArgs.APIAuthTokenFile, "api-auth-token-file", "/var/run/secrets/kubernetes.io/serviceaccount/token",
 		"API auth token file path. It is used to request kubelet configz endpoint, only takes effect when kubelet-config-uri is https. Default to /var/run/secrets/kubernetes.io/serviceaccount/token.")
 	flagset.StringVar(&resourcemonitorArgs.PodResourceSocketPath, "podresources-socket", hostpath.VarDir.Path("lib/kubelet/pod-resources/kubelet.sock"),
@@ -39,6 +39,8 @@ func main() {
 	flags := flag.NewFlagSet(ProgramName, flag.ExitOnError)
 
 	printVersion := flags.Bool("version", false, "Print version and exit.")
+	printFeatureSchema := flags.Bool("print-feature-schema", false,
+		"Print the schema of the features produced by the built-in feature sources in JSON format and exit.")
 
 	// Add FeatureGates flag
 	if err := features.NFDMutableFeatureGate.Add(features.DefaultNFDFeatureGates); err != nil {
@@ -47,18 +49,42 @@ func main() {
 	}
 	features.NFDMutableFeatureGate.AddFlag(flags)
 
-	args := parseArgs(flags, os.Args[1:]...)
+	// The check subcommand verifies that the host directories required by
+	// the enabled feature sources are available
+	osArgs := os.Args[1:]
+	check := len(osArgs) > 0 && osArgs[0] == "check"
+	if check {
+		osArgs = osArgs[1:]
+	}
+
+	args := parseArgs(flags, osArgs...)
 
 	if *printVersion {
 		fmt.Println(ProgramName, version.Get())
 		os.Exit(0)
 	}
 
+	if *printFeatureSchema {
+		if err := worker.PrintFeatureSchema(os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
 	// Assert that the version is known
 	if version.Undefined() {
 		klog.InfoS("version not set! Set -ldflags \"-X sigs.k8s.io/node-feature-discovery/pkg/version.version=`git describe --tags --dirty --always --match 'v*'`\" during build or run.")
 	}
 
+	if check {
+		if err := worker.Check(args, os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
 	// Get new NfdWorker instance
 	instance, err := worker.NewNfdWorker(worker.WithArgs(args))
 	if err != nil {
@@ -66,6 +92,7 @@ func main() {
 		os.Exit(1)
 	}
 
+	utils.StopOnSignal(instance.Stop)
 	if err = instance.Run(); err != nil {
 		klog.ErrorS(err, "error while running")
 		os.Exit(1)
@@ -110,8 +137,22 @@ func initFlags(flagset *flag.FlagSet) (*worker.Args, *worker.ConfigOverrideArgs)
 		"Do not publish feature labels")
 	flagset.IntVar(&args.MetricsPort, "metrics", 8081,
 		"Port on which to expose metrics.")
+	flagset.StringVar(&args.MetricsCertFile, "metrics-cert-file", "",
+		"Certificate file used for serving metrics and health endpoints over HTTPS.")
+	flagset.StringVar(&args.MetricsKeyFile, "metrics-key-file", "",
+		"Private key file used for serving metrics and health endpoints over HTTPS.")
+	flagset.IntVar(&args.FeatureApiPort, "feature-api-port", 0,
+		"Port on localhost on which to serve the discovered features. Zero disables the feature API.")
 	flagset.IntVar(&args.GrpcHealthPort, "grpc-health", 8082,
 		"Port on which to expose the grpc health endpoint.")
+	flagset.BoolVar(&args.Standalone, "standalone", false,
+		"Update the node object directly, without nfd-master and NodeFeature objects.")
+	flagset.StringVar(&args.MasterConfigFile, "master-config", "/etc/kubernetes/node-feature-discovery/nfd-master.conf",
+		"nfd-master config file used for processing labels in standalone mode.")
+	flagset.StringVar(&args.Tracing.Endpoint, "tracing-endpoint", "",
+		"OTLP gRPC endpoint for exporting OpenTelemetry traces. Tracing is disabled if empty.")
+	flagset.IntVar(&args.Tracing.SamplingRatePerMillion, "tracing-sampling-rate", 0,
+		"Number of traces sampled per million.")
 	flagset.StringVar(&args.Options, "options", "",
 		"Specify config options from command line. Config options are specified "+
 			"in the same format as in the config file (i.e. json or yaml). These options")
@@ -40,6 +40,15 @@ func TestParseArgs(t *testing.T) {
 			})
 		})
 
+		Convey("When standalone mode is specified", func() {
+			args := parseArgs(flags, "-standalone")
+
+			Convey("args.Standalone should be set", func() {
+				So(args.Standalone, ShouldBeTrue)
+				So(args.Oneshot, ShouldBeFalse)
+			})
+		})
+
 		Convey("When all override args are specified", func() {
 			args := parseArgs(flags,
 				"-no-publish",
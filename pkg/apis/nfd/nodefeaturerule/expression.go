@@ -50,6 +50,56 @@ var matchOps = map[nfdv1alpha1.MatchOp]struct{}{
 	nfdv1alpha1.MatchIsFalse:      {},
 }
 
+// ValidateMatchExpression checks that the MatchExpression is well-formed,
+// i.e. the operator is valid and the values are valid for the operator.
+func ValidateMatchExpression(m *nfdv1alpha1.MatchExpression) error {
+	if _, ok := matchOps[m.Op]; !ok {
+		return fmt.Errorf("invalid Op %q", m.Op)
+	}
+
+	switch m.Op {
+	case nfdv1alpha1.MatchAny, nfdv1alpha1.MatchExists, nfdv1alpha1.MatchDoesNotExist, nfdv1alpha1.MatchIsTrue, nfdv1alpha1.MatchIsFalse:
+		if len(m.Value) != 0 {
+			return fmt.Errorf("invalid expression, 'value' field must be empty for Op %q (have %v)", m.Op, m.Value)
+		}
+	case nfdv1alpha1.MatchIn, nfdv1alpha1.MatchNotIn:
+		if len(m.Value) == 0 {
+			return fmt.Errorf("invalid expression, 'value' field must be non-empty for Op %q", m.Op)
+		}
+	case nfdv1alpha1.MatchInRegexp:
+		if len(m.Value) == 0 {
+			return fmt.Errorf("invalid expression, 'value' field must be non-empty for Op %q", m.Op)
+		}
+		for _, v := range m.Value {
+			if _, err := regexp.Compile(v); err != nil {
+				return fmt.Errorf("invalid expression, invalid regexp %q for Op %q: %w", v, m.Op, err)
+			}
+		}
+	case nfdv1alpha1.MatchGt, nfdv1alpha1.MatchLt:
+		if len(m.Value) != 1 {
+			return fmt.Errorf("invalid expression, 'value' field must contain exactly one element for Op %q (have %v)", m.Op, m.Value)
+		}
+		if _, err := strconv.Atoi(m.Value[0]); err != nil {
+			return fmt.Errorf("not a number %q in %v", m.Value[0], m)
+		}
+	case nfdv1alpha1.MatchGtLt:
+		if len(m.Value) != 2 {
+			return fmt.Errorf("invalid expression, value' field must contain exactly two elements for Op %q (have %v)", m.Op, m.Value)
+		}
+		lr := make([]int, 2)
+		for i := range lr {
+			var err error
+			if lr[i], err = strconv.Atoi(m.Value[i]); err != nil {
+				return fmt.Errorf("not a number %q in %v", m.Value[i], m)
+			}
+		}
+		if lr[0] >= lr[1] {
+			return fmt.Errorf("invalid expression, value[0] must be less than Value[1] for Op %q (have %v)", m.Op, m.Value)
+		}
+	}
+	return nil
+}
+
 // evaluateMatchExpression evaluates the MatchExpression against a single input value.
 func evaluateMatchExpression(m *nfdv1alpha1.MatchExpression, valid bool, value interface{}) (bool, error) {
 	if _, ok := matchOps[m.Op]; !ok {
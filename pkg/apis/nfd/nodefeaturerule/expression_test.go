@@ -146,6 +146,38 @@ func TestEvaluateMatchExpression(t *testing.T) {
 	}
 }
 
+func TestValidateMatchExpression(t *testing.T) {
+	type V = nfdv1alpha1.MatchValue
+	tcs := []struct {
+		op     nfdv1alpha1.MatchOp
+		values V
+		valid  bool
+	}{
+		{op: nfdv1alpha1.MatchAny, valid: true},
+		{op: nfdv1alpha1.MatchAny, values: V{"1"}},
+		{op: nfdv1alpha1.MatchExists, valid: true},
+		{op: nfdv1alpha1.MatchIn, values: V{"1"}, valid: true},
+		{op: nfdv1alpha1.MatchIn},
+		{op: nfdv1alpha1.MatchInRegexp, values: V{"^a.*", "b+"}, valid: true},
+		{op: nfdv1alpha1.MatchInRegexp, values: V{"^a.*", "("}},
+		{op: nfdv1alpha1.MatchGt, values: V{"1"}, valid: true},
+		{op: nfdv1alpha1.MatchGt, values: V{"a"}},
+		{op: nfdv1alpha1.MatchLt, values: V{"1", "2"}},
+		{op: nfdv1alpha1.MatchGtLt, values: V{"1", "3"}, valid: true},
+		{op: nfdv1alpha1.MatchGtLt, values: V{"3", "1"}},
+		{op: nfdv1alpha1.MatchIsTrue, values: V{"true"}},
+		{op: "foo"},
+	}
+	for _, tc := range tcs {
+		err := ValidateMatchExpression(&nfdv1alpha1.MatchExpression{Op: tc.op, Value: tc.values})
+		if tc.valid {
+			assert.NoError(t, err, "op %q values %v", tc.op, tc.values)
+		} else {
+			assert.Error(t, err, "op %q values %v", tc.op, tc.values)
+		}
+	}
+}
+
 func TestEvaluateMatchExpressionKeys(t *testing.T) {
 	type V = nfdv1alpha1.MatchValue
 	type I = map[string]nfdv1alpha1.Nil
@@ -77,6 +77,15 @@ func Execute(r *nfdv1alpha1.Rule, features *nfdv1alpha1.Features, failFast bool)
 	labels := make(map[string]string)
 	vars := make(map[string]string)
 
+	if r.MatchNode != nil {
+		if isMatch, err = evaluateNodeMatcher(r.MatchNode, features, failFast); err != nil {
+			return RuleOutput{}, err
+		} else if !isMatch {
+			return noMatchOutput(r, &matchStatus), nil
+		}
+		klog.V(4).InfoS("matchNode matched", "ruleName", r.Name)
+	}
+
 	if n := len(r.MatchAny); n > 0 {
 		matchStatus.MatchAny = make([]*MatchFeatureStatus, 0, n)
 		// Logical OR over the matchAny matchers
@@ -110,8 +119,7 @@ func Execute(r *nfdv1alpha1.Rule, features *nfdv1alpha1.Features, failFast bool)
 		}
 
 		if !isMatch {
-			klog.V(2).InfoS("rule did not match", "ruleName", r.Name)
-			return RuleOutput{MatchStatus: &matchStatus}, nil
+			return noMatchOutput(r, &matchStatus), nil
 		}
 	}
 
@@ -119,8 +127,7 @@ func Execute(r *nfdv1alpha1.Rule, features *nfdv1alpha1.Features, failFast bool)
 		if isMatch, matchStatus.MatchFeatureStatus, err = evaluateFeatureMatcher(&r.MatchFeatures, features, failFast); err != nil {
 			return RuleOutput{}, err
 		} else if !isMatch {
-			klog.V(2).InfoS("rule did not match", "ruleName", r.Name)
-			return RuleOutput{MatchStatus: &matchStatus}, nil
+			return noMatchOutput(r, &matchStatus), nil
 		} else {
 			klog.V(4).InfoS("matchFeatures matched", "ruleName", r.Name, "matchedFeatures", utils.DelayedDumper(matchStatus.MatchedFeatures))
 			if err := executeLabelsTemplate(r, matchStatus.MatchedFeatures, labels); err != nil {
@@ -148,9 +155,32 @@ func Execute(r *nfdv1alpha1.Rule, features *nfdv1alpha1.Features, failFast bool)
 	return ret, nil
 }
 
+// noMatchOutput returns the output of a rule that did not match, i.e. the
+// else branch of the rule.
+func noMatchOutput(r *nfdv1alpha1.Rule, matchStatus *MatchStatus) RuleOutput {
+	ret := RuleOutput{MatchStatus: matchStatus}
+	if len(r.ElseLabels) > 0 || len(r.ElseTaints) > 0 {
+		ret.Labels = maps.Clone(r.ElseLabels)
+		ret.Taints = slices.Clone(r.ElseTaints)
+		klog.V(2).InfoS("rule did not match, applying else branch", "ruleName", r.Name, "ruleOutput", utils.DelayedDumper(ret))
+	} else {
+		klog.V(2).InfoS("rule did not match", "ruleName", r.Name)
+	}
+	return ret
+}
+
 // ExecuteGroupRule executes the GroupRule against a set of input features, and return true if the
 // rule matches.
 func ExecuteGroupRule(r *nfdv1alpha1.GroupRule, features *nfdv1alpha1.Features, failFast bool) (bool, error) {
+	if r.MatchNode != nil {
+		if isMatch, err := evaluateNodeMatcher(r.MatchNode, features, failFast); err != nil {
+			return false, err
+		} else if !isMatch {
+			klog.V(2).InfoS("rule did not match", "ruleName", r.Name)
+			return false, nil
+		}
+	}
+
 	matched := false
 	if len(r.MatchAny) > 0 {
 		// Logical OR over the matchAny matchers
@@ -227,6 +257,33 @@ type matchedFeatures map[string]domainMatchedFeatures
 
 type domainMatchedFeatures map[string][]MatchedElement
 
+// evaluateNodeMatcher evaluates a NodeMatcher against the metadata of the
+// node object, available in the special node.label and node.annotation
+// features.
+func evaluateNodeMatcher(m *nfdv1alpha1.NodeMatcher, features *nfdv1alpha1.Features, failFast bool) (bool, error) {
+	matchers := []struct {
+		feature string
+		exprs   *nfdv1alpha1.MatchExpressionSet
+	}{
+		{nfdv1alpha1.NodeMetadataDomain + "." + nfdv1alpha1.NodeLabelFeature, m.Labels},
+		{nfdv1alpha1.NodeMetadataDomain + "." + nfdv1alpha1.NodeAnnotationFeature, m.Annotations},
+	}
+	for _, matcher := range matchers {
+		if matcher.exprs == nil {
+			continue
+		}
+		// Missing metadata is treated as empty so that e.g. DoesNotExist
+		// matches
+		values := features.Attributes[matcher.feature].Elements
+		if isMatch, _, err := MatchValues(matcher.exprs, values, failFast); err != nil {
+			return false, fmt.Errorf("failed to evaluate matchNode against %s: %w", matcher.feature, err)
+		} else if !isMatch {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
 func evaluateMatchAnyElem(e *nfdv1alpha1.MatchAnyElem, features *nfdv1alpha1.Features, failFast bool) (bool, *MatchFeatureStatus, error) {
 	return evaluateFeatureMatcher(&e.MatchFeatures, features, failFast)
 }
@@ -315,7 +372,7 @@ type templateHelper struct {
 }
 
 func newTemplateHelper(name string) (*templateHelper, error) {
-	tmpl, err := template.New("").Option("missingkey=error").Parse(name)
+	tmpl, err := NewTemplate(name)
 	if err != nil {
 		return nil, fmt.Errorf("invalid template: %w", err)
 	}
@@ -20,6 +20,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
 
 	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
 )
@@ -291,6 +292,113 @@ func TestRule(t *testing.T) {
 	assert.Equal(t, r3.Labels, m.Labels, "instances should have matched")
 }
 
+func TestRuleElse(t *testing.T) {
+	f := nfdv1alpha1.NewFeatures()
+	f.Flags["domain-1.kf-1"] = nfdv1alpha1.NewFlagFeatures("key-1")
+	taint := corev1.Taint{Key: "example.com/taint", Effect: corev1.TaintEffectNoSchedule}
+
+	newRule := func(feature string) *nfdv1alpha1.Rule {
+		return &nfdv1alpha1.Rule{
+			Labels:     map[string]string{"label-1": "true"},
+			Vars:       map[string]string{"var-1": "true"},
+			ElseLabels: map[string]string{"label-1": "false", "label-2": "true"},
+			ElseTaints: []corev1.Taint{taint},
+			MatchFeatures: nfdv1alpha1.FeatureMatcher{
+				nfdv1alpha1.FeatureMatcherTerm{
+					Feature:          feature,
+					MatchExpressions: &nfdv1alpha1.MatchExpressionSet{"key-1": newMatchExpression(nfdv1alpha1.MatchExists)},
+				},
+			},
+		}
+	}
+
+	// Matching rule must not produce the else outputs
+	r := newRule("domain-1.kf-1")
+	m, err := Execute(r, f, true)
+	assert.NoError(t, err)
+	assert.True(t, m.MatchStatus.IsMatch)
+	assert.Equal(t, r.Labels, m.Labels)
+	assert.Equal(t, r.Vars, m.Vars)
+	assert.Empty(t, m.Taints)
+
+	// Non-matching rule produces the else outputs
+	r = newRule("domain-1.kf-2")
+	m, err = Execute(r, f, true)
+	assert.NoError(t, err)
+	assert.False(t, m.MatchStatus.IsMatch)
+	assert.Equal(t, r.ElseLabels, m.Labels)
+	assert.Equal(t, r.ElseTaints, m.Taints)
+	assert.Empty(t, m.Vars)
+
+	// The else branch also applies to matchAny
+	r = newRule("domain-1.kf-2")
+	r.MatchAny = []nfdv1alpha1.MatchAnyElem{{MatchFeatures: r.MatchFeatures}}
+	r.MatchFeatures = nil
+	m, err = Execute(r, f, true)
+	assert.NoError(t, err)
+	assert.False(t, m.MatchStatus.IsMatch)
+	assert.Equal(t, r.ElseLabels, m.Labels)
+	assert.Equal(t, r.ElseTaints, m.Taints)
+}
+
+func TestRuleMatchNode(t *testing.T) {
+	f := nfdv1alpha1.NewFeatures()
+	f.Flags["domain-1.kf-1"] = nfdv1alpha1.NewFlagFeatures("key-1")
+	f.InsertAttributeFeatures(nfdv1alpha1.NodeMetadataDomain, nfdv1alpha1.NodeLabelFeature, map[string]string{"node-role.kubernetes.io/worker": ""})
+	f.InsertAttributeFeatures(nfdv1alpha1.NodeMetadataDomain, nfdv1alpha1.NodeAnnotationFeature, map[string]string{"example.com/rack": "r1"})
+
+	r := &nfdv1alpha1.Rule{
+		Labels: map[string]string{"label-1": "true"},
+		MatchFeatures: nfdv1alpha1.FeatureMatcher{
+			nfdv1alpha1.FeatureMatcherTerm{
+				Feature:          "domain-1.kf-1",
+				MatchExpressions: &nfdv1alpha1.MatchExpressionSet{"key-1": newMatchExpression(nfdv1alpha1.MatchExists)},
+			},
+		},
+		MatchNode: &nfdv1alpha1.NodeMatcher{
+			Labels:      &nfdv1alpha1.MatchExpressionSet{"node-role.kubernetes.io/worker": newMatchExpression(nfdv1alpha1.MatchExists)},
+			Annotations: &nfdv1alpha1.MatchExpressionSet{"example.com/rack": newMatchExpression(nfdv1alpha1.MatchIn, "r1", "r2")},
+		},
+	}
+
+	// Both matchFeatures and matchNode match
+	m, err := Execute(r, f, true)
+	assert.NoError(t, err)
+	assert.True(t, m.MatchStatus.IsMatch)
+	assert.Equal(t, r.Labels, m.Labels)
+
+	// Mismatching annotation
+	r.MatchNode.Annotations = &nfdv1alpha1.MatchExpressionSet{"example.com/rack": newMatchExpression(nfdv1alpha1.MatchIn, "r3")}
+	m, err = Execute(r, f, true)
+	assert.NoError(t, err)
+	assert.False(t, m.MatchStatus.IsMatch)
+	assert.Empty(t, m.Labels)
+
+	// Missing node metadata is treated as empty
+	r.MatchNode.Annotations = nil
+	r.MatchNode.Labels = &nfdv1alpha1.MatchExpressionSet{"node-role.kubernetes.io/worker": newMatchExpression(nfdv1alpha1.MatchDoesNotExist)}
+	m, err = Execute(r, nfdv1alpha1.NewFeatures(), true)
+	assert.NoError(t, err)
+	assert.False(t, m.MatchStatus.IsMatch, "matchFeatures should not have matched")
+	r.MatchFeatures = nil
+	m, err = Execute(r, nfdv1alpha1.NewFeatures(), true)
+	assert.NoError(t, err)
+	assert.True(t, m.MatchStatus.IsMatch)
+
+	// Group rules
+	gr := &nfdv1alpha1.GroupRule{
+		MatchNode: &nfdv1alpha1.NodeMatcher{
+			Labels: &nfdv1alpha1.MatchExpressionSet{"node-role.kubernetes.io/worker": newMatchExpression(nfdv1alpha1.MatchExists)},
+		},
+	}
+	match, err := ExecuteGroupRule(gr, f, true)
+	assert.NoError(t, err)
+	assert.True(t, match)
+	match, err = ExecuteGroupRule(gr, nfdv1alpha1.NewFeatures(), true)
+	assert.NoError(t, err)
+	assert.False(t, match)
+}
+
 func TestTemplating(t *testing.T) {
 	f := &nfdv1alpha1.Features{
 		Flags: map[string]nfdv1alpha1.FlagFeatureSet{
@@ -557,3 +665,55 @@ var-2=
 	assert.Nilf(t, err, "unexpected error: %v", err)
 	assert.Equal(t, map[string]string(nil), m.Labels, "instances should have matched")
 }
+
+func TestTemplateFuncs(t *testing.T) {
+	f := &nfdv1alpha1.Features{
+		Attributes: map[string]nfdv1alpha1.AttributeFeatureSet{
+			"driver.version": {
+				Elements: map[string]string{
+					"nvidia": "V550.54.15-Open",
+				},
+			},
+		},
+	}
+	newRule := func(tmpl string) *nfdv1alpha1.Rule {
+		return &nfdv1alpha1.Rule{
+			LabelsTemplate: tmpl,
+			MatchFeatures: nfdv1alpha1.FeatureMatcher{
+				nfdv1alpha1.FeatureMatcherTerm{
+					Feature:   "driver.version",
+					MatchName: newMatchExpression(nfdv1alpha1.MatchExists),
+				},
+			},
+		}
+	}
+
+	tcs := []struct {
+		name     string
+		template string
+		expected string
+	}{
+		{"lower", `{{range .driver.version}}{{.Name}}={{.Value | lower}}{{end}}`, "v550.54.15-open"},
+		{"upper", `{{range .driver.version}}{{.Name}}={{.Value | upper}}{{end}}`, "V550.54.15-OPEN"},
+		{"replace", `{{range .driver.version}}{{.Name}}={{.Value | replace "." "_"}}{{end}}`, "V550_54_15-Open"},
+		{"trimPrefix", `{{range .driver.version}}{{.Name}}={{.Value | trimPrefix "V"}}{{end}}`, "550.54.15-Open"},
+		{"trimSuffix", `{{range .driver.version}}{{.Name}}={{.Value | trimSuffix "-Open"}}{{end}}`, "V550.54.15"},
+		{"regexReplace", `{{range .driver.version}}{{.Name}}={{.Value | regexReplace "^V([0-9]+)\\..*$" "$1"}}{{end}}`, "550"},
+		{"printf", `{{range .driver.version}}{{.Name}}={{printf "%s-%d" (.Value | lower) 1}}{{end}}`, "v550.54.15-open-1"},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			m, err := Execute(newRule(tc.template), f, true)
+			assert.NoError(t, err)
+			assert.Equal(t, map[string]string{"nvidia": tc.expected}, m.Labels)
+		})
+	}
+
+	// Invalid regexp
+	_, err := Execute(newRule(`{{range .driver.version}}{{.Name}}={{.Value | regexReplace "(" ""}}{{end}}`), f, true)
+	assert.Error(t, err)
+
+	// Unknown function
+	_, err = Execute(newRule(`{{range .driver.version}}{{.Name}}={{.Value | foo}}{{end}}`), f, true)
+	assert.Error(t, err)
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodefeaturerule
+
+import (
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+// TemplateFuncs returns the functions available in rule templates, in
+// addition to the built-in functions of text/template (e.g. printf). The
+// string to operate on is the last argument of each function so that they
+// can be used in pipelines, e.g. {{ .Value | replace "-" "_" | lower }}.
+func TemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"lower":        strings.ToLower,
+		"upper":        strings.ToUpper,
+		"replace":      templateReplace,
+		"trimPrefix":   templateTrimPrefix,
+		"trimSuffix":   templateTrimSuffix,
+		"regexReplace": templateRegexReplace,
+	}
+}
+
+// NewTemplate parses a rule template.
+func NewTemplate(text string) (*template.Template, error) {
+	return template.New("").Option("missingkey=error").Funcs(TemplateFuncs()).Parse(text)
+}
+
+func templateReplace(old, new, s string) string {
+	return strings.ReplaceAll(s, old, new)
+}
+
+func templateTrimPrefix(prefix, s string) string {
+	return strings.TrimPrefix(s, prefix)
+}
+
+func templateTrimSuffix(suffix, s string) string {
+	return strings.TrimSuffix(s, suffix)
+}
+
+// templateRegexReplace replaces all matches of the regular expression in s
+// with repl. Submatches can be referenced in repl with $1, ${name} etc.
+func templateRegexReplace(pattern, repl, s string) (string, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return "", err
+	}
+	return re.ReplaceAllString(s, repl), nil
+}
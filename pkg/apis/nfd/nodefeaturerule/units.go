@@ -0,0 +1,101 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodefeaturerule
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// UnitConversionSeparator separates the optional unit conversion from the
+// feature reference in dynamic values, e.g. "@memory.swap.total_mb|Mi:Gi".
+const UnitConversionSeparator = "|"
+
+type unit struct {
+	family string
+	factor *big.Int
+}
+
+func newUnit(family string, base, exp int64) unit {
+	f := new(big.Int).Exp(big.NewInt(base), big.NewInt(exp), nil)
+	return unit{family: family, factor: f}
+}
+
+// units contains the supported units of the unit conversions. Only units of
+// the same family can be converted to each other.
+var units = map[string]unit{
+	"":    newUnit("", 1, 0),
+	"Ki":  newUnit("size", 1024, 1),
+	"Mi":  newUnit("size", 1024, 2),
+	"Gi":  newUnit("size", 1024, 3),
+	"Ti":  newUnit("size", 1024, 4),
+	"Pi":  newUnit("size", 1024, 5),
+	"Ei":  newUnit("size", 1024, 6),
+	"k":   newUnit("size", 1000, 1),
+	"M":   newUnit("size", 1000, 2),
+	"G":   newUnit("size", 1000, 3),
+	"T":   newUnit("size", 1000, 4),
+	"P":   newUnit("size", 1000, 5),
+	"E":   newUnit("size", 1000, 6),
+	"Hz":  newUnit("frequency", 1000, 0),
+	"kHz": newUnit("frequency", 1000, 1),
+	"MHz": newUnit("frequency", 1000, 2),
+	"GHz": newUnit("frequency", 1000, 3),
+}
+
+// SplitUnitConversion splits a dynamic value into the feature reference and
+// the unit conversion. The unit conversion is empty if not specified.
+func SplitUnitConversion(value string) (string, string) {
+	ref, conversion, _ := strings.Cut(value, UnitConversionSeparator)
+	return ref, conversion
+}
+
+// ConvertUnits converts an integer value according to the unit conversion.
+// The conversion is of the form "<from>:<to>", or "<to>" in which case the
+// value is assumed to be in the base unit (e.g. bytes). The result is rounded
+// down to the nearest integer. An empty conversion returns the value as is.
+func ConvertUnits(value, conversion string) (string, error) {
+	if conversion == "" {
+		return value, nil
+	}
+
+	fromName, toName, ok := strings.Cut(conversion, ":")
+	if !ok {
+		fromName, toName = "", conversion
+	}
+	from, ok := units[fromName]
+	if !ok {
+		return "", fmt.Errorf("unknown unit %q", fromName)
+	}
+	to, ok := units[toName]
+	if !ok || toName == "" {
+		return "", fmt.Errorf("unknown unit %q", toName)
+	}
+	// Plain values (in the base unit) can be converted to any unit
+	if from.family != "" && from.family != to.family {
+		return "", fmt.Errorf("cannot convert %s to %s", fromName, toName)
+	}
+
+	v, ok := new(big.Int).SetString(strings.TrimSpace(value), 10)
+	if !ok {
+		return "", fmt.Errorf("value %q is not an integer", value)
+	}
+	v.Mul(v, from.factor)
+	v.Div(v, to.factor)
+	return v.String(), nil
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodefeaturerule
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitUnitConversion(t *testing.T) {
+	ref, conv := SplitUnitConversion("@memory.swap.total_mb|Mi:Gi")
+	assert.Equal(t, "@memory.swap.total_mb", ref)
+	assert.Equal(t, "Mi:Gi", conv)
+
+	ref, conv = SplitUnitConversion("@kernel.version.major")
+	assert.Equal(t, "@kernel.version.major", ref)
+	assert.Equal(t, "", conv)
+}
+
+func TestConvertUnits(t *testing.T) {
+	tcs := []struct {
+		value      string
+		conversion string
+		expected   string
+		fail       bool
+	}{
+		{value: "123", conversion: "", expected: "123"},
+		{value: "abc", conversion: "", expected: "abc"},
+		{value: "17179869184", conversion: "Gi", expected: "16"},
+		{value: "17179869183", conversion: "Gi", expected: "15"},
+		{value: "16384", conversion: "Mi:Gi", expected: "16"},
+		{value: "2", conversion: "Gi:Mi", expected: "2048"},
+		{value: "3000", conversion: "k:M", expected: "3"},
+		{value: "1048576", conversion: "Ki:G", expected: "1"},
+		{value: "3600000", conversion: "kHz:GHz", expected: "3"},
+		{value: "3600000000", conversion: "MHz", expected: "3600"},
+		{value: "1", conversion: "Ei:Ki", expected: "1125899906842624"},
+		{value: "1", conversion: "Xi", fail: true},
+		{value: "1", conversion: "Xi:Gi", fail: true},
+		{value: "1", conversion: "Gi:", fail: true},
+		{value: "1", conversion: "kHz:Gi", fail: true},
+		{value: "1.5", conversion: "Gi", fail: true},
+		{value: "abc", conversion: "Gi", fail: true},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.value+"|"+tc.conversion, func(t *testing.T) {
+			out, err := ConvertUnits(tc.value, tc.conversion)
+			if tc.fail {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expected, out)
+			}
+		})
+	}
+}
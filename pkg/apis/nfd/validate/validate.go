@@ -18,14 +18,15 @@ package validate
 
 import (
 	"fmt"
+	"sort"
 	"strings"
-	"text/template"
 
 	corev1 "k8s.io/api/core/v1"
 	k8sQuantity "k8s.io/apimachinery/pkg/api/resource"
 	k8svalidation "k8s.io/apimachinery/pkg/util/validation"
 
 	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
+	"sigs.k8s.io/node-feature-discovery/pkg/apis/nfd/nodefeaturerule"
 )
 
 var (
@@ -61,8 +62,131 @@ func MatchFeatures(matchFeature nfdv1alpha1.FeatureMatcher) []error {
 		if len(nameSplit) != 2 {
 			validationErr = append(validationErr, fmt.Errorf("invalid feature name %v (not <domain>.<feature>), cannot be used for templating", match.Feature))
 		}
+		if match.MatchExpressions != nil {
+			names := make([]string, 0, len(*match.MatchExpressions))
+			for name := range *match.MatchExpressions {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				if (*match.MatchExpressions)[name] == nil {
+					continue
+				}
+				if err := nodefeaturerule.ValidateMatchExpression((*match.MatchExpressions)[name]); err != nil {
+					validationErr = append(validationErr, fmt.Errorf("invalid match expression for %q of feature %s: %w", name, match.Feature, err))
+				}
+			}
+		}
+		if match.MatchName != nil {
+			if err := nodefeaturerule.ValidateMatchExpression(match.MatchName); err != nil {
+				validationErr = append(validationErr, fmt.Errorf("invalid matchName expression of feature %s: %w", match.Feature, err))
+			}
+		}
+	}
+
+	return validationErr
+}
+
+// MatchNode validates a NodeMatcher and returns a slice of errors if any of
+// the expressions are invalid.
+func MatchNode(matchNode *nfdv1alpha1.NodeMatcher) []error {
+	if matchNode == nil {
+		return nil
+	}
+	var validationErr []error
+
+	for _, m := range []struct {
+		field string
+		exprs *nfdv1alpha1.MatchExpressionSet
+	}{{"labels", matchNode.Labels}, {"annotations", matchNode.Annotations}} {
+		if m.exprs == nil {
+			continue
+		}
+		names := make([]string, 0, len(*m.exprs))
+		for name := range *m.exprs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if (*m.exprs)[name] == nil {
+				continue
+			}
+			if err := nodefeaturerule.ValidateMatchExpression((*m.exprs)[name]); err != nil {
+				validationErr = append(validationErr, fmt.Errorf("invalid matchNode expression for %q of %s: %w", name, m.field, err))
+			}
+		}
+	}
+
+	return validationErr
+}
+
+// Rule validates a NodeFeatureRule rule and returns a slice of errors if the
+// rule is invalid. Dynamic values of labels and extended resources (starting
+// with '@') are not resolved but only checked for syntax.
+func Rule(rule *nfdv1alpha1.Rule) []error {
+	var validationErr []error
+
+	if rule.Name == "" {
+		validationErr = append(validationErr, fmt.Errorf("rule name cannot be empty"))
+	}
+
+	validationErr = append(validationErr, Annotations(rule.Annotations)...)
+
+	// Dummy dynamic values before validating labels
+	labels := make(map[string]string, len(rule.Labels))
+	for k, v := range rule.Labels {
+		if strings.HasPrefix(v, "@") {
+			v = k8sQuantity.NewQuantity(0, k8sQuantity.DecimalSI).String()
+		}
+		labels[k] = v
+	}
+	validationErr = append(validationErr, Labels(labels)...)
+	validationErr = append(validationErr, Taints(rule.Taints)...)
+
+	// Validate else branch
+	validationErr = append(validationErr, Labels(rule.ElseLabels)...)
+	validationErr = append(validationErr, Taints(rule.ElseTaints)...)
+
+	// Dummy dynamic values before validating extended resources
+	extendedResources := make(map[string]string, len(rule.ExtendedResources))
+	for k, v := range rule.ExtendedResources {
+		if strings.HasPrefix(v, "@") {
+			if _, conversion := nodefeaturerule.SplitUnitConversion(v); conversion != "" {
+				if _, err := nodefeaturerule.ConvertUnits("0", conversion); err != nil {
+					validationErr = append(validationErr, fmt.Errorf("invalid unit conversion of extended resource %q: %w", k, err))
+				}
+			}
+			v = k8sQuantity.NewQuantity(0, k8sQuantity.DecimalSI).String()
+		}
+		extendedResources[k] = v
 	}
+	validationErr = append(validationErr, ExtendedResources(extendedResources)...)
 
+	validationErr = append(validationErr, Template(rule.LabelsTemplate)...)
+	validationErr = append(validationErr, Template(rule.VarsTemplate)...)
+	validationErr = append(validationErr, MatchFeatures(rule.MatchFeatures)...)
+	validationErr = append(validationErr, MatchAny(rule.MatchAny)...)
+	validationErr = append(validationErr, MatchNode(rule.MatchNode)...)
+
+	return validationErr
+}
+
+// NodeFeatureGroupSpec validates all rules of a NodeFeatureGroup and returns a
+// slice of errors if any of the rules are invalid.
+func NodeFeatureGroupSpec(spec *nfdv1alpha1.NodeFeatureGroupSpec) []error {
+	var validationErr []error
+	for _, rule := range spec.Rules {
+		var errs []error
+		if rule.Name == "" {
+			errs = append(errs, fmt.Errorf("rule name cannot be empty"))
+		}
+		errs = append(errs, MatchFeatures(rule.MatchFeatures)...)
+		errs = append(errs, MatchAny(rule.MatchAny)...)
+		errs = append(errs, MatchNode(rule.MatchNode)...)
+		for _, err := range errs {
+			validationErr = append(validationErr, fmt.Errorf("rule %q: %w", rule.Name, err))
+		}
+	}
 	return validationErr
 }
 
@@ -72,7 +196,7 @@ func Template(labelsTemplate string) []error {
 	var validationErr []error
 
 	// Validate template
-	_, err := template.New("").Option("missingkey=error").Parse(labelsTemplate)
+	_, err := nodefeaturerule.NewTemplate(labelsTemplate)
 	if err != nil {
 		validationErr = append(validationErr, fmt.Errorf("invalid template: %w", err))
 	}
@@ -497,6 +497,44 @@ func TestMatchFeatures(t *testing.T) {
 	}
 }
 
+func TestMatchNode(t *testing.T) {
+	tests := []struct {
+		name      string
+		matchNode *nfdv1alpha1.NodeMatcher
+		wantErr   bool
+	}{
+		{
+			name:      "Nil matchNode",
+			matchNode: nil,
+		},
+		{
+			name: "Valid matchNode",
+			matchNode: &nfdv1alpha1.NodeMatcher{
+				Labels:      &nfdv1alpha1.MatchExpressionSet{"node-role.kubernetes.io/worker": &nfdv1alpha1.MatchExpression{Op: nfdv1alpha1.MatchExists}},
+				Annotations: &nfdv1alpha1.MatchExpressionSet{"example.com/rack": &nfdv1alpha1.MatchExpression{Op: nfdv1alpha1.MatchIn, Value: []string{"r1"}}},
+			},
+		},
+		{
+			name: "Invalid matchNode",
+			matchNode: &nfdv1alpha1.NodeMatcher{
+				Annotations: &nfdv1alpha1.MatchExpressionSet{"example.com/rack": &nfdv1alpha1.MatchExpression{Op: nfdv1alpha1.MatchIn}},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := MatchNode(tt.matchNode)
+			if tt.wantErr {
+				assert.NotEmpty(t, errs)
+			} else {
+				assert.Empty(t, errs)
+			}
+		})
+	}
+}
+
 func TestMatchAny(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -590,6 +628,16 @@ func TestTemplate(t *testing.T) {
 			labelsTemplate: "{{.key1=value1,key2=value2}}",
 			want:           []error{fmt.Errorf("invalid template: template: :1: bad character U+003D '='")},
 		},
+		{
+			name:           "Valid template with functions",
+			labelsTemplate: `{{range .a.b}}{{.Name | lower}}={{.Value | regexReplace "^v" ""}}{{end}}`,
+			want:           nil,
+		},
+		{
+			name:           "Unknown function",
+			labelsTemplate: "{{.a | foo}}",
+			want:           []error{fmt.Errorf(`invalid template: template: :1: function "foo" not defined`)},
+		},
 	}
 
 	for _, tt := range tests {
@@ -27,3 +27,12 @@ func Cpuid(eax, ecx uint32) *ReturnValue {
 }
 
 func cpuidAsm(eaxArg, ecxArg uint32) (eax, ebx, ecx, edx uint32)
+
+// Xgetbv executes the XGETBV instruction, returning the contents of the
+// extended control register specified by index. The caller must ensure that
+// the OS has enabled XSAVE (CPUID.01H:ECX.OSXSAVE) before calling this.
+func Xgetbv(index uint32) (eax, edx uint32) {
+	return xgetbvAsm(index)
+}
+
+func xgetbvAsm(index uint32) (eax, edx uint32)
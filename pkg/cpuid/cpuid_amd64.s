@@ -27,3 +27,11 @@ TEXT ·cpuidAsm(SB), NOSPLIT, $0
     MOVL    CX, ecx+16(FP)
     MOVL    DX, edx+20(FP)
     RET
+
+// func xgetbvAsm(index uint32) (eax, edx uint32)
+TEXT ·xgetbvAsm(SB), NOSPLIT, $0
+    MOVL    index+0(FP), CX
+    BYTE    $0x0f; BYTE $0x01; BYTE $0xd0 // XGETBV
+    MOVL    AX, eax+8(FP)
+    MOVL    DX, edx+12(FP)
+    RET
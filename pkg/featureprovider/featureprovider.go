@@ -0,0 +1,191 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package featureprovider implements the gRPC API of external feature
+// providers. Feature providers are e.g. sidecar containers that stream
+// features to nfd-worker over a UNIX domain socket. The service is defined
+// in featureprovider.proto. The messages are google.protobuf.Struct objects
+// so that providers can be implemented in any language without generating
+// code for custom message types.
+package featureprovider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
+)
+
+const (
+	// ServiceName is the full name of the FeatureProvider service.
+	ServiceName = "featureprovider.v1alpha1.FeatureProvider"
+
+	watchFeaturesMethod = "/" + ServiceName + "/WatchFeatures"
+)
+
+// FeatureUpdate is the set of features and labels advertised by a feature
+// provider.
+type FeatureUpdate struct {
+	// Features are the features of the provider, usable in feature matching
+	// rules.
+	Features *nfdv1alpha1.Features `json:"features,omitempty"`
+	// Labels are the node labels requested by the provider.
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// ToStruct converts the update into the message sent over gRPC.
+func (u *FeatureUpdate) ToStruct() (*structpb.Struct, error) {
+	data, err := json.Marshal(u)
+	if err != nil {
+		return nil, err
+	}
+	m := map[string]interface{}{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	return structpb.NewStruct(m)
+}
+
+// FeatureUpdateFromStruct converts a message received over gRPC into a
+// FeatureUpdate.
+func FeatureUpdateFromStruct(s *structpb.Struct) (*FeatureUpdate, error) {
+	data, err := s.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	u := &FeatureUpdate{}
+	if err := json.Unmarshal(data, u); err != nil {
+		return nil, fmt.Errorf("invalid feature update: %w", err)
+	}
+	return u, nil
+}
+
+// FeatureProviderServer is the server API of the FeatureProvider service.
+type FeatureProviderServer interface {
+	// WatchFeatures streams the features of the provider.
+	WatchFeatures(*emptypb.Empty, WatchFeaturesServer) error
+}
+
+// UnimplementedFeatureProviderServer can be embedded in server
+// implementations for forward compatibility.
+type UnimplementedFeatureProviderServer struct{}
+
+// WatchFeatures method of the FeatureProviderServer interface.
+func (UnimplementedFeatureProviderServer) WatchFeatures(*emptypb.Empty, WatchFeaturesServer) error {
+	return status.Error(codes.Unimplemented, "method WatchFeatures not implemented")
+}
+
+// WatchFeaturesServer is the server side stream of the WatchFeatures method.
+type WatchFeaturesServer interface {
+	Send(*FeatureUpdate) error
+	grpc.ServerStream
+}
+
+// RegisterFeatureProviderServer registers the FeatureProvider service in a
+// gRPC server.
+func RegisterFeatureProviderServer(s grpc.ServiceRegistrar, srv FeatureProviderServer) {
+	s.RegisterService(&serviceDesc, srv)
+}
+
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: ServiceName,
+	HandlerType: (*FeatureProviderServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchFeatures",
+			Handler:       watchFeaturesHandler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "featureprovider.proto",
+}
+
+func watchFeaturesHandler(srv interface{}, stream grpc.ServerStream) error {
+	m := &emptypb.Empty{}
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(FeatureProviderServer).WatchFeatures(m, &watchFeaturesServer{stream})
+}
+
+type watchFeaturesServer struct {
+	grpc.ServerStream
+}
+
+func (x *watchFeaturesServer) Send(u *FeatureUpdate) error {
+	m, err := u.ToStruct()
+	if err != nil {
+		return err
+	}
+	return x.ServerStream.SendMsg(m)
+}
+
+// FeatureProviderClient is the client API of the FeatureProvider service.
+type FeatureProviderClient interface {
+	// WatchFeatures streams the features of the provider.
+	WatchFeatures(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (WatchFeaturesClient, error)
+}
+
+// WatchFeaturesClient is the client side stream of the WatchFeatures method.
+type WatchFeaturesClient interface {
+	Recv() (*FeatureUpdate, error)
+	grpc.ClientStream
+}
+
+type featureProviderClient struct {
+	cc grpc.ClientConnInterface
+}
+
+// NewFeatureProviderClient creates a new client of the FeatureProvider
+// service.
+func NewFeatureProviderClient(cc grpc.ClientConnInterface) FeatureProviderClient {
+	return &featureProviderClient{cc}
+}
+
+func (c *featureProviderClient) WatchFeatures(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (WatchFeaturesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &serviceDesc.Streams[0], watchFeaturesMethod, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &watchFeaturesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type watchFeaturesClient struct {
+	grpc.ClientStream
+}
+
+func (x *watchFeaturesClient) Recv() (*FeatureUpdate, error) {
+	m := &structpb.Struct{}
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return FeatureUpdateFromStruct(m)
+}
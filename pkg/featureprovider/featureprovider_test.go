@@ -0,0 +1,99 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package featureprovider
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
+)
+
+type fakeProvider struct {
+	UnimplementedFeatureProviderServer
+	updates []*FeatureUpdate
+}
+
+func (p *fakeProvider) WatchFeatures(_ *emptypb.Empty, stream WatchFeaturesServer) error {
+	for _, u := range p.updates {
+		if err := stream.Send(u); err != nil {
+			return err
+		}
+	}
+	<-stream.Context().Done()
+	return nil
+}
+
+func TestWatchFeatures(t *testing.T) {
+	features := nfdv1alpha1.NewFeatures()
+	features.Flags["flag"] = nfdv1alpha1.NewFlagFeatures("a", "b")
+	features.Attributes["attr"] = nfdv1alpha1.NewAttributeFeatures(map[string]string{"foo": "bar"})
+	features.Instances["inst"] = nfdv1alpha1.NewInstanceFeatures(*nfdv1alpha1.NewInstanceFeature(map[string]string{"id": "1"}))
+	updates := []*FeatureUpdate{
+		{Features: features, Labels: map[string]string{"vendor.io/foo": "true"}},
+		{Labels: map[string]string{"vendor.io/bar": "1"}},
+	}
+
+	socket := filepath.Join(t.TempDir(), "fake.sock")
+	lis, err := net.Listen("unix", socket)
+	assert.NoError(t, err)
+	srv := grpc.NewServer()
+	RegisterFeatureProviderServer(srv, &fakeProvider{updates: updates})
+	go func() { _ = srv.Serve(lis) }()
+	defer srv.Stop()
+
+	conn, err := grpc.NewClient("unix://"+socket, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream, err := NewFeatureProviderClient(conn).WatchFeatures(ctx, &emptypb.Empty{})
+	assert.NoError(t, err)
+
+	for _, expected := range updates {
+		u, err := stream.Recv()
+		assert.NoError(t, err)
+		assert.Equal(t, expected, u)
+	}
+}
+
+func TestFeatureUpdateFromStruct(t *testing.T) {
+	u := &FeatureUpdate{Labels: map[string]string{"foo": "bar"}}
+	s, err := u.ToStruct()
+	assert.NoError(t, err)
+	assert.Contains(t, s.Fields, "labels")
+	assert.NotContains(t, s.Fields, "features")
+
+	u2, err := FeatureUpdateFromStruct(s)
+	assert.NoError(t, err)
+	assert.Equal(t, u, u2)
+
+	// Invalid content
+	s, err = structpb.NewStruct(map[string]interface{}{"labels": []interface{}{"foo"}})
+	assert.NoError(t, err)
+	_, err = FeatureUpdateFromStruct(s)
+	assert.Error(t, err)
+}
@@ -24,6 +24,8 @@ const (
 	NodeFeatureAPI      featuregate.Feature = "NodeFeatureAPI"
 	DisableAutoPrefix   featuregate.Feature = "DisableAutoPrefix"
 	NodeFeatureGroupAPI featuregate.Feature = "NodeFeatureGroupAPI"
+	MasterSharding      featuregate.Feature = "MasterSharding"
+	FeatureTimestamps   featuregate.Feature = "FeatureTimestamps"
 )
 
 var (
@@ -38,4 +40,6 @@ var DefaultNFDFeatureGates = map[featuregate.Feature]featuregate.FeatureSpec{
 	NodeFeatureAPI:      {Default: true, PreRelease: featuregate.GA, LockToDefault: true},
 	DisableAutoPrefix:   {Default: false, PreRelease: featuregate.Alpha},
 	NodeFeatureGroupAPI: {Default: false, PreRelease: featuregate.Alpha},
+	MasterSharding:      {Default: false, PreRelease: featuregate.Alpha},
+	FeatureTimestamps:   {Default: false, PreRelease: featuregate.Alpha},
 }
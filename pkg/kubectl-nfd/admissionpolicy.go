@@ -0,0 +1,152 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubectlnfd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/yaml"
+
+	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
+)
+
+// AdmissionPolicyOptions contains the options for generating a
+// ValidatingAdmissionPolicy protecting NFD-managed node metadata.
+type AdmissionPolicyOptions struct {
+	// Name of the generated ValidatingAdmissionPolicy and binding.
+	Name string
+	// LabelNamespaces are additional (vendor) label namespaces to protect.
+	LabelNamespaces []string
+	// AnnotationNamespaces are additional annotation namespaces to protect,
+	// e.g. the annotationNs configured in nfd-master.
+	AnnotationNamespaces []string
+	// AllowedUsers are the users allowed to modify the protected labels and
+	// annotations.
+	AllowedUsers []string
+}
+
+// GenerateAdmissionPolicy returns a ValidatingAdmissionPolicy and a
+// ValidatingAdmissionPolicyBinding (in YAML format) that deny modifications
+// of NFD-managed node labels and annotations by users other than the ones
+// allowed.
+func GenerateAdmissionPolicy(opts AdmissionPolicyOptions) (string, error) {
+	if opts.Name == "" {
+		return "", fmt.Errorf("policy name must be specified")
+	}
+	if len(opts.AllowedUsers) == 0 {
+		return "", fmt.Errorf("at least one allowed user must be specified")
+	}
+
+	labelNs := append([]string{nfdv1alpha1.FeatureLabelNs, nfdv1alpha1.ProfileLabelNs}, opts.LabelNamespaces...)
+	annotationNs := append([]string{nfdv1alpha1.AnnotationNs, nfdv1alpha1.FeatureAnnotationNs}, opts.AnnotationNamespaces...)
+
+	policy := &admissionregistrationv1.ValidatingAdmissionPolicy{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: admissionregistrationv1.SchemeGroupVersion.String(),
+			Kind:       "ValidatingAdmissionPolicy",
+		},
+		ObjectMeta: metav1.ObjectMeta{Name: opts.Name},
+		Spec: admissionregistrationv1.ValidatingAdmissionPolicySpec{
+			FailurePolicy: ptr.To(admissionregistrationv1.Fail),
+			MatchConstraints: &admissionregistrationv1.MatchResources{
+				ResourceRules: []admissionregistrationv1.NamedRuleWithOperations{
+					{
+						RuleWithOperations: admissionregistrationv1.RuleWithOperations{
+							Operations: []admissionregistrationv1.OperationType{admissionregistrationv1.Update},
+							Rule: admissionregistrationv1.Rule{
+								APIGroups:   []string{""},
+								APIVersions: []string{"v1"},
+								Resources:   []string{"nodes"},
+							},
+						},
+					},
+				},
+			},
+			Variables: []admissionregistrationv1.Variable{
+				{Name: "newLabels", Expression: "has(object.metadata.labels) ? object.metadata.labels : {}"},
+				{Name: "oldLabels", Expression: "has(oldObject.metadata.labels) ? oldObject.metadata.labels : {}"},
+				{Name: "newAnnotations", Expression: "has(object.metadata.annotations) ? object.metadata.annotations : {}"},
+				{Name: "oldAnnotations", Expression: "has(oldObject.metadata.annotations) ? oldObject.metadata.annotations : {}"},
+				{Name: "changedLabels", Expression: changedKeysExpression("variables.oldLabels", "variables.newLabels")},
+				{Name: "changedAnnotations", Expression: changedKeysExpression("variables.oldAnnotations", "variables.newAnnotations")},
+				{Name: "protectedLabelChanged", Expression: protectedKeyExpression("variables.changedLabels", labelNs)},
+				{Name: "protectedAnnotationChanged", Expression: protectedKeyExpression("variables.changedAnnotations", annotationNs)},
+				{Name: "allowedUser", Expression: "request.userInfo.username in " + celStringList(opts.AllowedUsers)},
+			},
+			Validations: []admissionregistrationv1.Validation{
+				{
+					Expression: "!variables.protectedLabelChanged || variables.allowedUser",
+					Message:    "modification of NFD-managed node labels is not allowed",
+					Reason:     ptr.To(metav1.StatusReasonForbidden),
+				},
+				{
+					Expression: "!variables.protectedAnnotationChanged || variables.allowedUser",
+					Message:    "modification of NFD-managed node annotations is not allowed",
+					Reason:     ptr.To(metav1.StatusReasonForbidden),
+				},
+			},
+		},
+	}
+
+	binding := &admissionregistrationv1.ValidatingAdmissionPolicyBinding{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: admissionregistrationv1.SchemeGroupVersion.String(),
+			Kind:       "ValidatingAdmissionPolicyBinding",
+		},
+		ObjectMeta: metav1.ObjectMeta{Name: opts.Name},
+		Spec: admissionregistrationv1.ValidatingAdmissionPolicyBindingSpec{
+			PolicyName:        opts.Name,
+			ValidationActions: []admissionregistrationv1.ValidationAction{admissionregistrationv1.Deny},
+		},
+	}
+
+	var out []string
+	for _, obj := range []interface{}{policy, binding} {
+		data, err := yaml.Marshal(obj)
+		if err != nil {
+			return "", err
+		}
+		out = append(out, string(data))
+	}
+	return strings.Join(out, "---\n"), nil
+}
+
+// changedKeysExpression returns a CEL expression evaluating to the list of
+// keys that were added, removed or modified between two maps.
+func changedKeysExpression(oldMap, newMap string) string {
+	return fmt.Sprintf("%[2]s.filter(k, !(k in %[1]s) || %[1]s[k] != %[2]s[k]) + %[1]s.filter(k, !(k in %[2]s))", oldMap, newMap)
+}
+
+// protectedKeyExpression returns a CEL expression evaluating to true if any
+// of the keys in the list belongs to one of the namespaces (or any of their
+// sub-namespaces).
+func protectedKeyExpression(keys string, namespaces []string) string {
+	return fmt.Sprintf("%s.exists(k, k.contains('/') && %s.exists(ns, k.split('/')[0] == ns || k.split('/')[0].endsWith('.' + ns)))", keys, celStringList(namespaces))
+}
+
+func celStringList(items []string) string {
+	quoted := make([]string, len(items))
+	for i, s := range items {
+		quoted[i] = strconv.Quote(s)
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
@@ -0,0 +1,232 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubectlnfd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/ext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	"sigs.k8s.io/yaml"
+)
+
+func TestGenerateAdmissionPolicy(t *testing.T) {
+	tcs := []struct {
+		name   string
+		opts   AdmissionPolicyOptions
+		expErr bool
+	}{
+		{name: "valid options", opts: AdmissionPolicyOptions{Name: "policy", AllowedUsers: []string{"nfd-master"}}},
+		{name: "missing name", opts: AdmissionPolicyOptions{AllowedUsers: []string{"nfd-master"}}, expErr: true},
+		{name: "missing allowed users", opts: AdmissionPolicyOptions{Name: "policy"}, expErr: true},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := GenerateAdmissionPolicy(tc.opts)
+			if tc.expErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			policy, binding := parseAdmissionPolicy(t, out)
+			assert.Equal(t, tc.opts.Name, policy.Name)
+			assert.Equal(t, tc.opts.Name, binding.Name)
+			assert.Equal(t, tc.opts.Name, binding.Spec.PolicyName)
+			assert.Equal(t, []admissionregistrationv1.ValidationAction{admissionregistrationv1.Deny}, binding.Spec.ValidationActions)
+			assert.Len(t, policy.Spec.Validations, 2)
+		})
+	}
+}
+
+func TestAdmissionPolicyValidations(t *testing.T) {
+	out, err := GenerateAdmissionPolicy(AdmissionPolicyOptions{
+		Name:                 "policy",
+		LabelNamespaces:      []string{"vendor.example.com"},
+		AnnotationNamespaces: []string{"nfd.example.com"},
+		AllowedUsers:         []string{"system:serviceaccount:nfd:nfd-master"},
+	})
+	require.NoError(t, err)
+	policy, _ := parseAdmissionPolicy(t, out)
+
+	tcs := []struct {
+		name           string
+		user           string
+		oldLabels      map[string]string
+		newLabels      map[string]string
+		oldAnnotations map[string]string
+		newAnnotations map[string]string
+		expAllowed     bool
+	}{
+		{
+			name:       "unprotected label added",
+			user:       "admin",
+			newLabels:  map[string]string{"example.com/foo": "true"},
+			expAllowed: true,
+		},
+		{
+			name:       "feature label added",
+			user:       "admin",
+			newLabels:  map[string]string{"feature.node.kubernetes.io/foo": "true"},
+			expAllowed: false,
+		},
+		{
+			name:       "feature label removed",
+			user:       "admin",
+			oldLabels:  map[string]string{"feature.node.kubernetes.io/foo": "true"},
+			expAllowed: false,
+		},
+		{
+			name:       "feature label changed",
+			user:       "admin",
+			oldLabels:  map[string]string{"feature.node.kubernetes.io/foo": "true"},
+			newLabels:  map[string]string{"feature.node.kubernetes.io/foo": "false"},
+			expAllowed: false,
+		},
+		{
+			name:       "feature label unchanged",
+			user:       "admin",
+			oldLabels:  map[string]string{"feature.node.kubernetes.io/foo": "true"},
+			newLabels:  map[string]string{"feature.node.kubernetes.io/foo": "true", "example.com/bar": "1"},
+			expAllowed: true,
+		},
+		{
+			name:       "label in sub-namespace changed",
+			user:       "admin",
+			newLabels:  map[string]string{"sub.feature.node.kubernetes.io/foo": "true"},
+			expAllowed: false,
+		},
+		{
+			name:       "label in extra namespace changed",
+			user:       "admin",
+			newLabels:  map[string]string{"vendor.example.com/foo": "true"},
+			expAllowed: false,
+		},
+		{
+			name:       "label in similarly named namespace changed",
+			user:       "admin",
+			newLabels:  map[string]string{"notfeature.node.kubernetes.io/foo": "true"},
+			expAllowed: true,
+		},
+		{
+			name:           "tracking annotation changed",
+			user:           "admin",
+			oldAnnotations: map[string]string{"nfd.node.kubernetes.io/feature-labels": "foo"},
+			newAnnotations: map[string]string{"nfd.node.kubernetes.io/feature-labels": "bar"},
+			expAllowed:     false,
+		},
+		{
+			name:           "feature annotation added",
+			user:           "admin",
+			newAnnotations: map[string]string{"feature.node.kubernetes.io/foo": "bar"},
+			expAllowed:     false,
+		},
+		{
+			name:           "annotation in extra namespace changed",
+			user:           "admin",
+			oldAnnotations: map[string]string{"nfd.example.com/feature-labels": "foo"},
+			expAllowed:     false,
+		},
+		{
+			name:           "nfd-master changes labels and annotations",
+			user:           "system:serviceaccount:nfd:nfd-master",
+			newLabels:      map[string]string{"feature.node.kubernetes.io/foo": "true"},
+			newAnnotations: map[string]string{"nfd.node.kubernetes.io/feature-labels": "foo"},
+			expAllowed:     true,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			allowed := evalAdmissionPolicy(t, policy, tc.user,
+				nodeObject(tc.oldLabels, tc.oldAnnotations), nodeObject(tc.newLabels, tc.newAnnotations))
+			assert.Equal(t, tc.expAllowed, allowed)
+		})
+	}
+}
+
+func parseAdmissionPolicy(t *testing.T, out string) (*admissionregistrationv1.ValidatingAdmissionPolicy, *admissionregistrationv1.ValidatingAdmissionPolicyBinding) {
+	docs := strings.Split(out, "---\n")
+	require.Len(t, docs, 2)
+
+	policy := &admissionregistrationv1.ValidatingAdmissionPolicy{}
+	require.NoError(t, yaml.UnmarshalStrict([]byte(docs[0]), policy))
+	assert.Equal(t, "ValidatingAdmissionPolicy", policy.Kind)
+
+	binding := &admissionregistrationv1.ValidatingAdmissionPolicyBinding{}
+	require.NoError(t, yaml.UnmarshalStrict([]byte(docs[1]), binding))
+	assert.Equal(t, "ValidatingAdmissionPolicyBinding", binding.Kind)
+
+	return policy, binding
+}
+
+// nodeObject returns an unstructured node object with the given labels and
+// annotations, omitting the fields that are empty like the apiserver does.
+func nodeObject(labels, annotations map[string]string) map[string]any {
+	meta := map[string]any{"name": "node-1"}
+	if len(labels) > 0 {
+		meta["labels"] = labels
+	}
+	if len(annotations) > 0 {
+		meta["annotations"] = annotations
+	}
+	return map[string]any{"metadata": meta}
+}
+
+// evalAdmissionPolicy evaluates the variables and validations of the policy
+// like the apiserver does, returning true if the request is allowed.
+func evalAdmissionPolicy(t *testing.T, policy *admissionregistrationv1.ValidatingAdmissionPolicy, user string, oldObject, object map[string]any) bool {
+	env, err := cel.NewEnv(
+		ext.Strings(),
+		cel.Variable("object", cel.DynType),
+		cel.Variable("oldObject", cel.DynType),
+		cel.Variable("request", cel.DynType),
+		cel.Variable("variables", cel.MapType(cel.StringType, cel.DynType)),
+	)
+	require.NoError(t, err)
+
+	eval := func(expr string, vars map[string]any) any {
+		ast, iss := env.Compile(expr)
+		require.NoError(t, iss.Err(), expr)
+		prg, err := env.Program(ast)
+		require.NoError(t, err)
+		out, _, err := prg.Eval(map[string]any{
+			"object":    object,
+			"oldObject": oldObject,
+			"request":   map[string]any{"userInfo": map[string]any{"username": user}},
+			"variables": vars,
+		})
+		require.NoError(t, err, expr)
+		return out.Value()
+	}
+
+	vars := make(map[string]any)
+	for _, v := range policy.Spec.Variables {
+		vars[v.Name] = eval(v.Expression, vars)
+	}
+	for _, v := range policy.Spec.Validations {
+		if eval(v.Expression, vars) != true {
+			return false
+		}
+	}
+	return true
+}
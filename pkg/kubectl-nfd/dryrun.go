@@ -17,13 +17,18 @@ limitations under the License.
 package kubectlnfd
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 
 	"sigs.k8s.io/yaml"
 
 	corev1 "k8s.io/api/core/v1"
+	k8syaml "k8s.io/apimachinery/pkg/util/yaml"
 
 	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
 	"sigs.k8s.io/node-feature-discovery/pkg/apis/nfd/nodefeaturerule"
@@ -60,6 +65,69 @@ func DryRun(nodefeaturerulepath, nodefeaturepath string) []error {
 	return errs
 }
 
+// DryRunFeatures processes the NodeFeatureRule objects read from a file, or
+// from all YAML files in a directory, against the given features. Files may
+// contain multiple YAML documents.
+func DryRunFeatures(nodefeaturerulepath string, features *nfdv1alpha1.Features) []error {
+	var errs []error
+
+	nfrs, err := readNodeFeatureRules(nodefeaturerulepath)
+	if err != nil {
+		return []error{err}
+	}
+
+	spec := nfdv1alpha1.NodeFeatureSpec{Features: *features}
+	for _, nfr := range nfrs {
+		fmt.Printf("Evaluating NodeFeatureRule %q\n", nfr.Name)
+		errs = append(errs, processNodeFeatureRule(nfr, spec)...)
+	}
+
+	return errs
+}
+
+// readNodeFeatureRules reads NodeFeatureRule objects from a file or from all
+// YAML files in a directory.
+func readNodeFeatureRules(path string) ([]nfdv1alpha1.NodeFeatureRule, error) {
+	files := []string{path}
+	if fi, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("error reading NodeFeatureRule file: %w", err)
+	} else if fi.IsDir() {
+		files = nil
+		for _, pattern := range []string{"*.yaml", "*.yml"} {
+			matches, err := filepath.Glob(filepath.Join(path, pattern))
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, matches...)
+		}
+		sort.Strings(files)
+	}
+
+	var nfrs []nfdv1alpha1.NodeFeatureRule
+	for _, file := range files {
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, fmt.Errorf("error reading NodeFeatureRule file: %w", err)
+		}
+		decoder := k8syaml.NewYAMLOrJSONDecoder(f, 4096)
+		for {
+			nfr := nfdv1alpha1.NodeFeatureRule{}
+			if err := decoder.Decode(&nfr); errors.Is(err, io.EOF) {
+				break
+			} else if err != nil {
+				f.Close()
+				return nil, fmt.Errorf("error parsing NodeFeatureRule file %q: %w", file, err)
+			}
+			// Skip empty documents
+			if len(nfr.Spec.Rules) > 0 || nfr.Name != "" {
+				nfrs = append(nfrs, nfr)
+			}
+		}
+		f.Close()
+	}
+	return nfrs, nil
+}
+
 func processNodeFeatureRule(nodeFeatureRule nfdv1alpha1.NodeFeatureRule, nodeFeature nfdv1alpha1.NodeFeatureSpec) []error {
 	var errs []error
 	var taints []corev1.Taint
@@ -68,7 +136,16 @@ func processNodeFeatureRule(nodeFeatureRule nfdv1alpha1.NodeFeatureRule, nodeFea
 	labels := make(map[string]string)
 	annotations := make(map[string]string)
 
+	if nodeFeatureRule.Spec.Suspend {
+		fmt.Println("NodeFeatureRule is suspended, skipping")
+		return nil
+	}
+
 	for _, rule := range nodeFeatureRule.Spec.Rules {
+		if rule.Disabled {
+			fmt.Println("Skipping disabled rule: ", rule.Name)
+			continue
+		}
 		fmt.Println("Processing rule: ", rule.Name)
 		ruleOut, err := nodefeaturerule.Execute(&rule, &nodeFeature.Features, true)
 		if err != nil {
@@ -95,11 +172,17 @@ func processNodeFeatureRule(nodeFeatureRule nfdv1alpha1.NodeFeatureRule, nodeFea
 		for k, v := range ruleOut.ExtendedResources {
 			// Dynamic Value
 			if strings.HasPrefix(v, "@") {
-				dvalue, err := getDynamicValue(v, &nodeFeature.Features)
+				ref, conversion := nodefeaturerule.SplitUnitConversion(v)
+				dvalue, err := getDynamicValue(ref, &nodeFeature.Features)
 				if err != nil {
 					errs = append(errs, fmt.Errorf("failed to get dynamic value for extendedResource %q: %w", k, err))
 					continue
 				}
+				dvalue, err = nodefeaturerule.ConvertUnits(dvalue, conversion)
+				if err != nil {
+					errs = append(errs, fmt.Errorf("failed to convert dynamic value for extendedResource %q: %w", k, err))
+					continue
+				}
 				extendedResources[k] = dvalue
 				continue
 			}
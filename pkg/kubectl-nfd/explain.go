@@ -0,0 +1,240 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubectlnfd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sclient "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/yaml"
+
+	nfdmaster "sigs.k8s.io/node-feature-discovery/pkg/nfd-master"
+)
+
+// DefaultMasterSelector is the label selector of the nfd-master pods in the
+// default kustomize deployment.
+const DefaultMasterSelector = "app=nfd-master"
+
+// Explain fetches the explanation of the NFD-managed labels, annotations,
+// extended resources and taints of a node from nfd-master and writes a report
+// of it. The nfd-master pods are looked up from namespace with selector and
+// they must have the debug endpoints enabled. If label is non-empty only rules
+// related to that label are reported. Output format is one of "text", "yaml"
+// or "json".
+func Explain(w io.Writer, nodeName, kubeconfig, namespace, selector, label, output string) error {
+	if kubeconfig == "" {
+		kubeconfig = os.Getenv("KUBECONFIG")
+	}
+	config, err := clientcmd.BuildConfigFromFlags("", kubeconfig)
+	if err != nil {
+		return fmt.Errorf("error building kubeconfig: %w", err)
+	}
+	cli, err := k8sclient.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	e, err := fetchExplanation(context.TODO(), cli, namespace, selector, nodeName, label)
+	if err != nil {
+		return err
+	}
+
+	switch output {
+	case "", "text":
+		printExplanation(w, e)
+	case "yaml":
+		data, err := yaml.Marshal(e)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(data)
+		return err
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(e)
+	default:
+		return fmt.Errorf("invalid output format %q, must be one of text, yaml or json", output)
+	}
+	return nil
+}
+
+// fetchExplanation gets the explanation of a node from the debug endpoint of
+// a running nfd-master pod, through the API server proxy.
+func fetchExplanation(ctx context.Context, cli k8sclient.Interface, namespace, selector, nodeName, label string) (*nfdmaster.Explanation, error) {
+	if selector == "" {
+		selector = DefaultMasterSelector
+	}
+	pods, err := cli.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nfd-master pods: %w", err)
+	}
+	var pod *corev1.Pod
+	for i := range pods.Items {
+		if pods.Items[i].Status.Phase == corev1.PodRunning {
+			pod = &pods.Items[i]
+			break
+		}
+	}
+	if pod == nil {
+		return nil, fmt.Errorf("no running nfd-master pods found in namespace %q with selector %q", namespace, selector)
+	}
+	scheme, port, err := masterDebugPort(pod)
+	if err != nil {
+		return nil, err
+	}
+
+	params := map[string]string{"node": nodeName}
+	if label != "" {
+		params["label"] = label
+	}
+	data, err := cli.CoreV1().Pods(namespace).ProxyGet(scheme, pod.Name, port, nfdmaster.ExplainPath, params).DoRaw(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get explanation from nfd-master pod %s/%s (is nfd-master running with -enable-debug-endpoints?): %w", namespace, pod.Name, err)
+	}
+	e := &nfdmaster.Explanation{}
+	if err := json.Unmarshal(data, e); err != nil {
+		return nil, fmt.Errorf("failed to parse explanation: %w", err)
+	}
+	return e, nil
+}
+
+// masterDebugPort returns the scheme and port of the http server of
+// nfd-master serving the metrics and the debug endpoints.
+func masterDebugPort(pod *corev1.Pod) (string, string, error) {
+	for _, c := range pod.Spec.Containers {
+		for _, p := range c.Ports {
+			if p.Name != "metrics" {
+				continue
+			}
+			scheme := "http"
+			for _, arg := range c.Args {
+				if v, ok := strings.CutPrefix(strings.TrimLeft(arg, "-"), "metrics-cert-file="); ok && v != "" {
+					scheme = "https"
+				}
+			}
+			return scheme, strconv.Itoa(int(p.ContainerPort)), nil
+		}
+	}
+	return "", "", fmt.Errorf("metrics port not found in nfd-master pod %s/%s", pod.Namespace, pod.Name)
+}
+
+func printExplanation(w io.Writer, e *nfdmaster.Explanation) {
+	fmt.Fprintf(w, "Node: %s\n", e.Node)
+	if e.SkipReason != "" {
+		fmt.Fprintf(w, "Node is not updated by nfd-master: %s\n", e.SkipReason)
+	}
+	if len(e.NodeFeatures) == 0 {
+		fmt.Fprintln(w, "NodeFeature objects: <none>")
+	} else {
+		fmt.Fprintf(w, "NodeFeature objects: %s\n", strings.Join(e.NodeFeatures, ", "))
+	}
+	printMap(w, "Ignored NodeFeature object: ", e.IgnoredNodeFeatures)
+
+	fmt.Fprintln(w, "\nRules:")
+	for _, r := range e.Rules {
+		fmt.Fprintf(w, "  %s/%s: %s\n", r.Object, r.Rule, r.Status)
+		if r.Error != "" {
+			fmt.Fprintf(w, "    error: %s\n", r.Error)
+		}
+		for _, t := range r.UnmatchedTerms {
+			data, _ := json.Marshal(t)
+			fmt.Fprintf(w, "    unmatched: %s\n", data)
+		}
+		printMap(w, "    label ", r.Labels)
+		printMap(w, "    annotation ", r.Annotations)
+		printMap(w, "    extended resource ", r.ExtendedResources)
+		printMap(w, "    var ", r.Vars)
+		for _, t := range r.Taints {
+			fmt.Fprintf(w, "    taint %s\n", t.ToString())
+		}
+	}
+
+	if wh := e.EvaluationWebhook; wh != nil {
+		fmt.Fprintln(w, "\nEvaluation webhook:")
+		printMap(w, "  label ", wh.Labels)
+		printMap(w, "  annotation ", wh.Annotations)
+		printMap(w, "  extended resource ", wh.ExtendedResources)
+		for _, t := range wh.Taints {
+			fmt.Fprintf(w, "  taint %s\n", t.ToString())
+		}
+	}
+
+	fmt.Fprintln(w, "\nLabels:")
+	for _, k := range sortedKeys(e.Labels) {
+		status := "present on node"
+		if v, ok := e.NodeLabels[k]; !ok {
+			status = "NOT present on node, will be added on the next update"
+		} else if v != e.Labels[k] {
+			status = fmt.Sprintf("node has value %q", v)
+		}
+		fmt.Fprintf(w, "  %s=%s (%s)\n", k, e.Labels[k], status)
+	}
+	for _, k := range sortedKeys(e.NodeLabels) {
+		if _, ok := e.Labels[k]; !ok {
+			fmt.Fprintf(w, "  %s=%s (present on node but not produced, will be removed on the next update)\n", k, e.NodeLabels[k])
+		}
+	}
+	printMap(w, "Annotation: ", e.Annotations)
+	printMap(w, "Extended resource: ", e.ExtendedResources)
+	for _, t := range e.Taints {
+		fmt.Fprintf(w, "Taint: %s\n", t.ToString())
+	}
+
+	if r := e.Rejected; r != nil {
+		fmt.Fprintln(w, "\nRejected by the nfd-master configuration:")
+		for _, k := range []struct {
+			kind string
+			keys *nfdmaster.RejectedKeys
+		}{{"label", r.Labels}, {"annotation", r.Annotations}, {"extended resource", r.ExtendedResources}, {"taint", r.Taints}} {
+			if k.keys == nil {
+				continue
+			}
+			for _, rk := range k.keys.Keys {
+				fmt.Fprintf(w, "  %s %s: %s\n", k.kind, rk.Key, rk.Reason)
+			}
+			if n := k.keys.Count - len(k.keys.Keys); n > 0 {
+				fmt.Fprintf(w, "  ... and %d more %ss\n", n, k.kind)
+			}
+		}
+	}
+}
+
+func printMap(w io.Writer, prefix string, m map[string]string) {
+	for _, k := range sortedKeys(m) {
+		fmt.Fprintf(w, "%s%s=%s\n", prefix, k, m[k])
+	}
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
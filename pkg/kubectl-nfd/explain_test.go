@@ -0,0 +1,85 @@
+package kubectlnfd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakek8sclient "k8s.io/client-go/kubernetes/fake"
+	restclient "k8s.io/client-go/rest"
+	clienttesting "k8s.io/client-go/testing"
+
+	nfdmaster "sigs.k8s.io/node-feature-discovery/pkg/nfd-master"
+)
+
+type fakeProxyResponse []byte
+
+func (r fakeProxyResponse) DoRaw(context.Context) ([]byte, error) {
+	return r, nil
+}
+
+func (r fakeProxyResponse) Stream(context.Context) (io.ReadCloser, error) {
+	return io.NopCloser(bytes.NewReader(r)), nil
+}
+
+func TestFetchExplanation(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "nfd-master-1", Namespace: "nfd", Labels: map[string]string{"app": "nfd-master"}},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Name:  "nfd-master",
+				Args:  []string{"-enable-debug-endpoints", "-metrics-cert-file=/etc/tls/tls.crt"},
+				Ports: []corev1.ContainerPort{{Name: "metrics", ContainerPort: 8081}},
+			}},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	expected := &nfdmaster.Explanation{
+		Node:         "node-1",
+		NodeFeatures: []string{"nfd/node-1"},
+		Rules: []nfdmaster.RuleExplanation{
+			{Object: "rules", Rule: "match", Status: nfdmaster.RuleMatched, Labels: map[string]string{"vendor.io/foo": "true"}},
+			{Object: "rules", Rule: "no-match", Status: nfdmaster.RuleNotMatched},
+		},
+		Labels:     map[string]string{"feature.node.kubernetes.io/worker-label": "true", "vendor.io/foo": "true"},
+		NodeLabels: map[string]string{"feature.node.kubernetes.io/stale": "true", "vendor.io/foo": "true"},
+		Rejected: &nfdmaster.RejectedItems{
+			Labels: &nfdmaster.RejectedKeys{Count: 1, Keys: []nfdmaster.RejectedKey{{Key: "kubernetes.io/foo", Reason: "denied namespace"}}},
+		},
+	}
+	data, err := json.Marshal(expected)
+	assert.NoError(t, err)
+
+	cli := fakek8sclient.NewSimpleClientset(pod)
+	var action clienttesting.ProxyGetAction
+	cli.PrependProxyReactor("pods", func(a clienttesting.Action) (bool, restclient.ResponseWrapper, error) {
+		action = a.(clienttesting.ProxyGetAction)
+		return true, fakeProxyResponse(data), nil
+	})
+
+	e, err := fetchExplanation(context.TODO(), cli, "nfd", "", "node-1", "vendor.io/foo")
+	assert.NoError(t, err)
+	assert.Equal(t, expected, e)
+	assert.Equal(t, "https", action.GetScheme())
+	assert.Equal(t, "nfd-master-1", action.GetName())
+	assert.Equal(t, "8081", action.GetPort())
+	assert.Equal(t, nfdmaster.ExplainPath, action.GetPath())
+	assert.Equal(t, map[string]string{"node": "node-1", "label": "vendor.io/foo"}, action.GetParams())
+
+	var buf bytes.Buffer
+	printExplanation(&buf, e)
+	out := buf.String()
+	assert.Contains(t, out, "rules/no-match: NotMatched")
+	assert.Contains(t, out, "feature.node.kubernetes.io/worker-label=true (NOT present on node")
+	assert.Contains(t, out, "feature.node.kubernetes.io/stale=true (present on node but not produced")
+	assert.Contains(t, out, "label kubernetes.io/foo: denied namespace")
+
+	// No running nfd-master pods
+	_, err = fetchExplanation(context.TODO(), cli, "other", "", "node-1", "")
+	assert.Error(t, err)
+}
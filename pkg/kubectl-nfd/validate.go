@@ -19,9 +19,7 @@ package kubectlnfd
 import (
 	"fmt"
 	"os"
-	"strings"
 
-	"k8s.io/apimachinery/pkg/api/resource"
 	"sigs.k8s.io/yaml"
 
 	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
@@ -46,48 +44,7 @@ func ValidateNFR(filepath string) []error {
 
 	for _, rule := range nfr.Spec.Rules {
 		fmt.Println("Validating rule: ", rule.Name)
-		// Validate Rule Name
-		if rule.Name == "" {
-			validationErr = append(validationErr, fmt.Errorf("rule name cannot be empty"))
-		}
-
-		// Validate Annotations
-		validationErr = append(validationErr, validate.Annotations(rule.Annotations)...)
-
-		// Validate labels
-		// Dummy dynamic values before validating labels
-		labels := rule.Labels
-		for k, v := range labels {
-			if strings.HasPrefix(v, "@") {
-				labels[k] = resource.NewQuantity(0, resource.DecimalSI).String()
-			}
-		}
-		validationErr = append(validationErr, validate.Labels(labels)...)
-
-		// Validate Taints
-		validationErr = append(validationErr, validate.Taints(rule.Taints)...)
-
-		// Validate extended Resources
-		// Dummy dynamic values before validating extended resources
-		extendedResources := rule.ExtendedResources
-		for k, v := range extendedResources {
-			if strings.HasPrefix(v, "@") {
-				extendedResources[k] = resource.NewQuantity(0, resource.DecimalSI).String()
-			}
-		}
-		validationErr = append(validationErr, validate.ExtendedResources(extendedResources)...)
-
-		// Validate LabelsTemplate
-		validationErr = append(validationErr, validate.Template(rule.LabelsTemplate)...)
-
-		// Validate VarsTemplate
-		validationErr = append(validationErr, validate.Template(rule.VarsTemplate)...)
-
-		// Validate matchFeatures
-		validationErr = append(validationErr, validate.MatchFeatures(rule.MatchFeatures)...)
-
-		// Validate matchAny
-		validationErr = append(validationErr, validate.MatchAny(rule.MatchAny)...)
+		validationErr = append(validationErr, validate.Rule(&rule)...)
 	}
 
 	return validationErr
@@ -0,0 +1,133 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nfdadmission
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
+
+	"sigs.k8s.io/node-feature-discovery/pkg/utils"
+)
+
+// configReloadDelay is the time to wait for the configuration file changes to
+// settle before reloading it.
+const configReloadDelay = time.Second
+
+// config holds the settings of the nfd-master configuration file used in
+// validation. Other settings of nfd-master are ignored.
+type config struct {
+	DenyLabelNs  utils.StringSetVal
+	ExtraLabelNs utils.StringSetVal
+}
+
+// parseConfig reads the nfd-master configuration file and applies the
+// command line overrides.
+func (a *nfdAdmission) parseConfig() (*config, error) {
+	c := &config{
+		DenyLabelNs:  utils.StringSetVal{},
+		ExtraLabelNs: utils.StringSetVal{},
+	}
+
+	if a.args.ConfigFile != "" {
+		data, err := os.ReadFile(a.args.ConfigFile)
+		if err != nil {
+			if os.IsNotExist(err) {
+				klog.InfoS("config file not found, using defaults", "path", a.args.ConfigFile)
+			} else {
+				return nil, fmt.Errorf("error reading config file: %w", err)
+			}
+		} else {
+			if err := yaml.Unmarshal(data, c); err != nil {
+				return nil, fmt.Errorf("failed to parse config file: %w", err)
+			}
+			klog.InfoS("configuration file parsed", "path", a.args.ConfigFile)
+		}
+	}
+
+	if a.args.Overrides.DenyLabelNs != nil {
+		c.DenyLabelNs = *a.args.Overrides.DenyLabelNs
+	}
+	if a.args.Overrides.ExtraLabelNs != nil {
+		c.ExtraLabelNs = *a.args.Overrides.ExtraLabelNs
+	}
+	return c, nil
+}
+
+// configure (re-)creates the validator from the configuration.
+func (a *nfdAdmission) configure() error {
+	c, err := a.parseConfig()
+	if err != nil {
+		return err
+	}
+	a.validator.Store(newValidator(c.DenyLabelNs, c.ExtraLabelNs))
+	klog.InfoS("configuration successfully updated", "denyLabelNs", c.DenyLabelNs, "extraLabelNs", c.ExtraLabelNs)
+	return nil
+}
+
+// watchConfig watches for changes in the configuration file and reloads the
+// configuration when the file changes. The parent directory is watched in
+// order to catch updates of ConfigMap volumes, which replace the file by
+// swapping a symlink.
+func (a *nfdAdmission) watchConfig() error {
+	if a.args.ConfigFile == "" {
+		return nil
+	}
+	dir := filepath.Dir(a.args.ConfigFile)
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		klog.InfoS("configuration directory not found, not watching for configuration changes", "path", dir)
+		return nil
+	}
+
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	if err := w.Add(dir); err != nil {
+		w.Close()
+		return fmt.Errorf("failed to watch %q: %w", dir, err)
+	}
+
+	go func() {
+		defer w.Close()
+
+		var reload <-chan time.Time
+		for {
+			select {
+			case e := <-w.Events:
+				klog.V(5).InfoS("fsnotify event received", "filename", e.Name, "op", e.Op)
+				reload = time.After(configReloadDelay)
+			case err := <-w.Errors:
+				klog.ErrorS(err, "config file watcher error")
+			case <-reload:
+				klog.InfoS("configuration file changed, reloading", "path", a.args.ConfigFile)
+				if err := a.configure(); err != nil {
+					klog.ErrorS(err, "failed to reload configuration, keeping the old configuration")
+				}
+				reload = nil
+			case <-a.stop:
+				return
+			}
+		}
+	}()
+	return nil
+}
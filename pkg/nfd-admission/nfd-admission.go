@@ -0,0 +1,181 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nfdadmission
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
+	"sigs.k8s.io/node-feature-discovery/pkg/utils"
+	"sigs.k8s.io/node-feature-discovery/pkg/version"
+)
+
+const (
+	// ValidatePath is the path of the validating webhook endpoint.
+	ValidatePath = "/validate"
+
+	// maxRequestSize is the maximum size of an AdmissionReview request.
+	maxRequestSize = 3 * 1024 * 1024
+)
+
+// Args are the command line arguments of NfdAdmission.
+type Args struct {
+	CertFile string
+	KeyFile  string
+	Port     int
+	// ConfigFile is the nfd-master configuration file, read for the label
+	// namespace restrictions.
+	ConfigFile string
+	Overrides  ConfigOverrideArgs
+}
+
+// ConfigOverrideArgs are args that override the settings of the nfd-master
+// configuration file.
+type ConfigOverrideArgs struct {
+	DenyLabelNs  *utils.StringSetVal
+	ExtraLabelNs *utils.StringSetVal
+}
+
+// NfdAdmission is a validating admission webhook server for NodeFeatureRule
+// and NodeFeatureGroup objects.
+type NfdAdmission interface {
+	Run() error
+	Stop()
+}
+
+type nfdAdmission struct {
+	args      *Args
+	validator atomic.Pointer[validator]
+	server    *utils.HTTPServer
+	stop      chan struct{}
+}
+
+// New returns a new NfdAdmission instance.
+func New(args *Args) (NfdAdmission, error) {
+	if args.CertFile == "" || args.KeyFile == "" {
+		return nil, fmt.Errorf("both -cert-file and -key-file must be specified")
+	}
+	a := &nfdAdmission{
+		args: args,
+		stop: make(chan struct{}),
+	}
+	if err := a.configure(); err != nil {
+		return nil, err
+	}
+	a.server = utils.NewHTTPServer(args.Port, utils.WithTLS(args.CertFile, args.KeyFile))
+	a.server.Handle(ValidatePath, http.HandlerFunc(a.serveValidate))
+	return a, nil
+}
+
+// Run the webhook server. Blocks until Stop() is called.
+func (a *nfdAdmission) Run() error {
+	klog.InfoS("Node Feature Discovery Admission Webhook", "version", version.Get())
+
+	if err := a.watchConfig(); err != nil {
+		return err
+	}
+
+	a.server.SetReady(true)
+	a.server.Run()
+	return nil
+}
+
+// Stop the webhook server.
+func (a *nfdAdmission) Stop() {
+	a.server.Stop()
+	close(a.stop)
+}
+
+func (a *nfdAdmission) serveValidate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxRequestSize))
+	if err != nil {
+		http.Error(w, "failed to read request", http.StatusBadRequest)
+		return
+	}
+
+	review := admissionv1.AdmissionReview{}
+	if err := json.Unmarshal(body, &review); err != nil || review.Request == nil {
+		http.Error(w, "invalid AdmissionReview request", http.StatusBadRequest)
+		return
+	}
+
+	review.Response = a.review(review.Request)
+	review.Request = nil
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(review); err != nil {
+		klog.ErrorS(err, "failed to write AdmissionReview response")
+	}
+}
+
+// review validates the object of an admission request.
+func (a *nfdAdmission) review(req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	resp := &admissionv1.AdmissionResponse{UID: req.UID, Allowed: true}
+	if req.Operation == admissionv1.Delete {
+		return resp
+	}
+
+	var errs []error
+	switch req.Kind.Kind {
+	case "NodeFeatureRule":
+		obj := nfdv1alpha1.NodeFeatureRule{}
+		if err := json.Unmarshal(req.Object.Raw, &obj); err != nil {
+			errs = []error{fmt.Errorf("failed to decode NodeFeatureRule: %w", err)}
+		} else {
+			errs = a.validator.Load().nodeFeatureRuleSpec(&obj.Spec)
+		}
+	case "NodeFeatureGroup":
+		obj := nfdv1alpha1.NodeFeatureGroup{}
+		if err := json.Unmarshal(req.Object.Raw, &obj); err != nil {
+			errs = []error{fmt.Errorf("failed to decode NodeFeatureGroup: %w", err)}
+		} else {
+			errs = a.validator.Load().nodeFeatureGroupSpec(&obj.Spec)
+		}
+	default:
+		klog.V(2).InfoS("ignoring admission request for unsupported kind", "kind", req.Kind.Kind, "name", req.Name)
+		return resp
+	}
+
+	if len(errs) > 0 {
+		msgs := make([]string, len(errs))
+		for i, err := range errs {
+			msgs[i] = err.Error()
+		}
+		klog.V(1).InfoS("rejecting invalid object", "kind", req.Kind.Kind, "name", req.Name, "errors", msgs)
+		resp.Allowed = false
+		resp.Result = &metav1.Status{
+			Status:  metav1.StatusFailure,
+			Code:    http.StatusUnprocessableEntity,
+			Reason:  metav1.StatusReasonInvalid,
+			Message: fmt.Sprintf("invalid %s %q: %s", req.Kind.Kind, req.Name, strings.Join(msgs, "; ")),
+		}
+	}
+	return resp
+}
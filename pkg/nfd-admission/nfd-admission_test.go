@@ -0,0 +1,182 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nfdadmission
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
+	"sigs.k8s.io/node-feature-discovery/pkg/utils"
+)
+
+func newTestRule(mod func(r *nfdv1alpha1.Rule)) *nfdv1alpha1.NodeFeatureRule {
+	r := nfdv1alpha1.Rule{
+		Name:   "rule-1",
+		Labels: map[string]string{"foo": "true", "vendor.io/bar": "@kernel.version.major"},
+		MatchFeatures: nfdv1alpha1.FeatureMatcher{{
+			Feature: "kernel.version",
+			MatchExpressions: &nfdv1alpha1.MatchExpressionSet{
+				"full": &nfdv1alpha1.MatchExpression{Op: nfdv1alpha1.MatchInRegexp, Value: nfdv1alpha1.MatchValue{"^6\\..*"}},
+			},
+		}},
+	}
+	if mod != nil {
+		mod(&r)
+	}
+	return &nfdv1alpha1.NodeFeatureRule{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "nfd.k8s-sigs.io/v1alpha1", Kind: "NodeFeatureRule"},
+		ObjectMeta: metav1.ObjectMeta{Name: "test"},
+		Spec:       nfdv1alpha1.NodeFeatureRuleSpec{Rules: []nfdv1alpha1.Rule{r}},
+	}
+}
+
+func TestValidator(t *testing.T) {
+	v := newValidator(utils.StringSetVal{"*.denied.io": {}, "denied.example.com": {}}, utils.StringSetVal{"extra.kubernetes.io": {}})
+
+	tcs := []struct {
+		name  string
+		mod   func(r *nfdv1alpha1.Rule)
+		valid bool
+	}{
+		{name: "valid rule", valid: true},
+		{name: "invalid regexp", mod: func(r *nfdv1alpha1.Rule) {
+			(*r.MatchFeatures[0].MatchExpressions)["full"].Value = nfdv1alpha1.MatchValue{"("}
+		}},
+		{name: "invalid template", mod: func(r *nfdv1alpha1.Rule) { r.LabelsTemplate = "{{ .foo" }},
+		{name: "invalid taint effect", mod: func(r *nfdv1alpha1.Rule) {
+			r.Taints = []corev1.Taint{{Key: "vendor.io/foo", Effect: "Foo"}}
+		}},
+		{name: "denied namespace", mod: func(r *nfdv1alpha1.Rule) { r.Labels["sub.denied.io/foo"] = "true" }},
+		{name: "kubernetes.io namespace", mod: func(r *nfdv1alpha1.Rule) { r.ElseLabels = map[string]string{"kubernetes.io/foo": "true"} }},
+		{name: "extra namespace", mod: func(r *nfdv1alpha1.Rule) { r.Labels["extra.kubernetes.io/foo"] = "true" }, valid: true},
+		{name: "invalid dynamic value", mod: func(r *nfdv1alpha1.Rule) { r.Labels["foo"] = "@kernel.version" }},
+		{name: "invalid extended resource", mod: func(r *nfdv1alpha1.Rule) { r.ExtendedResources = map[string]string{"foo": "bar"} }},
+		{name: "empty rule name", mod: func(r *nfdv1alpha1.Rule) { r.Name = "" }},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			errs := v.nodeFeatureRuleSpec(&newTestRule(tc.mod).Spec)
+			if tc.valid {
+				assert.Empty(t, errs)
+			} else {
+				assert.NotEmpty(t, errs)
+			}
+		})
+	}
+
+	group := &nfdv1alpha1.NodeFeatureGroupSpec{Rules: []nfdv1alpha1.GroupRule{{Name: "group-rule", MatchFeatures: nfdv1alpha1.FeatureMatcher{{Feature: "invalid"}}}}}
+	assert.NotEmpty(t, v.nodeFeatureGroupSpec(group))
+}
+
+func TestServeValidate(t *testing.T) {
+	a := &nfdAdmission{}
+	a.validator.Store(newValidator(nil, nil))
+	srv := httptest.NewServer(http.HandlerFunc(a.serveValidate))
+	defer srv.Close()
+
+	review := func(obj runtime.Object, kind string) *admissionv1.AdmissionResponse {
+		raw, err := json.Marshal(obj)
+		assert.NoError(t, err)
+		req := admissionv1.AdmissionReview{
+			TypeMeta: metav1.TypeMeta{APIVersion: "admission.k8s.io/v1", Kind: "AdmissionReview"},
+			Request: &admissionv1.AdmissionRequest{
+				UID:       "1234",
+				Kind:      metav1.GroupVersionKind{Group: "nfd.k8s-sigs.io", Version: "v1alpha1", Kind: kind},
+				Operation: admissionv1.Create,
+				Object:    runtime.RawExtension{Raw: raw},
+			},
+		}
+		body, err := json.Marshal(req)
+		assert.NoError(t, err)
+		resp, err := http.Post(srv.URL, "application/json", bytes.NewReader(body))
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		out := admissionv1.AdmissionReview{}
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+		assert.Equal(t, req.Request.UID, out.Response.UID)
+		return out.Response
+	}
+
+	resp := review(newTestRule(nil), "NodeFeatureRule")
+	assert.True(t, resp.Allowed)
+
+	resp = review(newTestRule(func(r *nfdv1alpha1.Rule) { r.LabelsTemplate = "{{ .foo" }), "NodeFeatureRule")
+	assert.False(t, resp.Allowed)
+	assert.Equal(t, int32(http.StatusUnprocessableEntity), resp.Result.Code)
+	assert.Contains(t, resp.Result.Message, "invalid template")
+
+	resp = review(&nfdv1alpha1.NodeFeatureGroup{Spec: nfdv1alpha1.NodeFeatureGroupSpec{Rules: []nfdv1alpha1.GroupRule{{Name: "r"}}}}, "NodeFeatureGroup")
+	assert.True(t, resp.Allowed)
+
+	// Invalid requests
+	r, err := http.Get(srv.URL)
+	assert.NoError(t, err)
+	r.Body.Close()
+	assert.Equal(t, http.StatusMethodNotAllowed, r.StatusCode)
+	r, err = http.Post(srv.URL, "application/json", bytes.NewReader([]byte("{}")))
+	assert.NoError(t, err)
+	r.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+}
+
+func TestParseConfig(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "nfd-master.conf")
+	err := os.WriteFile(configFile, []byte(`
+denyLabelNs: ["*.denied.io"]
+extraLabelNs: ["extra.kubernetes.io"]
+noPublish: true
+`), 0644)
+	assert.NoError(t, err)
+
+	a := &nfdAdmission{args: &Args{ConfigFile: configFile}}
+	c, err := a.parseConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, utils.StringSetVal{"*.denied.io": {}}, c.DenyLabelNs)
+	assert.Equal(t, utils.StringSetVal{"extra.kubernetes.io": {}}, c.ExtraLabelNs)
+
+	// Command line flags override the config file
+	a.args.Overrides.ExtraLabelNs = &utils.StringSetVal{"other.kubernetes.io": {}}
+	c, err = a.parseConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, utils.StringSetVal{"*.denied.io": {}}, c.DenyLabelNs)
+	assert.Equal(t, utils.StringSetVal{"other.kubernetes.io": {}}, c.ExtraLabelNs)
+
+	// Missing config file is not an error
+	a.args = &Args{ConfigFile: filepath.Join(t.TempDir(), "missing.conf")}
+	c, err = a.parseConfig()
+	assert.NoError(t, err)
+	assert.Empty(t, c.DenyLabelNs)
+
+	// Invalid config file
+	assert.NoError(t, os.WriteFile(configFile, []byte("denyLabelNs: {"), 0644))
+	a.args = &Args{ConfigFile: configFile}
+	assert.Error(t, a.configure())
+}
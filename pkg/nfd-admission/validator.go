@@ -0,0 +1,185 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nfdadmission
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
+	"sigs.k8s.io/node-feature-discovery/pkg/apis/nfd/nodefeaturerule"
+	"sigs.k8s.io/node-feature-discovery/pkg/apis/nfd/validate"
+	"sigs.k8s.io/node-feature-discovery/pkg/utils"
+)
+
+// validator validates rule objects the same way nfd-master checks the rule
+// outputs when processing the rules.
+type validator struct {
+	deniedNs         utils.StringSetVal
+	wildcardDeniedNs utils.StringSetVal
+	extraLabelNs     utils.StringSetVal
+}
+
+func newValidator(denyLabelNs, extraLabelNs utils.StringSetVal) *validator {
+	v := &validator{
+		deniedNs:         utils.StringSetVal{},
+		wildcardDeniedNs: utils.StringSetVal{},
+		extraLabelNs:     extraLabelNs,
+	}
+	for ns := range denyLabelNs {
+		if strings.HasPrefix(ns, "*") {
+			v.wildcardDeniedNs[strings.TrimLeft(ns, "*")] = struct{}{}
+		} else {
+			v.deniedNs[ns] = struct{}{}
+		}
+	}
+	return v
+}
+
+func (v *validator) nodeFeatureRuleSpec(spec *nfdv1alpha1.NodeFeatureRuleSpec) []error {
+	var errs []error
+	for i := range spec.Rules {
+		for _, err := range v.rule(&spec.Rules[i]) {
+			errs = append(errs, fmt.Errorf("rule %q: %w", spec.Rules[i].Name, err))
+		}
+	}
+	return errs
+}
+
+func (v *validator) nodeFeatureGroupSpec(spec *nfdv1alpha1.NodeFeatureGroupSpec) []error {
+	return validate.NodeFeatureGroupSpec(spec)
+}
+
+func (v *validator) rule(rule *nfdv1alpha1.Rule) []error {
+	var errs []error
+
+	if rule.Name == "" {
+		errs = append(errs, fmt.Errorf("rule name cannot be empty"))
+	}
+
+	errs = append(errs, validate.MatchFeatures(rule.MatchFeatures)...)
+	errs = append(errs, validate.MatchAny(rule.MatchAny)...)
+	errs = append(errs, validate.MatchNode(rule.MatchNode)...)
+	errs = append(errs, validate.Template(rule.LabelsTemplate)...)
+	errs = append(errs, validate.Template(rule.VarsTemplate)...)
+
+	for _, labels := range []map[string]string{rule.Labels, rule.ElseLabels} {
+		for _, k := range sortedKeys(labels) {
+			if err := v.label(k, labels[k]); err != nil {
+				errs = append(errs, fmt.Errorf("invalid label %q: %w", k, err))
+			}
+		}
+	}
+	for _, k := range sortedKeys(rule.Annotations) {
+		name := addNs(k, nfdv1alpha1.FeatureAnnotationNs)
+		if err := validate.Annotation(name, rule.Annotations[k]); err != nil {
+			errs = append(errs, fmt.Errorf("invalid annotation %q: %w", k, err))
+		}
+	}
+	for _, k := range sortedKeys(rule.ExtendedResources) {
+		if err := extendedResource(k, rule.ExtendedResources[k]); err != nil {
+			errs = append(errs, fmt.Errorf("invalid extended resource %q: %w", k, err))
+		}
+	}
+	errs = append(errs, validate.Taints(rule.Taints)...)
+	errs = append(errs, validate.Taints(rule.ElseTaints)...)
+
+	return errs
+}
+
+// label checks a label like nfd-master does, including the denied and extra
+// label namespaces.
+func (v *validator) label(name, value string) error {
+	name = addNs(name, nfdv1alpha1.FeatureLabelNs)
+	if strings.HasPrefix(value, "@") {
+		if err := dynamicValue(value); err != nil {
+			return err
+		}
+		value = "0"
+	}
+
+	ns, _, _ := strings.Cut(name, "/")
+	err := validate.Label(name, value)
+	if errors.Is(err, validate.ErrNSNotAllowed) || v.isNamespaceDenied(ns) {
+		if _, ok := v.extraLabelNs[ns]; !ok {
+			return fmt.Errorf("namespace %q is not allowed", ns)
+		}
+	} else if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (v *validator) isNamespaceDenied(ns string) bool {
+	if _, ok := v.deniedNs[ns]; ok {
+		return true
+	}
+	for suffix := range v.wildcardDeniedNs {
+		if strings.HasSuffix(ns, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+func extendedResource(name, value string) error {
+	name = addNs(name, nfdv1alpha1.ExtendedResourceNs)
+	if strings.HasPrefix(value, "@") {
+		ref, conversion := nodefeaturerule.SplitUnitConversion(value)
+		if err := dynamicValue(ref); err != nil {
+			return err
+		}
+		if conversion != "" {
+			if _, err := nodefeaturerule.ConvertUnits("0", conversion); err != nil {
+				return fmt.Errorf("invalid unit conversion: %w", err)
+			}
+		}
+		value = resource.NewQuantity(0, resource.DecimalSI).String()
+	}
+	return validate.ExtendedResource(name, value)
+}
+
+// dynamicValue checks the syntax of a dynamic value reference.
+func dynamicValue(value string) error {
+	if split := strings.SplitN(value[1:], ".", 3); len(split) != 3 {
+		return fmt.Errorf("value %s is not in the form of '@domain.feature.element'", value)
+	}
+	return nil
+}
+
+// addNs adds a namespace to a name without one, like nfd-master does with
+// the default configuration.
+func addNs(name, ns string) string {
+	if strings.Contains(name, "/") {
+		return name
+	}
+	return path.Join(ns, name)
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,256 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nfddeviceplugin
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/informers"
+	k8sclient "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+	pluginapi "k8s.io/kubelet/pkg/apis/deviceplugin/v1beta1"
+
+	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
+	"sigs.k8s.io/node-feature-discovery/pkg/utils"
+	"sigs.k8s.io/node-feature-discovery/pkg/version"
+)
+
+const (
+	// maxDevices is the maximum number of devices advertised for one
+	// extended resource. Every device is sent to the kubelet individually so
+	// large capacities would result in huge ListAndWatch responses.
+	maxDevices = 100000
+	// retryInterval is the interval for re-trying failed registrations.
+	retryInterval = 30 * time.Second
+	// resyncPeriod is the resync period of the node informer.
+	resyncPeriod = time.Hour
+)
+
+// Args are the command line arguments of NfdDevicePlugin.
+type Args struct {
+	Instance   string
+	Kubeconfig string
+	PluginDir  string
+	// AnnotationNs is the annotation namespace configured in nfd-master.
+	AnnotationNs string
+}
+
+// NfdDevicePlugin advertises the extended resources created by nfd-master to
+// the kubelet of the node it is running on.
+type NfdDevicePlugin interface {
+	Run() error
+	Stop()
+}
+
+type nfdDevicePlugin struct {
+	args          *Args
+	k8sClient     k8sclient.Interface
+	nodeName      string
+	kubeletSocket string
+	servers       map[string]*resourceServer
+	// value is the last seen value of the extended resource annotation
+	value    string
+	stopChan chan struct{}
+}
+
+// New returns a new NfdDevicePlugin instance.
+func New(args *Args) (NfdDevicePlugin, error) {
+	kubeconfig, err := utils.GetKubeconfig(args.Kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	cli, err := k8sclient.NewForConfig(kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	return newNfdDevicePlugin(args, cli), nil
+}
+
+func newNfdDevicePlugin(args *Args, cli k8sclient.Interface) *nfdDevicePlugin {
+	if args.PluginDir == "" {
+		args.PluginDir = pluginapi.DevicePluginPath
+	}
+	return &nfdDevicePlugin{
+		args:          args,
+		k8sClient:     cli,
+		nodeName:      utils.NodeName(),
+		kubeletSocket: filepath.Join(args.PluginDir, filepath.Base(pluginapi.KubeletSocket)),
+		servers:       make(map[string]*resourceServer),
+		stopChan:      make(chan struct{}),
+	}
+}
+
+// Run the device plugin. Blocks until Stop() is called.
+func (p *nfdDevicePlugin) Run() error {
+	klog.InfoS("Node Feature Discovery Device Plugin", "version", version.Get(), "nodeName", p.nodeName)
+
+	// Watch the plugin directory in order to detect kubelet restarts
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create fsnotify watcher: %w", err)
+	}
+	defer watcher.Close()
+	if err := watcher.Add(p.args.PluginDir); err != nil {
+		return fmt.Errorf("failed to watch %q: %w", p.args.PluginDir, err)
+	}
+
+	values, err := p.startNodeInformer()
+	if err != nil {
+		return err
+	}
+
+	var retry <-chan time.Time
+	for {
+		select {
+		case v := <-values:
+			p.value = v
+			retry = p.update()
+		case <-retry:
+			retry = p.update()
+		case e := <-watcher.Events:
+			if e.Name == p.kubeletSocket && e.Has(fsnotify.Create) {
+				klog.InfoS("kubelet restart detected, re-registering extended resources")
+				p.shutdownServers()
+				retry = p.update()
+			}
+		case err := <-watcher.Errors:
+			klog.ErrorS(err, "fsnotify error")
+		case <-p.stopChan:
+			p.shutdownServers()
+			klog.InfoS("shutting down nfd-device-plugin")
+			return nil
+		}
+	}
+}
+
+// Stop the device plugin.
+func (p *nfdDevicePlugin) Stop() {
+	close(p.stopChan)
+}
+
+// startNodeInformer starts watching the node object. The value of the
+// extended resource annotation is sent to the returned channel on every
+// change.
+func (p *nfdDevicePlugin) startNodeInformer() (<-chan string, error) {
+	factory := informers.NewSharedInformerFactoryWithOptions(p.k8sClient, resyncPeriod,
+		informers.WithTweakListOptions(func(opts *metav1.ListOptions) {
+			opts.FieldSelector = fields.OneTermEqualSelector("metadata.name", p.nodeName).String()
+		}))
+	informer := factory.Core().V1().Nodes().Informer()
+
+	annotation := nfdv1alpha1.ExtendedResourceValuesAnnotation
+	if ns := p.args.AnnotationNs; ns != "" && ns != nfdv1alpha1.AnnotationNs {
+		annotation = ns + strings.TrimPrefix(annotation, nfdv1alpha1.AnnotationNs)
+	}
+	if p.args.Instance != "" {
+		annotation = p.args.Instance + "." + annotation
+	}
+
+	values := make(chan string)
+	send := func(obj interface{}) {
+		node, ok := obj.(*corev1.Node)
+		if !ok {
+			return
+		}
+		select {
+		case values <- node.Annotations[annotation]:
+		case <-p.stopChan:
+		}
+	}
+	if _, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    send,
+		UpdateFunc: func(_, newObj interface{}) { send(newObj) },
+		DeleteFunc: func(_ interface{}) {
+			select {
+			case values <- "":
+			case <-p.stopChan:
+			}
+		},
+	}); err != nil {
+		return nil, err
+	}
+
+	factory.Start(p.stopChan)
+	for t, ok := range factory.WaitForCacheSync(p.stopChan) {
+		if !ok {
+			return nil, fmt.Errorf("failed to sync %v informer cache", t)
+		}
+	}
+	return values, nil
+}
+
+// update reconciles the running device plugin servers with the last seen
+// annotation value. It returns a timer channel for re-trying if registering
+// some resources failed.
+func (p *nfdDevicePlugin) update() <-chan time.Time {
+	counts, err := ParseExtendedResourceValues(p.value)
+	if err != nil {
+		klog.ErrorS(err, "failed to parse extended resources annotation", "nodeName", p.nodeName)
+		return nil
+	}
+
+	for name, s := range p.servers {
+		if _, ok := counts[name]; !ok {
+			s.shutdown()
+			delete(p.servers, name)
+			klog.InfoS("extended resource removed", "resourceName", name)
+		}
+	}
+
+	failed := false
+	for name, count := range counts {
+		if count > maxDevices {
+			klog.ErrorS(fmt.Errorf("value %d exceeds the maximum of %d", count, maxDevices), "ignoring extended resource", "resourceName", name)
+			if s, ok := p.servers[name]; ok {
+				s.shutdown()
+				delete(p.servers, name)
+			}
+			continue
+		}
+		if s, ok := p.servers[name]; ok {
+			s.setCount(count)
+			continue
+		}
+		s := newResourceServer(name, p.args.PluginDir, count)
+		if err := s.start(p.kubeletSocket); err != nil {
+			klog.ErrorS(err, "failed to start device plugin", "resourceName", name)
+			failed = true
+			continue
+		}
+		p.servers[name] = s
+	}
+
+	if failed {
+		return time.After(retryInterval)
+	}
+	return nil
+}
+
+func (p *nfdDevicePlugin) shutdownServers() {
+	for name, s := range p.servers {
+		s.shutdown()
+		delete(p.servers, name)
+	}
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nfddeviceplugin
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	pluginapi "k8s.io/kubelet/pkg/apis/deviceplugin/v1beta1"
+)
+
+func TestExtendedResourceValues(t *testing.T) {
+	Convey("When formatting extended resource values", t, func() {
+		s := FormatExtendedResourceValues(map[string]string{"vendor.io/b": "2", "vendor.io/a": "10"})
+		So(s, ShouldEqual, "vendor.io/a=10,vendor.io/b=2")
+
+		Convey("they should be parsed back", func() {
+			v, err := ParseExtendedResourceValues(s)
+			So(err, ShouldBeNil)
+			So(v, ShouldResemble, map[string]int64{"vendor.io/a": 10, "vendor.io/b": 2})
+		})
+	})
+
+	Convey("When parsing extended resource values", t, func() {
+		v, err := ParseExtendedResourceValues("")
+		So(err, ShouldBeNil)
+		So(v, ShouldBeEmpty)
+
+		v, err = ParseExtendedResourceValues("vendor.io/a=2k")
+		So(err, ShouldBeNil)
+		So(v, ShouldResemble, map[string]int64{"vendor.io/a": 2000})
+
+		_, err = ParseExtendedResourceValues("vendor.io/a")
+		So(err, ShouldNotBeNil)
+		_, err = ParseExtendedResourceValues("vendor.io/a=foo")
+		So(err, ShouldNotBeNil)
+		_, err = ParseExtendedResourceValues("vendor.io/a=-1")
+		So(err, ShouldNotBeNil)
+	})
+}
+
+type fakeKubelet struct {
+	pluginapi.UnimplementedRegistrationServer
+
+	sync.Mutex
+	requests []*pluginapi.RegisterRequest
+}
+
+func (k *fakeKubelet) Register(_ context.Context, req *pluginapi.RegisterRequest) (*pluginapi.Empty, error) {
+	k.Lock()
+	defer k.Unlock()
+	k.requests = append(k.requests, req)
+	return &pluginapi.Empty{}, nil
+}
+
+func startFakeKubelet(t *testing.T, socket string) *fakeKubelet {
+	lis, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	k := &fakeKubelet{}
+	srv := grpc.NewServer()
+	pluginapi.RegisterRegistrationServer(srv, k)
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+	return k
+}
+
+func TestResourceServer(t *testing.T) {
+	pluginDir := t.TempDir()
+	kubeletSocket := filepath.Join(pluginDir, "kubelet.sock")
+	kubelet := startFakeKubelet(t, kubeletSocket)
+
+	Convey("When starting a resource server", t, func() {
+		s := newResourceServer("vendor.io/foo", pluginDir, 2)
+		So(s.start(kubeletSocket), ShouldBeNil)
+		defer s.shutdown()
+
+		Convey("the resource should be registered with the kubelet", func() {
+			kubelet.Lock()
+			defer kubelet.Unlock()
+			So(kubelet.requests, ShouldHaveLength, 1)
+			So(kubelet.requests[0].ResourceName, ShouldEqual, "vendor.io/foo")
+			So(kubelet.requests[0].Endpoint, ShouldEqual, "nfd-vendor.io_foo.sock")
+			So(kubelet.requests[0].Version, ShouldEqual, pluginapi.Version)
+		})
+
+		Convey("devices should be updated in ListAndWatch", func() {
+			conn, err := grpc.NewClient("unix://"+s.socket, grpc.WithTransportCredentials(insecure.NewCredentials()))
+			So(err, ShouldBeNil)
+			defer conn.Close()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			stream, err := pluginapi.NewDevicePluginClient(conn).ListAndWatch(ctx, &pluginapi.Empty{})
+			So(err, ShouldBeNil)
+
+			resp, err := stream.Recv()
+			So(err, ShouldBeNil)
+			So(resp.Devices, ShouldHaveLength, 2)
+			So(resp.Devices[0].Health, ShouldEqual, pluginapi.Healthy)
+
+			s.setCount(5)
+			resp, err = stream.Recv()
+			So(err, ShouldBeNil)
+			So(resp.Devices, ShouldHaveLength, 5)
+
+			aresp, err := pluginapi.NewDevicePluginClient(conn).Allocate(ctx, &pluginapi.AllocateRequest{
+				ContainerRequests: []*pluginapi.ContainerAllocateRequest{{DevicesIDs: []string{"nfd-0"}}},
+			})
+			So(err, ShouldBeNil)
+			So(aresp.ContainerResponses, ShouldHaveLength, 1)
+		})
+	})
+}
@@ -0,0 +1,195 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nfddeviceplugin
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"k8s.io/klog/v2"
+	pluginapi "k8s.io/kubelet/pkg/apis/deviceplugin/v1beta1"
+)
+
+// registerTimeout is the timeout for registering a resource with the kubelet.
+const registerTimeout = 10 * time.Second
+
+var socketNameInvalidChars = regexp.MustCompile(`[^A-Za-z0-9_.-]`)
+
+// resourceServer is a device plugin advertising one extended resource as a
+// number of identical, always healthy, devices. The devices do not represent
+// any actual hardware so allocation is a no-op.
+type resourceServer struct {
+	pluginapi.UnimplementedDevicePluginServer
+
+	resourceName string
+	socket       string
+	server       *grpc.Server
+
+	sync.Mutex
+	count int64
+	// changed is closed (and replaced) when the device count changes
+	changed chan struct{}
+	stop    chan struct{}
+}
+
+func newResourceServer(resourceName, pluginDir string, count int64) *resourceServer {
+	return &resourceServer{
+		resourceName: resourceName,
+		socket:       filepath.Join(pluginDir, "nfd-"+socketNameInvalidChars.ReplaceAllString(resourceName, "_")+".sock"),
+		count:        count,
+		changed:      make(chan struct{}),
+		stop:         make(chan struct{}),
+	}
+}
+
+// start starts serving the device plugin API and registers the resource with
+// the kubelet.
+func (s *resourceServer) start(kubeletSocket string) error {
+	if err := os.Remove(s.socket); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove stale socket %q: %w", s.socket, err)
+	}
+	lis, err := net.Listen("unix", s.socket)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %q: %w", s.socket, err)
+	}
+
+	s.server = grpc.NewServer()
+	pluginapi.RegisterDevicePluginServer(s.server, s)
+	go func() {
+		if err := s.server.Serve(lis); err != nil {
+			klog.ErrorS(err, "device plugin server exited with an error", "resourceName", s.resourceName)
+		}
+	}()
+
+	if err := s.register(kubeletSocket); err != nil {
+		s.shutdown()
+		return err
+	}
+	klog.InfoS("extended resource registered with the kubelet", "resourceName", s.resourceName, "devices", s.getCount())
+	return nil
+}
+
+func (s *resourceServer) register(kubeletSocket string) error {
+	conn, err := grpc.NewClient("unix://"+kubeletSocket, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return fmt.Errorf("failed to connect to the kubelet: %w", err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), registerTimeout)
+	defer cancel()
+	_, err = pluginapi.NewRegistrationClient(conn).Register(ctx, &pluginapi.RegisterRequest{
+		Version:      pluginapi.Version,
+		Endpoint:     filepath.Base(s.socket),
+		ResourceName: s.resourceName,
+		Options:      &pluginapi.DevicePluginOptions{},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to register %q with the kubelet: %w", s.resourceName, err)
+	}
+	return nil
+}
+
+// shutdown stops the server and removes the socket.
+func (s *resourceServer) shutdown() {
+	close(s.stop)
+	if s.server != nil {
+		s.server.Stop()
+	}
+	if err := os.Remove(s.socket); err != nil && !os.IsNotExist(err) {
+		klog.ErrorS(err, "failed to remove device plugin socket", "path", s.socket)
+	}
+}
+
+func (s *resourceServer) getCount() int64 {
+	s.Lock()
+	defer s.Unlock()
+	return s.count
+}
+
+// setCount updates the number of advertised devices.
+func (s *resourceServer) setCount(count int64) {
+	s.Lock()
+	defer s.Unlock()
+	if count == s.count {
+		return
+	}
+	s.count = count
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+func (s *resourceServer) devices() ([]*pluginapi.Device, <-chan struct{}) {
+	s.Lock()
+	defer s.Unlock()
+	devs := make([]*pluginapi.Device, s.count)
+	for i := range devs {
+		devs[i] = &pluginapi.Device{ID: "nfd-" + strconv.Itoa(i), Health: pluginapi.Healthy}
+	}
+	return devs, s.changed
+}
+
+// GetDevicePluginOptions method of the DevicePluginServer interface.
+func (s *resourceServer) GetDevicePluginOptions(context.Context, *pluginapi.Empty) (*pluginapi.DevicePluginOptions, error) {
+	return &pluginapi.DevicePluginOptions{}, nil
+}
+
+// ListAndWatch method of the DevicePluginServer interface.
+func (s *resourceServer) ListAndWatch(_ *pluginapi.Empty, stream pluginapi.DevicePlugin_ListAndWatchServer) error {
+	for {
+		devs, changed := s.devices()
+		if err := stream.Send(&pluginapi.ListAndWatchResponse{Devices: devs}); err != nil {
+			return err
+		}
+		select {
+		case <-changed:
+		case <-s.stop:
+			return nil
+		case <-stream.Context().Done():
+			return nil
+		}
+	}
+}
+
+// Allocate method of the DevicePluginServer interface. The devices do not
+// require any setup in the containers.
+func (s *resourceServer) Allocate(_ context.Context, req *pluginapi.AllocateRequest) (*pluginapi.AllocateResponse, error) {
+	resp := &pluginapi.AllocateResponse{}
+	for range req.ContainerRequests {
+		resp.ContainerResponses = append(resp.ContainerResponses, &pluginapi.ContainerAllocateResponse{})
+	}
+	return resp, nil
+}
+
+// GetPreferredAllocation method of the DevicePluginServer interface.
+func (s *resourceServer) GetPreferredAllocation(context.Context, *pluginapi.PreferredAllocationRequest) (*pluginapi.PreferredAllocationResponse, error) {
+	return &pluginapi.PreferredAllocationResponse{}, nil
+}
+
+// PreStartContainer method of the DevicePluginServer interface.
+func (s *resourceServer) PreStartContainer(context.Context, *pluginapi.PreStartContainerRequest) (*pluginapi.PreStartContainerResponse, error) {
+	return &pluginapi.PreStartContainerResponse{}, nil
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nfddeviceplugin
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// FormatExtendedResourceValues serializes extended resources into the value
+// of the extended resource values node annotation, i.e. a sorted
+// comma-separated list of <name>=<value> pairs.
+func FormatExtendedResourceValues(extendedResources map[string]string) string {
+	items := make([]string, 0, len(extendedResources))
+	for name, value := range extendedResources {
+		items = append(items, name+"="+value)
+	}
+	sort.Strings(items)
+	return strings.Join(items, ",")
+}
+
+// ParseExtendedResourceValues parses the value of the extended resource
+// values node annotation into device counts.
+func ParseExtendedResourceValues(s string) (map[string]int64, error) {
+	out := make(map[string]int64)
+	if s == "" {
+		return out, nil
+	}
+	for _, item := range strings.Split(s, ",") {
+		name, value, ok := strings.Cut(item, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid extended resource %q, expected <name>=<value>", item)
+		}
+		q, err := resource.ParseQuantity(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value of extended resource %q: %w", name, err)
+		}
+		n, ok := q.AsInt64()
+		if !ok || n < 0 {
+			return nil, fmt.Errorf("invalid value of extended resource %q: %q is not a non-negative integer", name, value)
+		}
+		out[name] = n
+	}
+	return out, nil
+}
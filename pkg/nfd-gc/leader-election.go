@@ -0,0 +1,114 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nfdgarbagecollector
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// leaseName is the name of the Lease object used for electing the
+	// active nfd-gc replica.
+	leaseName = "nfd-gc.nfd.kubernetes.io"
+
+	leaseDuration = 15 * time.Second
+	renewDeadline = 10 * time.Second
+	retryPeriod   = 2 * time.Second
+)
+
+// runWithLeaderElection runs the garbage collector only while holding the
+// leader election lease. Other replicas stay on standby, waiting to take over
+// if the leader goes away. An error is returned if the lease is lost.
+func (n *nfdGarbageCollector) runWithLeaderElection() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-n.stopChan:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		return fmt.Errorf("failed to get hostname: %w", err)
+	}
+
+	errChan := make(chan error, 2)
+	config := leaderelection.LeaderElectionConfig{
+		Lock: &resourcelock.LeaseLock{
+			LeaseMeta: metav1.ObjectMeta{
+				Name:      leaseName,
+				Namespace: n.namespace,
+			},
+			Client: n.k8sClient.CoordinationV1(),
+			LockConfig: resourcelock.ResourceLockConfig{
+				// Add uuid to prevent clashes of replicas with the same hostname
+				Identity: hostname + "_" + uuid.NewString(),
+			},
+		},
+		LeaseDuration:   leaseDuration,
+		RenewDeadline:   renewDeadline,
+		RetryPeriod:     retryPeriod,
+		ReleaseOnCancel: true,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(_ context.Context) {
+				klog.InfoS("acquired leader election lease, starting garbage collection")
+				isLeader.Set(1)
+				if err := n.startNodeInformer(); err != nil {
+					errChan <- err
+					cancel()
+					return
+				}
+				n.periodicGC(n.args.GCPeriod)
+			},
+			OnStoppedLeading: func() {
+				isLeader.Set(0)
+				select {
+				case <-n.stopChan:
+				default:
+					klog.InfoS("leader election lease was lost")
+					errChan <- fmt.Errorf("lost leader election lease")
+				}
+			},
+		},
+	}
+	leaderElector, err := leaderelection.NewLeaderElector(config)
+	if err != nil {
+		return fmt.Errorf("failed to create leader elector: %w", err)
+	}
+
+	klog.InfoS("waiting for leader election lease", "lease", klog.KRef(n.namespace, leaseName))
+	leaderElector.Run(ctx)
+
+	select {
+	case err := <-errChan:
+		return err
+	default:
+		return nil
+	}
+}
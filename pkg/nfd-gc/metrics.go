@@ -26,6 +26,9 @@ const (
 	buildInfoQuery          = "build_info"
 	objectsDeletedQuery     = "objects_deleted_total"
 	objectDeleteErrorsQuery = "object_delete_failures_total"
+	nodesPrunedQuery        = "nodes_pruned_total"
+	nodePruneErrorsQuery    = "node_prune_failures_total"
+	isLeaderQuery           = "leader"
 )
 
 const (
@@ -54,6 +57,21 @@ var (
 		Help:      "Number of errors in deleting NodeFeature and NodeResourceTopology objects."},
 		[]string{"kind"},
 	)
+	nodesPruned = prometheus.NewCounter(prometheus.CounterOpts{
+		Subsystem: nfdGCPrefix,
+		Name:      nodesPrunedQuery,
+		Help:      "Number of nodes whose orphaned labels, annotations and extended resources were removed.",
+	})
+	nodePruneErrors = prometheus.NewCounter(prometheus.CounterOpts{
+		Subsystem: nfdGCPrefix,
+		Name:      nodePruneErrorsQuery,
+		Help:      "Number of errors in removing orphaned labels, annotations and extended resources from nodes.",
+	})
+	isLeader = prometheus.NewGauge(prometheus.GaugeOpts{
+		Subsystem: nfdGCPrefix,
+		Name:      isLeaderQuery,
+		Help:      "Whether this nfd-gc replica is the active leader (1) or on standby (0). Only set when leader election is enabled.",
+	})
 )
 
 // registerVersion exposes the Operator build version.
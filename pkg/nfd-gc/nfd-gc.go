@@ -27,7 +27,8 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
-	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/types"
+	k8sclient "k8s.io/client-go/kubernetes"
 	metadataclient "k8s.io/client-go/metadata"
 	"k8s.io/client-go/metadata/metadatainformer"
 	"k8s.io/client-go/tools/cache"
@@ -49,6 +50,22 @@ type Args struct {
 	GCPeriod    time.Duration
 	Kubeconfig  string
 	MetricsPort int
+	// MetricsCertFile and MetricsKeyFile enable TLS on the metrics server.
+	MetricsCertFile string
+	MetricsKeyFile  string
+	// PruneNodeLabels enables removing the labels, feature annotations and
+	// extended resources created by nfd-master from nodes that do not have
+	// any NodeFeature objects.
+	PruneNodeLabels bool
+	// Instance and AnnotationNs specify the tracking annotations and
+	// ConfigMaps of the nfd-master instance whose labels (et al.) are
+	// pruned. They must match the -instance flag and the annotationNs
+	// setting of nfd-master.
+	Instance     string
+	AnnotationNs string
+	// EnableLeaderElection enables running multiple replicas of nfd-gc of
+	// which only the leader is active.
+	EnableLeaderElection bool
 }
 
 type NfdGarbageCollector interface {
@@ -57,10 +74,12 @@ type NfdGarbageCollector interface {
 }
 
 type nfdGarbageCollector struct {
-	args     *Args
-	stopChan chan struct{}
-	client   metadataclient.Interface
-	factory  metadatainformer.SharedInformerFactory
+	args      *Args
+	stopChan  chan struct{}
+	client    metadataclient.Interface
+	k8sClient k8sclient.Interface
+	factory   metadatainformer.SharedInformerFactory
+	namespace string
 }
 
 func New(args *Args) (NfdGarbageCollector, error) {
@@ -72,10 +91,12 @@ func New(args *Args) (NfdGarbageCollector, error) {
 	cli := metadataclient.NewForConfigOrDie(kubeconfig)
 
 	return &nfdGarbageCollector{
-		args:     args,
-		stopChan: make(chan struct{}),
-		client:   cli,
-		factory:  metadatainformer.NewSharedInformerFactory(cli, 0),
+		args:      args,
+		stopChan:  make(chan struct{}),
+		client:    cli,
+		k8sClient: k8sclient.NewForConfigOrDie(kubeconfig),
+		factory:   metadatainformer.NewSharedInformerFactory(cli, 0),
+		namespace: utils.GetKubernetesNamespace(),
 	}, nil
 }
 
@@ -126,7 +147,13 @@ func (n *nfdGarbageCollector) deleteNodeHandler(object interface{}) {
 	}
 	nodeName := meta.ObjectMeta.GetName()
 
-	n.deleteNRT(nodeName)
+	// The node may already have been re-created with the same name, don't
+	// delete the NodeResourceTopology object created for the new node
+	if nrt, err := n.client.Resource(gvrNRT).Get(context.TODO(), nodeName, metav1.GetOptions{}); err == nil && !nodeUIDMatches(nrt, meta.UID) {
+		klog.V(2).InfoS("NodeResourceTopology object belongs to a re-created node, omitting deletion", "nodeName", nodeName)
+	} else {
+		n.deleteNRT(nodeName)
+	}
 
 	// Delete all NodeFeature objects (from all namespaces) targeting the deleted node
 	nfListOptions := metav1.ListOptions{LabelSelector: nfdv1alpha1.NodeFeatureObjNodeNameLabel + "=" + nodeName}
@@ -147,10 +174,11 @@ func (n *nfdGarbageCollector) garbageCollect() {
 		klog.ErrorS(err, "failed to list Node objects")
 		return
 	}
-	nodeNames := sets.NewString()
+	nodeUIDs := make(map[string]types.UID, len(objs))
+	nodesWithFeatures := make(map[string]struct{})
 	for _, obj := range objs {
 		meta := obj.(*metav1.PartialObjectMetadata).ObjectMeta
-		nodeNames.Insert(meta.Name)
+		nodeUIDs[meta.Name] = meta.UID
 	}
 
 	listAndHandle := func(gvr schema.GroupVersionResource, handler func(metav1.PartialObjectMetadata)) {
@@ -183,17 +211,50 @@ func (n *nfdGarbageCollector) garbageCollect() {
 		if !ok {
 			klog.InfoS("node name label missing from NodeFeature object", "nodefeature", klog.KObj(&meta))
 		}
-		if !nodeNames.Has(nodeName) {
+		if _, ok := nodeUIDs[nodeName]; !ok {
 			n.deleteNodeFeature(meta.Namespace, meta.Name)
+		} else {
+			nodesWithFeatures[nodeName] = struct{}{}
 		}
 	})
 
 	// Handle NodeResourceTopology objects
 	listAndHandle(gvrNRT, func(meta metav1.PartialObjectMetadata) {
-		if !nodeNames.Has(meta.Name) {
+		nodeUID, ok := nodeUIDs[meta.Name]
+		if !ok {
+			n.deleteNRT(meta.Name)
+		} else if !nodeUIDMatches(&meta, nodeUID) {
+			// Delete objects of re-created nodes, the topology-updater
+			// running on the new node will create a fresh object
+			klog.InfoS("stale NodeResourceTopology object of a re-created node found", "nodeName", meta.Name, "nodeUID", nodeUID)
 			n.deleteNRT(meta.Name)
 		}
 	})
+
+	// Handle nodes whose NodeFeature objects have been removed, e.g. when
+	// nfd-worker was uninstalled while nfd-master was not running
+	if n.args.PruneNodeLabels {
+		names := n.trackingNames()
+		configMaps := n.trackingConfigMaps()
+		for _, obj := range objs {
+			meta := obj.(*metav1.PartialObjectMetadata)
+			if _, ok := nodesWithFeatures[meta.Name]; ok {
+				continue
+			}
+			if hasNodeFeatures(meta, names) || configMaps.Has(names.ConfigMapName(meta.Name)) {
+				klog.InfoS("node without NodeFeature objects has orphaned labels (et al.)", "nodeName", meta.Name)
+				n.pruneNode(meta.Name)
+			}
+		}
+	}
+}
+
+// nodeUIDMatches returns false if a NodeResourceTopology object has been
+// created for a node with a different UID. Objects without the node UID
+// annotation are assumed to match.
+func nodeUIDMatches(nrt metav1.Object, nodeUID types.UID) bool {
+	uid, ok := nrt.GetAnnotations()[nfdv1alpha1.NodeUIDAnnotation]
+	return !ok || uid == "" || types.UID(uid) == nodeUID
 }
 
 // periodicGC runs garbage collector at every gcPeriod to make sure we haven't missed any node
@@ -240,19 +301,36 @@ func (n *nfdGarbageCollector) startNodeInformer() error {
 
 // Run is a blocking function that removes stale NRT objects when Node is deleted and runs periodic GC to make sure any obsolete objects are removed
 func (n *nfdGarbageCollector) Run() error {
+	var httpServer *utils.HTTPServer
 	if n.args.MetricsPort > 0 {
-		m := utils.CreateMetricsServer(n.args.MetricsPort,
-			buildInfo,
-			objectsDeleted,
-			objectDeleteErrors)
-		go m.Run()
+		httpServer = utils.NewHTTPServer(n.args.MetricsPort,
+			utils.WithMetrics(
+				buildInfo,
+				objectsDeleted,
+				objectDeleteErrors,
+				nodesPruned,
+				nodePruneErrors,
+				isLeader),
+			utils.WithTLS(n.args.MetricsCertFile, n.args.MetricsKeyFile))
+		go httpServer.Run()
 		registerVersion(version.Get())
-		defer m.Stop()
+		defer httpServer.Stop()
+	}
+
+	if n.args.EnableLeaderElection {
+		// Replicas on standby are healthy, too
+		if httpServer != nil {
+			httpServer.SetReady(true)
+		}
+		return n.runWithLeaderElection()
 	}
 
 	if err := n.startNodeInformer(); err != nil {
 		return err
 	}
+	if httpServer != nil {
+		httpServer.SetReady(true)
+	}
 	// run periodic GC
 	n.periodicGC(n.args.GCPeriod)
 
@@ -25,13 +25,20 @@ import (
 	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
+	fakek8sclient "k8s.io/client-go/kubernetes/fake"
 	metadataclient "k8s.io/client-go/metadata"
 	"k8s.io/client-go/metadata/fake"
 	"k8s.io/client-go/metadata/metadatainformer"
 
+	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
+	"sigs.k8s.io/node-feature-discovery/pkg/nodetracking"
+
 	. "github.com/smartystreets/goconvey/convey"
 )
 
@@ -86,13 +93,211 @@ func TestNRTGC(t *testing.T) {
 
 		So(gc.client, shouldEventuallyHaveNRTs, "node1", "node2")
 	})
+	Convey("periodic GC should remove NRTs of re-created nodes", t, func() {
+		gc := newMockGC([]string{"node1", "node2", "node3"}, nil)
+
+		gvr := topologyv1alpha2.SchemeGroupVersion.WithResource("noderesourcetopologies")
+		for name, uid := range map[string]string{"node1": "uid-node1", "node2": "uid-old", "node3": ""} {
+			nrt := createPartialObjectMetadata("topology.node.k8s.io/v1alpha2", "NodeResourceTopology", "", name)
+			if uid != "" {
+				nrt.Annotations = map[string]string{nfdv1alpha1.NodeUIDAnnotation: uid}
+			}
+			_, err := gc.client.Resource(gvr).(fake.MetadataClient).CreateFake(nrt, metav1.CreateOptions{})
+			So(err, ShouldBeNil)
+		}
+
+		So(gc.startNodeInformer(), ShouldBeNil)
+		gc.garbageCollect()
+		gc.Stop()
+
+		So(gc.client, shouldEventuallyHaveNRTs, "node1", "node3")
+	})
+}
+
+func TestLeaderElection(t *testing.T) {
+	Convey("When leader election is enabled", t, func() {
+		gc := newMockGC(nil, []string{"node1"})
+		gc.args.EnableLeaderElection = true
+		gc.k8sClient = fakek8sclient.NewSimpleClientset()
+		gc.namespace = "nfd"
+
+		errChan := make(chan error)
+		go func() { errChan <- gc.Run() }()
+
+		Convey("the leader should acquire the lease and collect garbage", func() {
+			So(gc.client, shouldEventuallyHaveNRTs)
+
+			lease, err := gc.k8sClient.CoordinationV1().Leases("nfd").Get(context.TODO(), leaseName, metav1.GetOptions{})
+			So(err, ShouldBeNil)
+			So(lease.Spec.HolderIdentity, ShouldNotBeNil)
+
+			gc.Stop()
+			So(<-errChan, ShouldBeNil)
+		})
+	})
+}
+
+func TestPruneNodes(t *testing.T) {
+	newNode := func(name string) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+				UID:  types.UID("uid-" + name),
+				Labels: map[string]string{
+					"feature.node.kubernetes.io/foo": "true",
+					"example.io/bar":                 "1",
+					"kubernetes.io/hostname":         name,
+				},
+				Annotations: map[string]string{
+					nfdv1alpha1.FeatureLabelsAnnotation:              "foo,example.io/bar",
+					nfdv1alpha1.FeatureAnnotationsTrackingAnnotation: "baz",
+					nfdv1alpha1.ExtendedResourceAnnotation:           "qux",
+					nfdv1alpha1.NodeTaintsAnnotation:                 "feature.node.kubernetes.io/taint=true:NoSchedule",
+					"feature.node.kubernetes.io/baz":                 "a",
+					"other.io/annotation":                            "b",
+				},
+			},
+			Spec: corev1.NodeSpec{
+				Taints: []corev1.Taint{
+					{Key: "feature.node.kubernetes.io/taint", Value: "true", Effect: corev1.TaintEffectNoSchedule},
+					{Key: "other.io/taint", Effect: corev1.TaintEffectNoSchedule},
+				},
+			},
+			Status: corev1.NodeStatus{
+				Capacity: corev1.ResourceList{
+					"feature.node.kubernetes.io/qux": resource.MustParse("2"),
+					corev1.ResourceCPU:               resource.MustParse("4"),
+				},
+				Allocatable: corev1.ResourceList{
+					"feature.node.kubernetes.io/qux": resource.MustParse("2"),
+				},
+			},
+		}
+	}
+
+	setNodes := func(gc *mockGC, nodes ...*corev1.Node) {
+		objs := []runtime.Object{}
+		for _, node := range nodes {
+			gvr := corev1.SchemeGroupVersion.WithResource("nodes")
+			So(gc.client.Resource(gvr).(fake.MetadataClient).Delete(context.TODO(), node.Name, metav1.DeleteOptions{}), ShouldBeNil)
+			meta := createPartialObjectMetadata("v1", "Node", "", node.Name)
+			meta.ObjectMeta = node.ObjectMeta
+			_, err := gc.client.Resource(gvr).(fake.MetadataClient).CreateFake(meta, metav1.CreateOptions{})
+			So(err, ShouldBeNil)
+			objs = append(objs, node)
+		}
+		gc.k8sClient = fakek8sclient.NewSimpleClientset(objs...)
+	}
+
+	Convey("When a node has no NodeFeature objects", t, func() {
+		gc := newMockGC([]string{"node1", "node2"}, nil)
+		setNodes(gc, newNode("node1"), newNode("node2"))
+
+		nf := createPartialObjectMetadata("nfd.k8s-sigs.io/v1alpha1", "NodeFeature", "default", "node1")
+		nf.Labels = map[string]string{nfdv1alpha1.NodeFeatureObjNodeNameLabel: "node1"}
+		_, err := gc.client.Resource(gvrNF).Namespace("default").(fake.MetadataClient).CreateFake(nf, metav1.CreateOptions{})
+		So(err, ShouldBeNil)
+
+		So(gc.startNodeInformer(), ShouldBeNil)
+		defer gc.Stop()
+
+		Convey("Labels (et al.) should be preserved if pruning is disabled", func() {
+			gc.garbageCollect()
+
+			node2, err := gc.k8sClient.CoreV1().Nodes().Get(context.TODO(), "node2", metav1.GetOptions{})
+			So(err, ShouldBeNil)
+			So(node2, ShouldResemble, newNode("node2"))
+		})
+
+		Convey("Labels (et al.) of the node should be removed if pruning is enabled", func() {
+			gc.args.PruneNodeLabels = true
+			gc.garbageCollect()
+
+			node1, err := gc.k8sClient.CoreV1().Nodes().Get(context.TODO(), "node1", metav1.GetOptions{})
+			So(err, ShouldBeNil)
+			So(node1, ShouldResemble, newNode("node1"))
+
+			node2, err := gc.k8sClient.CoreV1().Nodes().Get(context.TODO(), "node2", metav1.GetOptions{})
+			So(err, ShouldBeNil)
+			So(node2.Labels, ShouldResemble, map[string]string{"kubernetes.io/hostname": "node2"})
+			So(node2.Annotations, ShouldResemble, map[string]string{"other.io/annotation": "b"})
+			So(node2.Status.Capacity, ShouldResemble, corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")})
+			So(node2.Status.Allocatable, ShouldBeEmpty)
+			So(node2.Spec.Taints, ShouldResemble, []corev1.Taint{{Key: "other.io/taint", Effect: corev1.TaintEffectNoSchedule}})
+		})
+	})
+
+	Convey("When nfd-master uses an instance name, a custom annotation namespace and ConfigMap tracking storage", t, func() {
+		gc := newMockGC([]string{"node1", "node2"}, nil)
+		gc.namespace = "nfd"
+		gc.args.Instance = "foo"
+		gc.args.AnnotationNs = "nfd.example.com"
+
+		// node1 has its tracking information in a ConfigMap, node2 in
+		// instance-specific annotations in the custom namespace
+		node1 := newNode("node1")
+		node1.Annotations = map[string]string{"feature.node.kubernetes.io/baz": "a", "other.io/annotation": "b"}
+		node2 := newNode("node2")
+		node2.Annotations = map[string]string{
+			"foo.nfd.example.com/feature-labels":      "foo,example.io/bar",
+			"foo.nfd.example.com/feature-annotations": "baz",
+			"foo.nfd.example.com/extended-resources":  "qux",
+			"nfd.example.com/taints":                  "feature.node.kubernetes.io/taint=true:NoSchedule",
+			"foo.nfd.example.com/last-applied-time":   "2025-01-01T00:00:00Z",
+			"feature.node.kubernetes.io/baz":          "a",
+			"other.io/annotation":                     "b",
+		}
+		setNodes(gc, node1, node2)
+
+		cmName := gc.trackingNames().ConfigMapName("node1")
+		cm := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      cmName,
+				Namespace: "nfd",
+				Labels:    map[string]string{nodetracking.ConfigMapLabel: "true"},
+			},
+			Data: map[string]string{
+				"foo.nfd.example.com/feature-labels":      "foo,example.io/bar",
+				"foo.nfd.example.com/feature-annotations": "baz",
+				"foo.nfd.example.com/extended-resources":  "qux",
+				"nfd.example.com/taints":                  "feature.node.kubernetes.io/taint=true:NoSchedule",
+			},
+		}
+		_, err := gc.k8sClient.CoreV1().ConfigMaps("nfd").Create(context.TODO(), cm, metav1.CreateOptions{})
+		So(err, ShouldBeNil)
+
+		So(gc.startNodeInformer(), ShouldBeNil)
+		defer gc.Stop()
+
+		gc.args.PruneNodeLabels = true
+		gc.garbageCollect()
+
+		Convey("Labels (et al.) of both nodes should be removed", func() {
+			for _, name := range []string{"node1", "node2"} {
+				node, err := gc.k8sClient.CoreV1().Nodes().Get(context.TODO(), name, metav1.GetOptions{})
+				So(err, ShouldBeNil)
+				So(node.Labels, ShouldResemble, map[string]string{"kubernetes.io/hostname": name})
+				So(node.Annotations, ShouldResemble, map[string]string{"other.io/annotation": "b"})
+				So(node.Status.Capacity, ShouldResemble, corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")})
+				So(node.Status.Allocatable, ShouldBeEmpty)
+				So(node.Spec.Taints, ShouldResemble, []corev1.Taint{{Key: "other.io/taint", Effect: corev1.TaintEffectNoSchedule}})
+			}
+		})
+
+		Convey("The tracking ConfigMap should be deleted", func() {
+			_, err := gc.k8sClient.CoreV1().ConfigMaps("nfd").Get(context.TODO(), cmName, metav1.GetOptions{})
+			So(errors.IsNotFound(err), ShouldBeTrue)
+		})
+	})
 }
 
 func newMockGC(nodes, nrts []string) *mockGC {
 	// Create fake objects
 	objs := []runtime.Object{}
 	for _, name := range nodes {
-		objs = append(objs, createPartialObjectMetadata("v1", "Node", "", name))
+		node := createPartialObjectMetadata("v1", "Node", "", name)
+		node.UID = types.UID("uid-" + name)
+		objs = append(objs, node)
 	}
 	for _, name := range nrts {
 		objs = append(objs, createPartialObjectMetadata("topology.node.k8s.io/v1alpha2", "NodeResourceTopology", "", name))
@@ -0,0 +1,241 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nfdgarbagecollector
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+	controller "k8s.io/kubernetes/pkg/controller"
+	taintutils "k8s.io/kubernetes/pkg/util/taints"
+
+	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
+	"sigs.k8s.io/node-feature-discovery/pkg/nodetracking"
+	"sigs.k8s.io/node-feature-discovery/pkg/utils"
+)
+
+// trackingNames returns the naming of the tracking annotations and
+// ConfigMaps of the nfd-master instance whose labels (et al.) are pruned.
+func (n *nfdGarbageCollector) trackingNames() nodetracking.Names {
+	return nodetracking.Names{Instance: n.args.Instance, AnnotationNs: n.args.AnnotationNs}
+}
+
+// hasNodeFeatures returns true if a node carries tracking annotations of
+// nfd-master, i.e. labels, feature annotations, extended resources or taints
+// created by nfd-master.
+func hasNodeFeatures(node metav1.Object, names nodetracking.Names) bool {
+	annotations := node.GetAnnotations()
+	keys := names.TrackingKeys()
+	for old := range names.LegacyTrackingKeys() {
+		keys.Insert(old)
+	}
+	for k := range keys {
+		if _, ok := annotations[k]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// trackingConfigMaps returns the names of the tracking ConfigMaps of
+// nfd-master in the namespace of nfd-gc.
+func (n *nfdGarbageCollector) trackingConfigMaps() sets.Set[string] {
+	names := sets.New[string]()
+	cms, err := n.k8sClient.CoreV1().ConfigMaps(n.namespace).List(context.TODO(), metav1.ListOptions{LabelSelector: nodetracking.ConfigMapLabel})
+	if err != nil {
+		klog.ErrorS(err, "failed to list tracking ConfigMaps", "namespace", n.namespace)
+		return names
+	}
+	for _, cm := range cms.Items {
+		names.Insert(cm.Name)
+	}
+	return names
+}
+
+// loadTracking returns the tracking information of a node, read from the
+// node annotations and the tracking ConfigMap, if one exists. Tracking
+// annotations in the default annotation namespace are mapped to their names
+// in the configured annotation namespace.
+func (n *nfdGarbageCollector) loadTracking(node *corev1.Node) (map[string]string, *corev1.ConfigMap, error) {
+	names := n.trackingNames()
+	keys := names.TrackingKeys()
+	legacyKeys := names.LegacyTrackingKeys()
+
+	data := make(map[string]string)
+	merge := func(in map[string]string) {
+		for k := range keys {
+			if v, ok := in[k]; ok {
+				data[k] = v
+			}
+		}
+		for old, k := range legacyKeys {
+			if _, ok := in[k]; ok {
+				continue
+			}
+			if v, ok := in[old]; ok {
+				data[k] = v
+			}
+		}
+	}
+	merge(node.Annotations)
+
+	cm, err := n.k8sClient.CoreV1().ConfigMaps(n.namespace).Get(context.TODO(), names.ConfigMapName(node.Name), metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return data, nil, nil
+	} else if err != nil {
+		return nil, nil, err
+	}
+	merge(cm.Data)
+	return data, cm, nil
+}
+
+// pruneNode removes the labels, feature annotations, extended resources and
+// taints created by nfd-master from a node.
+func (n *nfdGarbageCollector) pruneNode(nodeName string) {
+	node, err := n.k8sClient.CoreV1().Nodes().Get(context.TODO(), nodeName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		klog.V(2).InfoS("node not found, omitting pruning", "nodeName", nodeName)
+		return
+	} else if err != nil {
+		klog.ErrorS(err, "failed to get node", "nodeName", nodeName)
+		nodePruneErrors.Inc()
+		return
+	}
+
+	tracking, cm, err := n.loadTracking(node)
+	if err != nil {
+		klog.ErrorS(err, "failed to get tracking ConfigMap of node", "nodeName", nodeName)
+		nodePruneErrors.Inc()
+		return
+	}
+
+	patches, statusPatches := nodePrunePatches(node, n.trackingNames(), tracking)
+
+	// Remove extended resources and taints first, the tracking information
+	// is needed for retrying on failure
+	if err := n.patchNode(nodeName, statusPatches, "status"); err != nil {
+		klog.ErrorS(err, "failed to remove extended resources from node", "nodeName", nodeName)
+		nodePruneErrors.Inc()
+		return
+	}
+	if newNode, ok := pruneTaints(node, tracking[n.trackingNames().TaintsAnnotation()]); ok {
+		if err := controller.PatchNodeTaints(context.TODO(), n.k8sClient, nodeName, node, newNode); err != nil {
+			klog.ErrorS(err, "failed to remove taints from node", "nodeName", nodeName)
+			nodePruneErrors.Inc()
+			return
+		}
+	}
+	if err := n.patchNode(nodeName, patches); err != nil {
+		klog.ErrorS(err, "failed to remove labels and annotations from node", "nodeName", nodeName)
+		nodePruneErrors.Inc()
+		return
+	}
+	if cm != nil {
+		err := n.k8sClient.CoreV1().ConfigMaps(cm.Namespace).Delete(context.TODO(), cm.Name, metav1.DeleteOptions{})
+		if err != nil && !errors.IsNotFound(err) {
+			klog.ErrorS(err, "failed to delete tracking ConfigMap", "configmap", klog.KObj(cm))
+			nodePruneErrors.Inc()
+			return
+		}
+	}
+	klog.InfoS("orphaned labels, annotations, extended resources and taints removed from node", "nodeName", nodeName)
+	nodesPruned.Inc()
+}
+
+func (n *nfdGarbageCollector) patchNode(nodeName string, patches []utils.JsonPatch, subresources ...string) error {
+	if len(patches) == 0 {
+		return nil
+	}
+	data, err := json.Marshal(patches)
+	if err == nil {
+		_, err = n.k8sClient.CoreV1().Nodes().Patch(context.TODO(), nodeName, types.JSONPatchType, data, metav1.PatchOptions{}, subresources...)
+	}
+	return err
+}
+
+// nodePrunePatches returns the JSON patches for removing the labels and
+// annotations (patches) and the extended resources (statusPatches) created by
+// nfd-master from a node, as recorded in the tracking information.
+func nodePrunePatches(node *corev1.Node, names nodetracking.Names, tracking map[string]string) (patches, statusPatches []utils.JsonPatch) {
+	for _, name := range nsNames(tracking[names.TrackingAnnotation(nfdv1alpha1.FeatureLabelsAnnotation)], nfdv1alpha1.FeatureLabelNs) {
+		if _, ok := node.Labels[name]; ok {
+			patches = append(patches, utils.NewJsonPatch("remove", "/metadata/labels", name, ""))
+		}
+	}
+
+	annotations := names.NodeAnnotations()
+	annotations.Insert(nsNames(tracking[names.TrackingAnnotation(nfdv1alpha1.FeatureAnnotationsTrackingAnnotation)], nfdv1alpha1.FeatureAnnotationNs)...)
+	for _, name := range sets.List(annotations) {
+		if _, ok := node.Annotations[name]; ok {
+			patches = append(patches, utils.NewJsonPatch("remove", "/metadata/annotations", name, ""))
+		}
+	}
+
+	for _, name := range nsNames(tracking[names.TrackingAnnotation(nfdv1alpha1.ExtendedResourceAnnotation)], nfdv1alpha1.FeatureLabelNs) {
+		if _, ok := node.Status.Capacity[corev1.ResourceName(name)]; ok {
+			statusPatches = append(statusPatches, utils.NewJsonPatch("remove", "/status/capacity", name, ""))
+		}
+		if _, ok := node.Status.Allocatable[corev1.ResourceName(name)]; ok {
+			statusPatches = append(statusPatches, utils.NewJsonPatch("remove", "/status/allocatable", name, ""))
+		}
+	}
+	return patches, statusPatches
+}
+
+// pruneTaints returns a copy of the node with the taints listed in the
+// tracking annotation of NFD-managed taints removed. Returns false if no
+// taints were removed.
+func pruneTaints(node *corev1.Node, trackedTaints string) (*corev1.Node, bool) {
+	if trackedTaints == "" {
+		return nil, false
+	}
+	taints, _, err := taintutils.ParseTaints(strings.Split(trackedTaints, ","))
+	if err != nil {
+		klog.ErrorS(err, "failed to parse the taints annotation, not removing taints", "nodeName", node.Name)
+		return nil, false
+	}
+	newNode := node.DeepCopy()
+	removed := false
+	for _, taint := range taints {
+		var ok bool
+		newNode.Spec.Taints, ok = taintutils.DeleteTaint(newNode.Spec.Taints, &taint)
+		removed = removed || ok
+	}
+	return newNode, removed
+}
+
+// nsNames splits a comma-separated list of names, adding the default
+// namespace to names without one.
+func nsNames(list, defaultNs string) []string {
+	if list == "" {
+		return nil
+	}
+	names := strings.Split(list, ",")
+	for i, name := range names {
+		if !strings.Contains(name, "/") {
+			names[i] = defaultNs + "/" + name
+		}
+	}
+	return names
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nfdmaster
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+
+	nfdfeatures "sigs.k8s.io/node-feature-discovery/pkg/features"
+	"sigs.k8s.io/node-feature-discovery/pkg/utils/featuregate"
+)
+
+// hotReloadableFeatureGates are the feature gates that can be changed in the
+// configuration file without restarting nfd-master. They only affect the
+// processing of the node features, so re-evaluating all nodes is enough for
+// the change to take effect.
+var hotReloadableFeatureGates = sets.New(
+	nfdfeatures.DisableAutoPrefix,
+)
+
+// configReloadDelay is the time to wait for the configuration file changes to
+// settle before reloading it.
+const configReloadDelay = time.Second
+
+// configureFeatureGates applies the feature gates from the configuration file.
+// Feature gates specified on the command line take precedence and feature
+// gates not specified anywhere revert to their default value. On reload, only
+// the hot-reloadable feature gates are changed. Returns true if any feature
+// gate was changed.
+func (m *nfdMaster) configureFeatureGates(gates map[string]bool, reload bool) (bool, error) {
+	known := nfdfeatures.NFDMutableFeatureGate.GetAll()
+	for name := range gates {
+		if _, ok := known[featuregate.Feature(name)]; !ok {
+			return false, fmt.Errorf("unrecognized feature gate %q in featureGates", name)
+		}
+	}
+
+	changes := make(map[string]bool)
+	for name, spec := range known {
+		v, ok := m.args.FeatureGates[string(name)]
+		if !ok {
+			v, ok = gates[string(name)]
+		}
+		if !ok {
+			v = spec.Default
+		}
+		if v == nfdfeatures.NFDFeatureGate.Enabled(name) {
+			continue
+		}
+		if reload && !hotReloadableFeatureGates.Has(name) {
+			klog.InfoS("changing the feature gate requires a restart of nfd-master, ignoring", "featureGate", name, "value", v)
+			continue
+		}
+		changes[string(name)] = v
+	}
+	if len(changes) == 0 {
+		return false, nil
+	}
+
+	if err := nfdfeatures.NFDMutableFeatureGate.SetFromMap(changes); err != nil {
+		return false, err
+	}
+	klog.InfoS("feature gates updated", "featureGates", changes)
+	return true, nil
+}
+
+// reloadConfig re-reads the configuration file and applies changes in the
+// hot-reloadable feature gates. All nodes are re-evaluated if any of them
+// changed.
+func (m *nfdMaster) reloadConfig() {
+	c, err := m.parseConfig(m.configFilePath, m.args.Options)
+	if err != nil {
+		klog.ErrorS(err, "failed to reload configuration, keeping the old configuration")
+		return
+	}
+
+	changed, err := m.configureFeatureGates(c.FeatureGates, true)
+	if err != nil {
+		klog.ErrorS(err, "failed to update feature gates, keeping the old configuration")
+		return
+	}
+	if changed && m.nfdController != nil {
+		m.nfdController.updateAllNodes()
+	}
+}
+
+// watchConfig watches for changes in the configuration file and reloads the
+// configuration when the file changes. The parent directory is watched in
+// order to catch updates of ConfigMap volumes, which replace the file by
+// swapping a symlink.
+func (m *nfdMaster) watchConfig() error {
+	dir := filepath.Dir(m.configFilePath)
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		klog.InfoS("configuration directory not found, not watching for configuration changes", "path", dir)
+		return nil
+	}
+
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	if err := w.Add(dir); err != nil {
+		w.Close()
+		return fmt.Errorf("failed to watch %q: %w", dir, err)
+	}
+
+	go func() {
+		defer w.Close()
+
+		var reload <-chan time.Time
+		for {
+			select {
+			case e := <-w.Events:
+				klog.V(5).InfoS("fsnotify event received", "filename", e.Name, "op", e.Op)
+				reload = time.After(configReloadDelay)
+			case err := <-w.Errors:
+				klog.ErrorS(err, "config file watcher error")
+			case <-reload:
+				klog.InfoS("configuration file changed, reloading", "path", m.configFilePath)
+				m.reloadConfig()
+				reload = nil
+			case <-m.stop:
+				return
+			}
+		}
+	}()
+	return nil
+}
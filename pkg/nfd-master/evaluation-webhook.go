@@ -0,0 +1,205 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nfdmaster
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"os"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
+	nfdfeatures "sigs.k8s.io/node-feature-discovery/pkg/features"
+	"sigs.k8s.io/node-feature-discovery/pkg/utils"
+)
+
+const (
+	// EvaluationWebhookFailurePolicyIgnore causes errors from the evaluation
+	// webhook to be logged and ignored.
+	EvaluationWebhookFailurePolicyIgnore = "Ignore"
+	// EvaluationWebhookFailurePolicyFail causes the node update to fail (and
+	// be retried) if calling the evaluation webhook fails.
+	EvaluationWebhookFailurePolicyFail = "Fail"
+
+	// maxEvaluationResponseSize is the maximum size of a response of the
+	// evaluation webhook.
+	maxEvaluationResponseSize = 4 * 1024 * 1024
+)
+
+// EvaluationWebhookConfig contains the configuration of the external feature
+// evaluation webhook.
+type EvaluationWebhookConfig struct {
+	URL           string
+	CAFile        string
+	Timeout       utils.DurationVal
+	FailurePolicy string
+}
+
+// EvaluationRequest is the payload sent to the evaluation webhook.
+type EvaluationRequest struct {
+	// NodeName is the name of the node being evaluated.
+	NodeName string `json:"nodeName"`
+	// Features is the merged set of features of the node.
+	Features *nfdv1alpha1.Features `json:"features"`
+	// Labels contains the labels requested by the NodeFeature objects and
+	// the NodeFeatureRules of the node.
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// EvaluationResponse is the payload expected from the evaluation webhook.
+type EvaluationResponse struct {
+	Labels            map[string]string `json:"labels,omitempty"`
+	Annotations       map[string]string `json:"annotations,omitempty"`
+	ExtendedResources map[string]string `json:"extendedResources,omitempty"`
+	Taints            []corev1.Taint    `json:"taints,omitempty"`
+}
+
+type evaluationWebhook struct {
+	url           string
+	failurePolicy string
+	client        *http.Client
+}
+
+func newEvaluationWebhook(c EvaluationWebhookConfig) (*evaluationWebhook, error) {
+	switch c.FailurePolicy {
+	case "":
+		c.FailurePolicy = EvaluationWebhookFailurePolicyIgnore
+	case EvaluationWebhookFailurePolicyIgnore, EvaluationWebhookFailurePolicyFail:
+	default:
+		return nil, fmt.Errorf("invalid evaluationWebhook.failurePolicy %q", c.FailurePolicy)
+	}
+
+	transport, err := newHTTPTransport(c.CAFile)
+	if err != nil {
+		return nil, fmt.Errorf("invalid evaluationWebhook.caFile: %w", err)
+	}
+
+	timeout := c.Timeout.Duration
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+
+	return &evaluationWebhook{
+		url:           c.URL,
+		failurePolicy: c.FailurePolicy,
+		client:        &http.Client{Transport: transport, Timeout: timeout},
+	}, nil
+}
+
+// newHTTPTransport returns an HTTP transport trusting the CA certificates in
+// caFile. The system CAs are used if caFile is empty.
+func newHTTPTransport(caFile string) (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if caFile != "" {
+		caData, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caData) {
+			return nil, fmt.Errorf("no valid certificates found in %q", caFile)
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	}
+	return transport, nil
+}
+
+// evaluate sends the features of a node to the webhook and returns the
+// outputs computed by it.
+func (w *evaluationWebhook) evaluate(ctx context.Context, nodeName string, features *nfdv1alpha1.Features, labels map[string]string) (*EvaluationResponse, error) {
+	data, err := json.Marshal(EvaluationRequest{NodeName: nodeName, Features: features, Labels: labels})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("evaluation webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxEvaluationResponseSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read evaluation webhook response: %w", err)
+	}
+	if len(body) > maxEvaluationResponseSize {
+		return nil, fmt.Errorf("evaluation webhook response exceeds the maximum size of %d bytes", maxEvaluationResponseSize)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("evaluation webhook returned %s: %s", resp.Status, string(body))
+	}
+
+	out := &EvaluationResponse{}
+	if err := json.Unmarshal(body, out); err != nil {
+		return nil, fmt.Errorf("failed to parse evaluation webhook response: %w", err)
+	}
+	return out, nil
+}
+
+// processEvaluationWebhook calls the evaluation webhook for a node. A nil
+// response without an error is returned if the request failed but the
+// failure policy allows ignoring it.
+func (m *nfdMaster) processEvaluationWebhook(ctx context.Context, nodeName string, features *nfdv1alpha1.Features, nfLabels, ruleLabels map[string]string) (*EvaluationResponse, error) {
+	reqLabels := maps.Clone(nfLabels)
+	if reqLabels == nil {
+		reqLabels = make(map[string]string, len(ruleLabels))
+	}
+	maps.Copy(reqLabels, ruleLabels)
+
+	out, err := m.evaluationWebhook.evaluate(ctx, nodeName, features, reqLabels)
+	if err != nil {
+		evaluationWebhookErrors.Inc()
+		if m.evaluationWebhook.failurePolicy == EvaluationWebhookFailurePolicyFail {
+			return nil, fmt.Errorf("failed to evaluate features of node %q: %w", nodeName, err)
+		}
+		klog.ErrorS(err, "ignoring failed evaluation webhook request", "nodeName", nodeName)
+		return nil, nil
+	}
+	klog.V(4).InfoS("evaluation webhook response received", "nodeName", nodeName, "response", utils.DelayedDumper(out))
+
+	if !nfdfeatures.NFDFeatureGate.Enabled(nfdfeatures.DisableAutoPrefix) && m.config.AutoDefaultNs {
+		out.Labels = addNsToMapKeys(out.Labels, nfdv1alpha1.FeatureLabelNs)
+		out.ExtendedResources = addNsToMapKeys(out.ExtendedResources, nfdv1alpha1.ExtendedResourceNs)
+		out.Annotations = addNsToMapKeys(out.Annotations, nfdv1alpha1.FeatureAnnotationNs)
+	}
+	return out, nil
+}
+
+// mergeOutputs copies src into dst, allocating dst if needed.
+func mergeOutputs[M ~map[string]string](dst M, src map[string]string) M {
+	if dst == nil {
+		dst = make(M, len(src))
+	}
+	maps.Copy(dst, src)
+	return dst
+}
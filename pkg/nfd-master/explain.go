@@ -0,0 +1,297 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nfdmaster
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/klog/v2"
+
+	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
+	"sigs.k8s.io/node-feature-discovery/pkg/apis/nfd/nodefeaturerule"
+)
+
+// ExplainPath is the debug endpoint explaining how the NFD-managed labels,
+// annotations, extended resources and taints of a node are formed. The node
+// is specified with the "node" query parameter. The optional "label" query
+// parameter limits the explanation to one label.
+const ExplainPath = "/debug/explain"
+
+// Rule evaluation results reported in RuleExplanation.
+const (
+	RuleMatched    = "Matched"
+	RuleNotMatched = "NotMatched"
+	RuleDisabled   = "Disabled"
+	RuleSuspended  = "Suspended"
+	RuleError      = "Error"
+)
+
+// Explanation describes how the NFD-managed labels, annotations, extended
+// resources and taints of a node are formed from its NodeFeature objects and
+// the rules of the cluster, as evaluated by nfd-master with its live
+// configuration.
+type Explanation struct {
+	Node string `json:"node"`
+	// SkipReason is set if nfd-master does not update the node, e.g.
+	// because of the node selectors or node maintenance.
+	SkipReason string `json:"skipReason,omitempty"`
+	// NodeFeatures are the NodeFeature objects of the node, in the order of
+	// merging.
+	NodeFeatures []string `json:"nodeFeatures"`
+	// IgnoredNodeFeatures are the NodeFeature objects of the node that were
+	// ignored, mapped to the reason.
+	IgnoredNodeFeatures map[string]string `json:"ignoredNodeFeatures,omitempty"`
+	// Rules are the evaluation results of the rules of NodeFeatureRule
+	// objects (including rule bundles) and NamespacedNodeFeatureRule
+	// objects, in the order of evaluation.
+	Rules []RuleExplanation `json:"rules"`
+	// EvaluationWebhook is the response of the evaluation webhook, if
+	// configured.
+	EvaluationWebhook *EvaluationResponse `json:"evaluationWebhook,omitempty"`
+	// Labels et al. are the outputs applied on the node after filtering by
+	// the nfd-master configuration. Label conflict detection and NoExecute
+	// taint protection are not taken into account.
+	Labels            map[string]string `json:"labels,omitempty"`
+	Annotations       map[string]string `json:"annotations,omitempty"`
+	ExtendedResources map[string]string `json:"extendedResources,omitempty"`
+	Taints            []corev1.Taint    `json:"taints,omitempty"`
+	// Rejected are the outputs dropped by the nfd-master configuration.
+	Rejected *RejectedItems `json:"rejected,omitempty"`
+	// NodeLabels are the NFD-managed labels currently on the node.
+	NodeLabels map[string]string `json:"nodeLabels,omitempty"`
+
+	// label limits the explanation to the rules related to one label
+	label string
+}
+
+// RuleExplanation is the result of evaluating one rule.
+type RuleExplanation struct {
+	// Object is the name of the rule object, prefixed with the namespace
+	// for NamespacedNodeFeatureRule objects.
+	Object string `json:"object"`
+	Rule   string `json:"rule"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+	// UnmatchedTerms are the feature matcher terms that did not match the
+	// features of the node.
+	UnmatchedTerms    []nfdv1alpha1.FeatureMatcherTerm `json:"unmatchedTerms,omitempty"`
+	Labels            map[string]string                `json:"labels,omitempty"`
+	Annotations       map[string]string                `json:"annotations,omitempty"`
+	ExtendedResources map[string]string                `json:"extendedResources,omitempty"`
+	Taints            []corev1.Taint                   `json:"taints,omitempty"`
+	Vars              map[string]string                `json:"vars,omitempty"`
+}
+
+func (e *Explanation) addNodeFeature(obj *nfdv1alpha1.NodeFeature) {
+	if e != nil {
+		e.NodeFeatures = append(e.NodeFeatures, klog.KObj(obj).String())
+	}
+}
+
+func (e *Explanation) ignoreNodeFeature(obj *nfdv1alpha1.NodeFeature, reason string) {
+	if e == nil {
+		return
+	}
+	if e.IgnoredNodeFeatures == nil {
+		e.IgnoredNodeFeatures = make(map[string]string)
+	}
+	e.IgnoredNodeFeatures[klog.KObj(obj).String()] = reason
+}
+
+func (e *Explanation) addRule(r RuleExplanation, rule *nfdv1alpha1.Rule) {
+	if e != nil && (e.label == "" || isRelatedRule(&r, rule, e.label)) {
+		e.Rules = append(e.Rules, r)
+	}
+}
+
+// isRelatedRule returns true if a rule created a label or could create it,
+// i.e. the label is specified in the rule or the rule uses a template for
+// creating labels.
+func isRelatedRule(r *RuleExplanation, rule *nfdv1alpha1.Rule, label string) bool {
+	if _, ok := r.Labels[label]; ok {
+		return true
+	}
+	for _, l := range []map[string]string{rule.Labels, rule.ElseLabels} {
+		for k := range l {
+			if k == label || addNs(k, nfdv1alpha1.FeatureLabelNs) == label {
+				return true
+			}
+		}
+	}
+	return rule.LabelsTemplate != ""
+}
+
+// filterLabel limits the explanation to one label.
+func (e *Explanation) filterLabel() {
+	filter := func(in map[string]string) map[string]string {
+		if v, ok := in[e.label]; ok {
+			return map[string]string{e.label: v}
+		}
+		return nil
+	}
+	e.Labels = filter(e.Labels)
+	e.NodeLabels = filter(e.NodeLabels)
+	e.Annotations = nil
+	e.ExtendedResources = nil
+	e.Taints = nil
+	if e.Rejected != nil {
+		rejected := &RejectedItems{}
+		for _, k := range e.Rejected.Labels.keys() {
+			if k.Key == e.label {
+				rejected.addLabel(k.Key, k.Reason)
+			}
+		}
+		e.Rejected = nil
+		if rejected.Labels != nil {
+			e.Rejected = rejected
+		}
+	}
+	if e.EvaluationWebhook != nil {
+		e.EvaluationWebhook = &EvaluationResponse{Labels: filter(e.EvaluationWebhook.Labels)}
+	}
+}
+
+// addRuleResult records the outputs of an evaluated rule. Labels,
+// annotations and extended resources are the outputs with the default
+// namespaces added, if enabled.
+func (e *Explanation) addRuleResult(obj klog.KMetadata, rule *nfdv1alpha1.Rule, out nodefeaturerule.RuleOutput, labels, annotations, extendedResources map[string]string, features *nfdv1alpha1.Features) {
+	r := RuleExplanation{
+		Object:            klog.KObj(obj).String(),
+		Rule:              rule.Name,
+		Status:            RuleMatched,
+		Labels:            labels,
+		Annotations:       annotations,
+		ExtendedResources: extendedResources,
+		Taints:            out.Taints,
+		Vars:              out.Vars,
+	}
+	if out.MatchStatus == nil || !out.MatchStatus.IsMatch {
+		r.Status = RuleNotMatched
+		r.UnmatchedTerms = unmatchedTerms(rule, features)
+	}
+	e.addRule(r, rule)
+}
+
+// unmatchedTerms returns the feature matcher terms of a rule that do not
+// match, evaluating each term separately.
+func unmatchedTerms(rule *nfdv1alpha1.Rule, features *nfdv1alpha1.Features) []nfdv1alpha1.FeatureMatcherTerm {
+	terms := append([]nfdv1alpha1.FeatureMatcherTerm{}, rule.MatchFeatures...)
+	for _, m := range rule.MatchAny {
+		terms = append(terms, m.MatchFeatures...)
+	}
+
+	var unmatched []nfdv1alpha1.FeatureMatcherTerm
+	for _, term := range terms {
+		r := nfdv1alpha1.Rule{MatchFeatures: nfdv1alpha1.FeatureMatcher{term}}
+		if out, err := nodefeaturerule.Execute(&r, features, true); err != nil || out.MatchStatus == nil || !out.MatchStatus.IsMatch {
+			unmatched = append(unmatched, term)
+		}
+	}
+	return unmatched
+}
+
+// explainNode evaluates a node the same way as in a node update, without
+// modifying the node, and returns the explanation of the results. If label is
+// non-empty the explanation is limited to that label.
+func (m *nfdMaster) explainNode(node *corev1.Node, label string) (*Explanation, error) {
+	e := &Explanation{Node: node.Name, label: label}
+	if label != "" {
+		defer e.filterLabel()
+	}
+
+	view, _, err := m.loadNodeTracking(m.k8sClient, node)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range stringToNsNames(view.Annotations[m.trackingAnnotation(nfdv1alpha1.FeatureLabelsAnnotation)], nfdv1alpha1.FeatureLabelNs) {
+		if v, ok := node.Labels[name]; ok {
+			if e.NodeLabels == nil {
+				e.NodeLabels = make(map[string]string)
+			}
+			e.NodeLabels[name] = v
+		}
+	}
+
+	if reason, ok := nodeUnderMaintenance(node, m.config.NodeMaintenance); ok {
+		e.SkipReason = "node is under maintenance: " + reason
+		return e, nil
+	}
+	if !m.isNodeManaged(node) {
+		e.SkipReason = "node is excluded by the node selectors"
+		return e, nil
+	}
+	if m.config.NoPublish {
+		e.SkipReason = "publishing is disabled (noPublish=true)"
+	}
+
+	rejected := &RejectedItems{}
+	nodeFeatures, err := m.getAndMergeNodeFeatures(node.Name, rejected, e)
+	if err != nil {
+		return nil, err
+	}
+	out, err := m.evaluateNode(context.Background(), node, &nodeFeatures.Spec, rejected, e)
+	if err != nil {
+		return nil, err
+	}
+	e.Labels = out.labels
+	e.Annotations = out.annotations
+	e.ExtendedResources = out.extendedResources
+	e.Taints = out.taints
+	if rejected.Labels != nil || rejected.Annotations != nil || rejected.ExtendedResources != nil || rejected.Taints != nil {
+		e.Rejected = rejected
+	}
+	return e, nil
+}
+
+// explainHandler serves explanations of nodes in JSON format.
+func (m *nfdMaster) explainHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		nodeName := r.URL.Query().Get("node")
+		if nodeName == "" {
+			http.Error(w, "node not specified", http.StatusBadRequest)
+			return
+		}
+		if m.nfdController == nil || m.nfdController.featureLister == nil {
+			http.Error(w, "NFD API controller is not running", http.StatusServiceUnavailable)
+			return
+		}
+
+		node, err := getNode(m.k8sClient, nodeName)
+		if apierrors.IsNotFound(err) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		} else if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		e, err := m.explainNode(node, r.URL.Query().Get("label"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(e); err != nil {
+			klog.ErrorS(err, "failed to write explain response")
+		}
+	})
+}
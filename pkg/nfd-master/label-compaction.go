@@ -0,0 +1,53 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nfdmaster
+
+import (
+	"encoding/json"
+	"slices"
+)
+
+// compactLabels folds the boolean labels selected by the configuration into
+// a single annotation value. The annotation value is a JSON object mapping
+// label namespaces to sorted lists of label names (without the namespace).
+// Returns the remaining labels and the annotation value, or an empty value if
+// no labels were folded.
+func compactLabels(labels Labels, config LabelCompactionConfig) (Labels, string, error) {
+	out := make(Labels, len(labels))
+	compacted := make(map[string][]string)
+	for name, value := range labels {
+		if value != "true" || !config.Labels.MatchString(name) ||
+			(config.KeepLabels != nil && config.KeepLabels.MatchString(name)) {
+			out[name] = value
+			continue
+		}
+		ns, base := splitNs(name)
+		compacted[ns] = append(compacted[ns], base)
+	}
+	if len(compacted) == 0 {
+		return labels, "", nil
+	}
+	for _, names := range compacted {
+		slices.Sort(names)
+	}
+
+	data, err := json.Marshal(compacted)
+	if err != nil {
+		return nil, "", err
+	}
+	return out, string(data), nil
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nfdmaster
+
+import (
+	"regexp"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestCompactLabels(t *testing.T) {
+	Convey("When compacting labels", t, func() {
+		labels := Labels{
+			"feature.node.kubernetes.io/cpu-cpuid.AVX":     "true",
+			"feature.node.kubernetes.io/cpu-cpuid.AVX2":    "true",
+			"feature.node.kubernetes.io/cpu-cpuid.AVX512F": "true",
+			"feature.node.kubernetes.io/cpu-model.family":  "6",
+			"example.com/cpu-cpuid.FOO":                    "true",
+			"feature.node.kubernetes.io/cpu-cpuid.BAR":     "false",
+		}
+		config := LabelCompactionConfig{
+			Labels:     regexp.MustCompile(`/cpu-cpuid\.`),
+			KeepLabels: regexp.MustCompile(`AVX512F$`),
+		}
+
+		Convey("selected boolean labels should be folded into the annotation", func() {
+			out, value, err := compactLabels(labels, config)
+			So(err, ShouldBeNil)
+			So(out, ShouldResemble, Labels{
+				"feature.node.kubernetes.io/cpu-cpuid.AVX512F": "true",
+				"feature.node.kubernetes.io/cpu-model.family":  "6",
+				"feature.node.kubernetes.io/cpu-cpuid.BAR":     "false",
+			})
+			So(value, ShouldEqual, `{"example.com":["cpu-cpuid.FOO"],"feature.node.kubernetes.io":["cpu-cpuid.AVX","cpu-cpuid.AVX2"]}`)
+		})
+
+		Convey("labels should be unchanged if nothing matches", func() {
+			config.Labels = regexp.MustCompile(`^nothing$`)
+			out, value, err := compactLabels(labels, config)
+			So(err, ShouldBeNil)
+			So(out, ShouldResemble, labels)
+			So(value, ShouldBeEmpty)
+		})
+	})
+
+	Convey("When parsing label compaction configuration", t, func() {
+		master := newFakeMaster()
+		So(master.configure("", `{"labelCompaction": {"labels": "cpu-cpuid\\.", "keepLabels": "AVX512"}}`), ShouldBeNil)
+		So(master.config.LabelCompaction.Labels.String(), ShouldEqual, `cpu-cpuid\.`)
+		So(master.config.LabelCompaction.KeepLabels.String(), ShouldEqual, "AVX512")
+
+		So(master.configure("", `{"labelCompaction": {"labels": "("}}`), ShouldNotBeNil)
+	})
+}
@@ -0,0 +1,197 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nfdmaster
+
+import (
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+
+	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
+	"sigs.k8s.io/node-feature-discovery/pkg/utils"
+)
+
+// labelConflictTracker detects NFD-managed node labels that are repeatedly
+// changed or removed by someone else, e.g. another controller fighting with
+// nfd-master over the same label.
+type labelConflictTracker struct {
+	sync.Mutex
+	nodes map[string]*nodeLabelConflicts
+}
+
+// nodeLabelConflicts contains the label conflict state of a node.
+type nodeLabelConflicts struct {
+	// applied contains the labels last applied by nfd-master
+	applied map[string]string
+	// reverts contains the times the labels were found changed by others
+	reverts map[string][]time.Time
+	// backoff contains the times until which conflicting labels are not
+	// updated by nfd-master
+	backoff map[string]time.Time
+	// conflicts contains the labels currently reported as conflicting
+	conflicts sets.Set[string]
+}
+
+func newLabelConflictTracker() *labelConflictTracker {
+	return &labelConflictTracker{nodes: make(map[string]*nodeLabelConflicts)}
+}
+
+// remove drops the state of a node.
+func (t *labelConflictTracker) remove(nodeName string) {
+	t.Lock()
+	defer t.Unlock()
+	delete(t.nodes, nodeName)
+}
+
+// check detects the labels that have been changed on the node by others since
+// nfd-master last applied them. A label is conflicting if it has been changed
+// at least config.Threshold times within config.Window. It returns the labels
+// to apply on the node, with conflicting labels in backoff left untouched, and
+// the sorted list of conflicting labels.
+func (t *labelConflictTracker) check(node *corev1.Node, labels Labels, config LabelConflictsConfig, now time.Time) (Labels, []string) {
+	if config.Threshold <= 0 {
+		return labels, nil
+	}
+
+	t.Lock()
+	defer t.Unlock()
+
+	s, ok := t.nodes[node.Name]
+	if !ok {
+		s = &nodeLabelConflicts{
+			reverts:   make(map[string][]time.Time),
+			backoff:   make(map[string]time.Time),
+			conflicts: sets.New[string](),
+		}
+		t.nodes[node.Name] = s
+	}
+
+	for key, val := range s.applied {
+		if until, ok := s.backoff[key]; ok && now.Before(until) {
+			continue
+		}
+		if cur, ok := node.Labels[key]; !ok || cur != val {
+			klog.V(2).InfoS("NFD-managed label changed by someone else", "nodeName", node.Name, "label", key, "expected", val, "actual", cur)
+			s.reverts[key] = append(s.reverts[key], now)
+		}
+	}
+
+	cutoff := now.Add(-config.Window.Duration)
+	conflicts := sets.New[string]()
+	for key, times := range s.reverts {
+		times = slices.DeleteFunc(times, func(t time.Time) bool { return t.Before(cutoff) })
+		if _, ok := labels[key]; !ok || len(times) == 0 {
+			delete(s.reverts, key)
+			continue
+		}
+		s.reverts[key] = times
+		if len(times) < config.Threshold {
+			continue
+		}
+		conflicts.Insert(key)
+		if config.Backoff.Duration > 0 {
+			klog.InfoS("backing off updates of conflicting label", "nodeName", node.Name, "label", key, "backoff", config.Backoff.Duration)
+			s.backoff[key] = now.Add(config.Backoff.Duration)
+			delete(s.reverts, key)
+		}
+	}
+	for key, until := range s.backoff {
+		if _, ok := labels[key]; !ok || !now.Before(until) {
+			delete(s.backoff, key)
+			continue
+		}
+		conflicts.Insert(key)
+	}
+
+	for key := range conflicts.Difference(s.conflicts) {
+		klog.InfoS("NFD-managed label is repeatedly changed by someone else", "nodeName", node.Name, "label", key)
+		nodeLabelConflictsDetected.Inc()
+	}
+	s.conflicts = conflicts
+
+	// Leave the labels in backoff as they are on the node
+	if len(s.backoff) > 0 {
+		labels = maps.Clone(labels)
+		for key := range s.backoff {
+			if val, ok := node.Labels[key]; ok {
+				labels[key] = val
+			} else {
+				delete(labels, key)
+			}
+		}
+	}
+
+	return labels, sets.List(conflicts)
+}
+
+// setApplied records the labels applied on the node by nfd-master.
+func (t *labelConflictTracker) setApplied(nodeName string, applied map[string]string) {
+	t.Lock()
+	defer t.Unlock()
+	if s, ok := t.nodes[nodeName]; ok {
+		s.applied = applied
+	}
+}
+
+// appliedLabels returns the labels of the node managed by nfd-master after
+// applying the given patches.
+func appliedLabels(node *corev1.Node, labels Labels, patches []utils.JsonPatch) map[string]string {
+	result := maps.Clone(node.Labels)
+	if result == nil {
+		result = make(map[string]string)
+	}
+	prefix := "/metadata/labels/"
+	for _, p := range patches {
+		if !strings.HasPrefix(p.Path, prefix) {
+			continue
+		}
+		key := utils.UnescapeJsonPointer(strings.TrimPrefix(p.Path, prefix))
+		if p.Op == "remove" {
+			delete(result, key)
+		} else {
+			result[key] = p.Value
+		}
+	}
+
+	applied := make(map[string]string, len(labels))
+	for key, val := range labels {
+		// Labels not overwritten by nfd-master are not managed by it
+		if result[key] == val {
+			applied[key] = val
+		}
+	}
+	return applied
+}
+
+// labelConflictsAnnotationValue returns the value of the annotation listing
+// the conflicting labels of a node.
+func labelConflictsAnnotationValue(conflicts []string) string {
+	keys := make([]string, 0, len(conflicts))
+	for _, key := range conflicts {
+		// Drop the ns part for labels in the default ns
+		keys = append(keys, strings.TrimPrefix(key, nfdv1alpha1.FeatureLabelNs+"/"))
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, ",")
+}
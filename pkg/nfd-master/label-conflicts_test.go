@@ -0,0 +1,137 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nfdmaster
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	. "github.com/smartystreets/goconvey/convey"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakeclient "k8s.io/client-go/kubernetes/fake"
+
+	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
+	"sigs.k8s.io/node-feature-discovery/pkg/utils"
+)
+
+func TestLabelConflictTracker(t *testing.T) {
+	Convey("When tracking label conflicts", t, func() {
+		tracker := newLabelConflictTracker()
+		config := LabelConflictsConfig{Threshold: 2, Window: utils.DurationVal{Duration: time.Minute}}
+		labels := Labels{"feature.node.kubernetes.io/foo": "true", "example.com/bar": "1"}
+		node := newTestNode()
+		now := time.Now()
+
+		// revert simulates someone else changing a label after nfd-master
+		// has applied the labels
+		revert := func(value string, now time.Time) (Labels, []string) {
+			node.Labels = map[string]string{"feature.node.kubernetes.io/foo": value, "example.com/bar": "1"}
+			ret, conflicts := tracker.check(node, labels, config, now)
+			tracker.setApplied(node.Name, appliedLabels(&corev1.Node{ObjectMeta: metav1.ObjectMeta{Labels: ret}}, ret, nil))
+			return ret, conflicts
+		}
+		_, conflicts := revert("true", now)
+		So(conflicts, ShouldBeEmpty)
+
+		Convey("labels changed repeatedly within the window should be conflicting", func() {
+			_, conflicts := revert("false", now.Add(10*time.Second))
+			So(conflicts, ShouldBeEmpty)
+			_, conflicts = revert("false", now.Add(20*time.Second))
+			So(conflicts, ShouldResemble, []string{"feature.node.kubernetes.io/foo"})
+			So(labelConflictsAnnotationValue(conflicts), ShouldEqual, "foo")
+
+			Convey("the conflict should clear when the changes stop", func() {
+				_, conflicts := revert("true", now.Add(2*time.Minute))
+				So(conflicts, ShouldBeEmpty)
+			})
+		})
+
+		Convey("changes outside the window should not be conflicting", func() {
+			_, conflicts := revert("false", now.Add(10*time.Second))
+			So(conflicts, ShouldBeEmpty)
+			_, conflicts = revert("false", now.Add(2*time.Minute))
+			So(conflicts, ShouldBeEmpty)
+		})
+
+		Convey("conflicting labels should be left untouched during backoff", func() {
+			config.Backoff = utils.DurationVal{Duration: 5 * time.Minute}
+			revert("false", now.Add(10*time.Second))
+			ret, conflicts := revert("false", now.Add(20*time.Second))
+			So(conflicts, ShouldResemble, []string{"feature.node.kubernetes.io/foo"})
+			So(ret, ShouldResemble, Labels{"feature.node.kubernetes.io/foo": "false", "example.com/bar": "1"})
+
+			ret, conflicts = revert("other", now.Add(time.Minute))
+			So(conflicts, ShouldResemble, []string{"feature.node.kubernetes.io/foo"})
+			So(ret["feature.node.kubernetes.io/foo"], ShouldEqual, "other")
+
+			Convey("labels should be updated again after the backoff", func() {
+				ret, conflicts := revert("other", now.Add(6*time.Minute))
+				So(conflicts, ShouldBeEmpty)
+				So(ret, ShouldResemble, labels)
+			})
+		})
+
+		Convey("nothing should be detected if disabled", func() {
+			config.Threshold = 0
+			revert("false", now.Add(10*time.Second))
+			_, conflicts := revert("false", now.Add(20*time.Second))
+			So(conflicts, ShouldBeEmpty)
+		})
+	})
+}
+
+func TestLabelConflicts(t *testing.T) {
+	Convey("When another party repeatedly changes an NFD-managed label", t, func() {
+		testNode := newTestNode()
+		testNode.Labels["kubernetes.io/hostname"] = testNodeName
+		testNode.Annotations["example.com/foo"] = "bar"
+		fakeCli := fakeclient.NewSimpleClientset(testNode)
+		fakeMaster := newFakeMaster(WithKubernetesClient(fakeCli))
+		fakeMaster.config.LabelConflicts = LabelConflictsConfig{Threshold: 2, Window: utils.DurationVal{Duration: time.Minute}}
+		labels := Labels{nfdv1alpha1.FeatureLabelNs + "/foo": "true"}
+		conflicts := testutil.ToFloat64(nodeLabelConflictsDetected)
+
+		getNode := func() *corev1.Node {
+			node, err := fakeCli.CoreV1().Nodes().Get(context.TODO(), testNodeName, metav1.GetOptions{})
+			So(err, ShouldBeNil)
+			return node
+		}
+		for range 3 {
+			So(fakeMaster.updateNodeObject(fakeCli, getNode(), labels, nil, nil, nil, nil), ShouldBeNil)
+			node := getNode()
+			So(node.Labels[nfdv1alpha1.FeatureLabelNs+"/foo"], ShouldEqual, "true")
+			node.Labels[nfdv1alpha1.FeatureLabelNs+"/foo"] = "false"
+			_, err := fakeCli.CoreV1().Nodes().Update(context.TODO(), node, metav1.UpdateOptions{})
+			So(err, ShouldBeNil)
+		}
+
+		Convey("the label should be listed in the node annotation", func() {
+			So(fakeMaster.updateNodeObject(fakeCli, getNode(), labels, nil, nil, nil, nil), ShouldBeNil)
+			So(getNode().Annotations[nfdv1alpha1.LabelConflictsAnnotation], ShouldEqual, "foo")
+			So(testutil.ToFloat64(nodeLabelConflictsDetected)-conflicts, ShouldEqual, 1)
+		})
+
+		Convey("the annotation should be removed when the node is purged", func() {
+			fakeMaster.purgeNode(testNodeName)
+			So(fakeMaster.updateNodeObject(fakeCli, getNode(), labels, nil, nil, nil, nil), ShouldBeNil)
+			So(getNode().Annotations, ShouldNotContainKey, nfdv1alpha1.LabelConflictsAnnotation)
+		})
+	})
+}
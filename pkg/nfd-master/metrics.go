@@ -17,6 +17,8 @@ limitations under the License.
 package nfdmaster
 
 import (
+	"math"
+
 	"github.com/prometheus/client_golang/prometheus"
 	"sigs.k8s.io/node-feature-discovery/pkg/version"
 )
@@ -27,12 +29,27 @@ const (
 	nodeUpdateRequestsQuery             = "node_update_requests_total"
 	nodeUpdatesQuery                    = "node_updates_total"
 	nodeFeatureGroupUpdateRequestsQuery = "node_feature_group_update_requests_total"
+	nodeFeatureGroupUpdatesQuery        = "node_feature_group_updates_total"
+	nodeFeatureGroupUpdateFailuresQuery = "node_feature_group_update_failures_total"
+	nodeFeatureGroupUpdatesSkippedQuery = "node_feature_group_updates_skipped_total"
 	nodeUpdateFailuresQuery             = "node_update_failures_total"
 	nodeLabelsRejectedQuery             = "node_labels_rejected_total"
 	nodeERsRejectedQuery                = "node_extendedresources_rejected_total"
 	nodeTaintsRejectedQuery             = "node_taints_rejected_total"
+	nodeLabelConflictsQuery             = "node_label_conflicts_total"
 	nfrProcessingTimeQuery              = "nodefeaturerule_processing_duration_seconds"
 	nfrProcessingErrorsQuery            = "nodefeaturerule_processing_errors_total"
+	evaluationWebhookErrorsQuery        = "evaluation_webhook_errors_total"
+	nodeLastAppliedOldestQuery          = "node_last_applied_oldest_timestamp_seconds"
+	nodeFeatureObjectsQuery             = "nodefeature_objects"
+	nodeFeatureObjectsIgnoredQuery      = "nodefeature_objects_ignored"
+	nodeFeatureCacheElementsQuery       = "nodefeature_cache_elements"
+	nodeUpdatesCoalescedQuery           = "node_updates_coalesced_total"
+	nodeUpdatesPendingQuery             = "node_updates_pending_coalescing"
+	ruleBundleErrorsQuery               = "rule_bundle_errors_total"
+	ruleBundleRulesQuery                = "rule_bundle_objects"
+	ruleEvalCacheHitsQuery              = "rule_evaluation_cache_hits_total"
+	ruleEvalCacheMissesQuery            = "rule_evaluation_cache_misses_total"
 )
 
 const (
@@ -59,6 +76,21 @@ var (
 		Name:      nodeFeatureGroupUpdateRequestsQuery,
 		Help:      "Number of cluster feature update requests processed by the master.",
 	})
+	nodeFeatureGroupUpdates = prometheus.NewCounter(prometheus.CounterOpts{
+		Subsystem: nfdMasterPrefix,
+		Name:      nodeFeatureGroupUpdatesQuery,
+		Help:      "Number of NodeFeatureGroup status updates made by the master.",
+	})
+	nodeFeatureGroupUpdateFailures = prometheus.NewCounter(prometheus.CounterOpts{
+		Subsystem: nfdMasterPrefix,
+		Name:      nodeFeatureGroupUpdateFailuresQuery,
+		Help:      "Number of NodeFeatureGroup update failures.",
+	})
+	nodeFeatureGroupUpdatesSkipped = prometheus.NewCounter(prometheus.CounterOpts{
+		Subsystem: nfdMasterPrefix,
+		Name:      nodeFeatureGroupUpdatesSkippedQuery,
+		Help:      "Number of NodeFeatureGroup update requests skipped because the rules and the member features were unchanged.",
+	})
 	nodeUpdates = prometheus.NewCounter(prometheus.CounterOpts{
 		Subsystem: nfdMasterPrefix,
 		Name:      nodeUpdatesQuery,
@@ -84,6 +116,11 @@ var (
 		Name:      nodeTaintsRejectedQuery,
 		Help:      "Number of node taints that were rejected by nfd-master.",
 	})
+	nodeLabelConflictsDetected = prometheus.NewCounter(prometheus.CounterOpts{
+		Subsystem: nfdMasterPrefix,
+		Name:      nodeLabelConflictsQuery,
+		Help:      "Number of NFD-managed node labels detected to be repeatedly changed by someone else.",
+	})
 	nfrProcessingTime = prometheus.NewHistogramVec(
 		prometheus.HistogramOpts{
 			Subsystem: nfdMasterPrefix,
@@ -101,8 +138,68 @@ var (
 		Name:      nfrProcessingErrorsQuery,
 		Help:      "Number of errors encountered while processing NodeFeatureRule objects.",
 	})
+	evaluationWebhookErrors = prometheus.NewCounter(prometheus.CounterOpts{
+		Subsystem: nfdMasterPrefix,
+		Name:      evaluationWebhookErrorsQuery,
+		Help:      "Number of failed requests to the evaluation webhook.",
+	})
+	ruleBundleErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: nfdMasterPrefix,
+		Name:      ruleBundleErrorsQuery,
+		Help:      "Number of failures to fetch, verify or parse a rule bundle.",
+	}, []string{"bundle"})
+	ruleBundleRules = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: nfdMasterPrefix,
+		Name:      ruleBundleRulesQuery,
+		Help:      "Number of NodeFeatureRule objects in a rule bundle.",
+	}, []string{"bundle"})
+	nodeUpdatesCoalesced = prometheus.NewCounter(prometheus.CounterOpts{
+		Subsystem: nfdMasterPrefix,
+		Name:      nodeUpdatesCoalescedQuery,
+		Help:      "Number of NodeFeature changes merged into an already pending node update.",
+	})
+	ruleEvalCacheHits = prometheus.NewCounter(prometheus.CounterOpts{
+		Subsystem: nfdMasterPrefix,
+		Name:      ruleEvalCacheHitsQuery,
+		Help:      "Number of node updates that used a cached NodeFeatureRule evaluation result.",
+	})
+	ruleEvalCacheMisses = prometheus.NewCounter(prometheus.CounterOpts{
+		Subsystem: nfdMasterPrefix,
+		Name:      ruleEvalCacheMissesQuery,
+		Help:      "Number of node updates that required evaluating the NodeFeatureRule objects.",
+	})
 )
 
+// newNodeUpdatesPendingGauge returns a gauge reporting the number of node
+// updates currently postponed for coalescing NodeFeature changes.
+func newNodeUpdatesPendingGauge(u *updaterPool) prometheus.GaugeFunc {
+	return prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Subsystem: nfdMasterPrefix,
+		Name:      nodeUpdatesPendingQuery,
+		Help:      "Number of node updates waiting for NodeFeature changes to settle.",
+	}, func() float64 {
+		return float64(u.numPendingCoalesced())
+	})
+}
+
+// newNodeLastAppliedOldestGauge returns a gauge reporting the least recent
+// successful update time of all nodes. The cardinality of the metric does not
+// depend on the number of nodes. NaN is reported if no nodes have been
+// updated by this instance.
+func newNodeLastAppliedOldestGauge(t *reconcileTracker) prometheus.GaugeFunc {
+	return prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Subsystem: nfdMasterPrefix,
+		Name:      nodeLastAppliedOldestQuery,
+		Help:      "Unix timestamp of the least recent successful update among all nodes.",
+	}, func() float64 {
+		oldest := t.oldest()
+		if oldest.IsZero() {
+			return math.NaN()
+		}
+		return float64(oldest.UnixNano()) / 1e9
+	})
+}
+
 // registerVersion exposes the Operator build version.
 func registerVersion(version string) {
 	buildInfo.SetToCurrentTime()
@@ -17,6 +17,7 @@ package nfdmaster
 
 import (
 	"fmt"
+	"sync"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
@@ -24,20 +25,85 @@ import (
 	"k8s.io/client-go/informers"
 	k8sclient "k8s.io/client-go/kubernetes"
 	v1lister "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 )
 
+const (
+	// DeselectedNamespacePolicyRemove removes the labels (et al.)
+	// originating from NodeFeature objects of a deselected namespace
+	// immediately.
+	DeselectedNamespacePolicyRemove = "Remove"
+	// DeselectedNamespacePolicyKeepUntilTTL keeps processing the NodeFeature
+	// objects of a deselected namespace until a TTL expires.
+	DeselectedNamespacePolicyKeepUntilTTL = "KeepUntilTTL"
+	// DeselectedNamespacePolicyKeep keeps processing the NodeFeature objects
+	// of a deselected namespace until nfd-master is restarted.
+	DeselectedNamespacePolicyKeep = "Keep"
+
+	namespaceDeselectedReason = "NodeFeatureNamespaceDeselected"
+	namespaceRetainedReason   = "NodeFeatureNamespaceRetained"
+	namespaceExpiredReason    = "NodeFeatureNamespaceExpired"
+)
+
+// namespaceDeselectionOptions specify how NodeFeature objects of namespaces
+// that fall out of the namespace selector are handled.
+type namespaceDeselectionOptions struct {
+	Policy        string
+	TTL           time.Duration
+	EventRecorder record.EventRecorder
+	// OnChange is called when the set of namespaces whose NodeFeature
+	// objects are processed changes.
+	OnChange func()
+}
+
 // NamespaceLister lists kubernetes namespaces.
 type NamespaceLister struct {
 	namespaceLister v1lister.NamespaceLister
 	labelsSelector  labels.Selector
 	stopChan        chan struct{}
+
+	deselection namespaceDeselectionOptions
+	// deselected contains the time of deselection of the namespaces that
+	// are retained after they stopped matching the selector
+	deselected     map[string]time.Time
+	deselectedLock sync.Mutex
 }
 
-func newNamespaceLister(k8sClient k8sclient.Interface, labelsSelector labels.Selector) (*NamespaceLister, error) {
+func newNamespaceLister(k8sClient k8sclient.Interface, labelsSelector labels.Selector, deselection namespaceDeselectionOptions) (*NamespaceLister, error) {
 	factory := informers.NewSharedInformerFactory(k8sClient, time.Hour)
 	namespaceLister := factory.Core().V1().Namespaces().Lister()
 
+	lister := &NamespaceLister{
+		namespaceLister: namespaceLister,
+		labelsSelector:  labelsSelector,
+		deselection:     deselection,
+		deselected:      make(map[string]time.Time),
+	}
+	if _, err := factory.Core().V1().Namespaces().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldNs := oldObj.(*corev1.Namespace)
+			ns := newObj.(*corev1.Namespace)
+			wasSelected, selected := labelsSelector.Matches(labels.Set(oldNs.Labels)), labelsSelector.Matches(labels.Set(ns.Labels))
+			switch {
+			case wasSelected && !selected:
+				lister.deselect(ns, time.Now())
+			case !wasSelected && selected:
+				lister.reselect(ns)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if ns, ok := obj.(*corev1.Namespace); ok {
+				lister.deselectedLock.Lock()
+				delete(lister.deselected, ns.Name)
+				lister.deselectedLock.Unlock()
+			}
+		},
+	}); err != nil {
+		return nil, err
+	}
+
 	stopChan := make(chan struct{})
 	factory.Start(stopChan) // runs in background
 
@@ -50,11 +116,89 @@ func newNamespaceLister(k8sClient k8sclient.Interface, labelsSelector labels.Sel
 	}
 	klog.InfoS("namespace informer cache synced", "duration", time.Since(start))
 
-	return &NamespaceLister{
-		namespaceLister: namespaceLister,
-		labelsSelector:  labelsSelector,
-		stopChan:        stopChan,
-	}, nil
+	lister.stopChan = stopChan
+	return lister, nil
+}
+
+// deselect handles a namespace that stopped matching the selector, according
+// to the configured policy.
+func (lister *NamespaceLister) deselect(ns *corev1.Namespace, now time.Time) {
+	switch lister.deselection.Policy {
+	case DeselectedNamespacePolicyKeep:
+		lister.deselectedLock.Lock()
+		lister.deselected[ns.Name] = now
+		lister.deselectedLock.Unlock()
+		klog.InfoS("WARNING: namespace no longer matches restrictions.nodeFeatureNamespaceSelector but NodeFeature objects in it are still processed (restrictions.deselectedNamespacePolicy=Keep)", "namespace", ns.Name)
+		lister.recordEvent(ns, corev1.EventTypeWarning, namespaceRetainedReason,
+			"Namespace no longer matches the NodeFeature namespace selector, NodeFeature objects in it are still processed until nfd-master is restarted")
+	case DeselectedNamespacePolicyKeepUntilTTL:
+		lister.deselectedLock.Lock()
+		lister.deselected[ns.Name] = now
+		lister.deselectedLock.Unlock()
+		klog.InfoS("namespace no longer matches restrictions.nodeFeatureNamespaceSelector, NodeFeature objects in it are processed until the TTL expires", "namespace", ns.Name, "ttl", lister.deselection.TTL)
+		lister.recordEvent(ns, corev1.EventTypeNormal, namespaceRetainedReason,
+			"Namespace no longer matches the NodeFeature namespace selector, NodeFeature objects in it are still processed for %v", lister.deselection.TTL)
+		time.AfterFunc(lister.deselection.TTL, func() { lister.expire(ns, now) })
+	default:
+		klog.InfoS("namespace no longer matches restrictions.nodeFeatureNamespaceSelector, removing node labels (et al.) originating from NodeFeature objects in it", "namespace", ns.Name)
+		lister.recordEvent(ns, corev1.EventTypeNormal, namespaceDeselectedReason,
+			"Namespace no longer matches the NodeFeature namespace selector, node labels (et al.) originating from NodeFeature objects in it are removed")
+		lister.notify()
+	}
+}
+
+// expire stops processing the NodeFeature objects of a deselected namespace
+// after the TTL has expired, unless the namespace has been re-selected or
+// deselected again in the meantime.
+func (lister *NamespaceLister) expire(ns *corev1.Namespace, deselectedAt time.Time) {
+	lister.deselectedLock.Lock()
+	t, ok := lister.deselected[ns.Name]
+	if ok && t.Equal(deselectedAt) {
+		delete(lister.deselected, ns.Name)
+	}
+	lister.deselectedLock.Unlock()
+	if !ok || !t.Equal(deselectedAt) {
+		return
+	}
+
+	klog.InfoS("TTL of deselected namespace expired, removing node labels (et al.) originating from NodeFeature objects in it", "namespace", ns.Name)
+	lister.recordEvent(ns, corev1.EventTypeNormal, namespaceExpiredReason,
+		"TTL expired, node labels (et al.) originating from NodeFeature objects in the namespace are removed")
+	lister.notify()
+}
+
+// reselect handles a namespace that started matching the selector.
+func (lister *NamespaceLister) reselect(ns *corev1.Namespace) {
+	lister.deselectedLock.Lock()
+	_, retained := lister.deselected[ns.Name]
+	delete(lister.deselected, ns.Name)
+	lister.deselectedLock.Unlock()
+
+	klog.V(2).InfoS("namespace matches restrictions.nodeFeatureNamespaceSelector", "namespace", ns.Name)
+	if !retained {
+		lister.notify()
+	}
+}
+
+// isRetained returns true if the namespace does not match the selector but
+// its NodeFeature objects are still processed.
+func (lister *NamespaceLister) isRetained(namespace string) bool {
+	lister.deselectedLock.Lock()
+	defer lister.deselectedLock.Unlock()
+	_, ok := lister.deselected[namespace]
+	return ok
+}
+
+func (lister *NamespaceLister) recordEvent(ns *corev1.Namespace, eventType, reason, messageFmt string, args ...interface{}) {
+	if lister.deselection.EventRecorder != nil {
+		lister.deselection.EventRecorder.Eventf(ns, eventType, reason, messageFmt, args...)
+	}
+}
+
+func (lister *NamespaceLister) notify() {
+	if lister.deselection.OnChange != nil {
+		lister.deselection.OnChange()
+	}
 }
 
 // list returns all kubernetes namespaces.
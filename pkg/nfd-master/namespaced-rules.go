@@ -0,0 +1,85 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nfdmaster
+
+import (
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+
+	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
+)
+
+// processNamespacedNodeFeatureRules evaluates the NamespacedNodeFeatureRule
+// objects applicable to a node, i.e. the objects in the namespaces whose node
+// selector (restrictions.namespacedRuleNodeSelectors) matches the node. The
+// rules of each namespace are evaluated against a separate copy of the
+// features so that rule outputs (backreferences) of one namespace are not
+// visible to other namespaces or the rest of the processing. Nil labels are
+// returned if no rules were evaluated.
+func (m *nfdMaster) processNamespacedNodeFeatureRules(node *corev1.Node, features *nfdv1alpha1.Features, e *Explanation) (Labels, Annotations, ExtendedResources, []corev1.Taint) {
+	if m.nfdController == nil || m.nfdController.namespacedRuleLister == nil {
+		return nil, nil, nil, nil
+	}
+
+	objs, err := m.nfdController.namespacedRuleLister.List(labels.Everything())
+	if err != nil {
+		klog.ErrorS(err, "failed to list NamespacedNodeFeatureRule resources")
+		return nil, nil, nil, nil
+	}
+
+	byNs := make(map[string][]*nfdv1alpha1.NamespacedNodeFeatureRule)
+	for _, obj := range objs {
+		sel, ok := m.namespacedRuleNodeSelectors[obj.Namespace]
+		if !ok {
+			klog.V(4).InfoS("NamespacedNodeFeatureRules are not allowed in the namespace, skipping", "namespacednodefeaturerule", klog.KObj(obj))
+			continue
+		}
+		if !sel.Matches(labels.Set(node.Labels)) {
+			continue
+		}
+		byNs[obj.Namespace] = append(byNs[obj.Namespace], obj)
+	}
+	if len(byNs) == 0 {
+		return nil, nil, nil, nil
+	}
+
+	// Process namespaces in deterministic order
+	namespaces := make([]string, 0, len(byNs))
+	for ns := range byNs {
+		namespaces = append(namespaces, ns)
+	}
+	sort.Strings(namespaces)
+
+	outLabels := Labels{}
+	outAnnotations := Annotations{}
+	outExtendedResources := ExtendedResources{}
+	var outTaints []corev1.Taint
+	for _, ns := range namespaces {
+		nsFeatures := features.DeepCopy()
+		rules := byNs[ns]
+		sort.Slice(rules, func(i, j int) bool { return rules[i].Name < rules[j].Name })
+		for _, obj := range rules {
+			klog.V(1).InfoS("executing NamespacedNodeFeatureRule", "namespacednodefeaturerule", klog.KObj(obj), "nodeName", node.Name)
+			m.executeRuleSpec(obj, &obj.Spec, node.Name, nsFeatures, outLabels, outAnnotations, outExtendedResources, &outTaints, e)
+		}
+	}
+
+	return outLabels, outAnnotations, outExtendedResources, outTaints
+}
@@ -18,13 +18,19 @@ package nfdmaster
 
 import (
 	"fmt"
+	"maps"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	k8sclient "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/metadata"
+	"k8s.io/client-go/metadata/metadatainformer"
 	restclient "k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 
 	nfdclientset "sigs.k8s.io/node-feature-discovery/api/generated/clientset/versioned"
@@ -40,6 +46,8 @@ type nfdController struct {
 	featureLister      nfdlisters.NodeFeatureLister
 	ruleLister         nfdlisters.NodeFeatureRuleLister
 	featureGroupLister nfdlisters.NodeFeatureGroupLister
+	// namespacedRuleLister is nil if NamespacedNodeFeatureRules are disabled
+	namespacedRuleLister nfdlisters.NamespacedNodeFeatureRuleLister
 
 	stopChan chan struct{}
 
@@ -47,16 +55,34 @@ type nfdController struct {
 	updateOneNodeChan              chan string
 	updateAllNodeFeatureGroupsChan chan struct{}
 	updateNodeFeatureGroupChan     chan string
+	// nodeDeletedChan and nodeCreatedChan receive the names of Node objects
+	// deleted or created after the initial sync of the node informer
+	nodeDeletedChan chan string
+	nodeCreatedChan chan string
 
 	namespaceLister *NamespaceLister
+
+	// ruleNodes tracks the nodes on which NodeFeatureRule objects matched
+	ruleNodes *ruleNodeIndex
+
+	// isNfdManagedKey tells if a node label or annotation is managed by NFD
+	isNfdManagedKey func(string) bool
 }
 
 type nfdApiControllerOptions struct {
 	DisableNodeFeature           bool
 	DisableNodeFeatureGroup      bool
+	EnableNamespacedRules        bool
 	ResyncPeriod                 time.Duration
 	K8sClient                    k8sclient.Interface
 	NodeFeatureNamespaceSelector *metav1.LabelSelector
+	DeselectedNamespacePolicy    string
+	DeselectedNamespaceTTL       time.Duration
+	EventRecorder                record.EventRecorder
+	// IsNfdManagedKey tells if a node label or annotation is managed by NFD.
+	// Changes in other labels and annotations of a node trigger
+	// re-evaluation of rules that match on node metadata.
+	IsNfdManagedKey func(string) bool
 }
 
 func init() {
@@ -70,6 +96,10 @@ func newNfdController(config *restclient.Config, nfdApiControllerOptions nfdApiC
 		updateOneNodeChan:              make(chan string),
 		updateAllNodeFeatureGroupsChan: make(chan struct{}),
 		updateNodeFeatureGroupChan:     make(chan string),
+		nodeDeletedChan:                make(chan string),
+		nodeCreatedChan:                make(chan string),
+		ruleNodes:                      newRuleNodeIndex(),
+		isNfdManagedKey:                nfdApiControllerOptions.IsNfdManagedKey,
 	}
 
 	if nfdApiControllerOptions.NodeFeatureNamespaceSelector != nil {
@@ -78,7 +108,17 @@ func newNfdController(config *restclient.Config, nfdApiControllerOptions nfdApiC
 			klog.ErrorS(err, "failed to convert label selector to map", "selector", nfdApiControllerOptions.NodeFeatureNamespaceSelector)
 			return nil, err
 		}
-		c.namespaceLister, err = newNamespaceLister(nfdApiControllerOptions.K8sClient, labelMap)
+		c.namespaceLister, err = newNamespaceLister(nfdApiControllerOptions.K8sClient, labelMap, namespaceDeselectionOptions{
+			Policy:        nfdApiControllerOptions.DeselectedNamespacePolicy,
+			TTL:           nfdApiControllerOptions.DeselectedNamespaceTTL,
+			EventRecorder: nfdApiControllerOptions.EventRecorder,
+			OnChange: func() {
+				c.updateAllNodes()
+				if !nfdApiControllerOptions.DisableNodeFeatureGroup {
+					c.updateAllNodeFeatureGroups()
+				}
+			},
+		})
 		if err != nil {
 			klog.ErrorS(err, "coudn't create namespace lister")
 			return nil, err
@@ -89,7 +129,8 @@ func newNfdController(config *restclient.Config, nfdApiControllerOptions nfdApiC
 	nfdClient := nfdclientset.NewForConfigOrDie(config)
 	klog.V(2).InfoS("initializing new NFD API controller", "options", utils.DelayedDumper(nfdApiControllerOptions))
 
-	informerFactory := nfdinformers.NewSharedInformerFactory(nfdClient, nfdApiControllerOptions.ResyncPeriod)
+	informerFactory := nfdinformers.NewSharedInformerFactoryWithOptions(nfdClient, nfdApiControllerOptions.ResyncPeriod,
+		nfdinformers.WithTransform(stripManagedFields))
 
 	// Add informer for NodeFeature objects
 	if !nfdApiControllerOptions.DisableNodeFeature {
@@ -101,6 +142,8 @@ func newNfdController(config *restclient.Config, nfdApiControllerOptions nfdApiC
 			if opts.ResourceVersion == "0" {
 				opts.ResourceVersion = ""
 			}
+			// Only cache objects targeting a node, others are never used
+			opts.LabelSelector = nfdv1alpha1.NodeFeatureObjNodeNameLabel
 		}
 		featureInformer := nfdinformersv1alpha1.New(informerFactory, "", tweakListOpts).NodeFeatures()
 		if _, err := featureInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
@@ -117,7 +160,12 @@ func newNfdController(config *restclient.Config, nfdApiControllerOptions nfdApiC
 				}
 			},
 			UpdateFunc: func(oldObj, newObj interface{}) {
+				oldNfr := oldObj.(*nfdv1alpha1.NodeFeature)
 				nfr := newObj.(*nfdv1alpha1.NodeFeature)
+				if !specChanged(oldNfr, nfr) {
+					klog.V(4).InfoS("NodeFeature metadata updated, skipping", "nodefeature", klog.KObj(nfr))
+					return
+				}
 				klog.V(2).InfoS("NodeFeature updated", "nodefeature", klog.KObj(nfr))
 				c.updateOneNode("NodeFeature", nfr)
 				if !nfdApiControllerOptions.DisableNodeFeatureGroup {
@@ -142,28 +190,86 @@ func newNfdController(config *restclient.Config, nfdApiControllerOptions nfdApiC
 	nodeFeatureRuleInformer := informerFactory.Nfd().V1alpha1().NodeFeatureRules()
 	if _, err := nodeFeatureRuleInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc: func(object interface{}) {
-			klog.V(2).InfoS("NodeFeatureRule added", "nodefeaturerule", klog.KObj(object.(metav1.Object)))
+			nfr := object.(*nfdv1alpha1.NodeFeatureRule)
+			klog.V(2).InfoS("NodeFeatureRule added", "nodefeaturerule", klog.KObj(nfr))
 			if !nfdApiControllerOptions.DisableNodeFeature {
-				c.updateAllNodes()
+				c.updateNodesForRule(nil, nfr)
 			}
 		},
 		UpdateFunc: func(oldObject, newObject interface{}) {
-			klog.V(2).InfoS("NodeFeatureRule updated", "nodefeaturerule", klog.KObj(newObject.(metav1.Object)))
-			if !nfdApiControllerOptions.DisableNodeFeature {
+			oldNfr := oldObject.(*nfdv1alpha1.NodeFeatureRule)
+			nfr := newObject.(*nfdv1alpha1.NodeFeatureRule)
+			if nfdApiControllerOptions.DisableNodeFeature {
+				return
+			}
+			switch {
+			case oldNfr.ResourceVersion == nfr.ResourceVersion:
+				// Periodic resync, do a full update of all nodes as a
+				// safety net
+				klog.V(2).InfoS("NodeFeatureRule resync", "nodefeaturerule", klog.KObj(nfr))
 				c.updateAllNodes()
+			case !specChanged(oldNfr, nfr):
+				klog.V(4).InfoS("NodeFeatureRule metadata updated, skipping", "nodefeaturerule", klog.KObj(nfr))
+			default:
+				klog.V(2).InfoS("NodeFeatureRule updated", "nodefeaturerule", klog.KObj(nfr))
+				c.updateNodesForRule(oldNfr, nfr)
 			}
 		},
 		DeleteFunc: func(object interface{}) {
-			klog.V(2).InfoS("NodeFeatureRule deleted", "nodefeaturerule", klog.KObj(object.(metav1.Object)))
-			if !nfdApiControllerOptions.DisableNodeFeature {
+			if nfdApiControllerOptions.DisableNodeFeature {
+				return
+			}
+			nfr, ok := object.(*nfdv1alpha1.NodeFeatureRule)
+			if !ok {
+				// Final state of the object is unknown
+				klog.V(2).InfoS("NodeFeatureRule deleted", "object", object)
 				c.updateAllNodes()
+				return
 			}
+			klog.V(2).InfoS("NodeFeatureRule deleted", "nodefeaturerule", klog.KObj(nfr))
+			c.updateNodesForRule(nfr, nil)
 		},
 	}); err != nil {
 		return nil, err
 	}
 	c.ruleLister = nodeFeatureRuleInformer.Lister()
 
+	// Add informer for NamespacedNodeFeatureRule objects
+	if nfdApiControllerOptions.EnableNamespacedRules {
+		namespacedRuleInformer := informerFactory.Nfd().V1alpha1().NamespacedNodeFeatureRules()
+		if _, err := namespacedRuleInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc: func(object interface{}) {
+				nnfr := object.(*nfdv1alpha1.NamespacedNodeFeatureRule)
+				klog.V(2).InfoS("NamespacedNodeFeatureRule added", "namespacednodefeaturerule", klog.KObj(nnfr))
+				if !nfdApiControllerOptions.DisableNodeFeature {
+					c.updateAllNodes()
+				}
+			},
+			UpdateFunc: func(oldObject, newObject interface{}) {
+				oldNnfr := oldObject.(*nfdv1alpha1.NamespacedNodeFeatureRule)
+				nnfr := newObject.(*nfdv1alpha1.NamespacedNodeFeatureRule)
+				if nfdApiControllerOptions.DisableNodeFeature {
+					return
+				}
+				if oldNnfr.ResourceVersion != nnfr.ResourceVersion && !specChanged(oldNnfr, nnfr) {
+					klog.V(4).InfoS("NamespacedNodeFeatureRule metadata updated, skipping", "namespacednodefeaturerule", klog.KObj(nnfr))
+					return
+				}
+				klog.V(2).InfoS("NamespacedNodeFeatureRule updated", "namespacednodefeaturerule", klog.KObj(nnfr))
+				c.updateAllNodes()
+			},
+			DeleteFunc: func(object interface{}) {
+				klog.V(2).InfoS("NamespacedNodeFeatureRule deleted")
+				if !nfdApiControllerOptions.DisableNodeFeature {
+					c.updateAllNodes()
+				}
+			},
+		}); err != nil {
+			return nil, err
+		}
+		c.namespacedRuleLister = namespacedRuleInformer.Lister()
+	}
+
 	// Add informer for NodeFeatureGroup objects
 	if !nfdApiControllerOptions.DisableNodeFeatureGroup {
 		nodeFeatureGroupInformer := informerFactory.Nfd().V1alpha1().NodeFeatureGroups()
@@ -189,8 +295,68 @@ func newNfdController(config *restclient.Config, nfdApiControllerOptions nfdApiC
 		c.featureGroupLister = nodeFeatureGroupInformer.Lister()
 	}
 
+	// Add informer for Node objects. Only the metadata of the nodes is
+	// needed, avoiding the cost of caching full Node objects.
+	var nodeInformerFactory metadatainformer.SharedInformerFactory
+	if !nfdApiControllerOptions.DisableNodeFeature {
+		metadataClient, err := metadata.NewForConfig(config)
+		if err != nil {
+			return nil, err
+		}
+		nodeInformerFactory = metadatainformer.NewSharedInformerFactory(metadataClient, nfdApiControllerOptions.ResyncPeriod)
+		nodeInformer := nodeInformerFactory.ForResource(corev1.SchemeGroupVersion.WithResource("nodes"))
+		if _, err := nodeInformer.Informer().AddEventHandler(cache.ResourceEventHandlerDetailedFuncs{
+			AddFunc: func(obj interface{}, isInInitialList bool) {
+				// All nodes are updated at startup
+				if isInInitialList {
+					return
+				}
+				node := obj.(*metav1.PartialObjectMetadata)
+				klog.V(2).InfoS("node created", "nodeName", node.Name)
+				c.sendNodeName(c.nodeCreatedChan, node.Name)
+			},
+			UpdateFunc: func(oldObj, newObj interface{}) {
+				oldNode, ok := oldObj.(*metav1.PartialObjectMetadata)
+				if !ok {
+					return
+				}
+				newNode, ok := newObj.(*metav1.PartialObjectMetadata)
+				if !ok {
+					return
+				}
+				if c.isNfdManagedKey == nil || !nodeMetadataChanged(oldNode, newNode, c.isNfdManagedKey) {
+					return
+				}
+				if c.hasNodeMatchers() {
+					klog.V(2).InfoS("node metadata changed", "nodeName", newNode.Name)
+					c.updateOneNodeByName(newNode.Name)
+				}
+				if !nfdApiControllerOptions.DisableNodeFeatureGroup && c.hasGroupNodeMatchers() {
+					c.updateAllNodeFeatureGroups()
+				}
+			},
+			DeleteFunc: func(obj interface{}) {
+				if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+					obj = tombstone.Obj
+				}
+				node, ok := obj.(*metav1.PartialObjectMetadata)
+				if !ok {
+					klog.InfoS("unexpected object in node delete event", "object", obj)
+					return
+				}
+				klog.V(2).InfoS("node deleted", "nodeName", node.Name)
+				c.sendNodeName(c.nodeDeletedChan, node.Name)
+			},
+		}); err != nil {
+			return nil, err
+		}
+	}
+
 	// Start informers
 	informerFactory.Start(c.stopChan)
+	if nodeInformerFactory != nil {
+		nodeInformerFactory.Start(c.stopChan)
+	}
 	now := time.Now()
 	ret := informerFactory.WaitForCacheSync(c.stopChan)
 	for res, ok := range ret {
@@ -198,6 +364,13 @@ func newNfdController(config *restclient.Config, nfdApiControllerOptions nfdApiC
 			return nil, fmt.Errorf("informer cache failed to sync resource %s", res)
 		}
 	}
+	if nodeInformerFactory != nil {
+		for res, ok := range nodeInformerFactory.WaitForCacheSync(c.stopChan) {
+			if !ok {
+				return nil, fmt.Errorf("informer cache failed to sync resource %s", res)
+			}
+		}
+	}
 
 	klog.InfoS("informer caches synced", "duration", time.Since(now))
 
@@ -209,6 +382,16 @@ func (c *nfdController) stop() {
 	c.namespaceLister.stop()
 }
 
+// stripManagedFields drops the managed fields from objects before they are
+// stored in the informer caches. They are not used by nfd-master and may make
+// up a considerable part of the memory footprint of the caches.
+func stripManagedFields(obj interface{}) (interface{}, error) {
+	if o, ok := obj.(metav1.Object); ok {
+		o.SetManagedFields(nil)
+	}
+	return obj, nil
+}
+
 func getNodeNameForObj(obj metav1.Object) (string, error) {
 	nodeName, ok := obj.GetLabels()[nfdv1alpha1.NodeFeatureObjNodeNameLabel]
 	if !ok {
@@ -220,14 +403,89 @@ func getNodeNameForObj(obj metav1.Object) (string, error) {
 	return nodeName, nil
 }
 
+// specChanged returns false if only the metadata (other than labels) of an
+// object has changed, i.e. the generation and labels of the object are
+// unchanged. Periodic resyncs (no changes in resource version) are treated
+// as changes.
+func specChanged(oldObj, newObj metav1.Object) bool {
+	if oldObj.GetResourceVersion() == newObj.GetResourceVersion() {
+		return true
+	}
+	return oldObj.GetGeneration() == 0 || oldObj.GetGeneration() != newObj.GetGeneration() ||
+		!maps.Equal(oldObj.GetLabels(), newObj.GetLabels())
+}
+
 func (c *nfdController) updateOneNode(typ string, obj metav1.Object) {
 	nodeName, err := getNodeNameForObj(obj)
 	if err != nil {
 		klog.ErrorS(err, "failed to determine node name for object", "type", typ, "object", klog.KObj(obj))
 		return
 	}
+	c.updateOneNodeByName(nodeName)
+}
+
+func (c *nfdController) updateOneNodeByName(nodeName string) {
+	c.sendNodeName(c.updateOneNodeChan, nodeName)
+}
+
+// hasNodeMatchers returns true if any NodeFeatureRule or
+// NamespacedNodeFeatureRule object matches on node metadata.
+func (c *nfdController) hasNodeMatchers() bool {
+	hasMatchNode := func(spec *nfdv1alpha1.NodeFeatureRuleSpec) bool {
+		for _, rule := range spec.Rules {
+			if rule.MatchNode != nil {
+				return true
+			}
+		}
+		return false
+	}
+
+	if c.ruleLister != nil {
+		rules, err := c.ruleLister.List(labels.Everything())
+		if err == nil {
+			for _, r := range rules {
+				if hasMatchNode(&r.Spec) {
+					return true
+				}
+			}
+		}
+	}
+	if c.namespacedRuleLister != nil {
+		rules, err := c.namespacedRuleLister.List(labels.Everything())
+		if err == nil {
+			for _, r := range rules {
+				if hasMatchNode(&r.Spec) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// hasGroupNodeMatchers returns true if any NodeFeatureGroup object matches on
+// node metadata.
+func (c *nfdController) hasGroupNodeMatchers() bool {
+	if c.featureGroupLister == nil {
+		return false
+	}
+	groups, err := c.featureGroupLister.List(labels.Everything())
+	if err != nil {
+		return false
+	}
+	for _, g := range groups {
+		for _, rule := range g.Spec.Rules {
+			if rule.MatchNode != nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (c *nfdController) sendNodeName(ch chan<- string, nodeName string) {
 	select {
-	case c.updateOneNodeChan <- nodeName:
+	case ch <- nodeName:
 	case <-c.stopChan:
 	}
 }
@@ -251,7 +509,9 @@ func (c *nfdController) isNamespaceSelected(namespace string) bool {
 		}
 	}
 
-	return false
+	// Namespaces that stopped matching the selector may be retained,
+	// depending on restrictions.deselectedNamespacePolicy
+	return c.namespaceLister.isRetained(namespace)
 }
 
 func (c *nfdController) updateAllNodes() {
@@ -17,7 +17,9 @@ limitations under the License.
 package nfdmaster
 
 import (
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
@@ -25,6 +27,7 @@ import (
 	"k8s.io/apimachinery/pkg/watch"
 	fakeclient "k8s.io/client-go/kubernetes/fake"
 	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/record"
 
 	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
 )
@@ -104,10 +107,66 @@ func TestIsNamespaceSelected(t *testing.T) {
 
 	for _, tc := range testcases {
 		labelMap, _ := metav1.LabelSelectorAsSelector(tc.nodeFeatureNamespaceSelector)
-		lister, err := newNamespaceLister(fakeCli, labelMap)
+		lister, err := newNamespaceLister(fakeCli, labelMap, namespaceDeselectionOptions{})
 		assert.Nil(t, err)
 		c.namespaceLister = lister
 		res := c.isNamespaceSelected(tc.objectNamespace)
 		assert.Equal(t, res, tc.expectedResult)
 	}
 }
+
+func TestNamespaceDeselection(t *testing.T) {
+	ns := newTestNamespace("fake")
+	newLister := func(policy string, ttl time.Duration) (*NamespaceLister, *record.FakeRecorder, *int) {
+		recorder := record.NewFakeRecorder(10)
+		changes := 0
+		return &NamespaceLister{
+			deselection: namespaceDeselectionOptions{
+				Policy:        policy,
+				TTL:           ttl,
+				EventRecorder: recorder,
+				OnChange:      func() { changes++ },
+			},
+			deselected: make(map[string]time.Time),
+		}, recorder, &changes
+	}
+	assertEvent := func(recorder *record.FakeRecorder, reason string) {
+		select {
+		case e := <-recorder.Events:
+			assert.True(t, strings.Contains(e, reason), "unexpected event %q", e)
+		default:
+			t.Errorf("expected %s event", reason)
+		}
+	}
+
+	// Remove policy
+	l, recorder, changes := newLister(DeselectedNamespacePolicyRemove, 0)
+	l.deselect(ns, time.Now())
+	assert.False(t, l.isRetained(ns.Name))
+	assert.Equal(t, 1, *changes)
+	assertEvent(recorder, namespaceDeselectedReason)
+
+	// Keep policy
+	l, recorder, changes = newLister(DeselectedNamespacePolicyKeep, 0)
+	l.deselect(ns, time.Now())
+	assert.True(t, l.isRetained(ns.Name))
+	assert.Equal(t, 0, *changes)
+	assertEvent(recorder, namespaceRetainedReason)
+	l.reselect(ns)
+	assert.False(t, l.isRetained(ns.Name))
+	assert.Equal(t, 0, *changes)
+
+	// KeepUntilTTL policy
+	l, recorder, changes = newLister(DeselectedNamespacePolicyKeepUntilTTL, time.Hour)
+	now := time.Now()
+	l.deselect(ns, now)
+	assert.True(t, l.isRetained(ns.Name))
+	assertEvent(recorder, namespaceRetainedReason)
+	// Expiry of an earlier deselection has no effect
+	l.expire(ns, now.Add(-time.Minute))
+	assert.True(t, l.isRetained(ns.Name))
+	l.expire(ns, now)
+	assert.False(t, l.isRetained(ns.Name))
+	assert.Equal(t, 1, *changes)
+	assertEvent(recorder, namespaceExpiredReason)
+}
@@ -17,33 +17,42 @@ limitations under the License.
 package nfdmaster
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"maps"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"sort"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	. "github.com/smartystreets/goconvey/convey"
 	"golang.org/x/net/context"
 
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
 	fakeclient "k8s.io/client-go/kubernetes/fake"
 	fakecorev1client "k8s.io/client-go/kubernetes/typed/core/v1/fake"
 	clienttesting "k8s.io/client-go/testing"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
 
 	nfdclientset "sigs.k8s.io/node-feature-discovery/api/generated/clientset/versioned"
 	fakenfdclient "sigs.k8s.io/node-feature-discovery/api/generated/clientset/versioned/fake"
 	nfdscheme "sigs.k8s.io/node-feature-discovery/api/generated/clientset/versioned/scheme"
 	nfdinformers "sigs.k8s.io/node-feature-discovery/api/generated/informers/externalversions"
+	nfdlisters "sigs.k8s.io/node-feature-discovery/api/generated/listers/nfd/v1alpha1"
 	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
 	"sigs.k8s.io/node-feature-discovery/pkg/features"
 	"sigs.k8s.io/node-feature-discovery/pkg/utils"
@@ -67,6 +76,7 @@ func newFakeNfdAPIController(client *fakenfdclient.Clientset) *nfdController {
 		stopChan:           make(chan struct{}),
 		updateAllNodesChan: make(chan struct{}, 1),
 		updateOneNodeChan:  make(chan string),
+		ruleNodes:          newRuleNodeIndex(),
 	}
 
 	informerFactory := nfdinformers.NewSharedInformerFactory(client, 1*time.Hour)
@@ -178,7 +188,7 @@ func TestUpdateNodeObject(t *testing.T) {
 		fakeMaster := newFakeMaster(WithKubernetesClient(fakeCli))
 
 		Convey("When I successfully update the node with feature labels", func() {
-			err := fakeMaster.updateNodeObject(fakeCli, testNode, featureLabels, featureAnnotations, featureExtResources, nil)
+			err := fakeMaster.updateNodeObject(fakeCli, testNode, featureLabels, featureAnnotations, featureExtResources, nil, nil)
 			Convey("Error is nil", func() {
 				So(err, ShouldBeNil)
 			})
@@ -201,16 +211,38 @@ func TestUpdateNodeObject(t *testing.T) {
 
 				So(err, ShouldBeNil)
 				So(updatedNode.Labels, ShouldEqual, featureLabels)
+
+				// Time of the update must be recorded
+				_, err = time.Parse(time.RFC3339, updatedNode.Annotations[nfdv1alpha1.LastAppliedTimeAnnotation])
+				So(err, ShouldBeNil)
+				delete(updatedNode.Annotations, nfdv1alpha1.LastAppliedTimeAnnotation)
+
 				So(updatedNode.Annotations, ShouldEqual, expectedAnnotations)
 				So(updatedNode.Status.Capacity, ShouldEqual, expectedCapacity)
 			})
 		})
 
+		Convey("When I update the node with extended resources in DevicePlugin mode", func() {
+			fakeMaster.config.ExtendedResourceMode = ExtendedResourceModeDevicePlugin
+			defer func() { fakeMaster.config.ExtendedResourceMode = ExtendedResourceModeNodeStatus }()
+
+			err := fakeMaster.updateNodeObject(fakeCli, testNode, featureLabels, featureAnnotations, featureExtResources, nil, nil)
+			So(err, ShouldBeNil)
+
+			Convey("Extended resources are stored in an annotation instead of the node status", func() {
+				updatedNode, err := fakeCli.CoreV1().Nodes().Get(context.TODO(), testNodeName, metav1.GetOptions{})
+				So(err, ShouldBeNil)
+				So(updatedNode.Annotations[nfdv1alpha1.ExtendedResourceValuesAnnotation], ShouldEqual,
+					nfdv1alpha1.FeatureLabelNs+"/source-feature.1=1,"+nfdv1alpha1.FeatureLabelNs+"/source-feature.2=2")
+				So(updatedNode.Status.Capacity, ShouldEqual, testNode.Status.Capacity)
+			})
+		})
+
 		Convey("When I fail to patch a node", func() {
 			fakeCli.CoreV1().(*fakecorev1client.FakeCoreV1).PrependReactor("patch", "nodes", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
 				return true, &corev1.Node{}, errors.New("Fake error when patching node")
 			})
-			err := fakeMaster.updateNodeObject(fakeCli, testNode, nil, featureAnnotations, ExtendedResources{"": ""}, nil)
+			err := fakeMaster.updateNodeObject(fakeCli, testNode, nil, featureAnnotations, ExtendedResources{"": ""}, nil, nil)
 
 			Convey("Error is produced", func() {
 				So(err, ShouldBeError)
@@ -434,62 +466,6 @@ func TestFilterLabels(t *testing.T) {
 	}
 }
 
-func TestCreatePatches(t *testing.T) {
-	Convey("When creating JSON patches", t, func() {
-		existingItems := map[string]string{"key-1": "val-1", "key-2": "val-2", "key-3": "val-3"}
-		overwriteKeys := true
-		jsonPath := "/root"
-
-		Convey("When there are neither itmes to remoe nor to add or update", func() {
-			p := createPatches(sets.New([]string{"foo", "bar"}...), existingItems, map[string]string{}, jsonPath, overwriteKeys)
-			So(len(p), ShouldEqual, 0)
-		})
-
-		Convey("When there are itmes to remoe but none to add or update", func() {
-			p := createPatches(sets.New([]string{"key-2", "key-3", "foo"}...), existingItems, map[string]string{}, jsonPath, overwriteKeys)
-			expected := []utils.JsonPatch{
-				utils.NewJsonPatch("remove", jsonPath, "key-2", ""),
-				utils.NewJsonPatch("remove", jsonPath, "key-3", ""),
-			}
-			So(sortJsonPatches(p), ShouldResemble, sortJsonPatches(expected))
-		})
-
-		Convey("When there are no itmes to remove but new items to add", func() {
-			newItems := map[string]string{"new-key": "new-val", "key-1": "new-1"}
-			p := createPatches(sets.New([]string{"key-1"}...), existingItems, newItems, jsonPath, overwriteKeys)
-			expected := []utils.JsonPatch{
-				utils.NewJsonPatch("add", jsonPath, "new-key", newItems["new-key"]),
-				utils.NewJsonPatch("replace", jsonPath, "key-1", newItems["key-1"]),
-			}
-			So(sortJsonPatches(p), ShouldResemble, sortJsonPatches(expected))
-		})
-
-		Convey("When there are items to remove add and update", func() {
-			newItems := map[string]string{"new-key": "new-val", "key-2": "new-2", "key-4": "val-4"}
-			p := createPatches(sets.New([]string{"key-1", "key-2", "key-3", "foo"}...), existingItems, newItems, jsonPath, overwriteKeys)
-			expected := []utils.JsonPatch{
-				utils.NewJsonPatch("add", jsonPath, "new-key", newItems["new-key"]),
-				utils.NewJsonPatch("add", jsonPath, "key-4", newItems["key-4"]),
-				utils.NewJsonPatch("replace", jsonPath, "key-2", newItems["key-2"]),
-				utils.NewJsonPatch("remove", jsonPath, "key-1", ""),
-				utils.NewJsonPatch("remove", jsonPath, "key-3", ""),
-			}
-			So(sortJsonPatches(p), ShouldResemble, sortJsonPatches(expected))
-		})
-
-		Convey("When overwrite of keys is denied and there is already an existant key", func() {
-			overwriteKeys = false
-			newItems := map[string]string{"key-1": "new-2", "key-4": "val-4"}
-			p := createPatches(sets.New([]string{}...), existingItems, newItems, jsonPath, overwriteKeys)
-			expected := []utils.JsonPatch{
-				utils.NewJsonPatch("add", jsonPath, "key-4", newItems["key-4"]),
-				utils.NewJsonPatch("replace", jsonPath, "key-1", newItems["key-1"]),
-			}
-			So(sortJsonPatches(p), ShouldResemble, sortJsonPatches(expected))
-		})
-	})
-}
-
 func TestRemoveLabelsWithPrefix(t *testing.T) {
 	Convey("When removing labels", t, func() {
 		n := &corev1.Node{
@@ -625,7 +601,7 @@ func BenchmarkNfdAPIUpdateAllNodes(b *testing.B) {
 	updaterPool := newUpdaterPool(fakeMaster)
 	fakeMaster.updaterPool = updaterPool
 
-	updaterPool.start(10)
+	updaterPool.start(10, 10)
 
 	b.ResetTimer()
 
@@ -685,6 +661,38 @@ func removeLabelsWithPrefix(n *corev1.Node, search string) []utils.JsonPatch {
 	return p
 }
 
+func TestFilterExtendedResource(t *testing.T) {
+	features := &nfdv1alpha1.Features{
+		Attributes: map[string]nfdv1alpha1.AttributeFeatureSet{
+			"memory.swap": nfdv1alpha1.NewAttributeFeatures(map[string]string{"total_mb": "16384"}),
+		},
+	}
+	tests := []struct {
+		value string
+		want  string
+		fail  bool
+	}{
+		{value: "4", want: "4"},
+		{value: "@memory.swap.total_mb", want: "16384"},
+		{value: "@memory.swap.total_mb|Mi:Gi", want: "16"},
+		{value: "@memory.swap.total_mb|Ki", want: "16"},
+		{value: "@memory.swap.total_mb|Mi:kHz", fail: true},
+		{value: "@memory.swap.unknown|Mi:Gi", fail: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := filterExtendedResource(nfdv1alpha1.ExtendedResourceNs+"/test", tt.value, features)
+			if (err != nil) != tt.fail {
+				t.Errorf("filterExtendedResource() error = %v, want failure %v", err, tt.fail)
+			}
+			if got != tt.want {
+				t.Errorf("filterExtendedResource() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestGetDynamicValue(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -743,3 +751,1160 @@ func TestGetDynamicValue(t *testing.T) {
 		})
 	}
 }
+
+func TestEvaluationWebhook(t *testing.T) {
+	Convey("When using an evaluation webhook", t, func() {
+		var received EvaluationRequest
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			if received.NodeName == "fail" {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			if received.NodeName == "huge" {
+				_, _ = w.Write([]byte(strings.Repeat(" ", maxEvaluationResponseSize+1)))
+				return
+			}
+			_ = json.NewEncoder(w).Encode(EvaluationResponse{
+				Labels:      map[string]string{"webhook-label": "true", "example.io/label": "foo"},
+				Annotations: map[string]string{"webhook-annotation": "bar"},
+			})
+		}))
+		defer srv.Close()
+
+		m := newFakeMaster()
+		m.config.AutoDefaultNs = true
+		features := nfdv1alpha1.NewFeatures()
+
+		Convey("outputs of the webhook should be returned", func() {
+			wh, err := newEvaluationWebhook(EvaluationWebhookConfig{URL: srv.URL})
+			So(err, ShouldBeNil)
+			m.evaluationWebhook = wh
+
+			out, err := m.processEvaluationWebhook(context.Background(), testNodeName, features, map[string]string{"a": "1"}, map[string]string{"b": "2"})
+			So(err, ShouldBeNil)
+			So(received.NodeName, ShouldEqual, testNodeName)
+			So(received.Labels, ShouldResemble, map[string]string{"a": "1", "b": "2"})
+			So(out.Labels, ShouldResemble, map[string]string{
+				nfdv1alpha1.FeatureLabelNs + "/webhook-label": "true",
+				"example.io/label": "foo",
+			})
+			So(out.Annotations, ShouldResemble, map[string]string{nfdv1alpha1.FeatureAnnotationNs + "/webhook-annotation": "bar"})
+		})
+
+		Convey("failures should be ignored with the Ignore failure policy", func() {
+			wh, err := newEvaluationWebhook(EvaluationWebhookConfig{URL: srv.URL, FailurePolicy: EvaluationWebhookFailurePolicyIgnore})
+			So(err, ShouldBeNil)
+			m.evaluationWebhook = wh
+
+			out, err := m.processEvaluationWebhook(context.Background(), "fail", features, nil, nil)
+			So(err, ShouldBeNil)
+			So(out, ShouldBeNil)
+		})
+
+		Convey("failures should be returned with the Fail failure policy", func() {
+			wh, err := newEvaluationWebhook(EvaluationWebhookConfig{URL: srv.URL, FailurePolicy: EvaluationWebhookFailurePolicyFail})
+			So(err, ShouldBeNil)
+			m.evaluationWebhook = wh
+
+			_, err = m.processEvaluationWebhook(context.Background(), "fail", features, nil, nil)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("too large responses should be rejected", func() {
+			wh, err := newEvaluationWebhook(EvaluationWebhookConfig{URL: srv.URL, FailurePolicy: EvaluationWebhookFailurePolicyFail})
+			So(err, ShouldBeNil)
+			m.evaluationWebhook = wh
+
+			_, err = m.processEvaluationWebhook(context.Background(), "huge", features, nil, nil)
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldContainSubstring, "maximum size")
+		})
+
+		Convey("invalid failure policy should be rejected", func() {
+			_, err := newEvaluationWebhook(EvaluationWebhookConfig{URL: srv.URL, FailurePolicy: "foo"})
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestReconcileTracker(t *testing.T) {
+	Convey("When tracking node updates", t, func() {
+		tracker := newReconcileTracker()
+		So(tracker.oldest().IsZero(), ShouldBeTrue)
+
+		t0 := time.Now()
+		tracker.update("node-1", t0.Add(time.Minute))
+		tracker.update("node-2", t0)
+		tracker.update("node-3", t0.Add(2*time.Minute))
+		So(tracker.oldest(), ShouldEqual, t0)
+
+		Convey("removed nodes should not be considered", func() {
+			tracker.remove("node-2")
+			So(tracker.oldest(), ShouldEqual, t0.Add(time.Minute))
+
+			tracker.retain(sets.New("node-3"))
+			So(tracker.oldest(), ShouldEqual, t0.Add(2*time.Minute))
+		})
+	})
+}
+
+func TestTrackingStorage(t *testing.T) {
+	Convey("When storing tracking information in ConfigMaps", t, func() {
+		testNode := newTestNode()
+		testNode.Labels[nfdv1alpha1.FeatureLabelNs+"/old-feature"] = "old-value"
+		testNode.Annotations[nfdv1alpha1.FeatureLabelsAnnotation] = "old-feature"
+
+		fakeCli := fakeclient.NewSimpleClientset(testNode)
+		fakeMaster := newFakeMaster(
+			WithKubernetesClient(fakeCli),
+			withConfig(&NFDConfig{TrackingStorage: TrackingStorageConfigMap, Restrictions: Restrictions{AllowOverwrite: true}}))
+		cmName := fakeMaster.trackingConfigMapName(testNodeName)
+		labels := Labels{nfdv1alpha1.FeatureLabelNs + "/new-feature": "true"}
+
+		update := func() *corev1.Node {
+			node, err := fakeCli.CoreV1().Nodes().Get(context.TODO(), testNodeName, metav1.GetOptions{})
+			So(err, ShouldBeNil)
+			So(fakeMaster.updateNodeObject(fakeCli, node, labels, nil, nil, nil, nil), ShouldBeNil)
+			node, err = fakeCli.CoreV1().Nodes().Get(context.TODO(), testNodeName, metav1.GetOptions{})
+			So(err, ShouldBeNil)
+			return node
+		}
+
+		// First update creates the ConfigMap
+		node := update()
+		So(node.Labels, ShouldResemble, map[string]string(labels))
+		cm, err := fakeCli.CoreV1().ConfigMaps(fakeMaster.namespace).Get(context.TODO(), cmName, metav1.GetOptions{})
+		So(err, ShouldBeNil)
+		So(cm.Data, ShouldResemble, map[string]string{nfdv1alpha1.FeatureLabelsAnnotation: "new-feature"})
+
+		// Second update migrates tracking annotations away from the node
+		node = update()
+		So(node.Annotations, ShouldNotContainKey, nfdv1alpha1.FeatureLabelsAnnotation)
+
+		Convey("tracking information should be migrated back to node annotations", func() {
+			fakeMaster.config.TrackingStorage = TrackingStorageAnnotations
+			fakeMaster.updateStaleTrackingConfigMaps()
+
+			node := update()
+			So(node.Labels, ShouldResemble, map[string]string(labels))
+			So(node.Annotations[nfdv1alpha1.FeatureLabelsAnnotation], ShouldEqual, "new-feature")
+			_, err := fakeCli.CoreV1().ConfigMaps(fakeMaster.namespace).Get(context.TODO(), cmName, metav1.GetOptions{})
+			So(apierrors.IsNotFound(err), ShouldBeTrue)
+		})
+
+		Convey("ConfigMap should be deleted when nothing is tracked", func() {
+			labels = Labels{}
+			node := update()
+			So(node.Labels, ShouldBeEmpty)
+			_, err := fakeCli.CoreV1().ConfigMaps(fakeMaster.namespace).Get(context.TODO(), cmName, metav1.GetOptions{})
+			So(apierrors.IsNotFound(err), ShouldBeTrue)
+		})
+
+		Convey("new labels should be tracked even if patching the node fails", func() {
+			labels = Labels{nfdv1alpha1.FeatureLabelNs + "/another-feature": "true"}
+			fakeCli.CoreV1().(*fakecorev1client.FakeCoreV1).PrependReactor("patch", "nodes", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+				return true, &corev1.Node{}, errors.New("Fake error when patching node")
+			})
+			node, err := fakeCli.CoreV1().Nodes().Get(context.TODO(), testNodeName, metav1.GetOptions{})
+			So(err, ShouldBeNil)
+			So(fakeMaster.updateNodeObject(fakeCli, node, labels, nil, nil, nil, nil), ShouldBeError)
+
+			cm, err := fakeCli.CoreV1().ConfigMaps(fakeMaster.namespace).Get(context.TODO(), cmName, metav1.GetOptions{})
+			So(err, ShouldBeNil)
+			So(cm.Data, ShouldResemble, map[string]string{nfdv1alpha1.FeatureLabelsAnnotation: "another-feature,new-feature"})
+		})
+	})
+
+	Convey("When storing tracking information in node annotations", t, func() {
+		fakeCli := fakeclient.NewSimpleClientset(newTestNode())
+		fakeMaster := newFakeMaster(WithKubernetesClient(fakeCli))
+		numLists := func() int {
+			n := 0
+			for _, a := range fakeCli.Actions() {
+				if a.Matches("list", "configmaps") {
+					n++
+				}
+			}
+			return n
+		}
+
+		Convey("tracking ConfigMaps should not be listed after all have been migrated", func() {
+			fakeMaster.updateStaleTrackingConfigMaps()
+			fakeMaster.updateStaleTrackingConfigMaps()
+			So(numLists(), ShouldEqual, 1)
+		})
+	})
+
+	Convey("When the tracking ConfigMap name would be too long", t, func() {
+		fakeMaster := newFakeMaster()
+		nodeName := strings.Repeat("a", 250)
+		name := fakeMaster.trackingConfigMapName(nodeName)
+
+		Convey("it should be shortened to a valid and unique name", func() {
+			So(len(name), ShouldBeLessThanOrEqualTo, validation.DNS1123SubdomainMaxLength)
+			So(validation.IsDNS1123Subdomain(name), ShouldBeEmpty)
+			So(fakeMaster.trackingConfigMapName(nodeName+"b"), ShouldNotEqual, name)
+		})
+	})
+}
+
+func TestAnnotationNs(t *testing.T) {
+	Convey("When a custom annotation namespace is configured", t, func() {
+		testNode := newTestNode()
+		testNode.Labels[nfdv1alpha1.FeatureLabelNs+"/old-feature"] = "old-value"
+		testNode.Annotations[nfdv1alpha1.FeatureLabelsAnnotation] = "old-feature"
+		testNode.Annotations[nfdv1alpha1.LastAppliedTimeAnnotation] = "2006-01-02T15:04:05Z"
+
+		fakeCli := fakeclient.NewSimpleClientset(testNode)
+		fakeMaster := newFakeMaster(
+			WithKubernetesClient(fakeCli),
+			withConfig(&NFDConfig{AnnotationNs: "nfd.example.com", Restrictions: Restrictions{AllowOverwrite: true}}))
+		labels := Labels{nfdv1alpha1.FeatureLabelNs + "/new-feature": "true"}
+
+		So(fakeMaster.updateNodeObject(fakeCli, testNode, labels, nil, nil, nil, nil), ShouldBeNil)
+		node, err := fakeCli.CoreV1().Nodes().Get(context.TODO(), testNodeName, metav1.GetOptions{})
+		So(err, ShouldBeNil)
+
+		Convey("tracking annotations should be migrated to the new namespace", func() {
+			So(node.Labels, ShouldResemble, map[string]string(labels))
+			So(node.Annotations["nfd.example.com/feature-labels"], ShouldEqual, "new-feature")
+			So(node.Annotations, ShouldContainKey, "nfd.example.com/last-applied-time")
+			So(node.Annotations, ShouldNotContainKey, nfdv1alpha1.FeatureLabelsAnnotation)
+			So(node.Annotations, ShouldNotContainKey, nfdv1alpha1.LastAppliedTimeAnnotation)
+		})
+	})
+
+	Convey("When an invalid annotation namespace is configured", t, func() {
+		master := newFakeMaster()
+		So(master.configure("non-existing-file", `{"annotationNs": "Invalid_Ns"}`), ShouldNotBeNil)
+	})
+}
+
+func TestNodeSelectors(t *testing.T) {
+	Convey("When node selectors are configured", t, func() {
+		master := newFakeMaster()
+		overrides := `{"nodeSelector": {"matchLabels": {"kubernetes.io/os": "linux"}}, "excludeNodeSelector": {"matchExpressions": [{"key": "node-role.kubernetes.io/control-plane", "operator": "Exists"}]}}`
+		So(master.configure("non-existing-file", overrides), ShouldBeNil)
+
+		node := func(l map[string]string) *corev1.Node {
+			return &corev1.Node{ObjectMeta: metav1.ObjectMeta{Labels: l}}
+		}
+		So(master.isNodeManaged(node(map[string]string{"kubernetes.io/os": "linux"})), ShouldBeTrue)
+		So(master.isNodeManaged(node(map[string]string{"kubernetes.io/os": "windows"})), ShouldBeFalse)
+		So(master.isNodeManaged(node(map[string]string{"kubernetes.io/os": "linux", "node-role.kubernetes.io/control-plane": ""})), ShouldBeFalse)
+
+		Convey("labels should be removed from nodes that are not managed", func() {
+			testNode := newTestNode()
+			testNode.Labels[nfdv1alpha1.FeatureLabelNs+"/old-feature"] = "old-value"
+			testNode.Annotations[nfdv1alpha1.FeatureLabelsAnnotation] = "old-feature"
+
+			fakeCli := fakeclient.NewSimpleClientset(testNode)
+			master.k8sClient = fakeCli
+			master.nfdController = newFakeNfdAPIController(fakenfdclient.NewSimpleClientset())
+
+			So(master.nfdAPIUpdateOneNode(fakeCli, testNode), ShouldBeNil)
+			node, err := fakeCli.CoreV1().Nodes().Get(context.TODO(), testNodeName, metav1.GetOptions{})
+			So(err, ShouldBeNil)
+			So(node.Labels, ShouldNotContainKey, nfdv1alpha1.FeatureLabelNs+"/old-feature")
+			So(node.Annotations, ShouldNotContainKey, nfdv1alpha1.FeatureLabelsAnnotation)
+		})
+	})
+
+	Convey("When node selectors are invalid", t, func() {
+		master := newFakeMaster()
+		overrides := `{"excludeNodeSelector": {"matchExpressions": [{"key": "foo", "operator": "Invalid"}]}}`
+		So(master.configure("non-existing-file", overrides), ShouldNotBeNil)
+	})
+}
+
+func TestRuleNodeIndex(t *testing.T) {
+	Convey("When tracking nodes matched by NodeFeatureRules", t, func() {
+		idx := newRuleNodeIndex()
+		idx.setNode("node-1", sets.New("rule-a", "rule-b"))
+		idx.setNode("node-2", sets.New("rule-a"))
+		So(idx.nodesOf("rule-a"), ShouldResemble, sets.New("node-1", "node-2"))
+		So(idx.nodesOf("rule-b"), ShouldResemble, sets.New("node-1"))
+		So(idx.nodesOf("rule-c"), ShouldBeEmpty)
+
+		idx.setNode("node-1", sets.New("rule-c"))
+		So(idx.nodesOf("rule-a"), ShouldResemble, sets.New("node-2"))
+		So(idx.nodesOf("rule-b"), ShouldBeEmpty)
+		So(idx.nodesOf("rule-c"), ShouldResemble, sets.New("node-1"))
+
+		idx.removeNode("node-2")
+		So(idx.nodesOf("rule-a"), ShouldBeEmpty)
+		So(idx.nodes, ShouldHaveLength, 1)
+	})
+
+	Convey("When determining the features referenced by a NodeFeatureRule", t, func() {
+		term := func(feature string) nfdv1alpha1.FeatureMatcherTerm {
+			return nfdv1alpha1.FeatureMatcherTerm{Feature: feature}
+		}
+		spec := &nfdv1alpha1.NodeFeatureRuleSpec{
+			Rules: []nfdv1alpha1.Rule{
+				{Name: "r1", MatchFeatures: nfdv1alpha1.FeatureMatcher{term("kernel.loadedmodule")}},
+				{Name: "r2", MatchAny: []nfdv1alpha1.MatchAnyElem{
+					{MatchFeatures: nfdv1alpha1.FeatureMatcher{term("CPU.cpuid")}},
+					{MatchFeatures: nfdv1alpha1.FeatureMatcher{term("pci.device")}},
+				}},
+			},
+		}
+		keys, ok := ruleFeatureKeys(spec)
+		So(ok, ShouldBeTrue)
+		So(keys, ShouldResemble, sets.New("kernel.loadedmodule", "cpu.cpuid", "pci.device"))
+
+		Convey("rules without feature matchers match any node", func() {
+			spec.Rules = append(spec.Rules, nfdv1alpha1.Rule{Name: "r3"})
+			_, ok := ruleFeatureKeys(spec)
+			So(ok, ShouldBeFalse)
+		})
+		Convey("rules referencing outputs of other rules match any node", func() {
+			spec.Rules = append(spec.Rules, nfdv1alpha1.Rule{Name: "r3", MatchFeatures: nfdv1alpha1.FeatureMatcher{term("rule.matched")}})
+			_, ok := ruleFeatureKeys(spec)
+			So(ok, ShouldBeFalse)
+		})
+		Convey("rules with else outputs affect any node", func() {
+			spec.Rules[0].ElseLabels = map[string]string{"no-module": "true"}
+			_, ok := ruleFeatureKeys(spec)
+			So(ok, ShouldBeFalse)
+		})
+	})
+
+	Convey("When the else labels of a NodeFeatureRule change", t, func() {
+		c := newFakeNfdAPIController(fakenfdclient.NewSimpleClientset())
+		defer close(c.stopChan)
+
+		oldRule := &nfdv1alpha1.NodeFeatureRule{
+			ObjectMeta: metav1.ObjectMeta{Name: "rule-a"},
+			Spec: nfdv1alpha1.NodeFeatureRuleSpec{
+				Rules: []nfdv1alpha1.Rule{{
+					Name:          "r1",
+					Labels:        map[string]string{"module": "true"},
+					ElseLabels:    map[string]string{"no-module": "true"},
+					MatchFeatures: nfdv1alpha1.FeatureMatcher{{Feature: "kernel.loadedmodule"}},
+				}},
+			},
+		}
+		newRule := oldRule.DeepCopy()
+		newRule.Spec.Rules[0].ElseLabels = map[string]string{"no-module": "false"}
+		c.updateNodesForRule(oldRule, newRule)
+
+		Convey("all nodes should be updated", func() {
+			So(c.updateAllNodesChan, ShouldHaveLength, 1)
+		})
+	})
+
+	Convey("When checking for spec changes", t, func() {
+		oldObj := &nfdv1alpha1.NodeFeatureRule{ObjectMeta: metav1.ObjectMeta{ResourceVersion: "1", Generation: 1}}
+		newObj := oldObj.DeepCopy()
+		So(specChanged(oldObj, newObj), ShouldBeTrue)
+
+		newObj.ResourceVersion = "2"
+		newObj.Annotations = map[string]string{"foo": "bar"}
+		So(specChanged(oldObj, newObj), ShouldBeFalse)
+
+		newObj.Generation = 2
+		So(specChanged(oldObj, newObj), ShouldBeTrue)
+	})
+}
+
+func TestConfigureFeatureGates(t *testing.T) {
+	Convey("When feature gates are specified in the configuration", t, func() {
+		master := newFakeMaster()
+		defer func() {
+			master.args = Args{}
+			_, _ = master.configureFeatureGates(nil, false)
+		}()
+
+		So(master.configure("non-existing-file", `{"featureGates": {"DisableAutoPrefix": true, "NodeFeatureGroupAPI": true}}`), ShouldBeNil)
+		So(features.NFDFeatureGate.Enabled(features.DisableAutoPrefix), ShouldBeTrue)
+		So(features.NFDFeatureGate.Enabled(features.NodeFeatureGroupAPI), ShouldBeTrue)
+
+		Convey("only hot-reloadable feature gates should be changed on reload", func() {
+			changed, err := master.configureFeatureGates(map[string]bool{}, true)
+			So(err, ShouldBeNil)
+			So(changed, ShouldBeTrue)
+			So(features.NFDFeatureGate.Enabled(features.DisableAutoPrefix), ShouldBeFalse)
+			So(features.NFDFeatureGate.Enabled(features.NodeFeatureGroupAPI), ShouldBeTrue)
+
+			changed, err = master.configureFeatureGates(map[string]bool{}, true)
+			So(err, ShouldBeNil)
+			So(changed, ShouldBeFalse)
+		})
+
+		Convey("command line flags should take precedence", func() {
+			master.args.FeatureGates = map[string]bool{"DisableAutoPrefix": false}
+			changed, err := master.configureFeatureGates(map[string]bool{"DisableAutoPrefix": true}, true)
+			So(err, ShouldBeNil)
+			So(changed, ShouldBeTrue)
+			So(features.NFDFeatureGate.Enabled(features.DisableAutoPrefix), ShouldBeFalse)
+		})
+
+		Convey("unknown and locked feature gates should be rejected", func() {
+			_, err := master.configureFeatureGates(map[string]bool{"NonExistent": true}, true)
+			So(err, ShouldNotBeNil)
+			So(master.configure("non-existing-file", `{"featureGates": {"NodeFeatureAPI": false}}`), ShouldNotBeNil)
+		})
+	})
+}
+
+func TestNodeEvents(t *testing.T) {
+	Convey("When updating a node with node events enabled", t, func() {
+		testNode := newTestNode()
+		testNode.Labels["foo"] = "bar"
+		testNode.Annotations["foo"] = "bar"
+		fakeCli := fakeclient.NewSimpleClientset(testNode)
+		fakeMaster := newFakeMaster(WithKubernetesClient(fakeCli))
+		fakeMaster.config.EnableNodeEvents = true
+		recorder := record.NewFakeRecorder(10)
+		fakeMaster.eventRecorder = recorder
+
+		labels := Labels{nfdv1alpha1.FeatureLabelNs + "/a": "1", nfdv1alpha1.FeatureLabelNs + "/b": "2"}
+		extResources := ExtendedResources{nfdv1alpha1.FeatureLabelNs + "/res": "4"}
+		taints := []corev1.Taint{{Key: nfdv1alpha1.TaintNs + "/t", Value: "v", Effect: corev1.TaintEffectNoSchedule}}
+		So(fakeMaster.updateNodeObject(fakeCli, testNode, labels, nil, extResources, taints, nil), ShouldBeNil)
+
+		Convey("events with the changes should be emitted", func() {
+			So(recorder.Events, ShouldHaveLength, 3)
+			So(<-recorder.Events, ShouldEqual, "Normal FeatureLabelsChanged Feature labels changed: added: feature.node.kubernetes.io/a=1, feature.node.kubernetes.io/b=2")
+			So(<-recorder.Events, ShouldEqual, "Normal ExtendedResourcesChanged Extended resources changed: added: feature.node.kubernetes.io/res=4")
+			So(<-recorder.Events, ShouldEqual, "Normal FeatureTaintsChanged Feature taints changed: added: feature.node.kubernetes.io/t=v:NoSchedule")
+		})
+
+		Convey("only the delta should be reported on subsequent updates", func() {
+			for len(recorder.Events) > 0 {
+				<-recorder.Events
+			}
+			node, err := fakeCli.CoreV1().Nodes().Get(context.TODO(), testNodeName, metav1.GetOptions{})
+			So(err, ShouldBeNil)
+			labels := Labels{nfdv1alpha1.FeatureLabelNs + "/a": "3"}
+			So(fakeMaster.updateNodeObject(fakeCli, node, labels, nil, extResources, nil, nil), ShouldBeNil)
+			So(recorder.Events, ShouldHaveLength, 2)
+			So(<-recorder.Events, ShouldEqual, "Normal FeatureLabelsChanged Feature labels changed: removed: feature.node.kubernetes.io/b; changed: feature.node.kubernetes.io/a=1->3")
+			So(<-recorder.Events, ShouldEqual, "Normal FeatureTaintsChanged Feature taints changed: removed: feature.node.kubernetes.io/t=v:NoSchedule")
+		})
+
+		Convey("no events should be emitted if node events are disabled", func() {
+			for len(recorder.Events) > 0 {
+				<-recorder.Events
+			}
+			fakeMaster.config.EnableNodeEvents = false
+			node, err := fakeCli.CoreV1().Nodes().Get(context.TODO(), testNodeName, metav1.GetOptions{})
+			So(err, ShouldBeNil)
+			So(fakeMaster.updateNodeObject(fakeCli, node, nil, nil, extResources, nil, nil), ShouldBeNil)
+			So(recorder.Events, ShouldBeEmpty)
+		})
+	})
+
+	Convey("When formatting node changes", t, func() {
+		c := nodeChanges{}
+		for i := 0; i < maxNodeEventChanges+2; i++ {
+			c.added = append(c.added, fmt.Sprintf("a%03d", i))
+		}
+		c.removed = []string{"r"}
+		msg := c.String()
+		So(msg, ShouldEndWith, ", ... (2 more); removed: ... (1 more)")
+		So(nodeChanges{}.String(), ShouldBeEmpty)
+	})
+}
+
+func TestNodeFeatureNamespaceLimit(t *testing.T) {
+	Convey("When limiting the number of NodeFeature objects per namespace", t, func() {
+		master := newFakeMaster()
+		master.namespace = "nfd"
+		master.config.Restrictions.MaxNodeFeaturesPerNamespace = 2
+		recorder := record.NewFakeRecorder(10)
+		master.eventRecorder = recorder
+
+		now := time.Now()
+		newNF := func(ns, name string, age time.Duration) *nfdv1alpha1.NodeFeature {
+			return &nfdv1alpha1.NodeFeature{ObjectMeta: metav1.ObjectMeta{
+				Namespace:         ns,
+				Name:              name,
+				CreationTimestamp: metav1.NewTime(now.Add(-age)),
+			}}
+		}
+		objs := []*nfdv1alpha1.NodeFeature{
+			newNF("nfd", "a", 0),
+			newNF("nfd", "b", 0),
+			newNF("nfd", "c", 0),
+			newNF("tenant", "new", time.Minute),
+			newNF("tenant", "old", time.Hour),
+			newNF("tenant", "older", 2*time.Hour),
+			newNF("other", "x", 0),
+		}
+
+		names := func(objs []*nfdv1alpha1.NodeFeature) []string {
+			ret := []string{}
+			for _, o := range objs {
+				ret = append(ret, o.Namespace+"/"+o.Name)
+			}
+			sort.Strings(ret)
+			return ret
+		}
+
+		Convey("the newest excess objects should be ignored with an event", func() {
+			accepted := master.limitNodeFeatures(testNodeName, objs)
+			So(names(accepted), ShouldResemble, []string{"nfd/a", "nfd/b", "nfd/c", "other/x", "tenant/old", "tenant/older"})
+			So(recorder.Events, ShouldHaveLength, 1)
+			So(<-recorder.Events, ShouldContainSubstring, nodeFeatureLimitExceededReason)
+		})
+
+		Convey("no objects should be ignored if the limit is disabled", func() {
+			master.config.Restrictions.MaxNodeFeaturesPerNamespace = 0
+			So(master.limitNodeFeatures(testNodeName, objs), ShouldHaveLength, len(objs))
+			So(recorder.Events, ShouldBeEmpty)
+		})
+	})
+}
+
+func TestNamespacedNodeFeatureRules(t *testing.T) {
+	Convey("When processing NamespacedNodeFeatureRule objects", t, func() {
+		master := newFakeMaster()
+		overrides := `{"restrictions": {"namespacedRuleNodeSelectors": {"team-a": {"matchLabels": {"pool": "team-a"}}}}}`
+		So(master.configure("non-existing-file", overrides), ShouldBeNil)
+
+		newRule := func(ns, name, label string) *nfdv1alpha1.NamespacedNodeFeatureRule {
+			return &nfdv1alpha1.NamespacedNodeFeatureRule{
+				ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: name},
+				Spec: nfdv1alpha1.NodeFeatureRuleSpec{
+					Rules: []nfdv1alpha1.Rule{{Name: "rule", Labels: map[string]string{label: "true"}}},
+				},
+			}
+		}
+		indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+		So(indexer.Add(newRule("team-a", "rule-a", "example.io/team-a")), ShouldBeNil)
+		So(indexer.Add(newRule("team-b", "rule-b", "example.io/team-b")), ShouldBeNil)
+		master.nfdController = newFakeNfdAPIController(fakenfdclient.NewSimpleClientset())
+		master.nfdController.namespacedRuleLister = nfdlisters.NewNamespacedNodeFeatureRuleLister(indexer)
+
+		Convey("rules should only affect nodes matching the selector of the namespace", func() {
+			node := newTestNode()
+			node.Labels["pool"] = "team-a"
+			labels, _, _, _ := master.processNamespacedNodeFeatureRules(node, nfdv1alpha1.NewFeatures(), nil)
+			So(labels, ShouldResemble, Labels{"example.io/team-a": "true"})
+
+			node.Labels["pool"] = "team-b"
+			labels, _, _, _ = master.processNamespacedNodeFeatureRules(node, nfdv1alpha1.NewFeatures(), nil)
+			So(labels, ShouldBeNil)
+		})
+	})
+
+	Convey("When the namespace node selectors are invalid", t, func() {
+		master := newFakeMaster()
+		overrides := `{"restrictions": {"namespacedRuleNodeSelectors": {"team-a": null}}}`
+		So(master.configure("non-existing-file", overrides), ShouldNotBeNil)
+	})
+}
+
+func TestSuspendedRules(t *testing.T) {
+	Convey("When executing NodeFeatureRules", t, func() {
+		master := newFakeMaster()
+		spec := &nfdv1alpha1.NodeFeatureRuleSpec{
+			Rules: []nfdv1alpha1.Rule{
+				{Name: "rule-1", Labels: map[string]string{"example.io/rule-1": "true"}},
+				{Name: "rule-2", Labels: map[string]string{"example.io/rule-2": "true"}},
+			},
+		}
+		obj := &nfdv1alpha1.NodeFeatureRule{ObjectMeta: metav1.ObjectMeta{Name: "rules"}, Spec: *spec}
+		execute := func() (Labels, bool) {
+			labels := Labels{}
+			var taints []corev1.Taint
+			matched := master.executeRuleSpec(obj, &obj.Spec, testNodeName, nfdv1alpha1.NewFeatures(), labels, Annotations{}, ExtendedResources{}, &taints, nil)
+			return labels, matched
+		}
+
+		Convey("all rules should be executed by default", func() {
+			labels, matched := execute()
+			So(matched, ShouldBeTrue)
+			So(labels, ShouldResemble, Labels{"example.io/rule-1": "true", "example.io/rule-2": "true"})
+		})
+		Convey("disabled rules should be skipped", func() {
+			obj.Spec.Rules[0].Disabled = true
+			labels, matched := execute()
+			So(matched, ShouldBeTrue)
+			So(labels, ShouldResemble, Labels{"example.io/rule-2": "true"})
+		})
+		Convey("no rules of a suspended object should be executed", func() {
+			obj.Spec.Suspend = true
+			labels, matched := execute()
+			So(matched, ShouldBeFalse)
+			So(labels, ShouldBeEmpty)
+		})
+	})
+}
+
+func TestUpdateNodeInventory(t *testing.T) {
+	Convey("When recording the node inventory", t, func() {
+		nfdCli := fakenfdclient.NewSimpleClientset()
+		master := newFakeMaster(withNFDClient(nfdCli))
+		node := newTestNode()
+		node.Status.NodeInfo.SystemUUID = "EC2AB2D4-1234-5678-9ABC-DEF012345678"
+		name := "ec2ab2d4-1234-5678-9abc-def012345678"
+
+		Convey("NodeInventory object should be created and updated", func() {
+			So(master.updateNodeInventory(node, Labels{"feature.node.kubernetes.io/foo": "true"}), ShouldBeNil)
+			inv, err := nfdCli.NfdV1alpha1().NodeInventories().Get(context.TODO(), name, metav1.GetOptions{})
+			So(err, ShouldBeNil)
+			So(inv.Spec.NodeName, ShouldEqual, testNodeName)
+			So(inv.Spec.Labels, ShouldResemble, map[string]string{"feature.node.kubernetes.io/foo": "true"})
+
+			// Re-created node object with a different name
+			node.Name = "reprovisioned-node"
+			So(master.updateNodeInventory(node, Labels{"feature.node.kubernetes.io/bar": "true"}), ShouldBeNil)
+			inv, err = nfdCli.NfdV1alpha1().NodeInventories().Get(context.TODO(), name, metav1.GetOptions{})
+			So(err, ShouldBeNil)
+			So(inv.Spec.NodeName, ShouldEqual, "reprovisioned-node")
+			So(inv.Spec.Labels, ShouldResemble, map[string]string{"feature.node.kubernetes.io/bar": "true"})
+		})
+
+		Convey("nodes without a system UUID should be rejected", func() {
+			node.Status.NodeInfo.SystemUUID = ""
+			So(master.updateNodeInventory(node, Labels{}), ShouldNotBeNil)
+		})
+	})
+}
+
+func TestNodeMaintenance(t *testing.T) {
+	Convey("When pausing updates of nodes under maintenance", t, func() {
+		master := newFakeMaster()
+		overrides := `{"nodeMaintenance": {"skipCordoned": true, "annotation": "example.com/maintenance"}}`
+		So(master.configure("non-existing-file", overrides), ShouldBeNil)
+		So(master.config.NodeMaintenance.RecheckInterval.Duration, ShouldEqual, time.Minute)
+
+		node := newTestNode()
+		_, ok := nodeUnderMaintenance(node, master.config.NodeMaintenance)
+		So(ok, ShouldBeFalse)
+
+		node.Spec.Unschedulable = true
+		_, ok = nodeUnderMaintenance(node, master.config.NodeMaintenance)
+		So(ok, ShouldBeTrue)
+		_, ok = nodeUnderMaintenance(node, NodeMaintenanceConfig{})
+		So(ok, ShouldBeFalse)
+
+		node.Spec.Unschedulable = false
+		node.Annotations["example.com/maintenance"] = ""
+		_, ok = nodeUnderMaintenance(node, master.config.NodeMaintenance)
+		So(ok, ShouldBeTrue)
+
+		Convey("labels of cordoned nodes should not be updated", func() {
+			testNode := newTestNode()
+			testNode.Spec.Unschedulable = true
+			testNode.Labels[nfdv1alpha1.FeatureLabelNs+"/old-feature"] = "old-value"
+			testNode.Annotations[nfdv1alpha1.FeatureLabelsAnnotation] = "old-feature"
+
+			fakeCli := fakeclient.NewSimpleClientset(testNode)
+			master.k8sClient = fakeCli
+			master.nfdController = newFakeNfdAPIController(fakenfdclient.NewSimpleClientset())
+
+			So(master.nfdAPIUpdateOneNode(fakeCli, testNode), ShouldBeNil)
+			node, err := fakeCli.CoreV1().Nodes().Get(context.TODO(), testNodeName, metav1.GetOptions{})
+			So(err, ShouldBeNil)
+			So(node.Labels, ShouldContainKey, nfdv1alpha1.FeatureLabelNs+"/old-feature")
+
+			Convey("and updates should resume when the node is uncordoned", func() {
+				testNode.Spec.Unschedulable = false
+				So(master.nfdAPIUpdateOneNode(fakeCli, testNode), ShouldBeNil)
+				node, err := fakeCli.CoreV1().Nodes().Get(context.TODO(), testNodeName, metav1.GetOptions{})
+				So(err, ShouldBeNil)
+				So(node.Labels, ShouldNotContainKey, nfdv1alpha1.FeatureLabelNs+"/old-feature")
+			})
+		})
+	})
+}
+
+func TestRequireCreatorNode(t *testing.T) {
+	Convey("When requiring NodeFeature objects to be created by the target node", t, func() {
+		master := newFakeMaster()
+		master.config.Restrictions.RequireCreatorNode = true
+
+		newNF := func(name, creator string) *nfdv1alpha1.NodeFeature {
+			nf := &nfdv1alpha1.NodeFeature{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "nfd",
+					Name:      name,
+					Labels:    map[string]string{nfdv1alpha1.NodeFeatureObjNodeNameLabel: testNodeName},
+				},
+				Spec: nfdv1alpha1.NodeFeatureSpec{Labels: map[string]string{name: "true"}},
+			}
+			if creator != "" {
+				nf.Annotations = map[string]string{nfdv1alpha1.CreatorNodeAnnotation: creator}
+			}
+			return nf
+		}
+		indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+		So(indexer.Add(newNF("own", testNodeName)), ShouldBeNil)
+		So(indexer.Add(newNF("spoofed", "other-node")), ShouldBeNil)
+		So(indexer.Add(newNF("unknown", "")), ShouldBeNil)
+		master.nfdController = newFakeNfdAPIController(fakenfdclient.NewSimpleClientset())
+		master.nfdController.featureLister = nfdlisters.NewNodeFeatureLister(indexer)
+
+		Convey("only objects created by the node should be honored", func() {
+			nf, err := master.getAndMergeNodeFeatures(testNodeName, nil, nil)
+			So(err, ShouldBeNil)
+			So(nf.Spec.Labels, ShouldResemble, map[string]string{"own": "true"})
+		})
+
+		Convey("all objects should be honored if the restriction is disabled", func() {
+			master.config.Restrictions.RequireCreatorNode = false
+			nf, err := master.getAndMergeNodeFeatures(testNodeName, nil, nil)
+			So(err, ShouldBeNil)
+			So(nf.Spec.Labels, ShouldHaveLength, 3)
+		})
+	})
+}
+
+func TestMergeNodeFeatureRequests(t *testing.T) {
+	Convey("When merging NodeFeature objects that request annotations, extended resources and taints", t, func() {
+		master := newFakeMaster()
+		master.namespace = "nfd"
+
+		newNF := func(name string) *nfdv1alpha1.NodeFeature {
+			return &nfdv1alpha1.NodeFeature{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "nfd",
+					Name:      name,
+					Labels:    map[string]string{nfdv1alpha1.NodeFeatureObjNodeNameLabel: testNodeName},
+				},
+				Spec: nfdv1alpha1.NodeFeatureSpec{
+					Labels:            map[string]string{name: "true"},
+					Annotations:       map[string]string{name: "true"},
+					ExtendedResources: map[string]string{name: "1"},
+					Taints:            []corev1.Taint{{Key: "example.com/" + name, Effect: corev1.TaintEffectNoSchedule}},
+				},
+			}
+		}
+		indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+		So(indexer.Add(newNF(testNodeName)), ShouldBeNil)
+		So(indexer.Add(newNF("third-party")), ShouldBeNil)
+		master.nfdController = newFakeNfdAPIController(fakenfdclient.NewSimpleClientset())
+		master.nfdController.featureLister = nfdlisters.NewNodeFeatureLister(indexer)
+
+		Convey("requests of all objects should be merged", func() {
+			nf, err := master.getAndMergeNodeFeatures(testNodeName, nil, nil)
+			So(err, ShouldBeNil)
+			So(nf.Spec.Annotations, ShouldResemble, map[string]string{testNodeName: "true", "third-party": "true"})
+			So(nf.Spec.ExtendedResources, ShouldResemble, map[string]string{testNodeName: "1", "third-party": "1"})
+			So(nf.Spec.Taints, ShouldHaveLength, 2)
+		})
+
+		Convey("requests of third party objects should be dropped if denied", func() {
+			master.config.Restrictions.DenyNodeFeatureLabels = true
+			rejected := &RejectedItems{}
+			nf, err := master.getAndMergeNodeFeatures(testNodeName, rejected, nil)
+			So(err, ShouldBeNil)
+			So(nf.Spec.Labels, ShouldResemble, map[string]string{testNodeName: "true"})
+			So(nf.Spec.Annotations, ShouldResemble, map[string]string{testNodeName: "true"})
+			So(nf.Spec.ExtendedResources, ShouldResemble, map[string]string{testNodeName: "1"})
+			So(nf.Spec.Taints, ShouldResemble, []corev1.Taint{{Key: "example.com/" + testNodeName, Effect: corev1.TaintEffectNoSchedule}})
+			So(rejected.Labels.Count, ShouldEqual, 1)
+			So(rejected.Labels.Keys[0].Key, ShouldEqual, "third-party")
+			So(rejected.Annotations.Count, ShouldEqual, 1)
+			So(rejected.ExtendedResources.Count, ShouldEqual, 1)
+			So(rejected.Taints.Keys[0].Key, ShouldEqual, "example.com/third-party:NoSchedule")
+		})
+	})
+}
+
+func TestNfdAPIUpdateNodeFeatureGroup(t *testing.T) {
+	Convey("When updating the status of a NodeFeatureGroup", t, func() {
+		nodeNames := []string{"node-1", "node-2", "node-3"}
+		nodes := make([]runtime.Object, 0, len(nodeNames))
+		indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+		for i, name := range nodeNames {
+			nodes = append(nodes, &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name}})
+			nf := &nfdv1alpha1.NodeFeature{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "nfd",
+					Name:      name,
+					Labels:    map[string]string{nfdv1alpha1.NodeFeatureObjNodeNameLabel: name},
+				},
+				Spec: nfdv1alpha1.NodeFeatureSpec{Features: *nfdv1alpha1.NewFeatures()},
+			}
+			nf.Spec.Features.Attributes["system.name"] = nfdv1alpha1.NewAttributeFeatures(map[string]string{"nodename": name})
+			nf.Spec.Features.Attributes["fake.attr"] = nfdv1alpha1.NewAttributeFeatures(map[string]string{"index": strconv.Itoa(i)})
+			So(indexer.Add(nf), ShouldBeNil)
+		}
+
+		nfdCli := fakenfdclient.NewSimpleClientset()
+		master := newFakeMaster(WithKubernetesClient(fakeclient.NewSimpleClientset(nodes...)), withNFDClient(nfdCli))
+		master.nfdController = newFakeNfdAPIController(nfdCli)
+		master.nfdController.featureLister = nfdlisters.NewNodeFeatureLister(indexer)
+
+		term := func(op nfdv1alpha1.MatchOp, value ...string) nfdv1alpha1.FeatureMatcher {
+			return nfdv1alpha1.FeatureMatcher{{
+				Feature:          "fake.attr",
+				MatchExpressions: &nfdv1alpha1.MatchExpressionSet{"index": &nfdv1alpha1.MatchExpression{Op: op, Value: value}},
+			}}
+		}
+		nfg := &nfdv1alpha1.NodeFeatureGroup{
+			ObjectMeta: metav1.ObjectMeta{Namespace: master.namespace, Name: "group", Generation: 3},
+			Spec: nfdv1alpha1.NodeFeatureGroupSpec{
+				Rules: []nfdv1alpha1.GroupRule{
+					{Name: "rule-1", MatchFeatures: term(nfdv1alpha1.MatchIn, "0", "1")},
+					{Name: "rule-2", MatchFeatures: term(nfdv1alpha1.MatchExists)},
+				},
+			},
+		}
+		_, err := nfdCli.NfdV1alpha1().NodeFeatureGroups(master.namespace).Create(context.TODO(), nfg, metav1.CreateOptions{})
+		So(err, ShouldBeNil)
+
+		getStatus := func() nfdv1alpha1.NodeFeatureGroupStatus {
+			obj, err := nfdCli.NfdV1alpha1().NodeFeatureGroups(master.namespace).Get(context.TODO(), nfg.Name, metav1.GetOptions{})
+			So(err, ShouldBeNil)
+			return obj.Status
+		}
+		toNodes := func(names ...string) []nfdv1alpha1.FeatureGroupNode {
+			ret := make([]nfdv1alpha1.FeatureGroupNode, 0, len(names))
+			for _, n := range names {
+				ret = append(ret, nfdv1alpha1.FeatureGroupNode{Name: n})
+			}
+			return ret
+		}
+
+		Convey("observed generation and matching nodes should be reported", func() {
+			So(master.nfdAPIUpdateNodeFeatureGroup(nfdCli, nfg), ShouldBeNil)
+			status := getStatus()
+			So(status.ObservedGeneration, ShouldEqual, 3)
+			So(status.Nodes, ShouldResemble, toNodes(nodeNames...))
+			So(status.Rules, ShouldBeNil)
+		})
+
+		Convey("per-rule node lists should be reported if enabled", func() {
+			master.config.FeatureGroupStatus = FeatureGroupStatusConfig{RuleNodes: true}
+			So(master.nfdAPIUpdateNodeFeatureGroup(nfdCli, nfg), ShouldBeNil)
+			So(getStatus().Rules, ShouldResemble, []nfdv1alpha1.FeatureGroupRuleStatus{
+				{Name: "rule-1", Nodes: toNodes("node-1", "node-2"), NodeCount: 2},
+				{Name: "rule-2", Nodes: toNodes(nodeNames...), NodeCount: 3},
+			})
+		})
+
+		Convey("per-rule node lists should be truncated at the limit", func() {
+			master.config.FeatureGroupStatus = FeatureGroupStatusConfig{RuleNodes: true, MaxRuleNodes: 2}
+			So(master.nfdAPIUpdateNodeFeatureGroup(nfdCli, nfg), ShouldBeNil)
+			So(getStatus().Rules, ShouldResemble, []nfdv1alpha1.FeatureGroupRuleStatus{
+				{Name: "rule-1", Nodes: toNodes("node-1", "node-2"), NodeCount: 2},
+				{Name: "rule-2", Nodes: toNodes("node-1", "node-2"), NodeCount: 3, Truncated: true},
+			})
+		})
+
+		Convey("evaluation should be skipped if nothing has changed", func() {
+			getNfg := func() *nfdv1alpha1.NodeFeatureGroup {
+				obj, err := nfdCli.NfdV1alpha1().NodeFeatureGroups(master.namespace).Get(context.TODO(), nfg.Name, metav1.GetOptions{})
+				So(err, ShouldBeNil)
+				// The fake client does not maintain resource versions
+				obj.ResourceVersion = "1"
+				return obj
+			}
+			So(master.nfdAPIUpdateNodeFeatureGroup(nfdCli, getNfg()), ShouldBeNil)
+			skipped := testutil.ToFloat64(nodeFeatureGroupUpdatesSkipped)
+
+			So(master.nfdAPIUpdateNodeFeatureGroup(nfdCli, getNfg()), ShouldBeNil)
+			So(testutil.ToFloat64(nodeFeatureGroupUpdatesSkipped)-skipped, ShouldEqual, 1)
+
+			Convey("changed NodeFeatureGroup should be re-evaluated", func() {
+				obj := getNfg()
+				obj.ResourceVersion = "2"
+				So(master.nfdAPIUpdateNodeFeatureGroup(nfdCli, obj), ShouldBeNil)
+				So(testutil.ToFloat64(nodeFeatureGroupUpdatesSkipped)-skipped, ShouldEqual, 1)
+			})
+			Convey("changed node features should cause re-evaluation", func() {
+				nf, err := master.nfdController.featureLister.NodeFeatures("nfd").Get("node-3")
+				So(err, ShouldBeNil)
+				nf = nf.DeepCopy()
+				nf.Spec.Features.Attributes["fake.attr"].Elements["index"] = "1"
+				So(indexer.Update(nf), ShouldBeNil)
+				So(master.nfdAPIUpdateNodeFeatureGroup(nfdCli, getNfg()), ShouldBeNil)
+				So(testutil.ToFloat64(nodeFeatureGroupUpdatesSkipped)-skipped, ShouldEqual, 1)
+				So(master.nfdAPIUpdateNodeFeatureGroup(nfdCli, getNfg()), ShouldBeNil)
+				So(testutil.ToFloat64(nodeFeatureGroupUpdatesSkipped)-skipped, ShouldEqual, 2)
+			})
+			Convey("changed configuration should cause re-evaluation", func() {
+				master.config.FeatureGroupStatus = FeatureGroupStatusConfig{RuleNodes: true}
+				So(master.nfdAPIUpdateNodeFeatureGroup(nfdCli, getNfg()), ShouldBeNil)
+				So(testutil.ToFloat64(nodeFeatureGroupUpdatesSkipped)-skipped, ShouldEqual, 1)
+				So(getStatus().Rules, ShouldHaveLength, 2)
+			})
+		})
+	})
+}
+
+func TestNoExecuteTaintGate(t *testing.T) {
+	Convey("When filtering taints with the NoExecute taint gate", t, func() {
+		start := time.Now()
+		gate := newNoExecuteTaintGate()
+		gate.reset(start)
+
+		noSchedule := corev1.Taint{Key: "feature.node.kubernetes.io/a", Value: "true", Effect: corev1.TaintEffectNoSchedule}
+		noExecute := corev1.Taint{Key: "feature.node.kubernetes.io/b", Value: "true", Effect: corev1.TaintEffectNoExecute}
+		taints := []corev1.Taint{noSchedule, noExecute}
+		node := newTestNode()
+
+		config := NoExecuteTaintProtectionConfig{
+			WarmUpPeriod:      utils.DurationVal{Duration: time.Minute},
+			ConfirmationDelay: utils.DurationVal{Duration: 10 * time.Second},
+		}
+
+		Convey("All taints should pass if the protection is disabled", func() {
+			ret, requeue := gate.filter(node, taints, NoExecuteTaintProtectionConfig{}, start)
+			So(ret, ShouldResemble, taints)
+			So(requeue, ShouldEqual, 0)
+		})
+
+		Convey("New NoExecute taints should be dropped during the warm-up period", func() {
+			ret, requeue := gate.filter(node, taints, config, start.Add(20*time.Second))
+			So(ret, ShouldResemble, []corev1.Taint{noSchedule})
+			So(requeue, ShouldEqual, 40*time.Second)
+
+			Convey("NoExecute taints already on the node should be kept", func() {
+				node.Spec.Taints = []corev1.Taint{noExecute}
+				ret, requeue := gate.filter(node, taints, config, start.Add(20*time.Second))
+				So(ret, ShouldResemble, taints)
+				So(requeue, ShouldEqual, 0)
+			})
+		})
+
+		Convey("New NoExecute taints should require two consecutive evaluations after warm-up", func() {
+			ret, requeue := gate.filter(node, taints, config, start.Add(time.Minute))
+			So(ret, ShouldResemble, []corev1.Taint{noSchedule})
+			So(requeue, ShouldEqual, 10*time.Second)
+
+			ret, requeue = gate.filter(node, taints, config, start.Add(70*time.Second))
+			So(ret, ShouldResemble, taints)
+			So(requeue, ShouldEqual, 0)
+
+			Convey("Back-to-back evaluations should not confirm the taint before the delay", func() {
+				gate.filter(node, taints, config, start.Add(80*time.Second))
+				ret, requeue := gate.filter(node, taints, config, start.Add(81*time.Second))
+				So(ret, ShouldResemble, []corev1.Taint{noSchedule})
+				So(requeue, ShouldEqual, 9*time.Second)
+
+				ret, _ = gate.filter(node, taints, config, start.Add(90*time.Second))
+				So(ret, ShouldResemble, taints)
+			})
+
+			Convey("An evaluation without the taint should reset the confirmation", func() {
+				gate.filter(node, taints, config, start.Add(80*time.Second))
+				gate.filter(node, []corev1.Taint{noSchedule}, config, start.Add(90*time.Second))
+				ret, _ := gate.filter(node, taints, config, start.Add(100*time.Second))
+				So(ret, ShouldResemble, []corev1.Taint{noSchedule})
+			})
+		})
+	})
+}
+
+func TestNodeUpdater(t *testing.T) {
+	utilruntime.Must(features.NFDMutableFeatureGate.Add(features.DefaultNFDFeatureGates))
+
+	Convey("When updating a node directly with a NodeUpdater", t, func() {
+		testNode := newTestNode()
+		testNode.Labels[nfdv1alpha1.FeatureLabelNs+"/old-feature"] = "old-value"
+		testNode.Annotations[nfdv1alpha1.FeatureLabelsAnnotation] = "old-feature"
+		fakeCli := fakeclient.NewSimpleClientset(testNode)
+
+		configFile := t.TempDir() + "/nfd-master.conf"
+		So(os.WriteFile(configFile, []byte("denyLabelNs: [\"denied.example.com\"]\n"), 0644), ShouldBeNil)
+
+		u, err := NewNodeUpdater(fakeCli, testNodeName, configFile)
+		So(err, ShouldBeNil)
+
+		labels := map[string]string{
+			"feature-a":                  "true",
+			"vendor.example.com/feature": "1",
+			"denied.example.com/feature": "1",
+		}
+		spec := &nfdv1alpha1.NodeFeatureSpec{
+			Features:    *nfdv1alpha1.NewFeatures(),
+			Labels:      labels,
+			Annotations: map[string]string{"note": "value"},
+		}
+		So(u.UpdateNode(context.Background(), spec), ShouldBeNil)
+
+		Convey("labels should be filtered like in nfd-master", func() {
+			node, err := fakeCli.CoreV1().Nodes().Get(context.TODO(), testNodeName, metav1.GetOptions{})
+			So(err, ShouldBeNil)
+			So(node.Labels, ShouldResemble, map[string]string{
+				nfdv1alpha1.FeatureLabelNs + "/feature-a": "true",
+				"vendor.example.com/feature":              "1",
+			})
+			So(node.Annotations[nfdv1alpha1.FeatureLabelsAnnotation], ShouldEqual, "feature-a,vendor.example.com/feature")
+			So(labels, ShouldContainKey, "feature-a")
+		})
+
+		Convey("annotations of the spec should be created", func() {
+			node, err := fakeCli.CoreV1().Nodes().Get(context.TODO(), testNodeName, metav1.GetOptions{})
+			So(err, ShouldBeNil)
+			So(node.Annotations[nfdv1alpha1.FeatureAnnotationNs+"/note"], ShouldEqual, "value")
+			So(spec.Annotations, ShouldResemble, map[string]string{"note": "value"})
+		})
+
+		Convey("rejected labels should be recorded in an annotation", func() {
+			node, err := fakeCli.CoreV1().Nodes().Get(context.TODO(), testNodeName, metav1.GetOptions{})
+			So(err, ShouldBeNil)
+			So(node.Annotations[nfdv1alpha1.RejectedItemsAnnotation], ShouldEqual,
+				`{"labels":{"count":1,"keys":[{"key":"denied.example.com/feature","reason":"namespace \"denied.example.com\" is not allowed"}]}}`)
+		})
+	})
+
+	Convey("When the nfd-master configuration is invalid", t, func() {
+		configFile := t.TempDir() + "/nfd-master.conf"
+		So(os.WriteFile(configFile, []byte("trackingStorage: foo\n"), 0644), ShouldBeNil)
+
+		_, err := NewNodeUpdater(fakeclient.NewSimpleClientset(), testNodeName, configFile)
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestNodeFeatureCache(t *testing.T) {
+	Convey("When caching NodeFeature objects", t, func() {
+		nf := &nfdv1alpha1.NodeFeature{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:     "nfd",
+				Name:          testNodeName,
+				Labels:        map[string]string{nfdv1alpha1.NodeFeatureObjNodeNameLabel: testNodeName},
+				ManagedFields: []metav1.ManagedFieldsEntry{{Manager: "nfd-worker", Operation: metav1.ManagedFieldsOperationUpdate}},
+			},
+			Spec: nfdv1alpha1.NodeFeatureSpec{Features: *nfdv1alpha1.NewFeatures()},
+		}
+		nf.Spec.Features.Flags["fake.flag"] = nfdv1alpha1.NewFlagFeatures("a", "b")
+		nf.Spec.Features.Attributes["fake.attr"] = nfdv1alpha1.NewAttributeFeatures(map[string]string{"c": "1"})
+		nf.Spec.Features.Instances["fake.instance"] = nfdv1alpha1.NewInstanceFeatures(
+			*nfdv1alpha1.NewInstanceFeature(map[string]string{"d": "1", "e": "2"}),
+			*nfdv1alpha1.NewInstanceFeature(map[string]string{"d": "2"}))
+
+		Convey("managed fields should be stripped", func() {
+			obj, err := stripManagedFields(nf.DeepCopy())
+			So(err, ShouldBeNil)
+			So(obj.(*nfdv1alpha1.NodeFeature).ManagedFields, ShouldBeNil)
+			So(obj.(*nfdv1alpha1.NodeFeature).Labels, ShouldResemble, nf.Labels)
+		})
+
+		Convey("the number of feature elements should be reported", func() {
+			So(numFeatureElements(&nf.Spec.Features), ShouldEqual, 6)
+
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			So(indexer.Add(nf), ShouldBeNil)
+			master := newFakeMaster()
+			master.nfdController = newFakeNfdAPIController(fakenfdclient.NewSimpleClientset())
+			master.nfdController.featureLister = nfdlisters.NewNodeFeatureLister(indexer)
+
+			expected := `
+# HELP nfd_master_nodefeature_cache_elements Number of feature elements in the cached NodeFeature objects per namespace.
+# TYPE nfd_master_nodefeature_cache_elements gauge
+nfd_master_nodefeature_cache_elements{namespace="nfd"} 6
+`
+			So(testutil.CollectAndCompare(&nodeFeatureCollector{m: master}, strings.NewReader(expected), "nfd_master_nodefeature_cache_elements"), ShouldBeNil)
+		})
+	})
+}
+
+func TestExplainNode(t *testing.T) {
+	Convey("When explaining the labels of a node", t, func() {
+		testNode := newTestNode()
+		testNode.Labels[nfdv1alpha1.FeatureLabelNs+"/stale"] = "true"
+		testNode.Annotations[nfdv1alpha1.FeatureLabelsAnnotation] = "stale"
+		fakeCli := fakeclient.NewSimpleClientset(testNode)
+		master := newFakeMaster(
+			WithKubernetesClient(fakeCli),
+			withConfig(&NFDConfig{AutoDefaultNs: true, Restrictions: Restrictions{AllowOverwrite: true, RequireCreatorNode: true}}))
+
+		nf := &nfdv1alpha1.NodeFeature{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   "nfd",
+				Name:        testNodeName,
+				Labels:      map[string]string{nfdv1alpha1.NodeFeatureObjNodeNameLabel: testNodeName},
+				Annotations: map[string]string{nfdv1alpha1.CreatorNodeAnnotation: testNodeName},
+			},
+			Spec: nfdv1alpha1.NodeFeatureSpec{
+				Features: *nfdv1alpha1.NewFeatures(),
+				Labels:   map[string]string{"worker-label": "true"},
+			},
+		}
+		nf.Spec.Features.Attributes["kernel.version"] = nfdv1alpha1.NewAttributeFeatures(map[string]string{"major": "6"})
+		spoofed := nf.DeepCopy()
+		spoofed.Name = "spoofed"
+		spoofed.Annotations[nfdv1alpha1.CreatorNodeAnnotation] = "other-node"
+		indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+		So(indexer.Add(nf), ShouldBeNil)
+		So(indexer.Add(spoofed), ShouldBeNil)
+		master.nfdController = newFakeNfdAPIController(fakenfdclient.NewSimpleClientset())
+		master.nfdController.featureLister = nfdlisters.NewNodeFeatureLister(indexer)
+
+		newTerm := func(value string) nfdv1alpha1.FeatureMatcherTerm {
+			return nfdv1alpha1.FeatureMatcherTerm{
+				Feature: "kernel.version",
+				MatchExpressions: &nfdv1alpha1.MatchExpressionSet{
+					"major": &nfdv1alpha1.MatchExpression{Op: nfdv1alpha1.MatchIn, Value: nfdv1alpha1.MatchValue{value}},
+				},
+			}
+		}
+		rules := []*nfdv1alpha1.NodeFeatureRule{{
+			ObjectMeta: metav1.ObjectMeta{Name: "rules"},
+			Spec: nfdv1alpha1.NodeFeatureRuleSpec{
+				Rules: []nfdv1alpha1.Rule{
+					{Name: "match", Labels: map[string]string{"vendor.io/foo": "true"}, MatchFeatures: nfdv1alpha1.FeatureMatcher{newTerm("6")}},
+					{Name: "no-match", Labels: map[string]string{"bar": "true"}, ElseLabels: map[string]string{"no-bar": "true"},
+						MatchFeatures: nfdv1alpha1.FeatureMatcher{newTerm("6"), newTerm("5")}},
+					{Name: "disabled", Labels: map[string]string{"baz": "true"}, Disabled: true},
+				},
+			},
+		}}
+		master.ruleBundles = &ruleBundles{bundles: []*ruleBundle{{rules: rules}}}
+
+		e, err := master.explainNode(testNode, "")
+		So(err, ShouldBeNil)
+
+		Convey("the NodeFeature objects and their filtering should be reported", func() {
+			So(e.NodeFeatures, ShouldResemble, []string{"nfd/" + testNodeName})
+			So(e.IgnoredNodeFeatures, ShouldContainKey, "nfd/spoofed")
+		})
+
+		Convey("the rules should be evaluated with the master configuration", func() {
+			So(e.Rules, ShouldHaveLength, 3)
+			So(e.Rules[0].Status, ShouldEqual, RuleMatched)
+			So(e.Rules[0].Labels, ShouldResemble, map[string]string{"vendor.io/foo": "true"})
+			So(e.Rules[1].Status, ShouldEqual, RuleNotMatched)
+			So(e.Rules[1].UnmatchedTerms, ShouldResemble, []nfdv1alpha1.FeatureMatcherTerm{newTerm("5")})
+			So(e.Rules[1].Labels, ShouldResemble, map[string]string{nfdv1alpha1.FeatureLabelNs + "/no-bar": "true"})
+			So(e.Rules[2].Status, ShouldEqual, RuleDisabled)
+			So(e.Labels, ShouldResemble, map[string]string{
+				nfdv1alpha1.FeatureLabelNs + "/worker-label": "true",
+				nfdv1alpha1.FeatureLabelNs + "/no-bar":       "true",
+				"vendor.io/foo":                              "true",
+			})
+			So(e.NodeLabels, ShouldResemble, map[string]string{nfdv1alpha1.FeatureLabelNs + "/stale": "true"})
+		})
+
+		Convey("the node and the rule index should not be touched", func() {
+			So(master.nfdController.ruleNodes.nodesOf("rules"), ShouldBeEmpty)
+			for _, a := range fakeCli.Actions() {
+				So(a.GetVerb(), ShouldNotEqual, "patch")
+			}
+		})
+
+		Convey("the explanation should be limited to the given label", func() {
+			e, err := master.explainNode(testNode, nfdv1alpha1.FeatureLabelNs+"/bar")
+			So(err, ShouldBeNil)
+			So(e.Rules, ShouldHaveLength, 1)
+			So(e.Rules[0].Rule, ShouldEqual, "no-match")
+			So(e.Labels, ShouldBeEmpty)
+		})
+
+		Convey("the explanation should be served over HTTP", func() {
+			rec := httptest.NewRecorder()
+			master.explainHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, ExplainPath+"?node="+testNodeName, nil))
+			So(rec.Code, ShouldEqual, http.StatusOK)
+			served := &Explanation{}
+			So(json.Unmarshal(rec.Body.Bytes(), served), ShouldBeNil)
+			So(served.Labels, ShouldResemble, e.Labels)
+
+			rec = httptest.NewRecorder()
+			master.explainHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, ExplainPath+"?node=does-not-exist", nil))
+			So(rec.Code, ShouldEqual, http.StatusNotFound)
+		})
+	})
+}
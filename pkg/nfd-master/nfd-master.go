@@ -25,12 +25,15 @@ import (
 	"path"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"sort"
-	"strconv"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/net/context"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/health"
@@ -42,20 +45,26 @@ import (
 	k8sLabels "k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
+	k8svalidation "k8s.io/apimachinery/pkg/util/validation"
 	k8sclient "k8s.io/client-go/kubernetes"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	restclient "k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/leaderelection"
 	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 	controller "k8s.io/kubernetes/pkg/controller"
 	taintutils "k8s.io/kubernetes/pkg/util/taints"
 	"sigs.k8s.io/yaml"
 
 	nfdclientset "sigs.k8s.io/node-feature-discovery/api/generated/clientset/versioned"
+	nfdscheme "sigs.k8s.io/node-feature-discovery/api/generated/clientset/versioned/scheme"
 	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
 	"sigs.k8s.io/node-feature-discovery/pkg/apis/nfd/nodefeaturerule"
 	"sigs.k8s.io/node-feature-discovery/pkg/apis/nfd/validate"
 	nfdfeatures "sigs.k8s.io/node-feature-discovery/pkg/features"
+	nfddeviceplugin "sigs.k8s.io/node-feature-discovery/pkg/nfd-device-plugin"
+	"sigs.k8s.io/node-feature-discovery/pkg/nodepatch"
 	"sigs.k8s.io/node-feature-discovery/pkg/utils"
 	klogutils "sigs.k8s.io/node-feature-discovery/pkg/utils/klog"
 	"sigs.k8s.io/node-feature-discovery/pkg/version"
@@ -67,6 +76,18 @@ type Labels map[string]string
 // ExtendedResources are k8s extended resources which are created from discovered features.
 type ExtendedResources map[string]string
 
+// tracerName is the name of the OpenTelemetry tracer of nfd-master.
+const tracerName = "sigs.k8s.io/node-feature-discovery/pkg/nfd-master"
+
+const (
+	// ExtendedResourceModeNodeStatus publishes extended resources by
+	// patching the capacity in the node status.
+	ExtendedResourceModeNodeStatus = "NodeStatus"
+	// ExtendedResourceModeDevicePlugin publishes extended resources through
+	// nfd-device-plugin running on the nodes.
+	ExtendedResourceModeDevicePlugin = "DevicePlugin"
+)
+
 // Annotations are used for NFD-related node metadata
 type Annotations map[string]string
 
@@ -78,6 +99,24 @@ type Restrictions struct {
 	DisableAnnotations           bool
 	DenyNodeFeatureLabels        bool
 	AllowOverwrite               bool
+	// RequireCreatorNode makes nfd-master ignore NodeFeature objects that
+	// were not created with the identity of the node they are targeting.
+	RequireCreatorNode bool
+	// MaxNodeFeaturesPerNamespace is the maximum number of NodeFeature
+	// objects per node accepted from one namespace. Zero means unlimited.
+	MaxNodeFeaturesPerNamespace int
+	// NamespacedRuleNodeSelectors binds namespaces to node selectors.
+	// NamespacedNodeFeatureRule objects are only processed in the listed
+	// namespaces and only affect the nodes matching the selector of the
+	// namespace.
+	NamespacedRuleNodeSelectors map[string]*metav1.LabelSelector
+	// DeselectedNamespacePolicy specifies how NodeFeature objects of
+	// namespaces that stop matching NodeFeatureNamespaceSelector are
+	// handled. One of "Remove", "KeepUntilTTL" or "Keep".
+	DeselectedNamespacePolicy string
+	// DeselectedNamespaceTTL is the time NodeFeature objects of a deselected
+	// namespace are still processed with the KeepUntilTTL policy.
+	DeselectedNamespaceTTL utils.DurationVal
 }
 
 // NFDConfig contains the configuration settings of NfdMaster.
@@ -93,6 +132,125 @@ type NFDConfig struct {
 	NfdApiParallelism int
 	Klog              klogutils.KlogConfigOpts
 	Restrictions      Restrictions
+	EvaluationWebhook EvaluationWebhookConfig
+	TrackingStorage   string
+	// AnnotationNs is the namespace of the node annotations used by
+	// nfd-master for tracking the NFD-managed node properties.
+	AnnotationNs string
+	// ExtendedResourceMode specifies how extended resources are published,
+	// either by patching the node status or through nfd-device-plugin.
+	ExtendedResourceMode string
+	// NodeSelector selects the nodes managed by nfd-master. All nodes are
+	// managed if unset.
+	NodeSelector *metav1.LabelSelector
+	// ExcludeNodeSelector selects nodes that are never managed by
+	// nfd-master, even if they match NodeSelector.
+	ExcludeNodeSelector *metav1.LabelSelector
+	// FeatureGates enables or disables feature gates. Feature gates specified
+	// on the command line take precedence.
+	FeatureGates map[string]bool
+	// EnableNodeInventory enables recording the labels of each node in a
+	// NodeInventory object keyed by the system UUID of the machine.
+	EnableNodeInventory bool
+	// EnableNodeEvents enables emitting events on node objects when the
+	// labels, taints or extended resources managed by nfd-master change.
+	EnableNodeEvents bool
+	// FeatureGroupStatus contains the configuration of NodeFeatureGroup
+	// status updates.
+	FeatureGroupStatus FeatureGroupStatusConfig
+	// NodeFeatureGroupParallelism is the number of concurrent
+	// NodeFeatureGroup updaters. Zero means using NfdApiParallelism.
+	NodeFeatureGroupParallelism int
+	// NoExecuteTaintProtection contains the configuration for delaying the
+	// addition of new NoExecute taints.
+	NoExecuteTaintProtection NoExecuteTaintProtectionConfig
+	// NodeUpdateCoalescing contains the configuration for coalescing rapid
+	// successive NodeFeature changes of a node into a single node update.
+	NodeUpdateCoalescing NodeUpdateCoalescingConfig
+	// NodeMaintenance contains the configuration for pausing node updates
+	// while nodes are under maintenance.
+	NodeMaintenance NodeMaintenanceConfig
+	// RuleBundles contains signed bundles of NodeFeatureRule objects
+	// fetched periodically from HTTPS URLs or OCI registries.
+	RuleBundles []RuleBundleConfig
+	// LabelCompaction contains the configuration for folding boolean
+	// feature labels into a single node annotation.
+	LabelCompaction LabelCompactionConfig
+	// LabelConflicts contains the configuration for detecting NFD-managed
+	// labels that are repeatedly changed by someone else.
+	LabelConflicts LabelConflictsConfig
+}
+
+// FeatureGroupStatusConfig contains the configuration of NodeFeatureGroup
+// status updates.
+type FeatureGroupStatusConfig struct {
+	// RuleNodes enables reporting the nodes matched by each rule in the
+	// status of NodeFeatureGroup objects.
+	RuleNodes bool
+	// MaxRuleNodes is the maximum number of nodes listed per rule. A
+	// non-positive value disables the limit.
+	MaxRuleNodes int
+}
+
+// NoExecuteTaintProtectionConfig contains the configuration for protecting
+// against evictions caused by transiently missing feature data.
+type NoExecuteTaintProtectionConfig struct {
+	// WarmUpPeriod is the time after nfd-master startup during which no new
+	// NoExecute taints are added to nodes. Zero disables the warm-up period.
+	WarmUpPeriod utils.DurationVal
+	// ConfirmationDelay is the delay between two consecutive evaluations of
+	// a node that must both request a new NoExecute taint before it is added.
+	// Zero disables the confirmation.
+	ConfirmationDelay utils.DurationVal
+}
+
+// NodeUpdateCoalescingConfig contains the configuration for coalescing
+// NodeFeature changes into node updates.
+type NodeUpdateCoalescingConfig struct {
+	// Period is the quiet period after the latest NodeFeature change of a
+	// node before the node is updated. Zero disables coalescing.
+	Period utils.DurationVal
+	// MaxDelay is the maximum time a node update is postponed after the
+	// first coalesced NodeFeature change.
+	MaxDelay utils.DurationVal
+}
+
+// NodeMaintenanceConfig contains the configuration for pausing the updates
+// of nodes that are under maintenance.
+type NodeMaintenanceConfig struct {
+	// SkipCordoned pauses the updates of cordoned (unschedulable) nodes.
+	SkipCordoned bool
+	// Annotation is the name of a node annotation that pauses the updates of
+	// the node when present. Empty disables the annotation.
+	Annotation string
+	// RecheckInterval is the interval at which nodes with paused updates
+	// are re-checked.
+	RecheckInterval utils.DurationVal
+}
+
+// LabelCompactionConfig contains the configuration for folding boolean
+// feature labels into a single node annotation.
+type LabelCompactionConfig struct {
+	// Labels selects the labels with value "true" that are folded into the
+	// annotation, matched against the full label name. Compaction is
+	// disabled if unset.
+	Labels *regexp.Regexp
+	// KeepLabels selects labels that are published as node labels even if
+	// they are matched by Labels.
+	KeepLabels *regexp.Regexp
+}
+
+// LabelConflictsConfig contains the configuration for detecting NFD-managed
+// labels that are repeatedly changed or removed by someone else.
+type LabelConflictsConfig struct {
+	// Threshold is the number of changes by others within Window after
+	// which a label is reported as conflicting. Zero disables the detection.
+	Threshold int
+	// Window is the time window in which the changes are counted.
+	Window utils.DurationVal
+	// Backoff is the time nfd-master stops updating a conflicting label.
+	// Zero disables the backoff.
+	Backoff utils.DurationVal
 }
 
 // LeaderElectionConfig contains the configuration for leader election
@@ -126,7 +284,23 @@ type Args struct {
 	Prune                bool
 	Options              string
 	EnableLeaderElection bool
-	MetricsPort          int
+	// ShardCount enables sharding, splitting the nodes between ShardCount
+	// nfd-master instances. Zero disables sharding.
+	ShardCount int
+	// ShardIndex is the shard processed by this instance. A negative value
+	// means that the shard is claimed automatically with Lease objects.
+	ShardIndex  int
+	MetricsPort int
+	// MetricsCertFile and MetricsKeyFile enable TLS on the metrics server.
+	MetricsCertFile string
+	MetricsKeyFile  string
+	// EnableDebugEndpoints enables the debug endpoints (e.g. ExplainPath)
+	// on the metrics server.
+	EnableDebugEndpoints bool
+	// FeatureGates contains the feature gates specified on the command line.
+	FeatureGates map[string]bool
+	// Tracing contains the OpenTelemetry tracing options.
+	Tracing utils.TracingArgs
 
 	Overrides ConfigOverrideArgs
 }
@@ -158,16 +332,40 @@ type nfdMaster struct {
 	nfdClient      nfdclientset.Interface
 	updaterPool    *updaterPool
 	deniedNs
-	config *NFDConfig
+	config            *NFDConfig
+	evaluationWebhook *evaluationWebhook
+	ruleBundles       *ruleBundles
+	nodeReconciles    *reconcileTracker
+	ruleEvalCache     *ruleEvalCache
+	nfgEvalCache      *featureGroupEvalCache
+	eventBroadcaster  record.EventBroadcaster
+	eventRecorder     record.EventRecorder
+	noExecuteTaints   *noExecuteTaintGate
+	labelConflicts    *labelConflictTracker
+	// shard is the subset of nodes processed by this instance
+	shard shard
+
+	nodeSelector        labels.Selector
+	excludeNodeSelector labels.Selector
+	// namespacedRuleNodeSelectors contains the node selectors of the
+	// namespaces where NamespacedNodeFeatureRule objects are allowed
+	namespacedRuleNodeSelectors map[string]labels.Selector
+
+	staleTrackingConfigMaps staleTrackingConfigMaps
 }
 
 // NewNfdMaster creates a new NfdMaster server instance.
 func NewNfdMaster(opts ...NfdMasterOption) (NfdMaster, error) {
 	nfd := &nfdMaster{
-		nodeName:  utils.NodeName(),
-		namespace: utils.GetKubernetesNamespace(),
-		ready:     make(chan struct{}),
-		stop:      make(chan struct{}),
+		nodeName:        utils.NodeName(),
+		namespace:       utils.GetKubernetesNamespace(),
+		ready:           make(chan struct{}),
+		stop:            make(chan struct{}),
+		nodeReconciles:  newReconcileTracker(),
+		noExecuteTaints: newNoExecuteTaintGate(),
+		labelConflicts:  newLabelConflictTracker(),
+		ruleEvalCache:   newRuleEvalCache(),
+		nfgEvalCache:    newFeatureGroupEvalCache(),
 	}
 
 	for _, o := range opts {
@@ -245,25 +443,42 @@ func (f *nfdMasterOpt) apply(n *nfdMaster) {
 
 func newDefaultConfig() *NFDConfig {
 	return &NFDConfig{
-		DenyLabelNs:       utils.StringSetVal{},
-		ExtraLabelNs:      utils.StringSetVal{},
-		NoPublish:         false,
-		AutoDefaultNs:     true,
-		NfdApiParallelism: 10,
-		EnableTaints:      false,
-		ResyncPeriod:      utils.DurationVal{Duration: time.Duration(1) * time.Hour},
+		DenyLabelNs:          utils.StringSetVal{},
+		ExtraLabelNs:         utils.StringSetVal{},
+		NoPublish:            false,
+		AutoDefaultNs:        true,
+		NfdApiParallelism:    10,
+		EnableTaints:         false,
+		ResyncPeriod:         utils.DurationVal{Duration: time.Duration(1) * time.Hour},
+		TrackingStorage:      TrackingStorageAnnotations,
+		AnnotationNs:         nfdv1alpha1.AnnotationNs,
+		ExtendedResourceMode: ExtendedResourceModeNodeStatus,
 		LeaderElection: LeaderElectionConfig{
 			LeaseDuration: utils.DurationVal{Duration: time.Duration(15) * time.Second},
 			RetryPeriod:   utils.DurationVal{Duration: time.Duration(2) * time.Second},
 			RenewDeadline: utils.DurationVal{Duration: time.Duration(10) * time.Second},
 		},
 		Klog: make(map[string]string),
+		NodeUpdateCoalescing: NodeUpdateCoalescingConfig{
+			MaxDelay: utils.DurationVal{Duration: 10 * time.Second},
+		},
+		NodeMaintenance: NodeMaintenanceConfig{
+			RecheckInterval: utils.DurationVal{Duration: time.Minute},
+		},
+		LabelConflicts: LabelConflictsConfig{
+			Threshold: 3,
+			Window:    utils.DurationVal{Duration: 10 * time.Minute},
+		},
 		Restrictions: Restrictions{
-			DisableLabels:            false,
-			DisableExtendedResources: false,
-			DisableAnnotations:       false,
-			AllowOverwrite:           true,
-			DenyNodeFeatureLabels:    false,
+			DisableLabels:             false,
+			DisableExtendedResources:  false,
+			DisableAnnotations:        false,
+			AllowOverwrite:            true,
+			DenyNodeFeatureLabels:     false,
+			DeselectedNamespacePolicy: DeselectedNamespacePolicyRemove,
+		},
+		FeatureGroupStatus: FeatureGroupStatusConfig{
+			MaxRuleNodes: 1000,
 		},
 	}
 }
@@ -285,11 +500,44 @@ func (m *nfdMaster) Run() error {
 		return m.prune()
 	}
 
+	if err := m.validateSharding(); err != nil {
+		return err
+	}
+
+	shutdownTracing, err := utils.SetupTracing(context.Background(), "nfd-master", m.args.Tracing)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			klog.ErrorS(err, "failed to shut down tracing")
+		}
+	}()
+
+	m.startEventRecorder()
+
 	if err := m.startNfdApiController(); err != nil {
 		return err
 	}
 
-	m.updaterPool.start(m.config.NfdApiParallelism)
+	// The warm-up period of NoExecute taints starts when nfd-master starts
+	// processing nodes
+	m.noExecuteTaints.reset(time.Now())
+
+	m.updaterPool.start(m.config.NfdApiParallelism, m.nodeFeatureGroupParallelism())
+
+	if m.ruleBundles != nil && m.nfdController != nil {
+		m.ruleBundles.run(m.stop, m.nfdController.updateAllNodes)
+	}
+
+	// Watch for config file changes for hot-reloading feature gates
+	if m.configFilePath != "" {
+		if err := m.watchConfig(); err != nil {
+			klog.ErrorS(err, "failed to watch configuration file, configuration changes require a restart")
+		}
+	}
 
 	if !m.config.NoPublish {
 		err := m.updateMasterNode()
@@ -299,27 +547,48 @@ func (m *nfdMaster) Run() error {
 	}
 
 	// Register to metrics server
+	var httpServer *utils.HTTPServer
 	if m.args.MetricsPort > 0 {
-		m := utils.CreateMetricsServer(m.args.MetricsPort,
-			buildInfo,
-			nodeUpdateRequests,
-			nodeUpdates,
-			nodeUpdateFailures,
-			nodeLabelsRejected,
-			nodeERsRejected,
-			nodeTaintsRejected,
-			nfrProcessingTime,
-			nfrProcessingErrors)
-		go m.Run()
+		httpServer = utils.NewHTTPServer(m.args.MetricsPort,
+			utils.WithMetrics(
+				buildInfo,
+				nodeUpdateRequests,
+				nodeUpdates,
+				nodeUpdateFailures,
+				nodeFeatureGroupUpdateRequests,
+				nodeFeatureGroupUpdates,
+				nodeFeatureGroupUpdateFailures,
+				nodeFeatureGroupUpdatesSkipped,
+				nodeLabelsRejected,
+				nodeERsRejected,
+				nodeTaintsRejected,
+				nodeLabelConflictsDetected,
+				nfrProcessingTime,
+				nfrProcessingErrors,
+				evaluationWebhookErrors,
+				nodeUpdatesCoalesced,
+				ruleEvalCacheHits,
+				ruleEvalCacheMisses,
+				newNodeUpdatesPendingGauge(m.updaterPool),
+				newNodeLastAppliedOldestGauge(m.nodeReconciles),
+				&nodeFeatureCollector{m: m}),
+			utils.WithTLS(m.args.MetricsCertFile, m.args.MetricsKeyFile))
+		if m.args.EnableDebugEndpoints {
+			httpServer.Handle(ExplainPath, m.explainHandler())
+		}
+		go httpServer.Run()
 		registerVersion(version.Get())
-		defer m.Stop()
+		defer httpServer.Stop()
 	}
 
 	// Run updater that handles events from the nfd CRD API.
 	if m.nfdController != nil {
-		if m.args.EnableLeaderElection {
+		switch {
+		case m.args.ShardCount > 0:
+			go m.nfdAPIUpdateHandlerWithSharding()
+		case m.args.EnableLeaderElection:
 			go m.nfdAPIUpdateHandlerWithLeaderElection()
-		} else {
+		default:
 			go m.nfdAPIUpdateHandler()
 		}
 	}
@@ -334,6 +603,9 @@ func (m *nfdMaster) Run() error {
 
 	// Notify that we're ready to accept connections
 	close(m.ready)
+	if httpServer != nil {
+		httpServer.SetReady(true)
+	}
 
 	// NFD-Master main event loop
 	for {
@@ -348,6 +620,14 @@ func (m *nfdMaster) Run() error {
 	}
 }
 
+// startEventRecorder starts the recorder used for emitting events about the
+// NodeFeature objects processed by nfd-master.
+func (m *nfdMaster) startEventRecorder() {
+	m.eventBroadcaster = record.NewBroadcaster()
+	m.eventBroadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: m.k8sClient.CoreV1().Events("")})
+	m.eventRecorder = m.eventBroadcaster.NewRecorder(nfdscheme.Scheme, corev1.EventSource{Component: "nfd-master"})
+}
+
 // startGrpcHealthServer starts a gRPC health server for Kubernetes readiness/liveness probes.
 // TODO: improve status checking e.g. with watchdog in the main event loop and
 // cheking that node updater pool is alive.
@@ -389,6 +669,19 @@ func (m *nfdMaster) nfdAPIUpdateHandler() {
 			updateAll = true
 		case nodeName := <-m.nfdController.updateOneNodeChan:
 			updateNodes[nodeName] = struct{}{}
+		case nodeName := <-m.nfdController.nodeDeletedChan:
+			delete(updateNodes, nodeName)
+			m.updaterPool.cancelNode(nodeName)
+			m.purgeNode(nodeName)
+		case nodeName := <-m.nfdController.nodeCreatedChan:
+			// Drop any state left over from a previous node object of the
+			// same name and do a full update of the node right away
+			delete(updateNodes, nodeName)
+			m.updaterPool.cancelNode(nodeName)
+			m.purgeNode(nodeName)
+			if m.ownsNode(nodeName) {
+				m.updaterPool.addNode(nodeName)
+			}
 		case <-m.nfdController.updateAllNodeFeatureGroupsChan:
 			updateAllNodeFeatureGroups = true
 		case nodeFeatureGroupName := <-m.nfdController.updateNodeFeatureGroupChan:
@@ -403,7 +696,9 @@ func (m *nfdMaster) nfdAPIUpdateHandler() {
 				}
 			} else {
 				for nodeName := range updateNodes {
-					m.updaterPool.addNode(nodeName)
+					if m.ownsNode(nodeName) {
+						m.updaterPool.addNodeCoalesced(nodeName)
+					}
 				}
 			}
 			// NodeFeatureGroup
@@ -415,7 +710,9 @@ func (m *nfdMaster) nfdAPIUpdateHandler() {
 				}
 			} else {
 				for nodeFeatureGroupName := range nodeFeatureGroup {
-					m.updaterPool.addNodeFeatureGroup(nodeFeatureGroupName)
+					if m.shard.owns(nodeFeatureGroupName) {
+						m.updaterPool.addNodeFeatureGroup(nodeFeatureGroupName)
+					}
 				}
 			}
 
@@ -429,6 +726,17 @@ func (m *nfdMaster) nfdAPIUpdateHandler() {
 	}
 }
 
+// purgeNode drops the internal state kept about a node.
+func (m *nfdMaster) purgeNode(nodeName string) {
+	m.nodeReconciles.remove(nodeName)
+	m.noExecuteTaints.remove(nodeName)
+	m.labelConflicts.remove(nodeName)
+	m.ruleEvalCache.remove(nodeName)
+	if m.nfdController != nil && m.nfdController.ruleNodes != nil {
+		m.nfdController.ruleNodes.removeNode(nodeName)
+	}
+}
+
 // Stop NfdMaster
 func (m *nfdMaster) Stop() {
 	if m.server != nil {
@@ -444,6 +752,10 @@ func (m *nfdMaster) Stop() {
 
 	m.updaterPool.stop()
 
+	if m.eventBroadcaster != nil {
+		m.eventBroadcaster.Shutdown()
+	}
+
 	close(m.stop)
 }
 
@@ -473,7 +785,7 @@ func (m *nfdMaster) prune() error {
 		klog.InfoS("pruning node...", "nodeName", node.Name)
 
 		// Prune labels and extended resources
-		err := m.updateNodeObject(m.k8sClient, &node, Labels{}, Annotations{}, ExtendedResources{}, []corev1.Taint{})
+		err := m.updateNodeObject(m.k8sClient, &node, Labels{}, Annotations{}, ExtendedResources{}, []corev1.Taint{}, nil)
 		if err != nil {
 			nodeUpdateFailures.Inc()
 			return fmt.Errorf("failed to prune node %q: %v", node.Name, err)
@@ -485,7 +797,8 @@ func (m *nfdMaster) prune() error {
 			return err
 		}
 		maps.DeleteFunc(node.Annotations, func(k, v string) bool {
-			return strings.HasPrefix(k, m.instanceAnnotation(nfdv1alpha1.AnnotationNs))
+			return strings.HasPrefix(k, m.instanceAnnotation(nfdv1alpha1.AnnotationNs)) ||
+				strings.HasPrefix(k, m.trackingAnnotation(nfdv1alpha1.AnnotationNs))
 		})
 		_, err = m.k8sClient.CoreV1().Nodes().Update(context.TODO(), node, metav1.UpdateOptions{})
 		if err != nil {
@@ -506,7 +819,7 @@ func (m *nfdMaster) updateMasterNode() error {
 	}
 
 	// Advertise NFD version as an annotation
-	p := createPatches(sets.New([]string{m.instanceAnnotation(nfdv1alpha1.MasterVersionAnnotation)}...),
+	p := nodepatch.Items(sets.New([]string{m.instanceAnnotation(nfdv1alpha1.MasterVersionAnnotation)}...),
 		node.Annotations,
 		nil,
 		"/metadata/annotations", m.config.Restrictions.AllowOverwrite)
@@ -522,12 +835,13 @@ func (m *nfdMaster) updateMasterNode() error {
 // Filter labels by namespace and name whitelist, and, turn selected labels
 // into extended resources. This function also handles proper namespacing of
 // labels and ERs, i.e. adds the possibly missing default namespace for labels.
-func (m *nfdMaster) filterFeatureLabels(labels Labels, features *nfdv1alpha1.Features) Labels {
+func (m *nfdMaster) filterFeatureLabels(labels Labels, features *nfdv1alpha1.Features, rejected *RejectedItems) Labels {
 	outLabels := Labels{}
 	for name, value := range labels {
 		if value, err := m.filterFeatureLabel(name, value, features); err != nil {
 			klog.ErrorS(err, "ignoring label", "labelKey", name, "labelValue", value)
 			nodeLabelsRejected.Inc()
+			rejected.addLabel(name, err.Error())
 		} else {
 			outLabels[name] = value
 		}
@@ -535,6 +849,9 @@ func (m *nfdMaster) filterFeatureLabels(labels Labels, features *nfdv1alpha1.Fea
 
 	if len(outLabels) > 0 && m.config.Restrictions.DisableLabels {
 		klog.V(2).InfoS("node labels are disabled in configuration (restrictions.disableLabels=true)")
+		for name := range outLabels {
+			rejected.addLabel(name, "labels are disabled (restrictions.disableLabels=true)")
+		}
 		outLabels = Labels{}
 	}
 
@@ -592,13 +909,14 @@ func getDynamicValue(value string, features *nfdv1alpha1.Features) (string, erro
 	return element, nil
 }
 
-func filterTaints(taints []corev1.Taint) []corev1.Taint {
+func filterTaints(taints []corev1.Taint, rejected *RejectedItems) []corev1.Taint {
 	outTaints := []corev1.Taint{}
 
 	for _, taint := range taints {
 		if err := validate.Taint(&taint); err != nil {
 			klog.ErrorS(err, "ignoring taint", "taint", taint)
 			nodeTaintsRejected.Inc()
+			rejected.addTaint(taint.ToString(), err.Error())
 		} else {
 			outTaints = append(outTaints, taint)
 		}
@@ -629,16 +947,25 @@ func (m *nfdMaster) nfdAPIUpdateAllNodes() error {
 		return err
 	}
 
+	m.updateStaleTrackingConfigMaps()
+
+	nodeNames := sets.New[string]()
 	for _, node := range nodes.Items {
+		if !m.ownsNode(node.Name) {
+			continue
+		}
 		m.updaterPool.addNode(node.Name)
+		nodeNames.Insert(node.Name)
 	}
+	m.nodeReconciles.retain(nodeNames)
 
 	return nil
 }
 
 // getAndMergeNodeFeatures merges the NodeFeature objects of the given node into a single NodeFeatureSpec.
-// The Name field of the returned NodeFeatureSpec contains the node name.
-func (m *nfdMaster) getAndMergeNodeFeatures(nodeName string) (*nfdv1alpha1.NodeFeature, error) {
+// The Name field of the returned NodeFeatureSpec contains the node name. Items
+// denied by restrictions are recorded in rejected, if not nil.
+func (m *nfdMaster) getAndMergeNodeFeatures(nodeName string, rejected *RejectedItems, e *Explanation) (*nfdv1alpha1.NodeFeature, error) {
 	nodeFeatures := &nfdv1alpha1.NodeFeature{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: nodeName,
@@ -653,9 +980,26 @@ func (m *nfdMaster) getAndMergeNodeFeatures(nodeName string) (*nfdv1alpha1.NodeF
 
 	filteredObjs := []*nfdv1alpha1.NodeFeature{}
 	for _, obj := range objs {
-		if m.isNamespaceSelected(obj.Namespace) {
-			filteredObjs = append(filteredObjs, obj)
+		if !m.isNamespaceSelected(obj.Namespace) {
+			e.ignoreNodeFeature(obj, "namespace not selected (restrictions.nodeFeatureNamespaceSelector)")
+			continue
 		}
+		if m.config.Restrictions.RequireCreatorNode && obj.Annotations[nfdv1alpha1.CreatorNodeAnnotation] != nodeName {
+			klog.V(2).InfoS("ignoring NodeFeature object not created by the node it is targeting (restrictions.requireCreatorNode=true)", "nodefeature", klog.KObj(obj), "nodeName", nodeName, "creatorNode", obj.Annotations[nfdv1alpha1.CreatorNodeAnnotation])
+			e.ignoreNodeFeature(obj, "not created by the node (restrictions.requireCreatorNode=true)")
+			continue
+		}
+		filteredObjs = append(filteredObjs, obj)
+	}
+
+	if e != nil {
+		accepted, excess := splitByNamespaceLimit(filteredObjs, m.config.Restrictions.MaxNodeFeaturesPerNamespace, m.namespace)
+		for _, obj := range excess {
+			e.ignoreNodeFeature(obj, "exceeds the per-namespace limit (restrictions.maxNodeFeaturesPerNamespace)")
+		}
+		filteredObjs = accepted
+	} else {
+		filteredObjs = m.limitNodeFeatures(nodeName, filteredObjs)
 	}
 
 	// Node without a running NFD-Worker
@@ -680,45 +1024,72 @@ func (m *nfdMaster) getAndMergeNodeFeatures(nodeName string) (*nfdv1alpha1.NodeF
 		return filteredObjs[i].Namespace < filteredObjs[j].Namespace
 	})
 
+	for _, obj := range filteredObjs {
+		e.addNodeFeature(obj)
+	}
+
 	if len(filteredObjs) > 0 {
 		// Merge in features
 		//
 		// NOTE: changing the rule api to support handle multiple objects instead
 		// of merging would probably perform better with lot less data to copy.
-		features := filteredObjs[0].Spec.DeepCopy()
-
-		if m.config.Restrictions.DenyNodeFeatureLabels && m.isThirdPartyNodeFeature(*filteredObjs[0], nodeName, m.namespace) {
-			klog.V(2).InfoS("node feature labels are disabled in configuration (restrictions.denyNodeFeatureLabels=true)")
-			features.Labels = nil
-		}
-
-		if !nfdfeatures.NFDFeatureGate.Enabled(nfdfeatures.DisableAutoPrefix) && m.config.AutoDefaultNs {
-			features.Labels = addNsToMapKeys(features.Labels, nfdv1alpha1.FeatureLabelNs)
-		}
+		features := m.nodeFeatureSpec(filteredObjs[0], nodeName, rejected)
 
 		for _, o := range filteredObjs[1:] {
-			s := o.Spec.DeepCopy()
-			if m.config.Restrictions.DenyNodeFeatureLabels && m.isThirdPartyNodeFeature(*o, nodeName, m.namespace) {
-				klog.V(2).InfoS("node feature labels are disabled in configuration (restrictions.denyNodeFeatureLabels=true)")
-				s.Labels = nil
-			}
-
-			if !nfdfeatures.NFDFeatureGate.Enabled(nfdfeatures.DisableAutoPrefix) && m.config.AutoDefaultNs {
-				s.Labels = addNsToMapKeys(s.Labels, nfdv1alpha1.FeatureLabelNs)
-			}
-
-			s.MergeInto(features)
+			m.nodeFeatureSpec(o, nodeName, rejected).MergeInto(features)
 		}
 
 		// Set the merged features to the NodeFeature object
 		nodeFeatures.Spec = *features
 
+		// Continue the trace of the last update of nfd-worker
+		if tp := filteredObjs[0].Annotations[nfdv1alpha1.TraceParentAnnotation]; tp != "" && !m.isThirdPartyNodeFeature(*filteredObjs[0], nodeName, m.namespace) {
+			nodeFeatures.Annotations = map[string]string{nfdv1alpha1.TraceParentAnnotation: tp}
+		}
+
 		klog.V(4).InfoS("merged nodeFeatureSpecs", "newNodeFeatureSpec", utils.DelayedDumper(features))
 	}
 
 	return nodeFeatures, nil
 }
 
+// nodeFeatureSpec returns a copy of the spec of a NodeFeature object with the
+// configured restrictions and default namespaces applied.
+func (m *nfdMaster) nodeFeatureSpec(obj *nfdv1alpha1.NodeFeature, nodeName string, rejected *RejectedItems) *nfdv1alpha1.NodeFeatureSpec {
+	s := obj.Spec.DeepCopy()
+	if m.config.Restrictions.DenyNodeFeatureLabels && m.isThirdPartyNodeFeature(*obj, nodeName, m.namespace) {
+		klog.V(2).InfoS("node feature labels are disabled in configuration (restrictions.denyNodeFeatureLabels=true)")
+		reason := fmt.Sprintf("denied from NodeFeature %s/%s (restrictions.denyNodeFeatureLabels=true)", obj.Namespace, obj.Name)
+		for k := range s.Labels {
+			rejected.addLabel(k, reason)
+		}
+		for k := range s.Annotations {
+			rejected.addAnnotation(k, reason)
+		}
+		for k := range s.ExtendedResources {
+			rejected.addExtendedResource(k, reason)
+		}
+		for _, t := range s.Taints {
+			rejected.addTaint(t.ToString(), reason)
+		}
+		s.Labels = nil
+		s.Annotations = nil
+		s.ExtendedResources = nil
+		s.Taints = nil
+	}
+
+	if !nfdfeatures.NFDFeatureGate.Enabled(nfdfeatures.DisableAutoPrefix) && m.config.AutoDefaultNs {
+		s.Labels = addNsToMapKeys(s.Labels, nfdv1alpha1.FeatureLabelNs)
+		if s.Annotations != nil {
+			s.Annotations = addNsToMapKeys(s.Annotations, nfdv1alpha1.FeatureAnnotationNs)
+		}
+		if s.ExtendedResources != nil {
+			s.ExtendedResources = addNsToMapKeys(s.ExtendedResources, nfdv1alpha1.ExtendedResourceNs)
+		}
+	}
+	return s
+}
+
 // isThirdPartyNodeFeature determines whether a node feature is a third party one or created by nfd-worker
 func (m *nfdMaster) isThirdPartyNodeFeature(nodeFeature nfdv1alpha1.NodeFeature, nodeName, namespace string) bool {
 	return nodeFeature.Namespace != namespace || nodeFeature.Name != nodeName
@@ -729,22 +1100,59 @@ func (m *nfdMaster) nfdAPIUpdateOneNode(cli k8sclient.Interface, node *corev1.No
 		return nil
 	}
 
+	// Leave nodes under maintenance alone, re-checking them periodically
+	if reason, ok := nodeUnderMaintenance(node, m.config.NodeMaintenance); ok {
+		interval := m.config.NodeMaintenance.RecheckInterval.Duration
+		klog.V(1).InfoS("node update deferred, node is under maintenance", "nodeName", node.Name, "reason", reason, "recheckInterval", interval)
+		if interval > 0 && m.updaterPool.running() {
+			m.updaterPool.addNodeAfter(node.Name, interval)
+		}
+		return nil
+	}
+
+	// Remove all NFD-owned labels (et al.) from nodes that are not managed
+	// by us, e.g. after the node selectors have been changed
+	if !m.isNodeManaged(node) {
+		if m.config.NoPublish {
+			return nil
+		}
+		klog.V(2).InfoS("node excluded by node selectors, removing NFD-managed labels (et al.)", "nodeName", node.Name)
+		return m.updateNodeObject(cli, node, Labels{}, Annotations{}, ExtendedResources{}, []corev1.Taint{}, nil)
+	}
+
 	// Merge all NodeFeature objects into a single NodeFeatureSpec
-	nodeFeatures, err := m.getAndMergeNodeFeatures(node.Name)
+	rejected := &RejectedItems{}
+	nodeFeatures, err := m.getAndMergeNodeFeatures(node.Name, rejected, nil)
 	if err != nil {
 		return fmt.Errorf("failed to merge NodeFeature objects for node %q: %w", node.Name, err)
 	}
 
+	ctx := utils.ExtractTraceParent(context.Background(), nodeFeatures.Annotations[nfdv1alpha1.TraceParentAnnotation])
+	ctx, span := utils.StartSpan(ctx, tracerName, "UpdateNode", trace.WithAttributes(attribute.String("k8s.node.name", node.Name)))
+	defer span.End()
+
 	// Update node labels et al. This may also mean removing all NFD-owned
 	// labels (et al.), for example  in the case no NodeFeature objects are
 	// present.
-	if err := m.refreshNodeFeatures(cli, node, nodeFeatures.Spec.Labels, &nodeFeatures.Spec.Features); err != nil {
+	if err := m.refreshNodeFeatures(ctx, cli, node, &nodeFeatures.Spec, rejected); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
 		return err
 	}
 
 	return nil
 }
 
+// isNodeManaged returns true if the node is selected by the configured node
+// selectors, i.e. labels (et al.) of the node are managed by nfd-master.
+func (m *nfdMaster) isNodeManaged(node *corev1.Node) bool {
+	l := labels.Set(node.Labels)
+	if m.nodeSelector != nil && !m.nodeSelector.Matches(l) {
+		return false
+	}
+	return m.excludeNodeSelector == nil || !m.excludeNodeSelector.Matches(l)
+}
+
 func (m *nfdMaster) nfdAPIUpdateAllNodeFeatureGroups() error {
 	klog.V(1).InfoS("updating all NodeFeatureGroups")
 
@@ -755,7 +1163,9 @@ func (m *nfdMaster) nfdAPIUpdateAllNodeFeatureGroups() error {
 
 	if len(nodeFeatureGroupsList) > 0 {
 		for _, nodeFeatureGroup := range nodeFeatureGroupsList {
-			m.updaterPool.nfgQueue.Add(nodeFeatureGroup.Name)
+			if m.shard.owns(nodeFeatureGroup.Name) {
+				m.updaterPool.addNodeFeatureGroup(nodeFeatureGroup.Name)
+			}
 		}
 	} else {
 		klog.V(2).InfoS("no NodeFeatureGroup objects found")
@@ -777,8 +1187,11 @@ func (m *nfdMaster) nfdAPIUpdateNodeFeatureGroup(nfdClient nfdclientset.Interfac
 	}
 	nodeFeaturesList := make([]*nfdv1alpha1.NodeFeature, 0)
 	for _, node := range nodes.Items {
+		if !m.isNodeManaged(&node) {
+			continue
+		}
 		// Merge all NodeFeature objects into a single NodeFeatureSpec
-		nodeFeatures, err := m.getAndMergeNodeFeatures(node.Name)
+		nodeFeatures, err := m.getAndMergeNodeFeatures(node.Name, nil, nil)
 		if err != nil {
 			return fmt.Errorf("failed to merge NodeFeature objects for node %q: %w", node.Name, err)
 		}
@@ -786,13 +1199,28 @@ func (m *nfdMaster) nfdAPIUpdateNodeFeatureGroup(nfdClient nfdclientset.Interfac
 			// Nothing to do for this node
 			continue
 		}
+		m.insertNodeMetadata(&node, &nodeFeatures.Spec.Features)
 		nodeFeaturesList = append(nodeFeaturesList, nodeFeatures)
 	}
 
+	// Skip evaluation if neither the NodeFeatureGroup nor the features of
+	// the nodes have changed since the last evaluation
+	evalKey, err := featureGroupEvalCacheKey(nodeFeaturesList, m.config.FeatureGroupStatus)
+	if err != nil {
+		return fmt.Errorf("failed to calculate NodeFeatureGroup evaluation key: %w", err)
+	}
+	if m.nfgEvalCache.unchanged(nodeFeatureGroup.Name, nodeFeatureGroup.ResourceVersion, evalKey) {
+		klog.V(2).InfoS("no changes since last evaluation, skipping NodeFeatureGroup", "nodeFeatureGroup", klog.KObj(nodeFeatureGroup))
+		nodeFeatureGroupUpdatesSkipped.Inc()
+		return nil
+	}
+
 	// Execute rules and create matching groups
 	nodePool := make([]nfdv1alpha1.FeatureGroupNode, 0)
 	nodeGroupValidator := make(map[string]bool)
+	var ruleStatuses []nfdv1alpha1.FeatureGroupRuleStatus
 	for _, rule := range nodeFeatureGroup.Spec.Rules {
+		ruleStatus := nfdv1alpha1.FeatureGroupRuleStatus{Name: rule.Name}
 		for _, feature := range nodeFeaturesList {
 			match, err := nodefeaturerule.ExecuteGroupRule(&rule, &feature.Spec.Features, true)
 			if err != nil {
@@ -801,7 +1229,7 @@ func (m *nfdMaster) nfdAPIUpdateNodeFeatureGroup(nfdClient nfdclientset.Interfac
 			}
 
 			if match {
-				klog.ErrorS(err, "failed to evaluate rule", "ruleName", rule.Name, "nodeName", feature.Name)
+				klog.V(4).InfoS("NodeFeatureGroup rule matched", "ruleName", rule.Name, "nodeName", feature.Name)
 				system := feature.Spec.Features.Attributes["system.name"]
 				nodeName := system.Elements["nodename"]
 				if _, ok := nodeGroupValidator[nodeName]; !ok {
@@ -810,13 +1238,21 @@ func (m *nfdMaster) nfdAPIUpdateNodeFeatureGroup(nfdClient nfdclientset.Interfac
 					})
 					nodeGroupValidator[nodeName] = true
 				}
+				addFeatureGroupRuleNode(&ruleStatus, nodeName, m.config.FeatureGroupStatus.MaxRuleNodes)
 			}
 		}
+		ruleStatuses = append(ruleStatuses, ruleStatus)
 	}
 
 	// Update the NodeFeatureGroup object with the updated featureGroupRules
 	nodeFeatureGroupUpdated := nodeFeatureGroup.DeepCopy()
+	nodeFeatureGroupUpdated.Status.ObservedGeneration = nodeFeatureGroup.Generation
 	nodeFeatureGroupUpdated.Status.Nodes = nodePool
+	if m.config.FeatureGroupStatus.RuleNodes {
+		nodeFeatureGroupUpdated.Status.Rules = ruleStatuses
+	} else {
+		nodeFeatureGroupUpdated.Status.Rules = nil
+	}
 
 	if !apiequality.Semantic.DeepEqual(nodeFeatureGroup, nodeFeatureGroupUpdated) {
 		klog.InfoS("updating NodeFeatureGroup object", "nodeFeatureGroup", klog.KObj(nodeFeatureGroup))
@@ -825,22 +1261,38 @@ func (m *nfdMaster) nfdAPIUpdateNodeFeatureGroup(nfdClient nfdclientset.Interfac
 			return fmt.Errorf("failed to update NodeFeatureGroup object: %w", err)
 		}
 		klog.V(4).InfoS("NodeFeatureGroup object updated", "nodeFeatureGroup", utils.DelayedDumper(nodeFeatureGroupUpdated))
+		nodeFeatureGroupUpdates.Inc()
 	} else {
 		klog.V(1).InfoS("no changes in NodeFeatureGroup, object is up to date", "nodeFeatureGroup", klog.KObj(nodeFeatureGroup))
 	}
+	m.nfgEvalCache.set(nodeFeatureGroup.Name, nodeFeatureGroupUpdated.ResourceVersion, evalKey)
 
 	return nil
 }
 
+// addFeatureGroupRuleNode adds a node to the per-rule status of a
+// NodeFeatureGroup. The node count is always updated but the node list is
+// truncated at maxNodes entries to keep the size of the object in check in
+// big clusters. A non-positive maxNodes disables the limit.
+func addFeatureGroupRuleNode(s *nfdv1alpha1.FeatureGroupRuleStatus, nodeName string, maxNodes int) {
+	s.NodeCount++
+	if maxNodes > 0 && len(s.Nodes) >= maxNodes {
+		s.Truncated = true
+		return
+	}
+	s.Nodes = append(s.Nodes, nfdv1alpha1.FeatureGroupNode{Name: nodeName})
+}
+
 // filterExtendedResources filters extended resources and returns a map
 // of valid extended resources.
-func (m *nfdMaster) filterExtendedResources(features *nfdv1alpha1.Features, extendedResources ExtendedResources) ExtendedResources {
+func (m *nfdMaster) filterExtendedResources(features *nfdv1alpha1.Features, extendedResources ExtendedResources, rejected *RejectedItems) ExtendedResources {
 	outExtendedResources := ExtendedResources{}
 	for name, value := range extendedResources {
 		capacity, err := filterExtendedResource(name, value, features)
 		if err != nil {
 			klog.ErrorS(err, "failed to create extended resources", "extendedResourceName", name, "extendedResourceValue", value)
 			nodeERsRejected.Inc()
+			rejected.addExtendedResource(name, err.Error())
 		} else {
 			outExtendedResources[name] = capacity
 		}
@@ -852,11 +1304,15 @@ func filterExtendedResource(name, value string, features *nfdv1alpha1.Features)
 	// Dynamic Value
 	var filteredValue string
 	if strings.HasPrefix(value, "@") {
-		dynamicValue, err := getDynamicValue(value, features)
+		ref, conversion := nodefeaturerule.SplitUnitConversion(value)
+		dynamicValue, err := getDynamicValue(ref, features)
 		if err != nil {
 			return "", err
 		}
-		filteredValue = dynamicValue
+		filteredValue, err = nodefeaturerule.ConvertUnits(dynamicValue, conversion)
+		if err != nil {
+			return "", fmt.Errorf("failed to convert value of %s: %w", ref, err)
+		}
 	} else {
 		filteredValue = value
 	}
@@ -870,58 +1326,174 @@ func filterExtendedResource(name, value string, features *nfdv1alpha1.Features)
 	return filteredValue, nil
 }
 
-func (m *nfdMaster) refreshNodeFeatures(cli k8sclient.Interface, node *corev1.Node, labels map[string]string, features *nfdv1alpha1.Features) error {
+func (m *nfdMaster) refreshNodeFeatures(ctx context.Context, cli k8sclient.Interface, node *corev1.Node, spec *nfdv1alpha1.NodeFeatureSpec, rejected *RejectedItems) error {
+	out, err := m.evaluateNode(ctx, node, spec, rejected, nil)
+	if err != nil {
+		return err
+	}
+	labels := out.labels
+	taints := out.taints
+
+	if m.config.NoPublish {
+		klog.V(1).InfoS("node update skipped, NoPublish=true", "nodeName", node.Name)
+		return nil
+	}
+
+	if len(taints) > 0 {
+		var requeueAfter time.Duration
+		taints, requeueAfter = m.noExecuteTaints.filter(node, taints, m.config.NoExecuteTaintProtection, time.Now())
+		if requeueAfter > 0 && m.updaterPool.running() {
+			m.updaterPool.addNodeAfter(node.Name, requeueAfter)
+		}
+	}
+
+	_, updateSpan := utils.StartSpan(ctx, tracerName, "UpdateNodeObject")
+	err = m.updateNodeObject(cli, node, labels, out.annotations, out.extendedResources, taints, rejected)
+	updateSpan.End()
+	if err != nil {
+		klog.ErrorS(err, "failed to update node", "nodeName", node.Name)
+		return err
+	}
+
+	if m.config.EnableNodeInventory {
+		if err := m.updateNodeInventory(node, out.inventoryLabels); err != nil {
+			klog.ErrorS(err, "failed to update node inventory", "nodeName", node.Name)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// nodeOutputs are the labels, annotations, extended resources and taints
+// calculated for a node.
+type nodeOutputs struct {
+	labels            Labels
+	annotations       Annotations
+	extendedResources ExtendedResources
+	taints            []corev1.Taint
+	// inventoryLabels are the labels before label compaction
+	inventoryLabels Labels
+}
+
+// evaluateNode calculates the labels, annotations, extended resources and
+// taints of a node from its merged NodeFeature objects and the rules of the
+// cluster, filtered by the nfd-master configuration. The evaluation is
+// recorded in e, if non-nil.
+func (m *nfdMaster) evaluateNode(ctx context.Context, node *corev1.Node, spec *nfdv1alpha1.NodeFeatureSpec, rejected *RejectedItems, e *Explanation) (*nodeOutputs, error) {
+	features := &spec.Features
+	labels := maps.Clone(spec.Labels)
+	specAnnotations := maps.Clone(spec.Annotations)
+	specExtendedResources := maps.Clone(spec.ExtendedResources)
 	if !nfdfeatures.NFDFeatureGate.Enabled(nfdfeatures.DisableAutoPrefix) && m.config.AutoDefaultNs {
 		labels = addNsToMapKeys(labels, nfdv1alpha1.FeatureLabelNs)
+		specAnnotations = addNsToMapKeys(specAnnotations, nfdv1alpha1.FeatureAnnotationNs)
+		specExtendedResources = addNsToMapKeys(specExtendedResources, nfdv1alpha1.ExtendedResourceNs)
 	} else if labels == nil {
 		labels = make(map[string]string)
 	}
 
-	crLabels, crAnnotations, crExtendedResources, crTaints := m.processNodeFeatureRule(node.Name, features)
+	// Make the existing labels and annotations of the node available for
+	// matchNode matchers
+	m.insertNodeMetadata(node, features)
+
+	_, rulesSpan := utils.StartSpan(ctx, tracerName, "ProcessRules")
+	crLabels, crAnnotations, crExtendedResources, crTaints := m.processNodeFeatureRule(node.Name, features, e)
+
+	// Merge in outputs from NamespacedNodeFeatureRule objects. Outputs of
+	// the cluster-scoped NodeFeatureRule objects take precedence.
+	if nsLabels, nsAnnotations, nsExtendedResources, nsTaints := m.processNamespacedNodeFeatureRules(node, features, e); nsLabels != nil {
+		crLabels = mergeOutputs(nsLabels, crLabels)
+		crAnnotations = mergeOutputs(nsAnnotations, crAnnotations)
+		crExtendedResources = mergeOutputs(nsExtendedResources, crExtendedResources)
+		crTaints = append(nsTaints, crTaints...)
+	}
+
+	// Merge in outputs from the external evaluation webhook
+	if m.evaluationWebhook != nil {
+		out, err := m.processEvaluationWebhook(ctx, node.Name, features, labels, crLabels)
+		if err != nil {
+			rulesSpan.RecordError(err)
+			rulesSpan.End()
+			return nil, err
+		}
+		if e != nil {
+			e.EvaluationWebhook = out
+		}
+		if out != nil {
+			crLabels = mergeOutputs(crLabels, out.Labels)
+			crAnnotations = mergeOutputs(crAnnotations, out.Annotations)
+			crExtendedResources = mergeOutputs(crExtendedResources, out.ExtendedResources)
+			crTaints = append(crTaints, out.Taints...)
+		}
+	}
+	rulesSpan.End()
+
+	// Merge in annotations, extended resources and taints requested in the
+	// NodeFeature objects. Outputs of the rules take precedence.
+	crAnnotations = mergeOutputs(specAnnotations, crAnnotations)
+	crExtendedResources = mergeOutputs(specExtendedResources, crExtendedResources)
+	crTaints = append(slices.Clone(spec.Taints), crTaints...)
 
 	// Labels
 	maps.Copy(labels, crLabels)
-	labels = m.filterFeatureLabels(labels, features)
+	labels = m.filterFeatureLabels(labels, features, rejected)
 
 	// Extended resources
-	extendedResources := m.filterExtendedResources(features, crExtendedResources)
+	extendedResources := m.filterExtendedResources(features, crExtendedResources, rejected)
 
 	if len(extendedResources) > 0 && m.config.Restrictions.DisableExtendedResources {
 		klog.V(2).InfoS("extended resources are disabled in configuration (restrictions.disableExtendedResources=true)")
+		for name := range extendedResources {
+			rejected.addExtendedResource(name, "extended resources are disabled (restrictions.disableExtendedResources=true)")
+		}
 		extendedResources = map[string]string{}
 	}
 
 	// Annotations
-	annotations := m.filterFeatureAnnotations(crAnnotations)
+	annotations := m.filterFeatureAnnotations(crAnnotations, rejected)
+
+	// Fold boolean labels into an annotation. The node inventory still
+	// records all labels.
+	inventoryLabels := labels
+	if c := m.config.LabelCompaction; c.Labels != nil {
+		if m.config.Restrictions.DisableAnnotations {
+			klog.V(2).InfoS("label compaction skipped, annotations are disabled in configuration (restrictions.disableAnnotations=true)", "nodeName", node.Name)
+		} else if compacted, value, err := compactLabels(labels, c); err != nil {
+			klog.ErrorS(err, "failed to compact labels", "nodeName", node.Name)
+		} else if value != "" {
+			labels = compacted
+			annotations[nfdv1alpha1.CompactedLabelsAnnotation] = value
+		}
+	}
 
 	// Taints
 	var taints []corev1.Taint
 	if m.config.EnableTaints {
-		taints = filterTaints(crTaints)
-	}
-
-	if m.config.NoPublish {
-		klog.V(1).InfoS("node update skipped, NoPublish=true", "nodeName", node.Name)
-		return nil
-	}
-
-	err := m.updateNodeObject(cli, node, labels, annotations, extendedResources, taints)
-	if err != nil {
-		klog.ErrorS(err, "failed to update node", "nodeName", node.Name)
-		return err
+		taints = filterTaints(crTaints, rejected)
+	} else {
+		for _, t := range crTaints {
+			rejected.addTaint(t.ToString(), "taints are disabled (enableTaints=false)")
+		}
 	}
 
-	return nil
+	return &nodeOutputs{
+		labels:            labels,
+		annotations:       annotations,
+		extendedResources: extendedResources,
+		taints:            taints,
+		inventoryLabels:   inventoryLabels,
+	}, nil
 }
 
 // setTaints sets node taints and annotations based on the taints passed via
 // nodeFeatureRule custom resorce. If empty list of taints is passed, currently
 // NFD owned taints and annotations are removed from the node.
-func (m *nfdMaster) setTaints(cli k8sclient.Interface, taints []corev1.Taint, node *corev1.Node) error {
+func (m *nfdMaster) setTaints(cli k8sclient.Interface, taints []corev1.Taint, node *corev1.Node, tracking *nodeTracking) error {
 	// De-serialize the taints annotation into corev1.Taint type for comparision below.
 	var err error
 	oldTaints := []corev1.Taint{}
-	if val, ok := node.Annotations[nfdv1alpha1.NodeTaintsAnnotation]; ok {
+	if val, ok := node.Annotations[m.taintsAnnotation()]; ok {
 		sts := strings.Split(val, ",")
 		oldTaints, _, err = taintutils.ParseTaints(sts)
 		if err != nil {
@@ -955,13 +1527,6 @@ func (m *nfdMaster) setTaints(cli k8sclient.Interface, taints []corev1.Taint, no
 		taintsUpdated = taintsUpdated || updated
 	}
 
-	if taintsUpdated {
-		if err := controller.PatchNodeTaints(context.TODO(), cli, node.Name, node, newNode); err != nil {
-			return fmt.Errorf("failed to patch the node %v", node.Name)
-		}
-		klog.InfoS("updated node taints", "nodeName", node.Name)
-	}
-
 	// Update node annotation that holds the taints managed by us
 	newAnnotations := map[string]string{}
 	if len(taints) > 0 {
@@ -971,24 +1536,39 @@ func (m *nfdMaster) setTaints(cli k8sclient.Interface, taints []corev1.Taint, no
 		for _, taint := range taints {
 			taintStrs = append(taintStrs, taint.ToString())
 		}
-		newAnnotations[nfdv1alpha1.NodeTaintsAnnotation] = strings.Join(taintStrs, ",")
+		newAnnotations[m.taintsAnnotation()] = strings.Join(taintStrs, ",")
 	}
 
-	patches := createPatches(sets.New([]string{nfdv1alpha1.NodeTaintsAnnotation}...),
+	patches := nodepatch.Items(sets.New([]string{m.taintsAnnotation()}...),
 		node.Annotations, newAnnotations,
 		"/metadata/annotations",
 		m.config.Restrictions.AllowOverwrite,
 	)
+	patches = tracking.apply(patches)
+
+	// Record the new taints in the tracking ConfigMap before tainting the node
+	if err := tracking.prepareConfigMap(cli); err != nil {
+		return err
+	}
+
+	if taintsUpdated {
+		if err := controller.PatchNodeTaints(context.TODO(), cli, node.Name, node, newNode); err != nil {
+			return fmt.Errorf("failed to patch the node %v", node.Name)
+		}
+		klog.InfoS("updated node taints", "nodeName", node.Name)
+		m.recordNodeEvent(node.Name, nodeTaintsChangedReason, "Feature taints", taintChanges(oldTaints, taints))
+	}
+
 	if len(patches) > 0 {
 		if err := patchNode(cli, node.Name, patches); err != nil {
 			return fmt.Errorf("error while patching node object: %w", err)
 		}
 		klog.V(1).InfoS("patched node annotations for taints", "nodeName", node.Name)
 	}
-	return nil
+	return tracking.writeConfigMap(cli)
 }
 
-func (m *nfdMaster) processNodeFeatureRule(nodeName string, features *nfdv1alpha1.Features) (Labels, Annotations, ExtendedResources, []corev1.Taint) {
+func (m *nfdMaster) processNodeFeatureRule(nodeName string, features *nfdv1alpha1.Features, e *Explanation) (Labels, Annotations, ExtendedResources, []corev1.Taint) {
 	if m.nfdController == nil {
 		return nil, nil, nil, nil
 	}
@@ -998,17 +1578,41 @@ func (m *nfdMaster) processNodeFeatureRule(nodeName string, features *nfdv1alpha
 	annotations := make(map[string]string)
 	var taints []corev1.Taint
 	ruleSpecs, err := m.nfdController.ruleLister.List(k8sLabels.Everything())
+	if err != nil {
+		klog.ErrorS(err, "failed to list NodeFeatureRule resources")
+		return nil, nil, nil, nil
+	}
+	ruleSpecs = m.mergeBundledRules(ruleSpecs)
 	sort.Slice(ruleSpecs, func(i, j int) bool {
 		return ruleSpecs[i].Name < ruleSpecs[j].Name
 	})
 
-	if err != nil {
-		klog.ErrorS(err, "failed to list NodeFeatureRule resources")
-		return nil, nil, nil, nil
+	// Use the cached result if neither the features of the node nor the rules
+	// have changed since the previous evaluation. Explanations always
+	// evaluate the rules and leave the cache and the rule index untouched.
+	var cacheKey string
+	if e == nil {
+		autoPrefix := !nfdfeatures.NFDFeatureGate.Enabled(nfdfeatures.DisableAutoPrefix) && m.config.AutoDefaultNs
+		cacheKey, err = ruleEvalCacheKey(features, ruleSpecs, autoPrefix)
+		if err != nil {
+			klog.ErrorS(err, "failed to calculate rule evaluation cache key", "nodeName", nodeName)
+		} else if r := m.ruleEvalCache.get(nodeName, cacheKey); r != nil {
+			klog.V(2).InfoS("using cached NodeFeatureRule evaluation result", "nodeName", nodeName, "objectCount", len(ruleSpecs))
+			ruleEvalCacheHits.Inc()
+			if r.backrefs != nil {
+				features.InsertAttributeFeatures(nfdv1alpha1.RuleBackrefDomain, nfdv1alpha1.RuleBackrefFeature, r.backrefs)
+			}
+			if m.nfdController.ruleNodes != nil {
+				m.nfdController.ruleNodes.setNode(nodeName, r.matchedRules)
+			}
+			return r.labels, r.annotations, r.extendedResources, r.taints
+		}
+		ruleEvalCacheMisses.Inc()
 	}
 
 	// Process all rule CRs
 	processStart := time.Now()
+	matchedRules := sets.New[string]()
 	for _, spec := range ruleSpecs {
 		t := time.Now()
 		switch {
@@ -1017,45 +1621,113 @@ func (m *nfdMaster) processNodeFeatureRule(nodeName string, features *nfdv1alpha
 		case klog.V(1).Enabled():
 			klog.InfoS("executing NodeFeatureRule", "nodefeaturerule", klog.KObj(spec), "nodeName", nodeName)
 		}
-		for _, rule := range spec.Spec.Rules {
-			ruleOut, err := nodefeaturerule.Execute(&rule, features, true)
-			if err != nil {
-				klog.ErrorS(err, "failed to process rule", "ruleName", rule.Name, "nodefeaturerule", klog.KObj(spec), "nodeName", nodeName)
-				nfrProcessingErrors.Inc()
-				continue
-			}
-			taints = append(taints, ruleOut.Taints...)
-
-			l := ruleOut.Labels
-			e := ruleOut.ExtendedResources
-			a := ruleOut.Annotations
-			if !nfdfeatures.NFDFeatureGate.Enabled(nfdfeatures.DisableAutoPrefix) && m.config.AutoDefaultNs {
-				l = addNsToMapKeys(ruleOut.Labels, nfdv1alpha1.FeatureLabelNs)
-				e = addNsToMapKeys(ruleOut.ExtendedResources, nfdv1alpha1.ExtendedResourceNs)
-				a = addNsToMapKeys(ruleOut.Annotations, nfdv1alpha1.FeatureAnnotationNs)
-			}
-			maps.Copy(labels, l)
-			maps.Copy(extendedResources, e)
-			maps.Copy(annotations, a)
-
-			// Feed back rule output to features map for subsequent rules to match
-			features.InsertAttributeFeatures(nfdv1alpha1.RuleBackrefDomain, nfdv1alpha1.RuleBackrefFeature, ruleOut.Labels)
-			features.InsertAttributeFeatures(nfdv1alpha1.RuleBackrefDomain, nfdv1alpha1.RuleBackrefFeature, ruleOut.Vars)
+		if m.executeRuleSpec(spec, &spec.Spec, nodeName, features, labels, annotations, extendedResources, &taints, e) {
+			matchedRules.Insert(spec.Name)
 		}
 		nfrProcessingTime.WithLabelValues(spec.Name, nodeName).Observe(time.Since(t).Seconds())
 	}
 	processingTime := time.Since(processStart)
 	klog.V(2).InfoS("processed NodeFeatureRule objects", "nodeName", nodeName, "objectCount", len(ruleSpecs), "duration", processingTime)
 
+	if m.nfdController.ruleNodes != nil && e == nil {
+		m.nfdController.ruleNodes.setNode(nodeName, matchedRules)
+	}
+
+	if cacheKey != "" {
+		r := &ruleEvalResult{
+			key:               cacheKey,
+			labels:            labels,
+			annotations:       annotations,
+			extendedResources: extendedResources,
+			taints:            taints,
+			matchedRules:      matchedRules,
+		}
+		if backrefs, ok := features.Attributes[nfdv1alpha1.RuleBackrefDomain+"."+nfdv1alpha1.RuleBackrefFeature]; ok {
+			r.backrefs = backrefs.Elements
+		}
+		m.ruleEvalCache.set(nodeName, r)
+	}
+
 	return labels, annotations, extendedResources, taints
 }
 
+// executeRuleSpec executes the rules of one rule object, accumulating the
+// outputs into labels, annotations, extendedResources and taints. Suspended
+// objects and disabled rules are skipped. Rule outputs are fed back to
+// features for subsequent rules to match. Returns true if any of the rules
+// matched and produced output.
+func (m *nfdMaster) executeRuleSpec(obj klog.KMetadata, spec *nfdv1alpha1.NodeFeatureRuleSpec, nodeName string, features *nfdv1alpha1.Features, labels, annotations, extendedResources map[string]string, taints *[]corev1.Taint, e *Explanation) bool {
+	if spec.Suspend {
+		klog.V(2).InfoS("skipping suspended rule object", "object", klog.KObj(obj), "nodeName", nodeName)
+		for _, rule := range spec.Rules {
+			e.addRule(RuleExplanation{Object: klog.KObj(obj).String(), Rule: rule.Name, Status: RuleSuspended}, &rule)
+		}
+		return false
+	}
+
+	matched := false
+	for _, rule := range spec.Rules {
+		if rule.Disabled {
+			klog.V(3).InfoS("skipping disabled rule", "ruleName", rule.Name, "object", klog.KObj(obj), "nodeName", nodeName)
+			e.addRule(RuleExplanation{Object: klog.KObj(obj).String(), Rule: rule.Name, Status: RuleDisabled}, &rule)
+			continue
+		}
+		ruleOut, err := nodefeaturerule.Execute(&rule, features, true)
+		if err != nil {
+			klog.ErrorS(err, "failed to process rule", "ruleName", rule.Name, "object", klog.KObj(obj), "nodeName", nodeName)
+			nfrProcessingErrors.Inc()
+			e.addRule(RuleExplanation{Object: klog.KObj(obj).String(), Rule: rule.Name, Status: RuleError, Error: err.Error()}, &rule)
+			continue
+		}
+		*taints = append(*taints, ruleOut.Taints...)
+
+		// The outputs of the else branch of a rule do not count as a match
+		if ruleOut.MatchStatus.IsMatch && (len(ruleOut.Labels) > 0 || len(ruleOut.Annotations) > 0 ||
+			len(ruleOut.ExtendedResources) > 0 || len(ruleOut.Taints) > 0 || len(ruleOut.Vars) > 0) {
+			matched = true
+		}
+
+		l := ruleOut.Labels
+		er := ruleOut.ExtendedResources
+		a := ruleOut.Annotations
+		if !nfdfeatures.NFDFeatureGate.Enabled(nfdfeatures.DisableAutoPrefix) && m.config.AutoDefaultNs {
+			l = addNsToMapKeys(ruleOut.Labels, nfdv1alpha1.FeatureLabelNs)
+			er = addNsToMapKeys(ruleOut.ExtendedResources, nfdv1alpha1.ExtendedResourceNs)
+			a = addNsToMapKeys(ruleOut.Annotations, nfdv1alpha1.FeatureAnnotationNs)
+		}
+		maps.Copy(labels, l)
+		maps.Copy(extendedResources, er)
+		maps.Copy(annotations, a)
+		if e != nil {
+			e.addRuleResult(obj, &rule, ruleOut, l, a, er, features)
+		}
+
+		// Feed back rule output to features map for subsequent rules to match
+		features.InsertAttributeFeatures(nfdv1alpha1.RuleBackrefDomain, nfdv1alpha1.RuleBackrefFeature, ruleOut.Labels)
+		features.InsertAttributeFeatures(nfdv1alpha1.RuleBackrefDomain, nfdv1alpha1.RuleBackrefFeature, ruleOut.Vars)
+	}
+	return matched
+}
+
 // updateNodeObject ensures the Kubernetes node object is up to date,
 // creating new labels and extended resources where necessary and removing
 // outdated ones. Also updates the corresponding annotations.
-func (m *nfdMaster) updateNodeObject(cli k8sclient.Interface, node *corev1.Node, labels Labels, featureAnnotations Annotations, extendedResources ExtendedResources, taints []corev1.Taint) error {
+func (m *nfdMaster) updateNodeObject(cli k8sclient.Interface, node *corev1.Node, labels Labels, featureAnnotations Annotations, extendedResources ExtendedResources, taints []corev1.Taint, rejected *RejectedItems) error {
+	// Use a view of the node object with the tracking information merged
+	// into the annotations, regardless of where it is stored
+	node, tracking, err := m.loadNodeTracking(cli, node)
+	if err != nil {
+		return err
+	}
+
 	annotations := make(Annotations)
 
+	// Detect labels fought over with someone else
+	labels, labelConflicts := m.labelConflicts.check(node, labels, m.config.LabelConflicts, time.Now())
+	if len(labelConflicts) > 0 {
+		annotations[m.trackingAnnotation(nfdv1alpha1.LabelConflictsAnnotation)] = labelConflictsAnnotationValue(labelConflicts)
+	}
+
 	// Store names of labels in an annotation
 	if len(labels) > 0 {
 		labelKeys := make([]string, 0, len(labels))
@@ -1064,7 +1736,7 @@ func (m *nfdMaster) updateNodeObject(cli k8sclient.Interface, node *corev1.Node,
 			labelKeys = append(labelKeys, strings.TrimPrefix(key, nfdv1alpha1.FeatureLabelNs+"/"))
 		}
 		sort.Strings(labelKeys)
-		annotations[m.instanceAnnotation(nfdv1alpha1.FeatureLabelsAnnotation)] = strings.Join(labelKeys, ",")
+		annotations[m.trackingAnnotation(nfdv1alpha1.FeatureLabelsAnnotation)] = strings.Join(labelKeys, ",")
 	}
 
 	// Store names of extended resources in an annotation
@@ -1075,7 +1747,12 @@ func (m *nfdMaster) updateNodeObject(cli k8sclient.Interface, node *corev1.Node,
 			extendedResourceKeys = append(extendedResourceKeys, strings.TrimPrefix(key, nfdv1alpha1.FeatureLabelNs+"/"))
 		}
 		sort.Strings(extendedResourceKeys)
-		annotations[m.instanceAnnotation(nfdv1alpha1.ExtendedResourceAnnotation)] = strings.Join(extendedResourceKeys, ",")
+		annotations[m.trackingAnnotation(nfdv1alpha1.ExtendedResourceAnnotation)] = strings.Join(extendedResourceKeys, ",")
+
+		// Store the values for nfd-device-plugin
+		if m.config.ExtendedResourceMode == ExtendedResourceModeDevicePlugin {
+			annotations[m.trackingAnnotation(nfdv1alpha1.ExtendedResourceValuesAnnotation)] = nfddeviceplugin.FormatExtendedResourceValues(extendedResources)
+		}
 	}
 
 	// Store feature annotations
@@ -1087,26 +1764,72 @@ func (m *nfdMaster) updateNodeObject(cli k8sclient.Interface, node *corev1.Node,
 			annotationKeys = append(annotationKeys, strings.TrimPrefix(key, nfdv1alpha1.FeatureAnnotationNs+"/"))
 		}
 		sort.Strings(annotationKeys)
-		annotations[m.instanceAnnotation(nfdv1alpha1.FeatureAnnotationsTrackingAnnotation)] = strings.Join(annotationKeys, ",")
+		annotations[m.trackingAnnotation(nfdv1alpha1.FeatureAnnotationsTrackingAnnotation)] = strings.Join(annotationKeys, ",")
 		maps.Copy(annotations, featureAnnotations)
 	}
 
+	// Store the rejected labels (et al.) in an annotation
+	if value, err := rejected.annotationValue(); err != nil {
+		klog.ErrorS(err, "failed to serialize rejected items", "nodeName", node.Name)
+	} else if value != "" {
+		annotations[m.trackingAnnotation(nfdv1alpha1.RejectedItemsAnnotation)] = value
+	}
+
 	// Create JSON patches for changes in labels and annotations
-	oldLabels := stringToNsNames(node.Annotations[m.instanceAnnotation(nfdv1alpha1.FeatureLabelsAnnotation)], nfdv1alpha1.FeatureLabelNs)
-	oldAnnotations := stringToNsNames(node.Annotations[m.instanceAnnotation(nfdv1alpha1.FeatureAnnotationsTrackingAnnotation)], nfdv1alpha1.FeatureAnnotationNs)
-	patches := createPatches(sets.New(oldLabels...), node.Labels, labels, "/metadata/labels", m.config.Restrictions.AllowOverwrite)
+	oldLabels := stringToNsNames(node.Annotations[m.trackingAnnotation(nfdv1alpha1.FeatureLabelsAnnotation)], nfdv1alpha1.FeatureLabelNs)
+	oldAnnotations := stringToNsNames(node.Annotations[m.trackingAnnotation(nfdv1alpha1.FeatureAnnotationsTrackingAnnotation)], nfdv1alpha1.FeatureAnnotationNs)
+	patches := nodepatch.Items(sets.New(oldLabels...), node.Labels, labels, "/metadata/labels", m.config.Restrictions.AllowOverwrite)
+	labelChanges := patchChanges(patches, "/metadata/labels", node.Labels)
+	applied := appliedLabels(node, labels, patches)
 	oldAnnotations = append(oldAnnotations, []string{
-		m.instanceAnnotation(nfdv1alpha1.FeatureLabelsAnnotation),
-		m.instanceAnnotation(nfdv1alpha1.ExtendedResourceAnnotation),
-		m.instanceAnnotation(nfdv1alpha1.FeatureAnnotationsTrackingAnnotation),
+		m.trackingAnnotation(nfdv1alpha1.FeatureLabelsAnnotation),
+		m.trackingAnnotation(nfdv1alpha1.ExtendedResourceAnnotation),
+		m.trackingAnnotation(nfdv1alpha1.FeatureAnnotationsTrackingAnnotation),
+		m.trackingAnnotation(nfdv1alpha1.ExtendedResourceValuesAnnotation),
+		m.trackingAnnotation(nfdv1alpha1.RejectedItemsAnnotation),
+		m.trackingAnnotation(nfdv1alpha1.LabelConflictsAnnotation),
 		// Clean up deprecated/stale nfd version annotations
 		m.instanceAnnotation(nfdv1alpha1.MasterVersionAnnotation),
 		m.instanceAnnotation(nfdv1alpha1.WorkerVersionAnnotation)}...)
-	patches = append(patches, createPatches(sets.New(oldAnnotations...), node.Annotations, annotations, "/metadata/annotations", m.config.Restrictions.AllowOverwrite)...)
+	if m.config.AnnotationNs != "" && m.config.AnnotationNs != nfdv1alpha1.AnnotationNs {
+		// Clean up untracked annotations from the default annotation namespace
+		oldAnnotations = append(oldAnnotations,
+			m.instanceAnnotation(nfdv1alpha1.ExtendedResourceValuesAnnotation),
+			m.instanceAnnotation(nfdv1alpha1.LastAppliedTimeAnnotation))
+	}
+	patches = append(patches, nodepatch.Items(sets.New(oldAnnotations...), node.Annotations, annotations, "/metadata/annotations", m.config.Restrictions.AllowOverwrite)...)
 
 	// patch node status with extended resource changes
-	statusPatches := m.createExtendedResourcePatches(node, extendedResources)
-	err := patchNodeStatus(cli, node.Name, statusPatches)
+	var statusPatches []utils.JsonPatch
+	if m.config.ExtendedResourceMode == ExtendedResourceModeDevicePlugin {
+		// Extended resources are advertised to the kubelet by
+		// nfd-device-plugin. Only clean up extended resources previously
+		// created in the node status that are not needed anymore.
+		statusPatches = m.createExtendedResourceRemovePatches(node, extendedResources)
+	} else {
+		statusPatches = m.createExtendedResourcePatches(node, extendedResources)
+	}
+	capacity := make(map[string]string, len(node.Status.Capacity))
+	for name, quantity := range node.Status.Capacity {
+		capacity[string(name)] = quantity.String()
+	}
+	extendedResourceChanges := patchChanges(statusPatches, "/status/capacity", capacity)
+
+	// Divert tracking information to the configured storage
+	patches = tracking.apply(patches)
+
+	// Record the time of applying the changes
+	if len(patches) > 0 || len(statusPatches) > 0 {
+		patches = append(patches, utils.NewJsonPatch("add", "/metadata/annotations",
+			m.trackingAnnotation(nfdv1alpha1.LastAppliedTimeAnnotation), time.Now().UTC().Format(time.RFC3339)))
+	}
+
+	// Record the new tracking information before modifying the node
+	if err := tracking.prepareConfigMap(cli); err != nil {
+		return err
+	}
+
+	err = patchNodeStatus(cli, node.Name, statusPatches)
 	if err != nil {
 		return fmt.Errorf("error while patching extended resources: %w", err)
 	}
@@ -1116,89 +1839,52 @@ func (m *nfdMaster) updateNodeObject(cli k8sclient.Interface, node *corev1.Node,
 	if err != nil {
 		return fmt.Errorf("error while patching node object: %w", err)
 	}
+	m.labelConflicts.setApplied(node.Name, applied)
 
 	if len(patches) > 0 || len(statusPatches) > 0 {
 		nodeUpdates.Inc()
 		klog.InfoS("node updated", "nodeName", node.Name)
+		m.recordNodeEvent(node.Name, nodeLabelsChangedReason, "Feature labels", labelChanges)
+		m.recordNodeEvent(node.Name, nodeExtendedResourcesChangedReason, "Extended resources", extendedResourceChanges)
 	} else {
 		klog.V(1).InfoS("no updates to node", "nodeName", node.Name)
 	}
 
-	// Set taints
-	err = m.setTaints(cli, taints, node)
-	if err != nil {
+	if err := tracking.writeConfigMap(cli); err != nil {
 		return err
 	}
 
-	return err
-}
-
-// createPatches is a generic helper that returns json patch operations to perform
-func createPatches(removeKeys sets.Set[string], oldItems map[string]string, newItems map[string]string, jsonPath string, overwrite bool) []utils.JsonPatch {
-	patches := []utils.JsonPatch{}
-
-	// Determine items to remove
-	for key := range removeKeys {
-		if _, ok := oldItems[key]; ok {
-			if _, ok := newItems[key]; !ok {
-				patches = append(patches, utils.NewJsonPatch("remove", jsonPath, key, ""))
-			}
-		}
-	}
-
-	// Determine items to add or replace
-	for key, newVal := range newItems {
-		if oldVal, ok := oldItems[key]; ok {
-			if newVal != oldVal && (!removeKeys.Has(key) || overwrite) {
-				patches = append(patches, utils.NewJsonPatch("replace", jsonPath, key, newVal))
-			}
-		} else {
-			patches = append(patches, utils.NewJsonPatch("add", jsonPath, key, newVal))
-		}
+	// Set taints
+	err = m.setTaints(cli, taints, node, tracking)
+	if err != nil {
+		return err
 	}
 
-	return patches
+	return tracking.deleteStaleConfigMap(cli)
 }
 
 // createExtendedResourcePatches returns a slice of operations to perform on
 // the node status
 func (m *nfdMaster) createExtendedResourcePatches(n *corev1.Node, extendedResources ExtendedResources) []utils.JsonPatch {
-	patches := []utils.JsonPatch{}
-
-	// Form a list of namespaced resource names managed by us
-	oldResources := stringToNsNames(n.Annotations[m.instanceAnnotation(nfdv1alpha1.ExtendedResourceAnnotation)], nfdv1alpha1.FeatureLabelNs)
-
-	// figure out which resources to remove
-	for _, resource := range oldResources {
-		if _, ok := n.Status.Capacity[corev1.ResourceName(resource)]; ok {
-			// check if the ext resource is still needed
-			if _, extResNeeded := extendedResources[resource]; !extResNeeded {
-				patches = append(patches, utils.NewJsonPatch("remove", "/status/capacity", resource, ""))
-				patches = append(patches, utils.NewJsonPatch("remove", "/status/allocatable", resource, ""))
-			}
-		}
-	}
+	return nodepatch.ExtendedResources(n, m.managedExtendedResources(n), extendedResources)
+}
 
-	// figure out which resources to replace and which to add
-	for resource, value := range extendedResources {
-		// check if the extended resource already exists with the same capacity in the node
-		if quantity, ok := n.Status.Capacity[corev1.ResourceName(resource)]; ok {
-			val, _ := quantity.AsInt64()
-			if strconv.FormatInt(val, 10) != value {
-				patches = append(patches, utils.NewJsonPatch("replace", "/status/capacity", resource, value))
-				patches = append(patches, utils.NewJsonPatch("replace", "/status/allocatable", resource, value))
-			}
-		} else {
-			patches = append(patches, utils.NewJsonPatch("add", "/status/capacity", resource, value))
-			// "allocatable" gets added implicitly after adding to capacity
-		}
-	}
+// createExtendedResourceRemovePatches returns the operations for removing the
+// extended resources managed by us that are not needed anymore from the node
+// status.
+func (m *nfdMaster) createExtendedResourceRemovePatches(n *corev1.Node, extendedResources ExtendedResources) []utils.JsonPatch {
+	return nodepatch.ExtendedResourceRemovals(n, m.managedExtendedResources(n), extendedResources)
+}
 
-	return patches
+// managedExtendedResources returns the namespaced names of the extended
+// resources managed by us, as recorded in the tracking annotation.
+func (m *nfdMaster) managedExtendedResources(n *corev1.Node) []string {
+	return stringToNsNames(n.Annotations[m.trackingAnnotation(nfdv1alpha1.ExtendedResourceAnnotation)], nfdv1alpha1.FeatureLabelNs)
 }
 
-// Parse configuration options
-func (m *nfdMaster) configure(filepath string, overrides string) error {
+// parseConfig reads the configuration file and applies the overrides from the
+// command line.
+func (m *nfdMaster) parseConfig(filepath string, overrides string) (*NFDConfig, error) {
 	// Create a new default config
 	c := newDefaultConfig()
 
@@ -1209,12 +1895,12 @@ func (m *nfdMaster) configure(filepath string, overrides string) error {
 			if os.IsNotExist(err) {
 				klog.InfoS("config file not found, using defaults", "path", filepath)
 			} else {
-				return fmt.Errorf("error reading config file: %w", err)
+				return nil, fmt.Errorf("error reading config file: %w", err)
 			}
 		} else {
 			err = yaml.Unmarshal(data, c)
 			if err != nil {
-				return fmt.Errorf("failed to parse config file: %w", err)
+				return nil, fmt.Errorf("failed to parse config file: %w", err)
 			}
 
 			klog.InfoS("configuration file parsed", "path", filepath)
@@ -1223,7 +1909,7 @@ func (m *nfdMaster) configure(filepath string, overrides string) error {
 
 	// Parse config overrides
 	if err := yaml.Unmarshal([]byte(overrides), c); err != nil {
-		return fmt.Errorf("failed to parse -options: %w", err)
+		return nil, fmt.Errorf("failed to parse -options: %w", err)
 	}
 	if m.args.Overrides.NoPublish != nil {
 		c.NoPublish = *m.args.Overrides.NoPublish
@@ -1247,11 +1933,118 @@ func (m *nfdMaster) configure(filepath string, overrides string) error {
 		c.NfdApiParallelism = *m.args.Overrides.NfdApiParallelism
 	}
 
+	return c, nil
+}
+
+// nodeFeatureGroupParallelism returns the number of concurrent
+// NodeFeatureGroup updaters.
+func (m *nfdMaster) nodeFeatureGroupParallelism() int {
+	if m.config.NodeFeatureGroupParallelism > 0 {
+		return m.config.NodeFeatureGroupParallelism
+	}
+	return m.config.NfdApiParallelism
+}
+
+// Parse configuration options
+func (m *nfdMaster) configure(filepath string, overrides string) error {
+	c, err := m.parseConfig(filepath, overrides)
+	if err != nil {
+		return err
+	}
+
 	if c.NfdApiParallelism <= 0 {
 		return fmt.Errorf("the maximum number of concurrent labelers should be a non-zero positive number")
 	}
+	if c.NodeFeatureGroupParallelism < 0 {
+		return fmt.Errorf("the number of concurrent NodeFeatureGroup updaters must not be negative")
+	}
+
+	if c.LabelConflicts.Threshold > 0 && c.LabelConflicts.Window.Duration <= 0 {
+		return fmt.Errorf("labelConflicts.window must be positive when label conflict detection is enabled")
+	}
+
+	switch c.TrackingStorage {
+	case TrackingStorageAnnotations, TrackingStorageConfigMap:
+	default:
+		return fmt.Errorf("invalid trackingStorage %q, must be one of %q or %q", c.TrackingStorage, TrackingStorageAnnotations, TrackingStorageConfigMap)
+	}
+
+	if errs := k8svalidation.IsDNS1123Subdomain(c.AnnotationNs); len(errs) > 0 {
+		return fmt.Errorf("invalid annotationNs %q: %s", c.AnnotationNs, strings.Join(errs, "; "))
+	}
+
+	switch c.ExtendedResourceMode {
+	case ExtendedResourceModeNodeStatus, ExtendedResourceModeDevicePlugin:
+	default:
+		return fmt.Errorf("invalid extendedResourceMode %q, must be one of %q or %q", c.ExtendedResourceMode, ExtendedResourceModeNodeStatus, ExtendedResourceModeDevicePlugin)
+	}
+
+	nodeSelector := labels.Everything()
+	if c.NodeSelector != nil {
+		sel, err := metav1.LabelSelectorAsSelector(c.NodeSelector)
+		if err != nil {
+			return fmt.Errorf("invalid nodeSelector: %w", err)
+		}
+		nodeSelector = sel
+	}
+	excludeNodeSelector := labels.Nothing()
+	if c.ExcludeNodeSelector != nil {
+		sel, err := metav1.LabelSelectorAsSelector(c.ExcludeNodeSelector)
+		if err != nil {
+			return fmt.Errorf("invalid excludeNodeSelector: %w", err)
+		}
+		excludeNodeSelector = sel
+	}
+
+	namespacedRuleNodeSelectors := make(map[string]labels.Selector, len(c.Restrictions.NamespacedRuleNodeSelectors))
+	for ns, s := range c.Restrictions.NamespacedRuleNodeSelectors {
+		if s == nil {
+			return fmt.Errorf("invalid namespacedRuleNodeSelectors: empty node selector for namespace %q", ns)
+		}
+		sel, err := metav1.LabelSelectorAsSelector(s)
+		if err != nil {
+			return fmt.Errorf("invalid namespacedRuleNodeSelectors for namespace %q: %w", ns, err)
+		}
+		namespacedRuleNodeSelectors[ns] = sel
+	}
+
+	switch c.Restrictions.DeselectedNamespacePolicy {
+	case DeselectedNamespacePolicyRemove, DeselectedNamespacePolicyKeep:
+	case DeselectedNamespacePolicyKeepUntilTTL:
+		if c.Restrictions.DeselectedNamespaceTTL.Duration <= 0 {
+			return fmt.Errorf("restrictions.deselectedNamespaceTTL must be positive with restrictions.deselectedNamespacePolicy=%s", DeselectedNamespacePolicyKeepUntilTTL)
+		}
+	default:
+		return fmt.Errorf("invalid restrictions.deselectedNamespacePolicy %q, must be one of %q, %q or %q", c.Restrictions.DeselectedNamespacePolicy,
+			DeselectedNamespacePolicyRemove, DeselectedNamespacePolicyKeepUntilTTL, DeselectedNamespacePolicyKeep)
+	}
+
+	if _, err := m.configureFeatureGates(c.FeatureGates, false); err != nil {
+		return err
+	}
 
 	m.config = c
+	m.nodeSelector = nodeSelector
+	m.excludeNodeSelector = excludeNodeSelector
+	m.namespacedRuleNodeSelectors = namespacedRuleNodeSelectors
+
+	m.evaluationWebhook = nil
+	if c.EvaluationWebhook.URL != "" {
+		w, err := newEvaluationWebhook(c.EvaluationWebhook)
+		if err != nil {
+			return err
+		}
+		m.evaluationWebhook = w
+	}
+
+	m.ruleBundles = nil
+	if len(c.RuleBundles) > 0 {
+		b, err := newRuleBundles(c.RuleBundles)
+		if err != nil {
+			return err
+		}
+		m.ruleBundles = b
+	}
 
 	if err := klogutils.MergeKlogConfiguration(m.args.Klog, c.Klog); err != nil {
 		return err
@@ -1335,10 +2128,19 @@ func preProcessDeniedNamespaces(deniedNs map[string]struct{}) (normalDeniedNs ma
 }
 
 func (m *nfdMaster) instanceAnnotation(name string) string {
-	if m.args.Instance == "" {
-		return name
-	}
-	return m.args.Instance + "." + name
+	return m.trackingNames().InstanceAnnotation(name)
+}
+
+// trackingAnnotation returns the name of an NFD annotation in the configured
+// annotation namespace, prefixed with the instance name.
+func (m *nfdMaster) trackingAnnotation(name string) string {
+	return m.trackingNames().TrackingAnnotation(name)
+}
+
+// taintsAnnotation returns the name of the annotation tracking NFD-managed
+// taints. It is not instance-specific.
+func (m *nfdMaster) taintsAnnotation() string {
+	return m.trackingNames().TaintsAnnotation()
 }
 
 func (m *nfdMaster) startNfdApiController() error {
@@ -1351,6 +2153,11 @@ func (m *nfdMaster) startNfdApiController() error {
 		ResyncPeriod:                 m.config.ResyncPeriod.Duration,
 		K8sClient:                    m.k8sClient,
 		NodeFeatureNamespaceSelector: m.config.Restrictions.NodeFeatureNamespaceSelector,
+		DeselectedNamespacePolicy:    m.config.Restrictions.DeselectedNamespacePolicy,
+		DeselectedNamespaceTTL:       m.config.Restrictions.DeselectedNamespaceTTL.Duration,
+		EventRecorder:                m.eventRecorder,
+		EnableNamespacedRules:        len(m.namespacedRuleNodeSelectors) > 0,
+		IsNfdManagedKey:              m.isNfdManagedKey,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to initialize CRD controller: %w", err)
@@ -1398,7 +2205,7 @@ func (m *nfdMaster) nfdAPIUpdateHandlerWithLeaderElection() {
 }
 
 // Filter annotations by namespace. i.e. adds the possibly missing default namespace for annotations
-func (m *nfdMaster) filterFeatureAnnotations(annotations map[string]string) map[string]string {
+func (m *nfdMaster) filterFeatureAnnotations(annotations map[string]string, rejected *RejectedItems) map[string]string {
 	outAnnotations := make(map[string]string)
 
 	for annotation, value := range annotations {
@@ -1406,6 +2213,7 @@ func (m *nfdMaster) filterFeatureAnnotations(annotations map[string]string) map[
 		err := validate.Annotation(annotation, value)
 		if err != nil {
 			klog.ErrorS(err, "ignoring annotation", "annotationKey", annotation, "annotationValue", value)
+			rejected.addAnnotation(annotation, err.Error())
 			continue
 		}
 
@@ -1414,6 +2222,9 @@ func (m *nfdMaster) filterFeatureAnnotations(annotations map[string]string) map[
 
 	if len(outAnnotations) > 0 && m.config.Restrictions.DisableAnnotations {
 		klog.V(2).InfoS("node annotations are disabled in configuration (restrictions.disableAnnotations=true)")
+		for annotation := range outAnnotations {
+			rejected.addAnnotation(annotation, "annotations are disabled (restrictions.disableAnnotations=true)")
+		}
 		outAnnotations = map[string]string{}
 	}
 
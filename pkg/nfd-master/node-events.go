@@ -0,0 +1,126 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nfdmaster
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	taintutils "k8s.io/kubernetes/pkg/util/taints"
+
+	"sigs.k8s.io/node-feature-discovery/pkg/utils"
+)
+
+const (
+	// Reasons of the events emitted on node objects
+	nodeLabelsChangedReason            = "FeatureLabelsChanged"
+	nodeExtendedResourcesChangedReason = "ExtendedResourcesChanged"
+	nodeTaintsChangedReason            = "FeatureTaintsChanged"
+
+	// maxNodeEventChanges is the maximum number of individual changes listed
+	// in one event, in order to keep the size of the event bounded.
+	maxNodeEventChanges = 50
+)
+
+// nodeChanges contains the added, removed and changed properties of a node,
+// formatted for the message of an event.
+type nodeChanges struct {
+	added   []string
+	removed []string
+	changed []string
+}
+
+// patchChanges returns the changes the patches make to the items under
+// jsonPath, e.g. the labels of the node.
+func patchChanges(patches []utils.JsonPatch, jsonPath string, oldItems map[string]string) nodeChanges {
+	var c nodeChanges
+	prefix := jsonPath + "/"
+	for _, p := range patches {
+		if !strings.HasPrefix(p.Path, prefix) {
+			continue
+		}
+		key := utils.UnescapeJsonPointer(strings.TrimPrefix(p.Path, prefix))
+		switch p.Op {
+		case "add":
+			c.added = append(c.added, key+"="+p.Value)
+		case "remove":
+			c.removed = append(c.removed, key)
+		case "replace":
+			c.changed = append(c.changed, fmt.Sprintf("%s=%s->%s", key, oldItems[key], p.Value))
+		}
+	}
+	return c
+}
+
+// taintChanges returns the taints added and removed by nfd-master.
+func taintChanges(oldTaints, newTaints []corev1.Taint) nodeChanges {
+	var c nodeChanges
+	for _, t := range newTaints {
+		if !taintutils.TaintExists(oldTaints, &t) {
+			c.added = append(c.added, t.ToString())
+		}
+	}
+	for _, t := range oldTaints {
+		if !taintutils.TaintExists(newTaints, &t) {
+			c.removed = append(c.removed, t.ToString())
+		}
+	}
+	return c
+}
+
+// String formats the changes for the message of an event. An empty string is
+// returned if there are no changes.
+func (c nodeChanges) String() string {
+	var parts []string
+	n := 0
+	format := func(desc string, items []string) {
+		if len(items) == 0 {
+			return
+		}
+		sort.Strings(items)
+		remaining := max(maxNodeEventChanges-n, 0)
+		if len(items) > remaining {
+			items = append(items[:remaining:remaining], fmt.Sprintf("... (%d more)", len(items)-remaining))
+			n = maxNodeEventChanges
+		} else {
+			n += len(items)
+		}
+		parts = append(parts, desc+": "+strings.Join(items, ", "))
+	}
+	format("added", c.added)
+	format("removed", c.removed)
+	format("changed", c.changed)
+	return strings.Join(parts, "; ")
+}
+
+// recordNodeEvent emits an event on the node object if node events are
+// enabled and there are changes to report.
+func (m *nfdMaster) recordNodeEvent(nodeName, reason, what string, changes nodeChanges) {
+	if !m.config.EnableNodeEvents || m.eventRecorder == nil {
+		return
+	}
+	msg := changes.String()
+	if msg == "" {
+		return
+	}
+	// Nodes are referenced with their name as the UID, same as kubelet does
+	ref := &corev1.ObjectReference{Kind: "Node", Name: nodeName, UID: types.UID(nodeName)}
+	m.eventRecorder.Eventf(ref, corev1.EventTypeNormal, reason, "%s changed: %s", what, msg)
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nfdmaster
+
+import (
+	"sort"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+
+	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
+)
+
+// Reason of the events emitted for NodeFeature objects exceeding the
+// per-namespace limit.
+const nodeFeatureLimitExceededReason = "NodeFeatureLimitExceeded"
+
+// splitByNamespaceLimit splits the NodeFeature objects of one node into the
+// ones that are within the per-namespace limit and the ones exceeding it. The
+// oldest objects of each namespace are preferred. Objects in the exempt
+// namespace (the namespace of nfd-master) are never limited. A non-positive
+// limit disables limiting.
+func splitByNamespaceLimit(objs []*nfdv1alpha1.NodeFeature, limit int, exemptNs string) (accepted, excess []*nfdv1alpha1.NodeFeature) {
+	if limit <= 0 {
+		return objs, nil
+	}
+
+	byNs := make(map[string][]*nfdv1alpha1.NodeFeature)
+	for _, o := range objs {
+		byNs[o.Namespace] = append(byNs[o.Namespace], o)
+	}
+
+	accepted = make([]*nfdv1alpha1.NodeFeature, 0, len(objs))
+	for ns, nsObjs := range byNs {
+		if ns == exemptNs || len(nsObjs) <= limit {
+			accepted = append(accepted, nsObjs...)
+			continue
+		}
+		sort.Slice(nsObjs, func(i, j int) bool {
+			ti, tj := nsObjs[i].CreationTimestamp, nsObjs[j].CreationTimestamp
+			if !ti.Equal(&tj) {
+				return ti.Before(&tj)
+			}
+			return nsObjs[i].Name < nsObjs[j].Name
+		})
+		accepted = append(accepted, nsObjs[:limit]...)
+		excess = append(excess, nsObjs[limit:]...)
+	}
+	return accepted, excess
+}
+
+// limitNodeFeatures drops the NodeFeature objects of a node that exceed the
+// per-namespace limit (restrictions.maxNodeFeaturesPerNamespace). A warning
+// event is emitted for each dropped object.
+func (m *nfdMaster) limitNodeFeatures(nodeName string, objs []*nfdv1alpha1.NodeFeature) []*nfdv1alpha1.NodeFeature {
+	limit := m.config.Restrictions.MaxNodeFeaturesPerNamespace
+	accepted, excess := splitByNamespaceLimit(objs, limit, m.namespace)
+	for _, o := range excess {
+		klog.V(2).InfoS("ignoring NodeFeature object exceeding the per-namespace limit", "nodefeature", klog.KObj(o), "nodeName", nodeName, "limit", limit)
+		if m.eventRecorder != nil {
+			m.eventRecorder.Eventf(o, corev1.EventTypeWarning, nodeFeatureLimitExceededReason,
+				"NodeFeature object ignored: namespace %q has more than %d NodeFeature objects for node %q", o.Namespace, limit, nodeName)
+		}
+	}
+	return accepted
+}
+
+// nodeFeatureCollector is a prometheus collector reporting the number of
+// NodeFeature objects per namespace, the number of objects ignored because of
+// the per-namespace limit and the size of the NodeFeature informer cache.
+type nodeFeatureCollector struct {
+	m *nfdMaster
+}
+
+var (
+	nodeFeatureObjectsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName("", nfdMasterPrefix, nodeFeatureObjectsQuery),
+		"Number of NodeFeature objects per namespace.",
+		[]string{"namespace"}, nil)
+	nodeFeatureObjectsIgnoredDesc = prometheus.NewDesc(
+		prometheus.BuildFQName("", nfdMasterPrefix, nodeFeatureObjectsIgnoredQuery),
+		"Number of NodeFeature objects per namespace ignored because of exceeding the per-namespace limit.",
+		[]string{"namespace"}, nil)
+	nodeFeatureCacheElementsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName("", nfdMasterPrefix, nodeFeatureCacheElementsQuery),
+		"Number of feature elements in the cached NodeFeature objects per namespace.",
+		[]string{"namespace"}, nil)
+)
+
+// Describe implements the prometheus.Collector interface.
+func (c *nodeFeatureCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- nodeFeatureObjectsDesc
+	ch <- nodeFeatureObjectsIgnoredDesc
+	ch <- nodeFeatureCacheElementsDesc
+}
+
+// Collect implements the prometheus.Collector interface.
+func (c *nodeFeatureCollector) Collect(ch chan<- prometheus.Metric) {
+	if c.m.nfdController == nil || c.m.nfdController.featureLister == nil {
+		return
+	}
+	objs, err := c.m.nfdController.featureLister.List(labels.Everything())
+	if err != nil {
+		klog.ErrorS(err, "failed to list NodeFeature objects")
+		return
+	}
+
+	counts := make(map[string]int)
+	elements := make(map[string]int)
+	byNode := make(map[string][]*nfdv1alpha1.NodeFeature)
+	for _, o := range objs {
+		counts[o.Namespace]++
+		elements[o.Namespace] += numFeatureElements(&o.Spec.Features)
+		if nodeName, err := getNodeNameForObj(o); err == nil {
+			byNode[nodeName] = append(byNode[nodeName], o)
+		}
+	}
+
+	ignored := make(map[string]int)
+	for _, nodeObjs := range byNode {
+		_, excess := splitByNamespaceLimit(nodeObjs, c.m.config.Restrictions.MaxNodeFeaturesPerNamespace, c.m.namespace)
+		for _, o := range excess {
+			ignored[o.Namespace]++
+		}
+	}
+
+	for ns, n := range counts {
+		ch <- prometheus.MustNewConstMetric(nodeFeatureObjectsDesc, prometheus.GaugeValue, float64(n), ns)
+		ch <- prometheus.MustNewConstMetric(nodeFeatureObjectsIgnoredDesc, prometheus.GaugeValue, float64(ignored[ns]), ns)
+		ch <- prometheus.MustNewConstMetric(nodeFeatureCacheElementsDesc, prometheus.GaugeValue, float64(elements[ns]), ns)
+	}
+}
+
+// numFeatureElements returns the total number of flag, attribute and instance
+// attribute elements in a feature set.
+func numFeatureElements(f *nfdv1alpha1.Features) int {
+	n := 0
+	for _, s := range f.Flags {
+		n += len(s.Elements)
+	}
+	for _, s := range f.Attributes {
+		n += len(s.Elements)
+	}
+	for _, s := range f.Instances {
+		for _, i := range s.Elements {
+			n += len(i.Attributes)
+		}
+	}
+	return n
+}
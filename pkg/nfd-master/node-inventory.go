@@ -0,0 +1,94 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nfdmaster
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/klog/v2"
+
+	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
+)
+
+// nodeInventoryName returns the name of the NodeInventory object of a node.
+// The object is named after the system UUID of the machine which, unlike the
+// name or the provider ID of the node, stays the same when the node object is
+// re-created.
+func nodeInventoryName(node *corev1.Node) (string, error) {
+	uuid := strings.ToLower(node.Status.NodeInfo.SystemUUID)
+	if uuid == "" {
+		return "", fmt.Errorf("system UUID of node %q is not known", node.Name)
+	}
+	if errs := validation.IsDNS1123Subdomain(uuid); len(errs) > 0 {
+		return "", fmt.Errorf("invalid system UUID %q of node %q: %s", uuid, node.Name, strings.Join(errs, "; "))
+	}
+	return uuid, nil
+}
+
+// updateNodeInventory records the labels of a node in the NodeInventory object
+// of the machine, creating the object if it does not exist.
+func (m *nfdMaster) updateNodeInventory(node *corev1.Node, labels Labels) error {
+	name, err := nodeInventoryName(node)
+	if err != nil {
+		return err
+	}
+
+	spec := nfdv1alpha1.NodeInventorySpec{
+		SystemUUID: node.Status.NodeInfo.SystemUUID,
+		NodeName:   node.Name,
+		Labels:     labels,
+	}
+
+	cli := m.nfdClient.NfdV1alpha1().NodeInventories()
+	inv, err := cli.Get(context.TODO(), name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		inv = &nfdv1alpha1.NodeInventory{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       spec,
+		}
+		klog.V(2).InfoS("creating NodeInventory object", "nodeinventory", klog.KObj(inv), "nodeName", node.Name)
+		if _, err := cli.Create(context.TODO(), inv, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("failed to create NodeInventory %q: %w", name, err)
+		}
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to get NodeInventory %q: %w", name, err)
+	}
+
+	if inv.Spec.SystemUUID == spec.SystemUUID && inv.Spec.NodeName == spec.NodeName && maps.Equal(inv.Spec.Labels, spec.Labels) {
+		klog.V(4).InfoS("no changes in NodeInventory object, update skipped", "nodeinventory", klog.KObj(inv))
+		return nil
+	}
+	if inv.Spec.NodeName != spec.NodeName {
+		klog.InfoS("machine registered as a different node", "nodeinventory", klog.KObj(inv), "oldNodeName", inv.Spec.NodeName, "nodeName", spec.NodeName)
+	}
+
+	inv = inv.DeepCopy()
+	inv.Spec = spec
+	klog.V(2).InfoS("updating NodeInventory object", "nodeinventory", klog.KObj(inv), "nodeName", node.Name)
+	if _, err := cli.Update(context.TODO(), inv, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to update NodeInventory %q: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nfdmaster
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// nodeUnderMaintenance returns true if the updates of the node are paused
+// because of node maintenance, together with the reason.
+func nodeUnderMaintenance(node *corev1.Node, config NodeMaintenanceConfig) (string, bool) {
+	if config.SkipCordoned && node.Spec.Unschedulable {
+		return "node is cordoned", true
+	}
+	if config.Annotation != "" {
+		if _, ok := node.Annotations[config.Annotation]; ok {
+			return "node has annotation " + config.Annotation, true
+		}
+	}
+	return "", false
+}
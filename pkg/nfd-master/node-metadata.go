@@ -0,0 +1,99 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nfdmaster
+
+import (
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
+)
+
+// insertNodeMetadata makes the labels and annotations of the node available
+// for the matchNode matchers of rules. Labels and annotations managed by NFD
+// are left out so that rule outputs cannot feed back into the rule
+// evaluation. Any existing node metadata features, e.g. ones published by
+// the worker, are overwritten.
+func (m *nfdMaster) insertNodeMetadata(node *corev1.Node, features *nfdv1alpha1.Features) {
+	if features.Attributes == nil {
+		features.Attributes = make(map[string]nfdv1alpha1.AttributeFeatureSet)
+	}
+
+	labels := make(map[string]string, len(node.Labels))
+	for k, v := range node.Labels {
+		if !m.isNfdManagedKey(k) {
+			labels[k] = v
+		}
+	}
+	annotations := make(map[string]string, len(node.Annotations))
+	for k, v := range node.Annotations {
+		if !m.isNfdManagedKey(k) {
+			annotations[k] = v
+		}
+	}
+
+	features.Attributes[nfdv1alpha1.NodeMetadataDomain+"."+nfdv1alpha1.NodeLabelFeature] = nfdv1alpha1.NewAttributeFeatures(labels)
+	features.Attributes[nfdv1alpha1.NodeMetadataDomain+"."+nfdv1alpha1.NodeAnnotationFeature] = nfdv1alpha1.NewAttributeFeatures(annotations)
+}
+
+// isNfdManagedKey returns true if a label or annotation key is in one of the
+// namespaces that NFD manages.
+func (m *nfdMaster) isNfdManagedKey(key string) bool {
+	ns, _, found := strings.Cut(key, "/")
+	if !found {
+		return false
+	}
+	switch {
+	case ns == nfdv1alpha1.FeatureLabelNs || strings.HasSuffix(ns, nfdv1alpha1.FeatureLabelSubNsSuffix),
+		ns == nfdv1alpha1.ProfileLabelNs || strings.HasSuffix(ns, nfdv1alpha1.ProfileLabelSubNsSuffix),
+		ns == nfdv1alpha1.AnnotationNs || strings.HasSuffix(ns, "."+nfdv1alpha1.AnnotationNs):
+		return true
+	case m.config.AnnotationNs != "" && (ns == m.config.AnnotationNs || strings.HasSuffix(ns, "."+m.config.AnnotationNs)):
+		return true
+	}
+	_, ok := m.config.ExtraLabelNs[ns]
+	return ok
+}
+
+// nodeMetadataChanged returns true if the labels or annotations not managed
+// by NFD differ between two versions of a node object.
+func nodeMetadataChanged(oldObj, newObj metav1.Object, isNfdManagedKey func(string) bool) bool {
+	return !unmanagedEqual(oldObj.GetLabels(), newObj.GetLabels(), isNfdManagedKey) ||
+		!unmanagedEqual(oldObj.GetAnnotations(), newObj.GetAnnotations(), isNfdManagedKey)
+}
+
+func unmanagedEqual(a, b map[string]string, isNfdManagedKey func(string) bool) bool {
+	for k, v := range a {
+		if isNfdManagedKey(k) {
+			continue
+		}
+		if bv, ok := b[k]; !ok || bv != v {
+			return false
+		}
+	}
+	for k := range b {
+		if isNfdManagedKey(k) {
+			continue
+		}
+		if _, ok := a[k]; !ok {
+			return false
+		}
+	}
+	return true
+}
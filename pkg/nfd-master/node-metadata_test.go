@@ -0,0 +1,76 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nfdmaster
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
+	"sigs.k8s.io/node-feature-discovery/pkg/utils"
+)
+
+func TestInsertNodeMetadata(t *testing.T) {
+	Convey("When inserting node metadata into features", t, func() {
+		m := newFakeMaster()
+		m.config.ExtraLabelNs = utils.StringSetVal{"extra.example.com": struct{}{}}
+		node := &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "node-1",
+				Labels: map[string]string{
+					"kubernetes.io/hostname":                "node-1",
+					"node-role.kubernetes.io/worker":        "",
+					"feature.node.kubernetes.io/foo":        "true",
+					"vendor.feature.node.kubernetes.io/bar": "true",
+					"profile.node.kubernetes.io/baz":        "true",
+					"extra.example.com/qux":                 "true",
+				},
+				Annotations: map[string]string{
+					"example.com/rack":                      "r1",
+					"nfd.node.kubernetes.io/feature-labels": "foo",
+				},
+			},
+		}
+		features := nfdv1alpha1.NewFeatures()
+		features.InsertAttributeFeatures(nfdv1alpha1.NodeMetadataDomain, nfdv1alpha1.NodeLabelFeature, map[string]string{"spoofed": "true"})
+
+		m.insertNodeMetadata(node, features)
+
+		Convey("only labels and annotations not managed by NFD should be available", func() {
+			So(features.Attributes["node.label"].Elements, ShouldResemble, map[string]string{
+				"kubernetes.io/hostname":         "node-1",
+				"node-role.kubernetes.io/worker": "",
+			})
+			So(features.Attributes["node.annotation"].Elements, ShouldResemble, map[string]string{
+				"example.com/rack": "r1",
+			})
+		})
+
+		Convey("changes in NFD-managed metadata should be ignored", func() {
+			newNode := node.DeepCopy()
+			newNode.Labels["feature.node.kubernetes.io/foo"] = "false"
+			newNode.Annotations["nfd.node.kubernetes.io/feature-labels"] = ""
+			So(nodeMetadataChanged(node, newNode, m.isNfdManagedKey), ShouldBeFalse)
+
+			newNode.Labels["node-role.kubernetes.io/control-plane"] = ""
+			So(nodeMetadataChanged(node, newNode, m.isNfdManagedKey), ShouldBeTrue)
+		})
+	})
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nfdmaster
+
+import (
+	"context"
+	"fmt"
+
+	k8sclient "k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
+	"sigs.k8s.io/node-feature-discovery/pkg/utils"
+)
+
+// NodeUpdater updates the labels, annotations, extended resources and taints
+// of a single node directly from the features discovered on the node, without
+// NodeFeature objects. Labels (et al.) are processed and filtered exactly like
+// in nfd-master. NodeFeatureRule objects are not evaluated. It is used by
+// nfd-worker in standalone mode.
+type NodeUpdater interface {
+	UpdateNode(ctx context.Context, spec *nfdv1alpha1.NodeFeatureSpec) error
+}
+
+// NewNodeUpdater creates a new NodeUpdater for the given node. The processing
+// is configured with an nfd-master configuration file. The default
+// configuration is used if the file does not exist.
+func NewNodeUpdater(cli k8sclient.Interface, nodeName, configFile string) (NodeUpdater, error) {
+	m := &nfdMaster{
+		nodeName:        nodeName,
+		namespace:       utils.GetKubernetesNamespace(),
+		k8sClient:       cli,
+		nodeReconciles:  newReconcileTracker(),
+		noExecuteTaints: newNoExecuteTaintGate(),
+		labelConflicts:  newLabelConflictTracker(),
+		ruleEvalCache:   newRuleEvalCache(),
+		nfgEvalCache:    newFeatureGroupEvalCache(),
+	}
+	m.updaterPool = newUpdaterPool(m)
+
+	c, err := m.parseConfig(configFile, "")
+	if err != nil {
+		return nil, err
+	}
+
+	switch c.TrackingStorage {
+	case TrackingStorageAnnotations, TrackingStorageConfigMap:
+	default:
+		return nil, fmt.Errorf("invalid trackingStorage %q, must be one of %q or %q", c.TrackingStorage, TrackingStorageAnnotations, TrackingStorageConfigMap)
+	}
+
+	if c.EvaluationWebhook.URL != "" {
+		w, err := newEvaluationWebhook(c.EvaluationWebhook)
+		if err != nil {
+			return nil, err
+		}
+		m.evaluationWebhook = w
+	}
+
+	m.config = c
+	m.deniedNs.normal, m.deniedNs.wildcard = preProcessDeniedNamespaces(c.DenyLabelNs)
+	if c.EnableNodeEvents {
+		m.startEventRecorder()
+	}
+
+	klog.InfoS("node updater configured", "nodeName", nodeName, "configuration", utils.DelayedDumper(m.config))
+
+	return m, nil
+}
+
+// UpdateNode method of the NodeUpdater interface.
+func (m *nfdMaster) UpdateNode(ctx context.Context, spec *nfdv1alpha1.NodeFeatureSpec) error {
+	node, err := getNode(m.k8sClient, m.nodeName)
+	if err != nil {
+		return fmt.Errorf("failed to get node %q: %w", m.nodeName, err)
+	}
+	return m.refreshNodeFeatures(ctx, m.k8sClient, node, spec, &RejectedItems{})
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nfdmaster
+
+import (
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+	taintutils "k8s.io/kubernetes/pkg/util/taints"
+)
+
+// noExecuteTaintGate holds back the addition of new NoExecute taints until
+// nfd-master has warmed up and the taint has been consistently requested by
+// the evaluations of the node for the confirmation delay. This prevents evicting workloads because of transiently
+// missing NodeFeature data, e.g. while the informer caches are syncing after
+// a restart.
+type noExecuteTaintGate struct {
+	sync.Mutex
+	startTime time.Time
+	// pending contains the NoExecute taints of each node that have been
+	// requested but not yet added to the node, together with the time they
+	// were first requested
+	pending map[string]map[string]time.Time
+}
+
+func newNoExecuteTaintGate() *noExecuteTaintGate {
+	return &noExecuteTaintGate{
+		startTime: time.Now(),
+		pending:   make(map[string]map[string]time.Time),
+	}
+}
+
+// reset restarts the warm-up period and forgets all pending taints.
+func (g *noExecuteTaintGate) reset(now time.Time) {
+	g.Lock()
+	defer g.Unlock()
+	g.startTime = now
+	g.pending = make(map[string]map[string]time.Time)
+}
+
+// remove drops a node from the gate.
+func (g *noExecuteTaintGate) remove(nodeName string) {
+	g.Lock()
+	defer g.Unlock()
+	delete(g.pending, nodeName)
+}
+
+// filter returns the taints that may be applied on the node. NoExecute taints
+// that are not yet present on the node are dropped during the warm-up period
+// and until they have been requested by every evaluation for at least the
+// confirmation delay. The returned duration
+// specifies when the node should be re-evaluated, zero meaning that no
+// re-evaluation is needed.
+func (g *noExecuteTaintGate) filter(node *corev1.Node, taints []corev1.Taint, config NoExecuteTaintProtectionConfig, now time.Time) ([]corev1.Taint, time.Duration) {
+	if config.WarmUpPeriod.Duration <= 0 && config.ConfirmationDelay.Duration <= 0 {
+		return taints, 0
+	}
+
+	g.Lock()
+	defer g.Unlock()
+
+	warmUpLeft := g.startTime.Add(config.WarmUpPeriod.Duration).Sub(now)
+	prevPending := g.pending[node.Name]
+	newPending := make(map[string]time.Time)
+	var requeueAfter time.Duration
+	requeue := func(d time.Duration) {
+		if requeueAfter == 0 || d < requeueAfter {
+			requeueAfter = d
+		}
+	}
+
+	ret := make([]corev1.Taint, 0, len(taints))
+	for _, taint := range taints {
+		if taint.Effect != corev1.TaintEffectNoExecute || taintutils.TaintExists(node.Spec.Taints, &taint) {
+			ret = append(ret, taint)
+			continue
+		}
+
+		key := taint.ToString()
+		firstSeen, ok := prevPending[key]
+		if !ok {
+			firstSeen = now
+		}
+		confirmLeft := firstSeen.Add(config.ConfirmationDelay.Duration).Sub(now)
+
+		switch {
+		case warmUpLeft > 0:
+			klog.V(1).InfoS("delaying NoExecute taint until warm-up period has passed", "nodeName", node.Name, "taint", key, "delay", warmUpLeft)
+			requeue(warmUpLeft)
+		case confirmLeft > 0:
+			klog.V(1).InfoS("delaying NoExecute taint until confirmed by a later evaluation", "nodeName", node.Name, "taint", key, "delay", confirmLeft)
+			newPending[key] = firstSeen
+			requeue(confirmLeft)
+		default:
+			ret = append(ret, taint)
+		}
+	}
+
+	if len(newPending) > 0 {
+		g.pending[node.Name] = newPending
+	} else {
+		delete(g.pending, node.Name)
+	}
+
+	return ret, requeueAfter
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nfdmaster
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// reconcileTracker keeps track of the last successful update of each node.
+type reconcileTracker struct {
+	sync.Mutex
+	times map[string]time.Time
+}
+
+func newReconcileTracker() *reconcileTracker {
+	return &reconcileTracker{times: make(map[string]time.Time)}
+}
+
+// update records a successful update of a node.
+func (t *reconcileTracker) update(nodeName string, ts time.Time) {
+	t.Lock()
+	defer t.Unlock()
+	t.times[nodeName] = ts
+}
+
+// remove drops a node from the tracker.
+func (t *reconcileTracker) remove(nodeName string) {
+	t.Lock()
+	defer t.Unlock()
+	delete(t.times, nodeName)
+}
+
+// retain drops all nodes not in the given set from the tracker.
+func (t *reconcileTracker) retain(nodeNames sets.Set[string]) {
+	t.Lock()
+	defer t.Unlock()
+	for n := range t.times {
+		if !nodeNames.Has(n) {
+			delete(t.times, n)
+		}
+	}
+}
+
+// oldest returns the least recent update time of all tracked nodes. Zero
+// time is returned if no nodes are being tracked.
+func (t *reconcileTracker) oldest() time.Time {
+	t.Lock()
+	defer t.Unlock()
+	var oldest time.Time
+	for _, ts := range t.times {
+		if oldest.IsZero() || ts.Before(oldest) {
+			oldest = ts
+		}
+	}
+	return oldest
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nfdmaster
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+const (
+	// maxRejectedKeys is the maximum number of rejected keys of each kind
+	// recorded in the node annotation
+	maxRejectedKeys = 5
+
+	// maxRejectedReasonLen is the maximum length of the recorded reason of
+	// a rejection
+	maxRejectedReasonLen = 128
+)
+
+// RejectedItems records the labels, annotations, extended resources and
+// taints of one node that were rejected by validation or restrictions. It is
+// stored in a node annotation to help debugging missing labels (et al.)
+// without access to the logs of nfd-master. All methods are safe to call on
+// a nil receiver.
+type RejectedItems struct {
+	Labels            *RejectedKeys `json:"labels,omitempty"`
+	Annotations       *RejectedKeys `json:"annotations,omitempty"`
+	ExtendedResources *RejectedKeys `json:"extendedResources,omitempty"`
+	Taints            *RejectedKeys `json:"taints,omitempty"`
+}
+
+// RejectedKeys is the number of rejected items of one kind and a bounded list
+// of the rejected keys.
+type RejectedKeys struct {
+	Count int           `json:"count"`
+	Keys  []RejectedKey `json:"keys"`
+}
+
+// RejectedKey is one rejected item.
+type RejectedKey struct {
+	Key    string `json:"key"`
+	Reason string `json:"reason"`
+}
+
+func (r *RejectedItems) addLabel(key, reason string) {
+	if r != nil {
+		r.Labels = r.Labels.add(key, reason)
+	}
+}
+
+func (r *RejectedItems) addAnnotation(key, reason string) {
+	if r != nil {
+		r.Annotations = r.Annotations.add(key, reason)
+	}
+}
+
+func (r *RejectedItems) addExtendedResource(key, reason string) {
+	if r != nil {
+		r.ExtendedResources = r.ExtendedResources.add(key, reason)
+	}
+}
+
+func (r *RejectedItems) addTaint(key, reason string) {
+	if r != nil {
+		r.Taints = r.Taints.add(key, reason)
+	}
+}
+
+func (k *RejectedKeys) add(key, reason string) *RejectedKeys {
+	if k == nil {
+		k = &RejectedKeys{}
+	}
+	if len(reason) > maxRejectedReasonLen {
+		reason = reason[:maxRejectedReasonLen-3] + "..."
+	}
+	k.Count++
+	k.Keys = append(k.Keys, RejectedKey{Key: key, Reason: reason})
+	return k
+}
+
+func (k *RejectedKeys) keys() []RejectedKey {
+	if k == nil {
+		return nil
+	}
+	return k.Keys
+}
+
+// bound sorts the rejected keys and drops all but the first maxRejectedKeys
+// of them.
+func (k *RejectedKeys) bound() {
+	if k == nil {
+		return
+	}
+	sort.SliceStable(k.Keys, func(i, j int) bool { return k.Keys[i].Key < k.Keys[j].Key })
+	if len(k.Keys) > maxRejectedKeys {
+		k.Keys = k.Keys[:maxRejectedKeys]
+	}
+}
+
+// annotationValue returns the value of the rejected items annotation. An
+// empty string is returned if nothing was rejected.
+func (r *RejectedItems) annotationValue() (string, error) {
+	if r == nil || (r.Labels == nil && r.Annotations == nil && r.ExtendedResources == nil && r.Taints == nil) {
+		return "", nil
+	}
+	for _, k := range []*RejectedKeys{r.Labels, r.Annotations, r.ExtendedResources, r.Taints} {
+		k.bound()
+	}
+	data, err := json.Marshal(r)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
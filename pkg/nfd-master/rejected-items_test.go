@@ -0,0 +1,56 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nfdmaster
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestRejectedItems(t *testing.T) {
+	Convey("When recording rejected items", t, func() {
+		Convey("nothing should be recorded if nothing was rejected", func() {
+			v, err := (&RejectedItems{}).annotationValue()
+			So(err, ShouldBeNil)
+			So(v, ShouldEqual, "")
+
+			var r *RejectedItems
+			r.addLabel("foo", "bar")
+			v, err = r.annotationValue()
+			So(err, ShouldBeNil)
+			So(v, ShouldEqual, "")
+		})
+
+		Convey("the number of recorded keys should be bounded", func() {
+			r := &RejectedItems{}
+			for i := 9; i >= 0; i-- {
+				r.addLabel(fmt.Sprintf("label-%d", i), "invalid")
+			}
+			r.addTaint("taint", string(make([]byte, 2*maxRejectedReasonLen)))
+
+			_, err := r.annotationValue()
+			So(err, ShouldBeNil)
+			So(r.Labels.Count, ShouldEqual, 10)
+			So(r.Labels.Keys, ShouldHaveLength, maxRejectedKeys)
+			So(r.Labels.Keys[0].Key, ShouldEqual, "label-0")
+			So(r.Taints.Keys[0].Reason, ShouldHaveLength, maxRejectedReasonLen)
+			So(r.Annotations, ShouldBeNil)
+		})
+	})
+}
@@ -0,0 +1,436 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nfdmaster
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/klog/v2"
+	oras "oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/registry/remote"
+	"oras.land/oras-go/v2/registry/remote/auth"
+	"sigs.k8s.io/yaml"
+
+	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
+	"sigs.k8s.io/node-feature-discovery/pkg/utils"
+)
+
+const (
+	// RuleBundleMediaType is the media type of the OCI artifact layer
+	// containing the NodeFeatureRule manifests of a rule bundle.
+	RuleBundleMediaType = "application/vnd.nfd.rule-bundle.v1+yaml"
+	// RuleBundleSignatureMediaType is the media type of the OCI artifact
+	// layer containing the signature of a rule bundle.
+	RuleBundleSignatureMediaType = "application/vnd.nfd.rule-bundle.signature.v1"
+
+	// maxRuleBundleSize is the maximum size of a rule bundle fetched over
+	// HTTPS.
+	maxRuleBundleSize = 4 * 1024 * 1024
+)
+
+// RuleBundleConfig contains the configuration of a rule bundle, i.e. a
+// signed set of NodeFeatureRule manifests fetched from a remote location.
+type RuleBundleConfig struct {
+	// Name of the rule bundle.
+	Name string
+	// URL of the rule bundle, either an HTTPS URL
+	// (https://example.com/rules.yaml) or an OCI artifact reference
+	// (oci://registry.example.com/rules:v1).
+	URL string
+	// PublicKeyFile is the PEM-encoded public key used for verifying the
+	// signature of the bundle.
+	PublicKeyFile string
+	// CAFile is the CA bundle for verifying the server certificate of HTTPS
+	// URLs. The system CAs are used if empty.
+	CAFile string
+	// Interval between fetches of the bundle. Defaults to 1h.
+	Interval utils.DurationVal
+}
+
+// ruleBundleFetcher fetches the content and the signature of a rule bundle.
+type ruleBundleFetcher interface {
+	fetch(ctx context.Context) (content, signature []byte, err error)
+}
+
+// ruleBundle is a set of NodeFeatureRule objects fetched from a remote
+// location.
+type ruleBundle struct {
+	config    RuleBundleConfig
+	fetcher   ruleBundleFetcher
+	publicKey crypto.PublicKey
+
+	// digest is the digest of the latest successfully applied content
+	digest string
+	rules  []*nfdv1alpha1.NodeFeatureRule
+}
+
+// ruleBundles contains all configured rule bundles.
+type ruleBundles struct {
+	sync.RWMutex
+	bundles []*ruleBundle
+}
+
+func newRuleBundles(configs []RuleBundleConfig) (*ruleBundles, error) {
+	b := &ruleBundles{}
+	names := make(map[string]struct{}, len(configs))
+	for _, c := range configs {
+		if c.Name == "" {
+			return nil, fmt.Errorf("invalid ruleBundles: name must be specified")
+		}
+		if _, ok := names[c.Name]; ok {
+			return nil, fmt.Errorf("invalid ruleBundles: duplicate name %q", c.Name)
+		}
+		names[c.Name] = struct{}{}
+
+		bundle, err := newRuleBundle(c)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ruleBundles %q: %w", c.Name, err)
+		}
+		b.bundles = append(b.bundles, bundle)
+	}
+	return b, nil
+}
+
+func newRuleBundle(c RuleBundleConfig) (*ruleBundle, error) {
+	if c.Interval.Duration <= 0 {
+		c.Interval.Duration = time.Hour
+	}
+	if c.PublicKeyFile == "" {
+		return nil, fmt.Errorf("publicKeyFile must be specified")
+	}
+	pemData, err := os.ReadFile(c.PublicKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read public key: %w", err)
+	}
+	publicKey, err := parsePublicKey(pemData)
+	if err != nil {
+		return nil, err
+	}
+
+	var fetcher ruleBundleFetcher
+	switch {
+	case strings.HasPrefix(c.URL, "https://"):
+		transport, err := newHTTPTransport(c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("invalid caFile: %w", err)
+		}
+		fetcher = &httpsBundleFetcher{url: c.URL, client: &http.Client{Transport: transport, Timeout: time.Minute}}
+	case strings.HasPrefix(c.URL, "oci://"):
+		repo, err := remote.NewRepository(strings.TrimPrefix(c.URL, "oci://"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid OCI reference: %w", err)
+		}
+		repo.Client = auth.DefaultClient
+		fetcher = &ociBundleFetcher{repo: repo}
+	default:
+		return nil, fmt.Errorf("unsupported url %q, must start with https:// or oci://", c.URL)
+	}
+
+	return &ruleBundle{config: c, fetcher: fetcher, publicKey: publicKey}, nil
+}
+
+// rules returns the NodeFeatureRule objects of all rule bundles.
+func (b *ruleBundles) rules() []*nfdv1alpha1.NodeFeatureRule {
+	if b == nil {
+		return nil
+	}
+	b.RLock()
+	defer b.RUnlock()
+	var rules []*nfdv1alpha1.NodeFeatureRule
+	for _, bundle := range b.bundles {
+		rules = append(rules, bundle.rules...)
+	}
+	return rules
+}
+
+// run periodically fetches the rule bundles until the stop channel is
+// closed. The onChange callback is called whenever the rules of a bundle
+// change.
+func (b *ruleBundles) run(stop <-chan struct{}, onChange func()) {
+	for _, bundle := range b.bundles {
+		go func(bundle *ruleBundle) {
+			ticker := time.NewTicker(bundle.config.Interval.Duration)
+			defer ticker.Stop()
+			for {
+				if b.refresh(bundle) {
+					onChange()
+				}
+				select {
+				case <-ticker.C:
+				case <-stop:
+					return
+				}
+			}
+		}(bundle)
+	}
+}
+
+// refresh fetches a rule bundle and returns true if its rules changed. The
+// previous rules are retained if fetching, verifying or parsing the bundle
+// fails.
+func (b *ruleBundles) refresh(bundle *ruleBundle) bool {
+	name := bundle.config.Name
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	content, signature, err := bundle.fetcher.fetch(ctx)
+	if err != nil {
+		klog.ErrorS(err, "failed to fetch rule bundle", "ruleBundle", name, "url", bundle.config.URL)
+		ruleBundleErrors.WithLabelValues(name).Inc()
+		return false
+	}
+
+	sum := sha256.Sum256(content)
+	digest := hex.EncodeToString(sum[:])
+	b.RLock()
+	unchanged := digest == bundle.digest
+	b.RUnlock()
+	if unchanged {
+		klog.V(4).InfoS("rule bundle unchanged", "ruleBundle", name, "digest", digest)
+		return false
+	}
+
+	if err := verifySignature(bundle.publicKey, content, signature); err != nil {
+		klog.ErrorS(err, "rule bundle signature verification failed", "ruleBundle", name, "url", bundle.config.URL)
+		ruleBundleErrors.WithLabelValues(name).Inc()
+		return false
+	}
+	rules, err := parseRuleBundle(content)
+	if err != nil {
+		klog.ErrorS(err, "failed to parse rule bundle", "ruleBundle", name, "url", bundle.config.URL)
+		ruleBundleErrors.WithLabelValues(name).Inc()
+		return false
+	}
+
+	b.Lock()
+	bundle.digest = digest
+	bundle.rules = rules
+	b.Unlock()
+	ruleBundleRules.WithLabelValues(name).Set(float64(len(rules)))
+	klog.InfoS("rule bundle updated", "ruleBundle", name, "digest", digest, "objectCount", len(rules))
+	return true
+}
+
+// mergeBundledRules adds the NodeFeatureRule objects of the rule bundles to
+// the objects from the cluster. Objects in the cluster take precedence over
+// bundled objects of the same name.
+func (m *nfdMaster) mergeBundledRules(objs []*nfdv1alpha1.NodeFeatureRule) []*nfdv1alpha1.NodeFeatureRule {
+	bundled := m.ruleBundles.rules()
+	if len(bundled) == 0 {
+		return objs
+	}
+
+	names := make(map[string]struct{}, len(objs)+len(bundled))
+	for _, obj := range objs {
+		names[obj.Name] = struct{}{}
+	}
+	out := slices.Clone(objs)
+	for _, obj := range bundled {
+		if _, ok := names[obj.Name]; ok {
+			klog.V(2).InfoS("NodeFeatureRule from rule bundle shadowed by another object of the same name, skipping", "nodefeaturerule", klog.KObj(obj))
+			continue
+		}
+		names[obj.Name] = struct{}{}
+		out = append(out, obj)
+	}
+	return out
+}
+
+// parseRuleBundle parses the NodeFeatureRule manifests of a multi-document
+// YAML rule bundle.
+func parseRuleBundle(content []byte) ([]*nfdv1alpha1.NodeFeatureRule, error) {
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(content)))
+	rules := []*nfdv1alpha1.NodeFeatureRule{}
+	for {
+		doc, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		if len(bytes.TrimSpace(doc)) == 0 {
+			continue
+		}
+
+		nfr := &nfdv1alpha1.NodeFeatureRule{}
+		if err := yaml.UnmarshalStrict(doc, nfr); err != nil {
+			return nil, err
+		}
+		if nfr.APIVersion != nfdv1alpha1.SchemeGroupVersion.String() || nfr.Kind != "NodeFeatureRule" {
+			return nil, fmt.Errorf("unsupported object %s/%s, only %s/NodeFeatureRule is allowed", nfr.APIVersion, nfr.Kind, nfdv1alpha1.SchemeGroupVersion)
+		}
+		if nfr.Name == "" {
+			return nil, fmt.Errorf("NodeFeatureRule without a name")
+		}
+		rules = append(rules, nfr)
+	}
+	return rules, nil
+}
+
+// parsePublicKey parses a PEM-encoded PKIX public key.
+func parsePublicKey(pemData []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(pemData)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data found in public key")
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key: %w", err)
+	}
+	switch key.(type) {
+	case ed25519.PublicKey, *ecdsa.PublicKey, *rsa.PublicKey:
+	default:
+		return nil, fmt.Errorf("unsupported public key type %T", key)
+	}
+	return key, nil
+}
+
+// verifySignature verifies the base64-encoded signature of content. Ed25519
+// signatures are verified against the content directly, ECDSA (ASN.1) and
+// RSA (PKCS #1 v1.5) signatures against its SHA-256 digest.
+func verifySignature(key crypto.PublicKey, content, signature []byte) error {
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(signature)))
+	if err != nil {
+		return fmt.Errorf("failed to decode signature: %w", err)
+	}
+	digest := sha256.Sum256(content)
+
+	var ok bool
+	switch k := key.(type) {
+	case ed25519.PublicKey:
+		ok = ed25519.Verify(k, content, sig)
+	case *ecdsa.PublicKey:
+		ok = ecdsa.VerifyASN1(k, digest[:], sig)
+	case *rsa.PublicKey:
+		ok = rsa.VerifyPKCS1v15(k, crypto.SHA256, digest[:], sig) == nil
+	default:
+		return fmt.Errorf("unsupported public key type %T", key)
+	}
+	if !ok {
+		return fmt.Errorf("invalid signature")
+	}
+	return nil
+}
+
+// httpsBundleFetcher fetches a rule bundle from an HTTPS URL. The signature
+// is fetched from the same URL with the ".sig" suffix.
+type httpsBundleFetcher struct {
+	url    string
+	client *http.Client
+}
+
+func (f *httpsBundleFetcher) fetch(ctx context.Context) ([]byte, []byte, error) {
+	content, err := f.get(ctx, f.url)
+	if err != nil {
+		return nil, nil, err
+	}
+	signature, err := f.get(ctx, f.url+".sig")
+	if err != nil {
+		return nil, nil, err
+	}
+	return content, signature, nil
+}
+
+func (f *httpsBundleFetcher) get(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s returned status %d", url, resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxRuleBundleSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxRuleBundleSize {
+		return nil, fmt.Errorf("GET %s: response exceeds the maximum size of %d bytes", url, maxRuleBundleSize)
+	}
+	return data, nil
+}
+
+// ociBundleFetcher fetches a rule bundle from an OCI registry. The bundle is
+// an artifact with one layer of type RuleBundleMediaType containing the
+// manifests and one layer of type RuleBundleSignatureMediaType containing
+// the signature.
+type ociBundleFetcher struct {
+	repo *remote.Repository
+}
+
+func (f *ociBundleFetcher) fetch(ctx context.Context) ([]byte, []byte, error) {
+	_, data, err := oras.FetchBytes(ctx, f.repo, f.repo.Reference.Reference, oras.DefaultFetchBytesOptions)
+	if err != nil {
+		return nil, nil, err
+	}
+	manifest := ocispec.Manifest{}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+
+	var content, signature []byte
+	for _, layer := range manifest.Layers {
+		var dst *[]byte
+		switch layer.MediaType {
+		case RuleBundleMediaType:
+			dst = &content
+		case RuleBundleSignatureMediaType:
+			dst = &signature
+		default:
+			continue
+		}
+		_, *dst, err = oras.FetchBytes(ctx, f.repo.Blobs(), layer.Digest.String(), oras.DefaultFetchBytesOptions)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	if content == nil {
+		return nil, nil, fmt.Errorf("no layer of type %s found", RuleBundleMediaType)
+	}
+	if signature == nil {
+		return nil, nil, fmt.Errorf("no layer of type %s found", RuleBundleSignatureMediaType)
+	}
+	return content, signature, nil
+}
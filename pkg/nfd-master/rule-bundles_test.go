@@ -0,0 +1,183 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nfdmaster
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
+)
+
+const testRuleBundle = `
+apiVersion: nfd.k8s-sigs.io/v1alpha1
+kind: NodeFeatureRule
+metadata:
+  name: vendor-rule-a
+spec:
+  rules:
+    - name: rule-a
+      labels:
+        vendor.io/a: "true"
+---
+apiVersion: nfd.k8s-sigs.io/v1alpha1
+kind: NodeFeatureRule
+metadata:
+  name: vendor-rule-b
+spec:
+  rules:
+    - name: rule-b
+      labels:
+        vendor.io/b: "true"
+`
+
+func writePublicKey(t *testing.T, key crypto.PublicKey) string {
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "key.pem")
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestRuleBundles(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyFile := writePublicKey(t, pub)
+
+	var (
+		mu        sync.Mutex
+		content   = []byte(testRuleBundle)
+		signature = []byte(base64.StdEncoding.EncodeToString(ed25519.Sign(priv, content)))
+	)
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.URL.Path {
+		case "/rules.yaml":
+			_, _ = w.Write(content)
+		case "/rules.yaml.sig":
+			_, _ = w.Write(signature)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw}), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	Convey("When configuring rule bundles", t, func() {
+		Convey("invalid configurations should be rejected", func() {
+			_, err := newRuleBundles([]RuleBundleConfig{{URL: srv.URL + "/rules.yaml", PublicKeyFile: keyFile}})
+			So(err, ShouldNotBeNil)
+			_, err = newRuleBundles([]RuleBundleConfig{{Name: "a", URL: srv.URL + "/rules.yaml"}})
+			So(err, ShouldNotBeNil)
+			_, err = newRuleBundles([]RuleBundleConfig{{Name: "a", URL: "http://example.com/rules.yaml", PublicKeyFile: keyFile}})
+			So(err, ShouldNotBeNil)
+			_, err = newRuleBundles([]RuleBundleConfig{
+				{Name: "a", URL: "oci://registry.example.com/rules:v1", PublicKeyFile: keyFile},
+				{Name: "a", URL: "oci://registry.example.com/rules:v2", PublicKeyFile: keyFile},
+			})
+			So(err, ShouldNotBeNil)
+		})
+	})
+
+	Convey("When fetching a rule bundle over HTTPS", t, func() {
+		b, err := newRuleBundles([]RuleBundleConfig{{Name: "vendor", URL: srv.URL + "/rules.yaml", PublicKeyFile: keyFile, CAFile: caFile}})
+		So(err, ShouldBeNil)
+		bundle := b.bundles[0]
+
+		Convey("the rules should be available after a successful fetch", func() {
+			So(b.refresh(bundle), ShouldBeTrue)
+			rules := b.rules()
+			So(rules, ShouldHaveLength, 2)
+			So(rules[0].Name, ShouldEqual, "vendor-rule-a")
+			So(rules[1].Spec.Rules[0].Labels, ShouldResemble, map[string]string{"vendor.io/b": "true"})
+
+			Convey("an unchanged bundle should not be reported as changed", func() {
+				So(b.refresh(bundle), ShouldBeFalse)
+			})
+			Convey("a bundle with an invalid signature should be ignored", func() {
+				mu.Lock()
+				orig := content
+				content = []byte(testRuleBundle + "---\n" + testRuleBundle)
+				mu.Unlock()
+				defer func() {
+					mu.Lock()
+					content = orig
+					mu.Unlock()
+				}()
+				So(b.refresh(bundle), ShouldBeFalse)
+				So(b.rules(), ShouldHaveLength, 2)
+			})
+			Convey("bundled rules should be shadowed by cluster objects of the same name", func() {
+				m := &nfdMaster{ruleBundles: b}
+				objs := []*nfdv1alpha1.NodeFeatureRule{{ObjectMeta: metav1.ObjectMeta{Name: "vendor-rule-a"}}}
+				merged := m.mergeBundledRules(objs)
+				So(merged, ShouldHaveLength, 2)
+				So(merged[0], ShouldEqual, objs[0])
+				So(merged[1].Name, ShouldEqual, "vendor-rule-b")
+			})
+		})
+	})
+
+	Convey("When parsing a rule bundle", t, func() {
+		Convey("objects other than NodeFeatureRules should be rejected", func() {
+			_, err := parseRuleBundle([]byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: foo\n"))
+			So(err, ShouldNotBeNil)
+		})
+		Convey("unknown fields should be rejected", func() {
+			_, err := parseRuleBundle([]byte("apiVersion: nfd.k8s-sigs.io/v1alpha1\nkind: NodeFeatureRule\nmetadata:\n  name: foo\nspec:\n  foo: bar\n"))
+			So(err, ShouldNotBeNil)
+		})
+	})
+
+	Convey("When verifying an ECDSA signature", t, func() {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		So(err, ShouldBeNil)
+		digest := sha256.Sum256(content)
+		sig, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+		So(err, ShouldBeNil)
+
+		So(verifySignature(&key.PublicKey, content, []byte(base64.StdEncoding.EncodeToString(sig))), ShouldBeNil)
+		So(verifySignature(&key.PublicKey, []byte("foo"), []byte(base64.StdEncoding.EncodeToString(sig))), ShouldNotBeNil)
+		So(verifySignature(&key.PublicKey, content, []byte("not base64")), ShouldNotBeNil)
+	})
+}
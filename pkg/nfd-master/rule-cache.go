@@ -0,0 +1,194 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nfdmaster
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"maps"
+	"slices"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
+)
+
+// ruleEvalCache caches the outputs of NodeFeatureRule evaluation per node.
+// An entry is valid as long as the features of the node, the set of rules
+// and the options affecting rule evaluation stay unchanged, so that resyncs
+// of unchanged nodes skip rule evaluation.
+type ruleEvalCache struct {
+	sync.Mutex
+	entries map[string]*ruleEvalResult
+}
+
+// ruleEvalResult is the result of evaluating all NodeFeatureRule objects for
+// a node.
+type ruleEvalResult struct {
+	key               string
+	labels            Labels
+	annotations       Annotations
+	extendedResources ExtendedResources
+	taints            []corev1.Taint
+	matchedRules      sets.Set[string]
+	// backrefs are the rule outputs fed back to the features of the node
+	backrefs map[string]string
+}
+
+func newRuleEvalCache() *ruleEvalCache {
+	return &ruleEvalCache{entries: make(map[string]*ruleEvalResult)}
+}
+
+// get returns the cached result for a node, or nil if the node has no result
+// with a matching key.
+func (c *ruleEvalCache) get(nodeName, key string) *ruleEvalResult {
+	c.Lock()
+	defer c.Unlock()
+	r, ok := c.entries[nodeName]
+	if !ok || r.key != key {
+		return nil
+	}
+	return r.clone()
+}
+
+// set stores the result for a node.
+func (c *ruleEvalCache) set(nodeName string, r *ruleEvalResult) {
+	c.Lock()
+	defer c.Unlock()
+	c.entries[nodeName] = r.clone()
+}
+
+// remove drops the cached result of a node.
+func (c *ruleEvalCache) remove(nodeName string) {
+	c.Lock()
+	defer c.Unlock()
+	delete(c.entries, nodeName)
+}
+
+// clone returns a deep copy of the result so that callers are free to modify
+// the outputs.
+func (r *ruleEvalResult) clone() *ruleEvalResult {
+	return &ruleEvalResult{
+		key:               r.key,
+		labels:            maps.Clone(r.labels),
+		annotations:       maps.Clone(r.annotations),
+		extendedResources: maps.Clone(r.extendedResources),
+		taints:            slices.Clone(r.taints),
+		matchedRules:      r.matchedRules.Clone(),
+		backrefs:          maps.Clone(r.backrefs),
+	}
+}
+
+// ruleEvalCacheKey calculates the cache key for evaluating the given rules
+// against the given features. The LastUpdated timestamps of the features do
+// not affect rule evaluation and are ignored. Rule objects are identified by their
+// resourceVersion, or by their content if they don't have one (i.e. rules
+// from rule bundles). The autoPrefix argument tells if default namespaces are
+// automatically added to rule outputs.
+func ruleEvalCacheKey(features *nfdv1alpha1.Features, rules []*nfdv1alpha1.NodeFeatureRule, autoPrefix bool) (string, error) {
+	h := sha256.New()
+	enc := json.NewEncoder(h)
+	if err := enc.Encode(features.WithoutTimestamps()); err != nil {
+		return "", err
+	}
+	for _, r := range rules {
+		if err := enc.Encode(r.Name); err != nil {
+			return "", err
+		}
+		if r.ResourceVersion != "" {
+			if err := enc.Encode(r.ResourceVersion); err != nil {
+				return "", err
+			}
+		} else if err := enc.Encode(r.Spec); err != nil {
+			return "", err
+		}
+	}
+	if err := enc.Encode(autoPrefix); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// featureGroupEvalCache records the inputs of the last evaluation of each
+// NodeFeatureGroup so that re-evaluation can be skipped if neither the
+// NodeFeatureGroup object nor the features of the nodes have changed.
+type featureGroupEvalCache struct {
+	sync.Mutex
+	entries map[string]featureGroupEvalState
+}
+
+// featureGroupEvalState identifies the inputs of a NodeFeatureGroup
+// evaluation.
+type featureGroupEvalState struct {
+	// resourceVersion is the resourceVersion of the NodeFeatureGroup object
+	// after the evaluation
+	resourceVersion string
+	// key identifies the node features the NodeFeatureGroup was evaluated
+	// against
+	key string
+}
+
+func newFeatureGroupEvalCache() *featureGroupEvalCache {
+	return &featureGroupEvalCache{entries: make(map[string]featureGroupEvalState)}
+}
+
+// unchanged returns true if the NodeFeatureGroup was last evaluated with the
+// same inputs.
+func (c *featureGroupEvalCache) unchanged(name, resourceVersion, key string) bool {
+	c.Lock()
+	defer c.Unlock()
+	s, ok := c.entries[name]
+	return ok && resourceVersion != "" && s.resourceVersion == resourceVersion && s.key == key
+}
+
+// set stores the inputs of the last evaluation of a NodeFeatureGroup.
+func (c *featureGroupEvalCache) set(name, resourceVersion, key string) {
+	c.Lock()
+	defer c.Unlock()
+	c.entries[name] = featureGroupEvalState{resourceVersion: resourceVersion, key: key}
+}
+
+// remove drops the state of a NodeFeatureGroup.
+func (c *featureGroupEvalCache) remove(name string) {
+	c.Lock()
+	defer c.Unlock()
+	delete(c.entries, name)
+}
+
+// featureGroupEvalCacheKey calculates the key identifying the inputs of a
+// NodeFeatureGroup evaluation, i.e. the merged features of all nodes and the
+// options affecting the status of the NodeFeatureGroup. The LastUpdated
+// timestamps of the features are ignored.
+func featureGroupEvalCacheKey(nodeFeatures []*nfdv1alpha1.NodeFeature, statusConfig FeatureGroupStatusConfig) (string, error) {
+	h := sha256.New()
+	enc := json.NewEncoder(h)
+	for _, nf := range nodeFeatures {
+		if err := enc.Encode(nf.Name); err != nil {
+			return "", err
+		}
+		if err := enc.Encode(nf.Spec.Features.WithoutTimestamps()); err != nil {
+			return "", err
+		}
+	}
+	if err := enc.Encode(statusConfig); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nfdmaster
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	. "github.com/smartystreets/goconvey/convey"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	fakenfdclient "sigs.k8s.io/node-feature-discovery/api/generated/clientset/versioned/fake"
+	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
+)
+
+func TestRuleEvalCache(t *testing.T) {
+	Convey("When evaluating NodeFeatureRules", t, func() {
+		rules := []*nfdv1alpha1.NodeFeatureRule{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "rule-a"},
+				Spec: nfdv1alpha1.NodeFeatureRuleSpec{
+					Rules: []nfdv1alpha1.Rule{
+						{
+							Name:   "foo",
+							Labels: map[string]string{"foo": "true"},
+							MatchFeatures: nfdv1alpha1.FeatureMatcher{
+								{Feature: "fake.attribute", MatchExpressions: &nfdv1alpha1.MatchExpressionSet{"foo": {Op: nfdv1alpha1.MatchExists}}},
+							},
+						},
+					},
+				},
+			},
+		}
+		master := newFakeMaster()
+		master.nfdController = newFakeNfdAPIController(fakenfdclient.NewSimpleClientset())
+		master.ruleBundles = &ruleBundles{bundles: []*ruleBundle{{rules: rules}}}
+		newFeatures := func(attrs map[string]string) *nfdv1alpha1.Features {
+			f := nfdv1alpha1.NewFeatures()
+			f.Attributes["fake.attribute"] = nfdv1alpha1.NewAttributeFeatures(attrs)
+			return f
+		}
+		hits, misses := testutil.ToFloat64(ruleEvalCacheHits), testutil.ToFloat64(ruleEvalCacheMisses)
+
+		features := newFeatures(map[string]string{"foo": "1"})
+		labels, _, _, _ := master.processNodeFeatureRule("node-1", features, nil)
+		So(labels, ShouldResemble, Labels{"foo": "true"})
+		So(testutil.ToFloat64(ruleEvalCacheMisses)-misses, ShouldEqual, 1)
+
+		Convey("unchanged nodes should use the cached result", func() {
+			features := newFeatures(map[string]string{"foo": "1"})
+			cached, _, _, _ := master.processNodeFeatureRule("node-1", features, nil)
+			So(cached, ShouldResemble, labels)
+			So(testutil.ToFloat64(ruleEvalCacheHits)-hits, ShouldEqual, 1)
+			So(features.Attributes["rule.matched"].Elements, ShouldResemble, map[string]string{"foo": "true"})
+			So(master.nfdController.ruleNodes.nodesOf("rule-a"), ShouldContainKey, "node-1")
+
+			Convey("modifying the returned outputs should not affect the cache", func() {
+				cached["bar"] = "true"
+				again, _, _, _ := master.processNodeFeatureRule("node-1", newFeatures(map[string]string{"foo": "1"}), nil)
+				So(again, ShouldResemble, labels)
+			})
+		})
+		Convey("changed features should cause re-evaluation", func() {
+			labels, _, _, _ := master.processNodeFeatureRule("node-1", newFeatures(map[string]string{"bar": "1"}), nil)
+			So(labels, ShouldBeEmpty)
+			So(testutil.ToFloat64(ruleEvalCacheMisses)-misses, ShouldEqual, 2)
+		})
+		Convey("changed rules should cause re-evaluation", func() {
+			rules[0].Spec.Rules[0].Labels = map[string]string{"foo": "false"}
+			labels, _, _, _ := master.processNodeFeatureRule("node-1", newFeatures(map[string]string{"foo": "1"}), nil)
+			So(labels, ShouldResemble, Labels{"foo": "false"})
+			So(testutil.ToFloat64(ruleEvalCacheMisses)-misses, ShouldEqual, 2)
+		})
+		Convey("purged nodes should be re-evaluated", func() {
+			master.purgeNode("node-1")
+			_, _, _, _ = master.processNodeFeatureRule("node-1", newFeatures(map[string]string{"foo": "1"}), nil)
+			So(testutil.ToFloat64(ruleEvalCacheMisses)-misses, ShouldEqual, 2)
+		})
+	})
+}
+
+func TestRuleEvalCacheKey(t *testing.T) {
+	Convey("When calculating rule evaluation cache keys", t, func() {
+		features := nfdv1alpha1.NewFeatures()
+		features.Attributes["fake.attribute"] = nfdv1alpha1.NewAttributeFeatures(map[string]string{"a": "1", "b": "2", "c": "3"})
+		rules := []*nfdv1alpha1.NodeFeatureRule{{ObjectMeta: metav1.ObjectMeta{Name: "rule-a", ResourceVersion: "1"}}}
+
+		key, err := ruleEvalCacheKey(features, rules, true)
+		So(err, ShouldBeNil)
+
+		Convey("the key should be stable", func() {
+			for range 10 {
+				k, err := ruleEvalCacheKey(features.DeepCopy(), rules, true)
+				So(err, ShouldBeNil)
+				So(k, ShouldEqual, key)
+			}
+		})
+		Convey("the key should depend on the resourceVersion of the rules", func() {
+			rules[0].ResourceVersion = "2"
+			k, _ := ruleEvalCacheKey(features, rules, true)
+			So(k, ShouldNotEqual, key)
+		})
+		Convey("the key should depend on automatic prefixing", func() {
+			k, _ := ruleEvalCacheKey(features, rules, false)
+			So(k, ShouldNotEqual, key)
+		})
+		Convey("the key should not depend on feature timestamps", func() {
+			f := features.DeepCopy()
+			attrs := f.Attributes["fake.attribute"]
+			attrs.LastUpdated = &metav1.Time{Time: time.Now()}
+			f.Attributes["fake.attribute"] = attrs
+			k, _ := ruleEvalCacheKey(f, rules, true)
+			So(k, ShouldEqual, key)
+		})
+	})
+}
@@ -0,0 +1,189 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nfdmaster
+
+import (
+	"strings"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+
+	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
+)
+
+// ruleNodeIndex tracks the nodes on which each NodeFeatureRule object matched
+// on its last evaluation. It is used for determining the nodes that need to
+// be re-evaluated when a NodeFeatureRule object changes.
+type ruleNodeIndex struct {
+	sync.Mutex
+	// nodes maps NodeFeatureRule names to node names
+	nodes map[string]sets.Set[string]
+	// rules maps node names to NodeFeatureRule names
+	rules map[string]sets.Set[string]
+}
+
+func newRuleNodeIndex() *ruleNodeIndex {
+	return &ruleNodeIndex{
+		nodes: make(map[string]sets.Set[string]),
+		rules: make(map[string]sets.Set[string]),
+	}
+}
+
+// setNode sets the NodeFeatureRule objects that matched on a node.
+func (r *ruleNodeIndex) setNode(nodeName string, rules sets.Set[string]) {
+	r.Lock()
+	defer r.Unlock()
+
+	r.removeNodeLocked(nodeName)
+	if rules.Len() == 0 {
+		return
+	}
+	r.rules[nodeName] = rules
+	for rule := range rules {
+		if _, ok := r.nodes[rule]; !ok {
+			r.nodes[rule] = sets.New[string]()
+		}
+		r.nodes[rule].Insert(nodeName)
+	}
+}
+
+// removeNode drops a node from the index.
+func (r *ruleNodeIndex) removeNode(nodeName string) {
+	r.Lock()
+	defer r.Unlock()
+	r.removeNodeLocked(nodeName)
+}
+
+func (r *ruleNodeIndex) removeNodeLocked(nodeName string) {
+	for rule := range r.rules[nodeName] {
+		r.nodes[rule].Delete(nodeName)
+		if r.nodes[rule].Len() == 0 {
+			delete(r.nodes, rule)
+		}
+	}
+	delete(r.rules, nodeName)
+}
+
+// nodesOf returns the nodes on which a NodeFeatureRule object matched.
+func (r *ruleNodeIndex) nodesOf(ruleName string) sets.Set[string] {
+	r.Lock()
+	defer r.Unlock()
+	return r.nodes[ruleName].Clone()
+}
+
+// ruleFeatureKeys returns the (lowercase) names of the features referenced by
+// a NodeFeatureRule. The second return value is false if the rule may match
+// independent of the features of a node, i.e. it has rules without feature
+// matchers, it matches on node metadata, it depends on the outputs of other
+// rules or it has else outputs which apply on nodes where the rule does not
+// match.
+func ruleFeatureKeys(spec *nfdv1alpha1.NodeFeatureRuleSpec) (sets.Set[string], bool) {
+	keys := sets.New[string]()
+
+	addTerms := func(m nfdv1alpha1.FeatureMatcher) bool {
+		if len(m) == 0 {
+			return false
+		}
+		for _, term := range m {
+			name := strings.ToLower(term.Feature)
+			if strings.HasPrefix(name, nfdv1alpha1.RuleBackrefDomain+".") {
+				return false
+			}
+			keys.Insert(name)
+		}
+		return true
+	}
+
+	for _, rule := range spec.Rules {
+		if len(rule.MatchFeatures) == 0 && len(rule.MatchAny) == 0 {
+			return nil, false
+		}
+		if rule.MatchNode != nil || len(rule.ElseLabels) > 0 || len(rule.ElseTaints) > 0 {
+			return nil, false
+		}
+		if len(rule.MatchFeatures) > 0 && !addTerms(rule.MatchFeatures) {
+			return nil, false
+		}
+		for _, ma := range rule.MatchAny {
+			if !addTerms(ma.MatchFeatures) {
+				return nil, false
+			}
+		}
+	}
+	return keys, true
+}
+
+// nodesWithFeatures returns the names of the nodes having any of the given
+// features in their NodeFeature objects.
+func (c *nfdController) nodesWithFeatures(keys sets.Set[string]) (sets.Set[string], error) {
+	nodes := sets.New[string]()
+
+	objs, err := c.featureLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	for _, obj := range objs {
+		nodeName, err := getNodeNameForObj(obj)
+		if err != nil || nodes.Has(nodeName) {
+			continue
+		}
+		for key := range keys {
+			f := &obj.Spec.Features
+			_, okF := f.Flags[key]
+			_, okA := f.Attributes[key]
+			_, okI := f.Instances[key]
+			if okF || okA || okI {
+				nodes.Insert(nodeName)
+				break
+			}
+		}
+	}
+	return nodes, nil
+}
+
+// updateNodesForRule queues updates of the nodes affected by a change in a
+// NodeFeatureRule object, i.e. the nodes on which the old version of the rule
+// matched and the nodes on which the new version may match. Either of oldRule
+// and newRule may be nil.
+func (c *nfdController) updateNodesForRule(oldRule, newRule *nfdv1alpha1.NodeFeatureRule) {
+	nodes := sets.New[string]()
+	if oldRule != nil {
+		nodes = nodes.Union(c.ruleNodes.nodesOf(oldRule.Name))
+	}
+	if newRule != nil {
+		keys, ok := ruleFeatureKeys(&newRule.Spec)
+		if !ok {
+			klog.V(2).InfoS("NodeFeatureRule may match any node, updating all nodes", "nodefeaturerule", klog.KObj(newRule))
+			c.updateAllNodes()
+			return
+		}
+		candidates, err := c.nodesWithFeatures(keys)
+		if err != nil {
+			klog.ErrorS(err, "failed to determine nodes affected by NodeFeatureRule, updating all nodes", "nodefeaturerule", klog.KObj(newRule))
+			c.updateAllNodes()
+			return
+		}
+		nodes = nodes.Union(candidates)
+	}
+
+	klog.V(2).InfoS("updating nodes affected by NodeFeatureRule change", "nodeCount", nodes.Len())
+	for nodeName := range nodes {
+		c.updateOneNodeByName(nodeName)
+	}
+}
@@ -0,0 +1,189 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nfdmaster
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"time"
+
+	"github.com/google/uuid"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	"k8s.io/klog/v2"
+
+	nfdfeatures "sigs.k8s.io/node-feature-discovery/pkg/features"
+)
+
+// shard is the subset of nodes (and NodeFeatureGroups) processed by an
+// nfd-master instance when sharding is enabled. Objects are assigned to
+// shards by consistent hashing of their names.
+type shard struct {
+	index int
+	count int
+}
+
+// owns returns true if the object with the given name belongs to the shard.
+// All objects belong to the shard if sharding is disabled.
+func (s shard) owns(name string) bool {
+	if s.count <= 1 {
+		return true
+	}
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(name))
+	return jumpHash(h.Sum64(), s.count) == s.index
+}
+
+// jumpHash implements the jump consistent hash algorithm of Lamping and
+// Veach. It maps a key to one of numBuckets buckets so that only 1/n of the
+// keys move to a new bucket when the number of buckets grows to n.
+func jumpHash(key uint64, numBuckets int) int {
+	var b, j int64 = -1, 0
+	for j < int64(numBuckets) {
+		b = j
+		key = key*2862933555777941757 + 1
+		j = int64(float64(b+1) * (float64(int64(1)<<31) / float64((key>>33)+1)))
+	}
+	return int(b)
+}
+
+// shardLeaseName returns the name of the Lease object used for claiming a
+// shard.
+func shardLeaseName(index int) string {
+	return fmt.Sprintf("nfd-master-shard-%d.nfd.kubernetes.io", index)
+}
+
+// validateSharding validates the sharding options.
+func (m *nfdMaster) validateSharding() error {
+	if m.args.ShardCount <= 0 {
+		return nil
+	}
+	if !nfdfeatures.NFDFeatureGate.Enabled(nfdfeatures.MasterSharding) {
+		return fmt.Errorf("sharding requires the %s feature gate to be enabled", nfdfeatures.MasterSharding)
+	}
+	if m.args.EnableLeaderElection {
+		return fmt.Errorf("sharding and leader election are mutually exclusive")
+	}
+	if m.args.ShardIndex >= m.args.ShardCount {
+		return fmt.Errorf("invalid shard index %d, must be less than the shard count %d", m.args.ShardIndex, m.args.ShardCount)
+	}
+	return nil
+}
+
+// ownsNode returns true if the node is processed by this nfd-master instance.
+func (m *nfdMaster) ownsNode(nodeName string) bool {
+	return m.shard.owns(nodeName)
+}
+
+// nfdAPIUpdateHandlerWithSharding runs the update handler for one shard of
+// the nodes. The shard is either specified on the command line or claimed
+// automatically by acquiring one of the shard Lease objects.
+func (m *nfdMaster) nfdAPIUpdateHandlerWithSharding() {
+	if m.args.ShardIndex >= 0 {
+		m.shard = shard{index: m.args.ShardIndex, count: m.args.ShardCount}
+		klog.InfoS("processing a shard of the nodes", "shardIndex", m.shard.index, "shardCount", m.shard.count)
+		m.nfdAPIUpdateHandler()
+		return
+	}
+
+	identity := m.nodeName + "_" + uuid.NewString()
+	// The first round only claims free shards. The subsequent rounds wait
+	// long enough for taking over the leases of failed instances.
+	timeout := 2 * m.config.LeaderElection.RetryPeriod.Duration
+	for {
+		for i := 0; i < m.args.ShardCount; i++ {
+			if m.tryAcquireShard(i, identity, timeout) {
+				return
+			}
+		}
+		timeout = m.config.LeaderElection.LeaseDuration.Duration + 2*m.config.LeaderElection.RetryPeriod.Duration
+	}
+}
+
+// tryAcquireShard tries to acquire the Lease of a shard within the given
+// timeout. On success, the update handler is run for the shard and
+// nfd-master is stopped if the Lease is lost. The Lease is released when
+// nfd-master is stopped.
+func (m *nfdMaster) tryAcquireShard(index int, identity string, timeout time.Duration) bool {
+	ctx, cancel := context.WithCancel(context.Background())
+	acquired := make(chan struct{})
+
+	config := leaderelection.LeaderElectionConfig{
+		Lock: &resourcelock.LeaseLock{
+			LeaseMeta: metav1.ObjectMeta{
+				Name:      shardLeaseName(index),
+				Namespace: m.namespace,
+			},
+			Client:     m.k8sClient.CoordinationV1(),
+			LockConfig: resourcelock.ResourceLockConfig{Identity: identity},
+		},
+		LeaseDuration: m.config.LeaderElection.LeaseDuration.Duration,
+		RetryPeriod:   m.config.LeaderElection.RetryPeriod.Duration,
+		RenewDeadline: m.config.LeaderElection.RenewDeadline.Duration,
+		// Let another instance take over the shard immediately when this
+		// instance is stopped.
+		ReleaseOnCancel: true,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(_ context.Context) {
+				close(acquired)
+				m.shard = shard{index: index, count: m.args.ShardCount}
+				klog.InfoS("shard lease acquired, processing a shard of the nodes", "shardIndex", index, "shardCount", m.args.ShardCount)
+				m.nfdAPIUpdateHandler()
+			},
+			OnStoppedLeading: func() {
+				select {
+				case <-m.stop:
+				case <-acquired:
+					klog.InfoS("shard lease was lost", "shardIndex", index)
+					m.Stop()
+				default:
+				}
+			},
+		},
+	}
+	leaderElector, err := leaderelection.NewLeaderElector(config)
+	if err != nil {
+		klog.ErrorS(err, "couldn't create leader elector for shard", "shardIndex", index)
+		cancel()
+		m.Stop()
+		return true
+	}
+	go leaderElector.Run(ctx)
+
+	select {
+	case <-acquired:
+	case <-m.stop:
+		cancel()
+		return true
+	case <-time.After(timeout):
+		select {
+		case <-acquired:
+		default:
+			klog.V(2).InfoS("shard lease is held by another instance", "shardIndex", index)
+			cancel()
+			return false
+		}
+	}
+
+	go func() {
+		<-m.stop
+		cancel()
+	}()
+	return true
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nfdmaster
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	fakeclient "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/util/workqueue"
+
+	"sigs.k8s.io/node-feature-discovery/pkg/features"
+)
+
+func TestShardOwns(t *testing.T) {
+	Convey("When sharding is disabled", t, func() {
+		So(shard{}.owns("node-1"), ShouldBeTrue)
+		So(shard{index: 0, count: 1}.owns("node-1"), ShouldBeTrue)
+	})
+
+	Convey("When nodes are split into shards", t, func() {
+		const numNodes = 1000
+		shards := []shard{{0, 3}, {1, 3}, {2, 3}}
+
+		Convey("every node should belong to exactly one shard", func() {
+			counts := make([]int, len(shards))
+			for i := 0; i < numNodes; i++ {
+				name := fmt.Sprintf("node-%d", i)
+				owners := 0
+				for j, s := range shards {
+					if s.owns(name) {
+						owners++
+						counts[j]++
+					}
+				}
+				So(owners, ShouldEqual, 1)
+			}
+			for _, c := range counts {
+				So(c, ShouldBeBetween, numNodes/3-numNodes/10, numNodes/3+numNodes/10)
+			}
+		})
+
+		Convey("nodes should only move to the new shard when shards are added", func() {
+			for i := 0; i < numNodes; i++ {
+				name := fmt.Sprintf("node-%d", i)
+				for _, s := range shards {
+					if s.owns(name) {
+						grown := shard{index: s.index, count: s.count + 1}
+						newShard := shard{index: s.count, count: s.count + 1}
+						So(grown.owns(name) || newShard.owns(name), ShouldBeTrue)
+					}
+				}
+			}
+		})
+	})
+}
+
+func TestValidateSharding(t *testing.T) {
+	Convey("When validating sharding options", t, func() {
+		master := newFakeMaster()
+		defer func() {
+			_ = features.NFDMutableFeatureGate.SetFromMap(map[string]bool{string(features.MasterSharding): false})
+		}()
+
+		Convey("sharding should be disabled by default", func() {
+			So(master.validateSharding(), ShouldBeNil)
+		})
+
+		master.args.ShardCount = 2
+		master.args.ShardIndex = -1
+		Convey("the feature gate should be required", func() {
+			So(master.validateSharding(), ShouldNotBeNil)
+		})
+
+		So(features.NFDMutableFeatureGate.SetFromMap(map[string]bool{string(features.MasterSharding): true}), ShouldBeNil)
+		Convey("automatic shard assignment should be accepted", func() {
+			So(master.validateSharding(), ShouldBeNil)
+		})
+		Convey("leader election should be rejected", func() {
+			master.args.EnableLeaderElection = true
+			So(master.validateSharding(), ShouldNotBeNil)
+		})
+		Convey("out of range shard index should be rejected", func() {
+			master.args.ShardIndex = 2
+			So(master.validateSharding(), ShouldNotBeNil)
+		})
+	})
+}
+
+func TestNfdAPIUpdateAllNodesSharded(t *testing.T) {
+	Convey("When updating all nodes with sharding enabled", t, func() {
+		nodes := newTestNodeList()
+		fakeMaster := newFakeMaster(WithKubernetesClient(fakeclient.NewSimpleClientset(nodes)))
+		fakeMaster.shard = shard{index: 1, count: 4}
+		fakeMaster.updaterPool = newFakeupdaterPool(fakeMaster)
+		fakeMaster.updaterPool.queue = workqueue.NewTypedRateLimitingQueue[string](newUpdaterRateLimiter())
+		defer fakeMaster.updaterPool.queue.ShutDown()
+
+		So(fakeMaster.nfdAPIUpdateAllNodes(), ShouldBeNil)
+
+		Convey("only nodes of the own shard should be queued", func() {
+			expected := 0
+			for _, n := range nodes.Items {
+				if fakeMaster.shard.owns(n.Name) {
+					expected++
+				}
+			}
+			So(expected, ShouldBeGreaterThan, 0)
+			So(expected, ShouldBeLessThan, len(nodes.Items))
+			So(fakeMaster.updaterPool.queue.Len(), ShouldEqual, expected)
+			for range expected {
+				name, _ := fakeMaster.updaterPool.queue.Get()
+				So(fakeMaster.shard.owns(name), ShouldBeTrue)
+				fakeMaster.updaterPool.queue.Done(name)
+			}
+		})
+	})
+}
@@ -0,0 +1,419 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nfdmaster
+
+import (
+	"fmt"
+	"maps"
+	"path"
+	"strings"
+	"sync"
+
+	"golang.org/x/net/context"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	k8sclient "k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/node-feature-discovery/pkg/nodepatch"
+	"sigs.k8s.io/node-feature-discovery/pkg/nodetracking"
+	"sigs.k8s.io/node-feature-discovery/pkg/utils"
+)
+
+const (
+	// TrackingStorageAnnotations stores the bookkeeping of NFD-managed node
+	// labels, annotations, extended resources and taints in node annotations.
+	TrackingStorageAnnotations = "Annotations"
+	// TrackingStorageConfigMap stores the bookkeeping of NFD-managed node
+	// labels, annotations, extended resources and taints in a per-node
+	// ConfigMap in the namespace of nfd-master.
+	TrackingStorageConfigMap = "ConfigMap"
+)
+
+// staleTrackingConfigMaps holds the names of tracking ConfigMaps that need to
+// be migrated back to node annotations.
+type staleTrackingConfigMaps struct {
+	sync.Mutex
+	names sets.Set[string]
+	// clean is true when no tracking ConfigMaps are left, i.e. there is no
+	// need to look for them anymore
+	clean bool
+}
+
+func (s *staleTrackingConfigMaps) set(names sets.Set[string], clean bool) {
+	s.Lock()
+	defer s.Unlock()
+	s.names = names
+	s.clean = clean
+}
+
+func (s *staleTrackingConfigMaps) isClean() bool {
+	s.Lock()
+	defer s.Unlock()
+	return s.clean
+}
+
+func (s *staleTrackingConfigMaps) has(name string) bool {
+	s.Lock()
+	defer s.Unlock()
+	return s.names.Has(name)
+}
+
+func (s *staleTrackingConfigMaps) remove(name string) {
+	s.Lock()
+	defer s.Unlock()
+	s.names.Delete(name)
+}
+
+// nodeTracking holds the tracking information of one node while the node is
+// being updated.
+type nodeTracking struct {
+	storage string
+	keys    sets.Set[string]
+	// legacyKeys maps the names of tracking annotations in the default
+	// annotation namespace to their current names
+	legacyKeys map[string]string
+	node       *corev1.Node
+	data       map[string]string
+	// stored is the tracking information currently stored in the ConfigMap
+	// or in node annotations
+	stored     map[string]string
+	configMap  *corev1.ConfigMap
+	configName string
+	namespace  string
+	stale      *staleTrackingConfigMaps
+}
+
+// trackingNames returns the naming of the tracking annotations and
+// ConfigMaps of this nfd-master instance.
+func (m *nfdMaster) trackingNames() nodetracking.Names {
+	return nodetracking.Names{Instance: m.args.Instance, AnnotationNs: m.config.AnnotationNs}
+}
+
+// trackingConfigMapName returns the name of the tracking ConfigMap of a node.
+func (m *nfdMaster) trackingConfigMapName(nodeName string) string {
+	return m.trackingNames().ConfigMapName(nodeName)
+}
+
+// loadNodeTracking reads the tracking information of a node. It returns a
+// copy of the node object with the tracking information merged into its
+// annotations which can be used for calculating the node updates regardless
+// of the storage that is used.
+func (m *nfdMaster) loadNodeTracking(cli k8sclient.Interface, node *corev1.Node) (*corev1.Node, *nodeTracking, error) {
+	t := &nodeTracking{
+		storage:    m.config.TrackingStorage,
+		keys:       m.trackingNames().TrackingKeys(),
+		legacyKeys: m.trackingNames().LegacyTrackingKeys(),
+		node:       node.DeepCopy(),
+		data:       make(map[string]string),
+		configName: m.trackingConfigMapName(node.Name),
+		namespace:  m.namespace,
+		stale:      &m.staleTrackingConfigMaps,
+	}
+	if t.storage == "" {
+		t.storage = TrackingStorageAnnotations
+	}
+	if t.node.Annotations == nil {
+		t.node.Annotations = make(map[string]string)
+	}
+
+	for k := range t.keys {
+		if v, ok := t.node.Annotations[k]; ok {
+			t.data[k] = v
+		}
+	}
+	// Migrate tracking information from the default annotation namespace
+	for old, k := range t.legacyKeys {
+		if _, ok := t.data[k]; ok {
+			continue
+		}
+		if v, ok := t.node.Annotations[old]; ok {
+			t.data[k] = v
+		}
+	}
+
+	if t.storage == TrackingStorageConfigMap || m.staleTrackingConfigMaps.has(t.configName) {
+		cm, err := cli.CoreV1().ConfigMaps(t.namespace).Get(context.TODO(), t.configName, metav1.GetOptions{})
+		if err == nil {
+			t.configMap = cm
+			for k, v := range cm.Data {
+				if t.keys.Has(k) {
+					t.data[k] = v
+				}
+			}
+			for old, k := range t.legacyKeys {
+				if _, ok := cm.Data[k]; ok {
+					continue
+				}
+				if v, ok := cm.Data[old]; ok {
+					t.data[k] = v
+				}
+			}
+		} else if !apierrors.IsNotFound(err) {
+			return nil, nil, fmt.Errorf("failed to get tracking ConfigMap %s/%s: %w", t.namespace, t.configName, err)
+		}
+	}
+
+	t.stored = maps.Clone(t.data)
+
+	view := t.node.DeepCopy()
+	for k := range t.keys {
+		delete(view.Annotations, k)
+	}
+	for old := range t.legacyKeys {
+		delete(view.Annotations, old)
+	}
+	maps.Copy(view.Annotations, t.data)
+
+	return view, t, nil
+}
+
+// apply applies the json patches targeting tracking annotations to the
+// tracking information and returns the patches that need to be applied to the
+// node object. Tracking annotations are migrated between the node object and
+// the ConfigMap, if needed.
+func (t *nodeTracking) apply(patches []utils.JsonPatch) []utils.JsonPatch {
+	legacyKeys := t.presentLegacyKeys()
+	if t.storage == TrackingStorageAnnotations && t.configMap == nil && legacyKeys.Len() == 0 {
+		// Fast path, nothing to migrate
+		for _, p := range patches {
+			if k, ok := t.annotationKey(p); ok {
+				t.applyPatch(k, p)
+				t.applyPatchToNode(k, p)
+			}
+		}
+		return patches
+	}
+
+	nodePatches := make([]utils.JsonPatch, 0, len(patches))
+	for _, p := range patches {
+		if k, ok := t.annotationKey(p); ok {
+			t.applyPatch(k, p)
+		} else {
+			nodePatches = append(nodePatches, p)
+		}
+	}
+
+	// Tracking annotations in the default annotation namespace are removed
+	// together with the current ones
+	keys := t.keys.Union(legacyKeys)
+
+	var trackingPatches []utils.JsonPatch
+	if t.storage == TrackingStorageConfigMap {
+		// Remove tracking annotations from the node object, but only after
+		// the ConfigMap has been created so that no information is lost
+		if t.configMap != nil || len(t.data) == 0 {
+			trackingPatches = nodepatch.Items(keys, t.node.Annotations, nil, "/metadata/annotations", true)
+		}
+	} else {
+		trackingPatches = nodepatch.Items(keys, t.node.Annotations, t.data, "/metadata/annotations", true)
+	}
+	for _, p := range trackingPatches {
+		k, _ := t.annotationKey(p)
+		t.applyPatchToNode(k, p)
+	}
+	return append(nodePatches, trackingPatches...)
+}
+
+// presentLegacyKeys returns the names of the tracking annotations in the
+// default annotation namespace that still exist in the node object.
+func (t *nodeTracking) presentLegacyKeys() sets.Set[string] {
+	keys := sets.New[string]()
+	for old := range t.legacyKeys {
+		if _, ok := t.node.Annotations[old]; ok {
+			keys.Insert(old)
+		}
+	}
+	return keys
+}
+
+func (t *nodeTracking) annotationKey(p utils.JsonPatch) (string, bool) {
+	dir, key := path.Split(p.Path)
+	if dir != "/metadata/annotations/" {
+		return "", false
+	}
+	key = utils.UnescapeJsonPointer(key)
+	return key, t.keys.Has(key)
+}
+
+func (t *nodeTracking) applyPatch(key string, p utils.JsonPatch) {
+	if p.Op == "remove" {
+		delete(t.data, key)
+	} else {
+		t.data[key] = p.Value
+	}
+}
+
+func (t *nodeTracking) applyPatchToNode(key string, p utils.JsonPatch) {
+	if p.Op == "remove" {
+		delete(t.node.Annotations, key)
+	} else {
+		t.node.Annotations[key] = p.Value
+	}
+}
+
+// prepareConfigMap records the union of the stored and the new tracking
+// information in the tracking ConfigMap if ConfigMap storage is used. It must
+// be called before the node object is updated so that no NFD-managed node
+// properties are left untracked if the node update or the subsequent
+// writeConfigMap fails. Extra entries are cleaned up on the next update of the
+// node.
+func (t *nodeTracking) prepareConfigMap(cli k8sclient.Interface) error {
+	if t.storage != TrackingStorageConfigMap {
+		return nil
+	}
+
+	data := mergeTrackingData(t.stored, t.data)
+	if len(data) == 0 {
+		return nil
+	}
+	return t.storeConfigMap(cli, data)
+}
+
+// writeConfigMap stores the tracking information in the tracking ConfigMap
+// if ConfigMap storage is used. The ConfigMap is deleted if there is nothing
+// to track. It must be called after the node object has been updated, with
+// prepareConfigMap called before the update.
+func (t *nodeTracking) writeConfigMap(cli k8sclient.Interface) error {
+	if t.storage != TrackingStorageConfigMap {
+		return nil
+	}
+
+	if len(t.data) == 0 {
+		if err := t.deleteConfigMap(cli); err != nil {
+			return err
+		}
+	} else if err := t.storeConfigMap(cli, t.data); err != nil {
+		return err
+	}
+	t.stored = maps.Clone(t.data)
+	return nil
+}
+
+// storeConfigMap creates or updates the tracking ConfigMap with the given
+// data.
+func (t *nodeTracking) storeConfigMap(cli k8sclient.Interface, data map[string]string) error {
+	if t.configMap == nil {
+		cm := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      t.configName,
+				Namespace: t.namespace,
+				Labels:    map[string]string{nodetracking.ConfigMapLabel: "true"},
+				OwnerReferences: []metav1.OwnerReference{
+					{
+						APIVersion: "v1",
+						Kind:       "Node",
+						Name:       t.node.Name,
+						UID:        t.node.UID,
+					},
+				},
+			},
+			Data: maps.Clone(data),
+		}
+		created, err := cli.CoreV1().ConfigMaps(t.namespace).Create(context.TODO(), cm, metav1.CreateOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to create tracking ConfigMap %s/%s: %w", t.namespace, t.configName, err)
+		}
+		t.configMap = created
+		return nil
+	}
+
+	if maps.Equal(t.configMap.Data, data) {
+		return nil
+	}
+	cm := t.configMap.DeepCopy()
+	cm.Data = maps.Clone(data)
+	updated, err := cli.CoreV1().ConfigMaps(t.namespace).Update(context.TODO(), cm, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to update tracking ConfigMap %s/%s: %w", t.namespace, t.configName, err)
+	}
+	t.configMap = updated
+	return nil
+}
+
+// deleteStaleConfigMap deletes the tracking ConfigMap after the tracking
+// information has been migrated to node annotations.
+func (t *nodeTracking) deleteStaleConfigMap(cli k8sclient.Interface) error {
+	if t.storage == TrackingStorageConfigMap || t.configMap == nil {
+		return nil
+	}
+	if err := t.deleteConfigMap(cli); err != nil {
+		return err
+	}
+	t.stale.remove(t.configName)
+	return nil
+}
+
+func (t *nodeTracking) deleteConfigMap(cli k8sclient.Interface) error {
+	if t.configMap == nil {
+		return nil
+	}
+	err := cli.CoreV1().ConfigMaps(t.namespace).Delete(context.TODO(), t.configName, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete tracking ConfigMap %s/%s: %w", t.namespace, t.configName, err)
+	}
+	klog.V(2).InfoS("tracking ConfigMap deleted", "configMap", klog.KRef(t.namespace, t.configName))
+	t.configMap = nil
+	return nil
+}
+
+// mergeTrackingData returns the union of two sets of tracking information.
+// The values of tracking annotations are comma-separated lists of keys.
+func mergeTrackingData(a, b map[string]string) map[string]string {
+	ret := make(map[string]string, len(a)+len(b))
+	for _, data := range []map[string]string{a, b} {
+		for k, v := range data {
+			items := sets.New(strings.Split(ret[k], ",")...)
+			items.Insert(strings.Split(v, ",")...)
+			items.Delete("")
+			if items.Len() > 0 {
+				ret[k] = strings.Join(sets.List(items), ",")
+			}
+		}
+	}
+	return ret
+}
+
+// updateStaleTrackingConfigMaps refreshes the list of tracking ConfigMaps
+// that need to be migrated back to node annotations. The ConfigMaps are only
+// listed until none are left, i.e. there is no recurring overhead if
+// ConfigMap storage has not been used.
+func (m *nfdMaster) updateStaleTrackingConfigMaps() {
+	if m.config.TrackingStorage == TrackingStorageConfigMap {
+		m.staleTrackingConfigMaps.set(sets.New[string](), false)
+		return
+	}
+	if m.staleTrackingConfigMaps.isClean() {
+		return
+	}
+
+	cms, err := m.k8sClient.CoreV1().ConfigMaps(m.namespace).List(context.TODO(), metav1.ListOptions{LabelSelector: nodetracking.ConfigMapLabel})
+	if err != nil {
+		klog.ErrorS(err, "failed to list tracking ConfigMaps")
+		return
+	}
+	names := sets.New[string]()
+	for _, cm := range cms.Items {
+		names.Insert(cm.Name)
+	}
+	if names.Len() > 0 {
+		klog.InfoS("migrating node tracking information from ConfigMaps to node annotations", "numConfigMaps", names.Len())
+	}
+	m.staleTrackingConfigMaps.set(names, names.Len() == 0)
+}
@@ -26,7 +26,6 @@ import (
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
 	nfdclientset "sigs.k8s.io/node-feature-discovery/api/generated/clientset/versioned"
-	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
 )
 
 type updaterPool struct {
@@ -38,12 +37,31 @@ type updaterPool struct {
 	wg        sync.WaitGroup
 	nfgWg     sync.WaitGroup
 	nfdMaster *nfdMaster
+
+	// retrySlots limits the number of node updaters concurrently retrying
+	// failed node updates
+	retrySlots chan struct{}
+
+	// coalesceMu protects coalescing
+	coalesceMu sync.Mutex
+	// coalescing contains the node updates postponed for coalescing
+	// NodeFeature changes
+	coalescing map[string]*pendingNodeUpdate
+}
+
+// pendingNodeUpdate is a node update postponed until the NodeFeature changes
+// of the node have settled.
+type pendingNodeUpdate struct {
+	timer *time.Timer
+	// first is the time of the first coalesced change
+	first time.Time
 }
 
 func newUpdaterPool(nfdMaster *nfdMaster) *updaterPool {
 	return &updaterPool{
-		nfdMaster: nfdMaster,
-		wg:        sync.WaitGroup{},
+		nfdMaster:  nfdMaster,
+		wg:         sync.WaitGroup{},
+		coalescing: make(map[string]*pendingNodeUpdate),
 	}
 }
 
@@ -55,12 +73,35 @@ func (u *updaterPool) processNodeUpdateRequest(cli k8sclient.Interface) bool {
 
 	defer u.queue.Done(nodeName)
 
+	// Retries of failed updates may only occupy a part of the updaters so
+	// that repeatedly failing (and often slow) nodes cannot starve the
+	// updates of other nodes. A retry that does not get a slot is postponed
+	// like a failed attempt, i.e. with an increasing backoff.
+	if u.queue.NumRequeues(nodeName) > 0 {
+		select {
+		case u.retrySlots <- struct{}{}:
+			defer func() { <-u.retrySlots }()
+		default:
+			klog.V(2).InfoS("too many concurrent retries, postponing node update", "nodeName", nodeName)
+			u.queue.AddRateLimited(nodeName)
+			return true
+		}
+	}
+
 	nodeUpdateRequests.Inc()
 
 	// Check if node exists
-	if node, err := getNode(cli, nodeName); apierrors.IsNotFound(err) {
+	node, err := getNode(cli, nodeName)
+	if apierrors.IsNotFound(err) {
 		klog.InfoS("node not found, skip update", "nodeName", nodeName)
-	} else if err := u.nfdMaster.nfdAPIUpdateOneNode(cli, node); err != nil {
+		u.nfdMaster.purgeNode(nodeName)
+		u.queue.Forget(nodeName)
+		return true
+	} else if err == nil {
+		err = u.nfdMaster.nfdAPIUpdateOneNode(cli, node)
+	}
+
+	if err != nil {
 		if n := u.queue.NumRequeues(nodeName); n < 15 {
 			klog.InfoS("retrying node update", "nodeName", nodeName, "lastError", err, "numRetries", n)
 		} else {
@@ -71,6 +112,8 @@ func (u *updaterPool) processNodeUpdateRequest(cli k8sclient.Interface) bool {
 		u.queue.AddRateLimited(nodeName)
 		return true
 	}
+
+	u.nfdMaster.nodeReconciles.update(nodeName, time.Now())
 	u.queue.Forget(nodeName)
 	return true
 }
@@ -99,15 +142,23 @@ func (u *updaterPool) processNodeFeatureGroupUpdateRequest(cli nfdclientset.Inte
 	nodeFeatureGroupUpdateRequests.Inc()
 
 	// Check if NodeFeatureGroup exists
-	var nfg *nfdv1alpha1.NodeFeatureGroup
-	var err error
-	if nfg, err = getNodeFeatureGroup(cli, u.nfdMaster.namespace, nfgName); apierrors.IsNotFound(err) {
-		klog.InfoS("NodeFeatureGroup not found, skip update", "NodeFeatureGroupName", nfgName)
-	} else if err := u.nfdMaster.nfdAPIUpdateNodeFeatureGroup(u.nfdMaster.nfdClient, nfg); err != nil {
+	nfg, err := getNodeFeatureGroup(cli, u.nfdMaster.namespace, nfgName)
+	if apierrors.IsNotFound(err) {
+		klog.InfoS("NodeFeatureGroup not found, skip update", "nodeFeatureGroup", nfgName)
+		u.nfdMaster.nfgEvalCache.remove(nfgName)
+		u.nfgQueue.Forget(nfgName)
+		return true
+	} else if err == nil {
+		err = u.nfdMaster.nfdAPIUpdateNodeFeatureGroup(cli, nfg)
+	}
+
+	if err != nil {
 		if n := u.nfgQueue.NumRequeues(nfgName); n < 15 {
-			klog.InfoS("retrying NodeFeatureGroup update", "nodeFeatureGroup", klog.KObj(nfg), "lastError", err)
+			klog.InfoS("retrying NodeFeatureGroup update", "nodeFeatureGroup", nfgName, "lastError", err, "numRetries", n)
 		} else {
-			klog.ErrorS(err, "failed to update NodeFeatureGroup, queueing for retry", "nodeFeatureGroup", klog.KObj(nfg), "lastError", err, "numRetries", n)
+			klog.ErrorS(err, "failed to update NodeFeatureGroup, queueing for retry", "nodeFeatureGroup", nfgName, "numRetries", n)
+			// Count only long-failing attempts
+			nodeFeatureGroupUpdateFailures.Inc()
 		}
 		u.nfgQueue.AddRateLimited(nfgName)
 		return true
@@ -131,7 +182,9 @@ func (u *updaterPool) runNodeFeatureGroupUpdater() {
 	u.nfgWg.Done()
 }
 
-func (u *updaterPool) start(parallelism int) {
+// start starts the updater pool with the given number of node updaters and
+// NodeFeatureGroup updaters.
+func (u *updaterPool) start(parallelism, nfgParallelism int) {
 	u.Lock()
 	defer u.Unlock()
 
@@ -140,26 +193,35 @@ func (u *updaterPool) start(parallelism int) {
 		return
 	}
 
-	klog.InfoS("starting the NFD master updater pool", "parallelism", parallelism)
+	klog.InfoS("starting the NFD master updater pool", "parallelism", parallelism, "nodeFeatureGroupParallelism", nfgParallelism)
 
-	// Create ratelimiter. Mimic workqueue.DefaultControllerRateLimiter() but
-	// with modified per-item (node) rate limiting parameters.
-	rl := workqueue.NewTypedMaxOfRateLimiter[string](
-		workqueue.NewTypedItemExponentialFailureRateLimiter[string](50*time.Millisecond, 100*time.Second),
-		&workqueue.TypedBucketRateLimiter[string]{Limiter: rate.NewLimiter(rate.Limit(10), 100)},
-	)
-	u.queue = workqueue.NewTypedRateLimitingQueue[string](rl)
-	u.nfgQueue = workqueue.NewTypedRateLimitingQueue[string](rl)
+	// Separate rate limiters so that nodes and NodeFeatureGroups with the
+	// same name do not share their retry backoff
+	u.queue = workqueue.NewTypedRateLimitingQueue[string](newUpdaterRateLimiter())
+	u.nfgQueue = workqueue.NewTypedRateLimitingQueue[string](newUpdaterRateLimiter())
+	u.retrySlots = make(chan struct{}, max(parallelism/2, 1))
 
 	for i := 0; i < parallelism; i++ {
 		u.wg.Add(1)
 		go u.runNodeUpdater()
+	}
+	for i := 0; i < nfgParallelism; i++ {
 		u.nfgWg.Add(1)
 		go u.runNodeFeatureGroupUpdater()
 	}
 	u.started = true
 }
 
+// newUpdaterRateLimiter creates a rate limiter for the updater queues. Mimic
+// workqueue.DefaultControllerRateLimiter() but with modified per-item (node)
+// rate limiting parameters.
+func newUpdaterRateLimiter() workqueue.TypedRateLimiter[string] {
+	return workqueue.NewTypedMaxOfRateLimiter[string](
+		workqueue.NewTypedItemExponentialFailureRateLimiter[string](50*time.Millisecond, 100*time.Second),
+		&workqueue.TypedBucketRateLimiter[string]{Limiter: rate.NewLimiter(rate.Limit(10), 100)},
+	)
+}
+
 func (u *updaterPool) stop() {
 	u.Lock()
 	defer u.Unlock()
@@ -170,6 +232,12 @@ func (u *updaterPool) stop() {
 	}
 
 	klog.InfoS("stopping the NFD master updater pool")
+	u.coalesceMu.Lock()
+	for nodeName, p := range u.coalescing {
+		p.timer.Stop()
+		delete(u.coalescing, nodeName)
+	}
+	u.coalesceMu.Unlock()
 	u.queue.ShutDown()
 	u.wg.Wait()
 	u.nfgQueue.ShutDown()
@@ -189,6 +257,78 @@ func (u *updaterPool) addNode(nodeName string) {
 	u.queue.Add(nodeName)
 }
 
+// addNodeAfter queues a node for update after the given delay.
+func (u *updaterPool) addNodeAfter(nodeName string, delay time.Duration) {
+	u.RLock()
+	defer u.RUnlock()
+	u.queue.AddAfter(nodeName, delay)
+}
+
+// addNodeCoalesced queues a node for update after its NodeFeature changes
+// have settled. The update is postponed until no new changes have been
+// seen for the coalescing period, but at most the configured maximum delay
+// after the first change. The node is queued immediately if coalescing is
+// disabled.
+func (u *updaterPool) addNodeCoalesced(nodeName string) {
+	c := u.nfdMaster.config.NodeUpdateCoalescing
+	if c.Period.Duration <= 0 {
+		u.addNode(nodeName)
+		return
+	}
+
+	u.coalesceMu.Lock()
+	defer u.coalesceMu.Unlock()
+
+	now := time.Now()
+	if p, ok := u.coalescing[nodeName]; ok {
+		nodeUpdatesCoalesced.Inc()
+		delay := c.Period.Duration
+		if remaining := p.first.Add(c.MaxDelay.Duration).Sub(now); remaining < delay {
+			delay = max(remaining, 0)
+		}
+		// Stop fails if the timer already fired, in which case the update
+		// is being queued and will pick up the latest changes
+		if p.timer.Stop() {
+			p.timer.Reset(delay)
+		}
+		return
+	}
+
+	u.coalescing[nodeName] = &pendingNodeUpdate{
+		first: now,
+		timer: time.AfterFunc(c.Period.Duration, func() {
+			u.coalesceMu.Lock()
+			delete(u.coalescing, nodeName)
+			u.coalesceMu.Unlock()
+			u.addNode(nodeName)
+		}),
+	}
+}
+
+// cancelNode cancels the postponed update of a node and resets its retry
+// backoff. An update already in the queue is not removed but it is skipped
+// if the node does not exist.
+func (u *updaterPool) cancelNode(nodeName string) {
+	u.coalesceMu.Lock()
+	if p, ok := u.coalescing[nodeName]; ok {
+		p.timer.Stop()
+		delete(u.coalescing, nodeName)
+	}
+	u.coalesceMu.Unlock()
+
+	u.RLock()
+	defer u.RUnlock()
+	u.queue.Forget(nodeName)
+}
+
+// numPendingCoalesced returns the number of node updates postponed for
+// coalescing.
+func (u *updaterPool) numPendingCoalesced() int {
+	u.coalesceMu.Lock()
+	defer u.coalesceMu.Unlock()
+	return len(u.coalescing)
+}
+
 func (u *updaterPool) addNodeFeatureGroup(nodeFeatureGroupName string) {
 	u.RLock()
 	defer u.RUnlock()
@@ -17,19 +17,29 @@ limitations under the License.
 package nfdmaster
 
 import (
+	"context"
+	"fmt"
+	"strings"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	. "github.com/smartystreets/goconvey/convey"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sclient "k8s.io/client-go/kubernetes"
 	fakek8sclient "k8s.io/client-go/kubernetes/fake"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/util/workqueue"
 	fakenfdclient "sigs.k8s.io/node-feature-discovery/api/generated/clientset/versioned/fake"
 )
 
 func newFakeupdaterPool(nfdMaster *nfdMaster) *updaterPool {
 	return &updaterPool{
-		nfdMaster: nfdMaster,
-		wg:        sync.WaitGroup{},
+		nfdMaster:  nfdMaster,
+		wg:         sync.WaitGroup{},
+		coalescing: make(map[string]*pendingNodeUpdate),
 	}
 }
 
@@ -42,7 +52,7 @@ func TestUpdaterStart(t *testing.T) {
 	})
 
 	Convey("When starting the node updater pool", t, func() {
-		updaterPool.start(10)
+		updaterPool.start(10, 10)
 		Convey("Running node updater pool should report running=true", func() {
 			So(updaterPool.running(), ShouldBeTrue)
 		})
@@ -52,7 +62,7 @@ func TestUpdaterStart(t *testing.T) {
 			So(q.ShuttingDown(), ShouldBeFalse)
 		})
 
-		updaterPool.start(10)
+		updaterPool.start(10, 10)
 		Convey("Node updater pool queue should not change", func() {
 			So(updaterPool.queue, ShouldEqual, q)
 		})
@@ -63,7 +73,7 @@ func TestNodeUpdaterStop(t *testing.T) {
 	fakeMaster := newFakeMaster()
 	updaterPool := newFakeupdaterPool(fakeMaster)
 
-	updaterPool.start(10)
+	updaterPool.start(10, 10)
 	Convey("Running node updater pool should report running=true", t, func() {
 		So(updaterPool.running(), ShouldBeTrue)
 	})
@@ -87,7 +97,7 @@ func TestRunNodeUpdater(t *testing.T) {
 	fakeMaster.nfdController = newFakeNfdAPIController(fakenfdclient.NewSimpleClientset())
 	updaterPool := newFakeupdaterPool(fakeMaster)
 
-	updaterPool.start(10)
+	updaterPool.start(10, 10)
 	Convey("Queue has no element", t, func() {
 		So(updaterPool.queue.Len(), ShouldEqual, 0)
 	})
@@ -103,7 +113,7 @@ func TestRunNodeFeatureGroupUpdater(t *testing.T) {
 	fakeMaster.nfdController = newFakeNfdAPIController(fakenfdclient.NewSimpleClientset())
 	updaterPool := newFakeupdaterPool(fakeMaster)
 
-	updaterPool.start(10)
+	updaterPool.start(10, 10)
 	Convey("Queue has no element", t, func() {
 		So(updaterPool.nfgQueue.Len(), ShouldEqual, 0)
 	})
@@ -113,3 +123,162 @@ func TestRunNodeFeatureGroupUpdater(t *testing.T) {
 			withTimeout, 2*time.Second, ShouldEqual, 0)
 	})
 }
+
+func TestAddNodeCoalesced(t *testing.T) {
+	Convey("When coalescing node updates", t, func() {
+		fakeMaster := newFakeMaster()
+		updaterPool := newFakeupdaterPool(fakeMaster)
+		// No updaters are running so that queued nodes stay in the queue
+		updaterPool.queue = workqueue.NewTypedRateLimitingQueue[string](workqueue.DefaultTypedControllerRateLimiter[string]())
+		defer updaterPool.queue.ShutDown()
+		c := &fakeMaster.config.NodeUpdateCoalescing
+
+		Convey("nodes should be queued immediately if coalescing is disabled", func() {
+			updaterPool.addNodeCoalesced("node-1")
+			So(updaterPool.queue.Len(), ShouldEqual, 1)
+			So(updaterPool.numPendingCoalesced(), ShouldEqual, 0)
+		})
+		Convey("successive changes should result in one update", func() {
+			c.Period.Duration = 200 * time.Millisecond
+			coalesced := testutil.ToFloat64(nodeUpdatesCoalesced)
+
+			for i := 0; i < 3; i++ {
+				updaterPool.addNodeCoalesced("node-1")
+			}
+			updaterPool.addNodeCoalesced("node-2")
+			So(updaterPool.queue.Len(), ShouldEqual, 0)
+			So(updaterPool.numPendingCoalesced(), ShouldEqual, 2)
+			So(testutil.ToFloat64(nodeUpdatesCoalesced)-coalesced, ShouldEqual, 2)
+
+			So(func() interface{} { return updaterPool.queue.Len() },
+				withTimeout, 2*time.Second, ShouldEqual, 2)
+			So(updaterPool.numPendingCoalesced(), ShouldEqual, 0)
+		})
+		Convey("updates should not be postponed beyond the maximum delay", func() {
+			c.Period.Duration = 300 * time.Millisecond
+			c.MaxDelay.Duration = 500 * time.Millisecond
+
+			start := time.Now()
+			for updaterPool.queue.Len() == 0 && time.Since(start) < 3*time.Second {
+				updaterPool.addNodeCoalesced("node-1")
+				time.Sleep(50 * time.Millisecond)
+			}
+			So(updaterPool.queue.Len(), ShouldEqual, 1)
+			So(time.Since(start), ShouldBeLessThan, 2*time.Second)
+		})
+	})
+}
+
+func TestCancelNode(t *testing.T) {
+	Convey("When a node is deleted", t, func() {
+		fakeMaster := newFakeMaster()
+		updaterPool := newFakeupdaterPool(fakeMaster)
+		updaterPool.queue = workqueue.NewTypedRateLimitingQueue[string](workqueue.DefaultTypedControllerRateLimiter[string]())
+		defer updaterPool.queue.ShutDown()
+		fakeMaster.updaterPool = updaterPool
+		fakeMaster.config.NodeUpdateCoalescing.Period.Duration = 100 * time.Millisecond
+
+		updaterPool.addNodeCoalesced("node-1")
+		updaterPool.addNodeCoalesced("node-2")
+		updaterPool.queue.AddRateLimited("node-1")
+		fakeMaster.nodeReconciles.update("node-1", time.Now())
+
+		updaterPool.cancelNode("node-1")
+		fakeMaster.purgeNode("node-1")
+
+		Convey("postponed updates of the node should be cancelled", func() {
+			So(updaterPool.numPendingCoalesced(), ShouldEqual, 1)
+			So(updaterPool.queue.NumRequeues("node-1"), ShouldEqual, 0)
+			time.Sleep(300 * time.Millisecond)
+			// The retry of node-1 was queued before the deletion, node-2
+			// was queued by the coalescing timer
+			So(updaterPool.queue.Len(), ShouldEqual, 2)
+		})
+		Convey("the internal state of the node should be purged", func() {
+			So(fakeMaster.nodeReconciles.oldest(), ShouldBeZeroValue)
+		})
+	})
+}
+
+// slowNodeClient is a Kubernetes client where getting nodes named "bad-*"
+// fails slowly.
+type slowNodeClient struct {
+	k8sclient.Interface
+}
+
+func (c slowNodeClient) CoreV1() typedcorev1.CoreV1Interface {
+	return slowCoreV1Client{c.Interface.CoreV1()}
+}
+
+type slowCoreV1Client struct {
+	typedcorev1.CoreV1Interface
+}
+
+func (c slowCoreV1Client) Nodes() typedcorev1.NodeInterface {
+	return slowNodeInterface{c.CoreV1Interface.Nodes()}
+}
+
+type slowNodeInterface struct {
+	typedcorev1.NodeInterface
+}
+
+func (n slowNodeInterface) Get(ctx context.Context, name string, opts metav1.GetOptions) (*corev1.Node, error) {
+	if strings.HasPrefix(name, "bad-") {
+		time.Sleep(2 * time.Second)
+		return nil, fmt.Errorf("induced failure")
+	}
+	return n.NodeInterface.Get(ctx, name, opts)
+}
+
+func TestNodeUpdaterFairness(t *testing.T) {
+	Convey("When some nodes fail slowly", t, func() {
+		cli := fakek8sclient.NewSimpleClientset()
+		const numGood = 20
+		for i := 0; i < numGood; i++ {
+			_, err := cli.CoreV1().Nodes().Create(context.TODO(), &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("good-%d", i)}}, metav1.CreateOptions{})
+			So(err, ShouldBeNil)
+		}
+
+		fakeMaster := newFakeMaster(WithKubernetesClient(slowNodeClient{cli}))
+		fakeMaster.nfdController = newFakeNfdAPIController(fakenfdclient.NewSimpleClientset())
+		updaterPool := newFakeupdaterPool(fakeMaster)
+		updaterPool.start(4, 4)
+		defer updaterPool.stop()
+
+		// Get the failing nodes to the retry state
+		for i := 0; i < 4; i++ {
+			updaterPool.queue.AddRateLimited(fmt.Sprintf("bad-%d", i))
+		}
+		time.Sleep(200 * time.Millisecond)
+
+		start := time.Now()
+		for i := 0; i < numGood; i++ {
+			updaterPool.addNode(fmt.Sprintf("good-%d", i))
+		}
+
+		Convey("retries should not occupy all updaters", func() {
+			numReconciled := func() interface{} {
+				fakeMaster.nodeReconciles.Lock()
+				defer fakeMaster.nodeReconciles.Unlock()
+				return len(fakeMaster.nodeReconciles.times)
+			}
+			So(numReconciled, withTimeout, 3*time.Second, ShouldEqual, numGood)
+			So(time.Since(start), ShouldBeLessThan, time.Second)
+			So(updaterPool.queue.NumRequeues("bad-0"), ShouldBeGreaterThan, 0)
+		})
+	})
+}
+
+func TestUpdaterRateLimiters(t *testing.T) {
+	Convey("When a node update fails", t, func() {
+		updaterPool := newFakeupdaterPool(newFakeMaster())
+		updaterPool.start(1, 1)
+		updaterPool.stop()
+
+		updaterPool.queue.AddRateLimited("foo")
+		Convey("retry backoff should not be shared with NodeFeatureGroups", func() {
+			So(updaterPool.queue.NumRequeues("foo"), ShouldEqual, 1)
+			So(updaterPool.nfgQueue.NumRequeues("foo"), ShouldEqual, 0)
+		})
+	})
+}
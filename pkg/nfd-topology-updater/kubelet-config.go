@@ -0,0 +1,144 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nfdtopologyupdater
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"path/filepath"
+	"strconv"
+
+	"golang.org/x/net/context"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	kubeletconfigv1beta1 "k8s.io/kubelet/config/v1beta1"
+
+	"sigs.k8s.io/node-feature-discovery/pkg/utils/kubeconf"
+)
+
+// defaultKubeletPort is the kubelet port used if the node object does not
+// advertise one.
+const defaultKubeletPort = 10250
+
+// KubeletConfigAccess contains the settings for accessing the kubelet
+// configuration.
+type KubeletConfigAccess struct {
+	// CAFile is a CA bundle used for verifying the kubelet serving
+	// certificate.
+	CAFile string
+	// InsecureSkipVerify disables verification of the kubelet serving
+	// certificate. Ignored if CAFile is specified.
+	InsecureSkipVerify bool
+	// FallbackFile is a kubelet config file that is read if the kubelet
+	// configz endpoint is not available.
+	FallbackFile string
+}
+
+func newDefaultConfig(args Args) *NFDConfig {
+	c := &NFDConfig{
+		KubeletConfig: KubeletConfigAccess{InsecureSkipVerify: true},
+	}
+	if args.KubeletStateDir != "" {
+		c.KubeletConfig.FallbackFile = filepath.Join(args.KubeletStateDir, "config.yaml")
+	}
+	return c
+}
+
+// discoverKubeletConfigURI determines the kubelet configz endpoint from the
+// status of the node object.
+func (w *nfdTopologyUpdater) discoverKubeletConfigURI() (string, error) {
+	node, err := w.k8sClient.CoreV1().Nodes().Get(context.TODO(), w.nodeName, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get node %q: %w", w.nodeName, err)
+	}
+	uri, err := kubeletConfigURIFromNode(node)
+	if err != nil {
+		return "", err
+	}
+	klog.InfoS("discovered kubelet config endpoint", "uri", uri)
+	return uri, nil
+}
+
+func kubeletConfigURIFromNode(node *corev1.Node) (string, error) {
+	var address string
+	for _, addrType := range []corev1.NodeAddressType{corev1.NodeInternalIP, corev1.NodeExternalIP, corev1.NodeHostName} {
+		for _, a := range node.Status.Addresses {
+			if a.Type == addrType && a.Address != "" {
+				address = a.Address
+				break
+			}
+		}
+		if address != "" {
+			break
+		}
+	}
+	if address == "" {
+		return "", fmt.Errorf("no usable address found in the status of node %q", node.Name)
+	}
+
+	port := node.Status.DaemonEndpoints.KubeletEndpoint.Port
+	if port == 0 {
+		port = defaultKubeletPort
+	}
+	return "https://" + net.JoinHostPort(address, strconv.Itoa(int(port))) + "/configz", nil
+}
+
+func getKubeletConfigFunc(uri, apiAuthTokenFile string, c KubeletConfigAccess) (func() (*kubeletconfigv1beta1.KubeletConfiguration, error), error) {
+	u, err := url.ParseRequestURI(uri)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse -kubelet-config-uri: %w", err)
+	}
+
+	// init kubelet API client
+	switch u.Scheme {
+	case "file":
+		return func() (*kubeletconfigv1beta1.KubeletConfiguration, error) {
+			klConfig, err := kubeconf.GetKubeletConfigFromLocalFile(u.Path)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read kubelet config: %w", err)
+			}
+			return klConfig, err
+		}, nil
+	case "https":
+		restConfig, err := kubeconf.KubeletAPIConfig(u.String(), apiAuthTokenFile, c.CAFile, c.InsecureSkipVerify)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize rest config for kubelet config uri: %w", err)
+		}
+
+		return func() (*kubeletconfigv1beta1.KubeletConfiguration, error) {
+			klConfig, err := kubeconf.GetKubeletConfiguration(restConfig)
+			if err == nil {
+				return klConfig, nil
+			}
+			if c.FallbackFile == "" {
+				return nil, fmt.Errorf("failed to get kubelet config from configz endpoint: %w", err)
+			}
+
+			// The configz endpoint may be disabled in the kubelet
+			klog.V(2).InfoS("failed to get kubelet config from configz endpoint, falling back to local file", "error", err, "path", c.FallbackFile)
+			klConfig, fileErr := kubeconf.GetKubeletConfigFromLocalFile(c.FallbackFile)
+			if fileErr != nil {
+				return nil, fmt.Errorf("failed to get kubelet config from configz endpoint (%v) or from local file: %w", err, fileErr)
+			}
+			return klConfig, nil
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported URI scheme: %v", u.Scheme)
+}
@@ -25,6 +25,8 @@ import (
 const (
 	buildInfoQuery  = "build_info"
 	scanErrorsQuery = "scan_errors_total"
+	nrtUpdatesQuery = "nrt_updates_total"
+	nrtSkippedQuery = "nrt_updates_skipped_total"
 )
 
 const (
@@ -46,6 +48,16 @@ var (
 		Name:      scanErrorsQuery,
 		Help:      "Number of errors in scanning resource allocation of pods.",
 	})
+	nrtUpdates = prometheus.NewCounter(prometheus.CounterOpts{
+		Subsystem: nfdTopologyUpdaterPrefix,
+		Name:      nrtUpdatesQuery,
+		Help:      "Number of NodeResourceTopology object updates sent to the API server.",
+	})
+	nrtSkipped = prometheus.NewCounter(prometheus.CounterOpts{
+		Subsystem: nfdTopologyUpdaterPrefix,
+		Name:      nrtSkippedQuery,
+		Help:      "Number of NodeResourceTopology object updates skipped because nothing changed.",
+	})
 )
 
 // registerVersion exposes the Operator build version.
@@ -17,9 +17,9 @@ limitations under the License.
 package nfdtopologyupdater
 
 import (
+	"encoding/json"
 	"fmt"
 	"net"
-	"net/url"
 	"os"
 	"path/filepath"
 
@@ -28,6 +28,7 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/health"
 	"google.golang.org/grpc/health/grpc_health_v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -37,12 +38,12 @@ import (
 
 	"github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
 	topologyclientset "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/generated/clientset/versioned"
+	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
 	"sigs.k8s.io/node-feature-discovery/pkg/nfd-topology-updater/kubeletnotifier"
 	"sigs.k8s.io/node-feature-discovery/pkg/podres"
 	"sigs.k8s.io/node-feature-discovery/pkg/resourcemonitor"
 	"sigs.k8s.io/node-feature-discovery/pkg/topologypolicy"
 	"sigs.k8s.io/node-feature-discovery/pkg/utils"
-	"sigs.k8s.io/node-feature-discovery/pkg/utils/kubeconf"
 	"sigs.k8s.io/node-feature-discovery/pkg/version"
 	"sigs.k8s.io/yaml"
 )
@@ -63,13 +64,17 @@ type Args struct {
 	ConfigFile      string
 	KubeletStateDir string
 	GrpcHealthPort  int
+	// MetricsCertFile and MetricsKeyFile enable TLS on the metrics server.
+	MetricsCertFile string
+	MetricsKeyFile  string
 
 	Klog map[string]*utils.KlogFlagVal
 }
 
 // NFDConfig contains the configuration settings of NFDTopologyUpdater.
 type NFDConfig struct {
-	ExcludeList map[string][]string
+	ExcludeList   map[string][]string
+	KubeletConfig KubeletConfigAccess
 }
 
 type NfdTopologyUpdater interface {
@@ -88,6 +93,7 @@ type nfdTopologyUpdater struct {
 	config              *NFDConfig
 	kubernetesNamespace string
 	ownerRefs           []metav1.OwnerReference
+	nodeUID             types.UID
 	k8sClient           k8sclient.Interface
 	kubeletConfigFunc   func() (*kubeletconfigv1beta1.KubeletConfiguration, error)
 	healthServer        *grpc.Server
@@ -103,21 +109,15 @@ func NewTopologyUpdater(args Args, resourcemonitorArgs resourcemonitor.Args) (Nf
 	}
 	go ntf.Run()
 
-	kubeletConfigFunc, err := getKubeletConfigFunc(resourcemonitorArgs.KubeletConfigURI, resourcemonitorArgs.APIAuthTokenFile)
-	if err != nil {
-		return nil, err
-	}
-
 	nfd := &nfdTopologyUpdater{
 		args:                args,
 		resourcemonitorArgs: resourcemonitorArgs,
 		stop:                make(chan struct{}),
 		nodeName:            utils.NodeName(),
 		eventSource:         eventSource,
-		config:              &NFDConfig{},
+		config:              newDefaultConfig(args),
 		kubernetesNamespace: utils.GetKubernetesNamespace(),
 		ownerRefs:           []metav1.OwnerReference{},
-		kubeletConfigFunc:   kubeletConfigFunc,
 	}
 	if args.ConfigFile != "" {
 		nfd.configFilePath = filepath.Clean(args.ConfigFile)
@@ -187,14 +187,31 @@ func (w *nfdTopologyUpdater) Run() error {
 		return fmt.Errorf("faild to configure Node Feature Discovery Topology Updater: %w", err)
 	}
 
+	if w.resourcemonitorArgs.KubeletConfigURI == "" {
+		uri, err := w.discoverKubeletConfigURI()
+		if err != nil {
+			return fmt.Errorf("failed to determine kubelet config endpoint, please specify it with the -kubelet-config-uri flag: %w", err)
+		}
+		w.resourcemonitorArgs.KubeletConfigURI = uri
+	}
+	w.kubeletConfigFunc, err = getKubeletConfigFunc(w.resourcemonitorArgs.KubeletConfigURI, w.resourcemonitorArgs.APIAuthTokenFile, w.config.KubeletConfig)
+	if err != nil {
+		return err
+	}
+
 	// Register to metrics server
+	var httpServer *utils.HTTPServer
 	if w.args.MetricsPort > 0 {
-		m := utils.CreateMetricsServer(w.args.MetricsPort,
-			buildInfo,
-			scanErrors)
-		go m.Run()
+		httpServer = utils.NewHTTPServer(w.args.MetricsPort,
+			utils.WithMetrics(
+				buildInfo,
+				scanErrors,
+				nrtUpdates,
+				nrtSkipped),
+			utils.WithTLS(w.args.MetricsCertFile, w.args.MetricsKeyFile))
+		go httpServer.Run()
 		registerVersion(version.Get())
-		defer m.Stop()
+		defer httpServer.Stop()
 	}
 
 	var resScan resourcemonitor.ResourcesScanner
@@ -223,6 +240,9 @@ func (w *nfdTopologyUpdater) Run() error {
 			return fmt.Errorf("failed to start gRPC health server: %w", err)
 		}
 	}
+	if httpServer != nil {
+		httpServer.SetReady(true)
+	}
 
 	for {
 		select {
@@ -289,6 +309,15 @@ func (w *nfdTopologyUpdater) updateNodeResourceTopology(zoneInfo v1alpha2.ZoneLi
 		}
 	}
 
+	if w.nodeUID == "" {
+		node, err := w.k8sClient.CoreV1().Nodes().Get(context.TODO(), w.nodeName, metav1.GetOptions{})
+		if err != nil {
+			klog.ErrorS(err, "failed to get node UID", "nodeName", w.nodeName)
+		} else {
+			w.nodeUID = node.UID
+		}
+	}
+
 	nrt, err := w.topoClient.TopologyV1alpha2().NodeResourceTopologies().Get(context.TODO(), w.nodeName, metav1.GetOptions{})
 	if errors.IsNotFound(err) {
 		nrtNew := v1alpha2.NodeResourceTopology{
@@ -299,6 +328,7 @@ func (w *nfdTopologyUpdater) updateNodeResourceTopology(zoneInfo v1alpha2.ZoneLi
 			Zones:      zoneInfo,
 			Attributes: v1alpha2.AttributeList{},
 		}
+		w.setNodeUIDAnnotation(&nrtNew)
 
 		if err := w.updateNRTTopologyManagerInfo(&nrtNew); err != nil {
 			return err
@@ -317,6 +347,7 @@ func (w *nfdTopologyUpdater) updateNodeResourceTopology(zoneInfo v1alpha2.ZoneLi
 	nrtMutated := nrt.DeepCopy()
 	nrtMutated.Zones = zoneInfo
 	nrtMutated.OwnerReferences = w.ownerRefs
+	w.setNodeUIDAnnotation(nrtMutated)
 
 	attributes := scanResponse.Attributes
 
@@ -328,15 +359,77 @@ func (w *nfdTopologyUpdater) updateNodeResourceTopology(zoneInfo v1alpha2.ZoneLi
 
 	updateAttributes(&nrtMutated.Attributes, attributes)
 
-	nrtUpdated, err := w.topoClient.TopologyV1alpha2().NodeResourceTopologies().Update(context.TODO(), nrtMutated, metav1.UpdateOptions{})
+	patch, err := nrtMergePatch(nrt, nrtMutated)
+	if err != nil {
+		return err
+	}
+	if patch == nil {
+		klog.V(4).InfoS("NodeResourceTopology object unchanged, skipping update", "nodeResourceTopology", klog.KObj(nrt))
+		nrtSkipped.Inc()
+		return nil
+	}
+
+	nrtUpdated, err := w.topoClient.TopologyV1alpha2().NodeResourceTopologies().Patch(context.TODO(), nrt.Name, types.MergePatchType, patch, metav1.PatchOptions{})
 	if err != nil {
 		return fmt.Errorf("failed to update NodeResourceTopology: %w", err)
 	}
+	nrtUpdates.Inc()
 
 	klog.V(4).InfoS("NodeResourceTopology object updated", "nodeResourceTopology", utils.DelayedDumper(nrtUpdated))
 	return nil
 }
 
+// nrtMergePatch returns a JSON merge patch containing the fields of the
+// NodeResourceTopology object managed by nfd-topology-updater that differ
+// between the old and the new object, or nil if nothing changed. The
+// resource version of the old object is included for detecting conflicting
+// updates.
+func nrtMergePatch(oldNrt, newNrt *v1alpha2.NodeResourceTopology) ([]byte, error) {
+	metadata := map[string]interface{}{}
+	patch := map[string]interface{}{}
+
+	if !apiequality.Semantic.DeepEqual(oldNrt.Zones, newNrt.Zones) {
+		patch["zones"] = newNrt.Zones
+	}
+	if !apiequality.Semantic.DeepEqual(oldNrt.Attributes, newNrt.Attributes) {
+		patch["attributes"] = newNrt.Attributes
+	}
+	if !apiequality.Semantic.DeepEqual(oldNrt.TopologyPolicies, newNrt.TopologyPolicies) {
+		patch["topologyPolicies"] = newNrt.TopologyPolicies
+	}
+	if !apiequality.Semantic.DeepEqual(oldNrt.OwnerReferences, newNrt.OwnerReferences) {
+		metadata["ownerReferences"] = newNrt.OwnerReferences
+	}
+	if uid, ok := newNrt.Annotations[nfdv1alpha1.NodeUIDAnnotation]; ok && oldNrt.Annotations[nfdv1alpha1.NodeUIDAnnotation] != uid {
+		metadata["annotations"] = map[string]string{nfdv1alpha1.NodeUIDAnnotation: uid}
+	}
+
+	if len(patch) == 0 && len(metadata) == 0 {
+		return nil, nil
+	}
+	metadata["resourceVersion"] = oldNrt.ResourceVersion
+	patch["metadata"] = metadata
+
+	data, err := json.Marshal(patch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create NodeResourceTopology patch: %w", err)
+	}
+	return data, nil
+}
+
+// setNodeUIDAnnotation records the UID of the node in the NodeResourceTopology
+// object. It is used by nfd-gc for detecting stale objects of nodes that have
+// been re-created with the same name.
+func (w *nfdTopologyUpdater) setNodeUIDAnnotation(nrt *v1alpha2.NodeResourceTopology) {
+	if w.nodeUID == "" {
+		return
+	}
+	if nrt.Annotations == nil {
+		nrt.Annotations = make(map[string]string)
+	}
+	nrt.Annotations[nfdv1alpha1.NodeUIDAnnotation] = string(w.nodeUID)
+}
+
 func (w *nfdTopologyUpdater) updateNRTTopologyManagerInfo(nrt *v1alpha2.NodeResourceTopology) error {
 	policy, scope, err := w.detectTopologyPolicyAndScope()
 	if err != nil {
@@ -407,38 +500,3 @@ func updateAttributes(lhs *v1alpha2.AttributeList, rhs v1alpha2.AttributeList) {
 		updateAttribute(lhs, attr)
 	}
 }
-
-func getKubeletConfigFunc(uri, apiAuthTokenFile string) (func() (*kubeletconfigv1beta1.KubeletConfiguration, error), error) {
-	u, err := url.ParseRequestURI(uri)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse -kubelet-config-uri: %w", err)
-	}
-
-	// init kubelet API client
-	var klConfig *kubeletconfigv1beta1.KubeletConfiguration
-	switch u.Scheme {
-	case "file":
-		return func() (*kubeletconfigv1beta1.KubeletConfiguration, error) {
-			klConfig, err = kubeconf.GetKubeletConfigFromLocalFile(u.Path)
-			if err != nil {
-				return nil, fmt.Errorf("failed to read kubelet config: %w", err)
-			}
-			return klConfig, err
-		}, nil
-	case "https":
-		restConfig, err := kubeconf.InsecureConfig(u.String(), apiAuthTokenFile)
-		if err != nil {
-			return nil, fmt.Errorf("failed to initialize rest config for kubelet config uri: %w", err)
-		}
-
-		return func() (*kubeletconfigv1beta1.KubeletConfiguration, error) {
-			klConfig, err = kubeconf.GetKubeletConfiguration(restConfig)
-			if err != nil {
-				return nil, fmt.Errorf("failed to get kubelet config from configz endpoint: %w", err)
-			}
-			return klConfig, nil
-		}, nil
-	}
-
-	return nil, fmt.Errorf("unsupported URI scheme: %v", u.Scheme)
-}
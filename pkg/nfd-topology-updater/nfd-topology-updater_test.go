@@ -17,11 +17,17 @@ limitations under the License.
 package nfdtopologyupdater
 
 import (
+	"encoding/json"
 	"fmt"
 	"testing"
 
 	"github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
 	. "github.com/smartystreets/goconvey/convey"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
 )
 
 func TestTopologyUpdater(t *testing.T) {
@@ -93,6 +99,57 @@ func TestTopologyUpdater(t *testing.T) {
 	})
 }
 
+func TestNrtMergePatch(t *testing.T) {
+	Convey("Given a published NodeResourceTopology", t, func() {
+		oldNrt := &v1alpha2.NodeResourceTopology{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "node-1",
+				ResourceVersion: "42",
+				Annotations:     map[string]string{nfdv1alpha1.NodeUIDAnnotation: "uid-1"},
+			},
+			Zones: v1alpha2.ZoneList{
+				{Name: "node-0", Type: "Node", Resources: v1alpha2.ResourceInfoList{
+					{Name: "cpu", Available: resource.MustParse("4"), Allocatable: resource.MustParse("8"), Capacity: resource.MustParse("8")},
+				}},
+			},
+			Attributes: v1alpha2.AttributeList{{Name: TopologyManagerPolicyAttributeName, Value: "none"}},
+		}
+		newNrt := oldNrt.DeepCopy()
+
+		Convey("When nothing changed", func() {
+			patch, err := nrtMergePatch(oldNrt, newNrt)
+			Convey("Then no patch should be created", func() {
+				So(err, ShouldBeNil)
+				So(patch, ShouldBeNil)
+			})
+		})
+
+		Convey("When the available resources changed", func() {
+			newNrt.Zones[0].Resources[0].Available = resource.MustParse("2")
+			patch, err := nrtMergePatch(oldNrt, newNrt)
+			So(err, ShouldBeNil)
+
+			Convey("Then only the zones should be patched", func() {
+				var p map[string]interface{}
+				So(json.Unmarshal(patch, &p), ShouldBeNil)
+				So(p, ShouldContainKey, "zones")
+				So(p, ShouldNotContainKey, "attributes")
+				So(p["metadata"], ShouldResemble, map[string]interface{}{"resourceVersion": "42"})
+			})
+		})
+
+		Convey("When the node has been re-created", func() {
+			newNrt.Annotations[nfdv1alpha1.NodeUIDAnnotation] = "uid-2"
+			patch, err := nrtMergePatch(oldNrt, newNrt)
+			So(err, ShouldBeNil)
+
+			Convey("Then only the node UID annotation should be patched", func() {
+				So(string(patch), ShouldEqual, `{"metadata":{"annotations":{"`+nfdv1alpha1.NodeUIDAnnotation+`":"uid-2"},"resourceVersion":"42"}}`)
+			})
+		})
+	})
+}
+
 func getListOfNames(attrList v1alpha2.AttributeList) []string {
 	ret := make([]string, len(attrList))
 
@@ -110,3 +167,32 @@ func findAttributeByName(attrList v1alpha2.AttributeList, name string) (v1alpha2
 	}
 	return v1alpha2.AttributeInfo{}, fmt.Errorf("Attribute Not Found name:=%s", name)
 }
+
+func TestKubeletConfigURIFromNode(t *testing.T) {
+	Convey("When determining the kubelet config endpoint from a node object", t, func() {
+		node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}}
+
+		Convey("an error should be returned if the node has no addresses", func() {
+			_, err := kubeletConfigURIFromNode(node)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("the internal IP and the default port should be preferred", func() {
+			node.Status.Addresses = []corev1.NodeAddress{
+				{Type: corev1.NodeHostName, Address: "node-1"},
+				{Type: corev1.NodeInternalIP, Address: "10.0.0.1"},
+			}
+			uri, err := kubeletConfigURIFromNode(node)
+			So(err, ShouldBeNil)
+			So(uri, ShouldEqual, "https://10.0.0.1:10250/configz")
+		})
+
+		Convey("IPv6 addresses and the advertised kubelet port should be handled", func() {
+			node.Status.Addresses = []corev1.NodeAddress{{Type: corev1.NodeInternalIP, Address: "fd00::1"}}
+			node.Status.DaemonEndpoints.KubeletEndpoint.Port = 10251
+			uri, err := kubeletConfigURIFromNode(node)
+			So(err, ShouldBeNil)
+			So(uri, ShouldEqual, "https://[fd00::1]:10251/configz")
+		})
+	})
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nfdworker
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"sigs.k8s.io/node-feature-discovery/pkg/utils/hostpath"
+	"sigs.k8s.io/node-feature-discovery/source"
+)
+
+// hostDirs returns the host directories used by the feature sources mapped to
+// their location on the host system.
+func hostDirs() map[hostpath.HostDir]string {
+	return map[hostpath.HostDir]string{
+		hostpath.BootDir:  "/boot",
+		hostpath.EtcDir:   "/etc",
+		hostpath.SysfsDir: "/sys",
+		hostpath.UsrDir:   "/usr",
+		hostpath.VarDir:   "/var",
+		hostpath.LibDir:   "/lib",
+		hostpath.ProcDir:  "/proc",
+	}
+}
+
+// checkResult is the result of checking one host path required by a source.
+type checkResult struct {
+	source string
+	path   string
+	err    error
+}
+
+// Check verifies that the files and directories required by the enabled
+// feature sources are available and readable. The results are printed to out
+// and an error is returned if any of the checks failed.
+func Check(args *Args, out io.Writer) error {
+	w := &nfdWorker{
+		config: &NFDConfig{},
+		stop:   make(chan struct{}),
+	}
+	if args != nil {
+		w.args = *args
+	}
+	if w.args.ConfigFile != "" {
+		w.configFilePath = filepath.Clean(w.args.ConfigFile)
+	}
+
+	if err := w.configure(w.configFilePath, w.args.Options); err != nil {
+		fmt.Fprintf(out, "FAIL configuration: %v\n", err)
+		return fmt.Errorf("invalid configuration")
+	}
+
+	if !hostPathsMounted {
+		fmt.Fprintf(out, "SKIP host paths: not mounted on this platform\n")
+		return nil
+	}
+
+	results := w.checkHostPaths()
+	failed := 0
+	for _, r := range results {
+		if r.err != nil {
+			failed++
+			fmt.Fprintf(out, "FAIL %s: %v\n", r.source, r.err)
+		} else {
+			fmt.Fprintf(out, "OK   %s: %s\n", r.source, r.path)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d checks failed, features of the affected sources will be incomplete", failed, len(results))
+	}
+	return nil
+}
+
+// checkHostPaths checks the host paths required by all enabled feature
+// sources.
+func (w *nfdWorker) checkHostPaths() []checkResult {
+	results := []checkResult{}
+	for _, s := range w.featureSources {
+		hs, ok := s.(source.HostPathSource)
+		if !ok {
+			continue
+		}
+		for _, p := range hs.RequiredHostPaths() {
+			results = append(results, checkResult{source: s.Name(), path: p, err: checkHostPath(p)})
+		}
+	}
+	return results
+}
+
+// checkHostPath verifies that a file or directory exists and is readable.
+// The returned error contains a hint on how to fix the problem.
+func checkHostPath(path string) error {
+	if dir, hostDir := hostDirOf(path); dir != "" {
+		if _, err := os.Stat(dir); errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("%s does not exist, mount the host directory %s to %s in the nfd-worker container", path, hostDir, dir)
+		}
+	}
+
+	fi, err := os.Stat(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("%s does not exist, check that the correct host directory is mounted", path)
+		}
+		return fmt.Errorf("failed to access %s: %w", path, err)
+	}
+
+	if fi.IsDir() {
+		_, err = os.ReadDir(path)
+	} else {
+		var f *os.File
+		if f, err = os.Open(path); err == nil {
+			_, err = f.Read(make([]byte, 1))
+			if errors.Is(err, io.EOF) {
+				err = nil
+			}
+			f.Close()
+		}
+	}
+	if err != nil {
+		if errors.Is(err, fs.ErrPermission) {
+			return fmt.Errorf("%s is not readable, check the user and security context of the nfd-worker container: %w", path, err)
+		}
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return nil
+}
+
+// hostDirOf returns the host directory mount point containing path and the
+// corresponding directory on the host system. Empty strings are returned if
+// path is not under a known host directory or if no separate mount point is
+// used.
+func hostDirOf(path string) (string, string) {
+	for d, hostDir := range hostDirs() {
+		dir := filepath.Clean(string(d))
+		if dir == hostDir {
+			continue
+		}
+		if path == dir || strings.HasPrefix(path, dir+string(filepath.Separator)) {
+			return dir, hostDir
+		}
+	}
+	return "", ""
+}
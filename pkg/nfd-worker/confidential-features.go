@@ -0,0 +1,113 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nfdworker
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+
+	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
+)
+
+// confidentialHashLen is the length of the (hex-encoded) hashes of
+// confidential feature values. Kept short enough to be usable as label
+// values.
+const confidentialHashLen = 32
+
+// confidentialFeaturesConfig contains the configuration of features whose
+// values are published only as salted hashes.
+type confidentialFeaturesConfig struct {
+	Salt     string
+	SaltFile string
+	Features []string
+}
+
+// confidentialElement specifies one element (or all elements with "*") of a
+// feature whose values are hashed.
+type confidentialElement struct {
+	feature string
+	element string
+}
+
+// confidentialFeatures is the parsed confidential features configuration.
+type confidentialFeatures struct {
+	salt     []byte
+	elements map[string][]confidentialElement
+}
+
+// newConfidentialFeatures parses the confidential features configuration.
+// Nil is returned if no confidential features have been configured.
+func newConfidentialFeatures(c confidentialFeaturesConfig) (*confidentialFeatures, error) {
+	if len(c.Features) == 0 {
+		return nil, nil
+	}
+
+	salt := []byte(c.Salt)
+	if c.SaltFile != "" {
+		data, err := os.ReadFile(c.SaltFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read confidential features salt file: %w", err)
+		}
+		salt = []byte(strings.TrimSpace(string(data)))
+	}
+	if len(salt) == 0 {
+		return nil, fmt.Errorf("salt must be specified for confidential features")
+	}
+
+	cf := &confidentialFeatures{salt: salt, elements: make(map[string][]confidentialElement)}
+	for _, f := range c.Features {
+		split := strings.SplitN(f, ".", 3)
+		if len(split) != 3 || split[0] == "" || split[1] == "" || split[2] == "" {
+			return nil, fmt.Errorf("invalid confidential feature %q, must be in the form '<source>.<feature>.<element>'", f)
+		}
+		cf.elements[split[0]] = append(cf.elements[split[0]], confidentialElement{feature: split[1], element: split[2]})
+	}
+	return cf, nil
+}
+
+// apply replaces the values of confidential attribute and instance feature
+// elements of a source with their salted hashes.
+func (cf *confidentialFeatures) apply(sourceName string, features *nfdv1alpha1.Features) {
+	for _, e := range cf.elements[sourceName] {
+		if attrs, ok := features.Attributes[e.feature]; ok {
+			cf.hashElements(attrs.Elements, e.element)
+		}
+		if instances, ok := features.Instances[e.feature]; ok {
+			for _, i := range instances.Elements {
+				cf.hashElements(i.Attributes, e.element)
+			}
+		}
+	}
+}
+
+func (cf *confidentialFeatures) hashElements(elements map[string]string, name string) {
+	for k, v := range elements {
+		if name == "*" || k == name {
+			elements[k] = cf.hash(v)
+		}
+	}
+}
+
+func (cf *confidentialFeatures) hash(value string) string {
+	mac := hmac.New(sha256.New, cf.salt)
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))[:confidentialHashLen]
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nfdworker
+
+import (
+	"encoding/json"
+	"slices"
+	"sort"
+
+	"k8s.io/klog/v2"
+
+	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
+	"sigs.k8s.io/node-feature-discovery/source"
+)
+
+// deprecatedFeatureUsage describes the usage of one deprecated feature in the
+// feature matching rules of the worker.
+type deprecatedFeatureUsage struct {
+	// Elements contains the deprecated elements of the feature that are
+	// referenced. Empty if the whole feature is deprecated.
+	Elements []string `json:"elements,omitempty"`
+	// Rules contains the names of the rules referencing the feature.
+	Rules []string `json:"rules"`
+	// Replacement is a hint of what to use instead.
+	Replacement string `json:"replacement,omitempty"`
+	// RemovedIn is the release in which the feature is planned to be
+	// removed.
+	RemovedIn string `json:"removedIn,omitempty"`
+}
+
+// deprecatedFeatures contains the deprecated features referenced by the
+// rules, indexed by feature name.
+type deprecatedFeatures map[string]*deprecatedFeatureUsage
+
+// findDeprecatedFeatures returns the deprecated features that are referenced
+// by the given rules. A deprecated element is considered to be referenced if
+// a rule has a match expression for it. A deprecated feature is referenced
+// by any matcher term targeting the feature.
+func findDeprecatedFeatures(rules []nfdv1alpha1.Rule, deprecations []source.Deprecation) deprecatedFeatures {
+	ret := deprecatedFeatures{}
+	if len(deprecations) == 0 {
+		return ret
+	}
+
+	add := func(rule string, d source.Deprecation) {
+		u, ok := ret[d.Feature]
+		if !ok {
+			u = &deprecatedFeatureUsage{Replacement: d.Replacement, RemovedIn: d.RemovedIn}
+			ret[d.Feature] = u
+		}
+		if d.Element != "" && !slices.Contains(u.Elements, d.Element) {
+			u.Elements = append(u.Elements, d.Element)
+		}
+		if !slices.Contains(u.Rules, rule) {
+			u.Rules = append(u.Rules, rule)
+		}
+	}
+	checkTerms := func(rule string, terms nfdv1alpha1.FeatureMatcher) {
+		for _, term := range terms {
+			for _, d := range deprecations {
+				if term.Feature != d.Feature {
+					continue
+				}
+				if d.Element == "" {
+					add(rule, d)
+				} else if term.MatchExpressions != nil {
+					if _, ok := (*term.MatchExpressions)[d.Element]; ok {
+						add(rule, d)
+					}
+				}
+			}
+		}
+	}
+
+	for _, r := range rules {
+		if r.Disabled {
+			continue
+		}
+		checkTerms(r.Name, r.MatchFeatures)
+		for _, a := range r.MatchAny {
+			checkTerms(r.Name, a.MatchFeatures)
+		}
+	}
+
+	for _, u := range ret {
+		sort.Strings(u.Elements)
+		sort.Strings(u.Rules)
+	}
+	return ret
+}
+
+// annotationValue returns the deprecated features serialized for the
+// deprecated features annotation. An empty string is returned if no
+// deprecated features are in use.
+func (d deprecatedFeatures) annotationValue() (string, error) {
+	if len(d) == 0 {
+		return "", nil
+	}
+	data, err := json.Marshal(d)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// updateDeprecatedFeatures checks the rules of all enabled rule sources for
+// references to deprecated features, updating the metrics and logging a
+// warning for each referenced feature.
+func (w *nfdWorker) updateDeprecatedFeatures() {
+	var rules []nfdv1alpha1.Rule
+	for _, s := range w.labelSources {
+		if rs, ok := s.(source.RuleSource); ok {
+			rules = append(rules, rs.GetRules()...)
+		}
+	}
+	used := findDeprecatedFeatures(rules, source.GetDeprecations())
+
+	deprecatedFeaturesUsed.Reset()
+	for name, u := range used {
+		deprecatedFeaturesUsed.WithLabelValues(name).Set(float64(len(u.Rules)))
+		if _, ok := w.deprecatedFeatures[name]; !ok {
+			klog.InfoS("WARNING: deprecated feature is used by rules, please migrate", "feature", name, "elements", u.Elements, "rules", u.Rules, "replacement", u.Replacement, "removedIn", u.RemovedIn)
+		}
+	}
+	w.deprecatedFeatures = used
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nfdworker
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/node-feature-discovery/pkg/utils"
+)
+
+// FeaturesPath is the endpoint of the feature API serving the features
+// discovered in the latest feature discovery round.
+const FeaturesPath = "/features"
+
+// startFeatureAPIServer starts the HTTP server exposing the discovered
+// features on localhost. The returned server must be stopped by the caller.
+func (w *nfdWorker) startFeatureAPIServer() *utils.HTTPServer {
+	s := utils.NewHTTPServer(w.args.FeatureApiPort, utils.WithHost("localhost"))
+	s.Handle(FeaturesPath, w.featuresHandler())
+	go s.Run()
+	s.SetReady(true)
+	return s
+}
+
+// featuresHandler serves the features discovered in the latest feature
+// discovery round in JSON format.
+func (w *nfdWorker) featuresHandler() http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		features := w.features.Load()
+		if features == nil {
+			http.Error(rw, "feature discovery has not completed yet", http.StatusServiceUnavailable)
+			return
+		}
+		rw.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(rw)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(features); err != nil {
+			klog.ErrorS(err, "failed to write features response")
+		}
+	})
+}
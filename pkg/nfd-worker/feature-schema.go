@@ -0,0 +1,46 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nfdworker
+
+import (
+	"io"
+	"net/http"
+
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/node-feature-discovery/source/schema"
+)
+
+// FeatureSchemaPath is the HTTP endpoint serving the feature schema.
+const FeatureSchemaPath = "/feature-schema"
+
+// PrintFeatureSchema writes the schema of the features produced by the
+// built-in feature sources to out, in JSON format.
+func PrintFeatureSchema(out io.Writer) error {
+	_, err := out.Write(schema.JSON())
+	return err
+}
+
+// featureSchemaHandler serves the feature schema in JSON format.
+func featureSchemaHandler() http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+		if err := PrintFeatureSchema(rw); err != nil {
+			klog.ErrorS(err, "failed to write feature schema response")
+		}
+	})
+}
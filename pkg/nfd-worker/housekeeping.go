@@ -0,0 +1,98 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nfdworker
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"k8s.io/utils/cpuset"
+
+	"sigs.k8s.io/node-feature-discovery/pkg/utils/hostpath"
+)
+
+const (
+	ioPriorityClassIdle       = "idle"
+	ioPriorityClassBestEffort = "best-effort"
+)
+
+// housekeepingConfig contains the configuration for confining nfd-worker to
+// housekeeping CPUs and lowering its I/O priority so that feature discovery
+// does not interfere with latency-critical workloads.
+type housekeepingConfig struct {
+	// CPUs is the list of CPUs (in cpuset list format, e.g. "0-1,8") that
+	// nfd-worker is pinned to.
+	CPUs string
+	// NUMANode pins nfd-worker to the CPUs of a NUMA node. Mutually
+	// exclusive with CPUs.
+	NUMANode *int
+	// IOPriorityClass is the I/O scheduling class of nfd-worker, "idle" or
+	// "best-effort" (with the lowest priority). Empty keeps the default.
+	IOPriorityClass string
+}
+
+// housekeeping is the parsed housekeeping configuration.
+type housekeeping struct {
+	cpus    cpuset.CPUSet
+	ioClass string
+}
+
+// newHousekeeping parses the housekeeping configuration.
+func newHousekeeping(c housekeepingConfig) (*housekeeping, error) {
+	h := &housekeeping{ioClass: c.IOPriorityClass}
+
+	switch {
+	case c.CPUs != "" && c.NUMANode != nil:
+		return nil, fmt.Errorf("only one of core.housekeeping.cpus and core.housekeeping.numaNode may be specified")
+	case c.CPUs != "":
+		cpus, err := cpuset.Parse(c.CPUs)
+		if err != nil {
+			return nil, fmt.Errorf("invalid core.housekeeping.cpus %q: %w", c.CPUs, err)
+		}
+		h.cpus = cpus
+	case c.NUMANode != nil:
+		path := hostpath.SysfsDir.Path("bus/node/devices", "node"+strconv.Itoa(*c.NUMANode), "cpulist")
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CPUs of NUMA node %d: %w", *c.NUMANode, err)
+		}
+		cpus, err := cpuset.Parse(strings.TrimSpace(string(data)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CPUs of NUMA node %d: %w", *c.NUMANode, err)
+		}
+		if cpus.IsEmpty() {
+			return nil, fmt.Errorf("NUMA node %d has no CPUs", *c.NUMANode)
+		}
+		h.cpus = cpus
+	}
+
+	switch c.IOPriorityClass {
+	case "", ioPriorityClassIdle, ioPriorityClassBestEffort:
+	default:
+		return nil, fmt.Errorf("invalid core.housekeeping.ioPriorityClass %q, must be one of %q or %q",
+			c.IOPriorityClass, ioPriorityClassIdle, ioPriorityClassBestEffort)
+	}
+
+	return h, nil
+}
+
+// enabled returns true if CPU pinning or I/O priority has been configured.
+func (h *housekeeping) enabled() bool {
+	return !h.cpus.IsEmpty() || h.ioClass != ""
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nfdworker
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+
+	"golang.org/x/sys/unix"
+	"k8s.io/klog/v2"
+)
+
+// I/O priority definitions of the ioprio_set system call, see
+// include/uapi/linux/ioprio.h of the Linux kernel.
+const (
+	ioprioWhoProcess = 1
+	ioprioClassShift = 13
+	ioprioClassBE    = 2
+	ioprioClassIdle  = 3
+	// ioprioBELowest is the lowest priority level of the best-effort class
+	ioprioBELowest = 7
+)
+
+// taskDir lists the threads of nfd-worker. CPU affinity and I/O priority are
+// per-thread attributes so they need to be set on all of them. New threads
+// inherit them from the thread creating them.
+var taskDir = "/proc/self/task"
+
+var (
+	housekeepingMu      sync.Mutex
+	housekeepingApplied bool
+	origAffinity        unix.CPUSet
+	origIOPriority      int
+)
+
+// apply pins all threads of nfd-worker to the housekeeping CPUs and sets
+// their I/O priority. The original settings are restored if housekeeping has
+// been disabled.
+func (h *housekeeping) apply() error {
+	housekeepingMu.Lock()
+	defer housekeepingMu.Unlock()
+
+	if !housekeepingApplied {
+		if !h.enabled() {
+			return nil
+		}
+		if err := unix.SchedGetaffinity(0, &origAffinity); err != nil {
+			return fmt.Errorf("failed to get CPU affinity: %w", err)
+		}
+		prio, _, errno := unix.Syscall(unix.SYS_IOPRIO_GET, ioprioWhoProcess, 0, 0)
+		if errno != 0 {
+			return fmt.Errorf("failed to get I/O priority: %w", errno)
+		}
+		origIOPriority = int(prio)
+	}
+
+	affinity := origAffinity
+	if !h.cpus.IsEmpty() {
+		affinity.Zero()
+		for _, cpu := range h.cpus.List() {
+			affinity.Set(cpu)
+		}
+	}
+
+	ioPriority := origIOPriority
+	switch h.ioClass {
+	case ioPriorityClassIdle:
+		ioPriority = ioprioClassIdle << ioprioClassShift
+	case ioPriorityClassBestEffort:
+		ioPriority = ioprioClassBE<<ioprioClassShift | ioprioBELowest
+	}
+
+	tasks, err := os.ReadDir(taskDir)
+	if err != nil {
+		return fmt.Errorf("failed to list threads: %w", err)
+	}
+	for _, t := range tasks {
+		tid, err := strconv.Atoi(t.Name())
+		if err != nil {
+			continue
+		}
+		// Threads may exit at any time
+		if err := unix.SchedSetaffinity(tid, &affinity); err != nil && !errors.Is(err, unix.ESRCH) {
+			return fmt.Errorf("failed to set CPU affinity to %s: %w", h.cpus, err)
+		}
+		if _, _, errno := unix.Syscall(unix.SYS_IOPRIO_SET, ioprioWhoProcess, uintptr(tid), uintptr(ioPriority)); errno != 0 && errno != unix.ESRCH {
+			return fmt.Errorf("failed to set I/O priority: %w", errno)
+		}
+	}
+	housekeepingApplied = h.enabled()
+
+	if housekeepingApplied {
+		klog.InfoS("housekeeping settings applied", "cpus", h.cpus.String(), "ioPriorityClass", h.ioClass)
+	} else {
+		klog.InfoS("housekeeping disabled, original CPU affinity and I/O priority restored")
+	}
+
+	return nil
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nfdworker
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"golang.org/x/sys/unix"
+	"k8s.io/utils/cpuset"
+)
+
+func TestHousekeepingApply(t *testing.T) {
+	Convey("When applying housekeeping settings", t, func() {
+		var orig unix.CPUSet
+		So(unix.SchedGetaffinity(0, &orig), ShouldBeNil)
+		cpu := -1
+		for i := 0; cpu < 0; i++ {
+			if orig.IsSet(i) {
+				cpu = i
+			}
+		}
+
+		h := &housekeeping{cpus: cpuset.New(cpu), ioClass: ioPriorityClassBestEffort}
+		So(h.apply(), ShouldBeNil)
+
+		Convey("all threads should be pinned", func() {
+			var cur unix.CPUSet
+			So(unix.SchedGetaffinity(0, &cur), ShouldBeNil)
+			So(cur.Count(), ShouldEqual, 1)
+			So(cur.IsSet(cpu), ShouldBeTrue)
+			prio, _, errno := unix.Syscall(unix.SYS_IOPRIO_GET, ioprioWhoProcess, 0, 0)
+			So(errno, ShouldEqual, unix.Errno(0))
+			So(int(prio), ShouldEqual, ioprioClassBE<<ioprioClassShift|ioprioBELowest)
+		})
+
+		Convey("original settings should be restored when disabled", func() {
+			So((&housekeeping{}).apply(), ShouldBeNil)
+			var cur unix.CPUSet
+			So(unix.SchedGetaffinity(0, &cur), ShouldBeNil)
+			So(cur, ShouldEqual, orig)
+		})
+
+		So((&housekeeping{}).apply(), ShouldBeNil)
+	})
+}
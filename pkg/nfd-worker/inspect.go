@@ -0,0 +1,68 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nfdworker
+
+import (
+	"path/filepath"
+
+	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
+)
+
+// InspectResult contains the outcome of a local feature discovery run.
+type InspectResult struct {
+	// Labels are the feature labels that nfd-worker would advertise.
+	Labels Labels `json:"labels"`
+	// Features are the raw features discovered by the feature sources.
+	Features *nfdv1alpha1.Features `json:"features"`
+	// SourceErrors contains the errors of failed feature sources, keyed by
+	// the source name.
+	SourceErrors map[string]string `json:"sourceErrors,omitempty"`
+}
+
+// Inspect runs feature discovery once, using the same configuration handling
+// and label generation as nfd-worker, and returns the discovered features and
+// labels. The Kubernetes API is not accessed.
+func Inspect(args *Args) (*InspectResult, error) {
+	w := &nfdWorker{
+		config: &NFDConfig{},
+		stop:   make(chan struct{}),
+	}
+	if args != nil {
+		w.args = *args
+	}
+	if w.args.ConfigFile != "" {
+		w.configFilePath = filepath.Clean(w.args.ConfigFile)
+	}
+
+	if err := w.configure(w.configFilePath, w.args.Options); err != nil {
+		return nil, err
+	}
+
+	w.discoverFeatures()
+
+	ret := &InspectResult{
+		Labels:   createFeatureLabels(w.readyLabelSources(), w.config.Core.LabelWhiteList.Regexp),
+		Features: w.getFeatures(),
+	}
+	if len(w.sourceErrors) > 0 {
+		ret.SourceErrors = make(map[string]string, len(w.sourceErrors))
+		for name, e := range w.sourceErrors {
+			ret.SourceErrors[name] = e.Error
+		}
+	}
+	return ret, nil
+}
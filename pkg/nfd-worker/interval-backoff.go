@@ -0,0 +1,61 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nfdworker
+
+import (
+	"time"
+)
+
+// intervalBackoff lengthens the interval between feature discovery rounds
+// when consecutive rounds produce no changes in the discovered features.
+type intervalBackoff struct {
+	// base is the configured sleep interval.
+	base time.Duration
+	// max is the upper limit of the interval. Backoff is disabled if max is
+	// not greater than base.
+	max time.Duration
+	// current is the interval currently in use.
+	current time.Duration
+}
+
+func newIntervalBackoff(base, max time.Duration) *intervalBackoff {
+	b := &intervalBackoff{base: base, max: max}
+	b.reset()
+	return b
+}
+
+// next returns the interval to the next feature discovery round. The interval
+// is doubled, up to the maximum, if the features did not change and reset to
+// the base interval otherwise.
+func (b *intervalBackoff) next(changed bool) time.Duration {
+	if changed || b.base <= 0 || b.max <= b.base {
+		return b.reset()
+	}
+	b.current *= 2
+	if b.current > b.max {
+		b.current = b.max
+	}
+	sleepInterval.Set(b.current.Seconds())
+	return b.current
+}
+
+// reset returns the interval to the base interval.
+func (b *intervalBackoff) reset() time.Duration {
+	b.current = b.base
+	sleepInterval.Set(b.current.Seconds())
+	return b.current
+}
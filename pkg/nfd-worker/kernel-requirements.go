@@ -0,0 +1,92 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nfdworker
+
+import (
+	"fmt"
+	"strings"
+
+	utilversion "k8s.io/apimachinery/pkg/util/version"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/node-feature-discovery/source"
+	"sigs.k8s.io/node-feature-discovery/source/kernel"
+)
+
+// getKernelVersion runs discovery of the kernel source and returns the
+// detected kernel version.
+func getKernelVersion() (*utilversion.Version, error) {
+	s := source.GetFeatureSource(kernel.Name)
+	if s == nil {
+		return nil, fmt.Errorf("%q feature source not registered", kernel.Name)
+	}
+	if err := s.Discover(); err != nil {
+		return nil, err
+	}
+
+	attrs, ok := s.GetFeatures().Attributes[kernel.VersionFeature]
+	if !ok {
+		return nil, fmt.Errorf("kernel version not detected")
+	}
+
+	v := attrs.Elements["major"]
+	for _, n := range []string{"minor", "revision"} {
+		if attrs.Elements[n] == "" {
+			break
+		}
+		v += "." + attrs.Elements[n]
+	}
+	return utilversion.ParseGeneric(v)
+}
+
+// filterByKernelVersion drops feature sources whose kernel version
+// requirement is not met.
+func filterByKernelVersion(sources []source.FeatureSource, reqs map[string]string, kernelVersion *utilversion.Version) []source.FeatureSource {
+	disabled := make(map[string]struct{})
+
+	for name, minVersionStr := range reqs {
+		minVersion, err := utilversion.ParseGeneric(minVersionStr)
+		if err != nil {
+			klog.ErrorS(err, "invalid version in core.minKernelVersion, ignoring", "name", name, "version", minVersionStr)
+			continue
+		}
+		if !kernelVersion.AtLeast(minVersion) {
+			klog.InfoS("disabling feature source, kernel version requirement not met", "featureSource", name, "minKernelVersion", minVersionStr, "kernelVersion", kernelVersion)
+			disabled[name] = struct{}{}
+		}
+	}
+
+	enabled := make([]source.FeatureSource, 0, len(sources))
+	for _, s := range sources {
+		if _, ok := disabled[s.Name()]; !ok {
+			enabled = append(enabled, s)
+		}
+	}
+	return enabled
+}
+
+// validateKernelRequirements checks that the kernel version requirements only
+// target feature sources. Individual features of a source cannot be disabled
+// as the discovery of a source is always run as a whole.
+func validateKernelRequirements(reqs map[string]string) error {
+	for name := range reqs {
+		if strings.Contains(name, ".") {
+			return fmt.Errorf("invalid core.minKernelVersion entry %q: only feature sources can be disabled, not individual features", name)
+		}
+	}
+	return nil
+}
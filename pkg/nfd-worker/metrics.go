@@ -18,6 +18,8 @@ package nfdworker
 
 import (
 	"github.com/prometheus/client_golang/prometheus"
+
+	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
 	"sigs.k8s.io/node-feature-discovery/pkg/version"
 )
 
@@ -25,6 +27,13 @@ import (
 const (
 	buildInfoQuery                = "build_info"
 	featureDiscoveryDurationQuery = "feature_discovery_duration_seconds"
+	sourceDiscoveryDurationQuery  = "source_discovery_duration_seconds"
+	sourceDiscoveryErrorsQuery    = "source_discovery_errors_total"
+	sourceFeaturesQuery           = "source_features"
+	sourceDiscoveryTimeoutsQuery  = "source_discovery_timeouts_total"
+	sourceDisabledQuery           = "source_disabled"
+	sleepIntervalQuery            = "sleep_interval_seconds"
+	deprecatedFeaturesUsedQuery   = "deprecated_features_used"
 )
 
 const (
@@ -42,6 +51,62 @@ var (
 		},
 		[]string{"node"},
 	)
+	sourceDiscoveryDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Subsystem: nfdWorkerPrefix,
+			Name:      sourceDiscoveryDurationQuery,
+			Help:      "Time taken to discover the features of a feature source",
+			Buckets:   []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1},
+		},
+		[]string{"source"},
+	)
+	sourceDiscoveryErrors = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: nfdWorkerPrefix,
+			Name:      sourceDiscoveryErrorsQuery,
+			Help:      "Number of failed feature discovery runs of a feature source",
+		},
+		[]string{"source"},
+	)
+	sourceFeatures = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: nfdWorkerPrefix,
+			Name:      sourceFeaturesQuery,
+			Help:      "Number of feature elements (flags, attributes and instances) discovered by a feature source",
+		},
+		[]string{"source"},
+	)
+	sourceDiscoveryTimeouts = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: nfdWorkerPrefix,
+			Name:      sourceDiscoveryTimeoutsQuery,
+			Help:      "Number of feature discovery runs of a feature source that timed out",
+		},
+		[]string{"source"},
+	)
+	sourceDisabled = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: nfdWorkerPrefix,
+			Name:      sourceDisabledQuery,
+			Help:      "Whether a feature source is disabled by the circuit breaker because of repeated failures",
+		},
+		[]string{"source"},
+	)
+	sleepInterval = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Subsystem: nfdWorkerPrefix,
+			Name:      sleepIntervalQuery,
+			Help:      "Current interval between feature discovery rounds, including backoff",
+		},
+	)
+	deprecatedFeaturesUsed = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: nfdWorkerPrefix,
+			Name:      deprecatedFeaturesUsedQuery,
+			Help:      "Number of feature matching rules referencing a deprecated feature",
+		},
+		[]string{"feature"},
+	)
 	buildInfo = prometheus.NewGauge(prometheus.GaugeOpts{
 		Subsystem: nfdWorkerPrefix,
 		Name:      buildInfoQuery,
@@ -56,3 +121,18 @@ var (
 func registerVersion(version string) {
 	buildInfo.SetToCurrentTime()
 }
+
+// countFeatures returns the total number of feature elements.
+func countFeatures(f *nfdv1alpha1.Features) int {
+	n := 0
+	for _, s := range f.Flags {
+		n += len(s.Elements)
+	}
+	for _, s := range f.Attributes {
+		n += len(s.Elements)
+	}
+	for _, s := range f.Instances {
+		n += len(s.Elements)
+	}
+	return n
+}
@@ -17,22 +17,33 @@ limitations under the License.
 package nfdworker
 
 import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"regexp"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	. "github.com/smartystreets/goconvey/convey"
 	"github.com/vektra/errors"
+	utilversion "k8s.io/apimachinery/pkg/util/version"
 	fakeclient "k8s.io/client-go/kubernetes/fake"
 
 	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
 	"sigs.k8s.io/node-feature-discovery/pkg/utils"
+	"sigs.k8s.io/node-feature-discovery/pkg/utils/hostpath"
 	"sigs.k8s.io/node-feature-discovery/source"
 	"sigs.k8s.io/node-feature-discovery/source/cpu"
 	"sigs.k8s.io/node-feature-discovery/source/kernel"
 	"sigs.k8s.io/node-feature-discovery/source/pci"
+	"sigs.k8s.io/node-feature-discovery/source/schema"
 )
 
 const fakeLabelSourceName string = "testSource"
@@ -189,6 +200,38 @@ sources:
 				So(c.(*pci.Config).DeviceClassWhitelist, ShouldResemble, []string{"03"})
 			})
 		})
+
+		Convey("and a config directory is specified", func() {
+			worker.args = Args{}
+			dir := t.TempDir()
+			So(os.WriteFile(filepath.Join(dir, "10-base.conf"), []byte(`
+core:
+  noPublish: false
+  labelWhiteList: "foo"
+sources:
+  kernel:
+    configOpts: ["DMI"]
+  pci:
+    deviceClassWhitelist: ["ff"]`), 0644), ShouldBeNil)
+			So(os.WriteFile(filepath.Join(dir, "20-pool.conf"), []byte(`
+core:
+  noPublish: true
+sources:
+  pci:
+    deviceClassWhitelist: ["03"]`), 0644), ShouldBeNil)
+			So(os.WriteFile(filepath.Join(dir, "30-ignored.yaml"), []byte(`core: {labelWhiteList: "bar"}`), 0644), ShouldBeNil)
+			So(worker.configure(dir, ""), ShouldBeNil)
+
+			Convey("config files should be merged in lexical order", func() {
+				So(worker.config.Core.NoPublish, ShouldBeTrue)
+				So(worker.config.Core.LabelWhiteList.String(), ShouldEqual, "foo")
+
+				c := source.GetConfigurableSource("kernel").GetConfig()
+				So(c.(*kernel.Config).ConfigOpts, ShouldResemble, []string{"DMI"})
+				c = source.GetConfigurableSource("pci").GetConfig()
+				So(c.(*pci.Config).DeviceClassWhitelist, ShouldResemble, []string{"03"})
+			})
+		})
 	})
 }
 
@@ -264,3 +307,661 @@ func TestCreateFeatureLabels(t *testing.T) {
 		})
 	})
 }
+
+func TestFilterByKernelVersion(t *testing.T) {
+	Convey("When filtering feature sources by kernel version", t, func() {
+		sources := []source.FeatureSource{source.GetFeatureSource("cpu"), source.GetFeatureSource("pci")}
+		kernelVersion := utilversion.MustParseGeneric("4.19.0")
+
+		Convey("sources with unmet requirements should be disabled", func() {
+			reqs := map[string]string{"cpu": "4.4", "pci": "5.10"}
+			enabled := filterByKernelVersion(sources, reqs, kernelVersion)
+			So(len(enabled), ShouldEqual, 1)
+			So(enabled[0].Name(), ShouldEqual, "cpu")
+		})
+
+		Convey("requirements with invalid versions should be ignored", func() {
+			reqs := map[string]string{"pci": "invalid"}
+			enabled := filterByKernelVersion(sources, reqs, kernelVersion)
+			So(len(enabled), ShouldEqual, 2)
+		})
+	})
+
+	Convey("When validating kernel version requirements", t, func() {
+		So(validateKernelRequirements(map[string]string{"pci": "4.4"}), ShouldBeNil)
+		So(validateKernelRequirements(map[string]string{"cpu.rdt": "4.10"}), ShouldNotBeNil)
+	})
+}
+
+func TestConfidentialFeatures(t *testing.T) {
+	Convey("When parsing confidential features config", t, func() {
+		Convey("nothing should be returned if no features are configured", func() {
+			cf, err := newConfidentialFeatures(confidentialFeaturesConfig{Salt: "s"})
+			So(err, ShouldBeNil)
+			So(cf, ShouldBeNil)
+		})
+		Convey("an error should be returned if salt is missing", func() {
+			_, err := newConfidentialFeatures(confidentialFeaturesConfig{Features: []string{"system.dmiid.product_serial"}})
+			So(err, ShouldNotBeNil)
+		})
+		Convey("an error should be returned for invalid feature names", func() {
+			_, err := newConfidentialFeatures(confidentialFeaturesConfig{Salt: "s", Features: []string{"system.dmiid"}})
+			So(err, ShouldNotBeNil)
+		})
+	})
+
+	Convey("When hashing confidential features", t, func() {
+		cf, err := newConfidentialFeatures(confidentialFeaturesConfig{
+			Salt:     "cluster-salt",
+			Features: []string{"fake.attr.serial", "fake.inst.*"},
+		})
+		So(err, ShouldBeNil)
+
+		features := nfdv1alpha1.NewFeatures()
+		features.Attributes["attr"] = nfdv1alpha1.NewAttributeFeatures(map[string]string{"serial": "1234", "vendor": "acme"})
+		features.Instances["inst"] = nfdv1alpha1.NewInstanceFeatures(*nfdv1alpha1.NewInstanceFeature(map[string]string{"mac": "00:11:22:33:44:55"}))
+
+		cf.apply("fake", features)
+
+		serial := features.Attributes["attr"].Elements["serial"]
+		So(serial, ShouldNotEqual, "1234")
+		So(len(serial), ShouldEqual, confidentialHashLen)
+		So(serial, ShouldEqual, cf.hash("1234"))
+		So(features.Attributes["attr"].Elements["vendor"], ShouldEqual, "acme")
+		So(features.Instances["inst"].Elements[0].Attributes["mac"], ShouldEqual, cf.hash("00:11:22:33:44:55"))
+
+		Convey("hashes should depend on the salt", func() {
+			other, err := newConfidentialFeatures(confidentialFeaturesConfig{Salt: "other", Features: []string{"fake.attr.serial"}})
+			So(err, ShouldBeNil)
+			So(other.hash("1234"), ShouldNotEqual, serial)
+		})
+	})
+}
+
+func TestCheckHostPath(t *testing.T) {
+	Convey("When checking host paths", t, func() {
+		tmpDir := t.TempDir()
+		hostSys := filepath.Join(tmpDir, "host-sys")
+		So(os.MkdirAll(filepath.Join(hostSys, "class/net"), 0755), ShouldBeNil)
+		So(os.WriteFile(filepath.Join(tmpDir, "os-release"), []byte("ID=test\n"), 0644), ShouldBeNil)
+
+		origSysfsDir := hostpath.SysfsDir
+		defer func() { hostpath.SysfsDir = origSysfsDir }()
+
+		Convey("existing directories and files should pass", func() {
+			hostpath.SysfsDir = hostpath.HostDir(hostSys)
+			So(checkHostPath(hostpath.SysfsDir.Path("class/net")), ShouldBeNil)
+			So(checkHostPath(filepath.Join(tmpDir, "os-release")), ShouldBeNil)
+		})
+		Convey("a missing host mount should be reported", func() {
+			hostpath.SysfsDir = hostpath.HostDir(filepath.Join(tmpDir, "missing-sys"))
+			err := checkHostPath(hostpath.SysfsDir.Path("class/net"))
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldContainSubstring, "mount the host directory /sys")
+		})
+		Convey("a missing path should be reported", func() {
+			hostpath.SysfsDir = hostpath.HostDir(hostSys)
+			err := checkHostPath(hostpath.SysfsDir.Path("bus/pci/devices"))
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldContainSubstring, "does not exist")
+		})
+	})
+}
+
+func TestSourceErrors(t *testing.T) {
+	Convey("When recording source errors", t, func() {
+		t1 := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+		t2 := t1.Add(time.Minute)
+
+		errs := make(sourceErrors)
+		errs.add("cpu", errors.New("first failure"), nil, t1)
+		val, err := errs.annotationValue()
+		So(err, ShouldBeNil)
+		So(val, ShouldEqual, `{"cpu":{"error":"first failure","since":"2025-01-02T03:04:05Z"}}`)
+
+		Convey("time of the first failure should be preserved", func() {
+			next := make(sourceErrors)
+			next.add("cpu", errors.New("second failure"), errs, t2)
+			next.add("pci", errors.New("failure"), errs, t2)
+			So(next["cpu"].Error, ShouldEqual, "second failure")
+			So(next["cpu"].Since.Time, ShouldEqual, t1)
+			So(next["pci"].Since.Time, ShouldEqual, t2)
+		})
+
+		Convey("no annotation should be created without errors", func() {
+			val, err := sourceErrors{}.annotationValue()
+			So(err, ShouldBeNil)
+			So(val, ShouldBeEmpty)
+		})
+	})
+}
+
+func TestDeprecatedFeatures(t *testing.T) {
+	Convey("When checking rules for deprecated features", t, func() {
+		deprecations := []source.Deprecation{
+			{Feature: "fake.attribute", Element: "old", Replacement: "fake.attribute.new", RemovedIn: "v0.20"},
+			{Feature: "fake.flag"},
+		}
+		rules := []nfdv1alpha1.Rule{
+			{
+				Name: "rule-1",
+				MatchFeatures: nfdv1alpha1.FeatureMatcher{
+					{Feature: "fake.attribute", MatchExpressions: &nfdv1alpha1.MatchExpressionSet{"old": {Op: nfdv1alpha1.MatchExists}}},
+				},
+			},
+			{
+				Name: "rule-2",
+				MatchAny: []nfdv1alpha1.MatchAnyElem{
+					{MatchFeatures: nfdv1alpha1.FeatureMatcher{{Feature: "fake.flag", MatchName: &nfdv1alpha1.MatchExpression{Op: nfdv1alpha1.MatchExists}}}},
+					{MatchFeatures: nfdv1alpha1.FeatureMatcher{{Feature: "fake.attribute", MatchExpressions: &nfdv1alpha1.MatchExpressionSet{"new": {Op: nfdv1alpha1.MatchExists}}}}},
+				},
+			},
+			{
+				Name:     "rule-3",
+				Disabled: true,
+				MatchFeatures: nfdv1alpha1.FeatureMatcher{
+					{Feature: "fake.attribute", MatchExpressions: &nfdv1alpha1.MatchExpressionSet{"old": {Op: nfdv1alpha1.MatchExists}}},
+				},
+			},
+		}
+
+		used := findDeprecatedFeatures(rules, deprecations)
+		So(used, ShouldResemble, deprecatedFeatures{
+			"fake.attribute": {Elements: []string{"old"}, Rules: []string{"rule-1"}, Replacement: "fake.attribute.new", RemovedIn: "v0.20"},
+			"fake.flag":      {Rules: []string{"rule-2"}},
+		})
+		val, err := used.annotationValue()
+		So(err, ShouldBeNil)
+		So(val, ShouldEqual, `{"fake.attribute":{"elements":["old"],"rules":["rule-1"],"replacement":"fake.attribute.new","removedIn":"v0.20"},"fake.flag":{"rules":["rule-2"]}}`)
+
+		Convey("no annotation should be created if no deprecated features are used", func() {
+			used := findDeprecatedFeatures(rules[1:2], deprecations[:1])
+			So(used, ShouldBeEmpty)
+			val, err := used.annotationValue()
+			So(err, ShouldBeNil)
+			So(val, ShouldBeEmpty)
+		})
+	})
+}
+
+func TestInspect(t *testing.T) {
+	Convey("When inspecting features locally", t, func() {
+		args := &Args{
+			Overrides: ConfigOverrideArgs{
+				FeatureSources: &utils.StringSliceVal{"fake"},
+				LabelSources:   &utils.StringSliceVal{"fake"},
+			},
+		}
+		res, err := Inspect(args)
+		So(err, ShouldBeNil)
+
+		Convey("labels of the enabled sources should be returned", func() {
+			So(res.Labels, ShouldResemble, Labels{
+				nfdv1alpha1.FeatureLabelNs + "/fake-fakefeature1": "true",
+				nfdv1alpha1.FeatureLabelNs + "/fake-fakefeature2": "true",
+				nfdv1alpha1.FeatureLabelNs + "/fake-fakefeature3": "true",
+			})
+		})
+		Convey("raw features should be returned", func() {
+			So(res.Features.Flags, ShouldContainKey, "fake.flag")
+			So(res.Features.Attributes, ShouldContainKey, "fake.attribute")
+			So(res.SourceErrors, ShouldBeNil)
+		})
+		Convey("invalid configuration should be rejected", func() {
+			args.Options = "{invalid"
+			_, err := Inspect(args)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestFeatureSchema(t *testing.T) {
+	Convey("When I request the feature schema", t, func() {
+		rec := httptest.NewRecorder()
+		featureSchemaHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, FeatureSchemaPath, nil))
+
+		Convey("It should be served in JSON format", func() {
+			So(rec.Code, ShouldEqual, http.StatusOK)
+			So(rec.Header().Get("Content-Type"), ShouldEqual, "application/json")
+
+			s := &schema.Schema{}
+			So(json.Unmarshal(rec.Body.Bytes(), s), ShouldBeNil)
+			So(s.Sources, ShouldNotBeEmpty)
+
+			Convey("All sources in the schema should be registered feature sources", func() {
+				for _, src := range s.Sources {
+					So(source.GetFeatureSource(src.Name), ShouldNotBeNil)
+				}
+			})
+		})
+	})
+}
+
+func TestFeaturesHandler(t *testing.T) {
+	Convey("When I request the discovered features", t, func() {
+		w := &nfdWorker{}
+		get := func() *httptest.ResponseRecorder {
+			rec := httptest.NewRecorder()
+			w.featuresHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, FeaturesPath, nil))
+			return rec
+		}
+
+		Convey("Service unavailable should be returned before feature discovery", func() {
+			So(get().Code, ShouldEqual, http.StatusServiceUnavailable)
+		})
+
+		Convey("The latest features should be returned after feature discovery", func() {
+			features := nfdv1alpha1.NewFeatures()
+			features.Flags["fake.flag"] = nfdv1alpha1.NewFlagFeatures("flag_1")
+			features.Attributes["fake.attribute"] = nfdv1alpha1.NewAttributeFeatures(map[string]string{"attr_1": "true"})
+			w.features.Store(features)
+
+			rec := get()
+			So(rec.Code, ShouldEqual, http.StatusOK)
+			So(rec.Header().Get("Content-Type"), ShouldEqual, "application/json")
+
+			got := &nfdv1alpha1.Features{}
+			So(json.Unmarshal(rec.Body.Bytes(), got), ShouldBeNil)
+			So(got, ShouldResemble, features)
+		})
+	})
+}
+
+// testFeatureSource is a feature source returning static features.
+type testFeatureSource struct {
+	name     string
+	features *nfdv1alpha1.Features
+	err      error
+}
+
+func (s *testFeatureSource) Name() string                       { return s.name }
+func (s *testFeatureSource) Discover() error                    { return s.err }
+func (s *testFeatureSource) GetFeatures() *nfdv1alpha1.Features { return s.features }
+
+func TestSourceMetrics(t *testing.T) {
+	Convey("When discovering features", t, func() {
+		features := nfdv1alpha1.NewFeatures()
+		features.Flags["flags"] = nfdv1alpha1.NewFlagFeatures("flag_1", "flag_2")
+		features.Attributes["attrs"] = nfdv1alpha1.NewAttributeFeatures(map[string]string{"attr_1": "1"})
+		features.Instances["instances"] = nfdv1alpha1.NewInstanceFeatures(*nfdv1alpha1.NewInstanceFeature(map[string]string{"a": "1"}))
+
+		okSource := &testFeatureSource{name: "metrics-test-ok", features: features}
+		failSource := &testFeatureSource{name: "metrics-test-fail", features: nfdv1alpha1.NewFeatures(), err: errors.New("failure")}
+		w := &nfdWorker{config: newDefaultConfig(), featureSources: []source.FeatureSource{okSource, failSource}}
+		w.discoverFeatures()
+		w.discoverFeatures()
+
+		Convey("Per-source metrics should be updated", func() {
+			So(testutil.ToFloat64(sourceFeatures.WithLabelValues(okSource.name)), ShouldEqual, 4)
+			So(testutil.ToFloat64(sourceFeatures.WithLabelValues(failSource.name)), ShouldEqual, 0)
+			So(testutil.ToFloat64(sourceDiscoveryErrors.WithLabelValues(okSource.name)), ShouldEqual, 0)
+			So(testutil.ToFloat64(sourceDiscoveryErrors.WithLabelValues(failSource.name)), ShouldEqual, 2)
+			So(testutil.CollectAndCount(sourceDiscoveryDuration, "nfd_worker_"+sourceDiscoveryDurationQuery), ShouldBeGreaterThanOrEqualTo, 2)
+		})
+	})
+}
+
+// concurrencyTestSource tracks the number of sources being discovered
+// concurrently.
+type concurrencyTestSource struct {
+	testFeatureSource
+	mu                  *sync.Mutex
+	running, maxRunning *int
+}
+
+func (s *concurrencyTestSource) Discover() error {
+	s.mu.Lock()
+	*s.running++
+	if *s.running > *s.maxRunning {
+		*s.maxRunning = *s.running
+	}
+	s.mu.Unlock()
+
+	time.Sleep(20 * time.Millisecond)
+
+	s.mu.Lock()
+	*s.running--
+	s.mu.Unlock()
+	return s.err
+}
+
+func TestSourceConcurrency(t *testing.T) {
+	Convey("When discovering features", t, func() {
+		var (
+			mu                  sync.Mutex
+			running, maxRunning int
+		)
+		sources := []source.FeatureSource{}
+		for i := 0; i < 6; i++ {
+			s := &concurrencyTestSource{
+				testFeatureSource: testFeatureSource{name: fmt.Sprintf("concurrency-test-%d", i), features: nfdv1alpha1.NewFeatures()},
+				mu:                &mu,
+				running:           &running,
+				maxRunning:        &maxRunning,
+			}
+			if i%2 == 1 {
+				s.err = errors.New("failure")
+			}
+			sources = append(sources, s)
+		}
+		w := &nfdWorker{config: newDefaultConfig(), featureSources: sources}
+
+		Convey("sources should be discovered sequentially by default", func() {
+			w.discoverFeatures()
+			So(maxRunning, ShouldEqual, 1)
+			So(w.sourceErrors, ShouldHaveLength, 3)
+		})
+		Convey("sources should be discovered in parallel up to sourceConcurrency", func() {
+			w.config.Core.SourceConcurrency = 3
+			w.discoverFeatures()
+			So(maxRunning, ShouldBeBetweenOrEqual, 2, 3)
+			So(w.sourceErrors, ShouldHaveLength, 3)
+			So(w.sourceErrors, ShouldContainKey, "concurrency-test-1")
+		})
+	})
+}
+
+// blockingTestSource blocks in Discover until released.
+type blockingTestSource struct {
+	testFeatureSource
+	release chan struct{}
+	calls   atomic.Int32
+}
+
+func (s *blockingTestSource) Discover() error {
+	s.calls.Add(1)
+	<-s.release
+	return s.err
+}
+
+func TestSourceTimeout(t *testing.T) {
+	Convey("When a feature source hangs", t, func() {
+		s := &blockingTestSource{
+			testFeatureSource: testFeatureSource{name: "timeout-test", features: nfdv1alpha1.NewFeatures()},
+			release:           make(chan struct{}),
+		}
+		s.features.Attributes["attrs"] = nfdv1alpha1.NewAttributeFeatures(map[string]string{"a": "1"})
+		w := &nfdWorker{config: newDefaultConfig(), featureSources: []source.FeatureSource{s}}
+		w.config.Core.SourceTimeout.Duration = 50 * time.Millisecond
+		timeouts := testutil.ToFloat64(sourceDiscoveryTimeouts.WithLabelValues(s.name))
+
+		w.discoverFeatures()
+		Convey("discovery should time out", func() {
+			So(w.sourceErrors, ShouldContainKey, s.name)
+			So(w.sourceErrors[s.name].Error, ShouldContainSubstring, "timed out")
+			So(testutil.ToFloat64(sourceDiscoveryTimeouts.WithLabelValues(s.name))-timeouts, ShouldEqual, 1)
+		})
+		Convey("features of the source should not be advertised before discovery completes", func() {
+			So(w.getFeatures().Attributes, ShouldNotContainKey, s.name+".attrs")
+
+			close(s.release)
+			for start := time.Now(); w.sourceStates[s.name].running.Load() && time.Since(start) < 2*time.Second; {
+				time.Sleep(10 * time.Millisecond)
+			}
+			w.discoverFeatures()
+			So(w.getFeatures().Attributes, ShouldContainKey, s.name+".attrs")
+		})
+		Convey("the source should not be discovered again before it returns", func() {
+			w.discoverFeatures()
+			So(s.calls.Load(), ShouldEqual, 1)
+			So(w.sourceErrors[s.name].Error, ShouldEqual, errSourceBusy.Error())
+
+			close(s.release)
+			for start := time.Now(); w.sourceStates[s.name].running.Load() && time.Since(start) < 2*time.Second; {
+				time.Sleep(10 * time.Millisecond)
+			}
+			w.discoverFeatures()
+			So(s.calls.Load(), ShouldEqual, 2)
+			So(w.sourceErrors, ShouldBeEmpty)
+		})
+	})
+}
+
+func TestSourceCircuitBreaker(t *testing.T) {
+	Convey("When a feature source keeps failing", t, func() {
+		s := &blockingTestSource{
+			testFeatureSource: testFeatureSource{name: "circuit-breaker-test", features: nfdv1alpha1.NewFeatures(), err: errors.New("failure")},
+			release:           make(chan struct{}),
+		}
+		close(s.release)
+		w := &nfdWorker{config: newDefaultConfig(), featureSources: []source.FeatureSource{s}}
+		w.config.Core.SourceCircuitBreaker.FailureThreshold = 2
+		sourceDisabled.DeleteLabelValues(s.name)
+
+		w.discoverFeatures()
+		So(w.sourceErrors[s.name].DisabledUntil, ShouldBeNil)
+		So(testutil.ToFloat64(sourceDisabled.WithLabelValues(s.name)), ShouldEqual, 0)
+		w.discoverFeatures()
+
+		Convey("the source should be disabled after the failure threshold", func() {
+			So(w.sourceErrors[s.name].DisabledUntil, ShouldNotBeNil)
+			So(testutil.ToFloat64(sourceDisabled.WithLabelValues(s.name)), ShouldEqual, 1)
+
+			w.discoverFeatures()
+			So(s.calls.Load(), ShouldEqual, 2)
+			So(w.sourceErrors[s.name].Error, ShouldEqual, "failure")
+			So(w.sourceErrors[s.name].DisabledUntil, ShouldNotBeNil)
+		})
+		Convey("the source should be tried again after the cool-down", func() {
+			w.sourceStates[s.name].disabledUntil = time.Now()
+			s.err = nil
+			w.discoverFeatures()
+			So(s.calls.Load(), ShouldEqual, 3)
+			So(w.sourceErrors, ShouldBeEmpty)
+			So(testutil.ToFloat64(sourceDisabled.WithLabelValues(s.name)), ShouldEqual, 0)
+		})
+	})
+}
+
+func TestFeatureTimestamps(t *testing.T) {
+	Convey("When setting feature set timestamps", t, func() {
+		okSource := &testFeatureSource{name: "timestamp-test-ok", features: nfdv1alpha1.NewFeatures()}
+		failSource := &testFeatureSource{name: "timestamp-test-fail", features: nfdv1alpha1.NewFeatures(), err: errors.New("failure")}
+		w := &nfdWorker{config: newDefaultConfig(), featureSources: []source.FeatureSource{okSource, failSource}}
+		start := time.Now().Truncate(time.Second)
+		w.discoverFeatures()
+
+		features := nfdv1alpha1.NewFeatures()
+		features.Flags[okSource.name+".flags"] = nfdv1alpha1.NewFlagFeatures("a")
+		features.Attributes[okSource.name+".attrs"] = nfdv1alpha1.NewAttributeFeatures(map[string]string{"a": "1"})
+		features.Instances[okSource.name+".instances"] = nfdv1alpha1.NewInstanceFeatures()
+		features.Attributes[failSource.name+".attrs"] = nfdv1alpha1.NewAttributeFeatures(map[string]string{"a": "1"})
+		features.Attributes["unknown.attrs"] = nfdv1alpha1.NewAttributeFeatures(nil)
+		w.setFeatureTimestamps(features)
+
+		Convey("feature sets of successfully discovered sources should have a timestamp", func() {
+			So(features.Flags[okSource.name+".flags"].LastUpdated, ShouldNotBeNil)
+			So(features.Flags[okSource.name+".flags"].LastUpdated.Time, ShouldHappenOnOrAfter, start)
+			So(features.Attributes[okSource.name+".attrs"].LastUpdated, ShouldNotBeNil)
+			So(features.Instances[okSource.name+".instances"].LastUpdated, ShouldNotBeNil)
+		})
+		Convey("feature sets of failed or unknown sources should not have a timestamp", func() {
+			So(features.Attributes[failSource.name+".attrs"].LastUpdated, ShouldBeNil)
+			So(features.Attributes["unknown.attrs"].LastUpdated, ShouldBeNil)
+		})
+	})
+}
+
+func TestWriteFeatureFile(t *testing.T) {
+	Convey("When writing the feature file", t, func() {
+		path := filepath.Join(t.TempDir(), "nfd", "features.json")
+		w := &nfdWorker{config: newDefaultConfig()}
+		w.config.Core.FeatureFile = path
+
+		labels := Labels{"feature.node.kubernetes.io/fake-label": "true"}
+		w.writeFeatureFile(labels, nfdv1alpha1.NewFeatures())
+		w.writeFeatureFile(labels, nfdv1alpha1.NewFeatures())
+
+		Convey("the file should contain the current labels and features", func() {
+			data, err := os.ReadFile(path)
+			So(err, ShouldBeNil)
+			content := featureDump{}
+			So(json.Unmarshal(data, &content), ShouldBeNil)
+			So(content.Labels, ShouldResemble, labels)
+			So(content.Features, ShouldNotBeNil)
+			So(content.Timestamp.IsZero(), ShouldBeFalse)
+		})
+		Convey("no temporary files should be left behind", func() {
+			files, err := os.ReadDir(filepath.Dir(path))
+			So(err, ShouldBeNil)
+			So(files, ShouldHaveLength, 1)
+		})
+	})
+}
+
+func TestFeatureDumpCSV(t *testing.T) {
+	Convey("When encoding a feature dump in CSV format", t, func() {
+		features := nfdv1alpha1.NewFeatures()
+		features.Flags["cpu.cpuid"] = nfdv1alpha1.NewFlagFeatures("AVX")
+		features.Attributes["kernel.version"] = nfdv1alpha1.NewAttributeFeatures(map[string]string{"major": "6"})
+		features.Instances["pci.device"] = nfdv1alpha1.NewInstanceFeatures(
+			*nfdv1alpha1.NewInstanceFeature(map[string]string{"vendor": "8086", "class": "0200"}))
+		dump := featureDump{
+			Timestamp: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+			NodeName:  "node-1",
+			Labels:    Labels{"feature.node.kubernetes.io/foo": "a,b"},
+			Features:  features,
+		}
+
+		Convey("all labels and feature elements should be included", func() {
+			So(string(dump.csv()), ShouldEqual, `timestamp,node,type,feature,instance,name,value
+2025-01-02T03:04:05Z,node-1,label,,,feature.node.kubernetes.io/foo,"a,b"
+2025-01-02T03:04:05Z,node-1,flag,cpu.cpuid,,AVX,
+2025-01-02T03:04:05Z,node-1,attribute,kernel.version,,major,6
+2025-01-02T03:04:05Z,node-1,instance,pci.device,0,class,0200
+2025-01-02T03:04:05Z,node-1,instance,pci.device,0,vendor,8086
+`)
+		})
+	})
+}
+
+func TestIntervalBackoff(t *testing.T) {
+	Convey("When backoff is enabled", t, func() {
+		b := newIntervalBackoff(time.Minute, 5*time.Minute)
+		So(b.current, ShouldEqual, time.Minute)
+
+		Convey("the interval should be lengthened up to the maximum when nothing changes", func() {
+			So(b.next(false), ShouldEqual, 2*time.Minute)
+			So(b.next(false), ShouldEqual, 4*time.Minute)
+			So(b.next(false), ShouldEqual, 5*time.Minute)
+			So(b.next(false), ShouldEqual, 5*time.Minute)
+			So(testutil.ToFloat64(sleepInterval), ShouldEqual, 300)
+		})
+		Convey("the interval should snap back when features change", func() {
+			b.next(false)
+			b.next(false)
+			So(b.next(true), ShouldEqual, time.Minute)
+		})
+		Convey("the interval should snap back on reset", func() {
+			b.next(false)
+			So(b.reset(), ShouldEqual, time.Minute)
+			So(testutil.ToFloat64(sleepInterval), ShouldEqual, 60)
+		})
+	})
+	Convey("When backoff is disabled", t, func() {
+		Convey("the interval should stay at the sleep interval", func() {
+			b := newIntervalBackoff(time.Minute, 0)
+			So(b.next(false), ShouldEqual, time.Minute)
+		})
+		Convey("an infinite sleep interval should not be lengthened", func() {
+			b := newIntervalBackoff(0, 5*time.Minute)
+			So(b.next(false), ShouldEqual, 0)
+		})
+	})
+}
+
+// fakeUeventSocket returns the queued messages and times out when empty.
+type fakeUeventSocket struct {
+	msgs chan []byte
+}
+
+func (s *fakeUeventSocket) receive(buf []byte) (int, error) {
+	select {
+	case m := <-s.msgs:
+		return copy(buf, m), nil
+	case <-time.After(10 * time.Millisecond):
+		return 0, nil
+	}
+}
+
+func (s *fakeUeventSocket) close() error { return nil }
+
+func TestUevents(t *testing.T) {
+	Convey("When parsing uevents", t, func() {
+		Convey("kernel uevents should be parsed", func() {
+			ev, ok := parseUevent([]byte("add@/devices/pci0000:00/0000:00:01.0\x00ACTION=add\x00DEVPATH=/devices/pci0000:00/0000:00:01.0\x00SUBSYSTEM=pci\x00SEQNUM=1234\x00"))
+			So(ok, ShouldBeTrue)
+			So(ev, ShouldResemble, uevent{Action: "add", Subsystem: "pci", Devpath: "/devices/pci0000:00/0000:00:01.0"})
+		})
+		Convey("udev messages and garbage should be ignored", func() {
+			_, ok := parseUevent([]byte("libudev\x00\xfe\xed\xca\xfe"))
+			So(ok, ShouldBeFalse)
+			_, ok = parseUevent([]byte("foo"))
+			So(ok, ShouldBeFalse)
+			_, ok = parseUevent([]byte("add@/devices/foo\x00ACTION=add\x00"))
+			So(ok, ShouldBeFalse)
+		})
+	})
+
+	Convey("When watching uevents", t, func() {
+		sock := &fakeUeventSocket{msgs: make(chan []byte, 4)}
+		w := startUeventWatcher(sock, []string{"pci", "cpu"})
+		defer w.Stop()
+
+		Convey("only hotplug events of the watched subsystems should be reported", func() {
+			sock.msgs <- []byte("change@/devices/pci0000:00/0000:00:01.0\x00ACTION=change\x00SUBSYSTEM=pci\x00")
+			sock.msgs <- []byte("add@/devices/usb1\x00ACTION=add\x00SUBSYSTEM=usb\x00")
+			sock.msgs <- []byte("offline@/devices/system/cpu/cpu3\x00ACTION=offline\x00DEVPATH=/devices/system/cpu/cpu3\x00SUBSYSTEM=cpu\x00")
+
+			select {
+			case ev := <-w.Events():
+				So(ev, ShouldResemble, uevent{Action: "offline", Subsystem: "cpu", Devpath: "/devices/system/cpu/cpu3"})
+			case <-time.After(5 * time.Second):
+				t.Fatal("timeout waiting for uevent")
+			}
+			So(w.Events(), ShouldHaveLength, 0)
+		})
+	})
+}
+
+func TestNewHousekeeping(t *testing.T) {
+	Convey("When parsing housekeeping config", t, func() {
+		node := 1
+
+		Convey("an empty config should disable housekeeping", func() {
+			h, err := newHousekeeping(housekeepingConfig{})
+			So(err, ShouldBeNil)
+			So(h.enabled(), ShouldBeFalse)
+		})
+		Convey("a cpu list should be parsed", func() {
+			h, err := newHousekeeping(housekeepingConfig{CPUs: "0-1,4", IOPriorityClass: "idle"})
+			So(err, ShouldBeNil)
+			So(h.enabled(), ShouldBeTrue)
+			So(h.cpus.List(), ShouldResemble, []int{0, 1, 4})
+		})
+		Convey("the cpus of a NUMA node should be read from sysfs", func() {
+			tmpDir := t.TempDir()
+			So(os.MkdirAll(filepath.Join(tmpDir, "bus/node/devices/node1"), 0755), ShouldBeNil)
+			So(os.WriteFile(filepath.Join(tmpDir, "bus/node/devices/node1/cpulist"), []byte("8-11\n"), 0644), ShouldBeNil)
+			origSysfsDir := hostpath.SysfsDir
+			hostpath.SysfsDir = hostpath.HostDir(tmpDir)
+			defer func() { hostpath.SysfsDir = origSysfsDir }()
+
+			h, err := newHousekeeping(housekeepingConfig{NUMANode: &node})
+			So(err, ShouldBeNil)
+			So(h.cpus.List(), ShouldResemble, []int{8, 9, 10, 11})
+
+			other := 2
+			_, err = newHousekeeping(housekeepingConfig{NUMANode: &other})
+			So(err, ShouldNotBeNil)
+		})
+		Convey("invalid config should be rejected", func() {
+			_, err := newHousekeeping(housekeepingConfig{CPUs: "0", NUMANode: &node})
+			So(err, ShouldNotBeNil)
+			_, err = newHousekeeping(housekeepingConfig{CPUs: "a-b"})
+			So(err, ShouldNotBeNil)
+			_, err = newHousekeeping(housekeepingConfig{IOPriorityClass: "realtime"})
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
@@ -17,22 +17,29 @@ limitations under the License.
 package nfdworker
 
 import (
+	"bytes"
+	"encoding/csv"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net"
 	"os"
 	"path/filepath"
 	"regexp"
 	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"go.opentelemetry.io/otel/codes"
 	"golang.org/x/exp/maps"
 	"golang.org/x/net/context"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/health"
 	"google.golang.org/grpc/health/grpc_health_v1"
-	"k8s.io/apimachinery/pkg/api/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/validation"
 	k8sclient "k8s.io/client-go/kubernetes"
@@ -45,6 +52,8 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	nfdclient "sigs.k8s.io/node-feature-discovery/api/generated/clientset/versioned"
 	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
+	nfdfeatures "sigs.k8s.io/node-feature-discovery/pkg/features"
+	nfdmaster "sigs.k8s.io/node-feature-discovery/pkg/nfd-master"
 	"sigs.k8s.io/node-feature-discovery/pkg/utils"
 	"sigs.k8s.io/node-feature-discovery/pkg/version"
 	"sigs.k8s.io/node-feature-discovery/source"
@@ -53,11 +62,13 @@ import (
 	_ "sigs.k8s.io/node-feature-discovery/source/cpu"
 	_ "sigs.k8s.io/node-feature-discovery/source/custom"
 	_ "sigs.k8s.io/node-feature-discovery/source/fake"
+	_ "sigs.k8s.io/node-feature-discovery/source/gpu"
 	_ "sigs.k8s.io/node-feature-discovery/source/kernel"
 	_ "sigs.k8s.io/node-feature-discovery/source/local"
 	_ "sigs.k8s.io/node-feature-discovery/source/memory"
 	_ "sigs.k8s.io/node-feature-discovery/source/network"
 	_ "sigs.k8s.io/node-feature-discovery/source/pci"
+	_ "sigs.k8s.io/node-feature-discovery/source/provider"
 	_ "sigs.k8s.io/node-feature-discovery/source/storage"
 	_ "sigs.k8s.io/node-feature-discovery/source/system"
 	_ "sigs.k8s.io/node-feature-discovery/source/usb"
@@ -84,8 +95,66 @@ type coreConfig struct {
 	Sources        *[]string
 	LabelSources   []string
 	SleepInterval  utils.DurationVal
+	// MaxSleepInterval is the upper limit of the sleep interval when it is
+	// lengthened because consecutive feature discovery rounds produce no
+	// changes. Backoff is disabled if not greater than SleepInterval.
+	MaxSleepInterval utils.DurationVal
+	// UeventSubsystems is the list of kernel subsystems (e.g. "pci", "usb"
+	// or "cpu") whose hotplug uevents trigger immediate feature discovery.
+	// Empty disables the uevent watcher.
+	UeventSubsystems []string
+	// SourceConcurrency is the maximum number of feature sources discovered
+	// in parallel. Values below 2 mean sequential discovery.
+	SourceConcurrency int
+	// SourceTimeout is the maximum duration of the feature discovery of one
+	// feature source. Zero means no timeout.
+	SourceTimeout utils.DurationVal
+	// SourceCircuitBreaker contains the configuration of the circuit
+	// breaker disabling repeatedly failing feature sources.
+	SourceCircuitBreaker sourceCircuitBreakerConfig
+	// MinKernelVersion maps a feature source name (e.g. "pci") to the
+	// minimum kernel version required for it to be enabled.
+	MinKernelVersion     map[string]string
+	FeatureDump          featureDumpConfig
+	ConfidentialFeatures confidentialFeaturesConfig
+	// FeatureFile is the path of a file where the current features and
+	// labels are written after each feature discovery round. Empty disables
+	// the feature file.
+	FeatureFile string
+	// Housekeeping contains the configuration for confining nfd-worker to
+	// housekeeping CPUs and lowering its I/O priority.
+	Housekeeping housekeepingConfig
 }
 
+// sourceCircuitBreakerConfig contains the configuration of the circuit
+// breaker of feature sources.
+type sourceCircuitBreakerConfig struct {
+	// FailureThreshold is the number of consecutive failures after which a
+	// source is disabled. Zero disables the circuit breaker.
+	FailureThreshold int
+	// Cooldown is the time a source is kept disabled.
+	Cooldown utils.DurationVal
+}
+
+// featureDumpConfig contains the configuration of feature dump files.
+type featureDumpConfig struct {
+	Dir      string
+	MaxFiles int
+	MaxSize  int64
+	// Format is the format of the dump files, one of "yaml", "json" or
+	// "csv".
+	Format string
+}
+
+// tracerName is the name of the OpenTelemetry tracer of nfd-worker.
+const tracerName = "sigs.k8s.io/node-feature-discovery/pkg/nfd-worker"
+
+const (
+	featureDumpFormatYAML = "yaml"
+	featureDumpFormatJSON = "json"
+	featureDumpFormatCSV  = "csv"
+)
+
 type sourcesConfig map[string]source.Config
 
 // Labels are a Kubernetes representation of discovered features.
@@ -101,6 +170,20 @@ type Args struct {
 	MetricsPort    int
 	GrpcHealthPort int
 	NoOwnerRefs    bool
+	// MetricsCertFile and MetricsKeyFile enable TLS on the metrics server.
+	MetricsCertFile string
+	MetricsKeyFile  string
+	// FeatureApiPort is the port on localhost for serving the discovered
+	// features. Zero disables the feature API.
+	FeatureApiPort int
+	// Standalone makes nfd-worker update the node object directly instead
+	// of creating a NodeFeature object for nfd-master to process.
+	Standalone bool
+	// MasterConfigFile is the nfd-master configuration file used for
+	// processing the labels (et al.) in standalone mode.
+	MasterConfigFile string
+	// Tracing contains the OpenTelemetry tracing options.
+	Tracing utils.TracingArgs
 
 	Overrides ConfigOverrideArgs
 }
@@ -124,7 +207,15 @@ type nfdWorker struct {
 	stop                chan struct{} // channel for signaling stop
 	featureSources      []source.FeatureSource
 	labelSources        []source.LabelSource
+	confidential        *confidentialFeatures
+	sourceErrors        sourceErrors
+	deprecatedFeatures  deprecatedFeatures
+	sourceStates        map[string]*sourceState
 	ownerReference      []metav1.OwnerReference
+	nodeUpdater         nfdmaster.NodeUpdater
+	// features contains a snapshot of the features discovered in the latest
+	// feature discovery round
+	features atomic.Pointer[nfdv1alpha1.Features]
 }
 
 // This ticker can represent infinite and normal intervals.
@@ -197,11 +288,20 @@ func NewNfdWorker(opts ...NfdWorkerOption) (NfdWorker, error) {
 func newDefaultConfig() *NFDConfig {
 	return &NFDConfig{
 		Core: coreConfig{
-			LabelWhiteList: utils.RegexpVal{Regexp: *regexp.MustCompile("")},
-			SleepInterval:  utils.DurationVal{Duration: 60 * time.Second},
-			FeatureSources: []string{"all"},
-			LabelSources:   []string{"all"},
-			Klog:           make(map[string]string),
+			LabelWhiteList:    utils.RegexpVal{Regexp: *regexp.MustCompile("")},
+			SleepInterval:     utils.DurationVal{Duration: 60 * time.Second},
+			SourceConcurrency: 1,
+			FeatureSources:    []string{"all"},
+			LabelSources:      []string{"all"},
+			Klog:              make(map[string]string),
+			FeatureDump: featureDumpConfig{
+				MaxFiles: 5,
+				MaxSize:  1024 * 1024,
+				Format:   featureDumpFormatYAML,
+			},
+			SourceCircuitBreaker: sourceCircuitBreakerConfig{
+				Cooldown: utils.DurationVal{Duration: 10 * time.Minute},
+			},
 		},
 	}
 }
@@ -242,14 +342,15 @@ func (w *nfdWorker) startGrpcHealthServer(errChan chan<- error) error {
 
 // Run feature discovery.
 func (w *nfdWorker) runFeatureDiscovery() error {
+	ctx, span := utils.StartSpan(context.Background(), tracerName, "FeatureDiscovery")
+	defer span.End()
+
 	discoveryStart := time.Now()
-	for _, s := range w.featureSources {
-		currentSourceStart := time.Now()
-		if err := s.Discover(); err != nil {
-			klog.ErrorS(err, "feature discovery failed", "source", s.Name())
-		}
-		klog.V(3).InfoS("feature discovery completed", "featureSource", s.Name(), "duration", time.Since(currentSourceStart))
-	}
+	_, discoverySpan := utils.StartSpan(ctx, tracerName, "DiscoverFeatures")
+	w.discoverFeatures()
+	features := w.getFeatures()
+	w.features.Store(features)
+	discoverySpan.End()
 
 	discoveryDuration := time.Since(discoveryStart)
 	klog.V(2).InfoS("feature discovery of all sources completed", "duration", discoveryDuration)
@@ -258,16 +359,109 @@ func (w *nfdWorker) runFeatureDiscovery() error {
 		klog.InfoS("feature discovery sources took over half of sleep interval ", "duration", discoveryDuration, "sleepInterval", w.config.Core.SleepInterval.Duration)
 	}
 	// Get the set of feature labels.
-	labels := createFeatureLabels(w.labelSources, w.config.Core.LabelWhiteList.Regexp)
+	labelSources := w.readyLabelSources()
+	labels := createFeatureLabels(labelSources, w.config.Core.LabelWhiteList.Regexp)
+	requests := createNodeRequests(labelSources)
+	w.updateDeprecatedFeatures()
+
+	if w.config.Core.FeatureDump.Dir != "" {
+		w.dumpFeatures(labels, features)
+	}
+	if w.config.Core.FeatureFile != "" {
+		w.writeFeatureFile(labels, features)
+	}
 
 	// Update the node with the feature labels.
 	if !w.config.Core.NoPublish {
-		return w.advertiseFeatures(labels)
+		if err := w.advertiseFeatures(ctx, labels, features, requests); err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return err
+		}
 	}
 
 	return nil
 }
 
+// discoverFeatures runs feature discovery of all enabled feature sources.
+// Up to core.sourceConcurrency sources are discovered in parallel. Failures
+// of individual sources are recorded in w.sourceErrors.
+func (w *nfdWorker) discoverFeatures() {
+	errs := make(sourceErrors)
+	states := w.getSourceStates()
+	concurrency := w.config.Core.SourceConcurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	var (
+		mu  sync.Mutex
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, concurrency)
+	)
+	for _, s := range w.featureSources {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(s source.FeatureSource, st *sourceState) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			start, err := w.discoverSource(s, st)
+			if err != nil {
+				mu.Lock()
+				errs.add(s.Name(), err, w.sourceErrors, start)
+				if st.disabled(start) {
+					errs.setDisabled(s.Name(), st.disabledUntil)
+				}
+				mu.Unlock()
+			}
+		}(s, states[s.Name()])
+	}
+	wg.Wait()
+
+	w.sourceErrors = errs
+}
+
+// discoverSource runs feature discovery of one feature source and
+// post-processes the discovered features. The start time of the discovery
+// and the error returned by the source are returned. Sources disabled by the
+// circuit breaker are skipped, returning the error that disabled them.
+func (w *nfdWorker) discoverSource(s source.FeatureSource, st *sourceState) (time.Time, error) {
+	start := time.Now()
+	if st.disabled(start) {
+		klog.V(2).InfoS("feature source disabled because of repeated failures", "source", s.Name(), "disabledUntil", st.disabledUntil)
+		return start, st.lastErr
+	}
+
+	completed, err := runDiscover(s, st, w.config.Core.SourceTimeout.Duration)
+	duration := time.Since(start)
+	sourceDiscoveryDuration.WithLabelValues(s.Name()).Observe(duration.Seconds())
+	st.recordResult(s.Name(), err, w.config.Core.SourceCircuitBreaker, start)
+	if err != nil {
+		klog.ErrorS(err, "feature discovery failed", "source", s.Name())
+		sourceDiscoveryErrors.WithLabelValues(s.Name()).Inc()
+		if st.disabled(start) {
+			klog.InfoS("disabling failing feature source", "source", s.Name(), "consecutiveFailures", st.failures, "disabledUntil", st.disabledUntil)
+		}
+	}
+	if !completed {
+		// The features are still being modified by the discovery running
+		// in the background
+		return start, err
+	}
+	if err == nil {
+		st.lastUpdated = time.Now()
+	}
+	if w.confidential != nil {
+		w.confidential.apply(s.Name(), s.GetFeatures())
+	}
+	st.features = s.GetFeatures().DeepCopy()
+	sourceFeatures.WithLabelValues(s.Name()).Set(float64(countFeatures(s.GetFeatures())))
+	klog.V(3).InfoS("feature discovery completed", "featureSource", s.Name(), "duration", duration)
+	return start, err
+}
+
 // Set owner ref
 func (w *nfdWorker) setOwnerReference() error {
 	ownerReference := []metav1.OwnerReference{}
@@ -319,19 +513,48 @@ func (w *nfdWorker) Run() error {
 		return err
 	}
 
+	shutdownTracing, err := utils.SetupTracing(context.Background(), "nfd-worker", w.args.Tracing)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			klog.ErrorS(err, "failed to shut down tracing")
+		}
+	}()
+
 	// Create ticker for feature discovery and run feature discovery once before the loop.
+	backoff := newIntervalBackoff(w.config.Core.SleepInterval.Duration, w.config.Core.MaxSleepInterval.Duration)
 	labelTrigger := infiniteTicker{Ticker: time.NewTicker(1)}
-	labelTrigger.Reset(w.config.Core.SleepInterval.Duration)
+	labelTrigger.Reset(backoff.current)
 	defer labelTrigger.Stop()
 
 	// Register to metrics server
+	var httpServer *utils.HTTPServer
 	if w.args.MetricsPort > 0 {
-		m := utils.CreateMetricsServer(w.args.MetricsPort,
-			buildInfo,
-			featureDiscoveryDuration)
-		go m.Run()
+		httpServer = utils.NewHTTPServer(w.args.MetricsPort,
+			utils.WithMetrics(
+				buildInfo,
+				featureDiscoveryDuration,
+				sourceDiscoveryDuration,
+				sourceDiscoveryErrors,
+				sourceFeatures,
+				sourceDiscoveryTimeouts,
+				sourceDisabled,
+				sleepInterval,
+				deprecatedFeaturesUsed),
+			utils.WithTLS(w.args.MetricsCertFile, w.args.MetricsKeyFile))
+		httpServer.Handle(FeatureSchemaPath, featureSchemaHandler())
+		go httpServer.Run()
 		registerVersion(version.Get())
-		defer m.Stop()
+		defer httpServer.Stop()
+	}
+
+	if w.args.FeatureApiPort > 0 {
+		featureAPIServer := w.startFeatureAPIServer()
+		defer featureAPIServer.Stop()
 	}
 
 	err = w.runFeatureDiscovery()
@@ -344,6 +567,30 @@ func (w *nfdWorker) Run() error {
 		return nil
 	}
 
+	// Get notified about changes in the features of enabled event-capable
+	// sources
+	sourceEvent := make(chan source.FeatureSource)
+	for _, s := range w.featureSources {
+		if es, ok := s.(source.EventSource); ok {
+			if err := es.SetNotifyChannel(sourceEvent); err != nil {
+				klog.ErrorS(err, "failed to set notify channel for feature source, relying on periodic updates", "source", s.Name())
+			}
+		}
+	}
+
+	// Get notified about device hotplug events
+	var ueventCh <-chan uevent
+	if len(w.config.Core.UeventSubsystems) > 0 {
+		uw, err := newUeventWatcher(w.config.Core.UeventSubsystems)
+		if err != nil {
+			klog.ErrorS(err, "failed to start uevent watcher, relying on periodic updates")
+		} else {
+			defer uw.Stop()
+			ueventCh = uw.Events()
+		}
+	}
+	var ueventTrigger <-chan time.Time
+
 	grpcErr := make(chan error)
 
 	// Start gRPC server for liveness probe (at this point we're "live")
@@ -352,6 +599,9 @@ func (w *nfdWorker) Run() error {
 			return fmt.Errorf("failed to start gRPC health server: %w", err)
 		}
 	}
+	if httpServer != nil {
+		httpServer.SetReady(true)
+	}
 
 	for {
 		select {
@@ -359,10 +609,41 @@ func (w *nfdWorker) Run() error {
 			return fmt.Errorf("error in serving gRPC: %w", err)
 
 		case <-labelTrigger.C:
+			prevFeatures := w.features.Load()
+			err = w.runFeatureDiscovery()
+			if err != nil {
+				return err
+			}
+			prevInterval := backoff.current
+			// Refreshed timestamps alone do not count as a change
+			changed := !apiequality.Semantic.DeepEqual(prevFeatures.WithoutTimestamps(), w.features.Load().WithoutTimestamps())
+			interval := backoff.next(changed)
+			if interval != prevInterval {
+				klog.V(2).InfoS("sleep interval changed", "sleepInterval", interval)
+				labelTrigger.Reset(interval)
+			}
+
+		case s := <-sourceEvent:
+			klog.InfoS("features of source changed, running feature discovery", "source", s.Name())
+			err = w.runFeatureDiscovery()
+			if err != nil {
+				return err
+			}
+			labelTrigger.Reset(backoff.reset())
+
+		case ev := <-ueventCh:
+			klog.V(2).InfoS("uevent received", "action", ev.Action, "subsystem", ev.Subsystem, "devpath", ev.Devpath)
+			// Debounce, i.e. wait for the burst of events to settle
+			ueventTrigger = time.After(ueventDebounce)
+
+		case <-ueventTrigger:
+			klog.InfoS("device hotplug detected, running feature discovery")
+			ueventTrigger = nil
 			err = w.runFeatureDiscovery()
 			if err != nil {
 				return err
 			}
+			labelTrigger.Reset(backoff.reset())
 
 		case <-w.stop:
 			klog.InfoS("shutting down nfd-worker")
@@ -394,11 +675,26 @@ func (w *nfdWorker) configureCore(c coreConfig) error {
 		return err
 	}
 
+	switch c.FeatureDump.Format {
+	case featureDumpFormatYAML, featureDumpFormatJSON, featureDumpFormatCSV:
+	default:
+		return fmt.Errorf("invalid core.featureDump.format %q, must be one of %q, %q or %q",
+			c.FeatureDump.Format, featureDumpFormatYAML, featureDumpFormatJSON, featureDumpFormatCSV)
+	}
+	if err := validateKernelRequirements(c.MinKernelVersion); err != nil {
+		return err
+	}
+	// Features written into dump files are not dumped into the log
+	source.SetFeatureLogging(c.FeatureDump.Dir == "")
+
 	// Determine enabled feature sources
 	featureSources := make(map[string]source.FeatureSource)
 	for _, name := range c.FeatureSources {
 		if name == "all" {
 			for n, s := range source.GetAllFeatureSources() {
+				if !isPlatformSource(n) {
+					continue
+				}
 				if ts, ok := s.(source.SupplementalSource); !ok || !ts.DisableByDefault() {
 					featureSources[n] = s
 				}
@@ -431,6 +727,9 @@ func (w *nfdWorker) configureCore(c coreConfig) error {
 	for _, name := range c.LabelSources {
 		if name == "all" {
 			for n, s := range source.GetAllLabelSources() {
+				if !isPlatformSource(n) {
+					continue
+				}
 				if ts, ok := s.(source.SupplementalSource); !ok || !ts.DisableByDefault() {
 					labelSources[n] = s
 				}
@@ -487,6 +786,27 @@ func (w *nfdWorker) configureCore(c coreConfig) error {
 }
 
 // Parse configuration options
+// listConfigFiles returns the configuration files to read. If path is a
+// directory, all *.conf files in it are returned in lexical order.
+func listConfigFiles(path string) ([]string, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !fi.IsDir() {
+		return []string{path}, nil
+	}
+	// NOTE: filepath.Glob returns the matches in lexical order
+	files, err := filepath.Glob(filepath.Join(path, "*.conf"))
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		klog.InfoS("no config files found in config directory, using defaults", "path", path)
+	}
+	return files, nil
+}
+
 func (w *nfdWorker) configure(filepath string, overrides string) error {
 	// Create a new default config
 	c := newDefaultConfig()
@@ -496,27 +816,33 @@ func (w *nfdWorker) configure(filepath string, overrides string) error {
 		c.Sources[s.Name()] = s.NewConfig()
 	}
 
-	// Try to read and parse config file
+	// Try to read and parse config file(s)
 	if filepath != "" {
-		data, err := os.ReadFile(filepath)
+		files, err := listConfigFiles(filepath)
 		if err != nil {
 			if os.IsNotExist(err) {
 				klog.InfoS("config file not found, using defaults", "path", filepath)
 			} else {
 				return fmt.Errorf("error reading config file: %s", err)
 			}
-		} else {
+		}
+		// Later files override the settings of earlier ones
+		for _, file := range files {
+			data, err := os.ReadFile(file)
+			if err != nil {
+				return fmt.Errorf("error reading config file: %s", err)
+			}
 			err = yaml.Unmarshal(data, c)
 			if err != nil {
-				return fmt.Errorf("failed to parse config file: %s", err)
+				return fmt.Errorf("failed to parse config file %q: %s", file, err)
 			}
 
-			if c.Core.Sources != nil {
-				klog.InfoS("usage of deprecated 'core.sources' config file option, please use 'core.labelSources' instead")
-				c.Core.LabelSources = *c.Core.Sources
-			}
+			klog.InfoS("configuration file parsed", "path", file)
+		}
 
-			klog.InfoS("configuration file parsed", "path", filepath)
+		if c.Core.Sources != nil {
+			klog.InfoS("usage of deprecated 'core.sources' config file option, please use 'core.labelSources' instead")
+			c.Core.LabelSources = *c.Core.Sources
 		}
 	}
 
@@ -546,11 +872,37 @@ func (w *nfdWorker) configure(filepath string, overrides string) error {
 		return err
 	}
 
+	confidential, err := newConfidentialFeatures(c.Core.ConfidentialFeatures)
+	if err != nil {
+		return err
+	}
+	w.confidential = confidential
+
+	hk, err := newHousekeeping(c.Core.Housekeeping)
+	if err != nil {
+		return err
+	}
+	if err := hk.apply(); err != nil {
+		return err
+	}
+
 	// (Re-)configure sources
 	for _, s := range confSources {
 		s.SetConfig(c.Sources[s.Name()])
 	}
 
+	// Apply kernel version requirements. This needs to happen after the
+	// sources have been configured as we rely on the kernel source for
+	// detecting the kernel version.
+	if len(c.Core.MinKernelVersion) > 0 {
+		kernelVersion, err := getKernelVersion()
+		if err != nil {
+			klog.ErrorS(err, "failed to detect kernel version, ignoring core.minKernelVersion")
+		} else {
+			w.featureSources = filterByKernelVersion(w.featureSources, c.Core.MinKernelVersion, kernelVersion)
+		}
+	}
+
 	klog.InfoS("configuration successfully updated", "configuration", w.config)
 	return nil
 }
@@ -579,6 +931,33 @@ func createFeatureLabels(sources []source.LabelSource, labelWhiteList regexp.Reg
 	return labels
 }
 
+// createNodeRequests returns the node annotations, extended resources and
+// taints requested by the enabled sources.
+func createNodeRequests(sources []source.LabelSource) *source.NodeRequests {
+	requests := &source.NodeRequests{}
+	for _, s := range sources {
+		rs, ok := s.(source.NodeRequestSource)
+		if !ok {
+			continue
+		}
+		r := rs.GetNodeRequests()
+		if len(r.Annotations) > 0 {
+			if requests.Annotations == nil {
+				requests.Annotations = make(map[string]string, len(r.Annotations))
+			}
+			maps.Copy(requests.Annotations, r.Annotations)
+		}
+		if len(r.ExtendedResources) > 0 {
+			if requests.ExtendedResources == nil {
+				requests.ExtendedResources = make(map[string]string, len(r.ExtendedResources))
+			}
+			maps.Copy(requests.ExtendedResources, r.ExtendedResources)
+		}
+		requests.Taints = append(requests.Taints, r.Taints...)
+	}
+	return requests
+}
+
 // getFeatureLabels returns node labels for features discovered by the
 // supplied source.
 func getFeatureLabels(source source.LabelSource, labelWhiteList regexp.Regexp) (labels Labels, err error) {
@@ -591,8 +970,8 @@ func getFeatureLabels(source source.LabelSource, labelWhiteList regexp.Regexp) (
 	for k, v := range features {
 		name := k
 		switch sourceName := source.Name(); sourceName {
-		case "local", "custom":
-			// No mangling of labels from the custom rules or feature files
+		case "local", "custom", "provider":
+			// No mangling of labels from the custom rules, feature files or feature providers
 		default:
 			// Prefix for labels from other sources
 			if !strings.Contains(name, "/") {
@@ -633,18 +1012,159 @@ func getFeatureLabels(source source.LabelSource, labelWhiteList regexp.Regexp) (
 	return labels, nil
 }
 
+// featureDump is the content of one feature dump file.
+type featureDump struct {
+	Timestamp       time.Time             `json:"timestamp"`
+	NodeName        string                `json:"nodeName"`
+	Labels          Labels                `json:"labels"`
+	Features        *nfdv1alpha1.Features `json:"features,omitempty"`
+	FeaturesOmitted bool                  `json:"featuresOmitted,omitempty"`
+}
+
+// csv encodes the feature dump in CSV format, with one row per label and
+// feature element. Rows are sorted for stable output.
+func (d featureDump) csv() []byte {
+	ts := d.Timestamp.Format(time.RFC3339)
+	var rows [][]string
+	addRow := func(typ, feature, instance, name, value string) {
+		rows = append(rows, []string{ts, d.NodeName, typ, feature, instance, name, value})
+	}
+
+	for _, k := range sortedKeys(d.Labels) {
+		addRow("label", "", "", k, d.Labels[k])
+	}
+	if d.Features != nil {
+		for _, f := range sortedKeys(d.Features.Flags) {
+			for _, e := range sortedKeys(d.Features.Flags[f].Elements) {
+				addRow("flag", f, "", e, "")
+			}
+		}
+		for _, f := range sortedKeys(d.Features.Attributes) {
+			elems := d.Features.Attributes[f].Elements
+			for _, e := range sortedKeys(elems) {
+				addRow("attribute", f, "", e, elems[e])
+			}
+		}
+		for _, f := range sortedKeys(d.Features.Instances) {
+			for i, inst := range d.Features.Instances[f].Elements {
+				for _, a := range sortedKeys(inst.Attributes) {
+					addRow("instance", f, strconv.Itoa(i), a, inst.Attributes[a])
+				}
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	cw := csv.NewWriter(&buf)
+	_ = cw.Write([]string{"timestamp", "node", "type", "feature", "instance", "name", "value"})
+	_ = cw.WriteAll(rows)
+	return buf.Bytes()
+}
+
+// sortedKeys returns the keys of a map in sorted order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := maps.Keys(m)
+	sort.Strings(keys)
+	return keys
+}
+
+// dumpFeatures writes the discovered features and labels into a dump file.
+// Raw features are omitted if they would not fit in the size limit.
+func (w *nfdWorker) dumpFeatures(labels Labels, features *nfdv1alpha1.Features) {
+	c := w.config.Core.FeatureDump
+	dw := utils.DumpWriter{Dir: c.Dir, Prefix: "features-", MaxFiles: c.MaxFiles, MaxSize: c.MaxSize, Format: c.Format}
+	write := dw.Write
+	if c.Format == featureDumpFormatCSV {
+		write = func(obj interface{}) (string, error) {
+			return dw.WriteData(obj.(featureDump).csv(), featureDumpFormatCSV)
+		}
+	}
+
+	dump := featureDump{
+		Timestamp: time.Now().UTC(),
+		NodeName:  utils.NodeName(),
+		Labels:    labels,
+		Features:  features,
+	}
+	name, err := write(dump)
+	if errors.Is(err, utils.ErrDumpTooLarge) {
+		klog.InfoS("feature dump too large, omitting raw features", "maxSize", c.MaxSize)
+		dump.Features = nil
+		dump.FeaturesOmitted = true
+		name, err = write(dump)
+	}
+	if err != nil {
+		klog.ErrorS(err, "failed to write feature dump", "dir", c.Dir)
+		return
+	}
+	klog.V(1).InfoS("feature dump written", "path", name)
+}
+
+// writeFeatureFile writes the current features and labels into the feature
+// file for consumption by host-level agents. The file is replaced atomically.
+func (w *nfdWorker) writeFeatureFile(labels Labels, features *nfdv1alpha1.Features) {
+	path := w.config.Core.FeatureFile
+	content := featureDump{
+		Timestamp: time.Now().UTC(),
+		NodeName:  utils.NodeName(),
+		Labels:    labels,
+		Features:  features,
+	}
+	data, err := json.MarshalIndent(content, "", "  ")
+	if err != nil {
+		klog.ErrorS(err, "failed to marshal features")
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		klog.ErrorS(err, "failed to create feature file directory", "path", path)
+		return
+	}
+	if err := utils.WriteFileAtomic(path, append(data, '\n'), 0644); err != nil {
+		klog.ErrorS(err, "failed to write feature file", "path", path)
+		return
+	}
+	klog.V(2).InfoS("feature file written", "path", path)
+}
+
 // advertiseFeatures advertises the features of a Kubernetes node
-func (w *nfdWorker) advertiseFeatures(labels Labels) error {
+func (w *nfdWorker) advertiseFeatures(ctx context.Context, labels Labels, features *nfdv1alpha1.Features, requests *source.NodeRequests) error {
+	ctx, span := utils.StartSpan(ctx, tracerName, "AdvertiseFeatures")
+	defer span.End()
+
+	spec := newNodeFeatureSpec(labels, features, requests)
+	if nfdfeatures.NFDFeatureGate.Enabled(nfdfeatures.FeatureTimestamps) {
+		w.setFeatureTimestamps(&spec.Features)
+	}
+
+	if w.args.Standalone {
+		if err := w.updateNode(ctx, spec); err != nil {
+			return fmt.Errorf("failed to advertise features (standalone mode): %w", err)
+		}
+		return nil
+	}
+
 	// Create/update NodeFeature CR object
-	if err := w.updateNodeFeatureObject(labels); err != nil {
+	if err := w.updateNodeFeatureObject(ctx, spec); err != nil {
 		return fmt.Errorf("failed to advertise features (via CRD API): %w", err)
 	}
 
 	return nil
 }
 
+// newNodeFeatureSpec returns the NodeFeatureSpec advertising the features,
+// labels and other node requests of the node.
+func newNodeFeatureSpec(labels Labels, features *nfdv1alpha1.Features, requests *source.NodeRequests) *nfdv1alpha1.NodeFeatureSpec {
+	return &nfdv1alpha1.NodeFeatureSpec{
+		Features:          *features.DeepCopy(),
+		Labels:            labels,
+		Annotations:       requests.Annotations,
+		ExtendedResources: requests.ExtendedResources,
+		Taints:            requests.Taints,
+	}
+}
+
 // updateNodeFeatureObject creates/updates the node-specific NodeFeature custom resource.
-func (m *nfdWorker) updateNodeFeatureObject(labels Labels) error {
+func (m *nfdWorker) updateNodeFeatureObject(ctx context.Context, spec *nfdv1alpha1.NodeFeatureSpec) error {
 	cli, err := m.getNfdClient()
 	if err != nil {
 		return err
@@ -652,50 +1172,68 @@ func (m *nfdWorker) updateNodeFeatureObject(labels Labels) error {
 	nodename := utils.NodeName()
 	namespace := m.kubernetesNamespace
 
-	features := source.GetAllFeatures()
+	annotations := map[string]string{
+		nfdv1alpha1.WorkerVersionAnnotation: version.Get(),
+		nfdv1alpha1.CreatorNodeAnnotation:   nodename,
+	}
+	if errs, err := m.sourceErrors.annotationValue(); err != nil {
+		klog.ErrorS(err, "failed to serialize source errors")
+	} else if errs != "" {
+		annotations[nfdv1alpha1.SourceErrorsAnnotation] = errs
+	}
+	if deprecated, err := m.deprecatedFeatures.annotationValue(); err != nil {
+		klog.ErrorS(err, "failed to serialize deprecated features")
+	} else if deprecated != "" {
+		annotations[nfdv1alpha1.DeprecatedFeaturesAnnotation] = deprecated
+	}
 
 	// TODO: we could implement some simple caching of the object, only get it
 	// every 10 minutes or so because nobody else should really be modifying it
-	if nfr, err := cli.NfdV1alpha1().NodeFeatures(namespace).Get(context.TODO(), nodename, metav1.GetOptions{}); errors.IsNotFound(err) {
+	if nfr, err := cli.NfdV1alpha1().NodeFeatures(namespace).Get(ctx, nodename, metav1.GetOptions{}); apierrors.IsNotFound(err) {
 		nfr = &nfdv1alpha1.NodeFeature{
 			ObjectMeta: metav1.ObjectMeta{
 				Name:            nodename,
-				Annotations:     map[string]string{nfdv1alpha1.WorkerVersionAnnotation: version.Get()},
+				Annotations:     annotations,
 				Labels:          map[string]string{nfdv1alpha1.NodeFeatureObjNodeNameLabel: nodename},
 				OwnerReferences: m.ownerReference,
 			},
-			Spec: nfdv1alpha1.NodeFeatureSpec{
-				Features: *features,
-				Labels:   labels,
-			},
+			Spec: *spec,
 		}
+		setTraceParent(ctx, nfr)
 		klog.InfoS("creating NodeFeature object", "nodefeature", klog.KObj(nfr))
 
-		nfrCreated, err := cli.NfdV1alpha1().NodeFeatures(namespace).Create(context.TODO(), nfr, metav1.CreateOptions{})
+		nfrCreated, err := cli.NfdV1alpha1().NodeFeatures(namespace).Create(ctx, nfr, metav1.CreateOptions{})
 		if err != nil {
 			return fmt.Errorf("failed to create NodeFeature object %q: %w", nfr.Name, err)
 		}
 
-		klog.V(4).InfoS("NodeFeature object created", "nodeFeature", utils.DelayedDumper(nfrCreated))
+		if source.FeatureLoggingEnabled() {
+			klog.V(4).InfoS("NodeFeature object created", "nodeFeature", utils.DelayedDumper(nfrCreated))
+		}
 	} else if err != nil {
 		return fmt.Errorf("failed to get NodeFeature object: %w", err)
 	} else {
 		nfrUpdated := nfr.DeepCopy()
-		nfrUpdated.Annotations = map[string]string{nfdv1alpha1.WorkerVersionAnnotation: version.Get()}
+		nfrUpdated.Annotations = annotations
 		nfrUpdated.Labels = map[string]string{nfdv1alpha1.NodeFeatureObjNodeNameLabel: nodename}
 		nfrUpdated.OwnerReferences = m.ownerReference
-		nfrUpdated.Spec = nfdv1alpha1.NodeFeatureSpec{
-			Features: *features,
-			Labels:   labels,
-		}
+		nfrUpdated.Spec = *spec
 
-		if !apiequality.Semantic.DeepEqual(nfr, nfrUpdated) {
+		// The trace parent of the previous update alone must not trigger an
+		// update
+		nfrCurrent := nfr.DeepCopy()
+		delete(nfrCurrent.Annotations, nfdv1alpha1.TraceParentAnnotation)
+
+		if !apiequality.Semantic.DeepEqual(nfrCurrent, nfrUpdated) {
+			setTraceParent(ctx, nfrUpdated)
 			klog.InfoS("updating NodeFeature object", "nodefeature", klog.KObj(nfr))
-			nfrUpdated, err = cli.NfdV1alpha1().NodeFeatures(namespace).Update(context.TODO(), nfrUpdated, metav1.UpdateOptions{})
+			nfrUpdated, err = cli.NfdV1alpha1().NodeFeatures(namespace).Update(ctx, nfrUpdated, metav1.UpdateOptions{})
 			if err != nil {
 				return fmt.Errorf("failed to update NodeFeature object %q: %w", nfr.Name, err)
 			}
-			klog.V(4).InfoS("NodeFeature object updated", "nodeFeature", utils.DelayedDumper(nfrUpdated))
+			if source.FeatureLoggingEnabled() {
+				klog.V(4).InfoS("NodeFeature object updated", "nodeFeature", utils.DelayedDumper(nfrUpdated))
+			}
 		} else {
 			klog.V(1).InfoS("no changes in NodeFeature object, not updating", "nodefeature", klog.KObj(nfr))
 		}
@@ -703,6 +1241,30 @@ func (m *nfdWorker) updateNodeFeatureObject(labels Labels) error {
 	return nil
 }
 
+// setTraceParent stores the trace context of ctx in an annotation of the
+// NodeFeature object so that nfd-master can continue the trace.
+func setTraceParent(ctx context.Context, nf *nfdv1alpha1.NodeFeature) {
+	if tp := utils.InjectTraceParent(ctx); tp != "" {
+		if nf.Annotations == nil {
+			nf.Annotations = make(map[string]string)
+		}
+		nf.Annotations[nfdv1alpha1.TraceParentAnnotation] = tp
+	}
+}
+
+// updateNode updates the node object directly, using the same processing of
+// labels (et al.) as nfd-master.
+func (w *nfdWorker) updateNode(ctx context.Context, spec *nfdv1alpha1.NodeFeatureSpec) error {
+	if w.nodeUpdater == nil {
+		u, err := nfdmaster.NewNodeUpdater(w.k8sClient, utils.NodeName(), w.args.MasterConfigFile)
+		if err != nil {
+			return err
+		}
+		w.nodeUpdater = u
+	}
+	return w.nodeUpdater.UpdateNode(ctx, spec)
+}
+
 // getNfdClient returns the clientset for using the nfd CRD api
 func (m *nfdWorker) getNfdClient() (nfdclient.Interface, error) {
 	if m.nfdClient != nil {
@@ -714,7 +1276,7 @@ func (m *nfdWorker) getNfdClient() (nfdclient.Interface, error) {
 		return nil, err
 	}
 
-	c, err := nfdclient.NewForConfig(kubeconfig)
+	c, err := nfdclient.NewForConfig(utils.WithTracing(kubeconfig))
 	if err != nil {
 		return nil, err
 	}
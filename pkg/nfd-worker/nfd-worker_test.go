@@ -77,6 +77,7 @@ func TestRun(t *testing.T) {
 						},
 						Annotations: map[string]string{
 							"nfd.node.kubernetes.io/worker.version": "undefined",
+							"nfd.node.kubernetes.io/creator-node":   "fake-node",
 						},
 						OwnerReferences: []metav1.OwnerReference{},
 					},
@@ -0,0 +1,30 @@
+//go:build !windows
+// +build !windows
+
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nfdworker
+
+// hostPathsMounted tells if the host directories are mounted under the
+// hostpath prefix.
+const hostPathsMounted = true
+
+// isPlatformSource tells if a feature source supports the platform
+// nfd-worker is running on.
+func isPlatformSource(name string) bool {
+	return true
+}
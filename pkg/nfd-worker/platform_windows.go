@@ -0,0 +1,43 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nfdworker
+
+import (
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"sigs.k8s.io/node-feature-discovery/source/cpu"
+	"sigs.k8s.io/node-feature-discovery/source/custom"
+	"sigs.k8s.io/node-feature-discovery/source/local"
+	"sigs.k8s.io/node-feature-discovery/source/memory"
+	"sigs.k8s.io/node-feature-discovery/source/network"
+	"sigs.k8s.io/node-feature-discovery/source/system"
+)
+
+// windowsSources are the feature sources supporting Windows. Other sources
+// are not enabled by "all" and need to be enabled explicitly.
+var windowsSources = sets.New(cpu.Name, custom.Name, local.Name, memory.Name, network.Name, system.Name)
+
+// hostPathsMounted tells if the host directories are mounted under the
+// hostpath prefix. Windows HostProcess containers access the host filesystem
+// directly so there is nothing to check.
+const hostPathsMounted = false
+
+// isPlatformSource tells if a feature source supports the platform
+// nfd-worker is running on.
+func isPlatformSource(name string) bool {
+	return windowsSources.Has(name)
+}
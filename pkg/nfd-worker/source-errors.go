@@ -0,0 +1,73 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nfdworker
+
+import (
+	"encoding/json"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// sourceError describes a failure in the feature discovery of a source.
+type sourceError struct {
+	// Error is the error message of the latest failed discovery.
+	Error string `json:"error"`
+	// Since is the time when the source started failing. It is not updated
+	// while the source keeps failing, in order to avoid needless updates of
+	// the NodeFeature object.
+	Since metav1.Time `json:"since"`
+	// DisabledUntil is set if the source has been disabled by the circuit
+	// breaker because of repeated failures.
+	DisabledUntil *metav1.Time `json:"disabledUntil,omitempty"`
+}
+
+// sourceErrors contains the errors of the feature sources, indexed by source
+// name.
+type sourceErrors map[string]sourceError
+
+// add records a failure of a source. The time of the first failure is
+// preserved from prev if the source was failing also previously.
+func (e sourceErrors) add(name string, err error, prev sourceErrors, now time.Time) {
+	since := metav1.NewTime(now.UTC().Truncate(time.Second))
+	if p, ok := prev[name]; ok {
+		since = p.Since
+	}
+	e[name] = sourceError{Error: err.Error(), Since: since}
+}
+
+// setDisabled marks a failed source as disabled by the circuit breaker.
+func (e sourceErrors) setDisabled(name string, until time.Time) {
+	if se, ok := e[name]; ok {
+		t := metav1.NewTime(until.UTC().Truncate(time.Second))
+		se.DisabledUntil = &t
+		e[name] = se
+	}
+}
+
+// annotationValue returns the source errors serialized for the source errors
+// annotation. An empty string is returned if there are no errors.
+func (e sourceErrors) annotationValue() (string, error) {
+	if len(e) == 0 {
+		return "", nil
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
@@ -0,0 +1,206 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nfdworker
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+
+	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
+	"sigs.k8s.io/node-feature-discovery/source"
+)
+
+// sourceState tracks the health of a feature source across discovery passes.
+type sourceState struct {
+	// running is set while a discovery of the source is in progress. After
+	// a timeout it stays set until the abandoned discovery returns.
+	running atomic.Bool
+	// failures is the number of consecutive failed discoveries.
+	failures int
+	// lastErr is the error of the latest failed discovery.
+	lastErr error
+	// disabledUntil is the time until which the circuit breaker keeps the
+	// source disabled.
+	disabledUntil time.Time
+	// lastUpdated is the time of the latest successful discovery.
+	lastUpdated time.Time
+	// features is a snapshot of the features of the source, taken after the
+	// latest completed discovery. The features of the source itself may be
+	// modified by an abandoned discovery at any time.
+	features *nfdv1alpha1.Features
+}
+
+// errSourceBusy is returned if the previous discovery of a source (that timed
+// out) has not returned yet.
+var errSourceBusy = errors.New("previous feature discovery still running")
+
+// getSourceStates returns the state of each enabled feature source, creating
+// missing states. Must not be called concurrently with discoverSource.
+func (w *nfdWorker) getSourceStates() map[string]*sourceState {
+	if w.sourceStates == nil {
+		w.sourceStates = make(map[string]*sourceState)
+	}
+	for _, s := range w.featureSources {
+		if _, ok := w.sourceStates[s.Name()]; !ok {
+			w.sourceStates[s.Name()] = &sourceState{}
+		}
+	}
+	return w.sourceStates
+}
+
+// runDiscover runs the feature discovery of a source, giving up after the
+// timeout if it is positive. A discovery that timed out is left running in
+// the background and the source is not discovered again before it returns.
+// The returned boolean is true if the discovery completed, i.e. the features
+// of the source may be accessed.
+func runDiscover(s source.FeatureSource, st *sourceState, timeout time.Duration) (bool, error) {
+	if !st.running.CompareAndSwap(false, true) {
+		return false, errSourceBusy
+	}
+	if timeout <= 0 {
+		defer st.running.Store(false)
+		return true, s.Discover()
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		err := s.Discover()
+		st.running.Store(false)
+		done <- err
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case err := <-done:
+		return true, err
+	case <-timer.C:
+		sourceDiscoveryTimeouts.WithLabelValues(s.Name()).Inc()
+		return false, fmt.Errorf("feature discovery timed out after %v", timeout)
+	}
+}
+
+// recordResult updates the circuit breaker state of a source after a
+// discovery. The source is disabled for the cool-down period after
+// failureThreshold consecutive failures. After the cool-down the source is
+// tried again and disabled immediately if it is still failing. A
+// non-positive failureThreshold disables the circuit breaker.
+func (st *sourceState) recordResult(name string, err error, c sourceCircuitBreakerConfig, now time.Time) {
+	if err == nil {
+		st.failures = 0
+		st.lastErr = nil
+		st.disabledUntil = time.Time{}
+		sourceDisabled.WithLabelValues(name).Set(0)
+		return
+	}
+
+	st.failures++
+	st.lastErr = err
+	if c.FailureThreshold > 0 && st.failures >= c.FailureThreshold {
+		st.disabledUntil = now.Add(c.Cooldown.Duration)
+		sourceDisabled.WithLabelValues(name).Set(1)
+	}
+}
+
+// disabled returns true if the circuit breaker keeps the source disabled.
+func (st *sourceState) disabled(now time.Time) bool {
+	return now.Before(st.disabledUntil)
+}
+
+// getFeatures returns the combined features of all enabled feature sources,
+// using the snapshots taken after the latest completed discovery of each
+// source.
+func (w *nfdWorker) getFeatures() *nfdv1alpha1.Features {
+	features := nfdv1alpha1.NewFeatures()
+	for _, s := range w.featureSources {
+		st := w.sourceStates[s.Name()]
+		if st == nil || st.features == nil {
+			continue
+		}
+		for k, v := range st.features.Flags {
+			features.Flags[s.Name()+"."+k] = v
+		}
+		for k, v := range st.features.Attributes {
+			features.Attributes[s.Name()+"."+k] = v
+		}
+		for k, v := range st.features.Instances {
+			features.Instances[s.Name()+"."+k] = v
+		}
+	}
+	return features
+}
+
+// readyLabelSources returns the label sources whose features may be accessed,
+// i.e. whose feature source is not being discovered in the background. Label
+// sources that are not feature sources themselves (like the custom source)
+// read the features of all sources and are skipped if any source is busy.
+func (w *nfdWorker) readyLabelSources() []source.LabelSource {
+	busy := make(map[string]bool)
+	for name, st := range w.sourceStates {
+		if st.running.Load() {
+			busy[name] = true
+		}
+	}
+	if len(busy) == 0 {
+		return w.labelSources
+	}
+
+	ready := make([]source.LabelSource, 0, len(w.labelSources))
+	for _, s := range w.labelSources {
+		_, isFeatureSource := s.(source.FeatureSource)
+		if busy[s.Name()] || !isFeatureSource {
+			klog.V(2).InfoS("skipping label source, feature discovery still running", "source", s.Name())
+			continue
+		}
+		ready = append(ready, s)
+	}
+	return ready
+}
+
+// setFeatureTimestamps sets the LastUpdated timestamp of each feature set to
+// the time of the latest successful discovery of its feature source. Feature
+// sets of sources that have not been discovered successfully are left without
+// a timestamp.
+func (w *nfdWorker) setFeatureTimestamps(f *nfdv1alpha1.Features) {
+	lastUpdated := func(featureName string) *metav1.Time {
+		sourceName, _, _ := strings.Cut(featureName, ".")
+		if st := w.sourceStates[sourceName]; st != nil && !st.lastUpdated.IsZero() {
+			return ptr.To(metav1.NewTime(st.lastUpdated))
+		}
+		return nil
+	}
+
+	for k, v := range f.Flags {
+		v.LastUpdated = lastUpdated(k)
+		f.Flags[k] = v
+	}
+	for k, v := range f.Attributes {
+		v.LastUpdated = lastUpdated(k)
+		f.Attributes[k] = v
+	}
+	for k, v := range f.Instances {
+		v.LastUpdated = lastUpdated(k)
+		f.Instances[k] = v
+	}
+}
@@ -0,0 +1,179 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nfdworker
+
+import (
+	"bytes"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// ueventDebounce is the quiet period after the latest uevent before feature
+// discovery is triggered. It coalesces bursts of events, e.g. from a PCI
+// rescan, into one feature discovery round.
+const ueventDebounce = 2 * time.Second
+
+// ueventActions are the uevent actions that trigger feature discovery. Change
+// events are ignored as some devices (e.g. power supplies) emit them
+// constantly.
+var ueventActions = map[string]struct{}{
+	"add":     {},
+	"remove":  {},
+	"bind":    {},
+	"unbind":  {},
+	"online":  {},
+	"offline": {},
+}
+
+// uevent is a kernel object event.
+type uevent struct {
+	Action    string
+	Subsystem string
+	Devpath   string
+}
+
+// ueventSocket is a source of raw kernel uevent messages.
+type ueventSocket interface {
+	// receive reads one message into the buffer. It returns zero bytes
+	// without an error if no message was received before a timeout.
+	receive(buf []byte) (int, error)
+	close() error
+}
+
+// ueventWatcher watches kernel uevents of the given subsystems.
+type ueventWatcher struct {
+	sock       ueventSocket
+	subsystems map[string]struct{}
+	events     chan uevent
+	stop       chan struct{}
+	wg         sync.WaitGroup
+}
+
+// newUeventWatcher creates a new watcher for uevents of the given subsystems
+// and starts it.
+func newUeventWatcher(subsystems []string) (*ueventWatcher, error) {
+	sock, err := openUeventSocket()
+	if err != nil {
+		return nil, err
+	}
+	return startUeventWatcher(sock, subsystems), nil
+}
+
+func startUeventWatcher(sock ueventSocket, subsystems []string) *ueventWatcher {
+	w := &ueventWatcher{
+		sock:       sock,
+		subsystems: make(map[string]struct{}, len(subsystems)),
+		events:     make(chan uevent, 64),
+		stop:       make(chan struct{}),
+	}
+	for _, s := range subsystems {
+		w.subsystems[s] = struct{}{}
+	}
+
+	w.wg.Add(1)
+	go w.run()
+
+	return w
+}
+
+// Events returns the channel of received uevents.
+func (w *ueventWatcher) Events() <-chan uevent {
+	return w.events
+}
+
+// Stop stops the watcher.
+func (w *ueventWatcher) Stop() {
+	close(w.stop)
+	w.wg.Wait()
+	if err := w.sock.close(); err != nil {
+		klog.ErrorS(err, "failed to close uevent socket")
+	}
+}
+
+func (w *ueventWatcher) run() {
+	defer w.wg.Done()
+
+	buf := make([]byte, 8192)
+	for {
+		select {
+		case <-w.stop:
+			return
+		default:
+		}
+
+		n, err := w.sock.receive(buf)
+		if err != nil {
+			klog.ErrorS(err, "failed to receive uevent")
+			// Avoid a busy loop on persistent errors
+			select {
+			case <-w.stop:
+				return
+			case <-time.After(time.Second):
+			}
+			continue
+		}
+		if n == 0 {
+			continue
+		}
+
+		ev, ok := parseUevent(buf[:n])
+		if !ok {
+			continue
+		}
+		if _, ok := w.subsystems[ev.Subsystem]; !ok {
+			continue
+		}
+		if _, ok := ueventActions[ev.Action]; !ok {
+			continue
+		}
+		// Drop the event if the consumer is lagging behind, one pending
+		// event is enough for triggering feature discovery.
+		select {
+		case w.events <- ev:
+		default:
+		}
+	}
+}
+
+// parseUevent parses a raw kernel uevent message, consisting of a
+// "<action>@<devpath>" header and NUL-separated KEY=VALUE pairs. Messages
+// re-broadcast by udev are ignored.
+func parseUevent(b []byte) (uevent, bool) {
+	fields := bytes.Split(b, []byte{0})
+	if len(fields) < 2 || !bytes.Contains(fields[0], []byte("@")) {
+		return uevent{}, false
+	}
+
+	ev := uevent{}
+	for _, f := range fields[1:] {
+		k, v, ok := bytes.Cut(f, []byte("="))
+		if !ok {
+			continue
+		}
+		switch string(k) {
+		case "ACTION":
+			ev.Action = string(v)
+		case "SUBSYSTEM":
+			ev.Subsystem = string(v)
+		case "DEVPATH":
+			ev.Devpath = string(v)
+		}
+	}
+	return ev, ev.Action != "" && ev.Subsystem != ""
+}
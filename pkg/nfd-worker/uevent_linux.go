@@ -0,0 +1,66 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nfdworker
+
+import (
+	"errors"
+	"fmt"
+	"syscall"
+)
+
+// netlinkSocket is a netlink socket bound to the kernel uevent multicast
+// group.
+type netlinkSocket struct {
+	fd int
+}
+
+func openUeventSocket() (ueventSocket, error) {
+	fd, err := syscall.Socket(syscall.AF_NETLINK, syscall.SOCK_RAW|syscall.SOCK_CLOEXEC, syscall.NETLINK_KOBJECT_UEVENT)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create netlink socket: %w", err)
+	}
+
+	// Bind to the multicast group of kernel uevents (group 1)
+	if err := syscall.Bind(fd, &syscall.SockaddrNetlink{Family: syscall.AF_NETLINK, Groups: 1}); err != nil {
+		syscall.Close(fd)
+		return nil, fmt.Errorf("failed to bind netlink socket: %w", err)
+	}
+
+	// Use a receive timeout so that the watcher is able to stop
+	tv := syscall.Timeval{Sec: 1}
+	if err := syscall.SetsockoptTimeval(fd, syscall.SOL_SOCKET, syscall.SO_RCVTIMEO, &tv); err != nil {
+		syscall.Close(fd)
+		return nil, fmt.Errorf("failed to set netlink socket timeout: %w", err)
+	}
+
+	return &netlinkSocket{fd: fd}, nil
+}
+
+func (s *netlinkSocket) receive(buf []byte) (int, error) {
+	n, _, err := syscall.Recvfrom(s.fd, buf, 0)
+	if errors.Is(err, syscall.EAGAIN) || errors.Is(err, syscall.EINTR) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+func (s *netlinkSocket) close() error {
+	return syscall.Close(s.fd)
+}
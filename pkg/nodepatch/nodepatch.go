@@ -0,0 +1,110 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package nodepatch computes the JSON patches for updating the labels,
+// annotations and extended resources of Node objects.
+package nodepatch
+
+import (
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"sigs.k8s.io/node-feature-discovery/pkg/utils"
+)
+
+const (
+	// CapacityPath is the JSON path of the capacity in the node status.
+	CapacityPath = "/status/capacity"
+	// AllocatablePath is the JSON path of the allocatable resources in the
+	// node status.
+	AllocatablePath = "/status/allocatable"
+)
+
+// Items returns the JSON patch operations for updating a map of items, e.g.
+// the labels or annotations of a node, from oldItems to newItems. Only items
+// listed in removeKeys are removed if missing from newItems. Existing items
+// not listed in removeKeys are only replaced if overwrite is true.
+func Items(removeKeys sets.Set[string], oldItems map[string]string, newItems map[string]string, jsonPath string, overwrite bool) []utils.JsonPatch {
+	patches := []utils.JsonPatch{}
+
+	// Determine items to remove
+	for key := range removeKeys {
+		if _, ok := oldItems[key]; ok {
+			if _, ok := newItems[key]; !ok {
+				patches = append(patches, utils.NewJsonPatch("remove", jsonPath, key, ""))
+			}
+		}
+	}
+
+	// Determine items to add or replace
+	for key, newVal := range newItems {
+		if oldVal, ok := oldItems[key]; ok {
+			if newVal != oldVal && (!removeKeys.Has(key) || overwrite) {
+				patches = append(patches, utils.NewJsonPatch("replace", jsonPath, key, newVal))
+			}
+		} else {
+			patches = append(patches, utils.NewJsonPatch("add", jsonPath, key, newVal))
+		}
+	}
+
+	return patches
+}
+
+// ExtendedResources returns the JSON patch operations for updating the
+// extended resources in the status of a node. Resources listed in managed,
+// i.e. the ones created earlier, are removed if missing from
+// extendedResources.
+func ExtendedResources(node *corev1.Node, managed []string, extendedResources map[string]string) []utils.JsonPatch {
+	// figure out which resources to remove
+	patches := ExtendedResourceRemovals(node, managed, extendedResources)
+
+	// figure out which resources to replace and which to add
+	for resource, value := range extendedResources {
+		// check if the extended resource already exists with the same capacity in the node
+		if quantity, ok := node.Status.Capacity[corev1.ResourceName(resource)]; ok {
+			val, _ := quantity.AsInt64()
+			if strconv.FormatInt(val, 10) != value {
+				patches = append(patches, utils.NewJsonPatch("replace", CapacityPath, resource, value))
+				patches = append(patches, utils.NewJsonPatch("replace", AllocatablePath, resource, value))
+			}
+		} else {
+			patches = append(patches, utils.NewJsonPatch("add", CapacityPath, resource, value))
+			// "allocatable" gets added implicitly after adding to capacity
+		}
+	}
+
+	return patches
+}
+
+// ExtendedResourceRemovals returns the JSON patch operations for removing the
+// extended resources listed in managed that are missing from
+// extendedResources from the status of a node.
+func ExtendedResourceRemovals(node *corev1.Node, managed []string, extendedResources map[string]string) []utils.JsonPatch {
+	patches := []utils.JsonPatch{}
+
+	for _, resource := range managed {
+		if _, ok := node.Status.Capacity[corev1.ResourceName(resource)]; ok {
+			// check if the ext resource is still needed
+			if _, extResNeeded := extendedResources[resource]; !extResNeeded {
+				patches = append(patches, utils.NewJsonPatch("remove", CapacityPath, resource, ""))
+				patches = append(patches, utils.NewJsonPatch("remove", AllocatablePath, resource, ""))
+			}
+		}
+	}
+	return patches
+}
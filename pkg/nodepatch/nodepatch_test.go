@@ -0,0 +1,361 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodepatch
+
+import (
+	"fmt"
+	"math/rand"
+	"path"
+	"sort"
+	"strings"
+	"testing"
+	"testing/quick"
+
+	. "github.com/smartystreets/goconvey/convey"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	k8svalidation "k8s.io/apimachinery/pkg/util/validation"
+
+	"sigs.k8s.io/node-feature-discovery/pkg/utils"
+)
+
+func TestItems(t *testing.T) {
+	Convey("When creating JSON patches", t, func() {
+		existingItems := map[string]string{"key-1": "val-1", "key-2": "val-2", "key-3": "val-3"}
+		overwriteKeys := true
+		jsonPath := "/root"
+
+		Convey("When there are neither itmes to remoe nor to add or update", func() {
+			p := Items(sets.New([]string{"foo", "bar"}...), existingItems, map[string]string{}, jsonPath, overwriteKeys)
+			So(len(p), ShouldEqual, 0)
+		})
+
+		Convey("When there are itmes to remoe but none to add or update", func() {
+			p := Items(sets.New([]string{"key-2", "key-3", "foo"}...), existingItems, map[string]string{}, jsonPath, overwriteKeys)
+			expected := []utils.JsonPatch{
+				utils.NewJsonPatch("remove", jsonPath, "key-2", ""),
+				utils.NewJsonPatch("remove", jsonPath, "key-3", ""),
+			}
+			So(sortJsonPatches(p), ShouldResemble, sortJsonPatches(expected))
+		})
+
+		Convey("When there are no itmes to remove but new items to add", func() {
+			newItems := map[string]string{"new-key": "new-val", "key-1": "new-1"}
+			p := Items(sets.New([]string{"key-1"}...), existingItems, newItems, jsonPath, overwriteKeys)
+			expected := []utils.JsonPatch{
+				utils.NewJsonPatch("add", jsonPath, "new-key", newItems["new-key"]),
+				utils.NewJsonPatch("replace", jsonPath, "key-1", newItems["key-1"]),
+			}
+			So(sortJsonPatches(p), ShouldResemble, sortJsonPatches(expected))
+		})
+
+		Convey("When there are items to remove add and update", func() {
+			newItems := map[string]string{"new-key": "new-val", "key-2": "new-2", "key-4": "val-4"}
+			p := Items(sets.New([]string{"key-1", "key-2", "key-3", "foo"}...), existingItems, newItems, jsonPath, overwriteKeys)
+			expected := []utils.JsonPatch{
+				utils.NewJsonPatch("add", jsonPath, "new-key", newItems["new-key"]),
+				utils.NewJsonPatch("add", jsonPath, "key-4", newItems["key-4"]),
+				utils.NewJsonPatch("replace", jsonPath, "key-2", newItems["key-2"]),
+				utils.NewJsonPatch("remove", jsonPath, "key-1", ""),
+				utils.NewJsonPatch("remove", jsonPath, "key-3", ""),
+			}
+			So(sortJsonPatches(p), ShouldResemble, sortJsonPatches(expected))
+		})
+
+		Convey("When overwrite of keys is denied and there is already an existant key", func() {
+			overwriteKeys = false
+			newItems := map[string]string{"key-1": "new-2", "key-4": "val-4"}
+			p := Items(sets.New([]string{}...), existingItems, newItems, jsonPath, overwriteKeys)
+			expected := []utils.JsonPatch{
+				utils.NewJsonPatch("add", jsonPath, "key-4", newItems["key-4"]),
+				utils.NewJsonPatch("replace", jsonPath, "key-1", newItems["key-1"]),
+			}
+			So(sortJsonPatches(p), ShouldResemble, sortJsonPatches(expected))
+		})
+
+		Convey("When overwrite of keys is denied and the key has been managed by us", func() {
+			overwriteKeys = false
+			newItems := map[string]string{"key-1": "new-1"}
+			p := Items(sets.New("key-1"), existingItems, newItems, jsonPath, overwriteKeys)
+			So(p, ShouldBeEmpty)
+		})
+
+		Convey("Keys with slashes should be escaped", func() {
+			p := Items(nil, nil, map[string]string{"example.com/foo": "bar"}, jsonPath, overwriteKeys)
+			So(p, ShouldResemble, []utils.JsonPatch{{Op: "add", Path: "/root/example.com~1foo", Value: "bar"}})
+		})
+	})
+}
+
+func TestExtendedResources(t *testing.T) {
+	Convey("When creating JSON patches for extended resources", t, func() {
+		node := newTestNode()
+		node.Status.Capacity["example.com/foo"] = resource.MustParse("1")
+		node.Status.Allocatable["example.com/foo"] = resource.MustParse("1")
+		node.Status.Capacity["example.com/bar"] = resource.MustParse("2")
+		node.Status.Allocatable["example.com/bar"] = resource.MustParse("2")
+		node.Status.Capacity["example.com/unmanaged"] = resource.MustParse("3")
+		managed := []string{"example.com/foo", "example.com/bar", "example.com/gone"}
+
+		Convey("Nothing should be done if the resources are up to date", func() {
+			p := ExtendedResources(node, managed, map[string]string{"example.com/foo": "1", "example.com/bar": "2"})
+			So(p, ShouldBeEmpty)
+		})
+
+		Convey("Resources should be added, updated and removed", func() {
+			p := ExtendedResources(node, managed, map[string]string{"example.com/bar": "5", "example.com/new": "1"})
+			So(sortJsonPatches(p), ShouldResemble, sortJsonPatches([]utils.JsonPatch{
+				utils.NewJsonPatch("remove", CapacityPath, "example.com/foo", ""),
+				utils.NewJsonPatch("remove", AllocatablePath, "example.com/foo", ""),
+				utils.NewJsonPatch("replace", CapacityPath, "example.com/bar", "5"),
+				utils.NewJsonPatch("replace", AllocatablePath, "example.com/bar", "5"),
+				utils.NewJsonPatch("add", CapacityPath, "example.com/new", "1"),
+			}))
+		})
+
+		Convey("Unmanaged resources should not be removed", func() {
+			p := ExtendedResourceRemovals(node, managed, nil)
+			So(sortJsonPatches(p), ShouldResemble, sortJsonPatches([]utils.JsonPatch{
+				utils.NewJsonPatch("remove", CapacityPath, "example.com/foo", ""),
+				utils.NewJsonPatch("remove", AllocatablePath, "example.com/foo", ""),
+				utils.NewJsonPatch("remove", CapacityPath, "example.com/bar", ""),
+				utils.NewJsonPatch("remove", AllocatablePath, "example.com/bar", ""),
+			}))
+		})
+	})
+}
+
+// TestItemsIdempotency asserts that applying the computed patches to a node
+// converges, i.e. a second round yields no further changes.
+func TestItemsIdempotency(t *testing.T) {
+	f := func(seed int64) bool {
+		r := rand.New(rand.NewSource(seed))
+		oldItems, newItems := randomItems(r), randomItems(r)
+		removeKeys := sets.KeySet(randomItems(r))
+		overwrite := r.Intn(2) == 0
+		return checkItemsIdempotency(t, removeKeys, oldItems, newItems, overwrite)
+	}
+	if err := quick.Check(f, &quick.Config{MaxCount: 1000}); err != nil {
+		t.Error(err)
+	}
+}
+
+// TestExtendedResourcesIdempotency asserts that applying the computed patches
+// for extended resources converges.
+func TestExtendedResourcesIdempotency(t *testing.T) {
+	f := func(seed int64) bool {
+		r := rand.New(rand.NewSource(seed))
+		node := newTestNode()
+		for name, value := range randomItems(r) {
+			q := resource.MustParse(fmt.Sprint(len(value)))
+			node.Status.Capacity[corev1.ResourceName(name)] = q
+			node.Status.Allocatable[corev1.ResourceName(name)] = q
+		}
+		managed := sets.List(sets.KeySet(randomItems(r)))
+		extendedResources := map[string]string{}
+		for name, value := range randomItems(r) {
+			extendedResources[name] = fmt.Sprint(len(value))
+		}
+
+		patched, err := applyPatches(node, ExtendedResources(node, managed, extendedResources))
+		if err != nil {
+			t.Logf("seed %d: %v", seed, err)
+			return false
+		}
+		for name, value := range extendedResources {
+			if q := patched.Status.Capacity[corev1.ResourceName(name)]; q.String() != value {
+				t.Logf("seed %d: capacity of %q is %s, expected %s", seed, name, q.String(), value)
+				return false
+			}
+		}
+		if p := ExtendedResources(patched, managed, extendedResources); len(p) > 0 {
+			t.Logf("seed %d: unexpected patches on second round: %v", seed, p)
+			return false
+		}
+		return true
+	}
+	if err := quick.Check(f, &quick.Config{MaxCount: 1000}); err != nil {
+		t.Error(err)
+	}
+}
+
+func FuzzItems(f *testing.F) {
+	f.Add("a=1,b=2", "b=3,c=4", "a,b", true)
+	f.Add("example.com/a=1", "", "example.com/a", false)
+	f.Add("", "a=", "", false)
+	f.Fuzz(func(t *testing.T, oldStr, newStr, removeStr string, overwrite bool) {
+		oldItems, newItems := parseItems(oldStr), parseItems(newStr)
+		removeKeys := sets.KeySet(parseItems(strings.ReplaceAll(removeStr, ",", "=,") + "="))
+		checkItemsIdempotency(t, removeKeys, oldItems, newItems, overwrite)
+	})
+}
+
+func checkItemsIdempotency(t *testing.T, removeKeys sets.Set[string], oldItems, newItems map[string]string, overwrite bool) bool {
+	t.Helper()
+	node := newTestNode()
+	node.Labels = oldItems
+
+	patched, err := applyPatches(node, Items(removeKeys, oldItems, newItems, "/metadata/labels", overwrite))
+	if err != nil {
+		t.Errorf("failed to apply patches: %v", err)
+		return false
+	}
+	for key, value := range newItems {
+		oldValue, existed := oldItems[key]
+		// Items managed by us are left untouched if overwrite is denied
+		keep := existed && removeKeys.Has(key) && !overwrite
+		if got := patched.Labels[key]; (keep && got != oldValue) || (!keep && got != value) {
+			t.Errorf("unexpected value %q of %q", got, key)
+			return false
+		}
+	}
+	for key := range removeKeys {
+		if _, ok := newItems[key]; !ok {
+			if _, ok := patched.Labels[key]; ok {
+				t.Errorf("%q should have been removed", key)
+				return false
+			}
+		}
+	}
+	if p := Items(removeKeys, patched.Labels, newItems, "/metadata/labels", overwrite); len(p) > 0 {
+		t.Errorf("unexpected patches on second round: %v", p)
+		return false
+	}
+	return true
+}
+
+// applyPatches applies JSON patches to a copy of a node object, following
+// the semantics of the API server.
+func applyPatches(node *corev1.Node, patches []utils.JsonPatch) (*corev1.Node, error) {
+	n := node.DeepCopy()
+	for _, p := range patches {
+		dir, key := path.Split(p.Path)
+		dir = strings.TrimSuffix(dir, "/")
+		key = strings.NewReplacer("~1", "/", "~0", "~").Replace(key)
+
+		switch dir {
+		case "/metadata/labels", "/metadata/annotations":
+			m := &n.Labels
+			if dir == "/metadata/annotations" {
+				m = &n.Annotations
+			}
+			if err := applyToMap(*m, p.Op, key, p.Value, func(k string, v string) {
+				if *m == nil {
+					*m = map[string]string{}
+				}
+				(*m)[k] = v
+			}); err != nil {
+				return nil, err
+			}
+		case CapacityPath, AllocatablePath:
+			rl := n.Status.Capacity
+			if dir == AllocatablePath {
+				rl = n.Status.Allocatable
+			}
+			_, exists := rl[corev1.ResourceName(key)]
+			switch p.Op {
+			case "add", "replace":
+				if p.Op == "replace" && !exists {
+					return nil, fmt.Errorf("cannot replace missing %s", p.Path)
+				}
+				q, err := resource.ParseQuantity(p.Value)
+				if err != nil {
+					return nil, err
+				}
+				rl[corev1.ResourceName(key)] = q
+				// The allocatable resources are derived from the capacity
+				if dir == CapacityPath && p.Op == "add" {
+					n.Status.Allocatable[corev1.ResourceName(key)] = q
+				}
+			case "remove":
+				if !exists {
+					return nil, fmt.Errorf("cannot remove missing %s", p.Path)
+				}
+				delete(rl, corev1.ResourceName(key))
+			default:
+				return nil, fmt.Errorf("unsupported op %q", p.Op)
+			}
+		default:
+			return nil, fmt.Errorf("unsupported path %q", p.Path)
+		}
+	}
+	return n, nil
+}
+
+func applyToMap(m map[string]string, op, key, value string, set func(string, string)) error {
+	_, exists := m[key]
+	switch op {
+	case "add":
+		set(key, value)
+	case "replace":
+		if !exists {
+			return fmt.Errorf("cannot replace missing key %q", key)
+		}
+		set(key, value)
+	case "remove":
+		if !exists {
+			return fmt.Errorf("cannot remove missing key %q", key)
+		}
+		delete(m, key)
+	default:
+		return fmt.Errorf("unsupported op %q", op)
+	}
+	return nil
+}
+
+// randomItems returns a random map with keys from a small key space so that
+// the maps generated overlap.
+func randomItems(r *rand.Rand) map[string]string {
+	items := map[string]string{}
+	for i := r.Intn(6); i > 0; i-- {
+		items[fmt.Sprintf("example.com/key-%d", r.Intn(8))] = strings.Repeat("x", r.Intn(3)+1)
+	}
+	return items
+}
+
+// parseItems parses a comma-separated list of key=value pairs. Keys that are
+// not valid label keys are dropped.
+func parseItems(s string) map[string]string {
+	items := map[string]string{}
+	for _, kv := range strings.Split(s, ",") {
+		if k, v, ok := strings.Cut(kv, "="); ok && len(k8svalidation.IsQualifiedName(k)) == 0 {
+			items[k] = v
+		}
+	}
+	return items
+}
+
+func newTestNode() *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "node-1",
+			Labels:      map[string]string{},
+			Annotations: map[string]string{},
+		},
+		Status: corev1.NodeStatus{
+			Capacity:    corev1.ResourceList{},
+			Allocatable: corev1.ResourceList{},
+		},
+	}
+}
+
+func sortJsonPatches(p []utils.JsonPatch) []utils.JsonPatch {
+	sort.Slice(p, func(i, j int) bool { return p[i].Path < p[j].Path })
+	return p
+}
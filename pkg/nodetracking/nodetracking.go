@@ -0,0 +1,150 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package nodetracking implements the naming of the node annotations and
+// ConfigMaps that nfd-master uses for tracking the NFD-managed labels,
+// annotations, extended resources and taints of nodes.
+package nodetracking
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
+)
+
+const (
+	// ConfigMapLabel is the label identifying ConfigMaps holding tracking
+	// information of nfd-master.
+	ConfigMapLabel = nfdv1alpha1.AnnotationNs + "/tracking"
+
+	// configMapHashLen is the length of the hash suffix used in the names of
+	// tracking ConfigMaps that would otherwise be too long.
+	configMapHashLen = 16
+)
+
+// Names gives the names of the tracking annotations and ConfigMaps of one
+// nfd-master deployment.
+type Names struct {
+	// Instance is the instance name of nfd-master (-instance).
+	Instance string
+	// AnnotationNs is the annotation namespace of nfd-master (annotationNs).
+	AnnotationNs string
+}
+
+// InstanceAnnotation returns the name of an annotation prefixed with the
+// instance name.
+func (n Names) InstanceAnnotation(name string) string {
+	if n.Instance == "" {
+		return name
+	}
+	return n.Instance + "." + name
+}
+
+// TrackingAnnotation returns the name of an NFD annotation in the configured
+// annotation namespace, prefixed with the instance name.
+func (n Names) TrackingAnnotation(name string) string {
+	return n.InstanceAnnotation(RebrandAnnotation(name, n.AnnotationNs))
+}
+
+// TaintsAnnotation returns the name of the annotation tracking NFD-managed
+// taints. It is not instance-specific.
+func (n Names) TaintsAnnotation() string {
+	return RebrandAnnotation(nfdv1alpha1.NodeTaintsAnnotation, n.AnnotationNs)
+}
+
+// TrackingKeys returns the names of the node annotations used for tracking
+// NFD-managed node properties. These are stored in the tracking ConfigMap
+// instead of the node when the ConfigMap storage is used.
+func (n Names) TrackingKeys() sets.Set[string] {
+	return sets.New(
+		n.TrackingAnnotation(nfdv1alpha1.FeatureLabelsAnnotation),
+		n.TrackingAnnotation(nfdv1alpha1.FeatureAnnotationsTrackingAnnotation),
+		n.TrackingAnnotation(nfdv1alpha1.ExtendedResourceAnnotation),
+		n.TaintsAnnotation(),
+	)
+}
+
+// LegacyTrackingKeys returns a mapping from the names of the tracking
+// annotations in the default annotation namespace to their names in the
+// configured annotation namespace. It is empty if the default namespace is
+// used.
+func (n Names) LegacyTrackingKeys() map[string]string {
+	if n.AnnotationNs == "" || n.AnnotationNs == nfdv1alpha1.AnnotationNs {
+		return nil
+	}
+	return map[string]string{
+		n.InstanceAnnotation(nfdv1alpha1.FeatureLabelsAnnotation):              n.TrackingAnnotation(nfdv1alpha1.FeatureLabelsAnnotation),
+		n.InstanceAnnotation(nfdv1alpha1.FeatureAnnotationsTrackingAnnotation): n.TrackingAnnotation(nfdv1alpha1.FeatureAnnotationsTrackingAnnotation),
+		n.InstanceAnnotation(nfdv1alpha1.ExtendedResourceAnnotation):           n.TrackingAnnotation(nfdv1alpha1.ExtendedResourceAnnotation),
+		nfdv1alpha1.NodeTaintsAnnotation:                                       n.TaintsAnnotation(),
+	}
+}
+
+// NodeAnnotations returns the names of all node annotations managed by
+// nfd-master for bookkeeping, including the tracking annotations and their
+// legacy names.
+func (n Names) NodeAnnotations() sets.Set[string] {
+	names := n.TrackingKeys()
+	for old := range n.LegacyTrackingKeys() {
+		names.Insert(old)
+	}
+	for _, a := range []string{
+		nfdv1alpha1.ExtendedResourceValuesAnnotation,
+		nfdv1alpha1.LastAppliedTimeAnnotation,
+		nfdv1alpha1.RejectedItemsAnnotation,
+		nfdv1alpha1.LabelConflictsAnnotation,
+	} {
+		names.Insert(n.TrackingAnnotation(a), n.InstanceAnnotation(a))
+	}
+	return names
+}
+
+// ConfigMapName returns the name of the tracking ConfigMap of a node. Names
+// exceeding the maximum length are truncated and suffixed with a hash of the
+// full name to keep them unique.
+func (n Names) ConfigMapName(nodeName string) string {
+	name := "nfd-tracking-" + nodeName
+	if n.Instance != "" {
+		name = "nfd-tracking-" + n.Instance + "-" + nodeName
+	}
+	if len(name) <= validation.DNS1123SubdomainMaxLength {
+		return name
+	}
+	sum := sha256.Sum256([]byte(name))
+	// Drop trailing separators so that the name stays a valid DNS subdomain
+	prefix := strings.TrimRight(name[:validation.DNS1123SubdomainMaxLength-configMapHashLen-1], ".-")
+	return prefix + "-" + hex.EncodeToString(sum[:])[:configMapHashLen]
+}
+
+// RebrandAnnotation replaces the default NFD annotation namespace in the
+// name of an annotation.
+func RebrandAnnotation(name, ns string) string {
+	if ns == "" || ns == nfdv1alpha1.AnnotationNs {
+		return name
+	}
+	if name == nfdv1alpha1.AnnotationNs {
+		return ns
+	}
+	if n, ok := strings.CutPrefix(name, nfdv1alpha1.AnnotationNs+"/"); ok {
+		return ns + "/" + n
+	}
+	return name
+}
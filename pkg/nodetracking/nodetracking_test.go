@@ -0,0 +1,59 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodetracking
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
+)
+
+func TestNames(t *testing.T) {
+	Convey("When using the default instance and annotation namespace", t, func() {
+		n := Names{}
+
+		Convey("the default annotation names should be used", func() {
+			So(n.TrackingAnnotation(nfdv1alpha1.FeatureLabelsAnnotation), ShouldEqual, nfdv1alpha1.FeatureLabelsAnnotation)
+			So(n.TaintsAnnotation(), ShouldEqual, nfdv1alpha1.NodeTaintsAnnotation)
+			So(n.LegacyTrackingKeys(), ShouldBeEmpty)
+			So(n.ConfigMapName("node-1"), ShouldEqual, "nfd-tracking-node-1")
+		})
+	})
+
+	Convey("When using an instance name and a custom annotation namespace", t, func() {
+		n := Names{Instance: "foo", AnnotationNs: "nfd.example.com"}
+
+		Convey("the annotation names should be prefixed and rebranded", func() {
+			So(n.InstanceAnnotation(nfdv1alpha1.FeatureLabelsAnnotation), ShouldEqual, "foo.nfd.node.kubernetes.io/feature-labels")
+			So(n.TrackingAnnotation(nfdv1alpha1.FeatureLabelsAnnotation), ShouldEqual, "foo.nfd.example.com/feature-labels")
+			So(n.TaintsAnnotation(), ShouldEqual, "nfd.example.com/taints")
+			So(n.TrackingKeys(), ShouldResemble, sets.New(
+				"foo.nfd.example.com/feature-labels",
+				"foo.nfd.example.com/feature-annotations",
+				"foo.nfd.example.com/extended-resources",
+				"nfd.example.com/taints"))
+			So(n.LegacyTrackingKeys(), ShouldContainKey, "foo.nfd.node.kubernetes.io/feature-labels")
+			So(n.LegacyTrackingKeys(), ShouldContainKey, nfdv1alpha1.NodeTaintsAnnotation)
+			So(n.NodeAnnotations().Has("foo.nfd.example.com/last-applied-time"), ShouldBeTrue)
+			So(n.NodeAnnotations().Has("foo.nfd.node.kubernetes.io/last-applied-time"), ShouldBeTrue)
+			So(n.ConfigMapName("node-1"), ShouldEqual, "nfd-tracking-foo-node-1")
+		})
+	})
+}
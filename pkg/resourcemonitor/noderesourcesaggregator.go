@@ -19,6 +19,7 @@ package resourcemonitor
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -34,6 +35,16 @@ import (
 	"sigs.k8s.io/node-feature-discovery/pkg/utils/hostpath"
 )
 
+const (
+	// Names of the zone attributes
+	zoneAttributeNodeType   = "nodeType"
+	zoneAttributeMemoryTier = "memoryTier"
+
+	// Values of the nodeType zone attribute
+	nodeTypeMemoryOnly = "memory-only"
+	nodeTypeCPUOnly    = "cpu-only"
+)
+
 const (
 	// obtained these values from node e2e tests : https://github.com/kubernetes/kubernetes/blob/82baa26905c94398a0d19e1b1ecf54eb8acb6029/test/e2e_node/util.go#L70
 	defaultPodResourcesTimeout = 10 * time.Second
@@ -46,6 +57,7 @@ type nodeResources struct {
 	topo                           *ghw.TopologyInfo
 	reservedCPUIDPerNUMA           map[int][]string
 	memoryResourcesCapacityPerNUMA utils.NumaMemoryResources
+	memoryTierPerNUMA              map[int]int
 	excludeList                    ExcludeResourceList
 }
 
@@ -70,6 +82,11 @@ func NewResourcesAggregator(podResourceClient podresourcesapi.PodResourcesLister
 		return nil, err
 	}
 
+	memoryTierPerNUMA, err := utils.GetNumaMemoryTiers()
+	if err != nil {
+		klog.ErrorS(err, "failed to get memory tiers of NUMA nodes")
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), defaultPodResourcesTimeout)
 	defer cancel()
 
@@ -86,11 +103,11 @@ func NewResourcesAggregator(podResourceClient podresourcesapi.PodResourcesLister
 		return nil, fmt.Errorf("failed to get allocatable resources (ensure that KubeletPodResourcesGetAllocatable feature gate is enabled): %w", err)
 	}
 
-	return NewResourcesAggregatorFromData(topo, resp, memoryResourcesCapacityPerNUMA, excludeList), nil
+	return NewResourcesAggregatorFromData(topo, resp, memoryResourcesCapacityPerNUMA, memoryTierPerNUMA, excludeList), nil
 }
 
 // NewResourcesAggregatorFromData is used to aggregate resource information based on the received data from underlying hardware and podresource API
-func NewResourcesAggregatorFromData(topo *ghw.TopologyInfo, resp *podresourcesapi.AllocatableResourcesResponse, memoryResourceCapacity utils.NumaMemoryResources, excludeList ExcludeResourceList) ResourcesAggregator {
+func NewResourcesAggregatorFromData(topo *ghw.TopologyInfo, resp *podresourcesapi.AllocatableResourcesResponse, memoryResourceCapacity utils.NumaMemoryResources, memoryTiers map[int]int, excludeList ExcludeResourceList) ResourcesAggregator {
 	allDevs := getContainerDevicesFromAllocatableResources(resp, topo)
 	return &nodeResources{
 		topo:                           topo,
@@ -98,6 +115,7 @@ func NewResourcesAggregatorFromData(topo *ghw.TopologyInfo, resp *podresourcesap
 		perNUMAAllocatable:             makeNodeAllocatable(allDevs, resp.GetMemory()),
 		reservedCPUIDPerNUMA:           makeReservedCPUMap(topo.Nodes, allDevs),
 		memoryResourcesCapacityPerNUMA: memoryResourceCapacity,
+		memoryTierPerNUMA:              memoryTiers,
 		excludeList:                    excludeList,
 	}
 }
@@ -105,7 +123,8 @@ func NewResourcesAggregatorFromData(topo *ghw.TopologyInfo, resp *podresourcesap
 // Aggregate provides the mapping (numa zone name) -> Zone from the given PodResources.
 func (noderesourceData *nodeResources) Aggregate(podResData []PodResources) topologyv1alpha2.ZoneList {
 	perNuma := make(map[int]map[corev1.ResourceName]*resourceData)
-	for nodeID := range noderesourceData.topo.Nodes {
+	for _, node := range noderesourceData.topo.Nodes {
+		nodeID := node.ID
 		nodeRes, ok := noderesourceData.perNUMAAllocatable[nodeID]
 		if ok {
 			perNuma[nodeID] = make(map[corev1.ResourceName]*resourceData)
@@ -145,13 +164,23 @@ func (noderesourceData *nodeResources) Aggregate(podResData []PodResources) topo
 			}
 			// NUMA node doesn't have any allocatable resources, but yet it exists in the topology
 			// thus all its CPUs are reserved
-		} else {
+		} else if len(node.Cores) > 0 {
 			perNuma[nodeID] = make(map[corev1.ResourceName]*resourceData)
 			perNuma[nodeID]["cpu"] = &resourceData{
 				allocatable: int64(0),
 				available:   int64(0),
 				capacity:    int64(len(noderesourceData.reservedCPUIDPerNUMA[nodeID])),
 			}
+			// CPU-less NUMA node (e.g. a CXL memory expander) without allocatable resources,
+			// only advertise the capacity of its memory
+		} else {
+			perNuma[nodeID] = make(map[corev1.ResourceName]*resourceData)
+			for resName, capacity := range noderesourceData.memoryResourcesCapacityPerNUMA[nodeID] {
+				if noderesourceData.excludeList.IsExcluded(resName) {
+					continue
+				}
+				perNuma[nodeID][resName] = &resourceData{capacity: capacity}
+			}
 		}
 	}
 
@@ -175,6 +204,9 @@ func (noderesourceData *nodeResources) Aggregate(podResData []PodResources) topo
 			Type:      "Node",
 			Resources: make(topologyv1alpha2.ResourceInfoList, 0),
 		}
+		if attrs := noderesourceData.makeZoneAttributes(nodeID); len(attrs) > 0 {
+			zone.Attributes = attrs
+		}
 
 		costs, err := makeCostsPerNumaNode(noderesourceData.topo.Nodes, nodeID)
 		if err != nil {
@@ -194,8 +226,11 @@ func (noderesourceData *nodeResources) Aggregate(podResData []PodResources) topo
 				Capacity:    capacityQty,
 			})
 		}
+		sort.Slice(zone.Resources, func(i, j int) bool { return zone.Resources[i].Name < zone.Resources[j].Name })
 		zones = append(zones, zone)
 	}
+	// Keep the order stable so that unchanged zones compare equal
+	sort.Slice(zones, func(i, j int) bool { return zones[i].Name < zones[j].Name })
 	return zones
 }
 
@@ -260,6 +295,30 @@ func (noderesourceData *nodeResources) updateAvailable(numaData map[int]map[core
 	}
 }
 
+// makeZoneAttributes returns the attributes describing the type of a NUMA
+// zone. CPU-less and memory-less NUMA nodes are marked with the nodeType
+// attribute so that they can be told apart from regular NUMA nodes. The
+// memory tier of the node is advertised if the kernel supports memory tiering.
+func (noderesourceData *nodeResources) makeZoneAttributes(nodeID int) topologyv1alpha2.AttributeList {
+	var attrs topologyv1alpha2.AttributeList
+
+	node := findNodeByID(noderesourceData.topo.Nodes, nodeID)
+	if node != nil {
+		memCapacity, hasMemInfo := noderesourceData.memoryResourcesCapacityPerNUMA[nodeID]
+		switch {
+		case len(node.Cores) == 0:
+			attrs = append(attrs, topologyv1alpha2.AttributeInfo{Name: zoneAttributeNodeType, Value: nodeTypeMemoryOnly})
+		case hasMemInfo && memCapacity[corev1.ResourceMemory] == 0:
+			attrs = append(attrs, topologyv1alpha2.AttributeInfo{Name: zoneAttributeNodeType, Value: nodeTypeCPUOnly})
+		}
+	}
+
+	if tier, ok := noderesourceData.memoryTierPerNUMA[nodeID]; ok {
+		attrs = append(attrs, topologyv1alpha2.AttributeInfo{Name: zoneAttributeMemoryTier, Value: strconv.Itoa(tier)})
+	}
+	return attrs
+}
+
 // makeZoneName returns the canonical name of a NUMA zone from its ID.
 func makeZoneName(nodeID int) string {
 	return fmt.Sprintf("node-%d", nodeID)
@@ -350,9 +409,21 @@ func makeCostsPerNumaNode(nodes []*ghw.TopologyNode, nodeIDSrc int) ([]topologyv
 	if nodeSrc == nil {
 		return nil, fmt.Errorf("unknown node: %d", nodeIDSrc)
 	}
+	// The distance vector contains the distances to all (online) NUMA
+	// nodes, in the order of the node ID. Node IDs are not necessarily
+	// contiguous, e.g. with hot-plugged CXL memory.
+	nodeIDs := make([]int, 0, len(nodes))
+	for _, node := range nodes {
+		nodeIDs = append(nodeIDs, node.ID)
+	}
+	sort.Ints(nodeIDs)
+
 	nodeCosts := make([]topologyv1alpha2.CostInfo, 0)
-	for nodeIDDst, dist := range nodeSrc.Distances {
-		// TODO: this assumes there are no holes (= no offline node) in the distance vector
+	for i, dist := range nodeSrc.Distances {
+		nodeIDDst := i
+		if len(nodeSrc.Distances) == len(nodeIDs) {
+			nodeIDDst = nodeIDs[i]
+		}
 		nodeCosts = append(nodeCosts, topologyv1alpha2.CostInfo{
 			Name:  makeZoneName(nodeIDDst),
 			Value: int64(dist),
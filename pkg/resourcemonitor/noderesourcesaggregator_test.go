@@ -178,7 +178,7 @@ func TestResourcesAggregator(t *testing.T) {
 				corev1.ResourceName("hugepages-2Mi"): 2048,
 			},
 		}
-		resAggr = NewResourcesAggregatorFromData(&fakeTopo, availRes, memoryResourcesCapacity, NewExcludeResourceList(map[string][]string{}, ""))
+		resAggr = NewResourcesAggregatorFromData(&fakeTopo, availRes, memoryResourcesCapacity, nil, NewExcludeResourceList(map[string][]string{}, ""))
 
 		Convey("When aggregating resources", func() {
 			expected := topologyv1alpha2.ZoneList{
@@ -376,7 +376,7 @@ func TestResourcesAggregator(t *testing.T) {
 			},
 		}
 
-		resAggr = NewResourcesAggregatorFromData(&fakeTopo, availRes, memoryResourcesCapacity, NewExcludeResourceList(map[string][]string{}, ""))
+		resAggr = NewResourcesAggregatorFromData(&fakeTopo, availRes, memoryResourcesCapacity, nil, NewExcludeResourceList(map[string][]string{}, ""))
 
 		Convey("When aggregating resources", func() {
 			podRes := []PodResources{
@@ -517,6 +517,57 @@ func TestResourcesAggregator(t *testing.T) {
 
 }
 
+func TestResourcesAggregatorMemoryOnlyNodes(t *testing.T) {
+	Convey("When aggregating resources of a node with a CPU-less NUMA node", t, func() {
+		// NUMA node 3 is a CPU-less CXL memory expander, there is no node 2
+		topo := ghw.TopologyInfo{}
+		err := json.Unmarshal([]byte(`{"nodes": [
+			{"id": 0, "cores": [{"id": 0, "logical_processors": [0, 2]}], "distances": [10, 20, 30]},
+			{"id": 1, "cores": [{"id": 1, "logical_processors": [1, 3]}], "distances": [20, 10, 30]},
+			{"id": 3, "cores": [], "distances": [30, 30, 10]}
+		]}`), &topo)
+		So(err, ShouldBeNil)
+
+		availRes := &v1.AllocatableResourcesResponse{
+			CpuIds: []int64{0, 1, 2, 3},
+		}
+		memoryCapacity := utils.NumaMemoryResources{
+			0: {corev1.ResourceMemory: 2048},
+			1: {corev1.ResourceMemory: 2048},
+			3: {corev1.ResourceMemory: 8192},
+		}
+		memoryTiers := map[int]int{0: 4, 1: 4, 3: 22}
+		resAggr := NewResourcesAggregatorFromData(&topo, availRes, memoryCapacity, memoryTiers, NewExcludeResourceList(map[string][]string{}, ""))
+
+		zones := resAggr.Aggregate(nil)
+		So(zones, ShouldHaveLength, 3)
+
+		So(zones[0].Name, ShouldEqual, "node-0")
+		So(zones[0].Attributes, ShouldResemble, topologyv1alpha2.AttributeList{{Name: "memoryTier", Value: "4"}})
+		So(zones[0].Costs, ShouldResemble, topologyv1alpha2.CostList{
+			{Name: "node-0", Value: 10},
+			{Name: "node-1", Value: 20},
+			{Name: "node-3", Value: 30},
+		})
+		So(zones[0].Resources, ShouldHaveLength, 1)
+		So(zones[0].Resources[0].Name, ShouldEqual, "cpu")
+
+		So(zones[2].Name, ShouldEqual, "node-3")
+		So(zones[2].Attributes, ShouldResemble, topologyv1alpha2.AttributeList{
+			{Name: "nodeType", Value: "memory-only"},
+			{Name: "memoryTier", Value: "22"},
+		})
+		So(zones[2].Resources, ShouldResemble, topologyv1alpha2.ResourceInfoList{
+			{
+				Name:        "memory",
+				Capacity:    *resource.NewQuantity(8192, resource.DecimalSI),
+				Allocatable: *resource.NewQuantity(0, resource.DecimalSI),
+				Available:   *resource.NewQuantity(0, resource.DecimalSI),
+			},
+		})
+	})
+}
+
 // ghwc topology -f json
 var testTopology = `{
     "nodes": [
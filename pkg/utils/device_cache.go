@@ -0,0 +1,100 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"sync"
+)
+
+// DeviceCache caches device information read from sysfs across discovery
+// cycles. Entries are keyed by the sysfs path of the device and validated
+// against a fingerprint of the device (e.g. the modalias) so that a different
+// device appearing at the same path is detected. Entries of devices not looked
+// up between two Prune calls are dropped.
+type DeviceCache[T any] struct {
+	mu      sync.Mutex
+	entries map[string]deviceCacheEntry[T]
+	used    map[string]struct{}
+}
+
+type deviceCacheEntry[T any] struct {
+	fingerprint string
+	value       T
+}
+
+// NewDeviceCache returns a new empty DeviceCache.
+func NewDeviceCache[T any]() *DeviceCache[T] {
+	return &DeviceCache[T]{
+		entries: make(map[string]deviceCacheEntry[T]),
+		used:    make(map[string]struct{}),
+	}
+}
+
+// Get returns the cached value of a device. The value is only returned if the
+// fingerprint matches the one stored with the value.
+func (c *DeviceCache[T]) Get(path, fingerprint string) (T, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.used[path] = struct{}{}
+	e, ok := c.entries[path]
+	if !ok || e.fingerprint != fingerprint {
+		var zero T
+		return zero, false
+	}
+	return e.value, true
+}
+
+// Set stores the value of a device in the cache.
+func (c *DeviceCache[T]) Set(path, fingerprint string, value T) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.used[path] = struct{}{}
+	c.entries[path] = deviceCacheEntry[T]{fingerprint: fingerprint, value: value}
+}
+
+// Prune drops the entries of devices that have not been looked up since the
+// previous call to Prune, i.e. devices that have been removed.
+func (c *DeviceCache[T]) Prune() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for path := range c.entries {
+		if _, ok := c.used[path]; !ok {
+			delete(c.entries, path)
+		}
+	}
+	c.used = make(map[string]struct{})
+}
+
+// Invalidate drops all entries from the cache.
+func (c *DeviceCache[T]) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = make(map[string]deviceCacheEntry[T])
+	c.used = make(map[string]struct{})
+}
+
+// Len returns the number of entries in the cache.
+func (c *DeviceCache[T]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.entries)
+}
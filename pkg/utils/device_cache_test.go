@@ -0,0 +1,53 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"testing"
+)
+
+func TestDeviceCache(t *testing.T) {
+	c := NewDeviceCache[string]()
+
+	if _, ok := c.Get("dev0", "a"); ok {
+		t.Fatal("unexpected cache hit in an empty cache")
+	}
+	c.Set("dev0", "a", "value0")
+	c.Set("dev1", "b", "value1")
+	c.Prune()
+	if c.Len() != 2 {
+		t.Errorf("expected 2 entries after pruning, got %d", c.Len())
+	}
+
+	if v, ok := c.Get("dev0", "a"); !ok || v != "value0" {
+		t.Errorf("expected cache hit with value0, got %q (%v)", v, ok)
+	}
+	if _, ok := c.Get("dev0", "changed"); ok {
+		t.Error("unexpected cache hit with a changed fingerprint")
+	}
+
+	// Only dev0 was looked up in this cycle
+	c.Prune()
+	if c.Len() != 1 {
+		t.Errorf("expected 1 entry after pruning, got %d", c.Len())
+	}
+
+	c.Invalidate()
+	if c.Len() != 0 {
+		t.Errorf("expected empty cache after invalidation, got %d entries", c.Len())
+	}
+}
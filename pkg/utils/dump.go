@@ -17,7 +17,13 @@ limitations under the License.
 package utils
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
 
 	"sigs.k8s.io/yaml"
 )
@@ -46,3 +52,130 @@ func Dump(obj interface{}) string {
 	}
 	return string(out)
 }
+
+// DumpWriter writes dumps of objects into files in a directory, keeping only
+// a limited number of the most recent dumps.
+type DumpWriter struct {
+	// Dir is the directory where dump files are written.
+	Dir string
+	// Prefix is the file name prefix of the dump files.
+	Prefix string
+	// MaxFiles is the maximum number of dump files to keep. Zero or
+	// negative value means no limit.
+	MaxFiles int
+	// MaxSize is the maximum size of one dump file in bytes. Zero or
+	// negative value means no limit.
+	MaxSize int64
+	// Format is the format of the dump files written with Write, either
+	// "yaml" or "json". YAML is used if empty.
+	Format string
+}
+
+// ErrDumpTooLarge is returned if the dump exceeds the size limit.
+var ErrDumpTooLarge = errors.New("dump exceeds the size limit")
+
+// Write dumps an object into a new file in YAML or JSON format, and, removes
+// the oldest dump files exceeding the maximum count. The path of the written
+// file is returned.
+func (w *DumpWriter) Write(obj interface{}) (string, error) {
+	var (
+		data []byte
+		err  error
+	)
+	switch w.Format {
+	case "", "yaml":
+		data, err = yaml.Marshal(obj)
+	case "json":
+		data, err = json.MarshalIndent(obj, "", "  ")
+		data = append(data, '\n')
+	default:
+		return "", fmt.Errorf("unsupported dump format %q", w.Format)
+	}
+	if err != nil {
+		return "", err
+	}
+	ext := w.Format
+	if ext == "" {
+		ext = "yaml"
+	}
+	return w.WriteData(data, ext)
+}
+
+// WriteData writes already encoded data into a new dump file with the given
+// file name extension, and, removes the oldest dump files exceeding the
+// maximum count. The path of the written file is returned.
+func (w *DumpWriter) WriteData(data []byte, ext string) (string, error) {
+	if w.MaxSize > 0 && int64(len(data)) > w.MaxSize {
+		return "", fmt.Errorf("%w (%d > %d bytes)", ErrDumpTooLarge, len(data), w.MaxSize)
+	}
+
+	if err := os.MkdirAll(w.Dir, 0755); err != nil {
+		return "", err
+	}
+
+	// Write into a temporary file first so that readers never see partial dumps
+	name := filepath.Join(w.Dir, w.Prefix+time.Now().UTC().Format("20060102T150405.000000000Z")+"."+ext)
+	if err := WriteFileAtomic(name, data, 0644); err != nil {
+		return "", err
+	}
+
+	return name, w.rotate()
+}
+
+// WriteFileAtomic writes data into a file, replacing the file atomically so
+// that readers never see partially written content. The data is first
+// written into a temporary file in the same directory which is then renamed.
+func WriteFileAtomic(name string, data []byte, perm os.FileMode) error {
+	dir, base := filepath.Split(name)
+	if dir == "" {
+		dir = "."
+	}
+	tmp, err := os.CreateTemp(dir, ".tmp-"+base)
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Chmod(perm); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), name); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
+
+// rotate removes the oldest dump files exceeding MaxFiles.
+func (w *DumpWriter) rotate() error {
+	if w.MaxFiles <= 0 {
+		return nil
+	}
+
+	// Dumps of all formats are rotated together
+	files, err := filepath.Glob(filepath.Join(w.Dir, w.Prefix+"*"))
+	if err != nil {
+		return err
+	}
+	if len(files) <= w.MaxFiles {
+		return nil
+	}
+
+	// File names contain a timestamp so lexical order is chronological
+	sort.Strings(files)
+	for _, f := range files[:len(files)-w.MaxFiles] {
+		if err := os.Remove(f); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDumpWriter(t *testing.T) {
+	dir := t.TempDir()
+	w := DumpWriter{Dir: dir, Prefix: "test-", MaxFiles: 2, MaxSize: 64}
+
+	var written []string
+	for i := 0; i < 4; i++ {
+		name, err := w.Write(map[string]int{"i": i})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		written = append(written, name)
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "test-*.yaml"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(files) != 2 {
+		t.Fatalf("expected 2 dump files, got %d", len(files))
+	}
+	if files[0] != written[2] || files[1] != written[3] {
+		t.Errorf("expected the most recent dumps to be kept, got %v", files)
+	}
+
+	data, err := os.ReadFile(written[3])
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(data) != "i: 3\n" {
+		t.Errorf("unexpected dump content %q", string(data))
+	}
+
+	_, err = w.Write(map[string]string{"data": string(make([]byte, 100))})
+	if !errors.Is(err, ErrDumpTooLarge) {
+		t.Errorf("expected ErrDumpTooLarge, got %v", err)
+	}
+}
+
+func TestDumpWriterFormats(t *testing.T) {
+	dir := t.TempDir()
+	w := DumpWriter{Dir: dir, Prefix: "test-", MaxFiles: 2, Format: "json"}
+
+	name, err := w.Write(map[string]int{"i": 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if filepath.Ext(name) != ".json" {
+		t.Errorf("expected a .json file, got %q", name)
+	}
+	data, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(data) != "{\n  \"i\": 1\n}\n" {
+		t.Errorf("unexpected dump content %q", string(data))
+	}
+
+	// Dumps of different formats are rotated together
+	if _, err := w.WriteData([]byte("a,b\n"), "csv"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := w.WriteData([]byte("a,b\n"), "csv"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "test-*"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(files) != 2 || filepath.Ext(files[0]) != ".csv" {
+		t.Errorf("expected 2 csv dump files, got %v", files)
+	}
+
+	w.Format = "xml"
+	if _, err := w.Write(map[string]int{"i": 1}); err == nil {
+		t.Errorf("expected an error for an unsupported format")
+	}
+}
+
+func TestWriteFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "test.json")
+
+	for _, content := range []string{"first", "second"} {
+		if err := WriteFileAtomic(name, []byte(content), 0640); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		data, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if string(data) != content {
+			t.Errorf("expected %q, got %q", content, data)
+		}
+	}
+
+	info, err := os.Stat(name)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if info.Mode().Perm() != 0640 {
+		t.Errorf("expected mode 0640, got %v", info.Mode().Perm())
+	}
+	// No temporary files should be left behind
+	if files, _ := os.ReadDir(dir); len(files) != 1 {
+		t.Errorf("expected exactly one file, got %d", len(files))
+	}
+
+	if err := WriteFileAtomic(filepath.Join(dir, "non-existent", "test.json"), nil, 0644); err == nil {
+		t.Errorf("expected an error when the directory does not exist")
+	}
+}
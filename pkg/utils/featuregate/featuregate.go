@@ -129,6 +129,16 @@ func NewFeatureGate() *featureGate {
 // Set parses a string of the form "key1=value1,key2=value2,..." into a
 // map[string]bool of known keys or returns an error.
 func (f *featureGate) Set(value string) error {
+	m, err := Parse(value)
+	if err != nil {
+		return err
+	}
+	return f.SetFromMap(m)
+}
+
+// Parse parses a string of the form "key1=value1,key2=value2,..." into a
+// map[string]bool. The keys are not validated.
+func Parse(value string) (map[string]bool, error) {
 	m := make(map[string]bool)
 	for _, s := range strings.Split(value, ",") {
 		if len(s) == 0 {
@@ -137,16 +147,16 @@ func (f *featureGate) Set(value string) error {
 		arr := strings.SplitN(s, "=", 2)
 		k := strings.TrimSpace(arr[0])
 		if len(arr) != 2 {
-			return fmt.Errorf("missing bool value for %s", k)
+			return nil, fmt.Errorf("missing bool value for %s", k)
 		}
 		v := strings.TrimSpace(arr[1])
 		boolValue, err := strconv.ParseBool(v)
 		if err != nil {
-			return fmt.Errorf("invalid value of %s=%s, err: %v", k, v, err)
+			return nil, fmt.Errorf("invalid value of %s=%s, err: %v", k, v, err)
 		}
 		m[k] = boolValue
 	}
-	return f.SetFromMap(m)
+	return m, nil
 }
 
 // SetFromMap stores flag gates for known features from a map[string]bool or returns an error
@@ -34,6 +34,8 @@ var (
 	VarDir = HostDir(pathPrefix + "var")
 	// LibDir is where the /lib directory of the system to be inspected is located
 	LibDir = HostDir(pathPrefix + "lib")
+	// RunDir is where the /run directory of the system to be inspected is located
+	RunDir = HostDir(pathPrefix + "run")
 	// ProcDir is where the /proc directory of the system to be inspected is located
 	ProcDir = HostDir(pathPrefix + "proc")
 )
@@ -0,0 +1,176 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"k8s.io/klog/v2"
+)
+
+// Endpoint paths served by HTTPServer.
+const (
+	MetricsPath = "/metrics"
+	HealthzPath = "/healthz"
+	ReadyzPath  = "/readyz"
+)
+
+// httpServerShutdownTimeout is the maximum time to wait for active
+// connections to finish when stopping the server.
+var httpServerShutdownTimeout = 5 * time.Second
+
+// HTTPServer is the HTTP server shared by the NFD daemons. It serves
+// Prometheus metrics at MetricsPath, a liveness check at HealthzPath and a
+// readiness check at ReadyzPath. The readiness check fails until SetReady is
+// called.
+type HTTPServer struct {
+	srv      *http.Server
+	mux      *http.ServeMux
+	certFile string
+	keyFile  string
+	ready    atomic.Bool
+}
+
+// HTTPServerOption is an option for NewHTTPServer.
+type HTTPServerOption func(*HTTPServer)
+
+// WithMetrics registers the given collectors to be served at MetricsPath.
+func WithMetrics(cs ...prometheus.Collector) HTTPServerOption {
+	return func(s *HTTPServer) {
+		r := prometheus.NewRegistry()
+		r.MustRegister(cs...)
+		s.mux.Handle(MetricsPath, promhttp.HandlerFor(r, promhttp.HandlerOpts{}))
+	}
+}
+
+// WithTLS makes the server serve HTTPS using the given certificate and key
+// files. TLS is not enabled if either of the files is empty.
+func WithTLS(certFile, keyFile string) HTTPServerOption {
+	return func(s *HTTPServer) {
+		s.certFile = certFile
+		s.keyFile = keyFile
+	}
+}
+
+// WithHost makes the server listen only on the given host address (e.g.
+// "localhost") instead of all interfaces.
+func WithHost(host string) HTTPServerOption {
+	return func(s *HTTPServer) {
+		_, port, _ := net.SplitHostPort(s.srv.Addr)
+		s.srv.Addr = net.JoinHostPort(host, port)
+	}
+}
+
+// NewHTTPServer creates a new HTTP server listening on the given port.
+func NewHTTPServer(port int, opts ...HTTPServerOption) *HTTPServer {
+	s := &HTTPServer{mux: http.NewServeMux()}
+	s.srv = &http.Server{Addr: fmt.Sprintf(":%d", port), Handler: s.mux}
+
+	s.mux.HandleFunc(HealthzPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "ok")
+	})
+	s.mux.HandleFunc(ReadyzPath, func(w http.ResponseWriter, r *http.Request) {
+		if !s.ready.Load() {
+			http.Error(w, "not ready", http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprintln(w, "ok")
+	})
+
+	for _, o := range opts {
+		o(s)
+	}
+	return s
+}
+
+// Handle registers an additional handler for the given pattern.
+func (s *HTTPServer) Handle(pattern string, handler http.Handler) {
+	s.mux.Handle(pattern, handler)
+}
+
+// SetReady sets the state reported by the readiness check.
+func (s *HTTPServer) SetReady(ready bool) {
+	s.ready.Store(ready)
+}
+
+// TLSEnabled returns true if the server serves HTTPS.
+func (s *HTTPServer) TLSEnabled() bool {
+	return s.certFile != "" && s.keyFile != ""
+}
+
+// Run runs the server. It blocks until the server is stopped.
+func (s *HTTPServer) Run() {
+	lis, err := net.Listen("tcp", s.srv.Addr)
+	if err != nil {
+		klog.ErrorS(err, "failed to start http server", "address", s.srv.Addr)
+		return
+	}
+	s.Serve(lis)
+}
+
+// Serve runs the server on the given listener. It blocks until the server is
+// stopped.
+func (s *HTTPServer) Serve(lis net.Listener) {
+	klog.InfoS("http server starting", "address", lis.Addr().String(), "tls", s.TLSEnabled())
+	var err error
+	if s.TLSEnabled() {
+		err = s.srv.ServeTLS(lis, s.certFile, s.keyFile)
+	} else {
+		err = s.srv.Serve(lis)
+	}
+	if errors.Is(err, http.ErrServerClosed) {
+		err = nil
+	}
+	klog.InfoS("http server stopped", "address", lis.Addr().String(), "error", err)
+}
+
+// Stop gracefully stops the server, waiting for active connections to finish.
+func (s *HTTPServer) Stop() {
+	klog.InfoS("stopping http server", "address", s.srv.Addr)
+	s.ready.Store(false)
+	ctx, cancel := context.WithTimeout(context.Background(), httpServerShutdownTimeout)
+	defer cancel()
+	if err := s.srv.Shutdown(ctx); err != nil {
+		klog.ErrorS(err, "failed to gracefully stop http server", "address", s.srv.Addr)
+		s.srv.Close()
+	}
+}
+
+// StopOnSignal calls stop when the process receives SIGINT or SIGTERM. A
+// second signal terminates the process immediately.
+func StopOnSignal(stop func()) {
+	ch := make(chan os.Signal, 2)
+	signal.Notify(ch, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		sig := <-ch
+		klog.InfoS("received signal, shutting down", "signal", sig.String())
+		stop()
+		<-ch
+		os.Exit(1)
+	}()
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestHTTPServer(t *testing.T) {
+	counter := prometheus.NewCounter(prometheus.CounterOpts{Name: "test_counter", Help: "Test counter."})
+	s := NewHTTPServer(0, WithMetrics(counter))
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	done := make(chan struct{})
+	go func() {
+		s.Serve(lis)
+		close(done)
+	}()
+
+	get := func(path string) (int, string) {
+		resp, err := http.Get("http://" + lis.Addr().String() + path)
+		if err != nil {
+			t.Fatalf("request to %s failed: %v", path, err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(body)
+	}
+
+	if code, _ := get(HealthzPath); code != http.StatusOK {
+		t.Errorf("unexpected liveness status %d", code)
+	}
+	if code, _ := get(ReadyzPath); code != http.StatusServiceUnavailable {
+		t.Errorf("unexpected readiness status %d before ready", code)
+	}
+	s.SetReady(true)
+	if code, _ := get(ReadyzPath); code != http.StatusOK {
+		t.Errorf("unexpected readiness status %d after ready", code)
+	}
+	if code, body := get(MetricsPath); code != http.StatusOK || !strings.Contains(body, "test_counter") {
+		t.Errorf("unexpected metrics response %d: %q", code, body)
+	}
+
+	s.Stop()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("server did not stop")
+	}
+}
+
+func TestHTTPServerWithHost(t *testing.T) {
+	s := NewHTTPServer(8080, WithHost("localhost"))
+	if s.srv.Addr != "localhost:8080" {
+		t.Errorf("unexpected address %q", s.srv.Addr)
+	}
+}
@@ -28,7 +28,21 @@ type JsonPatch struct {
 	Value string `json:"value,omitempty"`
 }
 
+// jsonPointerEscaper escapes a key for use in a JSON pointer (RFC 6901).
+var jsonPointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// jsonPointerUnescaper reverts jsonPointerEscaper. The replacements are done
+// in one pass so that e.g. "~01" becomes "~1" and not "/" (RFC 6901).
+var jsonPointerUnescaper = strings.NewReplacer("~1", "/", "~0", "~")
+
 // NewJsonPatch returns a new JsonPatch object
 func NewJsonPatch(verb string, jsonpath string, key string, value string) JsonPatch {
-	return JsonPatch{verb, path.Join(jsonpath, strings.ReplaceAll(key, "/", "~1")), value}
+	return JsonPatch{verb, path.Join(jsonpath, jsonPointerEscaper.Replace(key)), value}
+}
+
+// UnescapeJsonPointer returns the key escaped in a reference token of a JSON
+// pointer, i.e. the last element of the path of a patch created with
+// NewJsonPatch.
+func UnescapeJsonPointer(token string) string {
+	return jsonPointerUnescaper.Replace(token)
 }
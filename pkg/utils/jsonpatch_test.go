@@ -0,0 +1,38 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestJsonPointerEscaping(t *testing.T) {
+	for _, key := range []string{"foo", "example.com/foo", "a~b", "a~1b/c", "~0~1", "~"} {
+		p := NewJsonPatch("add", "/metadata/labels", key, "")
+		token := strings.TrimPrefix(p.Path, "/metadata/labels/")
+		if strings.Contains(token, "/") {
+			t.Errorf("key %q not escaped: %q", key, token)
+		}
+		if got := UnescapeJsonPointer(token); got != key {
+			t.Errorf("unescaping %q: got %q, want %q", token, got, key)
+		}
+	}
+	if got := UnescapeJsonPointer("~01"); got != "~1" {
+		t.Errorf("unescaping %q: got %q, want %q", "~01", got, "~1")
+	}
+}
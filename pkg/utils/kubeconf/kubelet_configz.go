@@ -60,6 +60,14 @@ func GetKubeletConfiguration(restConfig *rest.Config) (*kubeletconfigv1beta1.Kub
 
 // InsecureConfig returns a kubelet API config object which uses the token path.
 func InsecureConfig(host, tokenFile string) (*rest.Config, error) {
+	return KubeletAPIConfig(host, tokenFile, "", true)
+}
+
+// KubeletAPIConfig returns a kubelet API config object which uses the token
+// path. The kubelet serving certificate is verified against the CA bundle in
+// caFile if specified, otherwise against the system trust store unless
+// insecure is set.
+func KubeletAPIConfig(host, tokenFile, caFile string, insecure bool) (*rest.Config, error) {
 	if tokenFile == "" {
 		return nil, fmt.Errorf("api auth token file must be defined")
 	}
@@ -72,7 +80,10 @@ func InsecureConfig(host, tokenFile string) (*rest.Config, error) {
 		return nil, err
 	}
 
-	tlsClientConfig := rest.TLSClientConfig{Insecure: true}
+	tlsClientConfig := rest.TLSClientConfig{Insecure: insecure}
+	if caFile != "" {
+		tlsClientConfig = rest.TLSClientConfig{CAFile: caFile}
+	}
 
 	return &rest.Config{
 		Host:            host,
@@ -28,12 +28,14 @@ import (
 	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/klog/v2"
 	resourcehelper "k8s.io/kubernetes/pkg/apis/core/helper"
+	"k8s.io/utils/cpuset"
 
 	"sigs.k8s.io/node-feature-discovery/pkg/utils/hostpath"
 )
 
 var (
-	sysBusNodeBasepath = hostpath.SysfsDir.Path("bus/node/devices")
+	sysBusNodeBasepath     = hostpath.SysfsDir.Path("bus/node/devices")
+	sysMemoryTiersBasepath = hostpath.SysfsDir.Path("devices/virtual/memory_tiering")
 )
 
 // NumaMemoryResources contains information of the memory resources per NUMA
@@ -68,13 +70,11 @@ func GetNumaMemoryResources() (NumaMemoryResources, error) {
 		info[corev1.ResourceMemory] = nodeTotalMemory
 
 		// Get hugepages
+		// Get hugepages. Memory-only nodes (e.g. CXL memory expanders) might
+		// not support hugepages, still report their memory.
 		hugepageBytes, err := getHugepagesBytes(filepath.Join(sysBusNodeBasepath, numaNode, "hugepages"))
-		if err != nil {
-			if os.IsNotExist(err) {
-				continue
-			} else {
-				return nil, err
-			}
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
 		}
 		maps.Copy(info, hugepageBytes)
 
@@ -84,6 +84,44 @@ func GetNumaMemoryResources() (NumaMemoryResources, error) {
 	return memoryResources, nil
 }
 
+// GetNumaMemoryTiers returns the memory tier of each NUMA node, as mapping
+// (NUMA node ID) -> (memory tier ID). Lower tier IDs are faster. An empty map
+// is returned if the kernel does not support memory tiering.
+func GetNumaMemoryTiers() (map[int]int, error) {
+	tiers := make(map[int]int)
+	entries, err := os.ReadDir(sysMemoryTiersBasepath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return tiers, nil
+		}
+		return nil, err
+	}
+
+	for _, e := range entries {
+		if !strings.HasPrefix(e.Name(), "memory_tier") {
+			continue
+		}
+		tierID, err := strconv.Atoi(strings.TrimPrefix(e.Name(), "memory_tier"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse memory tier ID of %q", e.Name())
+		}
+
+		data, err := os.ReadFile(filepath.Join(sysMemoryTiersBasepath, e.Name(), "nodelist"))
+		if err != nil {
+			return nil, err
+		}
+		nodes, err := cpuset.Parse(strings.TrimSpace(string(data)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse node list of memory tier %d: %w", tierID, err)
+		}
+		for _, nodeID := range nodes.List() {
+			tiers[nodeID] = tierID
+		}
+	}
+
+	return tiers, nil
+}
+
 func getHugepagesBytes(path string) (MemoryResourceInfo, error) {
 	entries, err := os.ReadDir(path)
 	if err != nil {
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 
 	corev1 "k8s.io/api/core/v1"
@@ -117,6 +118,38 @@ func TestGetMemoryResourceCounters(t *testing.T) {
 	}
 }
 
+func TestGetNumaMemoryTiers(t *testing.T) {
+	rootDir := t.TempDir()
+	sysMemoryTiersBasepath = filepath.Join(rootDir, "memory_tiering")
+
+	tiers, err := GetNumaMemoryTiers()
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if len(tiers) != 0 {
+		t.Errorf("expected no memory tiers without memory tiering support, got %v", tiers)
+	}
+
+	for tier, nodes := range map[string]string{"memory_tier4": "0-1", "memory_tier22": "2"} {
+		path := filepath.Join(sysMemoryTiersBasepath, tier)
+		if err := os.MkdirAll(path, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(path, "nodelist"), []byte(nodes+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tiers, err = GetNumaMemoryTiers()
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	expected := map[int]int{0: 4, 1: 4, 2: 22}
+	if !reflect.DeepEqual(tiers, expected) {
+		t.Errorf("unexpected memory tiers: got %v, expected %v", tiers, expected)
+	}
+}
+
 func makeMemoryTree(root string, numNodes int) error {
 	for idx := 0; idx < numNodes; idx++ {
 		path := filepath.Join(
@@ -0,0 +1,138 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// Relationship types of the logical processor information, see
+// LOGICAL_PROCESSOR_RELATIONSHIP in the Windows API.
+const (
+	RelationProcessorCore    = 0
+	RelationNumaNode         = 1
+	RelationProcessorPackage = 3
+	RelationAll              = 0xffff
+)
+
+// LtpPcSmt is the processor core flag indicating that the core has more than
+// one logical processor.
+const LtpPcSmt = 0x1
+
+var procGetLogicalProcessorInformationEx = windows.NewLazySystemDLL("kernel32.dll").NewProc("GetLogicalProcessorInformationEx")
+
+// LogicalProcessorInfo describes one processor core, processor package or
+// NUMA node of the system.
+type LogicalProcessorInfo struct {
+	// Relationship is the type of the entry.
+	Relationship uint32
+	// Flags of a processor core or package.
+	Flags byte
+	// NodeNumber is the number of a NUMA node.
+	NodeNumber uint32
+	// LogicalProcessors is the number of logical processors in the entry.
+	LogicalProcessors int
+}
+
+// groupAffinity corresponds to GROUP_AFFINITY of the Windows API.
+type groupAffinity struct {
+	Mask     uintptr
+	Group    uint16
+	Reserved [3]uint16
+}
+
+// logicalProcessorInformationEx corresponds to the header of
+// SYSTEM_LOGICAL_PROCESSOR_INFORMATION_EX of the Windows API.
+type logicalProcessorInformationEx struct {
+	Relationship uint32
+	Size         uint32
+}
+
+// processorRelationship corresponds to PROCESSOR_RELATIONSHIP of the Windows
+// API.
+type processorRelationship struct {
+	Flags           byte
+	EfficiencyClass byte
+	Reserved        [20]byte
+	GroupCount      uint16
+	GroupMask       [1]groupAffinity
+}
+
+// numaNodeRelationship corresponds to NUMA_NODE_RELATIONSHIP of the Windows
+// API.
+type numaNodeRelationship struct {
+	NodeNumber uint32
+	Reserved   [18]byte
+	GroupCount uint16
+	GroupMask  [1]groupAffinity
+}
+
+// GetLogicalProcessorInformation returns information about the processor
+// cores, processor packages and NUMA nodes of the system.
+func GetLogicalProcessorInformation(relationship uint32) ([]LogicalProcessorInfo, error) {
+	var size uint32
+	r, _, err := procGetLogicalProcessorInformationEx.Call(uintptr(relationship), 0, uintptr(unsafe.Pointer(&size)))
+	if r != 0 || !errors.Is(err, windows.ERROR_INSUFFICIENT_BUFFER) {
+		return nil, fmt.Errorf("failed to get logical processor information size: %w", err)
+	}
+
+	buf := make([]byte, size)
+	r, _, err = procGetLogicalProcessorInformationEx.Call(uintptr(relationship), uintptr(unsafe.Pointer(&buf[0])), uintptr(unsafe.Pointer(&size)))
+	if r == 0 {
+		return nil, fmt.Errorf("failed to get logical processor information: %w", err)
+	}
+
+	infos := []LogicalProcessorInfo{}
+	hdrSize := unsafe.Sizeof(logicalProcessorInformationEx{})
+	for off := uint32(0); off < size; {
+		hdr := (*logicalProcessorInformationEx)(unsafe.Pointer(&buf[off]))
+		info := LogicalProcessorInfo{Relationship: hdr.Relationship}
+		data := unsafe.Pointer(uintptr(unsafe.Pointer(hdr)) + hdrSize)
+
+		switch hdr.Relationship {
+		case RelationProcessorCore, RelationProcessorPackage:
+			p := (*processorRelationship)(data)
+			info.Flags = p.Flags
+			info.LogicalProcessors = countProcessors(unsafe.Slice(&p.GroupMask[0], p.GroupCount))
+			infos = append(infos, info)
+		case RelationNumaNode:
+			n := (*numaNodeRelationship)(data)
+			info.NodeNumber = n.NodeNumber
+			// GroupCount is zero on older Windows versions that only
+			// support one group per NUMA node
+			info.LogicalProcessors = countProcessors(unsafe.Slice(&n.GroupMask[0], max(n.GroupCount, 1)))
+			infos = append(infos, info)
+		}
+		off += hdr.Size
+	}
+	return infos, nil
+}
+
+// countProcessors returns the number of logical processors in a list of
+// group affinities.
+func countProcessors(groups []groupAffinity) int {
+	n := 0
+	for _, g := range groups {
+		n += bits.OnesCount64(uint64(g.Mask))
+	}
+	return n
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/component-base/tracing"
+	tracingapi "k8s.io/component-base/tracing/api/v1"
+	"k8s.io/klog/v2"
+)
+
+// traceParentKey is the W3C trace context key holding the parent span.
+const traceParentKey = "traceparent"
+
+// TracingArgs contains the command line options for OpenTelemetry tracing.
+type TracingArgs struct {
+	// Endpoint is the OTLP gRPC endpoint of the collector. Tracing is
+	// disabled if empty.
+	Endpoint string
+	// SamplingRatePerMillion is the number of traces sampled per million.
+	SamplingRatePerMillion int
+}
+
+// SetupTracing initializes the global OpenTelemetry tracer provider of a
+// component, exporting spans to the configured OTLP endpoint. The returned
+// function flushes and shuts down the tracer provider. Tracing is a no-op if
+// no endpoint has been configured.
+func SetupTracing(ctx context.Context, component string, args TracingArgs) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(tracing.Propagators())
+	if args.Endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+	if args.SamplingRatePerMillion < 0 || args.SamplingRatePerMillion > 1000000 {
+		return nil, fmt.Errorf("invalid tracing sampling rate %d, must be between 0 and 1000000", args.SamplingRatePerMillion)
+	}
+
+	rate := int32(args.SamplingRatePerMillion)
+	tp, err := tracing.NewProvider(ctx,
+		&tracingapi.TracingConfiguration{Endpoint: &args.Endpoint, SamplingRatePerMillion: &rate},
+		nil,
+		[]resource.Option{resource.WithAttributes(semconv.ServiceName(component), semconv.K8SNodeName(NodeName()))})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tracer provider: %w", err)
+	}
+	otel.SetTracerProvider(tp)
+	klog.InfoS("OpenTelemetry tracing enabled", "endpoint", args.Endpoint, "samplingRatePerMillion", rate)
+
+	return tp.Shutdown, nil
+}
+
+// StartSpan starts a new span using the global tracer provider.
+func StartSpan(ctx context.Context, tracer, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return otel.Tracer(tracer).Start(ctx, name, opts...)
+}
+
+// WithTracing instruments the transport of a Kubernetes client config so
+// that requests are traced and the trace context is propagated to the API
+// server.
+func WithTracing(config *restclient.Config) *restclient.Config {
+	config.Wrap(tracing.WrapperFor(otel.GetTracerProvider()))
+	return config
+}
+
+// InjectTraceParent returns the W3C traceparent of the span in ctx, for
+// propagating the trace context through an annotation of an API object. An
+// empty string is returned if ctx does not contain a sampled span.
+func InjectTraceParent(ctx context.Context) string {
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+	return carrier[traceParentKey]
+}
+
+// ExtractTraceParent returns a context with the remote span specified by a
+// W3C traceparent, as returned by InjectTraceParent.
+func ExtractTraceParent(ctx context.Context, traceParent string) context.Context {
+	if traceParent == "" {
+		return ctx
+	}
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier{traceParentKey: traceParent})
+}
@@ -0,0 +1,45 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"context"
+	"testing"
+)
+
+func TestTraceParent(t *testing.T) {
+	if _, err := SetupTracing(context.Background(), "test", TracingArgs{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := SetupTracing(context.Background(), "test", TracingArgs{Endpoint: "localhost:4317", SamplingRatePerMillion: 1000001}); err == nil {
+		t.Errorf("expected an error for invalid sampling rate")
+	}
+
+	if tp := InjectTraceParent(context.Background()); tp != "" {
+		t.Errorf("expected empty traceparent without a span, got %q", tp)
+	}
+
+	const traceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	ctx := ExtractTraceParent(context.Background(), traceParent)
+	if tp := InjectTraceParent(ctx); tp != traceParent {
+		t.Errorf("expected traceparent %q, got %q", traceParent, tp)
+	}
+
+	if ctx := ExtractTraceParent(context.Background(), ""); InjectTraceParent(ctx) != "" {
+		t.Errorf("expected no trace context from an empty traceparent")
+	}
+}
@@ -0,0 +1,106 @@
+//go:build amd64
+// +build amd64
+
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cpu
+
+import (
+	"bufio"
+	"os"
+	"strconv"
+	"strings"
+
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/node-feature-discovery/pkg/utils/hostpath"
+)
+
+// procSelfMaps is the memory map of the current process, used for detecting
+// the vsyscall page. The mapping is global so the maps of nfd-worker itself
+// reflect the vsyscall mode of the host.
+var procSelfMaps = "/proc/self/maps"
+
+// discoverCompat detects the availability of legacy and compatibility
+// interfaces that old binaries may rely on.
+func discoverCompat() map[string]string {
+	attrs := make(map[string]string)
+
+	if mode, err := vsyscallMode(); err != nil {
+		klog.ErrorS(err, "failed to detect vsyscall mode")
+	} else {
+		attrs["vsyscall"] = mode
+	}
+
+	// The abi.vsyscall32 sysctl only exists if the kernel was built with
+	// 32-bit (IA32) emulation support
+	ia32 := false
+	if data, err := os.ReadFile(hostpath.ProcDir.Path("sys/abi/vsyscall32")); err == nil {
+		ia32 = true
+		attrs["vdso32"] = strconv.FormatBool(strings.TrimSpace(string(data)) != "0")
+	}
+	// IA32 emulation may be disabled on the kernel command line (Linux v6.7+)
+	if v, ok := kernelCmdlineParam("ia32_emulation"); ok {
+		if b, err := strconv.ParseBool(v); err == nil {
+			ia32 = ia32 && b
+		}
+	}
+	attrs["ia32_emulation"] = strconv.FormatBool(ia32)
+
+	return attrs
+}
+
+// vsyscallMode returns the mode of the legacy vsyscall page, one of
+// "emulate", "xonly" or "none".
+func vsyscallMode() (string, error) {
+	f, err := os.Open(procSelfMaps)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 6 || fields[len(fields)-1] != "[vsyscall]" {
+			continue
+		}
+		// Readable in emulate mode, execute-only in xonly mode
+		if strings.HasPrefix(fields[1], "r") {
+			return "emulate", nil
+		}
+		return "xonly", nil
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	return "none", nil
+}
+
+// kernelCmdlineParam returns the value of a kernel command line parameter.
+func kernelCmdlineParam(name string) (string, bool) {
+	data, err := os.ReadFile(hostpath.ProcDir.Path("cmdline"))
+	if err != nil {
+		return "", false
+	}
+	for _, p := range strings.Fields(string(data)) {
+		if k, v, _ := strings.Cut(p, "="); k == name {
+			return v, true
+		}
+	}
+	return "", false
+}
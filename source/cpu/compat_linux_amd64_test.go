@@ -0,0 +1,56 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cpu
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"sigs.k8s.io/node-feature-discovery/pkg/utils/hostpath"
+)
+
+func TestDiscoverCompat(t *testing.T) {
+	root := t.TempDir()
+	origProcDir, origProcSelfMaps := hostpath.ProcDir, procSelfMaps
+	hostpath.ProcDir = hostpath.HostDir(root)
+	procSelfMaps = filepath.Join(root, "maps")
+	defer func() { hostpath.ProcDir, procSelfMaps = origProcDir, origProcSelfMaps }()
+
+	writeFile := func(p, content string) {
+		assert.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(root, p)), 0755))
+		assert.NoError(t, os.WriteFile(filepath.Join(root, p), []byte(content), 0644))
+	}
+	const maps = "7ffc3a9e1000-7ffc3a9e3000 r-xp 00000000 00:00 0                          [vdso]\n"
+
+	// No vsyscall page, no IA32 emulation
+	writeFile("maps", maps)
+	writeFile("cmdline", "BOOT_IMAGE=/vmlinuz root=/dev/sda1 vsyscall=none\n")
+	assert.Equal(t, map[string]string{"vsyscall": "none", "ia32_emulation": "false"}, discoverCompat())
+
+	// Execute-only vsyscall page, IA32 emulation enabled
+	writeFile("maps", maps+"ffffffffff600000-ffffffffff601000 --xp 00000000 00:00 0                  [vsyscall]\n")
+	writeFile("sys/abi/vsyscall32", "1\n")
+	assert.Equal(t, map[string]string{"vsyscall": "xonly", "ia32_emulation": "true", "vdso32": "true"}, discoverCompat())
+
+	// Emulated vsyscall page, IA32 emulation disabled on the command line
+	writeFile("maps", maps+"ffffffffff600000-ffffffffff601000 r-xp 00000000 00:00 0                  [vsyscall]\n")
+	writeFile("cmdline", "root=/dev/sda1 ia32_emulation=false\n")
+	assert.Equal(t, map[string]string{"vsyscall": "emulate", "ia32_emulation": "false", "vdso32": "true"}, discoverCompat())
+}
@@ -18,9 +18,7 @@ package cpu
 
 import (
 	"fmt"
-	"os"
 	"strconv"
-	"strings"
 
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/klog/v2"
@@ -46,6 +44,8 @@ const (
 	SstFeature         = "sst"
 	TopologyFeature    = "topology"
 	CoprocessorFeature = "coprocessor"
+	XstateFeature      = "xstate"
+	CompatFeature      = "compat"
 )
 
 // Configuration file options
@@ -115,6 +115,7 @@ var (
 	_   source.FeatureSource      = &src
 	_   source.LabelSource        = &src
 	_   source.ConfigurableSource = &src
+	_   source.HostPathSource     = &src
 )
 
 func (s *cpuSource) Name() string { return Name }
@@ -139,6 +140,14 @@ func (s *cpuSource) SetConfig(conf source.Config) {
 // Priority method of the LabelSource interface
 func (s *cpuSource) Priority() int { return 0 }
 
+// RequiredHostPaths method of the HostPathSource interface
+func (s *cpuSource) RequiredHostPaths() []string {
+	return []string{
+		hostpath.SysfsDir.Path("bus/cpu/devices"),
+		hostpath.SysfsDir.Path("devices/system/cpu"),
+	}
+}
+
 // GetLabels method of the LabelSource interface
 func (s *cpuSource) GetLabels() (source.FeatureLabels, error) {
 	labels := source.FeatureLabels{}
@@ -175,6 +184,9 @@ func (s *cpuSource) GetLabels() (source.FeatureLabels, error) {
 	skipLabel := sets.NewString(
 		"tdx.total_keys",
 		"sgx.epc",
+		"sgx.epc_sections",
+		"sgx.max_enclave_size",
+		"sev.snp.vmpls",
 		"sev.encrypted_state_ids",
 		"sev.asids")
 	for k, v := range features.Attributes[SecurityFeature].Elements {
@@ -244,7 +256,13 @@ func (s *cpuSource) Discover() error {
 	// Detect Coprocessor features
 	s.features.Attributes[CoprocessorFeature] = nfdv1alpha1.NewAttributeFeatures(discoverCoprocessor())
 
-	klog.V(3).InfoS("discovered features", "featureSource", s.Name(), "features", utils.DelayedDumper(s.features))
+	// Detect OS-enabled extended register states (AVX-512, AMX)
+	s.features.Attributes[XstateFeature] = nfdv1alpha1.NewAttributeFeatures(discoverXstate())
+
+	// Detect legacy compatibility interfaces (vsyscall, IA32 emulation)
+	s.features.Attributes[CompatFeature] = nfdv1alpha1.NewAttributeFeatures(discoverCompat())
+
+	source.LogDiscoveredFeatures(s.Name(), "features", utils.DelayedDumper(s.features))
 
 	return nil
 }
@@ -266,49 +284,6 @@ func getCPUModel() map[string]string {
 	return cpuModelInfo
 }
 
-func discoverTopology() map[string]string {
-	features := make(map[string]string)
-
-	files, err := os.ReadDir(hostpath.SysfsDir.Path("bus/cpu/devices"))
-	if err != nil {
-		klog.ErrorS(err, "failed to read devices folder")
-		return features
-	}
-
-	ht := false
-	uniquePhysicalIDs := sets.NewString()
-
-	for _, file := range files {
-		// Try to read siblings from topology
-		siblings, err := os.ReadFile(hostpath.SysfsDir.Path("bus/cpu/devices", file.Name(), "topology/thread_siblings_list"))
-		if err != nil {
-			klog.ErrorS(err, "error while reading thread_sigblings_list file")
-			return map[string]string{}
-		}
-		for _, char := range siblings {
-			// If list separator found, we determine that there are multiple siblings
-			if char == ',' || char == '-' {
-				ht = true
-				break
-			}
-		}
-
-		// Try to read physical_package_id from topology
-		physicalID, err := os.ReadFile(hostpath.SysfsDir.Path("bus/cpu/devices", file.Name(), "topology/physical_package_id"))
-		if err != nil {
-			klog.ErrorS(err, "error while reading physical_package_id file")
-			return map[string]string{}
-		}
-		id := strings.TrimSpace(string(physicalID))
-		uniquePhysicalIDs.Insert(id)
-	}
-
-	features["hardware_multithreading"] = strconv.FormatBool(ht)
-	features["socket_count"] = strconv.FormatInt(int64(uniquePhysicalIDs.Len()), 10)
-
-	return features
-}
-
 func (s *cpuSource) initCpuidFilter() {
 	newFilter := keyFilter{keys: map[string]struct{}{}}
 	if len(s.config.Cpuid.AttributeWhitelist) > 0 {
@@ -22,6 +22,7 @@ package cpu
 import (
 	"bufio"
 	"io"
+	"maps"
 	"os"
 	"strconv"
 	"strings"
@@ -42,6 +43,7 @@ func discoverSecurity() map[string]string {
 	if epcSize := sgxEnabled(); epcSize > 0 {
 		elems["sgx.enabled"] = "true"
 		elems["sgx.epc"] = strconv.FormatUint(uint64(epcSize), 10)
+		maps.Copy(elems, sgxAttributes(&cpuid.CPU.SGX))
 	}
 
 	if tdxEnabled() {
@@ -61,6 +63,11 @@ func discoverSecurity() map[string]string {
 		elems["sev.enabled"] = "true"
 
 		sevAddressSpaceIdentifiers := getCgroupMiscCapacity("sev")
+		if sevAddressSpaceIdentifiers < 0 {
+			// Fall back to the ASID ranges reported by the CPU if the misc
+			// cgroup controller is not available
+			sevAddressSpaceIdentifiers, _ = sevASIDs(&cpuid.CPU.AMDMemEncryption)
+		}
 		if sevAddressSpaceIdentifiers > -1 {
 			elems["sev.asids"] = strconv.FormatInt(int64(sevAddressSpaceIdentifiers), 10)
 		}
@@ -70,6 +77,9 @@ func discoverSecurity() map[string]string {
 		elems["sev.es.enabled"] = "true"
 
 		sevEncryptedStateIDs := getCgroupMiscCapacity("sev_es")
+		if sevEncryptedStateIDs < 0 {
+			_, sevEncryptedStateIDs = sevASIDs(&cpuid.CPU.AMDMemEncryption)
+		}
 		if sevEncryptedStateIDs > -1 {
 			elems["sev.encrypted_state_ids"] = strconv.FormatInt(int64(sevEncryptedStateIDs), 10)
 		}
@@ -77,6 +87,10 @@ func discoverSecurity() map[string]string {
 
 	if sevParameterEnabled("sev_snp") {
 		elems["sev.snp.enabled"] = "true"
+
+		if cpuid.CPU.AMDMemEncryption.Available {
+			elems["sev.snp.vmpls"] = strconv.FormatUint(uint64(cpuid.CPU.AMDMemEncryption.NumVMPL), 10)
+		}
 	}
 
 	return elems
@@ -93,6 +107,27 @@ func sgxEnabled() uint64 {
 	return epcSize
 }
 
+// sgxAttributes returns the details of the SGX capabilities of the CPU.
+func sgxAttributes(sgx *cpuid.SGXSupport) map[string]string {
+	return map[string]string{
+		"sgx.sgx2":             strconv.FormatBool(sgx.SGX2Supported),
+		"sgx.flc":              strconv.FormatBool(sgx.LaunchControl),
+		"sgx.epc_sections":     strconv.Itoa(len(sgx.EPCSections)),
+		"sgx.max_enclave_size": strconv.FormatInt(sgx.MaxEnclaveSize64, 10),
+	}
+}
+
+// sevASIDs returns the number of address space identifiers (ASIDs) available
+// for SEV guests and for SEV-ES (and SEV-SNP) guests, based on CPUID. ASIDs
+// below the minimum SEV ASID are reserved for SEV-ES guests. Returns -1 if
+// the information is not available.
+func sevASIDs(mem *cpuid.AMDMemEncryptionSupport) (sev, sevES int64) {
+	if !mem.Available || mem.NumEntryptedGuests == 0 || mem.MinSevNoEsAsid == 0 || mem.MinSevNoEsAsid > mem.NumEntryptedGuests+1 {
+		return -1, -1
+	}
+	return int64(mem.NumEntryptedGuests) - int64(mem.MinSevNoEsAsid) + 1, int64(mem.MinSevNoEsAsid) - 1
+}
+
 func tdxEnabled() bool {
 	// If /sys/module/kvm_intel/parameters/tdx is not present, or is present
 	// with a value different than "Y\n" assume TDX to be unavailable or
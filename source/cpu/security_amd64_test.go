@@ -0,0 +1,65 @@
+//go:build amd64
+// +build amd64
+
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cpu
+
+import (
+	"testing"
+
+	"github.com/klauspost/cpuid/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSgxAttributes(t *testing.T) {
+	sgx := &cpuid.SGXSupport{
+		Available:        true,
+		LaunchControl:    true,
+		SGX1Supported:    true,
+		SGX2Supported:    true,
+		MaxEnclaveSize64: 1 << 36,
+		EPCSections:      []cpuid.SGXEPCSection{{EPCSize: 1 << 30}, {EPCSize: 1 << 30}},
+	}
+	assert.Equal(t, map[string]string{
+		"sgx.sgx2":             "true",
+		"sgx.flc":              "true",
+		"sgx.epc_sections":     "2",
+		"sgx.max_enclave_size": "68719476736",
+	}, sgxAttributes(sgx))
+}
+
+func TestSevASIDs(t *testing.T) {
+	tcs := []struct {
+		name  string
+		mem   cpuid.AMDMemEncryptionSupport
+		sev   int64
+		sevES int64
+	}{
+		{name: "not available", mem: cpuid.AMDMemEncryptionSupport{NumEntryptedGuests: 509, MinSevNoEsAsid: 100}, sev: -1, sevES: -1},
+		{name: "sev and sev-es", mem: cpuid.AMDMemEncryptionSupport{Available: true, NumEntryptedGuests: 509, MinSevNoEsAsid: 100}, sev: 410, sevES: 99},
+		{name: "sev-es only", mem: cpuid.AMDMemEncryptionSupport{Available: true, NumEntryptedGuests: 509, MinSevNoEsAsid: 510}, sev: 0, sevES: 509},
+		{name: "invalid", mem: cpuid.AMDMemEncryptionSupport{Available: true, NumEntryptedGuests: 509, MinSevNoEsAsid: 600}, sev: -1, sevES: -1},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			sev, sevES := sevASIDs(&tc.mem)
+			assert.Equal(t, tc.sev, sev)
+			assert.Equal(t, tc.sevES, sevES)
+		})
+	}
+}
@@ -0,0 +1,115 @@
+//go:build !windows
+// +build !windows
+
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cpu
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/cpuset"
+
+	"sigs.k8s.io/node-feature-discovery/pkg/utils/hostpath"
+)
+
+func discoverTopology() map[string]string {
+	features := make(map[string]string)
+
+	files, err := os.ReadDir(hostpath.SysfsDir.Path("bus/cpu/devices"))
+	if err != nil {
+		klog.ErrorS(err, "failed to read devices folder")
+		return features
+	}
+
+	ht := false
+	threadsPerCore := 0
+	uniquePhysicalIDs := sets.NewString()
+
+	for _, file := range files {
+		// Try to read siblings from topology
+		siblings, err := os.ReadFile(hostpath.SysfsDir.Path("bus/cpu/devices", file.Name(), "topology/thread_siblings_list"))
+		if err != nil {
+			klog.ErrorS(err, "error while reading thread_sigblings_list file")
+			return map[string]string{}
+		}
+		for _, char := range siblings {
+			// If list separator found, we determine that there are multiple siblings
+			if char == ',' || char == '-' {
+				ht = true
+				break
+			}
+		}
+		if cpus, err := cpuset.Parse(strings.TrimSpace(string(siblings))); err != nil {
+			klog.ErrorS(err, "failed to parse thread_siblings_list", "cpu", file.Name())
+		} else if cpus.Size() > threadsPerCore {
+			threadsPerCore = cpus.Size()
+		}
+
+		// Try to read physical_package_id from topology
+		physicalID, err := os.ReadFile(hostpath.SysfsDir.Path("bus/cpu/devices", file.Name(), "topology/physical_package_id"))
+		if err != nil {
+			klog.ErrorS(err, "error while reading physical_package_id file")
+			return map[string]string{}
+		}
+		id := strings.TrimSpace(string(physicalID))
+		uniquePhysicalIDs.Insert(id)
+	}
+
+	features["hardware_multithreading"] = strconv.FormatBool(ht)
+	features["socket_count"] = strconv.FormatInt(int64(uniquePhysicalIDs.Len()), 10)
+	if threadsPerCore > 0 {
+		features["threads_per_core"] = strconv.Itoa(threadsPerCore)
+	}
+
+	for k, v := range discoverSMT() {
+		features[k] = v
+	}
+
+	return features
+}
+
+// discoverSMT detects the SMT (simultaneous multithreading) state from the
+// kernel SMT control interface. The interface is not available on all
+// architectures and kernel versions in which case no attributes are returned.
+func discoverSMT() map[string]string {
+	features := make(map[string]string)
+
+	control, err := os.ReadFile(hostpath.SysfsDir.Path("devices/system/cpu/smt/control"))
+	if err != nil {
+		klog.V(3).InfoS("SMT control interface not available", "error", err)
+		return features
+	}
+	c := strings.TrimSpace(string(control))
+	features["smt_control"] = c
+	// SMT can be switched at runtime only if it is not permanently disabled
+	// (forceoff) and is supported by the hardware and the kernel
+	features["smt_runtime_control"] = strconv.FormatBool(c == "on" || c == "off")
+
+	active, err := os.ReadFile(hostpath.SysfsDir.Path("devices/system/cpu/smt/active"))
+	if err != nil {
+		klog.ErrorS(err, "failed to read SMT active state")
+		return features
+	}
+	features["smt_enabled"] = strconv.FormatBool(strings.TrimSpace(string(active)) == "1")
+
+	return features
+}
@@ -0,0 +1,65 @@
+//go:build !windows
+// +build !windows
+
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cpu
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"sigs.k8s.io/node-feature-discovery/pkg/utils/hostpath"
+)
+
+func TestDiscoverTopology(t *testing.T) {
+	root := t.TempDir()
+	origSysfsDir := hostpath.SysfsDir
+	hostpath.SysfsDir = hostpath.HostDir(root)
+	defer func() { hostpath.SysfsDir = origSysfsDir }()
+
+	writeFile := func(p, content string) {
+		assert.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(root, p)), 0755))
+		assert.NoError(t, os.WriteFile(filepath.Join(root, p), []byte(content), 0644))
+	}
+
+	// Two cores with two threads each in one socket
+	for cpu, siblings := range map[string]string{"cpu0": "0,2", "cpu1": "1,3", "cpu2": "0,2", "cpu3": "1,3"} {
+		writeFile(filepath.Join("bus/cpu/devices", cpu, "topology/thread_siblings_list"), siblings+"\n")
+		writeFile(filepath.Join("bus/cpu/devices", cpu, "topology/physical_package_id"), "0\n")
+	}
+
+	// No SMT control interface
+	assert.Equal(t, map[string]string{
+		"hardware_multithreading": "true",
+		"socket_count":            "1",
+		"threads_per_core":        "2",
+	}, discoverTopology())
+
+	// SMT enabled and controllable at runtime
+	writeFile("devices/system/cpu/smt/control", "on\n")
+	writeFile("devices/system/cpu/smt/active", "1\n")
+	assert.Equal(t, map[string]string{"smt_control": "on", "smt_runtime_control": "true", "smt_enabled": "true"}, discoverSMT())
+
+	// SMT permanently disabled
+	writeFile("devices/system/cpu/smt/control", "forceoff\n")
+	writeFile("devices/system/cpu/smt/active", "0\n")
+	assert.Equal(t, map[string]string{"smt_control": "forceoff", "smt_runtime_control": "false", "smt_enabled": "false"}, discoverSMT())
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cpu
+
+import (
+	"strconv"
+
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/node-feature-discovery/pkg/utils"
+)
+
+// discoverTopology detects the CPU topology from the logical processor
+// information of the Windows API.
+func discoverTopology() map[string]string {
+	features := make(map[string]string)
+
+	infos, err := utils.GetLogicalProcessorInformation(utils.RelationAll)
+	if err != nil {
+		klog.ErrorS(err, "failed to get processor topology")
+		return features
+	}
+
+	ht := false
+	threadsPerCore := 0
+	sockets := 0
+	for _, info := range infos {
+		switch info.Relationship {
+		case utils.RelationProcessorCore:
+			if info.Flags&utils.LtpPcSmt != 0 {
+				ht = true
+			}
+			threadsPerCore = max(threadsPerCore, info.LogicalProcessors)
+		case utils.RelationProcessorPackage:
+			sockets++
+		}
+	}
+
+	features["hardware_multithreading"] = strconv.FormatBool(ht)
+	features["socket_count"] = strconv.Itoa(sockets)
+	if threadsPerCore > 0 {
+		features["threads_per_core"] = strconv.Itoa(threadsPerCore)
+	}
+
+	return features
+}
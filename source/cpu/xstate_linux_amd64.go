@@ -0,0 +1,81 @@
+//go:build amd64
+// +build amd64
+
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cpu
+
+import (
+	"strconv"
+
+	"sigs.k8s.io/node-feature-discovery/pkg/cpuid"
+)
+
+const (
+	// CPUID EAX input values
+	LEAF_FEATURE_INFO = 0x01
+
+	// CPUID bitmasks
+	FEATURE_INFO_ECX_OSXSAVE      = 1 << 27
+	EXT_FEATURE_FLAGS_EBX_AVX512F = 1 << 16
+	EXT_FEATURE_FLAGS_EDX_AMXBF16 = 1 << 22
+	EXT_FEATURE_FLAGS_EDX_AMXTILE = 1 << 24
+	EXT_FEATURE_FLAGS_EDX_AMXINT8 = 1 << 25
+
+	// XCR0 state components
+	XCR0_SSE       = 1 << 1
+	XCR0_AVX       = 1 << 2
+	XCR0_OPMASK    = 1 << 5
+	XCR0_ZMM_HI256 = 1 << 6
+	XCR0_HI16_ZMM  = 1 << 7
+	XCR0_XTILECFG  = 1 << 17
+	XCR0_XTILEDATA = 1 << 18
+
+	xcr0AVX    = XCR0_SSE | XCR0_AVX
+	xcr0AVX512 = xcr0AVX | XCR0_OPMASK | XCR0_ZMM_HI256 | XCR0_HI16_ZMM
+	xcr0AMX    = XCR0_XTILECFG | XCR0_XTILEDATA
+)
+
+// discoverXstate detects whether the extended register states needed by
+// AVX-512 and AMX are supported by the hardware and enabled by the OS (in
+// XCR0).
+func discoverXstate() map[string]string {
+	attrs := make(map[string]string)
+
+	featureInfo := cpuid.Cpuid(LEAF_FEATURE_INFO, 0)
+	extFeatures := cpuid.Cpuid(LEAF_EXT_FEATURE_FLAGS, 0)
+
+	avx512Supported := extFeatures.EBX&EXT_FEATURE_FLAGS_EBX_AVX512F != 0
+	amxSupported := extFeatures.EDX&(EXT_FEATURE_FLAGS_EDX_AMXBF16|EXT_FEATURE_FLAGS_EDX_AMXTILE|EXT_FEATURE_FLAGS_EDX_AMXINT8) != 0
+
+	osxsave := featureInfo.ECX&FEATURE_INFO_ECX_OSXSAVE != 0
+	var xcr0 uint64
+	if osxsave {
+		eax, edx := cpuid.Xgetbv(0)
+		xcr0 = uint64(edx)<<32 | uint64(eax)
+	}
+
+	attrs["osxsave"] = strconv.FormatBool(osxsave)
+	attrs["xcr0"] = "0x" + strconv.FormatUint(xcr0, 16)
+	attrs["avx_enabled"] = strconv.FormatBool(xcr0&xcr0AVX == xcr0AVX)
+	attrs["avx512_supported"] = strconv.FormatBool(avx512Supported)
+	attrs["avx512_enabled"] = strconv.FormatBool(avx512Supported && xcr0&xcr0AVX512 == xcr0AVX512)
+	attrs["amx_supported"] = strconv.FormatBool(amxSupported)
+	attrs["amx_enabled"] = strconv.FormatBool(amxSupported && xcr0&xcr0AMX == xcr0AMX)
+
+	return attrs
+}
@@ -55,6 +55,7 @@ var (
 		rules:  []nfdv1alpha1.Rule{},
 	}
 	_ source.LabelSource        = &src
+	_ source.RuleSource         = &src
 	_ source.ConfigurableSource = &src
 )
 
@@ -88,8 +89,7 @@ func (s *customSource) GetLabels() (source.FeatureLabels, error) {
 	features := source.GetAllFeatures()
 
 	labels := source.FeatureLabels{}
-	allFeatureConfig := append(getStaticRules(), s.rules...)
-	allFeatureConfig = append(allFeatureConfig, getDropinDirRules()...)
+	allFeatureConfig := s.GetRules()
 	klog.V(2).InfoS("resolving custom features", "configuration", utils.DelayedDumper(allFeatureConfig))
 	// Iterate over features
 	for _, rule := range allFeatureConfig {
@@ -110,6 +110,12 @@ func (s *customSource) GetLabels() (source.FeatureLabels, error) {
 	return labels, nil
 }
 
+// GetRules method of the RuleSource interface
+func (s *customSource) GetRules() []nfdv1alpha1.Rule {
+	rules := append(getStaticRules(), s.rules...)
+	return append(rules, getDropinDirRules()...)
+}
+
 func convertInternalRulesToNfdApi(in *[]api.Rule) []nfdv1alpha1.Rule {
 	out := make([]nfdv1alpha1.Rule, len(*in))
 	for i := range *in {
@@ -0,0 +1,66 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Deprecation describes a deprecated feature, or a deprecated element of a
+// feature, that is planned to be removed in a future release.
+type Deprecation struct {
+	// Feature is the fully qualified name of the feature, i.e.
+	// <source>.<feature>.
+	Feature string
+	// Element is the name of the deprecated element of the feature. An empty
+	// value means that the whole feature is deprecated.
+	Element string
+	// Replacement is an optional hint of what to use instead.
+	Replacement string
+	// RemovedIn is the release in which the feature is planned to be
+	// removed, if known.
+	RemovedIn string
+}
+
+// deprecations contains all registered deprecations
+var deprecations []Deprecation
+
+// RegisterDeprecation marks a feature, or an element of a feature, as
+// deprecated. It is supposed to be called from the init() function of the
+// source, similar to Register.
+func RegisterDeprecation(d Deprecation) {
+	if strings.Count(d.Feature, ".") < 1 {
+		panic(fmt.Sprintf("invalid deprecated feature name %q, must be in the form <source>.<feature>", d.Feature))
+	}
+	deprecations = append(deprecations, d)
+}
+
+// GetDeprecations returns all registered deprecations, sorted by the feature
+// and element name.
+func GetDeprecations() []Deprecation {
+	ret := make([]Deprecation, len(deprecations))
+	copy(ret, deprecations)
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].Feature != ret[j].Feature {
+			return ret[i].Feature < ret[j].Feature
+		}
+		return ret[i].Element < ret[j].Element
+	})
+	return ret
+}
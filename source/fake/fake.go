@@ -19,7 +19,6 @@ package fake
 import (
 	"fmt"
 
-	"k8s.io/klog/v2"
 	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
 	"sigs.k8s.io/node-feature-discovery/pkg/utils"
 	"sigs.k8s.io/node-feature-discovery/source"
@@ -128,7 +127,7 @@ func (s *fakeSource) Discover() error {
 	}
 	s.features.Instances[InstanceFeature] = nfdv1alpha1.NewInstanceFeatures(instances...)
 
-	klog.V(3).InfoS("discovered features", "featureSource", s.Name(), "features", utils.DelayedDumper(s.features))
+	source.LogDiscoveredFeatures(s.Name(), "features", utils.DelayedDumper(s.features))
 
 	return nil
 }
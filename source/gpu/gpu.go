@@ -0,0 +1,193 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gpu
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"k8s.io/klog/v2"
+
+	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
+	"sigs.k8s.io/node-feature-discovery/pkg/utils"
+	"sigs.k8s.io/node-feature-discovery/pkg/utils/hostpath"
+	"sigs.k8s.io/node-feature-discovery/source"
+)
+
+// Name of this feature source
+const Name = "gpu"
+
+// DeviceFeature is the name of the feature set that holds all discovered
+// GPU devices.
+const DeviceFeature = "device"
+
+// gpuSource implements the FeatureSource and LabelSource interfaces.
+type gpuSource struct {
+	features *nfdv1alpha1.Features
+}
+
+// Singleton source instance
+var (
+	src gpuSource
+	_   source.FeatureSource  = &src
+	_   source.LabelSource    = &src
+	_   source.HostPathSource = &src
+)
+
+// drmCardRe matches the DRM card devices, skipping the connectors
+// (e.g. card0-DP-1) of the cards.
+var drmCardRe = regexp.MustCompile(`^card[0-9]+$`)
+
+// Name returns an identifier string for this feature source.
+func (s *gpuSource) Name() string { return Name }
+
+// Priority method of the LabelSource interface
+func (s *gpuSource) Priority() int { return 0 }
+
+// RequiredHostPaths method of the HostPathSource interface
+func (s *gpuSource) RequiredHostPaths() []string {
+	return []string{hostpath.SysfsDir.Path("class/drm")}
+}
+
+// GetLabels method of the LabelSource interface
+func (s *gpuSource) GetLabels() (source.FeatureLabels, error) {
+	labels := source.FeatureLabels{}
+	features := s.GetFeatures()
+
+	if len(features.Instances[DeviceFeature].Elements) > 0 {
+		labels["present"] = true
+	}
+
+	return labels, nil
+}
+
+// Discover method of the FeatureSource interface
+func (s *gpuSource) Discover() error {
+	s.features = nfdv1alpha1.NewFeatures()
+
+	devs, err := detectGpus()
+	if err != nil {
+		return fmt.Errorf("failed to detect GPU devices: %w", err)
+	}
+	s.features.Instances[DeviceFeature] = nfdv1alpha1.NewInstanceFeatures(devs...)
+
+	source.LogDiscoveredFeatures(s.Name(), "features", utils.DelayedDumper(s.features))
+
+	return nil
+}
+
+// GetFeatures method of the FeatureSource Interface.
+func (s *gpuSource) GetFeatures() *nfdv1alpha1.Features {
+	if s.features == nil {
+		s.features = nfdv1alpha1.NewFeatures()
+	}
+	return s.features
+}
+
+// detectGpus detects the GPUs registered in the DRM subsystem.
+func detectGpus() ([]nfdv1alpha1.InstanceFeature, error) {
+	sysfsBasePath := hostpath.SysfsDir.Path("class/drm")
+
+	entries, err := os.ReadDir(sysfsBasePath)
+	if os.IsNotExist(err) {
+		klog.V(1).InfoS("no DRM devices present")
+		return []nfdv1alpha1.InstanceFeature{}, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to list DRM devices: %w", err)
+	}
+
+	info := make([]nfdv1alpha1.InstanceFeature, 0)
+	for _, e := range entries {
+		if !drmCardRe.MatchString(e.Name()) {
+			continue
+		}
+		attrs, err := readGpuInfo(filepath.Join(sysfsBasePath, e.Name()))
+		if err != nil {
+			klog.ErrorS(err, "failed to read GPU device info", "device", e.Name())
+			continue
+		}
+		info = append(info, *nfdv1alpha1.NewInstanceFeature(attrs))
+	}
+
+	return info, nil
+}
+
+// readGpuInfo reads the attributes of one DRM card device.
+func readGpuInfo(cardPath string) (map[string]string, error) {
+	devPath := filepath.Join(cardPath, "device")
+
+	// The parent device (e.g. the PCI address) of the card
+	target, err := os.Readlink(devPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve parent device: %w", err)
+	}
+
+	attrs := map[string]string{
+		"name":    filepath.Base(cardPath),
+		"address": filepath.Base(target),
+	}
+
+	for _, name := range []string{"vendor", "device"} {
+		if val, err := readSysfsAttr(filepath.Join(devPath, name)); err != nil {
+			klog.V(3).ErrorS(err, "failed to read GPU device attribute", "path", cardPath, "attributeName", name)
+		} else {
+			attrs[name] = strings.TrimPrefix(val, "0x")
+		}
+	}
+
+	if target, err := os.Readlink(filepath.Join(devPath, "driver")); err == nil {
+		attrs["driver"] = filepath.Base(target)
+	}
+
+	// Dedicated video memory, only reported by some drivers (e.g. amdgpu)
+	if val, err := readSysfsAttr(filepath.Join(devPath, "mem_info_vram_total")); err == nil {
+		if n, err := strconv.ParseUint(val, 10, 64); err != nil {
+			klog.ErrorS(err, "failed to parse GPU VRAM size", "path", cardPath)
+		} else if n > 0 {
+			attrs["vram_mb"] = strconv.FormatUint(n>>20, 10)
+		}
+	}
+
+	// Render nodes of the card
+	renderNodes := 0
+	if entries, err := os.ReadDir(filepath.Join(devPath, "drm")); err == nil {
+		for _, e := range entries {
+			if strings.HasPrefix(e.Name(), "renderD") {
+				renderNodes++
+			}
+		}
+	}
+	attrs["render_nodes"] = strconv.Itoa(renderNodes)
+
+	return attrs, nil
+}
+
+func readSysfsAttr(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+func init() {
+	source.Register(&src)
+}
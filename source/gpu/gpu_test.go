@@ -0,0 +1,97 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gpu
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"sigs.k8s.io/node-feature-discovery/pkg/utils/hostpath"
+)
+
+func TestGpuSource(t *testing.T) {
+	assert.Equal(t, src.Name(), Name)
+
+	// Check that GetLabels works with empty features
+	src.features = nil
+	l, err := src.GetLabels()
+
+	assert.Nil(t, err, err)
+	assert.Empty(t, l)
+}
+
+func TestDetectGpus(t *testing.T) {
+	root := t.TempDir()
+	origSysfsDir := hostpath.SysfsDir
+	hostpath.SysfsDir = hostpath.HostDir(root)
+	defer func() { hostpath.SysfsDir = origSysfsDir }()
+
+	// No DRM devices
+	devs, err := detectGpus()
+	assert.NoError(t, err)
+	assert.Empty(t, devs)
+
+	writeFile := func(path, content string) {
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	addCard := func(card, addr, vendor, device, driver string) string {
+		devPath := filepath.Join(root, "devices/pci0000:00", addr)
+		writeFile(filepath.Join(devPath, "vendor"), vendor+"\n")
+		writeFile(filepath.Join(devPath, "device"), device+"\n")
+		assert.NoError(t, os.MkdirAll(filepath.Join(devPath, "drm", card), 0755))
+		assert.NoError(t, os.MkdirAll(filepath.Join(root, "bus/pci/drivers", driver), 0755))
+		assert.NoError(t, os.Symlink(filepath.Join("../../../bus/pci/drivers", driver), filepath.Join(devPath, "driver")))
+
+		cardPath := filepath.Join(root, "class/drm", card)
+		assert.NoError(t, os.MkdirAll(cardPath, 0755))
+		assert.NoError(t, os.Symlink(devPath, filepath.Join(cardPath, "device")))
+		return devPath
+	}
+
+	amd := addCard("card0", "0000:03:00.0", "0x1002", "0x744c", "amdgpu")
+	writeFile(filepath.Join(amd, "mem_info_vram_total"), "25753026560\n")
+	assert.NoError(t, os.MkdirAll(filepath.Join(amd, "drm/renderD128"), 0755))
+	addCard("card1", "0000:00:02.0", "0x8086", "0xa780", "i915")
+	// Connectors and other entries must be ignored
+	assert.NoError(t, os.MkdirAll(filepath.Join(root, "class/drm/card0-DP-1"), 0755))
+	writeFile(filepath.Join(root, "class/drm/version"), "drm 1.1.0\n")
+
+	devs, err = detectGpus()
+	assert.NoError(t, err)
+	assert.Len(t, devs, 2)
+	assert.Equal(t, map[string]string{
+		"name":         "card0",
+		"address":      "0000:03:00.0",
+		"vendor":       "1002",
+		"device":       "744c",
+		"driver":       "amdgpu",
+		"vram_mb":      "24560",
+		"render_nodes": "1",
+	}, devs[0].Attributes)
+	assert.Equal(t, map[string]string{
+		"name":         "card1",
+		"address":      "0000:00:02.0",
+		"vendor":       "8086",
+		"device":       "a780",
+		"driver":       "i915",
+		"render_nodes": "0",
+	}, devs[1].Attributes)
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kernel
+
+import (
+	"bufio"
+	"os"
+	"strconv"
+	"strings"
+
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/node-feature-discovery/pkg/utils/hostpath"
+)
+
+// cpuinfoPath is the path of the cpuinfo file. The file is not namespaced so
+// the host procfs is not needed.
+var cpuinfoPath = "/proc/cpuinfo"
+
+// discoverClocksource detects the kernel timekeeping properties: the current
+// and available clocksources, the timer interrupt frequency and the
+// reliability flags of the TSC (x86 only).
+func discoverClocksource(kconfig map[string]string) map[string]string {
+	attrs := make(map[string]string)
+
+	basePath := hostpath.SysfsDir.Path("devices/system/clocksource/clocksource0")
+	if data, err := os.ReadFile(basePath + "/current_clocksource"); err != nil {
+		klog.V(3).InfoS("failed to read current clocksource", "error", err)
+	} else {
+		attrs["current"] = strings.TrimSpace(string(data))
+	}
+	if data, err := os.ReadFile(basePath + "/available_clocksource"); err != nil {
+		klog.V(3).InfoS("failed to read available clocksources", "error", err)
+	} else {
+		attrs["available"] = strings.Join(strings.Fields(string(data)), ",")
+	}
+
+	if hz, ok := kconfig["HZ"]; ok {
+		attrs["timer_hz"] = hz
+	}
+
+	flags, err := getCPUFlags()
+	if err != nil {
+		klog.V(3).InfoS("failed to read cpu flags", "error", err)
+	} else if _, ok := flags["tsc"]; ok {
+		for _, f := range []string{"constant_tsc", "nonstop_tsc", "tsc_reliable"} {
+			_, ok := flags[f]
+			attrs[f] = strconv.FormatBool(ok)
+		}
+	}
+
+	return attrs
+}
+
+// getCPUFlags returns the CPU flags reported by the kernel for the first CPU.
+func getCPUFlags() (map[string]struct{}, error) {
+	f, err := os.Open(cpuinfoPath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	flags := make(map[string]struct{})
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok || strings.TrimSpace(key) != "flags" {
+			continue
+		}
+		for _, flag := range strings.Fields(value) {
+			flags[flag] = struct{}{}
+		}
+		break
+	}
+	return flags, scanner.Err()
+}
@@ -24,6 +24,7 @@ import (
 
 	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
 	"sigs.k8s.io/node-feature-discovery/pkg/utils"
+	"sigs.k8s.io/node-feature-discovery/pkg/utils/hostpath"
 	"sigs.k8s.io/node-feature-discovery/source"
 )
 
@@ -36,6 +37,10 @@ const (
 	SelinuxFeature       = "selinux"
 	VersionFeature       = "version"
 	EnabledModuleFeature = "enabledmodule"
+	ClocksourceFeature   = "clocksource"
+	LsmFeature           = "lsm"
+	BuiltinModuleFeature = "builtinmodule"
+	ModuleParamFeature   = "moduleparameter"
 )
 
 // Configuration file options
@@ -72,6 +77,7 @@ var (
 	_   source.FeatureSource      = &src
 	_   source.LabelSource        = &src
 	_   source.ConfigurableSource = &src
+	_   source.HostPathSource     = &src
 )
 
 func (s *kernelSource) Name() string { return Name }
@@ -95,6 +101,16 @@ func (s *kernelSource) SetConfig(conf source.Config) {
 // Priority method of the LabelSource interface
 func (s *kernelSource) Priority() int { return 0 }
 
+// RequiredHostPaths method of the HostPathSource interface
+func (s *kernelSource) RequiredHostPaths() []string {
+	return []string{
+		hostpath.BootDir.Path(),
+		hostpath.LibDir.Path("modules"),
+		hostpath.SysfsDir.Path("module"),
+		hostpath.SysfsDir.Path("devices/system/clocksource"),
+	}
+}
+
 // GetLabels method of the LabelSource interface
 func (s *kernelSource) GetLabels() (source.FeatureLabels, error) {
 	labels := source.FeatureLabels{}
@@ -129,7 +145,8 @@ func (s *kernelSource) Discover() error {
 	}
 
 	// Read kconfig
-	if realKconfig, legacyKconfig, err := parseKconfig(s.config.KconfigFile); err != nil {
+	realKconfig, legacyKconfig, err := parseKconfig(s.config.KconfigFile)
+	if err != nil {
 		s.legacyKconfig = nil
 		klog.ErrorS(err, "failed to read kconfig")
 	} else {
@@ -137,6 +154,9 @@ func (s *kernelSource) Discover() error {
 		s.legacyKconfig = legacyKconfig
 	}
 
+	// Detect clocksource and timer properties
+	s.features.Attributes[ClocksourceFeature] = nfdv1alpha1.NewAttributeFeatures(discoverClocksource(realKconfig))
+
 	var enabledModules []string
 	if kmods, err := getLoadedModules(); err != nil {
 		klog.ErrorS(err, "failed to get loaded kernel modules")
@@ -150,6 +170,13 @@ func (s *kernelSource) Discover() error {
 	} else {
 		enabledModules = append(enabledModules, builtinMods...)
 		s.features.Flags[EnabledModuleFeature] = nfdv1alpha1.NewFlagFeatures(enabledModules...)
+		s.features.Flags[BuiltinModuleFeature] = nfdv1alpha1.NewFlagFeatures(builtinMods...)
+	}
+
+	if params, err := getModuleParameters(); err != nil {
+		klog.ErrorS(err, "failed to get kernel module parameters")
+	} else {
+		s.features.Attributes[ModuleParamFeature] = nfdv1alpha1.NewAttributeFeatures(params)
 	}
 
 	if selinux, err := SelinuxEnabled(); err != nil {
@@ -159,7 +186,13 @@ func (s *kernelSource) Discover() error {
 		s.features.Attributes[SelinuxFeature].Elements["enabled"] = strconv.FormatBool(selinux)
 	}
 
-	klog.V(3).InfoS("discovered features", "featureSource", s.Name(), "features", utils.DelayedDumper(s.features))
+	if lsm, err := discoverLsm(); err != nil {
+		klog.ErrorS(err, "failed to detect active LSMs")
+	} else if lsm != nil {
+		s.features.Attributes[LsmFeature] = nfdv1alpha1.NewAttributeFeatures(lsm)
+	}
+
+	source.LogDiscoveredFeatures(s.Name(), "features", utils.DelayedDumper(s.features))
 
 	return nil
 }
@@ -17,9 +17,14 @@ limitations under the License.
 package kernel
 
 import (
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"sigs.k8s.io/node-feature-discovery/pkg/utils/hostpath"
 )
 
 func TestKernelSource(t *testing.T) {
@@ -33,3 +38,88 @@ func TestKernelSource(t *testing.T) {
 	assert.Empty(t, l)
 
 }
+
+func TestDiscoverClocksource(t *testing.T) {
+	root := t.TempDir()
+	origSysfsDir, origCpuinfoPath := hostpath.SysfsDir, cpuinfoPath
+	hostpath.SysfsDir = hostpath.HostDir(filepath.Join(root, "sys"))
+	cpuinfoPath = filepath.Join(root, "cpuinfo")
+	defer func() { hostpath.SysfsDir, cpuinfoPath = origSysfsDir, origCpuinfoPath }()
+
+	// Nothing available
+	assert.Empty(t, discoverClocksource(nil))
+
+	csDir := filepath.Join(root, "sys/devices/system/clocksource/clocksource0")
+	assert.NoError(t, os.MkdirAll(csDir, 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(csDir, "current_clocksource"), []byte("tsc\n"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(csDir, "available_clocksource"), []byte("tsc hpet acpi_pm \n"), 0644))
+	cpuinfo := "processor\t: 0\nflags\t\t: fpu tsc constant_tsc nonstop_tsc\n\nprocessor\t: 1\nflags\t\t: fpu tsc constant_tsc nonstop_tsc\n"
+	assert.NoError(t, os.WriteFile(cpuinfoPath, []byte(cpuinfo), 0644))
+
+	expected := map[string]string{
+		"current":      "tsc",
+		"available":    "tsc,hpet,acpi_pm",
+		"timer_hz":     "1000",
+		"constant_tsc": "true",
+		"nonstop_tsc":  "true",
+		"tsc_reliable": "false",
+	}
+	assert.Equal(t, expected, discoverClocksource(map[string]string{"HZ": "1000"}))
+}
+
+func TestDiscoverLsm(t *testing.T) {
+	root := t.TempDir()
+	origSysfsDir := hostpath.SysfsDir
+	hostpath.SysfsDir = hostpath.HostDir(root)
+	defer func() { hostpath.SysfsDir = origSysfsDir }()
+
+	// securityfs not mounted
+	attrs, err := discoverLsm()
+	assert.NoError(t, err)
+	assert.Nil(t, attrs)
+
+	assert.NoError(t, os.MkdirAll(filepath.Join(root, "kernel/security"), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "kernel/security/lsm"), []byte("lockdown,capability,landlock,yama,apparmor,bpf"), 0444))
+
+	attrs, err = discoverLsm()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"order":      "lockdown,capability,landlock,yama,apparmor,bpf",
+		"lockdown":   "1",
+		"capability": "2",
+		"landlock":   "3",
+		"yama":       "4",
+		"apparmor":   "5",
+		"bpf":        "6",
+	}, attrs)
+}
+
+func TestGetModuleParameters(t *testing.T) {
+	root := t.TempDir()
+	origSysfsDir := hostpath.SysfsDir
+	hostpath.SysfsDir = hostpath.HostDir(root)
+	defer func() { hostpath.SysfsDir = origSysfsDir }()
+
+	writeParam := func(module, param, value string, mode os.FileMode) {
+		dir := filepath.Join(root, "module", module, "parameters")
+		assert.NoError(t, os.MkdirAll(dir, 0755))
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, param), []byte(value), mode))
+	}
+
+	// No modules
+	params, err := getModuleParameters()
+	assert.NoError(t, err)
+	assert.Empty(t, params)
+
+	writeParam("kvm_intel", "nested", "Y\n", 0644)
+	writeParam("kvm", "halt_poll_ns", "200000\n", 0644)
+	writeParam("foo", "array", strings.Repeat("0,", maxModuleParameterLen), 0644)
+	assert.NoError(t, os.MkdirAll(filepath.Join(root, "module", "bar"), 0755))
+
+	params, err = getModuleParameters()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"kvm_intel.nested": "Y",
+		"kvm.halt_poll_ns": "200000",
+	}, params)
+}
@@ -0,0 +1,50 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kernel
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/node-feature-discovery/pkg/utils/hostpath"
+)
+
+// discoverLsm detects the active Linux Security Modules from securityfs. The
+// returned attributes contain the comma-separated list of active LSMs in the
+// order they are invoked by the kernel, and the position (starting from 1) of
+// each active LSM in that list.
+func discoverLsm() (map[string]string, error) {
+	data, err := os.ReadFile(hostpath.SysfsDir.Path("kernel/security/lsm"))
+	if os.IsNotExist(err) {
+		klog.V(1).InfoS("list of active LSMs not available, securityfs not mounted")
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	lsms := strings.Split(strings.TrimSpace(string(data)), ",")
+	attrs := map[string]string{"order": strings.Join(lsms, ",")}
+	for i, name := range lsms {
+		if name != "" {
+			attrs[name] = strconv.Itoa(i + 1)
+		}
+	}
+	return attrs, nil
+}
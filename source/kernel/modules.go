@@ -20,13 +20,21 @@ import (
 	"fmt"
 	"os"
 	"path"
+	"path/filepath"
 	"strings"
 
+	"k8s.io/klog/v2"
+
 	"sigs.k8s.io/node-feature-discovery/pkg/utils/hostpath"
 )
 
 const kmodProcfsPath = "/proc/modules"
 
+// maxModuleParameterLen is the maximum length of a kernel module parameter
+// value that is reported. Longer values, e.g. big per-device arrays, are
+// skipped to keep the size of the features in check.
+const maxModuleParameterLen = 128
+
 func getLoadedModules() ([]string, error) {
 	out, err := os.ReadFile(kmodProcfsPath)
 	if err != nil {
@@ -72,3 +80,31 @@ func getBuiltinModules() ([]string, error) {
 	}
 	return builtinMods, nil
 }
+
+// getModuleParameters returns the parameters of the loaded and built-in kernel
+// modules, read from /sys/module/<module>/parameters. The parameters are
+// reported as "<module>.<parameter>" keys. Parameters that are not readable
+// (e.g. write-only) are skipped.
+func getModuleParameters() (map[string]string, error) {
+	paramFiles, err := filepath.Glob(hostpath.SysfsDir.Path("module/*/parameters/*"))
+	if err != nil {
+		return nil, err
+	}
+
+	params := make(map[string]string, len(paramFiles))
+	for _, f := range paramFiles {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			klog.V(4).InfoS("failed to read kernel module parameter", "path", f, "error", err)
+			continue
+		}
+		value := strings.TrimSpace(string(data))
+		if len(value) > maxModuleParameterLen {
+			klog.V(4).InfoS("skipping too long kernel module parameter value", "path", f)
+			continue
+		}
+		module := filepath.Base(filepath.Dir(filepath.Dir(f)))
+		params[module+"."+filepath.Base(f)] = value
+	}
+	return params, nil
+}
@@ -19,12 +19,16 @@ package local
 import (
 	"bytes"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
 	"strings"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
 
 	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
 	"sigs.k8s.io/node-feature-discovery/pkg/utils"
@@ -57,6 +61,9 @@ const (
 // DirectivePrefix defines the prefix of directives that should be parsed
 const DirectivePrefix = "# +"
 
+// FeatureFileVersionV2 is the version of the structured feature file format
+const FeatureFileVersionV2 = "v2"
+
 // MaxFeatureFileSize defines the maximum size of a feature file size
 const MaxFeatureFileSize = 65536
 
@@ -68,6 +75,7 @@ var (
 // localSource implements the FeatureSource and LabelSource interfaces.
 type localSource struct {
 	features *nfdv1alpha1.Features
+	requests *source.NodeRequests
 	config   *Config
 }
 
@@ -81,12 +89,40 @@ type parsingOpts struct {
 	SkipFeature bool
 }
 
+// featureFileV2 is the structured feature file format, in YAML or JSON.
+type featureFileV2 struct {
+	Version string             `json:"version"`
+	Entries []featureFileEntry `json:"entries"`
+}
+
+// featureFileEntry is one entry of a structured feature file. Each entry may
+// have its own expiry time.
+type featureFileEntry struct {
+	Name              string            `json:"name,omitempty"`
+	ExpiryTime        *metav1.Time      `json:"expiryTime,omitempty"`
+	TTL               *metav1.Duration  `json:"ttl,omitempty"`
+	Features          map[string]string `json:"features,omitempty"`
+	Labels            map[string]string `json:"labels,omitempty"`
+	Annotations       map[string]string `json:"annotations,omitempty"`
+	ExtendedResources map[string]string `json:"extendedResources,omitempty"`
+	Taints            []corev1.Taint    `json:"taints,omitempty"`
+}
+
+// featureFileData is the content of one or more feature files.
+type featureFileData struct {
+	features map[string]string
+	labels   map[string]string
+	requests source.NodeRequests
+}
+
 // Singleton source instance
 var (
 	src                           = localSource{}
 	_   source.FeatureSource      = &src
 	_   source.LabelSource        = &src
 	_   source.ConfigurableSource = &src
+	_   source.HostPathSource     = &src
+	_   source.NodeRequestSource  = &src
 )
 
 // Name method of the LabelSource interface
@@ -111,6 +147,9 @@ func (s *localSource) SetConfig(conf source.Config) {
 // Priority method of the LabelSource interface
 func (s *localSource) Priority() int { return 20 }
 
+// RequiredHostPaths method of the HostPathSource interface
+func (s *localSource) RequiredHostPaths() []string { return []string{featureFilesDir} }
+
 // GetLabels method of the LabelSource interface
 func (s *localSource) GetLabels() (source.FeatureLabels, error) {
 	labels := make(source.FeatureLabels)
@@ -122,19 +161,28 @@ func (s *localSource) GetLabels() (source.FeatureLabels, error) {
 	return labels, nil
 }
 
+// GetNodeRequests method of the NodeRequestSource interface
+func (s *localSource) GetNodeRequests() *source.NodeRequests {
+	if s.requests == nil {
+		return &source.NodeRequests{}
+	}
+	return s.requests
+}
+
 // Discover method of the FeatureSource interface
 func (s *localSource) Discover() error {
 	s.features = nfdv1alpha1.NewFeatures()
 
-	featuresFromFiles, labelsFromFiles, err := getFeaturesFromFiles()
+	data, err := getFeaturesFromFiles()
 	if err != nil {
 		klog.ErrorS(err, "failed to read feature files")
 	}
 
-	s.features.Attributes[LabelFeature] = nfdv1alpha1.NewAttributeFeatures(labelsFromFiles)
-	s.features.Attributes[RawFeature] = nfdv1alpha1.NewAttributeFeatures(featuresFromFiles)
+	s.features.Attributes[LabelFeature] = nfdv1alpha1.NewAttributeFeatures(data.labels)
+	s.features.Attributes[RawFeature] = nfdv1alpha1.NewAttributeFeatures(data.features)
+	s.requests = &data.requests
 
-	klog.V(3).InfoS("discovered features", "featureSource", s.Name(), "features", utils.DelayedDumper(s.features))
+	source.LogDiscoveredFeatures(s.Name(), "features", utils.DelayedDumper(s.features), "nodeRequests", utils.DelayedDumper(s.requests))
 
 	return nil
 }
@@ -243,17 +291,19 @@ func updateFeatures(m map[string]string, lineSplit []string) {
 }
 
 // Read all files to get features
-func getFeaturesFromFiles() (map[string]string, map[string]string, error) {
-	features := make(map[string]string)
-	labels := make(map[string]string)
+func getFeaturesFromFiles() (featureFileData, error) {
+	data := featureFileData{
+		features: make(map[string]string),
+		labels:   make(map[string]string),
+	}
 
 	files, err := os.ReadDir(featureFilesDir)
 	if err != nil {
 		if os.IsNotExist(err) {
 			klog.InfoS("features directory does not exist", "path", featureFilesDir)
-			return features, labels, nil
+			return data, nil
 		}
-		return features, labels, fmt.Errorf("unable to access %v: %w", featureFilesDir, err)
+		return data, fmt.Errorf("unable to access %v: %w", featureFilesDir, err)
 	}
 
 	for _, file := range files {
@@ -262,60 +312,158 @@ func getFeaturesFromFiles() (map[string]string, map[string]string, error) {
 		if strings.HasPrefix(fileName, ".") {
 			continue
 		}
-		lines, err := getFileContent(fileName)
+		content, modTime, err := getFileContent(fileName)
 		if err != nil {
 			klog.ErrorS(err, "failed to read file", "fileName", fileName)
 			continue
 		}
 
-		// Append features
-		fileFeatures, fileLabels := parseFeatureFile(lines, fileName)
+		var fileData featureFileData
+		if isFeatureFileV2(content) {
+			fileData, err = parseFeatureFileV2(content, modTime, time.Now())
+			if err != nil {
+				klog.ErrorS(err, "failed to parse feature file", "fileName", fileName)
+				continue
+			}
+		} else {
+			fileData.features, fileData.labels = parseFeatureFile(bytes.Split(content, []byte("\n")), fileName)
+		}
 
-		klog.V(4).InfoS("feature file read", "fileName", fileName, "features", utils.DelayedDumper(fileFeatures))
-		for k, v := range fileFeatures {
-			if old, ok := features[k]; ok {
+		// Append features
+		if source.FeatureLoggingEnabled() {
+			klog.V(4).InfoS("feature file read", "fileName", fileName, "features", utils.DelayedDumper(fileData.features))
+		}
+		for k, v := range fileData.features {
+			if old, ok := data.features[k]; ok {
 				klog.InfoS("overriding label value from another feature file", "featureKey", k, "oldValue", old, "newValue", v, "fileName", fileName)
 			}
-			features[k] = v
+			data.features[k] = v
 		}
 
-		for k, v := range fileLabels {
-			if old, ok := labels[k]; ok {
+		for k, v := range fileData.labels {
+			if old, ok := data.labels[k]; ok {
 				klog.InfoS("overriding label value from another feature file", "labelKey", k, "oldValue", old, "newValue", v, "fileName", fileName)
 			}
-			labels[k] = v
+			data.labels[k] = v
 		}
+
+		mergeNodeRequests(&data.requests, &fileData.requests, fileName)
 	}
 
-	return features, labels, nil
+	return data, nil
 }
 
-// Read one file
-func getFileContent(fileName string) ([][]byte, error) {
-	var lines [][]byte
+// isFeatureFileV2 returns true if the content is a structured (v2) feature
+// file. Anything else is parsed as a legacy key=value feature file.
+func isFeatureFileV2(content []byte) bool {
+	var header struct {
+		Version string `json:"version"`
+	}
+	if err := yaml.Unmarshal(content, &header); err != nil {
+		return false
+	}
+	return header.Version == FeatureFileVersionV2
+}
+
+// parseFeatureFileV2 parses a structured feature file. Entries that have
+// expired at the given time are skipped. The TTL of an entry is relative to
+// the modification time of the file.
+func parseFeatureFileV2(content []byte, modTime, now time.Time) (featureFileData, error) {
+	data := featureFileData{
+		features: make(map[string]string),
+		labels:   make(map[string]string),
+	}
+
+	file := featureFileV2{}
+	if err := yaml.UnmarshalStrict(content, &file); err != nil {
+		return data, err
+	}
 
+	for i, e := range file.Entries {
+		if e.TTL != nil && e.TTL.Duration <= 0 {
+			return featureFileData{}, fmt.Errorf("invalid ttl %v in entry %d (%q), must be positive", e.TTL.Duration, i, e.Name)
+		}
+		if isEntryExpired(e, modTime, now) {
+			klog.V(4).InfoS("skipping expired feature file entry", "entryIndex", i, "entryName", e.Name)
+			continue
+		}
+
+		maps.Copy(data.features, e.Features)
+		maps.Copy(data.labels, e.Labels)
+		mergeNodeRequests(&data.requests, &source.NodeRequests{
+			Annotations:       e.Annotations,
+			ExtendedResources: e.ExtendedResources,
+			Taints:            e.Taints,
+		}, "")
+	}
+
+	return data, nil
+}
+
+// isEntryExpired returns true if the expiry time or the TTL of the entry has
+// passed. If both are specified the one expiring first takes effect.
+func isEntryExpired(e featureFileEntry, modTime, now time.Time) bool {
+	if e.ExpiryTime != nil && !e.ExpiryTime.After(now) {
+		return true
+	}
+	if e.TTL != nil && !modTime.Add(e.TTL.Duration).After(now) {
+		return true
+	}
+	return false
+}
+
+// mergeNodeRequests merges node requests into another set of node requests.
+// Annotations and extended resources of the input override the existing ones.
+func mergeNodeRequests(out, in *source.NodeRequests, fileName string) {
+	if len(in.Annotations) > 0 {
+		if out.Annotations == nil {
+			out.Annotations = make(map[string]string, len(in.Annotations))
+		}
+		for k, v := range in.Annotations {
+			if old, ok := out.Annotations[k]; ok && fileName != "" {
+				klog.InfoS("overriding annotation value from another feature file", "annotationKey", k, "oldValue", old, "newValue", v, "fileName", fileName)
+			}
+			out.Annotations[k] = v
+		}
+	}
+	if len(in.ExtendedResources) > 0 {
+		if out.ExtendedResources == nil {
+			out.ExtendedResources = make(map[string]string, len(in.ExtendedResources))
+		}
+		for k, v := range in.ExtendedResources {
+			if old, ok := out.ExtendedResources[k]; ok && fileName != "" {
+				klog.InfoS("overriding extended resource value from another feature file", "extendedResource", k, "oldValue", old, "newValue", v, "fileName", fileName)
+			}
+			out.ExtendedResources[k] = v
+		}
+	}
+	out.Taints = append(out.Taints, in.Taints...)
+}
+
+// Read one file
+func getFileContent(fileName string) ([]byte, time.Time, error) {
 	path := filepath.Join(featureFilesDir, fileName)
 	filestat, err := os.Stat(path)
 	if err != nil {
 		klog.ErrorS(err, "failed to get filestat, skipping features file", "path", path)
-		return lines, err
+		return nil, time.Time{}, err
 	}
 
-	if filestat.Mode().IsRegular() {
-		if filestat.Size() > MaxFeatureFileSize {
-			return lines, fmt.Errorf("file size limit exceeded: %d bytes > %d bytes", filestat.Size(), MaxFeatureFileSize)
-		}
+	if !filestat.Mode().IsRegular() {
+		return nil, time.Time{}, nil
+	}
 
-		fileContent, err := os.ReadFile(path)
+	if filestat.Size() > MaxFeatureFileSize {
+		return nil, time.Time{}, fmt.Errorf("file size limit exceeded: %d bytes > %d bytes", filestat.Size(), MaxFeatureFileSize)
+	}
 
-		// Do not return any lines if an error occurred
-		if err != nil {
-			return lines, err
-		}
-		lines = bytes.Split(fileContent, []byte("\n"))
+	// Do not return any content if an error occurred
+	fileContent, err := os.ReadFile(path)
+	if err != nil {
+		return nil, time.Time{}, err
 	}
 
-	return lines, nil
+	return fileContent, filestat.ModTime(), nil
 }
 
 func init() {
@@ -23,6 +23,9 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+
+	"sigs.k8s.io/node-feature-discovery/source"
 )
 
 func TestLocalSource(t *testing.T) {
@@ -43,11 +46,92 @@ func TestGetExpirationDate(t *testing.T) {
 
 	pwd, _ := os.Getwd()
 	featureFilesDir = filepath.Join(pwd, "testdata/features.d")
-	features, labels, err := getFeaturesFromFiles()
+	data, err := getFeaturesFromFiles()
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedFeaturesLen, len(data.features))
+	assert.Equal(t, expectedLabelsLen, len(data.labels))
+}
+
+func TestIsFeatureFileV2(t *testing.T) {
+	assert.True(t, isFeatureFileV2([]byte("version: v2\nentries: []\n")))
+	assert.True(t, isFeatureFileV2([]byte(`{"version": "v2"}`)))
+	assert.False(t, isFeatureFileV2([]byte("foo=bar\n# +no-label\nbar=baz\n")))
+	assert.False(t, isFeatureFileV2([]byte("version: v3\n")))
+	assert.False(t, isFeatureFileV2(nil))
+}
+
+func TestParseFeatureFileV2(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	modTime := now.Add(-time.Hour)
+
+	content := []byte(`
+version: v2
+entries:
+  - name: static
+    features:
+      feat-1: "true"
+    labels:
+      label-1: val-1
+    annotations:
+      annotation-1: val-1
+    extendedResources:
+      resource-1: "2"
+    taints:
+      - key: example.com/taint
+        value: "true"
+        effect: NoSchedule
+  - name: expired
+    expiryTime: "2025-06-01T11:00:00Z"
+    labels:
+      label-2: val-2
+  - name: not-expired
+    expiryTime: "2025-06-01T13:00:00Z"
+    labels:
+      label-3: val-3
+  - name: ttl-expired
+    ttl: 30m
+    features:
+      feat-2: val-2
+  - name: ttl
+    ttl: 2h
+    features:
+      feat-3: val-3
+    annotations:
+      annotation-1: overridden
+`)
+
+	data, err := parseFeatureFileV2(content, modTime, now)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"feat-1": "true", "feat-3": "val-3"}, data.features)
+	assert.Equal(t, map[string]string{"label-1": "val-1", "label-3": "val-3"}, data.labels)
+	assert.Equal(t, map[string]string{"annotation-1": "overridden"}, data.requests.Annotations)
+	assert.Equal(t, map[string]string{"resource-1": "2"}, data.requests.ExtendedResources)
+	assert.Equal(t, []corev1.Taint{{Key: "example.com/taint", Value: "true", Effect: corev1.TaintEffectNoSchedule}}, data.requests.Taints)
 
+	// Unknown fields
+	_, err = parseFeatureFileV2([]byte("version: v2\nentries:\n  - lables:\n      foo: bar\n"), modTime, now)
+	assert.Error(t, err)
+
+	// Invalid TTL
+	_, err = parseFeatureFileV2([]byte("version: v2\nentries:\n  - ttl: -1h\n"), modTime, now)
+	assert.Error(t, err)
+}
+
+func TestGetFeaturesFromFilesV2(t *testing.T) {
+	featureFilesDir = t.TempDir()
+
+	assert.NoError(t, os.WriteFile(filepath.Join(featureFilesDir, "legacy"), []byte("foo=bar\n"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(featureFilesDir, "v2.json"), []byte(`{
+  "version": "v2",
+  "entries": [{"labels": {"baz": "qux"}, "extendedResources": {"res": "1"}}]
+}`), 0644))
+
+	data, err := getFeaturesFromFiles()
 	assert.NoError(t, err)
-	assert.Equal(t, expectedFeaturesLen, len(features))
-	assert.Equal(t, expectedLabelsLen, len(labels))
+	assert.Equal(t, map[string]string{"foo": "bar"}, data.features)
+	assert.Equal(t, map[string]string{"foo": "bar", "baz": "qux"}, data.labels)
+	assert.Equal(t, map[string]string{"res": "1"}, data.requests.ExtendedResources)
 }
 
 func TestParseDirectives(t *testing.T) {
@@ -83,3 +167,19 @@ func TestParseDirectives(t *testing.T) {
 		})
 	}
 }
+
+func TestNotify(t *testing.T) {
+	featureFilesDir = t.TempDir()
+	notifyDelay = 10 * time.Millisecond
+
+	ch := make(chan source.FeatureSource)
+	assert.NoError(t, src.SetNotifyChannel(ch))
+
+	assert.NoError(t, os.WriteFile(filepath.Join(featureFilesDir, "feature-file"), []byte("foo=bar\n"), 0644))
+	select {
+	case s := <-ch:
+		assert.Equal(t, Name, s.Name())
+	case <-time.After(5 * time.Second):
+		t.Fatal("no notification received")
+	}
+}
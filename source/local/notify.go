@@ -0,0 +1,82 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package local
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/node-feature-discovery/source"
+)
+
+// notifyDelay is the time to wait for changes in the feature files to settle
+// before sending a notification. It prevents a burst of notifications when
+// multiple files are changed at once.
+var notifyDelay = time.Second
+
+var _ source.EventSource = &src
+
+// SetNotifyChannel method of the EventSource interface. Changes in the feature
+// files directory are watched and a notification is sent to the channel when
+// files are created, modified or removed.
+func (s *localSource) SetNotifyChannel(ch chan<- source.FeatureSource) error {
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	if err := w.Add(featureFilesDir); err != nil {
+		w.Close()
+		return fmt.Errorf("failed to watch %q: %w", featureFilesDir, err)
+	}
+
+	go s.runNotifier(w, ch)
+
+	return nil
+}
+
+func (s *localSource) runNotifier(w *fsnotify.Watcher, ch chan<- source.FeatureSource) {
+	defer w.Close()
+
+	var notify <-chan time.Time
+	for {
+		select {
+		case e, ok := <-w.Events:
+			if !ok {
+				return
+			}
+			// Attribute-only changes do not affect the features
+			if e.Op == fsnotify.Chmod {
+				continue
+			}
+			klog.V(4).InfoS("feature file change detected", "path", e.Name, "op", e.Op)
+			if notify == nil {
+				notify = time.After(notifyDelay)
+			}
+		case err, ok := <-w.Errors:
+			if !ok {
+				return
+			}
+			klog.ErrorS(err, "error watching feature files", "path", featureFilesDir)
+		case <-notify:
+			notify = nil
+			ch <- s
+		}
+	}
+}
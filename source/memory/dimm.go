@@ -0,0 +1,208 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package memory
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"k8s.io/klog/v2"
+
+	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
+	"sigs.k8s.io/node-feature-discovery/pkg/utils/hostpath"
+)
+
+// SMBIOS "Memory Device" (type 17) structure offsets
+const (
+	dmiMemDevSize              = 0x0c
+	dmiMemDevLocator           = 0x10
+	dmiMemDevBankLocator       = 0x11
+	dmiMemDevType              = 0x12
+	dmiMemDevSpeed             = 0x15
+	dmiMemDevManufacturer      = 0x17
+	dmiMemDevPartNumber        = 0x1a
+	dmiMemDevExtendedSize      = 0x1c
+	dmiMemDevConfiguredSpeed   = 0x20
+	dmiMemDevExtendedSpeed     = 0x54
+	dmiMemDevExtendedConfSpeed = 0x58
+)
+
+// dmiMemoryTypes maps the SMBIOS memory type to a human-readable name.
+var dmiMemoryTypes = map[byte]string{
+	0x12: "DDR",
+	0x13: "DDR2",
+	0x18: "DDR3",
+	0x1a: "DDR4",
+	0x1b: "LPDDR",
+	0x1c: "LPDDR2",
+	0x1d: "LPDDR3",
+	0x1e: "LPDDR4",
+	0x1f: "Logical non-volatile device",
+	0x20: "HBM",
+	0x21: "HBM2",
+	0x22: "DDR5",
+	0x23: "LPDDR5",
+	0x24: "HBM3",
+}
+
+// detectDimms detects the installed memory modules from the SMBIOS Memory
+// Device (type 17) entries of the DMI tables. Empty slots are skipped.
+func detectDimms() ([]nfdv1alpha1.InstanceFeature, error) {
+	sysfsBasePath := hostpath.SysfsDir.Path("firmware/dmi/entries")
+	info := make([]nfdv1alpha1.InstanceFeature, 0)
+
+	entries, err := filepath.Glob(filepath.Join(sysfsBasePath, "17-*"))
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		klog.V(1).InfoS("no DMI memory device entries present")
+		return info, nil
+	}
+
+	for _, entry := range entries {
+		raw, err := os.ReadFile(filepath.Join(entry, "raw"))
+		if errors.Is(err, fs.ErrPermission) {
+			// The raw DMI entries are only readable by root
+			klog.V(1).InfoS("DMI memory device entries not readable, DIMM inventory not available", "path", entry)
+			return info, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to read DMI memory device entry: %w", err)
+		}
+		attrs, err := parseDmiMemoryDevice(raw)
+		if err != nil {
+			klog.ErrorS(err, "failed to parse DMI memory device entry", "path", entry)
+			continue
+		}
+		if attrs != nil {
+			info = append(info, *nfdv1alpha1.NewInstanceFeature(attrs))
+		}
+	}
+
+	return info, nil
+}
+
+// dimmSummary returns the number and total size of the memory modules.
+func dimmSummary(dimms []nfdv1alpha1.InstanceFeature) map[string]string {
+	var totalMB uint64
+	for _, d := range dimms {
+		if n, err := strconv.ParseUint(d.Attributes["size_mb"], 10, 64); err == nil {
+			totalMB += n
+		}
+	}
+	return map[string]string{
+		"count":         strconv.Itoa(len(dimms)),
+		"total_size_mb": strconv.FormatUint(totalMB, 10),
+	}
+}
+
+// parseDmiMemoryDevice parses one raw SMBIOS Memory Device structure. Nil
+// attributes are returned for empty memory slots.
+func parseDmiMemoryDevice(raw []byte) (map[string]string, error) {
+	if len(raw) < 2 || raw[0] != 17 {
+		return nil, fmt.Errorf("not a memory device entry")
+	}
+	length := int(raw[1])
+	if length < dmiMemDevSpeed+2 || len(raw) < length {
+		return nil, fmt.Errorf("memory device entry too short (%d bytes)", len(raw))
+	}
+	formatted, strs := raw[:length], dmiStrings(raw[length:])
+
+	word := func(off int) uint16 { return binary.LittleEndian.Uint16(formatted[off:]) }
+	dword := func(off int) uint32 { return binary.LittleEndian.Uint32(formatted[off:]) }
+	str := func(off int) string {
+		if off >= length || formatted[off] == 0 || int(formatted[off]) > len(strs) {
+			return ""
+		}
+		return strs[formatted[off]-1]
+	}
+
+	// Size in MB: 0 means an empty slot and 0xffff an unknown size
+	var sizeMB uint64
+	switch size := word(dmiMemDevSize); {
+	case size == 0:
+		return nil, nil
+	case size == 0xffff:
+	case size == 0x7fff && length >= dmiMemDevExtendedSize+4:
+		sizeMB = uint64(dword(dmiMemDevExtendedSize) & 0x7fffffff)
+	case size&0x8000 != 0:
+		sizeMB = uint64(size&0x7fff) / 1024
+	default:
+		sizeMB = uint64(size)
+	}
+
+	attrs := map[string]string{
+		"locator":      str(dmiMemDevLocator),
+		"bank_locator": str(dmiMemDevBankLocator),
+	}
+	if sizeMB > 0 {
+		attrs["size_mb"] = strconv.FormatUint(sizeMB, 10)
+	}
+	if t, ok := dmiMemoryTypes[formatted[dmiMemDevType]]; ok {
+		attrs["type"] = t
+	}
+	if speed := dmiSpeed(formatted, dmiMemDevSpeed, dmiMemDevExtendedSpeed); speed > 0 {
+		attrs["speed_mts"] = strconv.FormatUint(uint64(speed), 10)
+	}
+	if speed := dmiSpeed(formatted, dmiMemDevConfiguredSpeed, dmiMemDevExtendedConfSpeed); speed > 0 {
+		attrs["configured_speed_mts"] = strconv.FormatUint(uint64(speed), 10)
+	}
+	if s := str(dmiMemDevManufacturer); s != "" {
+		attrs["manufacturer"] = s
+	}
+	if s := str(dmiMemDevPartNumber); s != "" {
+		attrs["part_number"] = s
+	}
+	return attrs, nil
+}
+
+// dmiSpeed returns a memory speed (in MT/s) from a formatted SMBIOS structure,
+// using the extended speed field if the speed does not fit in 16 bits. Zero
+// is returned if the speed is unknown.
+func dmiSpeed(formatted []byte, off, extOff int) uint32 {
+	if len(formatted) < off+2 {
+		return 0
+	}
+	speed := uint32(binary.LittleEndian.Uint16(formatted[off:]))
+	if speed == 0xffff {
+		if len(formatted) < extOff+4 {
+			return 0
+		}
+		speed = binary.LittleEndian.Uint32(formatted[extOff:]) & 0x7fffffff
+	}
+	return speed
+}
+
+// dmiStrings returns the strings following the formatted area of an SMBIOS
+// structure.
+func dmiStrings(data []byte) []string {
+	ret := []string{}
+	for _, s := range bytes.Split(data, []byte{0}) {
+		if len(s) == 0 {
+			break
+		}
+		ret = append(ret, strings.TrimSpace(string(s)))
+	}
+	return ret
+}
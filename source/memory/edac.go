@@ -0,0 +1,80 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package memory
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/node-feature-discovery/pkg/utils/hostpath"
+)
+
+// edacCounters is the list of error counters (under each EDAC memory
+// controller) that are summed up over all memory controllers.
+var edacCounters = []string{"ce_count", "ue_count", "ce_noinfo_count", "ue_noinfo_count"}
+
+// detectEdac detects the memory error counters reported by the EDAC (Error
+// Detection And Correction) subsystem. The counters of all memory controllers
+// are summed up. Nil is returned if no EDAC memory controllers are present.
+func detectEdac() (map[string]string, error) {
+	sysfsBasePath := hostpath.SysfsDir.Path("devices/system/edac/mc")
+
+	entries, err := os.ReadDir(sysfsBasePath)
+	if os.IsNotExist(err) {
+		klog.V(1).InfoS("no EDAC memory controllers present")
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to list EDAC memory controllers: %w", err)
+	}
+
+	mcCount := 0
+	counts := make(map[string]uint64, len(edacCounters))
+	for _, e := range entries {
+		if !strings.HasPrefix(e.Name(), "mc") {
+			continue
+		}
+		mcCount++
+		for _, name := range edacCounters {
+			path := filepath.Join(sysfsBasePath, e.Name(), name)
+			data, err := os.ReadFile(path)
+			if err != nil {
+				klog.V(3).ErrorS(err, "failed to read EDAC counter", "path", path)
+				continue
+			}
+			n, err := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+			if err != nil {
+				klog.ErrorS(err, "failed to parse EDAC counter", "path", path)
+				continue
+			}
+			counts[name] += n
+		}
+	}
+	if mcCount == 0 {
+		return nil, nil
+	}
+
+	attrs := map[string]string{"mc_count": strconv.Itoa(mcCount)}
+	for _, name := range edacCounters {
+		attrs[name] = strconv.FormatUint(counts[name], 10)
+	}
+	return attrs, nil
+}
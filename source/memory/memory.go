@@ -20,7 +20,6 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"strconv"
 	"strings"
 
 	"k8s.io/klog/v2"
@@ -43,6 +42,22 @@ const NumaFeature = "numa"
 // SwapFeature is the name of the feature set that holds all Swap related features
 const SwapFeature = "swap"
 
+// EdacFeature is the name of the feature set that holds the memory error
+// counters reported by EDAC.
+const EdacFeature = "edac"
+
+// DimmFeature is the name of the feature set that holds all discovered memory
+// modules (DIMMs).
+const DimmFeature = "dimm"
+
+// DimmSummaryFeature is the name of the feature set that holds the summary of
+// all discovered memory modules.
+const DimmSummaryFeature = "dimm_summary"
+
+// NumaNodeFeature is the name of the feature set that holds the information
+// of each NUMA node.
+const NumaNodeFeature = "numa_node"
+
 // memorySource implements the FeatureSource and LabelSource interfaces.
 type memorySource struct {
 	features *nfdv1alpha1.Features
@@ -51,8 +66,9 @@ type memorySource struct {
 // Singleton source instance
 var (
 	src memorySource
-	_   source.FeatureSource = &src
-	_   source.LabelSource   = &src
+	_   source.FeatureSource  = &src
+	_   source.LabelSource    = &src
+	_   source.HostPathSource = &src
 )
 
 // Name returns an identifier string for this feature source.
@@ -61,6 +77,14 @@ func (s *memorySource) Name() string { return Name }
 // Priority method of the LabelSource interface
 func (s *memorySource) Priority() int { return 0 }
 
+// RequiredHostPaths method of the HostPathSource interface
+func (s *memorySource) RequiredHostPaths() []string {
+	return []string{
+		hostpath.ProcDir.Path("swaps"),
+		hostpath.SysfsDir.Path("bus/node/devices"),
+	}
+}
+
 // GetLabels method of the LabelSource interface
 func (s *memorySource) GetLabels() (source.FeatureLabels, error) {
 	labels := source.FeatureLabels{}
@@ -95,10 +119,11 @@ func (s *memorySource) Discover() error {
 	s.features = nfdv1alpha1.NewFeatures()
 
 	// Detect NUMA
-	if numa, err := detectNuma(); err != nil {
+	if numa, nodes, err := detectNuma(); err != nil {
 		klog.ErrorS(err, "failed to detect NUMA nodes")
 	} else {
 		s.features.Attributes[NumaFeature] = nfdv1alpha1.AttributeFeatureSet{Elements: numa}
+		s.features.Instances[NumaNodeFeature] = nfdv1alpha1.InstanceFeatureSet{Elements: nodes}
 	}
 
 	// Detect Swap
@@ -115,7 +140,24 @@ func (s *memorySource) Discover() error {
 		s.features.Instances[NvFeature] = nfdv1alpha1.InstanceFeatureSet{Elements: nv}
 	}
 
-	klog.V(3).InfoS("discovered features", "featureSource", s.Name(), "features", utils.DelayedDumper(s.features))
+	// Detect memory errors
+	if edac, err := detectEdac(); err != nil {
+		klog.ErrorS(err, "failed to detect EDAC memory errors")
+	} else if edac != nil {
+		s.features.Attributes[EdacFeature] = nfdv1alpha1.AttributeFeatureSet{Elements: edac}
+	}
+
+	// Detect DIMMs
+	if dimms, err := detectDimms(); err != nil {
+		klog.ErrorS(err, "failed to detect DIMMs")
+	} else {
+		s.features.Instances[DimmFeature] = nfdv1alpha1.InstanceFeatureSet{Elements: dimms}
+		if len(dimms) > 0 {
+			s.features.Attributes[DimmSummaryFeature] = nfdv1alpha1.AttributeFeatureSet{Elements: dimmSummary(dimms)}
+		}
+	}
+
+	source.LogDiscoveredFeatures(s.Name(), "features", utils.DelayedDumper(s.features))
 
 	return nil
 }
@@ -128,35 +170,6 @@ func (s *memorySource) GetFeatures() *nfdv1alpha1.Features {
 	return s.features
 }
 
-// detectSwap detects Swap node information
-func detectSwap() (map[string]string, error) {
-	procBasePath := hostpath.ProcDir.Path("swaps")
-	lines, err := getNumberOfNonEmptyLinesFromFile(procBasePath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read swaps file: %w", err)
-	}
-	// /proc/swaps has a header row
-	// If there is more than a header then we assume we have swap.
-	return map[string]string{
-		"enabled": strconv.FormatBool(lines > 1),
-	}, nil
-}
-
-// detectNuma detects NUMA node information
-func detectNuma() (map[string]string, error) {
-	sysfsBasePath := hostpath.SysfsDir.Path("bus/node/devices")
-
-	nodes, err := os.ReadDir(sysfsBasePath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to list numa nodes: %w", err)
-	}
-
-	return map[string]string{
-		"is_numa":    strconv.FormatBool(len(nodes) > 1),
-		"node_count": strconv.Itoa(len(nodes)),
-	}, nil
-}
-
 // detectNv detects NVDIMM devices
 func detectNv() ([]nfdv1alpha1.InstanceFeature, error) {
 	sysfsBasePath := hostpath.SysfsDir.Path("bus/nd/devices")
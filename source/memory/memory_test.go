@@ -17,9 +17,15 @@ limitations under the License.
 package memory
 
 import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
+	"sigs.k8s.io/node-feature-discovery/pkg/utils/hostpath"
 )
 
 func TestMemorySource(t *testing.T) {
@@ -61,3 +67,108 @@ func TestGetNumberofLinesFromFile(t *testing.T) {
 		assert.Equal(t, tc.expectedLines, actual, "lines should match")
 	}
 }
+
+func TestDetectEdac(t *testing.T) {
+	root := t.TempDir()
+	origSysfsDir := hostpath.SysfsDir
+	hostpath.SysfsDir = hostpath.HostDir(root)
+	defer func() { hostpath.SysfsDir = origSysfsDir }()
+
+	// No EDAC support
+	attrs, err := detectEdac()
+	assert.NoError(t, err)
+	assert.Nil(t, attrs)
+
+	writeFile := func(p, content string) {
+		assert.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(root, p)), 0755))
+		assert.NoError(t, os.WriteFile(filepath.Join(root, p), []byte(content), 0644))
+	}
+	writeFile("devices/system/edac/mc/mc0/ce_count", "3\n")
+	writeFile("devices/system/edac/mc/mc0/ue_count", "0\n")
+	writeFile("devices/system/edac/mc/mc1/ce_count", "2\n")
+	writeFile("devices/system/edac/mc/mc1/ue_count", "1\n")
+	writeFile("devices/system/edac/mc/mc1/ce_noinfo_count", "1\n")
+	writeFile("devices/system/edac/mc/power/control", "auto\n")
+
+	attrs, err = detectEdac()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"mc_count":        "2",
+		"ce_count":        "5",
+		"ue_count":        "1",
+		"ce_noinfo_count": "1",
+		"ue_noinfo_count": "0",
+	}, attrs)
+}
+
+// newDmiMemoryDevice returns a raw SMBIOS memory device (type 17) structure.
+func newDmiMemoryDevice(size uint16, extSize uint32, memType byte, speed, confSpeed uint16, strs ...string) []byte {
+	raw := make([]byte, 0x28)
+	raw[0] = 17
+	raw[1] = byte(len(raw))
+	binary.LittleEndian.PutUint16(raw[dmiMemDevSize:], size)
+	raw[dmiMemDevLocator] = 1
+	raw[dmiMemDevBankLocator] = 2
+	raw[dmiMemDevType] = memType
+	binary.LittleEndian.PutUint16(raw[dmiMemDevSpeed:], speed)
+	raw[dmiMemDevManufacturer] = 3
+	raw[dmiMemDevPartNumber] = 4
+	binary.LittleEndian.PutUint32(raw[dmiMemDevExtendedSize:], extSize)
+	binary.LittleEndian.PutUint16(raw[dmiMemDevConfiguredSpeed:], confSpeed)
+	for _, s := range strs {
+		raw = append(raw, append([]byte(s), 0)...)
+	}
+	return append(raw, 0)
+}
+
+func TestDetectDimms(t *testing.T) {
+	root := t.TempDir()
+	origSysfsDir := hostpath.SysfsDir
+	hostpath.SysfsDir = hostpath.HostDir(root)
+	defer func() { hostpath.SysfsDir = origSysfsDir }()
+
+	// No DMI tables
+	dimms, err := detectDimms()
+	assert.NoError(t, err)
+	assert.Empty(t, dimms)
+
+	writeEntry := func(name string, raw []byte) {
+		dir := filepath.Join(root, "firmware/dmi/entries", name)
+		assert.NoError(t, os.MkdirAll(dir, 0755))
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, "raw"), raw, 0644))
+	}
+	writeEntry("17-0", newDmiMemoryDevice(16384, 0, 0x1a, 3200, 2933, "DIMM_A1", "BANK 0", "Samsung", "M393A2K43DB3-CWE"))
+	writeEntry("17-1", newDmiMemoryDevice(0, 0, 0x02, 0, 0, "DIMM_A2", "BANK 1", "NO DIMM", "NO DIMM"))
+	writeEntry("17-2", newDmiMemoryDevice(0x7fff, 65536, 0x22, 4800, 4800, "DIMM_B1", "BANK 2", "Micron", ""))
+	writeEntry("16-0", []byte{16, 4, 0, 0, 0, 0})
+
+	dimms, err = detectDimms()
+	assert.NoError(t, err)
+	assert.Equal(t, []nfdv1alpha1.InstanceFeature{
+		*nfdv1alpha1.NewInstanceFeature(map[string]string{
+			"locator":              "DIMM_A1",
+			"bank_locator":         "BANK 0",
+			"size_mb":              "16384",
+			"type":                 "DDR4",
+			"speed_mts":            "3200",
+			"configured_speed_mts": "2933",
+			"manufacturer":         "Samsung",
+			"part_number":          "M393A2K43DB3-CWE",
+		}),
+		*nfdv1alpha1.NewInstanceFeature(map[string]string{
+			"locator":              "DIMM_B1",
+			"bank_locator":         "BANK 2",
+			"size_mb":              "65536",
+			"type":                 "DDR5",
+			"speed_mts":            "4800",
+			"configured_speed_mts": "4800",
+			"manufacturer":         "Micron",
+		}),
+	}, dimms)
+
+	assert.Equal(t, map[string]string{"count": "2", "total_size_mb": "81920"}, dimmSummary(dimms))
+
+	// Invalid entries
+	_, err = parseDmiMemoryDevice([]byte{17, 4, 0, 0})
+	assert.Error(t, err)
+}
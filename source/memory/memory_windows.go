@@ -0,0 +1,58 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package memory
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
+	"sigs.k8s.io/node-feature-discovery/pkg/utils"
+)
+
+// detectNuma detects NUMA node information from the logical processor
+// information of the Windows API. The memory size and distances of the NUMA
+// nodes are not available.
+func detectNuma() (map[string]string, []nfdv1alpha1.InstanceFeature, error) {
+	infos, err := utils.GetLogicalProcessorInformation(utils.RelationNumaNode)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list numa nodes: %w", err)
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].NodeNumber < infos[j].NodeNumber })
+
+	attrs := map[string]string{
+		"is_numa":    strconv.FormatBool(len(infos) > 1),
+		"node_count": strconv.Itoa(len(infos)),
+	}
+
+	instances := make([]nfdv1alpha1.InstanceFeature, 0, len(infos))
+	for _, info := range infos {
+		instances = append(instances, *nfdv1alpha1.NewInstanceFeature(map[string]string{
+			"node":      strconv.FormatUint(uint64(info.NodeNumber), 10),
+			"cpu_count": strconv.Itoa(info.LogicalProcessors),
+		}))
+	}
+
+	return attrs, instances, nil
+}
+
+// detectSwap returns no attributes as swap is not supported by the kubelet on
+// Windows.
+func detectSwap() (map[string]string, error) {
+	return nil, nil
+}
@@ -0,0 +1,189 @@
+//go:build !windows
+// +build !windows
+
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package memory
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"k8s.io/klog/v2"
+	"k8s.io/utils/cpuset"
+
+	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
+	"sigs.k8s.io/node-feature-discovery/pkg/utils/hostpath"
+)
+
+// numaNode contains the information of one NUMA node.
+type numaNode struct {
+	id        int
+	cpus      int
+	memoryMB  uint64
+	distances []int
+}
+
+// detectNuma detects NUMA node information
+func detectNuma() (map[string]string, []nfdv1alpha1.InstanceFeature, error) {
+	sysfsBasePath := hostpath.SysfsDir.Path("bus/node/devices")
+
+	entries, err := os.ReadDir(sysfsBasePath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list numa nodes: %w", err)
+	}
+
+	attrs := map[string]string{
+		"is_numa":    strconv.FormatBool(len(entries) > 1),
+		"node_count": strconv.Itoa(len(entries)),
+	}
+
+	nodes := make([]numaNode, 0, len(entries))
+	for _, e := range entries {
+		id, err := strconv.Atoi(strings.TrimPrefix(e.Name(), "node"))
+		if err != nil || !strings.HasPrefix(e.Name(), "node") {
+			continue
+		}
+		n, err := readNumaNode(filepath.Join(sysfsBasePath, e.Name()), id)
+		if err != nil {
+			klog.ErrorS(err, "failed to read NUMA node information", "numaNode", id)
+			continue
+		}
+		nodes = append(nodes, n)
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].id < nodes[j].id })
+
+	if len(nodes) == 0 {
+		return attrs, nil, nil
+	}
+	for k, v := range numaSummary(nodes) {
+		attrs[k] = v
+	}
+
+	instances := make([]nfdv1alpha1.InstanceFeature, 0, len(nodes))
+	for _, n := range nodes {
+		distances := make([]string, len(n.distances))
+		for i, d := range n.distances {
+			distances[i] = strconv.Itoa(d)
+		}
+		instances = append(instances, *nfdv1alpha1.NewInstanceFeature(map[string]string{
+			"node":      strconv.Itoa(n.id),
+			"cpu_count": strconv.Itoa(n.cpus),
+			"memory_mb": strconv.FormatUint(n.memoryMB, 10),
+			"distances": strings.Join(distances, ","),
+		}))
+	}
+
+	return attrs, instances, nil
+}
+
+// numaSummary returns the summary attributes of the NUMA nodes.
+func numaSummary(nodes []numaNode) map[string]string {
+	minCPUs, maxCPUs := nodes[0].cpus, nodes[0].cpus
+	minMem, maxMem := nodes[0].memoryMB, nodes[0].memoryMB
+	cpuless := 0
+	maxDistance := 0
+	for _, n := range nodes {
+		minCPUs = min(minCPUs, n.cpus)
+		maxCPUs = max(maxCPUs, n.cpus)
+		minMem = min(minMem, n.memoryMB)
+		maxMem = max(maxMem, n.memoryMB)
+		if n.cpus == 0 {
+			cpuless++
+		}
+		for _, d := range n.distances {
+			maxDistance = max(maxDistance, d)
+		}
+	}
+
+	attrs := map[string]string{
+		"min_cpus_per_node":      strconv.Itoa(minCPUs),
+		"max_cpus_per_node":      strconv.Itoa(maxCPUs),
+		"min_memory_per_node_mb": strconv.FormatUint(minMem, 10),
+		"max_memory_per_node_mb": strconv.FormatUint(maxMem, 10),
+		"cpuless_node_count":     strconv.Itoa(cpuless),
+	}
+	if maxDistance > 0 {
+		attrs["max_distance"] = strconv.Itoa(maxDistance)
+	}
+	return attrs
+}
+
+// readNumaNode reads the CPUs, memory and distances of one NUMA node from
+// sysfs.
+func readNumaNode(path string, id int) (numaNode, error) {
+	n := numaNode{id: id}
+
+	data, err := os.ReadFile(filepath.Join(path, "cpulist"))
+	if err != nil {
+		return n, err
+	}
+	cpus, err := cpuset.Parse(strings.TrimSpace(string(data)))
+	if err != nil {
+		return n, fmt.Errorf("failed to parse cpulist: %w", err)
+	}
+	n.cpus = cpus.Size()
+
+	n.memoryMB, err = readNumaNodeMemTotal(filepath.Join(path, "meminfo"))
+	if err != nil {
+		return n, err
+	}
+
+	// Distances are not available on all architectures
+	if data, err := os.ReadFile(filepath.Join(path, "distance")); err == nil {
+		for _, f := range strings.Fields(string(data)) {
+			d, err := strconv.Atoi(f)
+			if err != nil {
+				return n, fmt.Errorf("failed to parse distance %q: %w", f, err)
+			}
+			n.distances = append(n.distances, d)
+		}
+	}
+
+	return n, nil
+}
+
+// readNumaNodeMemTotal returns the total memory of a NUMA node in MiB, read
+// from the "Node <id> MemTotal: <size> kB" line of the meminfo file.
+func readNumaNodeMemTotal(path string) (uint64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 4 && fields[2] == "MemTotal:" {
+			kb, err := strconv.ParseUint(fields[3], 10, 64)
+			if err != nil {
+				return 0, fmt.Errorf("failed to parse MemTotal of %q: %w", path, err)
+			}
+			return kb >> 10, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+	return 0, fmt.Errorf("MemTotal not found in %q", path)
+}
@@ -0,0 +1,66 @@
+//go:build !windows
+// +build !windows
+
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package memory
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"sigs.k8s.io/node-feature-discovery/pkg/utils/hostpath"
+)
+
+func TestDetectNuma(t *testing.T) {
+	root := t.TempDir()
+	origSysfsDir := hostpath.SysfsDir
+	hostpath.SysfsDir = hostpath.HostDir(root)
+	defer func() { hostpath.SysfsDir = origSysfsDir }()
+
+	writeNode := func(id, cpulist, memKB, distance string) {
+		dir := filepath.Join(root, "bus/node/devices", "node"+id)
+		assert.NoError(t, os.MkdirAll(dir, 0755))
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, "cpulist"), []byte(cpulist+"\n"), 0644))
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, "distance"), []byte(distance+"\n"), 0644))
+		meminfo := "Node " + id + " MemTotal:       " + memKB + " kB\nNode " + id + " MemFree:        1024 kB\n"
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, "meminfo"), []byte(meminfo), 0644))
+	}
+	writeNode("0", "0-3,8-11", "16777216", "10 21 14")
+	writeNode("1", "4-7,12-15", "16777216", "21 10 24")
+	// Memory-only node, e.g. CXL memory
+	writeNode("2", "", "8388608", "14 24 10")
+
+	attrs, nodes, err := detectNuma()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"is_numa":                "true",
+		"node_count":             "3",
+		"min_cpus_per_node":      "0",
+		"max_cpus_per_node":      "8",
+		"min_memory_per_node_mb": "8192",
+		"max_memory_per_node_mb": "16384",
+		"cpuless_node_count":     "1",
+		"max_distance":           "24",
+	}, attrs)
+	assert.Len(t, nodes, 3)
+	assert.Equal(t, map[string]string{"node": "1", "cpu_count": "8", "memory_mb": "16384", "distances": "21,10,24"}, nodes[1].Attributes)
+	assert.Equal(t, "0", nodes[2].Attributes["cpu_count"])
+}
@@ -0,0 +1,148 @@
+//go:build !windows
+// +build !windows
+
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package memory
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/node-feature-discovery/pkg/utils/hostpath"
+)
+
+// swapSizeBuckets are the upper bounds (in MiB) of the swap size buckets.
+var swapSizeBuckets = []struct {
+	name  string
+	maxMB uint64
+}{
+	{"le1Gi", 1 << 10},
+	{"le4Gi", 4 << 10},
+	{"le16Gi", 16 << 10},
+	{"le64Gi", 64 << 10},
+}
+
+// detectSwap detects Swap node information
+func detectSwap() (map[string]string, error) {
+	data, err := os.ReadFile(hostpath.ProcDir.Path("swaps"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read swaps file: %w", err)
+	}
+
+	// /proc/swaps has a header row, the other rows are swap devices:
+	// Filename	Type	Size	Used	Priority
+	var (
+		totalKB     uint64
+		devices     int
+		zramDevices []string
+	)
+	lines := strings.Split(string(data), "\n")
+	for _, line := range lines[1:] {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		devices++
+		if len(fields) >= 3 {
+			if size, err := strconv.ParseUint(fields[2], 10, 64); err == nil {
+				totalKB += size
+			}
+		}
+		if name := filepath.Base(fields[0]); strings.HasPrefix(name, "zram") {
+			zramDevices = append(zramDevices, name)
+		}
+	}
+
+	totalMB := totalKB >> 10
+	attrs := map[string]string{
+		"enabled":      strconv.FormatBool(devices > 0),
+		"total_mb":     strconv.FormatUint(totalMB, 10),
+		"size_bucket":  swapSizeBucket(devices, totalMB),
+		"zram_devices": strconv.Itoa(len(zramDevices)),
+	}
+	if len(zramDevices) > 0 {
+		if alg := zramCompAlgorithm(zramDevices[0]); alg != "" {
+			attrs["zram_algorithm"] = alg
+		}
+	}
+	for k, v := range detectZswap() {
+		attrs[k] = v
+	}
+	return attrs, nil
+}
+
+// swapSizeBucket returns the size bucket of the total swap size.
+func swapSizeBucket(devices int, totalMB uint64) string {
+	if devices == 0 {
+		return "none"
+	}
+	for _, b := range swapSizeBuckets {
+		if totalMB <= b.maxMB {
+			return b.name
+		}
+	}
+	return "gt64Gi"
+}
+
+// zramCompAlgorithm returns the compression algorithm of a zram device. The
+// sysfs file lists all available algorithms with the selected one in
+// brackets, e.g. "lzo lzo-rle lz4 [zstd]".
+func zramCompAlgorithm(dev string) string {
+	data, err := os.ReadFile(hostpath.SysfsDir.Path("block", dev, "comp_algorithm"))
+	if err != nil {
+		klog.V(3).InfoS("failed to read zram compression algorithm", "device", dev, "error", err)
+		return ""
+	}
+	for _, alg := range strings.Fields(string(data)) {
+		if strings.HasPrefix(alg, "[") && strings.HasSuffix(alg, "]") {
+			return strings.Trim(alg, "[]")
+		}
+	}
+	return ""
+}
+
+// detectZswap detects the configuration of zswap, the compressed cache for
+// swap pages. Nil is returned if zswap is not supported by the kernel.
+func detectZswap() map[string]string {
+	params := hostpath.SysfsDir.Path("module/zswap/parameters")
+	enabled, err := os.ReadFile(filepath.Join(params, "enabled"))
+	if err != nil {
+		if !os.IsNotExist(err) {
+			klog.V(3).InfoS("failed to read zswap parameters", "error", err)
+		}
+		return nil
+	}
+
+	attrs := map[string]string{
+		"zswap_enabled": strconv.FormatBool(strings.TrimSpace(string(enabled)) == "Y"),
+	}
+	for attr, param := range map[string]string{
+		"zswap_compressor":       "compressor",
+		"zswap_max_pool_percent": "max_pool_percent",
+	} {
+		if data, err := os.ReadFile(filepath.Join(params, param)); err == nil {
+			attrs[attr] = strings.TrimSpace(string(data))
+		}
+	}
+	return attrs
+}
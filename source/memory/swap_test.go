@@ -0,0 +1,91 @@
+//go:build !windows
+// +build !windows
+
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package memory
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"sigs.k8s.io/node-feature-discovery/pkg/utils/hostpath"
+)
+
+func TestDetectSwap(t *testing.T) {
+	root := t.TempDir()
+	origProcDir, origSysfsDir := hostpath.ProcDir, hostpath.SysfsDir
+	hostpath.ProcDir = hostpath.HostDir(filepath.Join(root, "proc"))
+	hostpath.SysfsDir = hostpath.HostDir(filepath.Join(root, "sys"))
+	defer func() {
+		hostpath.ProcDir, hostpath.SysfsDir = origProcDir, origSysfsDir
+	}()
+
+	writeFile := func(p, content string) {
+		assert.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(root, p)), 0755))
+		assert.NoError(t, os.WriteFile(filepath.Join(root, p), []byte(content), 0644))
+	}
+
+	// Swaps file missing
+	_, err := detectSwap()
+	assert.Error(t, err)
+
+	// No swap
+	writeFile("proc/swaps", "Filename\tType\tSize\tUsed\tPriority\n")
+	attrs, err := detectSwap()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"enabled":      "false",
+		"total_mb":     "0",
+		"size_bucket":  "none",
+		"zram_devices": "0",
+	}, attrs)
+
+	// Swap partition, zram device and zswap
+	writeFile("proc/swaps", `Filename                                Type            Size            Used            Priority
+/dev/nvme0n1p3                          partition       4194300         0               -2
+/dev/zram0                              partition       8388604         1024            100
+`)
+	writeFile("sys/block/zram0/comp_algorithm", "lzo lzo-rle lz4 lz4hc 842 [zstd]\n")
+	writeFile("sys/module/zswap/parameters/enabled", "Y\n")
+	writeFile("sys/module/zswap/parameters/compressor", "lz4\n")
+	writeFile("sys/module/zswap/parameters/max_pool_percent", "20\n")
+	attrs, err = detectSwap()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"enabled":                "true",
+		"total_mb":               "12287",
+		"size_bucket":            "le16Gi",
+		"zram_devices":           "1",
+		"zram_algorithm":         "zstd",
+		"zswap_enabled":          "true",
+		"zswap_compressor":       "lz4",
+		"zswap_max_pool_percent": "20",
+	}, attrs)
+}
+
+func TestSwapSizeBucket(t *testing.T) {
+	assert.Equal(t, "none", swapSizeBucket(0, 0))
+	assert.Equal(t, "le1Gi", swapSizeBucket(1, 0))
+	assert.Equal(t, "le1Gi", swapSizeBucket(1, 1024))
+	assert.Equal(t, "le4Gi", swapSizeBucket(1, 1025))
+	assert.Equal(t, "le64Gi", swapSizeBucket(2, 64<<10))
+	assert.Equal(t, "gt64Gi", swapSizeBucket(1, 64<<10+1))
+}
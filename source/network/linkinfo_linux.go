@@ -0,0 +1,165 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package network
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"syscall"
+	"unsafe"
+)
+
+// iflaMaxMTU is the netlink attribute type of the maximum MTU of a link. Not
+// defined in the syscall package.
+const iflaMaxMTU = 0x33
+
+// Ethtool ioctl commands, not defined in the syscall package
+const (
+	siocEthtool       = 0x8946
+	ethtoolGChannels  = 0x3c
+	ethtoolGRSSH      = 0x46
+	ethtoolIfNameSize = 16
+)
+
+// ethtoolChannels is struct ethtool_channels of the kernel API
+type ethtoolChannels struct {
+	cmd           uint32
+	maxRx         uint32
+	maxTx         uint32
+	maxOther      uint32
+	maxCombined   uint32
+	rxCount       uint32
+	txCount       uint32
+	otherCount    uint32
+	combinedCount uint32
+}
+
+// ethtoolRxfh is the fixed-size header of struct ethtool_rxfh of the kernel
+// API
+type ethtoolRxfh struct {
+	cmd        uint32
+	rssContext uint32
+	indirSize  uint32
+	keySize    uint32
+	hfunc      uint8
+	inputXfrm  uint8
+	rsvd8      [2]uint8
+	rsvd32     uint32
+}
+
+// ethtoolIfreq is struct ifreq of the kernel API, with a pointer to the
+// ethtool command data
+type ethtoolIfreq struct {
+	name [ethtoolIfNameSize]byte
+	data uintptr
+}
+
+// ethtool runs an ethtool ioctl command on the given network interface. The
+// command and its results are stored in data.
+func ethtool(iface string, data unsafe.Pointer) error {
+	if len(iface) >= ethtoolIfNameSize {
+		return fmt.Errorf("interface name %q too long", iface)
+	}
+	fd, err := syscall.Socket(syscall.AF_INET, syscall.SOCK_DGRAM, 0)
+	if err != nil {
+		return fmt.Errorf("failed to create socket: %w", err)
+	}
+	defer syscall.Close(fd)
+
+	ifr := ethtoolIfreq{data: uintptr(data)}
+	copy(ifr.name[:], iface)
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd), siocEthtool, uintptr(unsafe.Pointer(&ifr))); errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// getChannelInfo returns the current and maximum number of channels (queues)
+// of a network interface.
+func getChannelInfo(iface string) (*channelInfo, error) {
+	ch := ethtoolChannels{cmd: ethtoolGChannels}
+	if err := ethtool(iface, unsafe.Pointer(&ch)); err != nil {
+		return nil, fmt.Errorf("ETHTOOL_GCHANNELS failed: %w", err)
+	}
+	return &channelInfo{
+		rx:          ch.rxCount,
+		maxRx:       ch.maxRx,
+		tx:          ch.txCount,
+		maxTx:       ch.maxTx,
+		combined:    ch.combinedCount,
+		maxCombined: ch.maxCombined,
+	}, nil
+}
+
+// getRSSIndirSize returns the size of the RSS indirection table of a network
+// interface. Zero means that the interface does not support RSS.
+func getRSSIndirSize(iface string) (uint32, error) {
+	rxfh := ethtoolRxfh{cmd: ethtoolGRSSH}
+	if err := ethtool(iface, unsafe.Pointer(&rxfh)); err != nil {
+		if err == syscall.EOPNOTSUPP {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("ETHTOOL_GRSSH failed: %w", err)
+	}
+	return rxfh.indirSize, nil
+}
+
+// getLinkInfo returns the network links of the network namespace nfd-worker
+// is running in, queried over netlink.
+func getLinkInfo() (map[string]linkInfo, error) {
+	data, err := syscall.NetlinkRIB(syscall.RTM_GETLINK, syscall.AF_UNSPEC)
+	if err != nil {
+		return nil, fmt.Errorf("netlink request failed: %w", err)
+	}
+	msgs, err := syscall.ParseNetlinkMessage(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse netlink messages: %w", err)
+	}
+
+	links := make(map[string]linkInfo)
+	for _, m := range msgs {
+		if m.Header.Type != syscall.RTM_NEWLINK || len(m.Data) < syscall.SizeofIfInfomsg {
+			continue
+		}
+		attrs, err := syscall.ParseNetlinkRouteAttr(&m)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse netlink link attributes: %w", err)
+		}
+
+		// Interface index is at offset 4 of struct ifinfomsg
+		l := linkInfo{index: int(int32(binary.NativeEndian.Uint32(m.Data[4:8])))}
+		var name string
+		for _, a := range attrs {
+			switch a.Attr.Type {
+			case syscall.IFLA_IFNAME:
+				name = string(bytes.TrimRight(a.Value, "\x00"))
+			case syscall.IFLA_ADDRESS:
+				l.address = net.HardwareAddr(a.Value).String()
+			case iflaMaxMTU:
+				if len(a.Value) >= 4 {
+					l.maxMTU = binary.NativeEndian.Uint32(a.Value)
+				}
+			}
+		}
+		if name != "" {
+			links[name] = l
+		}
+	}
+	return links, nil
+}
@@ -0,0 +1,34 @@
+//go:build !linux
+// +build !linux
+
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package network
+
+import "fmt"
+
+func getLinkInfo() (map[string]linkInfo, error) {
+	return nil, fmt.Errorf("netlink not supported on this platform")
+}
+
+func getChannelInfo(iface string) (*channelInfo, error) {
+	return nil, fmt.Errorf("ethtool not supported on this platform")
+}
+
+func getRSSIndirSize(iface string) (uint32, error) {
+	return 0, fmt.Errorf("ethtool not supported on this platform")
+}
@@ -0,0 +1,331 @@
+//go:build !windows
+// +build !windows
+
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package network
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"k8s.io/klog/v2"
+
+	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
+	"sigs.k8s.io/node-feature-discovery/pkg/utils/hostpath"
+)
+
+const sysfsBaseDir = "class/net"
+
+// rtfUp is the route flag of usable routes
+const rtfUp = 0x1
+
+var (
+	// devIfaceAttrs is the list of files under /sys/class/net/<iface> that we're reading
+	devIfaceAttrs = []string{"operstate", "speed", "mtu", "device/sriov_numvfs", "device/sriov_totalvfs"}
+
+	// virtualIfaceAttrs is the list of files under /sys/class/net/<iface> that we're reading
+	virtualIfaceAttrs = []string{"operstate", "speed", "mtu"}
+)
+
+func detectNetDevices() ([]nfdv1alpha1.InstanceFeature, []nfdv1alpha1.InstanceFeature, error) {
+	sysfsBasePath := hostpath.SysfsDir.Path(sysfsBaseDir)
+
+	ifaces, err := os.ReadDir(sysfsBasePath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list network interfaces: %w", err)
+	}
+
+	ifaces = slices.DeleteFunc(ifaces, func(iface os.DirEntry) bool {
+		return iface.Name() == "bonding_masters"
+	})
+
+	// Link information is only available if running in the host network namespace
+	links, err := getLinkInfo()
+	if err != nil {
+		klog.V(2).InfoS("failed to get network link information", "error", err)
+	}
+
+	// Iterate over devices
+	devIfacesinfo := make([]nfdv1alpha1.InstanceFeature, 0, len(ifaces))
+	virtualIfacesinfo := make([]nfdv1alpha1.InstanceFeature, 0, len(ifaces))
+
+	for _, iface := range ifaces {
+		name := iface.Name()
+		path := filepath.Join(sysfsBasePath, name)
+		var info nfdv1alpha1.InstanceFeature
+		isDev := false
+		if _, err := os.Stat(filepath.Join(path, "device")); err == nil {
+			isDev = true
+			info = readIfaceInfo(path, devIfaceAttrs)
+			readQueueInfo(path, info.Attributes)
+			devIfacesinfo = append(devIfacesinfo, info)
+		} else {
+			info = readIfaceInfo(path, virtualIfaceAttrs)
+			virtualIfacesinfo = append(virtualIfacesinfo, info)
+		}
+		if l, ok := links[name]; ok && isSameLink(path, l) {
+			if l.maxMTU > 0 {
+				info.Attributes["max_mtu"] = strconv.FormatUint(uint64(l.maxMTU), 10)
+			}
+			if isDev {
+				readEthtoolInfo(name, info.Attributes)
+			}
+		}
+	}
+
+	return devIfacesinfo, virtualIfacesinfo, nil
+}
+
+func readIfaceInfo(path string, attrFiles []string) nfdv1alpha1.InstanceFeature {
+	attrs := map[string]string{"name": filepath.Base(path)}
+	for _, attrFile := range attrFiles {
+		data, err := os.ReadFile(filepath.Join(path, attrFile))
+		if err != nil {
+			if !os.IsNotExist(err) && !errors.Is(err, syscall.EINVAL) {
+				klog.ErrorS(err, "failed to read net iface attribute", "attributeName", attrFile)
+			}
+			continue
+		}
+		attrName := filepath.Base(attrFile)
+		attrs[attrName] = strings.TrimSpace(string(data))
+	}
+
+	return *nfdv1alpha1.NewInstanceFeature(attrs)
+
+}
+
+// readQueueInfo reads the number of RX and TX queues of a network interface
+// and whether Receive Packet Steering (RPS) is enabled on any of the RX
+// queues.
+func readQueueInfo(path string, attrs map[string]string) {
+	entries, err := os.ReadDir(filepath.Join(path, "queues"))
+	if err != nil {
+		if !os.IsNotExist(err) {
+			klog.ErrorS(err, "failed to read net iface queues", "path", path)
+		}
+		return
+	}
+
+	rx, tx := 0, 0
+	rps := false
+	for _, e := range entries {
+		switch name := e.Name(); {
+		case strings.HasPrefix(name, "rx-"):
+			rx++
+			if !rps {
+				data, err := os.ReadFile(filepath.Join(path, "queues", name, "rps_cpus"))
+				rps = err == nil && strings.Trim(strings.TrimSpace(string(data)), "0,") != ""
+			}
+		case strings.HasPrefix(name, "tx-"):
+			tx++
+		}
+	}
+	attrs["rx_queues"] = strconv.Itoa(rx)
+	attrs["tx_queues"] = strconv.Itoa(tx)
+	attrs["rps_enabled"] = strconv.FormatBool(rps)
+}
+
+// readEthtoolInfo queries the channel (queue) counts and Receive Side Scaling
+// (RSS) capability of a network interface with ethtool. Only available if
+// running in the host network namespace.
+func readEthtoolInfo(iface string, attrs map[string]string) {
+	if ch, err := getChannelInfo(iface); err != nil {
+		klog.V(3).InfoS("failed to get network channel information", "interface", iface, "error", err)
+	} else {
+		for _, c := range []struct {
+			name     string
+			cur, max uint32
+		}{
+			{"rx_channels", ch.rx, ch.maxRx},
+			{"tx_channels", ch.tx, ch.maxTx},
+			{"combined_channels", ch.combined, ch.maxCombined},
+		} {
+			if c.max > 0 {
+				attrs[c.name] = strconv.FormatUint(uint64(c.cur), 10)
+				attrs["max_"+c.name] = strconv.FormatUint(uint64(c.max), 10)
+			}
+		}
+	}
+
+	if size, err := getRSSIndirSize(iface); err != nil {
+		klog.V(3).InfoS("failed to get RSS information", "interface", iface, "error", err)
+	} else {
+		attrs["rss_capable"] = strconv.FormatBool(size > 0)
+	}
+}
+
+// isSameLink checks that the link queried over netlink is the same as the
+// one in the (host) sysfs, i.e. that nfd-worker is running in the host network
+// namespace.
+func isSameLink(sysfsPath string, l linkInfo) bool {
+	index, err := os.ReadFile(filepath.Join(sysfsPath, "ifindex"))
+	if err != nil || strings.TrimSpace(string(index)) != strconv.Itoa(l.index) {
+		return false
+	}
+	address, err := os.ReadFile(filepath.Join(sysfsPath, "address"))
+	return err == nil && strings.TrimSpace(string(address)) == l.address
+}
+
+// detectPrimaryIface returns the attributes of the network interface with
+// the default route. The route table of the network namespace nfd-worker is
+// running in is used so detection only succeeds if nfd-worker is running in
+// the host network namespace.
+func detectPrimaryIface(ifaces []nfdv1alpha1.InstanceFeature) *nfdv1alpha1.AttributeFeatureSet {
+	f, err := os.Open("/proc/net/route")
+	if err != nil {
+		klog.V(2).InfoS("failed to read route table", "error", err)
+		return nil
+	}
+	defer f.Close()
+
+	name, err := parseDefaultRouteIface(f)
+	if err != nil {
+		klog.V(2).InfoS("failed to detect primary network interface", "error", err)
+		return nil
+	}
+
+	for _, iface := range ifaces {
+		if iface.Attributes["name"] != name {
+			continue
+		}
+		// Max MTU is only available if nfd-worker is running in the host
+		// network namespace so we use it as an indicator of that, too.
+		maxMTU, ok := iface.Attributes["max_mtu"]
+		if !ok {
+			klog.V(2).InfoS("not running in the host network namespace, primary network interface not detected")
+			return nil
+		}
+		mtu := iface.Attributes["mtu"]
+		jumbo := false
+		if v, err := strconv.Atoi(mtu); err == nil && v > standardMTU {
+			jumbo = true
+		}
+		primary := nfdv1alpha1.NewAttributeFeatures(map[string]string{
+			"name":                 name,
+			"mtu":                  mtu,
+			"max_mtu":              maxMTU,
+			"jumbo_frames_enabled": strconv.FormatBool(jumbo),
+		})
+		return &primary
+	}
+	return nil
+}
+
+// parseDefaultRouteIface returns the interface of the default route (with
+// the lowest metric) from /proc/net/route formatted data.
+func parseDefaultRouteIface(r io.Reader) (string, error) {
+	scanner := bufio.NewScanner(r)
+	iface := ""
+	lowestMetric := -1
+	// Skip the header line
+	scanner.Scan()
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		// Iface Destination Gateway Flags RefCnt Use Metric Mask ...
+		if len(fields) < 8 || fields[1] != "00000000" || fields[7] != "00000000" {
+			continue
+		}
+		flags, err := strconv.ParseUint(fields[3], 16, 32)
+		if err != nil || flags&rtfUp == 0 {
+			continue
+		}
+		metric, err := strconv.Atoi(fields[6])
+		if err != nil {
+			continue
+		}
+		if lowestMetric < 0 || metric < lowestMetric {
+			iface = fields[0]
+			lowestMetric = metric
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	if iface == "" {
+		return "", fmt.Errorf("no default route found")
+	}
+	return iface, nil
+}
+
+// detectSriovVFs returns the SR-IOV virtual functions of the given physical
+// network devices.
+func detectSriovVFs(devs []nfdv1alpha1.InstanceFeature) []sriovVF {
+	sysfsBasePath := hostpath.SysfsDir.Path(sysfsBaseDir)
+
+	vfs := []sriovVF{}
+	for _, dev := range devs {
+		if n, err := strconv.Atoi(dev.Attributes["sriov_numvfs"]); err != nil || n == 0 {
+			continue
+		}
+		pfName := dev.Attributes["name"]
+		devPath := filepath.Join(sysfsBasePath, pfName, "device")
+		pfAddr, err := os.Readlink(devPath)
+		if err != nil {
+			klog.ErrorS(err, "failed to read PCI address of network device", "deviceName", pfName)
+			continue
+		}
+
+		links, err := filepath.Glob(filepath.Join(devPath, "virtfn*"))
+		if err != nil {
+			continue
+		}
+		for _, l := range links {
+			index, err := strconv.Atoi(strings.TrimPrefix(filepath.Base(l), "virtfn"))
+			if err != nil {
+				continue
+			}
+			addr, err := os.Readlink(l)
+			if err != nil {
+				klog.ErrorS(err, "failed to read PCI address of SR-IOV VF", "deviceName", pfName, "vfIndex", index)
+				continue
+			}
+			vf := sriovVF{
+				pfName:     pfName,
+				index:      index,
+				pciAddress: filepath.Base(addr),
+				rootDevice: filepath.Base(pfAddr),
+				vendor:     readPciID(filepath.Join(l, "vendor")),
+				device:     readPciID(filepath.Join(l, "device")),
+			}
+			// The driver link does not exist if no driver is bound
+			if driver, err := os.Readlink(filepath.Join(l, "driver")); err == nil {
+				vf.driver = filepath.Base(driver)
+			}
+			vfs = append(vfs, vf)
+		}
+	}
+	return vfs
+}
+
+// readPciID reads a PCI vendor or device ID from sysfs, without the 0x
+// prefix.
+func readPciID(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(strings.TrimSpace(string(data)), "0x")
+}
@@ -0,0 +1,110 @@
+//go:build !windows
+// +build !windows
+
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package network
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
+	"sigs.k8s.io/node-feature-discovery/pkg/utils/hostpath"
+)
+
+func TestParseDefaultRouteIface(t *testing.T) {
+	routes := `Iface	Destination	Gateway 	Flags	RefCnt	Use	Metric	Mask		MTU	Window	IRTT
+eth1	00000000	0102A8C0	0003	0	0	200	00000000	0	0	0
+eth0	00000000	0101A8C0	0003	0	0	100	00000000	0	0	0
+eth0	0001A8C0	00000000	0001	0	0	100	00FFFFFF	0	0	0
+`
+	iface, err := parseDefaultRouteIface(strings.NewReader(routes))
+	assert.Nil(t, err, err)
+	assert.Equal(t, "eth0", iface)
+
+	_, err = parseDefaultRouteIface(strings.NewReader(strings.Join(strings.Split(routes, "\n")[3:], "\n")))
+	assert.NotNil(t, err)
+}
+
+func TestReadQueueInfo(t *testing.T) {
+	path := t.TempDir()
+	writeFile := func(name, content string) {
+		assert.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(path, name)), 0755))
+		assert.NoError(t, os.WriteFile(filepath.Join(path, name), []byte(content), 0644))
+	}
+
+	// No queues directory
+	attrs := map[string]string{}
+	readQueueInfo(path, attrs)
+	assert.Empty(t, attrs)
+
+	writeFile("queues/rx-0/rps_cpus", "00000000,00000000\n")
+	writeFile("queues/rx-1/rps_cpus", "00000000,00000000\n")
+	writeFile("queues/tx-0/xps_cpus", "00000000,00000001\n")
+	readQueueInfo(path, attrs)
+	assert.Equal(t, map[string]string{"rx_queues": "2", "tx_queues": "1", "rps_enabled": "false"}, attrs)
+
+	writeFile("queues/rx-1/rps_cpus", "00000000,0000000f\n")
+	readQueueInfo(path, attrs)
+	assert.Equal(t, "true", attrs["rps_enabled"])
+}
+
+func TestDetectSriovVFs(t *testing.T) {
+	root := t.TempDir()
+	origSysfsDir := hostpath.SysfsDir
+	hostpath.SysfsDir = hostpath.HostDir(root)
+	defer func() { hostpath.SysfsDir = origSysfsDir }()
+
+	mkdir := func(name string) {
+		assert.NoError(t, os.MkdirAll(filepath.Join(root, name), 0755))
+	}
+	writeFile := func(name, content string) {
+		assert.NoError(t, os.WriteFile(filepath.Join(root, name), []byte(content), 0644))
+	}
+	symlink := func(target, name string) {
+		assert.NoError(t, os.Symlink(target, filepath.Join(root, name)))
+	}
+
+	mkdir("devices/pci0000:3a/0000:3b:00.0")
+	mkdir("devices/pci0000:3a/0000:3b:02.0")
+	mkdir("bus/pci/drivers/iavf")
+	writeFile("devices/pci0000:3a/0000:3b:02.0/vendor", "0x8086\n")
+	writeFile("devices/pci0000:3a/0000:3b:02.0/device", "0x154c\n")
+	symlink("../../../bus/pci/drivers/iavf", "devices/pci0000:3a/0000:3b:02.0/driver")
+	symlink("../0000:3b:02.0", "devices/pci0000:3a/0000:3b:00.0/virtfn0")
+	mkdir("class/net/eth0")
+	symlink("../../../devices/pci0000:3a/0000:3b:00.0", "class/net/eth0/device")
+
+	devs := []nfdv1alpha1.InstanceFeature{
+		*nfdv1alpha1.NewInstanceFeature(map[string]string{"name": "eth0", "sriov_numvfs": "1"}),
+		*nfdv1alpha1.NewInstanceFeature(map[string]string{"name": "eth1", "sriov_numvfs": "0"}),
+	}
+	assert.Equal(t, []sriovVF{{
+		pfName:     "eth0",
+		index:      0,
+		pciAddress: "0000:3b:02.0",
+		rootDevice: "0000:3b:00.0",
+		vendor:     "8086",
+		device:     "154c",
+		driver:     "iavf",
+	}}, detectSriovVFs(devs))
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package network
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+	"k8s.io/klog/v2"
+
+	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
+)
+
+// ifHardwareInterface is the flag of MIB_IF_ROW2 indicating that the network
+// interface is attached to a physical network adapter.
+const ifHardwareInterface = 0x1
+
+// operStates maps the operational status of a network interface to the
+// operstate values used in the Linux sysfs.
+var operStates = map[uint32]string{
+	windows.IfOperStatusUp:             "up",
+	windows.IfOperStatusDown:           "down",
+	windows.IfOperStatusTesting:        "testing",
+	windows.IfOperStatusUnknown:        "unknown",
+	windows.IfOperStatusDormant:        "dormant",
+	windows.IfOperStatusNotPresent:     "notpresent",
+	windows.IfOperStatusLowerLayerDown: "lowerlayerdown",
+}
+
+func detectNetDevices() ([]nfdv1alpha1.InstanceFeature, []nfdv1alpha1.InstanceFeature, error) {
+	adapters, err := getAdapters()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list network interfaces: %w", err)
+	}
+
+	devIfacesinfo := make([]nfdv1alpha1.InstanceFeature, 0, len(adapters))
+	virtualIfacesinfo := make([]nfdv1alpha1.InstanceFeature, 0, len(adapters))
+
+	for _, a := range adapters {
+		attrs := map[string]string{
+			"name": windows.UTF16PtrToString(a.FriendlyName),
+			"mtu":  strconv.FormatUint(uint64(a.Mtu), 10),
+		}
+		if s, ok := operStates[a.OperStatus]; ok {
+			attrs["operstate"] = s
+		}
+		// Speed is in Mbps, same as in the Linux sysfs
+		if a.TransmitLinkSpeed != math.MaxUint64 && a.TransmitLinkSpeed > 0 {
+			attrs["speed"] = strconv.FormatUint(a.TransmitLinkSpeed/1000000, 10)
+		}
+
+		info := *nfdv1alpha1.NewInstanceFeature(attrs)
+		if isHardwareInterface(a) {
+			devIfacesinfo = append(devIfacesinfo, info)
+		} else {
+			virtualIfacesinfo = append(virtualIfacesinfo, info)
+		}
+	}
+
+	return devIfacesinfo, virtualIfacesinfo, nil
+}
+
+// isHardwareInterface checks if a network interface is attached to a
+// physical network adapter.
+func isHardwareInterface(a *windows.IpAdapterAddresses) bool {
+	row := windows.MibIfRow2{InterfaceLuid: a.Luid}
+	if err := windows.GetIfEntry2Ex(windows.MibIfEntryNormalWithoutStatistics, &row); err != nil {
+		klog.V(3).InfoS("failed to get network interface information", "interface", windows.UTF16PtrToString(a.FriendlyName), "error", err)
+		return false
+	}
+	return row.InterfaceAndOperStatusFlags&ifHardwareInterface != 0
+}
+
+// detectPrimaryIface returns the attributes of the network interface with
+// the default route (with the lowest metric).
+func detectPrimaryIface(ifaces []nfdv1alpha1.InstanceFeature) *nfdv1alpha1.AttributeFeatureSet {
+	adapters, err := getAdapters()
+	if err != nil {
+		klog.V(2).InfoS("failed to list network interfaces", "error", err)
+		return nil
+	}
+
+	var primary *windows.IpAdapterAddresses
+	for _, a := range adapters {
+		if a.FirstGatewayAddress == nil || a.OperStatus != windows.IfOperStatusUp {
+			continue
+		}
+		if primary == nil || a.Ipv4Metric < primary.Ipv4Metric {
+			primary = a
+		}
+	}
+	if primary == nil {
+		klog.V(2).InfoS("failed to detect primary network interface", "error", "no default route found")
+		return nil
+	}
+
+	name := windows.UTF16PtrToString(primary.FriendlyName)
+	for _, iface := range ifaces {
+		if iface.Attributes["name"] != name {
+			continue
+		}
+		mtu := iface.Attributes["mtu"]
+		jumbo := false
+		if v, err := strconv.Atoi(mtu); err == nil && v > standardMTU {
+			jumbo = true
+		}
+		attrs := nfdv1alpha1.NewAttributeFeatures(map[string]string{
+			"name":                 name,
+			"mtu":                  mtu,
+			"jumbo_frames_enabled": strconv.FormatBool(jumbo),
+		})
+		return &attrs
+	}
+	return nil
+}
+
+// getAdapters returns the network adapters of the system, including the
+// default gateways.
+func getAdapters() ([]*windows.IpAdapterAddresses, error) {
+	size := uint32(15000)
+	for {
+		buf := make([]byte, size)
+		first := (*windows.IpAdapterAddresses)(unsafe.Pointer(&buf[0]))
+		err := windows.GetAdaptersAddresses(windows.AF_UNSPEC, windows.GAA_FLAG_INCLUDE_GATEWAYS, 0, first, &size)
+		if errors.Is(err, windows.ERROR_BUFFER_OVERFLOW) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		adapters := []*windows.IpAdapterAddresses{}
+		for a := first; a != nil; a = a.Next {
+			adapters = append(adapters, a)
+		}
+		return adapters, nil
+	}
+}
+
+// detectSriovVFs returns the SR-IOV virtual functions of the given physical
+// network devices. Not supported on Windows.
+func detectSriovVFs(devs []nfdv1alpha1.InstanceFeature) []sriovVF {
+	return nil
+}
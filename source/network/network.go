@@ -17,14 +17,8 @@ limitations under the License.
 package network
 
 import (
-	"errors"
 	"fmt"
-	"os"
-	"path/filepath"
-	"slices"
 	"strconv"
-	"strings"
-	"syscall"
 
 	"k8s.io/klog/v2"
 
@@ -42,36 +36,87 @@ const (
 	DeviceFeature = "device"
 	// VirtualFeature exposes features for network interfaces that are not attached to a physical device
 	VirtualFeature = "virtual"
+	// PrimaryFeature exposes features of the network interface with the default route
+	PrimaryFeature = "primary"
+	// SriovDpResourceFeature exposes the resources configured in the SR-IOV network device plugin
+	SriovDpResourceFeature = "sriov_dp_resource"
 )
 
-const sysfsBaseDir = "class/net"
+// Config contains the configuration parameters of this source.
+type Config struct {
+	// SriovDevicePluginConfig is the path of the configuration file of the
+	// SR-IOV network device plugin. Empty disables the detection.
+	SriovDevicePluginConfig string `json:"sriovDevicePluginConfig,omitempty"`
+}
+
+// newDefaultConfig returns a new config with pre-populated defaults
+func newDefaultConfig() *Config {
+	return &Config{
+		SriovDevicePluginConfig: hostpath.EtcDir.Path("pcidp/config.json"),
+	}
+}
+
+// standardMTU is the largest MTU of a standard (non-jumbo) ethernet frame
+const standardMTU = 1500
+
+// linkInfo contains information about a network link that is not available
+// in sysfs.
+type linkInfo struct {
+	index   int
+	address string
+	maxMTU  uint32
+}
 
-// networkSource implements the FeatureSource and LabelSource interfaces.
+// channelInfo contains the current and maximum number of channels (queues)
+// of a network interface, as reported by ethtool.
+type channelInfo struct {
+	rx, maxRx             uint32
+	tx, maxTx             uint32
+	combined, maxCombined uint32
+}
+
+// networkSource implements the FeatureSource, LabelSource and ConfigurableSource interfaces.
 type networkSource struct {
+	config   *Config
 	features *nfdv1alpha1.Features
 }
 
 // Singleton source instance
 var (
-	src networkSource
-	_   source.FeatureSource = &src
-	_   source.LabelSource   = &src
-)
-
-var (
-	// devIfaceAttrs is the list of files under /sys/class/net/<iface> that we're reading
-	devIfaceAttrs = []string{"operstate", "speed", "device/sriov_numvfs", "device/sriov_totalvfs"}
-
-	// virtualIfaceAttrs is the list of files under /sys/class/net/<iface> that we're reading
-	virtualIfaceAttrs = []string{"operstate", "speed"}
+	src                           = networkSource{config: newDefaultConfig()}
+	_   source.FeatureSource      = &src
+	_   source.LabelSource        = &src
+	_   source.ConfigurableSource = &src
+	_   source.HostPathSource     = &src
 )
 
 // Name returns an identifier string for this feature source.
 func (s *networkSource) Name() string { return Name }
 
+// NewConfig method of the LabelSource interface
+func (s *networkSource) NewConfig() source.Config { return newDefaultConfig() }
+
+// GetConfig method of the LabelSource interface
+func (s *networkSource) GetConfig() source.Config { return s.config }
+
+// SetConfig method of the LabelSource interface
+func (s *networkSource) SetConfig(conf source.Config) {
+	switch v := conf.(type) {
+	case *Config:
+		s.config = v
+	default:
+		panic(fmt.Sprintf("invalid config type: %T", conf))
+	}
+}
+
 // Priority method of the LabelSource interface
 func (s *networkSource) Priority() int { return 0 }
 
+// RequiredHostPaths method of the HostPathSource interface
+func (s *networkSource) RequiredHostPaths() []string {
+	return []string{hostpath.SysfsDir.Path("class/net")}
+}
+
 // GetLabels method of the LabelSource interface
 func (s *networkSource) GetLabels() (source.FeatureLabels, error) {
 	labels := source.FeatureLabels{}
@@ -95,6 +140,15 @@ func (s *networkSource) GetLabels() (source.FeatureLabels, error) {
 			}
 		}
 	}
+
+	if primary, ok := features.Attributes[PrimaryFeature]; ok {
+		if mtu, err := strconv.Atoi(primary.Elements["mtu"]); err == nil && mtu > standardMTU {
+			labels["jumbo_frames.enabled"] = true
+		}
+		if maxMTU, err := strconv.Atoi(primary.Elements["max_mtu"]); err == nil && maxMTU > standardMTU {
+			labels["jumbo_frames.capable"] = true
+		}
+	}
 	return labels, nil
 }
 
@@ -109,7 +163,19 @@ func (s *networkSource) Discover() error {
 	s.features.Instances[DeviceFeature] = nfdv1alpha1.InstanceFeatureSet{Elements: devs}
 	s.features.Instances[VirtualFeature] = nfdv1alpha1.InstanceFeatureSet{Elements: virts}
 
-	klog.V(3).InfoS("discovered features", "featureSource", s.Name(), "features", utils.DelayedDumper(s.features))
+	if primary := detectPrimaryIface(append(devs, virts...)); primary != nil {
+		s.features.Attributes[PrimaryFeature] = *primary
+	}
+
+	if path := s.config.SriovDevicePluginConfig; path != "" {
+		resources, err := detectSriovDpResources(path, detectSriovVFs(devs))
+		if err != nil {
+			klog.ErrorS(err, "failed to detect SR-IOV network device plugin resources")
+		}
+		s.features.Instances[SriovDpResourceFeature] = nfdv1alpha1.InstanceFeatureSet{Elements: resources}
+	}
+
+	source.LogDiscoveredFeatures(s.Name(), "features", utils.DelayedDumper(s.features))
 
 	return nil
 }
@@ -122,52 +188,6 @@ func (s *networkSource) GetFeatures() *nfdv1alpha1.Features {
 	return s.features
 }
 
-func detectNetDevices() ([]nfdv1alpha1.InstanceFeature, []nfdv1alpha1.InstanceFeature, error) {
-	sysfsBasePath := hostpath.SysfsDir.Path(sysfsBaseDir)
-
-	ifaces, err := os.ReadDir(sysfsBasePath)
-	if err != nil {
-		return nil, nil, fmt.Errorf("failed to list network interfaces: %w", err)
-	}
-
-	ifaces = slices.DeleteFunc(ifaces, func(iface os.DirEntry) bool {
-		return iface.Name() == "bonding_masters"
-	})
-
-	// Iterate over devices
-	devIfacesinfo := make([]nfdv1alpha1.InstanceFeature, 0, len(ifaces))
-	virtualIfacesinfo := make([]nfdv1alpha1.InstanceFeature, 0, len(ifaces))
-
-	for _, iface := range ifaces {
-		name := iface.Name()
-		if _, err := os.Stat(filepath.Join(sysfsBasePath, name, "device")); err == nil {
-			devIfacesinfo = append(devIfacesinfo, readIfaceInfo(filepath.Join(sysfsBasePath, name), devIfaceAttrs))
-		} else {
-			virtualIfacesinfo = append(virtualIfacesinfo, readIfaceInfo(filepath.Join(sysfsBasePath, name), virtualIfaceAttrs))
-		}
-	}
-
-	return devIfacesinfo, virtualIfacesinfo, nil
-}
-
-func readIfaceInfo(path string, attrFiles []string) nfdv1alpha1.InstanceFeature {
-	attrs := map[string]string{"name": filepath.Base(path)}
-	for _, attrFile := range attrFiles {
-		data, err := os.ReadFile(filepath.Join(path, attrFile))
-		if err != nil {
-			if !os.IsNotExist(err) && !errors.Is(err, syscall.EINVAL) {
-				klog.ErrorS(err, "failed to read net iface attribute", "attributeName", attrFile)
-			}
-			continue
-		}
-		attrName := filepath.Base(attrFile)
-		attrs[attrName] = strings.TrimSpace(string(data))
-	}
-
-	return *nfdv1alpha1.NewInstanceFeature(attrs)
-
-}
-
 func init() {
 	source.Register(&src)
 }
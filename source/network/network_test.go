@@ -20,6 +20,9 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
+	"sigs.k8s.io/node-feature-discovery/source"
 )
 
 func TestNetworkSource(t *testing.T) {
@@ -33,3 +36,17 @@ func TestNetworkSource(t *testing.T) {
 	assert.Empty(t, l)
 
 }
+
+func TestJumboFrameLabels(t *testing.T) {
+	src.features = nfdv1alpha1.NewFeatures()
+	src.features.Attributes[PrimaryFeature] = nfdv1alpha1.NewAttributeFeatures(map[string]string{"name": "eth0", "mtu": "9000", "max_mtu": "9216"})
+	l, err := src.GetLabels()
+	assert.Nil(t, err, err)
+	assert.Equal(t, source.FeatureLabels{"jumbo_frames.enabled": true, "jumbo_frames.capable": true}, l)
+
+	src.features.Attributes[PrimaryFeature] = nfdv1alpha1.NewAttributeFeatures(map[string]string{"name": "eth0", "mtu": "1500", "max_mtu": "9216"})
+	l, err = src.GetLabels()
+	assert.Nil(t, err, err)
+	assert.Equal(t, source.FeatureLabels{"jumbo_frames.capable": true}, l)
+	src.features = nil
+}
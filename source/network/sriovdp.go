@@ -0,0 +1,193 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package network
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"k8s.io/klog/v2"
+
+	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
+)
+
+// sriovDpDefaultResourcePrefix is the default resource prefix of the SR-IOV
+// network device plugin
+const sriovDpDefaultResourcePrefix = "intel.com"
+
+// sriovDpNetDevice is the device type of network device resources of the
+// SR-IOV network device plugin
+const sriovDpNetDevice = "netDevice"
+
+// sriovDpConfig is the part of the SR-IOV network device plugin configuration
+// file relevant for us.
+type sriovDpConfig struct {
+	ResourceList []sriovDpResource `json:"resourceList"`
+}
+
+// sriovDpResource is one resource of the SR-IOV network device plugin
+// configuration.
+type sriovDpResource struct {
+	ResourceName   string `json:"resourceName"`
+	ResourcePrefix string `json:"resourcePrefix,omitempty"`
+	DeviceType     string `json:"deviceType,omitempty"`
+	// Selectors is either a single selector object or a list of them
+	Selectors json.RawMessage `json:"selectors,omitempty"`
+}
+
+// sriovDpSelector is the subset of the device selectors of the SR-IOV network
+// device plugin that can be evaluated against the VFs found in sysfs.
+type sriovDpSelector struct {
+	Vendors      []string `json:"vendors,omitempty"`
+	Devices      []string `json:"devices,omitempty"`
+	Drivers      []string `json:"drivers,omitempty"`
+	PciAddresses []string `json:"pciAddresses,omitempty"`
+	PfNames      []string `json:"pfNames,omitempty"`
+	RootDevices  []string `json:"rootDevices,omitempty"`
+}
+
+// sriovVF describes one SR-IOV virtual function of a network device.
+type sriovVF struct {
+	pfName     string
+	index      int
+	pciAddress string
+	rootDevice string
+	vendor     string
+	device     string
+	driver     string
+}
+
+// detectSriovDpResources reads the SR-IOV network device plugin configuration
+// file and returns the network device resources configured in it, together
+// with the number of VFs on the node matching the selectors of each
+// resource. Nothing is returned if the file does not exist.
+func detectSriovDpResources(path string, vfs []sriovVF) ([]nfdv1alpha1.InstanceFeature, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		klog.V(2).InfoS("SR-IOV network device plugin configuration not available", "path", path)
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	config := sriovDpConfig{}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse SR-IOV network device plugin configuration %q: %w", path, err)
+	}
+
+	resources := make([]nfdv1alpha1.InstanceFeature, 0, len(config.ResourceList))
+	for _, r := range config.ResourceList {
+		if r.DeviceType != "" && r.DeviceType != sriovDpNetDevice {
+			continue
+		}
+		selectors, err := r.selectors()
+		if err != nil {
+			klog.ErrorS(err, "invalid selectors in SR-IOV network device plugin configuration", "resourceName", r.ResourceName)
+			continue
+		}
+
+		prefix := r.ResourcePrefix
+		if prefix == "" {
+			prefix = sriovDpDefaultResourcePrefix
+		}
+		count := 0
+		for _, vf := range vfs {
+			if slices.ContainsFunc(selectors, vf.matches) {
+				count++
+			}
+		}
+		resources = append(resources, *nfdv1alpha1.NewInstanceFeature(map[string]string{
+			"name":        prefix + "/" + r.ResourceName,
+			"device_type": sriovDpNetDevice,
+			"vfs":         strconv.Itoa(count),
+		}))
+	}
+	return resources, nil
+}
+
+// selectors returns the device selectors of the resource. A device matching
+// any of the selectors belongs to the resource.
+func (r sriovDpResource) selectors() ([]sriovDpSelector, error) {
+	if len(r.Selectors) == 0 {
+		return []sriovDpSelector{{}}, nil
+	}
+	if strings.HasPrefix(strings.TrimSpace(string(r.Selectors)), "[") {
+		selectors := []sriovDpSelector{}
+		err := json.Unmarshal(r.Selectors, &selectors)
+		return selectors, err
+	}
+	selector := sriovDpSelector{}
+	err := json.Unmarshal(r.Selectors, &selector)
+	return []sriovDpSelector{selector}, err
+}
+
+// matches returns true if the VF matches all the criteria of the selector.
+func (vf sriovVF) matches(s sriovDpSelector) bool {
+	if len(s.Vendors) > 0 && !slices.Contains(s.Vendors, vf.vendor) {
+		return false
+	}
+	if len(s.Devices) > 0 && !slices.Contains(s.Devices, vf.device) {
+		return false
+	}
+	if len(s.Drivers) > 0 && !slices.Contains(s.Drivers, vf.driver) {
+		return false
+	}
+	if len(s.PciAddresses) > 0 && !slices.Contains(s.PciAddresses, vf.pciAddress) {
+		return false
+	}
+	if len(s.RootDevices) > 0 && !slices.Contains(s.RootDevices, vf.rootDevice) {
+		return false
+	}
+	if len(s.PfNames) > 0 && !slices.ContainsFunc(s.PfNames, vf.matchesPfName) {
+		return false
+	}
+	return true
+}
+
+// matchesPfName checks the VF against one pfNames selector, i.e. a PF name
+// optionally followed by VF ranges, e.g. "eth0#0-3,6".
+func (vf sriovVF) matchesPfName(pfName string) bool {
+	name, ranges, found := strings.Cut(pfName, "#")
+	if name != vf.pfName {
+		return false
+	}
+	if !found {
+		return true
+	}
+	for _, r := range strings.Split(ranges, ",") {
+		first, last, isRange := strings.Cut(r, "-")
+		if !isRange {
+			last = first
+		}
+		f, err1 := strconv.Atoi(strings.TrimSpace(first))
+		l, err2 := strconv.Atoi(strings.TrimSpace(last))
+		if err1 != nil || err2 != nil {
+			klog.V(2).InfoS("invalid VF range in pfNames selector", "pfName", pfName)
+			continue
+		}
+		if vf.index >= f && vf.index <= l {
+			return true
+		}
+	}
+	return false
+}
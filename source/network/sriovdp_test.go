@@ -0,0 +1,92 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package network
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
+)
+
+func TestDetectSriovDpResources(t *testing.T) {
+	vfs := []sriovVF{
+		{pfName: "eth0", index: 0, pciAddress: "0000:3b:02.0", rootDevice: "0000:3b:00.0", vendor: "8086", device: "154c", driver: "iavf"},
+		{pfName: "eth0", index: 1, pciAddress: "0000:3b:02.1", rootDevice: "0000:3b:00.0", vendor: "8086", device: "154c", driver: "vfio-pci"},
+		{pfName: "eth0", index: 2, pciAddress: "0000:3b:02.2", rootDevice: "0000:3b:00.0", vendor: "8086", device: "154c", driver: "vfio-pci"},
+		{pfName: "eth1", index: 0, pciAddress: "0000:5e:00.2", rootDevice: "0000:5e:00.0", vendor: "15b3", device: "1018", driver: "mlx5_core"},
+	}
+
+	config := `{
+  "resourceList": [
+    {
+      "resourceName": "intel_sriov_netdevice",
+      "selectors": {"vendors": ["8086"], "drivers": ["iavf"]}
+    },
+    {
+      "resourceName": "intel_sriov_dpdk",
+      "resourcePrefix": "example.com",
+      "selectors": [{"pfNames": ["eth0#1-2"], "drivers": ["vfio-pci"]}, {"rootDevices": ["0000:5e:00.0"]}]
+    },
+    {
+      "resourceName": "mlnx_missing",
+      "deviceType": "netDevice",
+      "selectors": {"pfNames": ["eth2"]}
+    },
+    {
+      "resourceName": "intel_fpga",
+      "deviceType": "accelerator",
+      "selectors": {"vendors": ["8086"]}
+    }
+  ]
+}`
+	path := filepath.Join(t.TempDir(), "config.json")
+	assert.NoError(t, os.WriteFile(path, []byte(config), 0644))
+
+	resources, err := detectSriovDpResources(path, vfs)
+	assert.NoError(t, err)
+	assert.Equal(t, []nfdv1alpha1.InstanceFeature{
+		{Attributes: map[string]string{"name": "intel.com/intel_sriov_netdevice", "device_type": "netDevice", "vfs": "1"}},
+		{Attributes: map[string]string{"name": "example.com/intel_sriov_dpdk", "device_type": "netDevice", "vfs": "3"}},
+		{Attributes: map[string]string{"name": "intel.com/mlnx_missing", "device_type": "netDevice", "vfs": "0"}},
+	}, resources)
+
+	// Missing configuration file
+	resources, err = detectSriovDpResources(filepath.Join(t.TempDir(), "config.json"), vfs)
+	assert.NoError(t, err)
+	assert.Nil(t, resources)
+
+	// Invalid configuration file
+	assert.NoError(t, os.WriteFile(path, []byte("resourceList: []"), 0644))
+	_, err = detectSriovDpResources(path, vfs)
+	assert.Error(t, err)
+}
+
+func TestMatchesPfName(t *testing.T) {
+	vf := sriovVF{pfName: "eth0", index: 3}
+	assert.True(t, vf.matchesPfName("eth0"))
+	assert.True(t, vf.matchesPfName("eth0#0-3"))
+	assert.True(t, vf.matchesPfName("eth0#1,3"))
+	assert.True(t, vf.matchesPfName("eth0#0,2-5"))
+	assert.False(t, vf.matchesPfName("eth0#0-2"))
+	assert.False(t, vf.matchesPfName("eth0#4-7,1"))
+	assert.False(t, vf.matchesPfName("eth1"))
+	assert.False(t, vf.matchesPfName("eth0#x"))
+}
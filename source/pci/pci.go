@@ -25,6 +25,7 @@ import (
 
 	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
 	"sigs.k8s.io/node-feature-discovery/pkg/utils"
+	"sigs.k8s.io/node-feature-discovery/pkg/utils/hostpath"
 	"sigs.k8s.io/node-feature-discovery/source"
 )
 
@@ -60,6 +61,7 @@ var (
 	_   source.FeatureSource      = &src
 	_   source.LabelSource        = &src
 	_   source.ConfigurableSource = &src
+	_   source.HostPathSource     = &src
 )
 
 // Name returns the name of the feature source
@@ -84,6 +86,11 @@ func (s *pciSource) SetConfig(conf source.Config) {
 // Priority method of the LabelSource interface
 func (s *pciSource) Priority() int { return 0 }
 
+// RequiredHostPaths method of the HostPathSource interface
+func (s *pciSource) RequiredHostPaths() []string {
+	return []string{hostpath.SysfsDir.Path("bus/pci/devices")}
+}
+
 // GetLabels method of the LabelSource interface
 func (s *pciSource) GetLabels() (source.FeatureLabels, error) {
 	labels := source.FeatureLabels{}
@@ -145,7 +152,7 @@ func (s *pciSource) Discover() error {
 	}
 	s.features.Instances[DeviceFeature] = nfdv1alpha1.NewInstanceFeatures(devs...)
 
-	klog.V(3).InfoS("discovered features", "featureSource", s.Name(), "features", utils.DelayedDumper(s.features))
+	source.LogDiscoveredFeatures(s.Name(), "features", utils.DelayedDumper(s.features))
 
 	return nil
 }
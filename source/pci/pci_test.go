@@ -17,6 +17,7 @@ limitations under the License.
 package pci
 
 import (
+	"os"
 	"path/filepath"
 	"testing"
 
@@ -99,11 +100,16 @@ func TestPciSource(t *testing.T) {
 						},
 						{
 							Attributes: map[string]string{
-								"class":            "0604",
-								"device":           "a193",
-								"subsystem_device": "35cf",
-								"subsystem_vendor": "8086",
-								"vendor":           "8086",
+								"class":               "0604",
+								"current_link_speed":  "5",
+								"current_link_width":  "1",
+								"device":              "a193",
+								"link_width_degraded": "false",
+								"max_link_speed":      "8",
+								"max_link_width":      "1",
+								"subsystem_device":    "35cf",
+								"subsystem_vendor":    "8086",
+								"vendor":              "8086",
 							},
 						},
 						{
@@ -127,9 +133,14 @@ func TestPciSource(t *testing.T) {
 						{
 							Attributes: map[string]string{
 								"class":                     "0b40",
+								"current_link_speed":        "5",
+								"current_link_width":        "16",
 								"device":                    "37c8",
 								"iommu/intel-iommu/version": "1:0",
 								"iommu_group/type":          "identity",
+								"link_width_degraded":       "false",
+								"max_link_speed":            "5",
+								"max_link_width":            "16",
 								"sriov_totalvfs":            "16",
 								"subsystem_device":          "35cf",
 								"subsystem_vendor":          "8086",
@@ -138,12 +149,17 @@ func TestPciSource(t *testing.T) {
 						},
 						{
 							Attributes: map[string]string{
-								"class":            "0200",
-								"device":           "37d2",
-								"sriov_totalvfs":   "32",
-								"subsystem_device": "35cf",
-								"subsystem_vendor": "8086",
-								"vendor":           "8086",
+								"class":               "0200",
+								"current_link_speed":  "2.5",
+								"current_link_width":  "1",
+								"device":              "37d2",
+								"link_width_degraded": "false",
+								"max_link_speed":      "2.5",
+								"max_link_width":      "1",
+								"sriov_totalvfs":      "32",
+								"subsystem_device":    "35cf",
+								"subsystem_vendor":    "8086",
+								"vendor":              "8086",
 							},
 						},
 					},
@@ -252,3 +268,68 @@ func TestPciSource(t *testing.T) {
 		})
 	}
 }
+
+func TestReadPcieLinkInfo(t *testing.T) {
+	devPath := t.TempDir()
+	writeAttr := func(name string, data []byte) {
+		if err := os.WriteFile(filepath.Join(devPath, name), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeAttr("current_link_speed", []byte("8.0 GT/s PCIe\n"))
+	writeAttr("max_link_speed", []byte("16.0 GT/s PCIe\n"))
+	writeAttr("current_link_width", []byte("8\n"))
+	writeAttr("max_link_width", []byte("16\n"))
+
+	// Unprivileged read of the config space, capabilities are not visible
+	cfg := make([]byte, 256)
+	cfg[pciStatusReg] = pciStatusCapList
+	cfg[pciCapPointerReg] = 0x40
+	writeAttr("config", cfg[:64])
+
+	expected := map[string]string{
+		"current_link_speed":  "8",
+		"current_link_width":  "8",
+		"link_width_degraded": "true",
+		"max_link_speed":      "16",
+		"max_link_width":      "16",
+	}
+	assert.Equal(t, expected, readPcieLinkInfo(devPath))
+
+	// Power management capability at 0x40, followed by the PCI Express
+	// capability at 0x50 with 512 bytes max payload size supported and
+	// 256 bytes configured
+	cfg[0x40] = 0x01
+	cfg[0x41] = 0x50
+	cfg[0x50] = pciCapIDExp
+	cfg[0x50+pciExpDevCapReg] = 0x02
+	cfg[0x50+pciExpDevCtlReg] = 0x01 << 5
+	writeAttr("config", cfg)
+
+	expected["max_payload_size"] = "256"
+	expected["max_payload_size_supported"] = "512"
+	assert.Equal(t, expected, readPcieLinkInfo(devPath))
+}
+
+func TestReadPciAuthInfo(t *testing.T) {
+	devPath := t.TempDir()
+	writeAttr := func(name, val string) {
+		if err := os.WriteFile(filepath.Join(devPath, name), []byte(val+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Attributes not supported by the kernel
+	assert.Empty(t, readPciAuthInfo(devPath))
+
+	writeAttr("authorized", "0")
+	writeAttr("removable", "removable")
+	assert.Equal(t, map[string]string{"authorized": "0", "removable": "true"}, readPciAuthInfo(devPath))
+
+	writeAttr("authorized", "1")
+	writeAttr("removable", "fixed")
+	assert.Equal(t, map[string]string{"authorized": "1", "removable": "false"}, readPciAuthInfo(devPath))
+
+	writeAttr("removable", "unknown")
+	assert.Equal(t, map[string]string{"authorized": "1"}, readPciAuthInfo(devPath))
+}
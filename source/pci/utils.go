@@ -17,20 +17,40 @@ limitations under the License.
 package pci
 
 import (
+	"encoding/binary"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"k8s.io/klog/v2"
 
 	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
+	"sigs.k8s.io/node-feature-discovery/pkg/utils"
 	"sigs.k8s.io/node-feature-discovery/pkg/utils/hostpath"
 )
 
 var mandatoryDevAttrs = []string{"class", "vendor", "device", "subsystem_vendor", "subsystem_device"}
 var optionalDevAttrs = []string{"sriov_totalvfs", "iommu_group/type", "iommu/intel-iommu/version"}
 
+const (
+	// Offsets and IDs in the PCI configuration space
+	pciStatusReg       = 0x06
+	pciStatusCapList   = 0x10
+	pciCapPointerReg   = 0x34
+	pciCapIDExp        = 0x10
+	pciExpDevCapReg    = 0x04
+	pciExpDevCtlReg    = 0x08
+	pciMaxCapabilities = 48
+)
+
+// devCache caches the device information across discovery cycles. The
+// modalias of the device, which encodes the mandatory device attributes, is
+// used to detect device changes.
+var devCache = utils.NewDeviceCache[*nfdv1alpha1.InstanceFeature]()
+
 // Read a single PCI device attribute
 // A PCI attribute in this context, maps to the corresponding sysfs file
 func readSinglePciAttribute(devPath string, attrName string) (string, error) {
@@ -68,6 +88,88 @@ func readPciDevInfo(devPath string) (*nfdv1alpha1.InstanceFeature, error) {
 	return nfdv1alpha1.NewInstanceFeature(attrs), nil
 }
 
+// readPcieLinkInfo reads the PCIe link speed and width, and the max payload
+// size of a device. The link speeds are reported in GT/s. Attributes that are
+// not available, e.g. for non-PCIe devices, are omitted.
+func readPcieLinkInfo(devPath string) map[string]string {
+	attrs := make(map[string]string)
+
+	for _, attr := range []string{"current_link_speed", "max_link_speed"} {
+		if val, err := readSinglePciAttribute(devPath, attr); err == nil {
+			// The value is e.g. "8.0 GT/s PCIe" or "Unknown"
+			if speed, err := strconv.ParseFloat(strings.Fields(val + " ")[0], 64); err == nil && speed > 0 {
+				attrs[attr] = strconv.FormatFloat(speed, 'f', -1, 64)
+			}
+		}
+	}
+	for _, attr := range []string{"current_link_width", "max_link_width"} {
+		if val, err := readSinglePciAttribute(devPath, attr); err == nil {
+			if width, err := strconv.Atoi(val); err == nil && width > 0 && width <= 32 {
+				attrs[attr] = val
+			}
+		}
+	}
+	if cur, ok := attrs["current_link_width"]; ok {
+		if maxWidth, ok := attrs["max_link_width"]; ok {
+			c, _ := strconv.Atoi(cur)
+			m, _ := strconv.Atoi(maxWidth)
+			attrs["link_width_degraded"] = strconv.FormatBool(c < m)
+		}
+	}
+
+	if mps, mpss, err := readPcieMaxPayloadSize(devPath); err == nil {
+		attrs["max_payload_size"] = strconv.Itoa(mps)
+		attrs["max_payload_size_supported"] = strconv.Itoa(mpss)
+	} else {
+		klog.V(4).InfoS("max payload size not available", "devicePath", devPath, "reason", err)
+	}
+	return attrs
+}
+
+// readPciAuthInfo reads the authorization state of a device and whether the
+// device is removable, i.e. connected through an external-facing port such as
+// Thunderbolt. Attributes that are not supported by the kernel are omitted.
+func readPciAuthInfo(devPath string) map[string]string {
+	attrs := make(map[string]string)
+	if val, err := readSinglePciAttribute(devPath, "authorized"); err == nil && val != "" {
+		attrs["authorized"] = val
+	}
+	// The value is "removable", "fixed" or "unknown"
+	if val, err := readSinglePciAttribute(devPath, "removable"); err == nil && val != "unknown" && val != "" {
+		attrs["removable"] = strconv.FormatBool(val == "removable")
+	}
+	return attrs
+}
+
+// readPcieMaxPayloadSize returns the configured and the maximum supported max
+// payload size (in bytes) of a PCIe device, read from the PCI Express
+// capability in the configuration space of the device. Reading the
+// capabilities requires privileges, unprivileged users only see the first 64
+// bytes of the configuration space.
+func readPcieMaxPayloadSize(devPath string) (int, int, error) {
+	cfg, err := os.ReadFile(filepath.Join(devPath, "config"))
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(cfg) <= pciCapPointerReg || binary.LittleEndian.Uint16(cfg[pciStatusReg:])&pciStatusCapList == 0 {
+		return 0, 0, fmt.Errorf("no capability list")
+	}
+
+	ptr := int(cfg[pciCapPointerReg] & 0xfc)
+	for i := 0; ptr != 0 && i < pciMaxCapabilities; i++ {
+		if ptr+pciExpDevCtlReg+2 > len(cfg) {
+			return 0, 0, fmt.Errorf("capability at offset %#x not readable, config space truncated to %d bytes", ptr, len(cfg))
+		}
+		if cfg[ptr] == pciCapIDExp {
+			devCap := binary.LittleEndian.Uint32(cfg[ptr+pciExpDevCapReg:])
+			devCtl := binary.LittleEndian.Uint16(cfg[ptr+pciExpDevCtlReg:])
+			return 128 << ((devCtl >> 5) & 0x7), 128 << (devCap & 0x7), nil
+		}
+		ptr = int(cfg[ptr+1] & 0xfc)
+	}
+	return 0, 0, fmt.Errorf("no PCI Express capability")
+}
+
 // detectPci detects available PCI devices and retrieves their device attributes.
 // An error is returned if reading any of the mandatory attributes fails.
 func detectPci() ([]nfdv1alpha1.InstanceFeature, error) {
@@ -81,13 +183,36 @@ func detectPci() ([]nfdv1alpha1.InstanceFeature, error) {
 	// Iterate over devices
 	devInfo := make([]nfdv1alpha1.InstanceFeature, 0, len(devices))
 	for _, device := range devices {
-		info, err := readPciDevInfo(filepath.Join(sysfsBasePath, device.Name()))
+		devPath := filepath.Join(sysfsBasePath, device.Name())
+
+		// The link and authorization state may change at runtime so they
+		// are not cached
+		linkInfo := readPcieLinkInfo(devPath)
+		maps.Copy(linkInfo, readPciAuthInfo(devPath))
+
+		modalias, err := os.ReadFile(filepath.Join(devPath, "modalias"))
+		if err == nil {
+			if info, ok := devCache.Get(devPath, string(modalias)); ok {
+				info = info.DeepCopy()
+				maps.Copy(info.Attributes, linkInfo)
+				devInfo = append(devInfo, *info)
+				continue
+			}
+		}
+
+		info, err := readPciDevInfo(devPath)
 		if err != nil {
 			klog.ErrorS(err, "failed to read PCI device info")
 			continue
 		}
+
+		if modalias != nil {
+			devCache.Set(devPath, string(modalias), info.DeepCopy())
+		}
+		maps.Copy(info.Attributes, linkInfo)
 		devInfo = append(devInfo, *info)
 	}
+	devCache.Prune()
 
 	return devInfo, nil
 }
@@ -0,0 +1,90 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/node-feature-discovery/source"
+)
+
+// notifyDelay is the time to wait for changes to settle before sending a
+// notification. It prevents a burst of notifications when multiple
+// providers are (re-)started at once.
+var notifyDelay = time.Second
+
+// SetNotifyChannel method of the EventSource interface. A notification is
+// sent to the channel when feature providers are added or removed, or when
+// any provider sends new features.
+func (s *providerSource) SetNotifyChannel(ch chan<- source.FeatureSource) error {
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	if err := w.Add(s.config.SocketDir); err != nil {
+		// New providers will be detected in the periodic feature discovery
+		klog.V(2).InfoS("not watching feature provider directory", "path", s.config.SocketDir, "reason", err)
+		w.Close()
+		w = nil
+	}
+
+	go s.runNotifier(w, ch)
+
+	return nil
+}
+
+func (s *providerSource) runNotifier(w *fsnotify.Watcher, ch chan<- source.FeatureSource) {
+	var events <-chan fsnotify.Event
+	var errors <-chan error
+	if w != nil {
+		defer w.Close()
+		events = w.Events
+		errors = w.Errors
+	}
+
+	var notify <-chan time.Time
+	for {
+		select {
+		case e, ok := <-events:
+			if !ok {
+				return
+			}
+			if !strings.HasSuffix(e.Name, socketSuffix) || !e.Has(fsnotify.Create|fsnotify.Remove|fsnotify.Rename) {
+				continue
+			}
+			klog.V(4).InfoS("feature provider socket change detected", "path", e.Name, "op", e.Op)
+		case err, ok := <-errors:
+			if !ok {
+				return
+			}
+			klog.ErrorS(err, "error watching feature provider directory", "path", s.config.SocketDir)
+			continue
+		case <-s.updates:
+		case <-notify:
+			notify = nil
+			ch <- s
+			continue
+		}
+		if notify == nil {
+			notify = time.After(notifyDelay)
+		}
+	}
+}
@@ -0,0 +1,317 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"k8s.io/klog/v2"
+
+	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
+	"sigs.k8s.io/node-feature-discovery/pkg/featureprovider"
+	"sigs.k8s.io/node-feature-discovery/pkg/utils"
+	"sigs.k8s.io/node-feature-discovery/source"
+)
+
+// Name of this feature source
+const Name = "provider"
+
+// socketSuffix is the filename suffix of feature provider sockets
+const socketSuffix = ".sock"
+
+var (
+	// firstUpdateTimeout is the maximum time to wait for the initial
+	// features of a newly discovered feature provider
+	firstUpdateTimeout = 2 * time.Second
+
+	// retryInterval is the time to wait before reconnecting to a feature
+	// provider after the connection has been lost
+	retryInterval = 5 * time.Second
+)
+
+// Config contains the configuration parameters of this source.
+type Config struct {
+	// SocketDir is the directory where the feature providers create their
+	// UNIX domain sockets.
+	SocketDir string `json:"socketDir,omitempty"`
+}
+
+// newDefaultConfig returns a new config with pre-populated defaults
+func newDefaultConfig() *Config {
+	return &Config{
+		SocketDir: "/var/run/node-feature-discovery/feature-providers",
+	}
+}
+
+// providerSource implements the FeatureSource, LabelSource,
+// ConfigurableSource and EventSource interfaces.
+type providerSource struct {
+	config   *Config
+	features *nfdv1alpha1.Features
+	labels   source.FeatureLabels
+
+	mu    sync.Mutex
+	conns map[string]*providerConn
+	// updates is signalled when the features of any provider change
+	updates chan struct{}
+}
+
+// Singleton source instance
+var (
+	src = providerSource{
+		config:  newDefaultConfig(),
+		conns:   map[string]*providerConn{},
+		updates: make(chan struct{}, 1),
+	}
+	_ source.FeatureSource      = &src
+	_ source.LabelSource        = &src
+	_ source.ConfigurableSource = &src
+	_ source.EventSource        = &src
+)
+
+// Name method of the LabelSource interface
+func (s *providerSource) Name() string { return Name }
+
+// NewConfig method of the LabelSource interface
+func (s *providerSource) NewConfig() source.Config { return newDefaultConfig() }
+
+// GetConfig method of the LabelSource interface
+func (s *providerSource) GetConfig() source.Config { return s.config }
+
+// SetConfig method of the LabelSource interface
+func (s *providerSource) SetConfig(conf source.Config) {
+	switch v := conf.(type) {
+	case *Config:
+		s.config = v
+	default:
+		panic(fmt.Sprintf("invalid config type: %T", conf))
+	}
+}
+
+// Priority method of the LabelSource interface
+func (s *providerSource) Priority() int { return 20 }
+
+// GetLabels method of the LabelSource interface
+func (s *providerSource) GetLabels() (source.FeatureLabels, error) {
+	labels := make(source.FeatureLabels, len(s.labels))
+	maps.Copy(labels, s.labels)
+	return labels, nil
+}
+
+// Discover method of the FeatureSource interface
+func (s *providerSource) Discover() error {
+	s.features = nfdv1alpha1.NewFeatures()
+	s.labels = make(source.FeatureLabels)
+
+	s.syncProviders()
+
+	s.mu.Lock()
+	names := slices.Sorted(maps.Keys(s.conns))
+	conns := make([]*providerConn, 0, len(names))
+	for _, n := range names {
+		conns = append(conns, s.conns[n])
+	}
+	s.mu.Unlock()
+
+	// Give newly connected providers a chance to send their features
+	timeout := time.After(firstUpdateTimeout)
+	for _, c := range conns {
+		select {
+		case <-c.ready:
+		case <-timeout:
+		}
+	}
+
+	for _, c := range conns {
+		u := c.getUpdate()
+		if u == nil {
+			klog.V(2).InfoS("no features received from feature provider", "provider", c.name, "socket", c.socket)
+			continue
+		}
+		if u.Features != nil {
+			for k, v := range u.Features.Flags {
+				s.features.Flags[c.name+"."+k] = v
+			}
+			for k, v := range u.Features.Attributes {
+				s.features.Attributes[c.name+"."+k] = v
+			}
+			for k, v := range u.Features.Instances {
+				s.features.Instances[c.name+"."+k] = v
+			}
+		}
+		for k, v := range u.Labels {
+			s.labels[k] = v
+		}
+	}
+
+	source.LogDiscoveredFeatures(s.Name(), "features", utils.DelayedDumper(s.features))
+
+	return nil
+}
+
+// GetFeatures method of the FeatureSource Interface
+func (s *providerSource) GetFeatures() *nfdv1alpha1.Features {
+	if s.features == nil {
+		s.features = nfdv1alpha1.NewFeatures()
+	}
+	return s.features
+}
+
+// syncProviders connects to new feature provider sockets and disconnects
+// from the ones that have been removed.
+func (s *providerSource) syncProviders() {
+	sockets := map[string]string{}
+	entries, err := os.ReadDir(s.config.SocketDir)
+	if err != nil && !os.IsNotExist(err) {
+		klog.ErrorS(err, "failed to read feature provider directory", "path", s.config.SocketDir)
+	}
+	for _, e := range entries {
+		name, ok := strings.CutSuffix(e.Name(), socketSuffix)
+		if !ok || name == "" || e.Type()&os.ModeSocket == 0 {
+			continue
+		}
+		sockets[filepath.Join(s.config.SocketDir, e.Name())] = name
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for socket, c := range s.conns {
+		if _, ok := sockets[socket]; !ok {
+			klog.InfoS("feature provider removed", "provider", c.name, "socket", socket)
+			c.cancel()
+			delete(s.conns, socket)
+		}
+	}
+	for socket, name := range sockets {
+		if _, ok := s.conns[socket]; ok {
+			continue
+		}
+		klog.InfoS("feature provider detected", "provider", name, "socket", socket)
+		s.conns[socket] = newProviderConn(name, socket, s.notify)
+	}
+}
+
+// notify signals that the features of a provider have changed.
+func (s *providerSource) notify() {
+	select {
+	case s.updates <- struct{}{}:
+	default:
+	}
+}
+
+// providerConn is a connection to one feature provider.
+type providerConn struct {
+	name   string
+	socket string
+	cancel context.CancelFunc
+	// ready is closed when the first update has been received, or the first
+	// connection attempt has failed
+	ready     chan struct{}
+	readyOnce sync.Once
+	onChange  func()
+
+	mu     sync.Mutex
+	update *featureprovider.FeatureUpdate
+}
+
+func newProviderConn(name, socket string, onChange func()) *providerConn {
+	ctx, cancel := context.WithCancel(context.Background())
+	c := &providerConn{
+		name:     name,
+		socket:   socket,
+		cancel:   cancel,
+		ready:    make(chan struct{}),
+		onChange: onChange,
+	}
+	go c.run(ctx)
+	return c
+}
+
+func (c *providerConn) getUpdate() *featureprovider.FeatureUpdate {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.update
+}
+
+func (c *providerConn) setUpdate(u *featureprovider.FeatureUpdate) {
+	c.mu.Lock()
+	changed := u != nil || c.update != nil
+	c.update = u
+	c.mu.Unlock()
+
+	c.readyOnce.Do(func() { close(c.ready) })
+	if changed {
+		c.onChange()
+	}
+}
+
+// run keeps receiving features from the provider until the context is
+// cancelled, reconnecting after failures.
+func (c *providerConn) run(ctx context.Context) {
+	for {
+		err := c.watch(ctx)
+		// Features of a disconnected provider are dropped
+		c.setUpdate(nil)
+		if ctx.Err() != nil {
+			return
+		}
+		klog.ErrorS(err, "connection to feature provider lost, retrying", "provider", c.name, "socket", c.socket, "retryInterval", retryInterval)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(retryInterval):
+		}
+	}
+}
+
+func (c *providerConn) watch(ctx context.Context) error {
+	conn, err := grpc.NewClient("unix://"+c.socket, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	stream, err := featureprovider.NewFeatureProviderClient(conn).WatchFeatures(ctx, &emptypb.Empty{})
+	if err != nil {
+		return err
+	}
+	for {
+		u, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+		klog.V(2).InfoS("features received from feature provider", "provider", c.name)
+		c.setUpdate(u)
+	}
+}
+
+func init() {
+	source.Register(&src)
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
+	"sigs.k8s.io/node-feature-discovery/pkg/featureprovider"
+	"sigs.k8s.io/node-feature-discovery/source"
+)
+
+type fakeProvider struct {
+	featureprovider.UnimplementedFeatureProviderServer
+	updates chan *featureprovider.FeatureUpdate
+}
+
+func (p *fakeProvider) WatchFeatures(_ *emptypb.Empty, stream featureprovider.WatchFeaturesServer) error {
+	for {
+		select {
+		case u := <-p.updates:
+			if err := stream.Send(u); err != nil {
+				return err
+			}
+		case <-stream.Context().Done():
+			return nil
+		}
+	}
+}
+
+func startFakeProvider(t *testing.T, socket string) (*fakeProvider, *grpc.Server) {
+	lis, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &fakeProvider{updates: make(chan *featureprovider.FeatureUpdate, 1)}
+	srv := grpc.NewServer()
+	featureprovider.RegisterFeatureProviderServer(srv, p)
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+	return p, srv
+}
+
+func newTestSource(dir string) *providerSource {
+	return &providerSource{
+		config:  &Config{SocketDir: dir},
+		conns:   map[string]*providerConn{},
+		updates: make(chan struct{}, 1),
+	}
+}
+
+func waitForUpdate(t *testing.T, s *providerSource) {
+	select {
+	case <-s.updates:
+	case <-time.After(10 * time.Second):
+		t.Fatal("timeout waiting for feature provider update")
+	}
+}
+
+func drainUpdates(s *providerSource) {
+	select {
+	case <-s.updates:
+	default:
+	}
+}
+
+func TestProviderSource(t *testing.T) {
+	// Non-existent directory
+	s := newTestSource(filepath.Join(t.TempDir(), "non-existent"))
+	assert.NoError(t, s.Discover())
+	assert.Empty(t, s.GetFeatures().Attributes)
+	l, err := s.GetLabels()
+	assert.NoError(t, err)
+	assert.Empty(t, l)
+
+	// Running provider
+	dir := t.TempDir()
+	s = newTestSource(dir)
+	defer func() {
+		for _, c := range s.conns {
+			c.cancel()
+		}
+	}()
+	socket := filepath.Join(dir, "foo.sock")
+	p, srv := startFakeProvider(t, socket)
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "bar.sock"), nil, 0644))
+
+	f := nfdv1alpha1.NewFeatures()
+	f.Attributes["attr"] = nfdv1alpha1.NewAttributeFeatures(map[string]string{"a": "1"})
+	p.updates <- &featureprovider.FeatureUpdate{Features: f, Labels: map[string]string{"vendor.io/foo": "true"}}
+
+	assert.NoError(t, s.Discover())
+	drainUpdates(s)
+	assert.Len(t, s.conns, 1, "only sockets should be treated as providers")
+	assert.Equal(t, map[string]nfdv1alpha1.AttributeFeatureSet{"foo.attr": f.Attributes["attr"]}, s.GetFeatures().Attributes)
+	l, err = s.GetLabels()
+	assert.NoError(t, err)
+	assert.Equal(t, source.FeatureLabels{"vendor.io/foo": "true"}, l)
+
+	// Updated features
+	p.updates <- &featureprovider.FeatureUpdate{Labels: map[string]string{"vendor.io/foo": "false"}}
+	waitForUpdate(t, s)
+	assert.NoError(t, s.Discover())
+	assert.Empty(t, s.GetFeatures().Attributes)
+	l, err = s.GetLabels()
+	assert.NoError(t, err)
+	assert.Equal(t, source.FeatureLabels{"vendor.io/foo": "false"}, l)
+
+	// Provider stopped, which also removes the socket
+	srv.Stop()
+	waitForUpdate(t, s)
+	assert.NoError(t, s.Discover())
+	assert.Empty(t, s.conns)
+	l, err = s.GetLabels()
+	assert.NoError(t, err)
+	assert.Empty(t, l)
+}
@@ -0,0 +1,245 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Command gen generates the feature schema of the built-in feature sources by
+// inspecting their source code. The features are detected from assignments of
+// the form
+//
+//	s.features.<Flags|Attributes|Instances>[<feature name>] = ...
+//
+// where the feature name is a string literal or a string constant of the
+// package.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/node-feature-discovery/source/schema"
+)
+
+var featureTypes = map[string]string{
+	"Flags":      schema.FlagType,
+	"Attributes": schema.AttributeType,
+	"Instances":  schema.InstanceType,
+}
+
+func main() {
+	output := flag.String("o", "", "Output file. Defaults to stdout.")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [-o FILE] SOURCE_DIR\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	s, err := generate(flag.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	data, err := marshal(s)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	if *output == "" {
+		_, err = os.Stdout.Write(data)
+	} else {
+		err = os.WriteFile(*output, data, 0644)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// marshal serializes the schema in the format stored in the repository.
+func marshal(s *schema.Schema) ([]byte, error) {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// generate creates the feature schema from the feature source packages under
+// sourceDir.
+func generate(sourceDir string) (*schema.Schema, error) {
+	entries, err := os.ReadDir(sourceDir)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &schema.Schema{Sources: []schema.Source{}}
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		src, err := inspectPackage(filepath.Join(sourceDir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		if src != nil {
+			s.Sources = append(s.Sources, *src)
+		}
+	}
+
+	sort.Slice(s.Sources, func(i, j int) bool { return s.Sources[i].Name < s.Sources[j].Name })
+	return s, nil
+}
+
+// inspectPackage detects the features produced by the feature source
+// implemented in dir. Nil is returned if dir does not contain a feature
+// source.
+func inspectPackage(dir string) (*schema.Source, error) {
+	fset := token.NewFileSet()
+	files := []*ast.File{}
+
+	matches, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range matches {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, path, nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, f)
+	}
+
+	consts := stringConsts(files)
+	name, ok := consts["Name"]
+	if !ok {
+		return nil, nil
+	}
+
+	found := map[schema.Feature]struct{}{}
+	for _, f := range files {
+		ast.Inspect(f, func(n ast.Node) bool {
+			assign, ok := n.(*ast.AssignStmt)
+			if !ok {
+				return true
+			}
+			for _, lhs := range assign.Lhs {
+				if ft, ok := featureAssignment(lhs, consts); ok {
+					ft.Name = name + "." + ft.Name
+					found[ft] = struct{}{}
+				}
+			}
+			return true
+		})
+	}
+	if len(found) == 0 {
+		return nil, nil
+	}
+
+	src := &schema.Source{Name: name, Features: make([]schema.Feature, 0, len(found))}
+	for ft := range found {
+		src.Features = append(src.Features, ft)
+	}
+	sort.Slice(src.Features, func(i, j int) bool {
+		a, b := src.Features[i], src.Features[j]
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.Type < b.Type
+	})
+	return src, nil
+}
+
+// featureAssignment checks if expr is of the form
+// <x>.features.<Flags|Attributes|Instances>[<name>] and returns the
+// corresponding feature (without the source name prefix).
+func featureAssignment(expr ast.Expr, consts map[string]string) (schema.Feature, bool) {
+	idx, ok := expr.(*ast.IndexExpr)
+	if !ok {
+		return schema.Feature{}, false
+	}
+	sel, ok := idx.X.(*ast.SelectorExpr)
+	if !ok {
+		return schema.Feature{}, false
+	}
+	typ, ok := featureTypes[sel.Sel.Name]
+	if !ok {
+		return schema.Feature{}, false
+	}
+	if parent, ok := sel.X.(*ast.SelectorExpr); !ok || parent.Sel.Name != "features" {
+		return schema.Feature{}, false
+	}
+
+	var name string
+	switch v := idx.Index.(type) {
+	case *ast.BasicLit:
+		s, err := strconv.Unquote(v.Value)
+		if err != nil || v.Kind != token.STRING {
+			return schema.Feature{}, false
+		}
+		name = s
+	case *ast.Ident:
+		if name, ok = consts[v.Name]; !ok {
+			return schema.Feature{}, false
+		}
+	default:
+		return schema.Feature{}, false
+	}
+	return schema.Feature{Name: name, Type: typ}, true
+}
+
+// stringConsts returns the top-level string constants declared in files.
+func stringConsts(files []*ast.File) map[string]string {
+	consts := map[string]string{}
+	for _, f := range files {
+		for _, decl := range f.Decls {
+			gd, ok := decl.(*ast.GenDecl)
+			if !ok || gd.Tok != token.CONST {
+				continue
+			}
+			for _, spec := range gd.Specs {
+				vs := spec.(*ast.ValueSpec)
+				for i, ident := range vs.Names {
+					if i >= len(vs.Values) {
+						continue
+					}
+					lit, ok := vs.Values[i].(*ast.BasicLit)
+					if !ok || lit.Kind != token.STRING {
+						continue
+					}
+					if s, err := strconv.Unquote(lit.Value); err == nil {
+						consts[ident.Name] = s
+					}
+				}
+			}
+		}
+	}
+	return consts
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"sigs.k8s.io/node-feature-discovery/source/schema"
+)
+
+func TestGenerate(t *testing.T) {
+	dir := t.TempDir()
+	writeFile := func(path, content string) {
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	writeFile(filepath.Join(dir, "foo/foo.go"), `package foo
+const Name = "foo"
+const (
+	BarFeature = "bar"
+	BazFeature = "baz"
+)
+func (s *fooSource) Discover() error {
+	s.features.Flags[BarFeature] = nil
+	s.features.Attributes[BarFeature] = nil
+	s.features.Instances[BazFeature], x = nil, nil
+	s.features.Attributes["qux"] = nil
+	other.Attributes["ignored"] = nil
+	return nil
+}
+`)
+	// Test files are ignored
+	writeFile(filepath.Join(dir, "foo/foo_test.go"), `package foo
+func f(s *fooSource) { s.features.Flags["test"] = nil }
+`)
+	// Packages without a source name are ignored
+	writeFile(filepath.Join(dir, "util/util.go"), `package util
+func f(s *src) { s.features.Flags["util"] = nil }
+`)
+
+	s, err := generate(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, &schema.Schema{Sources: []schema.Source{
+		{
+			Name: "foo",
+			Features: []schema.Feature{
+				{Name: "foo.bar", Type: schema.AttributeType},
+				{Name: "foo.bar", Type: schema.FlagType},
+				{Name: "foo.baz", Type: schema.InstanceType},
+				{Name: "foo.qux", Type: schema.AttributeType},
+			},
+		},
+	}}, s)
+}
+
+// TestSchemaUpToDate verifies that the embedded schema matches the feature
+// sources.
+func TestSchemaUpToDate(t *testing.T) {
+	s, err := generate("../..")
+	assert.NoError(t, err)
+	data, err := marshal(s)
+	assert.NoError(t, err)
+	assert.Equal(t, string(data), string(schema.JSON()), "feature schema is out of date, run 'make feature-schema'")
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package schema provides a machine-readable description of the features
+// that the built-in feature sources can produce. The schema is generated from
+// the source code of the feature sources with "go generate" and embedded in
+// the binary.
+package schema
+
+import (
+	_ "embed"
+	"encoding/json"
+)
+
+//go:generate go run ./gen -o schema.json ..
+
+// Feature types
+const (
+	FlagType      = "flag"
+	AttributeType = "attribute"
+	InstanceType  = "instance"
+)
+
+// Schema describes the features of all feature sources.
+type Schema struct {
+	// Sources contains the feature sources, sorted by name.
+	Sources []Source `json:"sources"`
+}
+
+// Source describes the features of one feature source.
+type Source struct {
+	// Name of the feature source.
+	Name string `json:"name"`
+	// Features contains the features the source can produce, sorted by name
+	// and type.
+	Features []Feature `json:"features"`
+}
+
+// Feature describes one feature.
+type Feature struct {
+	// Name of the feature, as referenced in the matchFeatures of
+	// NodeFeatureRules (e.g. "cpu.cpuid").
+	Name string `json:"name"`
+	// Type of the feature, one of "flag", "attribute" or "instance".
+	Type string `json:"type"`
+}
+
+//go:embed schema.json
+var schemaJSON []byte
+
+// JSON returns the embedded feature schema in JSON format.
+func JSON() []byte {
+	return schemaJSON
+}
+
+// Get returns the embedded feature schema.
+func Get() (*Schema, error) {
+	s := &Schema{}
+	if err := json.Unmarshal(schemaJSON, s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
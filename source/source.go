@@ -20,6 +20,10 @@ package source
 
 import (
 	"fmt"
+	"sync/atomic"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
 
 	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
 )
@@ -77,6 +81,53 @@ type SupplementalSource interface {
 	DisableByDefault() bool
 }
 
+// HostPathSource is an interface for sources that read files from the host
+// system. It is used for verifying that the required host directories have
+// been made available to nfd-worker.
+type HostPathSource interface {
+	Source
+
+	// RequiredHostPaths returns the files and directories the source reads
+	RequiredHostPaths() []string
+}
+
+// EventSource is an interface for sources that are able to notify about
+// changes in the features they discover.
+type EventSource interface {
+	FeatureSource
+
+	// SetNotifyChannel sets the channel used for notifying about changes in
+	// the features of the source
+	SetNotifyChannel(chan<- FeatureSource) error
+}
+
+// RuleSource is an interface for label sources that create labels by
+// evaluating feature matching rules.
+type RuleSource interface {
+	LabelSource
+
+	// GetRules returns the rules evaluated by the source
+	GetRules() []nfdv1alpha1.Rule
+}
+
+// NodeRequestSource is an interface for label sources that also request node
+// annotations, extended resources and taints to be created.
+type NodeRequestSource interface {
+	LabelSource
+
+	// GetNodeRequests returns the requested annotations, extended resources
+	// and taints
+	GetNodeRequests() *NodeRequests
+}
+
+// NodeRequests is the set of node annotations, extended resources and taints
+// requested by a source.
+type NodeRequests struct {
+	Annotations       map[string]string
+	ExtendedResources map[string]string
+	Taints            []corev1.Taint
+}
+
 // FeatureLabelValue represents the value of one feature label
 type FeatureLabelValue interface{}
 
@@ -179,3 +230,28 @@ func GetAllFeatures() *nfdv1alpha1.Features {
 	}
 	return features
 }
+
+// featureLoggingDisabled disables dumping the discovered features into the
+// log, e.g. when they are written into dedicated dump files instead.
+var featureLoggingDisabled atomic.Bool
+
+// SetFeatureLogging enables or disables dumping the discovered features into
+// the log.
+func SetFeatureLogging(enabled bool) {
+	featureLoggingDisabled.Store(!enabled)
+}
+
+// FeatureLoggingEnabled returns true if the discovered features should be
+// dumped into the log.
+func FeatureLoggingEnabled() bool {
+	return !featureLoggingDisabled.Load()
+}
+
+// LogDiscoveredFeatures logs the features discovered by a feature source at
+// verbosity level 3, unless feature logging has been disabled.
+func LogDiscoveredFeatures(name string, keysAndValues ...interface{}) {
+	if !FeatureLoggingEnabled() {
+		return
+	}
+	klog.V(3).InfoS("discovered features", append([]interface{}{"featureSource", name}, keysAndValues...)...)
+}
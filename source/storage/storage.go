@@ -43,8 +43,9 @@ type storageSource struct {
 // Singleton source instance
 var (
 	src storageSource
-	_   source.FeatureSource = &src
-	_   source.LabelSource   = &src
+	_   source.FeatureSource  = &src
+	_   source.LabelSource    = &src
+	_   source.HostPathSource = &src
 )
 
 // queueAttrs is the list of files under /sys/block/<dev>/queue that we're trying to read
@@ -56,6 +57,11 @@ func (s *storageSource) Name() string { return Name }
 // Priority method of the LabelSource interface
 func (s *storageSource) Priority() int { return 0 }
 
+// RequiredHostPaths method of the HostPathSource interface
+func (s *storageSource) RequiredHostPaths() []string {
+	return []string{hostpath.SysfsDir.Path("block")}
+}
+
 // GetLabels method of the LabelSource interface
 func (s *storageSource) GetLabels() (source.FeatureLabels, error) {
 	labels := source.FeatureLabels{}
@@ -81,7 +87,7 @@ func (s *storageSource) Discover() error {
 	}
 	s.features.Instances[BlockFeature] = nfdv1alpha1.InstanceFeatureSet{Elements: devs}
 
-	klog.V(3).InfoS("discovered features", "featureSource", s.Name(), "features", utils.DelayedDumper(s.features))
+	source.LogDiscoveredFeatures(s.Name(), "features", utils.DelayedDumper(s.features))
 
 	return nil
 }
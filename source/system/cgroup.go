@@ -0,0 +1,148 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
+
+	"sigs.k8s.io/node-feature-discovery/pkg/utils/hostpath"
+)
+
+const (
+	cgroupDriverSystemd  = "systemd"
+	cgroupDriverCgroupfs = "cgroupfs"
+
+	cgroupVersionV1     = "v1"
+	cgroupVersionV2     = "v2"
+	cgroupVersionHybrid = "hybrid"
+)
+
+// psiProcfsPath is the directory of the pressure stall information (PSI)
+// interface files. It only exists if PSI is enabled in the kernel.
+var psiProcfsPath = "/proc/pressure"
+
+// kubeletConfigFile is the path of the kubelet configuration file relative to
+// the host /var directory.
+var kubeletConfigFile = "lib/kubelet/config.yaml"
+
+// detectCgroupDriver detects the cgroup driver expected by the host, i.e.
+// whether the cgroup hierarchy is managed by systemd, and the cgroup driver
+// used by the kubelet.
+func detectCgroupDriver() map[string]string {
+	attrs := make(map[string]string)
+
+	hostDriver := detectHostCgroupDriver()
+	if hostDriver != "" {
+		attrs["host_driver"] = hostDriver
+	}
+
+	kubeletDriver := detectKubeletCgroupDriver()
+	if kubeletDriver != "" {
+		attrs["kubelet_driver"] = kubeletDriver
+	}
+
+	if hostDriver != "" && kubeletDriver != "" {
+		attrs["driver_mismatch"] = strconv.FormatBool(hostDriver != kubeletDriver)
+	}
+	return attrs
+}
+
+// detectCgroupVersion detects the version of the cgroup hierarchy of the host
+// and whether pressure stall information (PSI) is enabled.
+func detectCgroupVersion() map[string]string {
+	attrs := make(map[string]string)
+
+	if version := detectHostCgroupVersion(); version != "" {
+		attrs["version"] = version
+	}
+
+	_, err := os.Stat(filepath.Join(psiProcfsPath, "cpu"))
+	attrs["psi"] = strconv.FormatBool(err == nil)
+
+	return attrs
+}
+
+// detectHostCgroupVersion detects if the host uses the unified (v2) cgroup
+// hierarchy, the legacy (v1) hierarchies or the hybrid mode where the unified
+// hierarchy is mounted alongside the v1 hierarchies without any controllers.
+func detectHostCgroupVersion() string {
+	root := hostpath.SysfsDir.Path("fs/cgroup")
+	if _, err := os.Stat(root); err != nil {
+		return ""
+	}
+	if _, err := os.Stat(filepath.Join(root, "cgroup.controllers")); err == nil {
+		return cgroupVersionV2
+	}
+	if _, err := os.Stat(filepath.Join(root, "unified", "cgroup.controllers")); err == nil {
+		return cgroupVersionHybrid
+	}
+	return cgroupVersionV1
+}
+
+// detectHostCgroupDriver detects if the cgroup hierarchy of the host is
+// managed by systemd. Systemd creates the init.scope cgroup (cgroup v2) or a
+// named systemd hierarchy (cgroup v1).
+func detectHostCgroupDriver() string {
+	root := hostpath.SysfsDir.Path("fs/cgroup")
+	if _, err := os.Stat(root); err != nil {
+		klog.V(1).InfoS("cgroup filesystem not available", "path", root, "error", err)
+		return ""
+	}
+	for _, p := range []string{"init.scope", "systemd"} {
+		if _, err := os.Stat(hostpath.SysfsDir.Path("fs/cgroup", p)); err == nil {
+			return cgroupDriverSystemd
+		}
+	}
+	return cgroupDriverCgroupfs
+}
+
+// detectKubeletCgroupDriver detects the cgroup driver used by the kubelet.
+// The kubelet configuration file is used if it is accessible. Otherwise, the
+// driver is inferred from the name of the top-level cgroup of pods created by
+// the kubelet.
+func detectKubeletCgroupDriver() string {
+	if data, err := os.ReadFile(hostpath.VarDir.Path(kubeletConfigFile)); err == nil {
+		config := struct {
+			CgroupDriver string `json:"cgroupDriver"`
+		}{}
+		if err := yaml.Unmarshal(data, &config); err != nil {
+			klog.ErrorS(err, "failed to parse kubelet configuration", "path", hostpath.VarDir.Path(kubeletConfigFile))
+		} else if config.CgroupDriver != "" {
+			return config.CgroupDriver
+		} else {
+			// Default of the kubelet
+			return cgroupDriverCgroupfs
+		}
+	}
+
+	// Check the top-level pod cgroup in the unified (v2) hierarchy and the
+	// cpu controller hierarchy (v1)
+	for _, dir := range []string{"", "cpu"} {
+		if _, err := os.Stat(hostpath.SysfsDir.Path("fs/cgroup", dir, "kubepods.slice")); err == nil {
+			return cgroupDriverSystemd
+		}
+		if _, err := os.Stat(hostpath.SysfsDir.Path("fs/cgroup", dir, "kubepods")); err == nil {
+			return cgroupDriverCgroupfs
+		}
+	}
+	return ""
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package system
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/node-feature-discovery/pkg/utils/hostpath"
+)
+
+// knownCgroupControllers are the cgroup v2 controllers that are always
+// reported, i.e. also when they are not enabled.
+var knownCgroupControllers = []string{"cpu", "cpuset", "io", "memory", "hugetlb", "misc", "pids", "rdma"}
+
+// cgroupSchedulerProbeDirs are the (non-root) cgroups that are checked for
+// the interface files of the cpu controller. The root cgroup does not have
+// them.
+var cgroupSchedulerProbeDirs = []string{"kubepods.slice", "kubepods", "system.slice", "init.scope"}
+
+// detectCgroupControllers detects the cgroup v2 controllers that are enabled
+// for child cgroups of the root cgroup and the availability of optional
+// scheduler features of the cpu controller. Nothing is reported on cgroup v1
+// hosts.
+func detectCgroupControllers() map[string]string {
+	data, err := os.ReadFile(hostpath.SysfsDir.Path("fs/cgroup/cgroup.subtree_control"))
+	if err != nil {
+		klog.V(1).InfoS("cgroup v2 controllers not available", "error", err)
+		return nil
+	}
+
+	attrs := make(map[string]string, len(knownCgroupControllers)+2)
+	for _, c := range knownCgroupControllers {
+		attrs[c] = "false"
+	}
+	for _, c := range strings.Fields(string(data)) {
+		attrs[c] = "true"
+	}
+
+	cpuIdle, cpuBurst := false, false
+	if attrs["cpu"] == "true" {
+		for _, dir := range cgroupSchedulerProbeDirs {
+			if _, err := os.Stat(hostpath.SysfsDir.Path("fs/cgroup", dir, "cpu.max")); err != nil {
+				continue
+			}
+			_, err := os.Stat(hostpath.SysfsDir.Path("fs/cgroup", dir, "cpu.idle"))
+			cpuIdle = err == nil
+			_, err = os.Stat(hostpath.SysfsDir.Path("fs/cgroup", dir, "cpu.max.burst"))
+			cpuBurst = err == nil
+			break
+		}
+	}
+	attrs["cpu_idle"] = strconv.FormatBool(cpuIdle)
+	attrs["cpu_burst"] = strconv.FormatBool(cpuBurst)
+
+	return attrs
+}
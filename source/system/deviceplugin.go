@@ -0,0 +1,142 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package system
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"k8s.io/klog/v2"
+	podresourcesapi "k8s.io/kubelet/pkg/apis/podresources/v1"
+
+	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
+	"sigs.k8s.io/node-feature-discovery/pkg/utils/hostpath"
+)
+
+// podResourcesTimeout is the timeout of queries to the kubelet pod resources
+// API.
+const podResourcesTimeout = 5 * time.Second
+
+var (
+	// podResourcesSocket is the path of the kubelet pod resources socket
+	// relative to the host /var directory.
+	podResourcesSocket = "lib/kubelet/pod-resources/kubelet.sock"
+	// devicePluginCheckpoint is the path of the device manager checkpoint of
+	// the kubelet relative to the host /var directory.
+	devicePluginCheckpoint = "lib/kubelet/device-plugins/kubelet_internal_checkpoint"
+)
+
+// discoverDevicePlugins discovers the resources registered by device plugins
+// in the kubelet. The resources are queried from the pod resources API of the
+// kubelet if the socket is accessible. Otherwise, they are read from the
+// checkpoint file of the kubelet device manager.
+func discoverDevicePlugins() []nfdv1alpha1.InstanceFeature {
+	devices, err := podResourcesDevices()
+	if err != nil {
+		klog.ErrorS(err, "failed to query allocatable resources from the kubelet")
+	}
+	if devices == nil {
+		devices, err = checkpointDevices()
+		if err != nil {
+			klog.ErrorS(err, "failed to read device plugin checkpoint")
+			return nil
+		}
+	}
+
+	names := make([]string, 0, len(devices))
+	for name := range devices {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	plugins := make([]nfdv1alpha1.InstanceFeature, 0, len(names))
+	for _, name := range names {
+		plugins = append(plugins, *nfdv1alpha1.NewInstanceFeature(map[string]string{
+			"name":    name,
+			"devices": strconv.Itoa(devices[name]),
+		}))
+	}
+	return plugins
+}
+
+// podResourcesDevices returns the number of allocatable devices per resource
+// name, queried from the pod resources API of the kubelet. Nil is returned if
+// the socket does not exist.
+func podResourcesDevices() (map[string]int, error) {
+	socket := hostpath.VarDir.Path(podResourcesSocket)
+	if _, err := os.Stat(socket); errors.Is(err, fs.ErrNotExist) {
+		klog.V(2).InfoS("kubelet pod resources socket not available", "path", socket)
+		return nil, nil
+	}
+
+	conn, err := grpc.NewClient("unix://"+socket, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), podResourcesTimeout)
+	defer cancel()
+
+	resp, err := podresourcesapi.NewPodResourcesListerClient(conn).GetAllocatableResources(ctx, &podresourcesapi.AllocatableResourcesRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	devices := make(map[string]int)
+	for _, d := range resp.GetDevices() {
+		devices[d.GetResourceName()] += len(d.GetDeviceIds())
+	}
+	return devices, nil
+}
+
+// checkpointDevices returns the number of registered devices per resource
+// name, read from the checkpoint file of the kubelet device manager. Nil is
+// returned if the file does not exist.
+func checkpointDevices() (map[string]int, error) {
+	path := hostpath.VarDir.Path(devicePluginCheckpoint)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		klog.V(2).InfoS("device plugin checkpoint not available", "path", path)
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	checkpoint := struct {
+		Data struct {
+			RegisteredDevices map[string][]string `json:"RegisteredDevices"`
+		} `json:"Data"`
+	}{}
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		return nil, err
+	}
+
+	devices := make(map[string]int, len(checkpoint.Data.RegisteredDevices))
+	for name, ids := range checkpoint.Data.RegisteredDevices {
+		devices[name] = len(ids)
+	}
+	return devices, nil
+}
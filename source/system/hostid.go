@@ -0,0 +1,70 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package system
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"strings"
+
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/node-feature-discovery/pkg/utils/hostpath"
+)
+
+// bootIDPath is the path of the boot ID. The boot ID is not namespaced so it
+// is read from the /proc of the container.
+var bootIDPath = "/proc/sys/kernel/random/boot_id"
+
+// hostIDHashLen is the length of the published hashes, short enough for
+// being usable as a label value.
+const hostIDHashLen = 32
+
+// getHostIDAttributes returns salted hashes of the machine ID and the boot
+// ID of the host. The raw identifiers are never published.
+func getHostIDAttributes(salt string) map[string]string {
+	attrs := make(map[string]string)
+
+	ids := map[string]string{
+		"machine_id_hash": hostpath.EtcDir.Path("machine-id"),
+		"boot_id_hash":    bootIDPath,
+	}
+	for name, path := range ids {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				klog.V(2).InfoS("host ID not available", "path", path)
+			} else {
+				klog.ErrorS(err, "failed to read host ID", "path", path)
+			}
+			continue
+		}
+		if id := strings.TrimSpace(string(data)); id != "" {
+			attrs[name] = hashHostID(id, salt)
+		}
+	}
+	return attrs
+}
+
+// hashHostID calculates a salted hash (HMAC-SHA256) of a host identifier.
+func hashHostID(id, salt string) string {
+	mac := hmac.New(sha256.New, []byte(salt))
+	mac.Write([]byte(id))
+	return hex.EncodeToString(mac.Sum(nil))[:hostIDHashLen]
+}
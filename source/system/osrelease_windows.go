@@ -0,0 +1,70 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package system
+
+import (
+	"fmt"
+	"strconv"
+
+	"golang.org/x/sys/windows/registry"
+)
+
+// currentVersionKey is the registry key holding the version information of
+// Windows.
+const currentVersionKey = `SOFTWARE\Microsoft\Windows NT\CurrentVersion`
+
+// parseOSRelease returns the operating system information in os-release
+// format. VERSION_ID is in <major>.<minor>.<build> format, matching the
+// node.kubernetes.io/windows-build label of the kubelet.
+func parseOSRelease() (map[string]string, error) {
+	k, err := registry.OpenKey(registry.LOCAL_MACHINE, currentVersionKey, registry.QUERY_VALUE)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open registry key %q: %w", currentVersionKey, err)
+	}
+	defer k.Close()
+
+	major, _, err := k.GetIntegerValue("CurrentMajorVersionNumber")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read major version: %w", err)
+	}
+	minor, _, err := k.GetIntegerValue("CurrentMinorVersionNumber")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read minor version: %w", err)
+	}
+	build, _, err := k.GetStringValue("CurrentBuildNumber")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read build number: %w", err)
+	}
+
+	release := map[string]string{
+		"ID":         "windows",
+		"VERSION_ID": strconv.FormatUint(major, 10) + "." + strconv.FormatUint(minor, 10) + "." + build,
+		"BUILD_ID":   build,
+	}
+	if name, _, err := k.GetStringValue("ProductName"); err == nil {
+		release["NAME"] = name
+	}
+	if version, _, err := k.GetStringValue("DisplayVersion"); err == nil {
+		release["VERSION"] = version
+	}
+	// Update build revision
+	if ubr, _, err := k.GetIntegerValue("UBR"); err == nil {
+		release["BUILD_ID"] += "." + strconv.FormatUint(ubr, 10)
+	}
+
+	return release, nil
+}
@@ -0,0 +1,198 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package system
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	runtimeapi "k8s.io/cri-api/pkg/apis/runtime/v1"
+	"k8s.io/klog/v2"
+
+	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
+	"sigs.k8s.io/node-feature-discovery/pkg/utils/hostpath"
+)
+
+const (
+	runtimeContainerd = "containerd"
+	runtimeCrio       = "cri-o"
+)
+
+// criTimeout is the timeout of queries to the CRI runtime.
+const criTimeout = 5 * time.Second
+
+// containerRuntime describes how to discover the runtime handlers of a
+// container runtime.
+type containerRuntime struct {
+	name string
+	// socket is the path of the CRI socket relative to the host /run
+	// directory.
+	socket string
+	// configFiles are glob patterns of the configuration files relative to
+	// the host /etc directory.
+	configFiles []string
+	// handlerRe matches the table headers of runtime handlers in the
+	// configuration files.
+	handlerRe *regexp.Regexp
+}
+
+var containerRuntimes = []containerRuntime{
+	{
+		name:        runtimeContainerd,
+		socket:      "containerd/containerd.sock",
+		configFiles: []string{"containerd/config.toml", "containerd/conf.d/*.toml"},
+		handlerRe:   regexp.MustCompile(`^\[\s*plugins\s*\.\s*"io\.containerd\.(?:grpc\.v1\.cri|cri\.v1\.runtime)"\s*\.\s*containerd\s*\.\s*runtimes\s*\.\s*(?:"([^"]+)"|'([^']+)'|([\w-]+))\s*\]`),
+	},
+	{
+		name:        runtimeCrio,
+		socket:      "crio/crio.sock",
+		configFiles: []string{"crio/crio.conf", "crio/crio.conf.d/*"},
+		handlerRe:   regexp.MustCompile(`^\[\s*crio\s*\.\s*runtime\s*\.\s*runtimes\s*\.\s*(?:"([^"]+)"|'([^']+)'|([\w-]+))\s*\]`),
+	},
+}
+
+// discoverRuntimeHandlers discovers the runtime handlers configured in the
+// container runtime of the node. The handlers are queried from the CRI
+// socket of the runtime if it is accessible. Otherwise, they are parsed from
+// the configuration files of the runtime.
+func discoverRuntimeHandlers() []nfdv1alpha1.InstanceFeature {
+	for _, r := range containerRuntimes {
+		handlers, err := r.criRuntimeHandlers()
+		if err != nil {
+			klog.ErrorS(err, "failed to query runtime handlers from the CRI runtime", "runtime", r.name)
+		} else if len(handlers) > 0 {
+			return handlers
+		}
+
+		if handlers := r.configRuntimeHandlers(); len(handlers) > 0 {
+			return handlers
+		}
+	}
+	return nil
+}
+
+// criRuntimeHandlers queries the runtime handlers from the CRI socket of the
+// runtime. Nothing is returned if the socket does not exist or the runtime
+// does not report its runtime handlers.
+func (r containerRuntime) criRuntimeHandlers() ([]nfdv1alpha1.InstanceFeature, error) {
+	socket := hostpath.RunDir.Path(r.socket)
+	if _, err := os.Stat(socket); errors.Is(err, fs.ErrNotExist) {
+		klog.V(2).InfoS("CRI socket not available", "path", socket)
+		return nil, nil
+	}
+
+	conn, err := grpc.NewClient("unix://"+socket, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), criTimeout)
+	defer cancel()
+
+	resp, err := runtimeapi.NewRuntimeServiceClient(conn).Status(ctx, &runtimeapi.StatusRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	handlers := make([]nfdv1alpha1.InstanceFeature, 0, len(resp.RuntimeHandlers))
+	for _, h := range resp.RuntimeHandlers {
+		// The default runtime handler has an empty name
+		if h.Name == "" {
+			continue
+		}
+		attrs := map[string]string{
+			"name":    h.Name,
+			"runtime": r.name,
+		}
+		if h.Features != nil {
+			attrs["recursive_read_only_mounts"] = strconv.FormatBool(h.Features.RecursiveReadOnlyMounts)
+			attrs["user_namespaces"] = strconv.FormatBool(h.Features.UserNamespaces)
+		}
+		handlers = append(handlers, *nfdv1alpha1.NewInstanceFeature(attrs))
+	}
+	return handlers, nil
+}
+
+// configRuntimeHandlers parses the runtime handlers from the configuration
+// files of the runtime.
+func (r containerRuntime) configRuntimeHandlers() []nfdv1alpha1.InstanceFeature {
+	names := make(map[string]struct{})
+	for _, pattern := range r.configFiles {
+		files, err := filepath.Glob(hostpath.EtcDir.Path(pattern))
+		if err != nil {
+			klog.ErrorS(err, "invalid configuration file pattern", "pattern", pattern)
+			continue
+		}
+		for _, f := range files {
+			if err := parseRuntimeHandlers(f, r.handlerRe, names); err != nil {
+				klog.ErrorS(err, "failed to parse container runtime configuration", "runtime", r.name, "path", f)
+			}
+		}
+	}
+
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+
+	handlers := make([]nfdv1alpha1.InstanceFeature, 0, len(sorted))
+	for _, name := range sorted {
+		handlers = append(handlers, *nfdv1alpha1.NewInstanceFeature(map[string]string{
+			"name":    name,
+			"runtime": r.name,
+		}))
+	}
+	return handlers
+}
+
+// parseRuntimeHandlers adds the names of the runtime handler tables found in
+// a TOML configuration file to names.
+func parseRuntimeHandlers(path string, re *regexp.Regexp, names map[string]struct{}) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		m := re.FindStringSubmatch(strings.TrimSpace(s.Text()))
+		if m == nil {
+			continue
+		}
+		for _, name := range m[1:] {
+			if name != "" {
+				names[name] = struct{}{}
+				break
+			}
+		}
+	}
+	return s.Err()
+}
@@ -17,7 +17,8 @@ limitations under the License.
 package system
 
 import (
-	"bufio"
+	"fmt"
+	"maps"
 	"os"
 	"regexp"
 	"strings"
@@ -41,28 +42,68 @@ var osReleaseFields = [...]string{
 const Name = "system"
 
 const (
-	OsReleaseFeature = "osrelease"
-	NameFeature      = "name"
-	DmiIdFeature     = "dmiid"
+	OsReleaseFeature        = "osrelease"
+	NameFeature             = "name"
+	DmiIdFeature            = "dmiid"
+	CgroupFeature           = "cgroup"
+	CgroupControllerFeature = "cgroupcontroller"
+	RuntimeHandlerFeature   = "runtimehandler"
+	HostIDFeature           = "hostid"
+	DevicePluginFeature     = "deviceplugin"
 )
 
-// systemSource implements the FeatureSource and LabelSource interfaces.
+// Config contains the configuration parameters of this source.
+type Config struct {
+	// HostIDSalt is the salt used in hashing the machine ID and boot ID.
+	HostIDSalt string `json:"hostIdSalt,omitempty"`
+}
+
+// newDefaultConfig returns a new config with pre-populated defaults
+func newDefaultConfig() *Config {
+	return &Config{}
+}
+
+// systemSource implements the FeatureSource, LabelSource and ConfigurableSource interfaces.
 type systemSource struct {
+	config   *Config
 	features *nfdv1alpha1.Features
 }
 
 // Singleton source instance
 var (
-	src systemSource
-	_   source.FeatureSource = &src
-	_   source.LabelSource   = &src
+	src                           = systemSource{config: newDefaultConfig()}
+	_   source.FeatureSource      = &src
+	_   source.LabelSource        = &src
+	_   source.ConfigurableSource = &src
+	_   source.HostPathSource     = &src
 )
 
 func (s *systemSource) Name() string { return Name }
 
+// NewConfig method of the LabelSource interface
+func (s *systemSource) NewConfig() source.Config { return newDefaultConfig() }
+
+// GetConfig method of the LabelSource interface
+func (s *systemSource) GetConfig() source.Config { return s.config }
+
+// SetConfig method of the LabelSource interface
+func (s *systemSource) SetConfig(conf source.Config) {
+	switch v := conf.(type) {
+	case *Config:
+		s.config = v
+	default:
+		panic(fmt.Sprintf("invalid config type: %T", conf))
+	}
+}
+
 // Priority method of the LabelSource interface
 func (s *systemSource) Priority() int { return 0 }
 
+// RequiredHostPaths method of the HostPathSource interface
+func (s *systemSource) RequiredHostPaths() []string {
+	return []string{hostpath.EtcDir.Path("os-release")}
+}
+
 // GetLabels method of the LabelSource interface
 func (s *systemSource) GetLabels() (source.FeatureLabels, error) {
 	labels := source.FeatureLabels{}
@@ -103,22 +144,37 @@ func (s *systemSource) Discover() error {
 	}
 
 	// Get DMI ID attributes
-	dmiIDAttributeNames := []string{"sys_vendor", "product_name"}
-	dmiAttrs := make(map[string]string)
-	for _, name := range dmiIDAttributeNames {
-		val, err := getDmiIDAttribute(name)
-		if err != nil {
-			klog.ErrorS(err, "failed to get DMI entry", "attributeName", name)
-		} else {
-			dmiAttrs[name] = val
-		}
-	}
-
+	dmiAttrs := getDmiIDAttributes()
 	if len(dmiAttrs) > 0 {
 		s.features.Attributes[DmiIdFeature] = nfdv1alpha1.NewAttributeFeatures(dmiAttrs)
 	}
 
-	klog.V(3).InfoS("discovered features", "featureSource", s.Name(), "features", utils.DelayedDumper(s.features))
+	// Get salted hashes of the host identifiers
+	if attrs := getHostIDAttributes(s.config.HostIDSalt); len(attrs) > 0 {
+		s.features.Attributes[HostIDFeature] = nfdv1alpha1.NewAttributeFeatures(attrs)
+	}
+
+	// Get cgroup version and driver information
+	cgroupAttrs := detectCgroupVersion()
+	maps.Copy(cgroupAttrs, detectCgroupDriver())
+	s.features.Attributes[CgroupFeature] = nfdv1alpha1.NewAttributeFeatures(cgroupAttrs)
+
+	// Get cgroup v2 controller information
+	if attrs := detectCgroupControllers(); len(attrs) > 0 {
+		s.features.Attributes[CgroupControllerFeature] = nfdv1alpha1.NewAttributeFeatures(attrs)
+	}
+
+	// Get runtime handlers of the container runtime
+	if handlers := discoverRuntimeHandlers(); len(handlers) > 0 {
+		s.features.Instances[RuntimeHandlerFeature] = nfdv1alpha1.NewInstanceFeatures(handlers...)
+	}
+
+	// Get resources registered by device plugins
+	if plugins := discoverDevicePlugins(); len(plugins) > 0 {
+		s.features.Instances[DevicePluginFeature] = nfdv1alpha1.NewInstanceFeatures(plugins...)
+	}
+
+	source.LogDiscoveredFeatures(s.Name(), "features", utils.DelayedDumper(s.features))
 
 	return nil
 }
@@ -131,29 +187,6 @@ func (s *systemSource) GetFeatures() *nfdv1alpha1.Features {
 	return s.features
 }
 
-// Read and parse os-release file
-func parseOSRelease() (map[string]string, error) {
-	release := map[string]string{}
-
-	f, err := os.Open(hostpath.EtcDir.Path("os-release"))
-	if err != nil {
-		return nil, err
-	}
-
-	re := regexp.MustCompile(`^(?P<key>\w+)=(?P<value>.+)`)
-
-	// Read line-by-line
-	s := bufio.NewScanner(f)
-	for s.Scan() {
-		line := s.Text()
-		if m := re.FindStringSubmatch(line); m != nil {
-			release[m[1]] = strings.Trim(m[2], `"'`)
-		}
-	}
-
-	return release, nil
-}
-
 // Split version number into sub-components. Verifies that they are numerical
 // so that they can be fully utilized in k8s nodeAffinity
 func splitVersion(version string) map[string]string {
@@ -170,6 +203,36 @@ func splitVersion(version string) map[string]string {
 	return components
 }
 
+// dmiIDAttributeNames are the attributes read from /sys/devices/virtual/dmi/id.
+var dmiIDAttributeNames = []string{
+	"sys_vendor",
+	"product_name",
+	"product_family",
+	"bios_vendor",
+	"bios_version",
+	"bios_date",
+	"chassis_type",
+}
+
+// getDmiIDAttributes reads the DMI ID attributes. Attributes that are not
+// provided by the firmware are skipped.
+func getDmiIDAttributes() map[string]string {
+	attrs := make(map[string]string)
+	for _, name := range dmiIDAttributeNames {
+		val, err := getDmiIDAttribute(name)
+		if err != nil {
+			if os.IsNotExist(err) {
+				klog.V(2).InfoS("DMI entry not available", "attributeName", name)
+			} else {
+				klog.ErrorS(err, "failed to get DMI entry", "attributeName", name)
+			}
+		} else if val != "" {
+			attrs[name] = val
+		}
+	}
+	return attrs
+}
+
 // Read /sys/devices/virtual/dmi/id attribute
 func getDmiIDAttribute(name string) (string, error) {
 	s, err := os.ReadFile(hostpath.SysfsDir.Path("devices/virtual/dmi/id/", name))
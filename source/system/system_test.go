@@ -17,9 +17,14 @@ limitations under the License.
 package system
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
+	"sigs.k8s.io/node-feature-discovery/pkg/utils/hostpath"
 )
 
 func TestSystemSource(t *testing.T) {
@@ -33,3 +38,216 @@ func TestSystemSource(t *testing.T) {
 	assert.Empty(t, l)
 
 }
+
+func TestDetectCgroupDriver(t *testing.T) {
+	root := t.TempDir()
+	origSysfsDir, origVarDir := hostpath.SysfsDir, hostpath.VarDir
+	hostpath.SysfsDir = hostpath.HostDir(filepath.Join(root, "sys"))
+	hostpath.VarDir = hostpath.HostDir(filepath.Join(root, "var"))
+	defer func() { hostpath.SysfsDir, hostpath.VarDir = origSysfsDir, origVarDir }()
+
+	mkdir := func(p string) {
+		assert.NoError(t, os.MkdirAll(filepath.Join(root, p), 0755))
+	}
+
+	// No cgroup filesystem
+	assert.Empty(t, detectCgroupDriver())
+
+	// Systemd host, kubelet driver inferred from the cgroup hierarchy
+	mkdir("sys/fs/cgroup/init.scope")
+	mkdir("sys/fs/cgroup/kubepods")
+	assert.Equal(t, map[string]string{"host_driver": "systemd", "kubelet_driver": "cgroupfs", "driver_mismatch": "true"}, detectCgroupDriver())
+
+	// Kubelet driver read from the kubelet configuration file
+	mkdir("var/lib/kubelet")
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "var/lib/kubelet/config.yaml"), []byte("cgroupDriver: systemd\n"), 0644))
+	assert.Equal(t, map[string]string{"host_driver": "systemd", "kubelet_driver": "systemd", "driver_mismatch": "false"}, detectCgroupDriver())
+}
+
+func TestDetectCgroupVersion(t *testing.T) {
+	root := t.TempDir()
+	origSysfsDir, origPsiProcfsPath := hostpath.SysfsDir, psiProcfsPath
+	hostpath.SysfsDir = hostpath.HostDir(filepath.Join(root, "sys"))
+	psiProcfsPath = filepath.Join(root, "proc/pressure")
+	defer func() { hostpath.SysfsDir, psiProcfsPath = origSysfsDir, origPsiProcfsPath }()
+
+	touch := func(p string) {
+		assert.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(root, p)), 0755))
+		assert.NoError(t, os.WriteFile(filepath.Join(root, p), nil, 0644))
+	}
+
+	// No cgroup filesystem, PSI disabled
+	assert.Equal(t, map[string]string{"psi": "false"}, detectCgroupVersion())
+
+	// Legacy hierarchies
+	assert.NoError(t, os.MkdirAll(filepath.Join(root, "sys/fs/cgroup/cpu"), 0755))
+	assert.Equal(t, map[string]string{"version": "v1", "psi": "false"}, detectCgroupVersion())
+
+	// Hybrid mode
+	touch("sys/fs/cgroup/unified/cgroup.controllers")
+	assert.Equal(t, map[string]string{"version": "hybrid", "psi": "false"}, detectCgroupVersion())
+
+	// Unified hierarchy, PSI enabled
+	touch("sys/fs/cgroup/cgroup.controllers")
+	touch("proc/pressure/cpu")
+	assert.Equal(t, map[string]string{"version": "v2", "psi": "true"}, detectCgroupVersion())
+}
+
+func TestDetectCgroupControllers(t *testing.T) {
+	root := t.TempDir()
+	origSysfsDir := hostpath.SysfsDir
+	hostpath.SysfsDir = hostpath.HostDir(filepath.Join(root, "sys"))
+	defer func() { hostpath.SysfsDir = origSysfsDir }()
+
+	writeFile := func(p, content string) {
+		assert.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(root, p)), 0755))
+		assert.NoError(t, os.WriteFile(filepath.Join(root, p), []byte(content), 0644))
+	}
+
+	// No cgroup v2 filesystem
+	assert.Empty(t, detectCgroupControllers())
+
+	// Cpu controller not enabled
+	writeFile("sys/fs/cgroup/cgroup.subtree_control", "memory pids\n")
+	assert.Equal(t, map[string]string{
+		"cpu": "false", "cpuset": "false", "io": "false", "memory": "true", "hugetlb": "false",
+		"misc": "false", "pids": "true", "rdma": "false", "cpu_idle": "false", "cpu_burst": "false",
+	}, detectCgroupControllers())
+
+	// Cpu controller enabled with cpu.idle support
+	writeFile("sys/fs/cgroup/cgroup.subtree_control", "cpuset cpu io memory hugetlb pids misc\n")
+	writeFile("sys/fs/cgroup/kubepods.slice/cpu.max", "max 100000\n")
+	writeFile("sys/fs/cgroup/kubepods.slice/cpu.idle", "0\n")
+	assert.Equal(t, map[string]string{
+		"cpu": "true", "cpuset": "true", "io": "true", "memory": "true", "hugetlb": "true",
+		"misc": "true", "pids": "true", "rdma": "false", "cpu_idle": "true", "cpu_burst": "false",
+	}, detectCgroupControllers())
+
+	// Cpu burst support
+	writeFile("sys/fs/cgroup/kubepods.slice/cpu.max.burst", "0\n")
+	assert.Equal(t, "true", detectCgroupControllers()["cpu_burst"])
+}
+
+func TestGetDmiIDAttributes(t *testing.T) {
+	root := t.TempDir()
+	origSysfsDir := hostpath.SysfsDir
+	hostpath.SysfsDir = hostpath.HostDir(root)
+	defer func() { hostpath.SysfsDir = origSysfsDir }()
+
+	// No DMI information
+	assert.Empty(t, getDmiIDAttributes())
+
+	dir := filepath.Join(root, "devices/virtual/dmi/id")
+	assert.NoError(t, os.MkdirAll(dir, 0755))
+	for name, val := range map[string]string{
+		"sys_vendor":     "Acme Corp.\n",
+		"product_name":   "Server 1000\n",
+		"product_family": "\n",
+		"bios_version":   "2.1.3\n",
+		"chassis_type":   "23\n",
+		"product_serial": "secret\n",
+	} {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(val), 0644))
+	}
+
+	expected := map[string]string{
+		"sys_vendor":   "Acme Corp.",
+		"product_name": "Server 1000",
+		"bios_version": "2.1.3",
+		"chassis_type": "23",
+	}
+	assert.Equal(t, expected, getDmiIDAttributes())
+}
+
+func TestDiscoverRuntimeHandlers(t *testing.T) {
+	root := t.TempDir()
+	origEtcDir, origRunDir := hostpath.EtcDir, hostpath.RunDir
+	hostpath.EtcDir = hostpath.HostDir(filepath.Join(root, "etc"))
+	hostpath.RunDir = hostpath.HostDir(filepath.Join(root, "run"))
+	defer func() { hostpath.EtcDir, hostpath.RunDir = origEtcDir, origRunDir }()
+
+	writeFile := func(p, data string) {
+		assert.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(root, p)), 0755))
+		assert.NoError(t, os.WriteFile(filepath.Join(root, p), []byte(data), 0644))
+	}
+
+	// No container runtime configuration
+	assert.Empty(t, discoverRuntimeHandlers())
+
+	// CRI-O
+	writeFile("etc/crio/crio.conf.d/10-kata.conf", `
+[crio.runtime.runtimes.kata]
+runtime_path = "/usr/bin/containerd-shim-kata-v2"
+runtime_type = "vm"
+`)
+	assert.Equal(t, []nfdv1alpha1.InstanceFeature{
+		{Attributes: map[string]string{"name": "kata", "runtime": "cri-o"}},
+	}, discoverRuntimeHandlers())
+
+	// Containerd takes precedence, options tables are ignored
+	writeFile("etc/containerd/config.toml", `
+version = 2
+[plugins."io.containerd.grpc.v1.cri".containerd.runtimes.runc]
+  runtime_type = "io.containerd.runc.v2"
+[plugins."io.containerd.grpc.v1.cri".containerd.runtimes.runc.options]
+  SystemdCgroup = true
+`)
+	writeFile("etc/containerd/conf.d/gvisor.toml", `
+[plugins."io.containerd.cri.v1.runtime".containerd.runtimes."runsc"]
+  runtime_type = "io.containerd.runsc.v1"
+[plugins."io.containerd.grpc.v1.cri".containerd.runtimes.nvidia]
+  runtime_type = "io.containerd.runc.v2"
+`)
+	assert.Equal(t, []nfdv1alpha1.InstanceFeature{
+		{Attributes: map[string]string{"name": "nvidia", "runtime": "containerd"}},
+		{Attributes: map[string]string{"name": "runc", "runtime": "containerd"}},
+		{Attributes: map[string]string{"name": "runsc", "runtime": "containerd"}},
+	}, discoverRuntimeHandlers())
+}
+
+func TestGetHostIDAttributes(t *testing.T) {
+	root := t.TempDir()
+	origEtcDir, origBootIDPath := hostpath.EtcDir, bootIDPath
+	hostpath.EtcDir = hostpath.HostDir(root)
+	bootIDPath = filepath.Join(root, "boot_id")
+	defer func() { hostpath.EtcDir, bootIDPath = origEtcDir, origBootIDPath }()
+
+	// No host IDs available
+	assert.Empty(t, getHostIDAttributes("salt"))
+
+	machineID := "0123456789abcdef0123456789abcdef"
+	bootID := "01234567-89ab-cdef-0123-456789abcdef"
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "machine-id"), []byte(machineID+"\n"), 0644))
+	assert.NoError(t, os.WriteFile(bootIDPath, []byte(bootID+"\n"), 0644))
+
+	attrs := getHostIDAttributes("salt")
+	assert.Equal(t, map[string]string{
+		"machine_id_hash": hashHostID(machineID, "salt"),
+		"boot_id_hash":    hashHostID(bootID, "salt"),
+	}, attrs)
+	assert.Len(t, attrs["machine_id_hash"], hostIDHashLen)
+	assert.NotContains(t, attrs["machine_id_hash"], machineID)
+
+	// Hashes depend on the salt
+	assert.NotEqual(t, attrs, getHostIDAttributes("other-salt"))
+}
+
+func TestDiscoverDevicePlugins(t *testing.T) {
+	root := t.TempDir()
+	origVarDir := hostpath.VarDir
+	hostpath.VarDir = hostpath.HostDir(root)
+	defer func() { hostpath.VarDir = origVarDir }()
+
+	// No kubelet state available
+	assert.Empty(t, discoverDevicePlugins())
+
+	checkpoint := `{"Data":{"PodDeviceEntries":null,"RegisteredDevices":{"nvidia.com/gpu":["GPU-1","GPU-2"],"example.com/foo":[]}},"Checksum":1234}`
+	p := filepath.Join(root, devicePluginCheckpoint)
+	assert.NoError(t, os.MkdirAll(filepath.Dir(p), 0755))
+	assert.NoError(t, os.WriteFile(p, []byte(checkpoint), 0644))
+
+	assert.Equal(t, []nfdv1alpha1.InstanceFeature{
+		{Attributes: map[string]string{"name": "example.com/foo", "devices": "0"}},
+		{Attributes: map[string]string{"name": "nvidia.com/gpu", "devices": "2"}},
+	}, discoverDevicePlugins())
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package usb
+
+import (
+	"path/filepath"
+	"strconv"
+
+	"k8s.io/klog/v2"
+
+	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
+	"sigs.k8s.io/node-feature-discovery/pkg/utils/hostpath"
+)
+
+// Thunderbolt security level that does not require device authorization.
+const thunderboltSecurityNone = "none"
+
+var thunderboltDevAttrs = []string{"vendor", "device", "vendor_name", "device_name", "authorized"}
+
+// detectAuthorization detects whether authorization of new USB and
+// Thunderbolt devices is enforced.
+func detectAuthorization() (map[string]string, error) {
+	attrs := make(map[string]string)
+
+	// Default authorization state of new devices is controlled by the root
+	// hubs (usb1, usb2 etc.): 0 means unauthorized, 1 authorized and 2 that
+	// only internal devices are authorized
+	hubs, err := filepath.Glob(hostpath.SysfsDir.Path("bus/usb/devices/usb*/authorized_default"))
+	if err != nil {
+		return nil, err
+	}
+	if len(hubs) > 0 {
+		enforced := true
+		for _, h := range hubs {
+			val, err := readSingleUsbSysfsAttribute(h)
+			if err != nil {
+				klog.ErrorS(err, "failed to read USB authorization default")
+				enforced = false
+				continue
+			}
+			if v, err := strconv.Atoi(val); err != nil || v == 1 || v < 0 {
+				enforced = false
+			}
+		}
+		attrs["usb_enforced"] = strconv.FormatBool(enforced)
+	}
+
+	// Thunderbolt domains have a security level controlling the
+	// authorization of connected devices
+	domains, err := filepath.Glob(hostpath.SysfsDir.Path("bus/thunderbolt/devices/domain*/security"))
+	if err != nil {
+		return nil, err
+	}
+	if len(domains) > 0 {
+		security := ""
+		enforced := true
+		for _, d := range domains {
+			val, err := readSingleUsbSysfsAttribute(d)
+			if err != nil {
+				klog.ErrorS(err, "failed to read Thunderbolt security level")
+				enforced = false
+				continue
+			}
+			if val == thunderboltSecurityNone {
+				enforced = false
+			}
+			if security == "" {
+				security = val
+			} else if security != val {
+				security = "mixed"
+			}
+		}
+		if security != "" {
+			attrs["thunderbolt_security"] = security
+		}
+		attrs["thunderbolt_enforced"] = strconv.FormatBool(enforced)
+	}
+
+	return attrs, nil
+}
+
+// detectThunderbolt detects connected Thunderbolt devices and their
+// authorization state.
+func detectThunderbolt() ([]nfdv1alpha1.InstanceFeature, error) {
+	devPaths, err := filepath.Glob(hostpath.SysfsDir.Path("bus/thunderbolt/devices/*/authorized"))
+	if err != nil {
+		return nil, err
+	}
+
+	devs := make([]nfdv1alpha1.InstanceFeature, 0, len(devPaths))
+	for _, devPath := range devPaths {
+		devPath = filepath.Dir(devPath)
+		attrs := map[string]string{"name": filepath.Base(devPath)}
+		for _, attr := range thunderboltDevAttrs {
+			if val, err := readSingleUsbSysfsAttribute(filepath.Join(devPath, attr)); err == nil && val != "" {
+				attrs[attr] = val
+			}
+		}
+		devs = append(devs, *nfdv1alpha1.NewInstanceFeature(attrs))
+	}
+	return devs, nil
+}
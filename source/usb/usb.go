@@ -25,6 +25,7 @@ import (
 
 	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
 	"sigs.k8s.io/node-feature-discovery/pkg/utils"
+	"sigs.k8s.io/node-feature-discovery/pkg/utils/hostpath"
 	"sigs.k8s.io/node-feature-discovery/source"
 )
 
@@ -33,6 +34,14 @@ const Name = "usb"
 
 const DeviceFeature = "device"
 
+// AuthorizationFeature is the name of the feature holding the device
+// authorization policy of the system.
+const AuthorizationFeature = "authorization"
+
+// ThunderboltDeviceFeature is the name of the feature holding connected
+// Thunderbolt devices.
+const ThunderboltDeviceFeature = "thunderbolt_device"
+
 type Config struct {
 	DeviceClassWhitelist []string `json:"deviceClassWhitelist,omitempty"`
 	DeviceLabelFields    []string `json:"deviceLabelFields,omitempty"`
@@ -63,6 +72,7 @@ var (
 	_   source.FeatureSource      = &src
 	_   source.LabelSource        = &src
 	_   source.ConfigurableSource = &src
+	_   source.HostPathSource     = &src
 )
 
 // Name returns the name of the feature source
@@ -87,6 +97,11 @@ func (s *usbSource) SetConfig(conf source.Config) {
 // Priority method of the LabelSource interface
 func (s *usbSource) Priority() int { return 0 }
 
+// RequiredHostPaths method of the HostPathSource interface
+func (s *usbSource) RequiredHostPaths() []string {
+	return []string{hostpath.SysfsDir.Path("bus/usb/devices")}
+}
+
 // GetLabels method of the LabelSource interface
 func (s *usbSource) GetLabels() (source.FeatureLabels, error) {
 	labels := source.FeatureLabels{}
@@ -144,7 +159,19 @@ func (s *usbSource) Discover() error {
 	}
 	s.features.Instances[DeviceFeature] = nfdv1alpha1.NewInstanceFeatures(devs...)
 
-	klog.V(3).InfoS("discovered features", "featureSource", s.Name(), "features", utils.DelayedDumper(s.features))
+	if auth, err := detectAuthorization(); err != nil {
+		klog.ErrorS(err, "failed to detect device authorization policy")
+	} else {
+		s.features.Attributes[AuthorizationFeature] = nfdv1alpha1.NewAttributeFeatures(auth)
+	}
+
+	if tbDevs, err := detectThunderbolt(); err != nil {
+		klog.ErrorS(err, "failed to detect Thunderbolt devices")
+	} else {
+		s.features.Instances[ThunderboltDeviceFeature] = nfdv1alpha1.NewInstanceFeatures(tbDevs...)
+	}
+
+	source.LogDiscoveredFeatures(s.Name(), "features", utils.DelayedDumper(s.features))
 
 	return nil
 }
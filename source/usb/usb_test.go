@@ -17,9 +17,14 @@ limitations under the License.
 package usb
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
+	"sigs.k8s.io/node-feature-discovery/pkg/utils/hostpath"
 )
 
 func TestUsbSource(t *testing.T) {
@@ -33,3 +38,91 @@ func TestUsbSource(t *testing.T) {
 	assert.Empty(t, l)
 
 }
+
+func TestDetectAuthorization(t *testing.T) {
+	sysfs := t.TempDir()
+	origSysfsDir := hostpath.SysfsDir
+	hostpath.SysfsDir = hostpath.HostDir(sysfs)
+	defer func() { hostpath.SysfsDir = origSysfsDir }()
+
+	writeAttr := func(p, val string) {
+		p = filepath.Join(sysfs, p)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(p), 0755))
+		assert.NoError(t, os.WriteFile(p, []byte(val+"\n"), 0644))
+	}
+
+	// No USB or Thunderbolt support
+	attrs, err := detectAuthorization()
+	assert.NoError(t, err)
+	assert.Empty(t, attrs)
+
+	writeAttr("bus/usb/devices/usb1/authorized_default", "0")
+	writeAttr("bus/usb/devices/usb2/authorized_default", "2")
+	writeAttr("bus/thunderbolt/devices/domain0/security", "user")
+	writeAttr("bus/thunderbolt/devices/0-1/authorized", "1")
+	writeAttr("bus/thunderbolt/devices/0-1/vendor", "0x1")
+	writeAttr("bus/thunderbolt/devices/0-1/device", "0x8003")
+
+	attrs, err = detectAuthorization()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"usb_enforced": "true", "thunderbolt_security": "user", "thunderbolt_enforced": "true"}, attrs)
+
+	devs, err := detectThunderbolt()
+	assert.NoError(t, err)
+	assert.Len(t, devs, 1)
+	assert.Equal(t, map[string]string{"name": "0-1", "authorized": "1", "vendor": "0x1", "device": "0x8003"}, devs[0].Attributes)
+
+	// Authorization is not enforced if any of the root hubs or domains
+	// authorize devices by default
+	writeAttr("bus/usb/devices/usb3/authorized_default", "1")
+	writeAttr("bus/thunderbolt/devices/domain1/security", "none")
+
+	attrs, err = detectAuthorization()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"usb_enforced": "false", "thunderbolt_security": "mixed", "thunderbolt_enforced": "false"}, attrs)
+}
+
+func TestDetectUsbCache(t *testing.T) {
+	sysfs := t.TempDir()
+	origSysfsDir := hostpath.SysfsDir
+	hostpath.SysfsDir = hostpath.HostDir(sysfs)
+	defer func() { hostpath.SysfsDir = origSysfsDir }()
+	defer devCache.Invalidate()
+
+	writeAttr := func(name, val string) {
+		p := filepath.Join(sysfs, "bus/usb/devices/1-1", name)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(p), 0755))
+		assert.NoError(t, os.WriteFile(p, []byte(val+"\n"), 0644))
+	}
+	writeAttr("idProduct", "0001")
+	writeAttr("idVendor", "1d6b")
+	writeAttr("bDeviceClass", "09")
+	writeAttr("authorized", "1")
+	writeAttr("uevent", "PRODUCT=1d6b/1/0\nBUSNUM=001\nDEVNUM=002")
+
+	devs, err := detectUsb()
+	assert.NoError(t, err)
+	assert.Equal(t, []map[string]string{{"class": "09", "vendor": "1d6b", "device": "0001", "authorized": "1"}}, attrsOf(devs))
+
+	// Cached device information is used if the uevent file is unchanged,
+	// except for the authorization state
+	writeAttr("idVendor", "8086")
+	writeAttr("authorized", "0")
+	devs, err = detectUsb()
+	assert.NoError(t, err)
+	assert.Equal(t, []map[string]string{{"class": "09", "vendor": "1d6b", "device": "0001", "authorized": "0"}}, attrsOf(devs))
+
+	// Device information is re-read when the device changes
+	writeAttr("uevent", "PRODUCT=8086/1/0\nBUSNUM=001\nDEVNUM=003")
+	devs, err = detectUsb()
+	assert.NoError(t, err)
+	assert.Equal(t, []map[string]string{{"class": "09", "vendor": "8086", "device": "0001", "authorized": "0"}}, attrsOf(devs))
+}
+
+func attrsOf(devs []nfdv1alpha1.InstanceFeature) []map[string]string {
+	ret := make([]map[string]string, len(devs))
+	for i, d := range devs {
+		ret[i] = d.Attributes
+	}
+	return ret
+}
@@ -26,11 +26,17 @@ import (
 	"k8s.io/klog/v2"
 
 	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
+	"sigs.k8s.io/node-feature-discovery/pkg/utils"
 	"sigs.k8s.io/node-feature-discovery/pkg/utils/hostpath"
 )
 
 var devAttrs = []string{"class", "vendor", "device", "serial"}
 
+// devCache caches the device information across discovery cycles. The
+// content of the uevent file of the device, which includes the product and
+// the bus address of the device, is used to detect device changes.
+var devCache = utils.NewDeviceCache[[]nfdv1alpha1.InstanceFeature]()
+
 // The USB device sysfs files do not have terribly user friendly names, map
 // these for consistency with the PCI matcher.
 var devAttrFileMap = map[string]string{
@@ -69,6 +75,11 @@ func readUsbDevInfo(devPath string) ([]nfdv1alpha1.InstanceFeature, error) {
 		}
 	}
 
+	// Authorization state of the device, not usable in device labels
+	if attrVal, _ := readSingleUsbSysfsAttribute(path.Join(devPath, "authorized")); len(attrVal) > 0 {
+		attrs["authorized"] = attrVal
+	}
+
 	// USB devices encode their class information either at the device or the interface level. If the device class
 	// is set, return as-is.
 	if attrs["class"] != "00" {
@@ -116,14 +127,42 @@ func detectUsb() ([]nfdv1alpha1.InstanceFeature, error) {
 	// Iterate over devices
 	devInfo := make([]nfdv1alpha1.InstanceFeature, 0)
 	for _, devPath := range devPaths {
-		devs, err := readUsbDevInfo(filepath.Dir(devPath))
+		devPath = filepath.Dir(devPath)
+
+		uevent, err := os.ReadFile(filepath.Join(devPath, "uevent"))
+		if err == nil {
+			if devs, ok := devCache.Get(devPath, string(uevent)); ok {
+				// The authorization state may change without changes in
+				// the uevent file
+				authorized, _ := readSingleUsbSysfsAttribute(path.Join(devPath, "authorized"))
+				for _, d := range devs {
+					d := d.DeepCopy()
+					if len(authorized) > 0 {
+						d.Attributes["authorized"] = authorized
+					}
+					devInfo = append(devInfo, *d)
+				}
+				continue
+			}
+		}
+
+		devs, err := readUsbDevInfo(devPath)
 		if err != nil {
 			klog.ErrorS(err, "failed to read USB device info")
 			continue
 		}
 
 		devInfo = append(devInfo, devs...)
+
+		if uevent != nil {
+			cached := make([]nfdv1alpha1.InstanceFeature, len(devs))
+			for i := range devs {
+				cached[i] = *devs[i].DeepCopy()
+			}
+			devCache.Set(devPath, string(uevent), cached)
+		}
 	}
+	devCache.Prune()
 
 	return devInfo, nil
 }
@@ -34,6 +34,8 @@ import (
 var (
 	dockerRepo = flag.String("nfd.repo", "gcr.io/k8s-staging-nfd/node-feature-discovery", "Docker repository to fetch image from")
 	dockerTag  = flag.String("nfd.tag", "master", "Docker tag to use")
+
+	upgradeFromTag = flag.String("nfd.upgrade-from-tag", "", "Docker tag of the previous NFD version to use in upgrade tests. Upgrade tests are skipped if empty")
 )
 
 // handleFlags sets up all flags and parses the command line.
@@ -49,6 +51,11 @@ func dockerImage() string {
 	return fmt.Sprintf("%s:%s", *dockerRepo, *dockerTag)
 }
 
+// must be called after flags are parsed
+func upgradeFromDockerImage() string {
+	return fmt.Sprintf("%s:%s", *dockerRepo, *upgradeFromTag)
+}
+
 func TestMain(m *testing.M) {
 	// Register test flags, then parse flags.
 	handleFlags()
@@ -30,6 +30,8 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	clientset "k8s.io/client-go/kubernetes"
 	e2elog "k8s.io/kubernetes/test/e2e/framework"
+
+	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
 )
 
 type k8sLabels map[string]string
@@ -69,7 +71,11 @@ func MatchAnnotations(expectedNew map[string]k8sAnnotations, oldNodes []corev1.N
 		matchFunc: func(newNode, oldNode corev1.Node, expected k8sAnnotations) ([]string, []string, []string) {
 			expectedAll := maps.Clone(oldNode.Annotations)
 			maps.Copy(expectedAll, expected)
-			return matchMap(newNode.Annotations, expectedAll)
+			// The value of the last-applied-time annotation is not predictable
+			annotations := maps.Clone(newNode.Annotations)
+			delete(annotations, nfdv1alpha1.LastAppliedTimeAnnotation)
+			delete(expectedAll, nfdv1alpha1.LastAppliedTimeAnnotation)
+			return matchMap(annotations, expectedAll)
 		},
 	}
 
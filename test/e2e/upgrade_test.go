@@ -0,0 +1,335 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	extclient "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/kubernetes/test/e2e/framework"
+	e2edeployment "k8s.io/kubernetes/test/e2e/framework/deployment"
+	admissionapi "k8s.io/pod-security-admission/api"
+
+	nfdclient "sigs.k8s.io/node-feature-discovery/api/generated/clientset/versioned"
+	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
+	testutils "sigs.k8s.io/node-feature-discovery/test/e2e/utils"
+	testds "sigs.k8s.io/node-feature-discovery/test/e2e/utils/daemonset"
+	testdeploy "sigs.k8s.io/node-feature-discovery/test/e2e/utils/deployment"
+	testpod "sigs.k8s.io/node-feature-discovery/test/e2e/utils/pod"
+)
+
+// nfdNodeState is the part of a node object managed by NFD
+type nfdNodeState struct {
+	Labels      map[string]string
+	Annotations map[string]string
+	Taints      map[string]corev1.Taint
+}
+
+// getNFDNodeState returns the NFD-managed labels, annotations and taints of a
+// node.
+func getNFDNodeState(node *corev1.Node) nfdNodeState {
+	state := nfdNodeState{
+		Labels:      map[string]string{},
+		Annotations: map[string]string{},
+		Taints:      map[string]corev1.Taint{},
+	}
+
+	nfdLabels := map[string]struct{}{}
+	for _, name := range strings.Split(node.Annotations[nfdv1alpha1.FeatureLabelsAnnotation], ",") {
+		if strings.Contains(name, "/") {
+			nfdLabels[name] = struct{}{}
+		}
+	}
+	for k, v := range node.Labels {
+		if _, ok := nfdLabels[k]; ok || strings.HasPrefix(k, nfdv1alpha1.FeatureLabelNs) {
+			state.Labels[k] = v
+		}
+	}
+
+	for k, v := range node.Annotations {
+		// The value of the last-applied-time annotation is not predictable
+		if k == nfdv1alpha1.LastAppliedTimeAnnotation {
+			continue
+		}
+		if strings.HasPrefix(k, nfdv1alpha1.AnnotationNs) || strings.HasPrefix(k, nfdv1alpha1.FeatureAnnotationNs) {
+			state.Annotations[k] = v
+		}
+	}
+
+	for _, t := range node.Spec.Taints {
+		if strings.HasPrefix(t.Key, nfdv1alpha1.TaintNs) {
+			t.TimeAdded = nil
+			state.Taints[t.ToString()] = t
+		}
+	}
+	return state
+}
+
+// getNFDNodeStates returns the NFD-managed state of all non-control-plane
+// nodes.
+func getNFDNodeStates(ctx context.Context, cs clientset.Interface) (map[string]nfdNodeState, error) {
+	nodes, err := getNonControlPlaneNodes(ctx, cs)
+	if err != nil {
+		return nil, err
+	}
+	states := make(map[string]nfdNodeState, len(nodes))
+	for _, n := range nodes {
+		states[n.Name] = getNFDNodeState(&n)
+	}
+	return states, nil
+}
+
+// nfdNodeStateRemovals returns the labels, annotations and taints of the
+// expected state that are missing or have a different value in the current
+// state.
+func nfdNodeStateRemovals(expected, current nfdNodeState) []string {
+	var removals []string
+	for k, v := range expected.Labels {
+		if cur, ok := current.Labels[k]; !ok || cur != v {
+			removals = append(removals, fmt.Sprintf("label %s=%s", k, v))
+		}
+	}
+	for k, v := range expected.Annotations {
+		if cur, ok := current.Annotations[k]; !ok || cur != v {
+			removals = append(removals, fmt.Sprintf("annotation %s=%s", k, v))
+		}
+	}
+	for k := range expected.Taints {
+		if _, ok := current.Taints[k]; !ok {
+			removals = append(removals, fmt.Sprintf("taint %s", k))
+		}
+	}
+	return removals
+}
+
+// watchNFDNodeStateRemovals watches the nodes and records all transient or
+// permanent removals of the expected NFD-managed node state. The returned
+// function stops the watch and returns the recorded removals.
+func watchNFDNodeStateRemovals(ctx context.Context, cs clientset.Interface, expected map[string]nfdNodeState) func() []string {
+	ctx, cancel := context.WithCancel(ctx)
+	w, err := cs.CoreV1().Nodes().Watch(ctx, metav1.ListOptions{})
+	Expect(err).NotTo(HaveOccurred())
+
+	var (
+		mu       sync.Mutex
+		removals []string
+		wg       sync.WaitGroup
+	)
+	wg.Add(1)
+	go func() {
+		defer GinkgoRecover()
+		defer wg.Done()
+		for e := range w.ResultChan() {
+			if e.Type != watch.Modified {
+				continue
+			}
+			node, ok := e.Object.(*corev1.Node)
+			if !ok {
+				continue
+			}
+			exp, ok := expected[node.Name]
+			if !ok {
+				continue
+			}
+			if r := nfdNodeStateRemovals(exp, getNFDNodeState(node)); len(r) > 0 {
+				mu.Lock()
+				for _, s := range r {
+					removals = append(removals, fmt.Sprintf("node %s: %s (resourceVersion %s)", node.Name, s, node.ResourceVersion))
+				}
+				mu.Unlock()
+			}
+		}
+	}()
+
+	return func() []string {
+		w.Stop()
+		cancel()
+		wg.Wait()
+		mu.Lock()
+		defer mu.Unlock()
+		return removals
+	}
+}
+
+// Actual test suite
+var _ = NFDDescribe(Label("upgrade"), func() {
+	f := framework.NewDefaultFramework("nfd-upgrade")
+	// nfd-worker needs host mounts
+	f.NamespacePodSecurityLevel = admissionapi.LevelPrivileged
+
+	Context("when upgrading from the previous NFD version", Ordered, func() {
+		var (
+			crds      []*apiextensionsv1.CustomResourceDefinition
+			extClient *extclient.Clientset
+			nfdClient *nfdclient.Clientset
+		)
+
+		var testTolerations []corev1.Toleration
+		for _, key := range []string{"fake-special-node", "fake-dedicated-node", "performance-optimized-node"} {
+			testTolerations = append(testTolerations, corev1.Toleration{
+				Key:      nfdv1alpha1.TaintNs + "/" + key,
+				Operator: corev1.TolerationOpExists,
+			})
+		}
+
+		BeforeAll(func(ctx context.Context) {
+			if *upgradeFromTag == "" {
+				Skip("no previous version to upgrade from specified (-nfd.upgrade-from-tag)")
+			}
+
+			// Create clients for apiextensions and our CRD api
+			extClient = extclient.NewForConfigOrDie(f.ClientConfig())
+			nfdClient = nfdclient.NewForConfigOrDie(f.ClientConfig())
+
+			By("Creating NFD CRDs")
+			var err error
+			crds, err = testutils.CreateNfdCRDs(ctx, extClient)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterAll(func(ctx context.Context) {
+			for _, crd := range crds {
+				err := extClient.ApiextensionsV1().CustomResourceDefinitions().Delete(ctx, crd.Name, metav1.DeleteOptions{})
+				Expect(err).NotTo(HaveOccurred())
+			}
+		})
+
+		JustBeforeEach(func(ctx context.Context) {
+			err := testutils.ConfigureRBAC(ctx, f.ClientSet, f.Namespace.Name)
+			Expect(err).NotTo(HaveOccurred())
+
+			// Remove pre-existing stale annotations and labels etc and CRDs
+			cleanupCRs(ctx, nfdClient, f.Namespace.Name)
+			cleanupNode(ctx, f.ClientSet)
+		})
+
+		AfterEach(func(ctx context.Context) {
+			if *upgradeFromTag == "" {
+				return
+			}
+			Expect(testutils.DeconfigureRBAC(ctx, f.ClientSet, f.Namespace.Name)).NotTo(HaveOccurred())
+
+			cleanupNode(ctx, f.ClientSet)
+			cleanupCRs(ctx, nfdClient, f.Namespace.Name)
+		})
+
+		It("node labels, annotations and taints should be retained", Label("nfd-master", "nfd-worker"), func(ctx context.Context) {
+			nodes, err := getNonControlPlaneNodes(ctx, f.ClientSet)
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Creating NodeFeatureRules #1 and #3")
+			Expect(testutils.CreateNodeFeatureRulesFromFile(ctx, nfdClient, "nodefeaturerule-1.yaml")).NotTo(HaveOccurred())
+			Expect(testutils.CreateNodeFeatureRulesFromFile(ctx, nfdClient, "nodefeaturerule-3.yaml")).NotTo(HaveOccurred())
+
+			By(fmt.Sprintf("Creating nfd-master deployment with image %s", upgradeFromDockerImage()))
+			masterDeploy := testdeploy.NFDMaster(
+				testpod.SpecWithContainerImage(upgradeFromDockerImage()),
+				testpod.SpecWithContainerExtraArgs("-enable-taints"),
+				testpod.SpecWithTolerations(testTolerations),
+			)
+			masterDeploy, err = f.ClientSet.AppsV1().Deployments(f.Namespace.Name).Create(ctx, masterDeploy, metav1.CreateOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(e2edeployment.WaitForDeploymentComplete(f.ClientSet, masterDeploy)).NotTo(HaveOccurred())
+
+			By("Creating nfd-worker config")
+			cm := testutils.NewConfigMap("nfd-worker-conf", "nfd-worker.conf", `
+core:
+  sleepInterval: "1s"
+  featureSources: ["fake"]
+  labelSources: []
+`)
+			cm, err = f.ClientSet.CoreV1().ConfigMaps(f.Namespace.Name).Create(ctx, cm, metav1.CreateOptions{})
+			Expect(err).NotTo(HaveOccurred())
+
+			By(fmt.Sprintf("Creating nfd-worker daemonset with image %s", upgradeFromDockerImage()))
+			workerDS := testds.NFDWorker(
+				testpod.SpecWithContainerImage(upgradeFromDockerImage()),
+				testpod.SpecWithConfigMap(cm.Name, "/etc/kubernetes/node-feature-discovery"),
+				testpod.SpecWithTolerations(testTolerations),
+			)
+			workerDS, err = f.ClientSet.AppsV1().DaemonSets(f.Namespace.Name).Create(ctx, workerDS, metav1.CreateOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(testds.WaitForRollout(ctx, f.ClientSet, f.Namespace.Name, workerDS.Name)).NotTo(HaveOccurred())
+
+			By("Verifying node labels and taints created by the previous version")
+			expectedLabels := map[string]k8sLabels{
+				"*": {
+					nfdv1alpha1.FeatureLabelNs + "/e2e-flag-test-1":      "true",
+					nfdv1alpha1.FeatureLabelNs + "/e2e-flag-test-2":      "true",
+					nfdv1alpha1.FeatureLabelNs + "/e2e-attribute-test-1": "true",
+					nfdv1alpha1.FeatureLabelNs + "/e2e-attribute-test-2": "true",
+					nfdv1alpha1.FeatureLabelNs + "/e2e-instance-test-1":  "true",
+					nfdv1alpha1.FeatureLabelNs + "/e2e-instance-test-2":  "true",
+				},
+			}
+			expectedTaints := map[string][]corev1.Taint{
+				"*": {
+					{Key: "feature.node.kubernetes.io/fake-special-node", Value: "exists", Effect: "PreferNoSchedule"},
+					{Key: "feature.node.kubernetes.io/fake-dedicated-node", Value: "true", Effect: "NoExecute"},
+					{Key: "feature.node.kubernetes.io/performance-optimized-node", Value: "true", Effect: "NoExecute"},
+				},
+			}
+			eventuallyNonControlPlaneNodes(ctx, f.ClientSet).WithTimeout(1 * time.Minute).Should(MatchLabels(expectedLabels, nodes))
+			eventuallyNonControlPlaneNodes(ctx, f.ClientSet).WithTimeout(1 * time.Minute).Should(MatchTaints(expectedTaints, nodes))
+
+			By("Recording node state")
+			recorded, err := getNFDNodeStates(ctx, f.ClientSet)
+			Expect(err).NotTo(HaveOccurred())
+			stopWatch := watchNFDNodeStateRemovals(ctx, f.ClientSet, recorded)
+
+			By(fmt.Sprintf("Upgrading nfd-master to image %s", dockerImage()))
+			masterDeploy, err = f.ClientSet.AppsV1().Deployments(f.Namespace.Name).Get(ctx, masterDeploy.Name, metav1.GetOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			masterDeploy.Spec.Template.Spec.Containers[0].Image = dockerImage()
+			masterDeploy, err = f.ClientSet.AppsV1().Deployments(f.Namespace.Name).Update(ctx, masterDeploy, metav1.UpdateOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(e2edeployment.WaitForDeploymentComplete(f.ClientSet, masterDeploy)).NotTo(HaveOccurred())
+
+			By("Verifying node state after upgrading nfd-master")
+			Consistently(getNFDNodeStates).WithArguments(f.ClientSet).WithPolling(1 * time.Second).WithTimeout(10 * time.Second).WithContext(ctx).Should(Equal(recorded))
+
+			By(fmt.Sprintf("Upgrading nfd-worker to image %s", dockerImage()))
+			workerDS, err = f.ClientSet.AppsV1().DaemonSets(f.Namespace.Name).Get(ctx, workerDS.Name, metav1.GetOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			workerDS.Spec.Template.Spec.Containers[0].Image = dockerImage()
+			_, err = f.ClientSet.AppsV1().DaemonSets(f.Namespace.Name).Update(ctx, workerDS, metav1.UpdateOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(testds.WaitForRollout(ctx, f.ClientSet, f.Namespace.Name, workerDS.Name)).NotTo(HaveOccurred())
+
+			By("Verifying node state after upgrading nfd-worker")
+			Consistently(getNFDNodeStates).WithArguments(f.ClientSet).WithPolling(1 * time.Second).WithTimeout(10 * time.Second).WithContext(ctx).Should(Equal(recorded))
+
+			By("Verifying that nothing was removed from the nodes during the upgrade")
+			Expect(stopWatch()).To(BeEmpty())
+
+			By("Deleting nfd-worker daemonset and nfd-master deployment")
+			Expect(f.ClientSet.AppsV1().DaemonSets(f.Namespace.Name).Delete(ctx, workerDS.Name, metav1.DeleteOptions{})).NotTo(HaveOccurred())
+			Expect(f.ClientSet.AppsV1().Deployments(f.Namespace.Name).Delete(ctx, masterDeploy.Name, metav1.DeleteOptions{})).NotTo(HaveOccurred())
+		})
+	})
+})
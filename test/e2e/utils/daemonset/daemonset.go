@@ -17,10 +17,15 @@ limitations under the License.
 package daemonset
 
 import (
+	"context"
+	"time"
+
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clientset "k8s.io/client-go/kubernetes"
 
 	"sigs.k8s.io/node-feature-discovery/test/e2e/utils"
 	"sigs.k8s.io/node-feature-discovery/test/e2e/utils/pod"
@@ -56,3 +61,18 @@ func new(name string, podSpec *corev1.PodSpec) *appsv1.DaemonSet {
 		},
 	}
 }
+
+// WaitForRollout waits until all pods of the daemon set have been updated to
+// the latest pod template and are available.
+func WaitForRollout(ctx context.Context, c clientset.Interface, ns, name string) error {
+	return wait.PollUntilContextTimeout(ctx, 2*time.Second, 5*time.Minute, false, func(ctx context.Context) (bool, error) {
+		ds, err := c.AppsV1().DaemonSets(ns).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return false, nil
+		}
+		s := ds.Status
+		return s.ObservedGeneration >= ds.Generation &&
+			s.UpdatedNumberScheduled == s.DesiredNumberScheduled &&
+			s.NumberAvailable == s.DesiredNumberScheduled, nil
+	})
+}
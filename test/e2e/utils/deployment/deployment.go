@@ -29,6 +29,14 @@ func NFDGC(opts ...pod.SpecOption) *appsv1.Deployment {
 	return new("nfd-gc", pod.NFDGCSpec(opts...))
 }
 
+// NFDMaster returns a deployment for nfd-master
+func NFDMaster(opts ...pod.SpecOption) *appsv1.Deployment {
+	podSpec := pod.NFDMaster(opts...).Spec
+	// Deployments only support the Always restart policy
+	podSpec.RestartPolicy = corev1.RestartPolicyAlways
+	return new("nfd-master", &podSpec)
+}
+
 func new(name string, podSpec *corev1.PodSpec) *appsv1.Deployment {
 	return &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
@@ -0,0 +1,233 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
+	nfdmaster "sigs.k8s.io/node-feature-discovery/pkg/nfd-master"
+)
+
+const (
+	testNamespace = "nfd-integration"
+	// caseAnnotation is set by a catch-all NodeFeatureRule to the name of
+	// the test case, signaling that nfd-master has processed the node with
+	// the configuration of the test case.
+	caseAnnotation = nfdv1alpha1.FeatureAnnotationNs + "/integration-case"
+
+	nodeFeatureLabel = nfdv1alpha1.FeatureLabelNs + "/node-feature-label"
+	ruleLabel        = nfdv1alpha1.FeatureLabelNs + "/rule-label"
+	ruleER           = nfdv1alpha1.FeatureLabelNs + "/rule-er"
+)
+
+// restrictionsCase is one combination of the restrictions under test.
+type restrictionsCase struct {
+	denyNodeFeatureLabels    bool
+	disableLabels            bool
+	disableExtendedResources bool
+	// namespaceSelector is the value of the team label selected by
+	// nodeFeatureNamespaceSelector, empty meaning no selector
+	namespaceSelector string
+}
+
+func (c restrictionsCase) name() string {
+	return fmt.Sprintf("deny-nf-labels=%t,disable-labels=%t,disable-ers=%t,ns-selector=%q",
+		c.denyNodeFeatureLabels, c.disableLabels, c.disableExtendedResources, c.namespaceSelector)
+}
+
+// options returns the nfd-master configuration of the test case.
+func (c restrictionsCase) options() string {
+	r := map[string]interface{}{
+		"denyNodeFeatureLabels":    c.denyNodeFeatureLabels,
+		"disableLabels":            c.disableLabels,
+		"disableExtendedResources": c.disableExtendedResources,
+	}
+	if c.namespaceSelector != "" {
+		r["nodeFeatureNamespaceSelector"] = metav1.LabelSelector{MatchLabels: map[string]string{"team": c.namespaceSelector}}
+	}
+	data, _ := json.Marshal(map[string]interface{}{"restrictions": r})
+	return string(data)
+}
+
+// nodeFeaturesSelected returns true if the NodeFeature object of the test
+// namespace is processed by nfd-master.
+func (c restrictionsCase) nodeFeaturesSelected() bool {
+	return c.namespaceSelector == "" || c.namespaceSelector == "a"
+}
+
+func restrictionsCases() []restrictionsCase {
+	cases := []restrictionsCase{}
+	for _, deny := range []bool{false, true} {
+		for _, disableLabels := range []bool{false, true} {
+			for _, disableERs := range []bool{false, true} {
+				for _, sel := range []string{"", "a", "b"} {
+					cases = append(cases, restrictionsCase{
+						denyNodeFeatureLabels:    deny,
+						disableLabels:            disableLabels,
+						disableExtendedResources: disableERs,
+						namespaceSelector:        sel,
+					})
+				}
+			}
+		}
+	}
+	return cases
+}
+
+// TestRestrictions verifies that the restrictions are applied to all inputs
+// of nfd-master, i.e. both NodeFeature and NodeFeatureRule objects.
+func TestRestrictions(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	_, err := env.k8sCli.CoreV1().Namespaces().Create(ctx, &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: testNamespace, Labels: map[string]string{"team": "a"}},
+	}, metav1.CreateOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	nf := &nfdv1alpha1.NodeFeature{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      testNodeName,
+			Namespace: testNamespace,
+			Labels:    map[string]string{nfdv1alpha1.NodeFeatureObjNodeNameLabel: testNodeName},
+		},
+		Spec: nfdv1alpha1.NodeFeatureSpec{
+			Features: nfdv1alpha1.Features{
+				Attributes: map[string]nfdv1alpha1.AttributeFeatureSet{
+					"fake.attribute": nfdv1alpha1.NewAttributeFeatures(map[string]string{"present": "true"}),
+				},
+			},
+			Labels: map[string]string{nodeFeatureLabel: "true"},
+		},
+	}
+	_, err = env.nfdCli.NfdV1alpha1().NodeFeatures(testNamespace).Create(ctx, nf, metav1.CreateOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	for _, tc := range restrictionsCases() {
+		t.Run(tc.name(), func(t *testing.T) {
+			g := NewWithT(t)
+
+			resetNode(ctx, g)
+			setRules(ctx, g, tc.name())
+
+			m := startMaster(g, tc.options())
+			defer m.Stop()
+
+			var node *corev1.Node
+			g.Eventually(func(g Gomega) {
+				var err error
+				node, err = env.k8sCli.CoreV1().Nodes().Get(ctx, testNodeName, metav1.GetOptions{})
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(node.Annotations).To(HaveKeyWithValue(caseAnnotation, tc.name()))
+			}).WithTimeout(30 * time.Second).WithPolling(200 * time.Millisecond).Should(Succeed())
+
+			expectKey := func(m interface{}, key string, expected bool) {
+				if expected {
+					g.Expect(m).To(HaveKey(key))
+				} else {
+					g.Expect(m).NotTo(HaveKey(key))
+				}
+			}
+			selected := tc.nodeFeaturesSelected()
+			expectKey(node.Labels, nodeFeatureLabel, selected && !tc.denyNodeFeatureLabels && !tc.disableLabels)
+			expectKey(node.Labels, ruleLabel, selected && !tc.disableLabels)
+			expectKey(node.Status.Capacity, ruleER, selected && !tc.disableExtendedResources)
+		})
+	}
+}
+
+// resetNode re-creates the test node, dropping all labels, annotations and
+// extended resources set in previous test cases.
+func resetNode(ctx context.Context, g *WithT) {
+	err := env.k8sCli.CoreV1().Nodes().Delete(ctx, testNodeName, metav1.DeleteOptions{})
+	if !apierrors.IsNotFound(err) {
+		g.Expect(err).NotTo(HaveOccurred())
+	}
+
+	g.Eventually(func() error {
+		_, err := env.k8sCli.CoreV1().Nodes().Create(ctx, &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: testNodeName},
+		}, metav1.CreateOptions{})
+		return err
+	}).WithTimeout(10 * time.Second).Should(Succeed())
+}
+
+// setRules creates or updates the NodeFeatureRule of the test.
+func setRules(ctx context.Context, g *WithT, caseName string) {
+	nfr := &nfdv1alpha1.NodeFeatureRule{
+		ObjectMeta: metav1.ObjectMeta{Name: "integration-restrictions"},
+		Spec: nfdv1alpha1.NodeFeatureRuleSpec{
+			Rules: []nfdv1alpha1.Rule{
+				{
+					Name:              "feature rule",
+					Labels:            map[string]string{ruleLabel: "true"},
+					ExtendedResources: map[string]string{ruleER: "4"},
+					MatchFeatures: nfdv1alpha1.FeatureMatcher{
+						{
+							Feature: "fake.attribute",
+							MatchExpressions: &nfdv1alpha1.MatchExpressionSet{
+								"present": &nfdv1alpha1.MatchExpression{Op: nfdv1alpha1.MatchIsTrue},
+							},
+						},
+					},
+				},
+				{
+					Name:        "catch-all rule",
+					Annotations: map[string]string{caseAnnotation: caseName},
+				},
+			},
+		},
+	}
+
+	cli := env.nfdCli.NfdV1alpha1().NodeFeatureRules()
+	old, err := cli.Get(ctx, nfr.Name, metav1.GetOptions{})
+	if err == nil {
+		nfr.ResourceVersion = old.ResourceVersion
+		_, err = cli.Update(ctx, nfr, metav1.UpdateOptions{})
+	} else {
+		_, err = cli.Create(ctx, nfr, metav1.CreateOptions{})
+	}
+	g.Expect(err).NotTo(HaveOccurred())
+}
+
+// startMaster runs nfd-master with the given configuration options.
+func startMaster(g *WithT, options string) nfdmaster.NfdMaster {
+	m, err := nfdmaster.NewNfdMaster(nfdmaster.WithArgs(&nfdmaster.Args{
+		Kubeconfig: env.kubeconfig,
+		Options:    options,
+	}))
+	g.Expect(err).NotTo(HaveOccurred())
+
+	go func() {
+		if err := m.Run(); err != nil {
+			klog.ErrorS(err, "nfd-master exited with an error")
+		}
+	}()
+	g.Expect(m.WaitForReady(30 * time.Second)).To(BeTrue())
+	return m
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package integration contains integration tests running nfd-master against a
+// real API server (without any nodes or controllers) provided by envtest. The
+// tests require the envtest binaries and are skipped if KUBEBUILDER_ASSETS is
+// not set. Use "make integration-test" to download the binaries and run the
+// tests.
+package integration
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	k8sclient "k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+
+	nfdclientset "sigs.k8s.io/node-feature-discovery/api/generated/clientset/versioned"
+	"sigs.k8s.io/node-feature-discovery/pkg/features"
+)
+
+const testNodeName = "integration-node"
+
+// env holds the test environment shared by all tests.
+var env struct {
+	k8sCli     k8sclient.Interface
+	nfdCli     nfdclientset.Interface
+	kubeconfig string
+}
+
+func TestMain(m *testing.M) {
+	if os.Getenv("KUBEBUILDER_ASSETS") == "" {
+		fmt.Println("KUBEBUILDER_ASSETS not set, skipping integration tests")
+		os.Exit(0)
+	}
+	os.Exit(run(m))
+}
+
+func run(m *testing.M) int {
+	testEnv := &envtest.Environment{
+		CRDDirectoryPaths:     []string{filepath.Join("..", "..", "deployment", "base", "nfd-crds", "nfd-api-crds.yaml")},
+		ErrorIfCRDPathMissing: true,
+	}
+	cfg, err := testEnv.Start()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to start test environment: %v\n", err)
+		return 1
+	}
+	defer func() {
+		if err := testEnv.Stop(); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to stop test environment: %v\n", err)
+		}
+	}()
+
+	env.k8sCli = k8sclient.NewForConfigOrDie(cfg)
+	env.nfdCli = nfdclientset.NewForConfigOrDie(cfg)
+
+	// nfd-master only takes a kubeconfig file
+	user, err := testEnv.AddUser(envtest.User{Name: "nfd-master", Groups: []string{"system:masters"}}, nil)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to add user: %v\n", err)
+		return 1
+	}
+	data, err := user.KubeConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to create kubeconfig: %v\n", err)
+		return 1
+	}
+	dir, err := os.MkdirTemp("", "nfd-integration-")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to create temporary directory: %v\n", err)
+		return 1
+	}
+	defer os.RemoveAll(dir)
+	env.kubeconfig = filepath.Join(dir, "kubeconfig")
+	if err := os.WriteFile(env.kubeconfig, data, 0600); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to write kubeconfig: %v\n", err)
+		return 1
+	}
+
+	// nfd-master runs in the context of the test node
+	os.Setenv("NODE_NAME", testNodeName)
+	os.Setenv("KUBERNETES_NAMESPACE", "default")
+
+	if err := features.NFDMutableFeatureGate.Add(features.DefaultNFDFeatureGates); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to add feature gates: %v\n", err)
+		return 1
+	}
+
+	return m.Run()
+}